@@ -3,10 +3,14 @@ package api
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 // swagger:route POST /admin/provisioning/dashboards/reload admin_provisioning adminProvisioningReloadDashboards
@@ -108,3 +112,80 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *contextmodel.ReqContext
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+// swagger:route POST /admin/provisioning/dry-run admin_provisioning adminProvisioningDryRun
+//
+// Preview provisioning configuration changes.
+//
+// Reads and validates the provisioning config files for datasources, plugins, dashboards, alerting, reports, teams, roles, service accounts, folders and permissions, and returns the resources that reloading them would create, update or delete, per resource type. Nothing is stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scopes `provisioners:dashboards`, `provisioners:datasources`, `provisioners:plugins` and `provisioners:alerting`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminProvisioningDryRunResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningDryRun(c *contextmodel.ReqContext) response.Response {
+	result, err := hs.ProvisioningService.DryRun(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to dry-run provisioning", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:response adminProvisioningDryRunResponse
+type AdminProvisioningDryRunResponse struct {
+	// in:body
+	Body *dryrun.Result `json:"body"`
+}
+
+// maxGitWebhookPayloadSize is the size limit of the git webhook payloads, the
+// limit of GitHub.
+const maxGitWebhookPayloadSize = 25 << 20
+
+// swagger:route POST /provisioning/dashboards/git/{name}/webhook admin_provisioning provisioningDashboardGitWebhook
+//
+// Trigger the sync of a git dashboard provider.
+//
+// Receives the push webhooks of the repository of a git dashboard provider, and syncs the provider without waiting for the next poll. The webhook is authenticated with the `webhookSecret` secure option of the provider, either as the HMAC-SHA256 signature of the payload in the `X-Hub-Signature-256` or `X-Gitea-Signature` header, or as the `X-Gitlab-Token` header.
+//
+// Responses:
+// 202: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+func (hs *HTTPServer) ProvisioningDashboardGitWebhook(c *contextmodel.ReqContext) response.Response {
+	payload, err := io.ReadAll(io.LimitReader(c.Req.Body, maxGitWebhookPayloadSize))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to read webhook payload", err)
+	}
+
+	signature := c.Req.Header.Get("X-Hub-Signature-256")
+	if signature == "" {
+		signature = c.Req.Header.Get("X-Gitea-Signature")
+	}
+	token := c.Req.Header.Get("X-Gitlab-Token")
+
+	err = hs.ProvisioningService.HandleDashboardGitWebhook(web.Params(c.Req)[":name"], payload, signature, token)
+	switch {
+	case errors.Is(err, dashboards.ErrGitProviderNotFound):
+		return response.Error(http.StatusNotFound, "Git dashboard provider not found", err)
+	case errors.Is(err, dashboards.ErrGitWebhookDisabled):
+		return response.Error(http.StatusForbidden, "Git dashboard provider has no webhook secret", err)
+	case errors.Is(err, dashboards.ErrGitWebhookInvalidSignature):
+		return response.Error(http.StatusUnauthorized, "Invalid webhook signature", err)
+	case err != nil:
+		return response.Error(http.StatusInternalServerError, "Failed to handle webhook", err)
+	}
+	return response.JSON(http.StatusAccepted, map[string]string{"message": "Git dashboard provider sync triggered"})
+}
+
+// swagger:parameters provisioningDashboardGitWebhook
+type ProvisioningDashboardGitWebhookParams struct {
+	// in:path
+	// required:true
+	Name string `json:"name"`
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web/webtest"
 )
@@ -156,6 +158,38 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 			expectedCode: http.StatusForbidden,
 			url:          "/api/admin/provisioning/alerting/reload",
 		},
+		{
+			desc:         "should work for dry-run with broader scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"valid":true,"summary":{},"changes":{},"errors":[]}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersAll,
+				},
+			},
+			url: "/api/admin/provisioning/dry-run",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.DryRun, 1)
+				assert.Empty(t, mock.Calls.ProvisionDashboards)
+			},
+		},
+		{
+			desc:         "should fail for dry-run with a single provisioner scope",
+			expectedCode: http.StatusForbidden,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersDashboards,
+				},
+			},
+			url: "/api/admin/provisioning/dry-run",
+		},
+		{
+			desc:         "should fail for dry-run with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/dry-run",
+		},
 	}
 
 	for _, tt := range tests {
@@ -185,3 +219,40 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 		})
 	}
 }
+
+func TestAPI_ProvisioningDashboardGitWebhook(t *testing.T) {
+	tests := []struct {
+		desc         string
+		err          error
+		expectedCode int
+	}{
+		{desc: "should trigger the sync", expectedCode: http.StatusAccepted},
+		{desc: "should fail for unknown providers", err: dashboards.ErrGitProviderNotFound, expectedCode: http.StatusNotFound},
+		{desc: "should fail without webhook secret", err: dashboards.ErrGitWebhookDisabled, expectedCode: http.StatusForbidden},
+		{desc: "should fail with an invalid signature", err: dashboards.ErrGitWebhookInvalidSignature, expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			pService := provisioning.NewProvisioningServiceMock(context.Background())
+			pService.HandleDashboardGitWebhookFunc = func(name string, payload []byte, signature string, token string) error {
+				assert.Equal(t, "dashboards", name)
+				assert.Equal(t, `{"ref":"refs/heads/main"}`, string(payload))
+				assert.Equal(t, "sha256=abcd", signature)
+				return tt.err
+			}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.Cfg = setting.NewCfg()
+				hs.ProvisioningService = pService
+			})
+
+			req := server.NewPostRequest("/api/provisioning/dashboards/git/dashboards/webhook", strings.NewReader(`{"ref":"refs/heads/main"}`))
+			req.Header.Set("X-Hub-Signature-256", "sha256=abcd")
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+			assert.Len(t, pService.Calls.HandleDashboardGitWebhook, 1)
+		})
+	}
+}
@@ -31,6 +31,7 @@ package api
 
 import (
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/login/saml"
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/middleware/requestmeta"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -69,7 +70,12 @@ func (hs *HTTPServer) registerRoutes() {
 	// not logged in views
 	r.Get("/logout", hs.Logout)
 	r.Post("/login", requestmeta.SetOwner(requestmeta.TeamAuth), quota(string(auth.QuotaTargetSrv)), routing.Wrap(hs.LoginPost))
+	r.Get("/login/saml", quota(string(auth.QuotaTargetSrv)), hs.SAMLLogin)
 	r.Get("/login/:name", quota(string(auth.QuotaTargetSrv)), hs.OAuthLogin)
+	r.Post(saml.ACSPath, quota(string(auth.QuotaTargetSrv)), hs.SAMLACS)
+	r.Get(saml.MetadataPath, hs.SAMLMetadata)
+	r.Get(saml.SLOPath, hs.SAMLSingleLogout)
+	r.Post(saml.SLOPath, hs.SAMLSingleLogout)
 	r.Get("/login", hs.LoginView)
 	r.Get("/invite/:code", hs.Index)
 
@@ -173,6 +179,7 @@ func (hs *HTTPServer) registerRoutes() {
 	r.Get("/explore", authorize(ac.EvalPermission(ac.ActionDatasourcesExplore)), hs.Index)
 
 	r.Get("/playlists/", reqSignedIn, hs.Index)
+	r.Get("/playlists/kiosk/:kioskToken", hs.StartPlaylistKiosk)
 	r.Get("/playlists/*", reqSignedIn, hs.Index)
 	r.Get("/alerting/", reqSignedIn, hs.Index)
 	r.Get("/alerting/*", reqSignedIn, hs.Index)
@@ -465,12 +472,25 @@ func (hs *HTTPServer) registerRoutes() {
 				dashUidRoute.Get("/versions", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersions))
 				dashUidRoute.Post("/restore", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.RestoreDashboardVersion))
 				dashUidRoute.Get("/versions/:id", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersion))
+				dashUidRoute.Get("/lock", authorize(ac.EvalPermission(dashboards.ActionDashboardsRead)), routing.Wrap(hs.GetDashboardLock))
+				dashUidRoute.Post("/lock", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.AcquireDashboardLock))
+				dashUidRoute.Delete("/lock", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.ReleaseDashboardLock))
 				dashUidRoute.Group("/permissions", func(dashboardPermissionRoute routing.RouteRegister) {
 					dashboardPermissionRoute.Get("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead)), routing.Wrap(hs.GetDashboardPermissionList))
 					dashboardPermissionRoute.Post("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsWrite)), routing.Wrap(hs.UpdateDashboardPermissions))
 				})
 			})
 
+			dashboardRoute.Group("/recently-deleted", func(deletedRoute routing.RouteRegister) {
+				canManageDeleted := authorize(ac.EvalAny(
+					ac.EvalPermission(dashboards.ActionDashboardsDelete, dashboards.ScopeDashboardsAll),
+					ac.EvalPermission(dashboards.ActionFoldersDelete, dashboards.ScopeFoldersAll),
+				))
+				deletedRoute.Get("/", canManageDeleted, routing.Wrap(hs.ListDeletedDashboards))
+				deletedRoute.Post("/:uid/restore", canManageDeleted, routing.Wrap(hs.RestoreDeletedDashboard))
+				deletedRoute.Delete("/:uid", canManageDeleted, routing.Wrap(hs.PurgeDeletedDashboard))
+			})
+
 			dashboardRoute.Post("/calculate-diff", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.CalculateDashboardDiff))
 
 			dashboardRoute.Post("/db", authorize(ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsCreate), ac.EvalPermission(dashboards.ActionDashboardsWrite))), routing.Wrap(hs.PostDashboard))
@@ -598,8 +618,12 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/provisioning/datasources/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/notifications/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersNotifications)), routing.Wrap(hs.AdminProvisioningReloadNotifications))
 		adminRoute.Post("/provisioning/alerting/reload", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAlertRules)), routing.Wrap(hs.AdminProvisioningReloadAlerting))
+		adminRoute.Post("/provisioning/dry-run", authorize(ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDashboards, ScopeProvisionersDatasources, ScopeProvisionersPlugins, ScopeProvisionersAlertRules)), routing.Wrap(hs.AdminProvisioningDryRun))
 	}, reqSignedIn)
 
+	// the webhooks of the git dashboard providers are authenticated by their signature
+	r.Post("/api/provisioning/dashboards/git/:name/webhook", routing.Wrap(hs.ProvisioningDashboardGitWebhook))
+
 	// Administering users
 	r.Group("/api/admin/users", func(adminUserRoute routing.RouteRegister) {
 		userIDScope := ac.Scope("global.users", "id", ac.Parameter(":id"))
@@ -630,7 +654,12 @@ func (hs *HTTPServer) registerRoutes() {
 	// Snapshots
 	r.Post("/api/snapshots/", reqSnapshotPublicModeOrSignedIn, hs.getCreatedSnapshotHandler())
 	r.Get("/api/snapshot/shared-options/", reqSignedIn, hs.GetSharingOptions)
+	r.Post("/api/snapshots/import", reqSnapshotPublicModeOrSignedIn, routing.Wrap(hs.ImportDashboardSnapshot))
 	r.Get("/api/snapshots/:key", routing.Wrap(hs.GetDashboardSnapshot))
+	r.Get("/api/snapshots/:key/export", routing.Wrap(hs.ExportDashboardSnapshot))
+	r.Get("/api/snapshots/:key/refresh", reqSignedIn, routing.Wrap(hs.GetDashboardSnapshotRefreshSchedule))
+	r.Put("/api/snapshots/:key/refresh", reqSignedIn, routing.Wrap(hs.SaveDashboardSnapshotRefreshSchedule))
+	r.Delete("/api/snapshots/:key/refresh", reqSignedIn, routing.Wrap(hs.DeleteDashboardSnapshotRefreshSchedule))
 	r.Get("/api/snapshots-delete/:deleteKey", reqSnapshotPublicModeOrSignedIn, routing.Wrap(hs.DeleteDashboardSnapshotByDeleteKey))
 	r.Delete("/api/snapshots/:key", reqSignedIn, routing.Wrap(hs.DeleteDashboardSnapshot))
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/login/authinfotest"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/search/model"
@@ -313,6 +314,10 @@ func SetupAPITestServer(t *testing.T, opts ...APITestServerOption) *webtest.Serv
 		hs.AccessControl = acimpl.ProvideAccessControl(hs.Cfg)
 	}
 
+	if hs.provenanceService == nil {
+		hs.provenanceService = provenancetest.NewFakeService()
+	}
+
 	hs.registerRoutes()
 
 	s := webtest.NewServer(t, hs.RouteRegister)
@@ -211,6 +211,7 @@ func (hs *HTTPServer) GetDashboard(c *contextmodel.ReqContext) response.Response
 			// is for better UX, showing in Save/Delete dialogs and so it won't break anything if it is empty.
 			hs.log.Warn("Failed to create ProvisionedExternalId", "err", err)
 		}
+		meta.ProvisionedCommitSha = provisioningData.CommitSHA
 	}
 
 	// make sure db version is in sync with json model version
@@ -309,6 +310,19 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 
 	namespaceID, userIDStr := c.SignedInUser.GetNamespacedID()
 
+	// library element connections are kept with the deleted dashboard if it can be restored,
+	// so the dashboard has to be deleted before disconnecting the remaining ones
+	err = hs.DashboardService.DeleteDashboard(c.Req.Context(), dash.ID, c.SignedInUser.GetOrgID())
+	if err != nil {
+		var dashboardErr dashboards.DashboardErr
+		if ok := errors.As(err, &dashboardErr); ok {
+			if errors.Is(err, dashboards.ErrDashboardCannotDeleteProvisionedDashboard) {
+				return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+			}
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to delete dashboard", err)
+	}
+
 	// disconnect all library elements for this dashboard
 	err = hs.LibraryElementService.DisconnectElementsFromDashboard(c.Req.Context(), dash.ID)
 	if err != nil {
@@ -326,17 +340,6 @@ func (hs *HTTPServer) deleteDashboard(c *contextmodel.ReqContext) response.Respo
 		hs.log.Error("Failed to delete public dashboard")
 	}
 
-	err = hs.DashboardService.DeleteDashboard(c.Req.Context(), dash.ID, c.SignedInUser.GetOrgID())
-	if err != nil {
-		var dashboardErr dashboards.DashboardErr
-		if ok := errors.As(err, &dashboardErr); ok {
-			if errors.Is(err, dashboards.ErrDashboardCannotDeleteProvisionedDashboard) {
-				return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
-			}
-		}
-		return response.Error(http.StatusInternalServerError, "Failed to delete dashboard", err)
-	}
-
 	userDTODisplay, err := user.NewUserDisplayDTOFromRequester(c.SignedInUser)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Error while parsing the user DTO model", err)
@@ -441,7 +444,23 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		Overwrite: cmd.Overwrite,
 	}
 
-	dashboard, err := hs.DashboardService.SaveDashboard(alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled()), dashItem, allowUiUpdate)
+	saveCtx := alerting.WithUAEnabled(ctx, hs.Cfg.UnifiedAlerting.IsEnabled())
+	dashboard, err := hs.DashboardService.SaveDashboard(saveCtx, dashItem, allowUiUpdate)
+
+	// Someone else saved the dashboard in the meantime, try to merge both changes
+	merged := false
+	var conflicts []dashdiffs.MergeConflict
+	if errors.Is(err, dashboards.ErrDashboardVersionMismatch) && !cmd.Overwrite {
+		mergedDashboard, mergeConflicts, mergeErr := hs.mergeDashboardSave(saveCtx, dashItem, allowUiUpdate)
+		switch {
+		case mergeErr != nil:
+			hs.log.Debug("Failed to merge concurrent dashboard changes", "uid", dash.UID, "error", mergeErr)
+		case len(mergeConflicts) > 0:
+			conflicts = mergeConflicts
+		default:
+			dashboard, err, merged = mergedDashboard, nil, true
+		}
+	}
 
 	if hs.Live != nil {
 		// Tell everyone listening that the dashboard changed
@@ -473,6 +492,13 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 	}
 
 	if err != nil {
+		if len(conflicts) > 0 {
+			return response.JSON(http.StatusPreconditionFailed, util.DynMap{
+				"status":    dashboards.ErrDashboardVersionMismatch.Status,
+				"message":   dashboards.ErrDashboardVersionMismatch.Error(),
+				"conflicts": conflicts,
+			})
+		}
 		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
 	}
 
@@ -497,7 +523,56 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		"uid":       dashboard.UID,
 		"url":       dashboard.GetURL(),
 		"folderUid": dashboard.FolderUID,
+		"merged":    merged,
+	})
+}
+
+// mergeDashboardSave merges the changes of a dashboard saved with an outdated
+// version with the changes saved since that version. The merged dashboard is
+// only saved when the changes do not conflict.
+func (hs *HTTPServer) mergeDashboardSave(ctx context.Context, dto *dashboards.SaveDashboardDTO, allowUiUpdate bool) (*dashboards.Dashboard, []dashdiffs.MergeConflict, error) {
+	dash := dto.Dashboard
+	if dash.ID == 0 && dash.UID == "" {
+		return nil, nil, errors.New("dashboard id or uid is required to merge changes")
+	}
+
+	current, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: dto.OrgID, ID: dash.ID, UID: dash.UID})
+	if err != nil {
+		return nil, nil, err
+	}
+	base, err := hs.dashboardVersionService.Get(ctx, &dashver.GetDashboardVersionQuery{
+		OrgID:        dto.OrgID,
+		DashboardID:  current.ID,
+		DashboardUID: current.UID,
+		Version:      dash.Version,
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := dashdiffs.Merge(base.Data, dash.Data, current.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result.HasConflicts() {
+		return nil, result.Conflicts, nil
+	}
+
+	mergedDash := *dash
+	mergedDash.Data = result.Dashboard
+	mergedDash.Data.Set("version", current.Version)
+	mergedDash.ID = current.ID
+	mergedDash.Version = current.Version
+	mergedDash.Title = mergedDash.Data.Get("title").MustString()
+	mergedDash.UpdateSlug()
+
+	mergedDTO := *dto
+	mergedDTO.Dashboard = &mergedDash
+	saved, err := hs.DashboardService.SaveDashboard(ctx, &mergedDTO, allowUiUpdate)
+	if err != nil {
+		return nil, nil, err
+	}
+	return saved, nil, nil
 }
 
 // swagger:route GET /dashboards/home dashboards getHomeDashboard
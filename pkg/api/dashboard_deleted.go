@@ -0,0 +1,261 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// Deleted dashboards no longer exist, so their scopes can not be resolved.
+// Managing them requires delete permission on all dashboards or folders.
+func (hs *HTTPServer) canManageDeletedDashboard(c *contextmodel.ReqContext, isFolder bool) bool {
+	evaluator := ac.EvalPermission(dashboards.ActionDashboardsDelete, dashboards.ScopeDashboardsAll)
+	if isFolder {
+		evaluator = ac.EvalPermission(dashboards.ActionFoldersDelete, dashboards.ScopeFoldersAll)
+	}
+	ok, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+	if err != nil {
+		hs.log.Warn("Failed to evaluate permissions for deleted dashboards", "error", err)
+		return false
+	}
+	return ok
+}
+
+func (hs *HTTPServer) getDeletedDashboard(c *contextmodel.ReqContext, uid string) (*dashboards.DeletedDashboard, response.Response) {
+	d, err := hs.DashboardService.GetDeletedDashboard(c.Req.Context(), &dashboards.GetDeletedDashboardQuery{OrgID: c.SignedInUser.GetOrgID(), UID: uid})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDeletedDashboardNotFound) {
+			return nil, response.Error(http.StatusNotFound, err.Error(), nil)
+		}
+		return nil, response.Error(http.StatusInternalServerError, "Failed to get deleted dashboard", err)
+	}
+	if !hs.canManageDeletedDashboard(c, d.IsFolder) {
+		return nil, response.Error(http.StatusForbidden, "Access denied to deleted dashboard", nil)
+	}
+	return d, nil
+}
+
+// canRestoreDeletedDashboard checks that the user can create the deleted
+// dashboard or folder in the folder it is restored to.
+func (hs *HTTPServer) canRestoreDeletedDashboard(c *contextmodel.ReqContext, deleted *dashboards.DeletedDashboard, folderUID string) bool {
+	var evaluator ac.Evaluator
+	switch {
+	case deleted.IsFolder && folderUID == "":
+		evaluator = ac.EvalPermission(dashboards.ActionFoldersCreate)
+	case deleted.IsFolder:
+		evaluator = ac.EvalAll(
+			ac.EvalPermission(dashboards.ActionFoldersCreate),
+			ac.EvalPermission(dashboards.ActionFoldersWrite, dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)),
+		)
+	default:
+		if folderUID == "" {
+			folderUID = ac.GeneralFolderUID
+		}
+		evaluator = ac.EvalPermission(dashboards.ActionDashboardsCreate, dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID))
+	}
+	ok, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+	if err != nil {
+		hs.log.Warn("Failed to evaluate permissions to restore deleted dashboard", "error", err)
+		return false
+	}
+	return ok
+}
+
+// swagger:route GET /dashboards/recently-deleted dashboards listDeletedDashboards
+//
+// List recently deleted dashboards and folders.
+//
+// Returns the dashboards and folders which were deleted and can still be restored.
+//
+// Responses:
+// 200: listDeletedDashboardsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ListDeletedDashboards(c *contextmodel.ReqContext) response.Response {
+	query := &dashboards.ListDeletedDashboardsQuery{
+		OrgID: c.SignedInUser.GetOrgID(),
+		Limit: c.QueryInt("limit"),
+	}
+	switch c.Query("type") {
+	case "":
+	case string(model.DashHitDB):
+		query.IsFolder = util.Pointer(false)
+	case string(model.DashHitFolder):
+		query.IsFolder = util.Pointer(true)
+	default:
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("invalid type: %s", c.Query("type")), nil)
+	}
+	if c.Req.URL.Query().Has("folderUid") {
+		query.FolderUID = util.Pointer(c.Query("folderUid"))
+	}
+
+	result, err := hs.DashboardService.ListDeletedDashboards(c.Req.Context(), query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get deleted dashboards", err)
+	}
+
+	canManage := map[bool]bool{
+		false: hs.canManageDeletedDashboard(c, false),
+		true:  hs.canManageDeletedDashboard(c, true),
+	}
+	filtered := make([]*dashboards.DeletedDashboard, 0, len(result))
+	for _, d := range result {
+		if canManage[d.IsFolder] {
+			filtered = append(filtered, d)
+		}
+	}
+	return response.JSON(http.StatusOK, filtered)
+}
+
+// swagger:route POST /dashboards/recently-deleted/{uid}/restore dashboards restoreDeletedDashboard
+//
+// Restore a deleted dashboard or folder.
+//
+// Restores the dashboard together with its versions, permissions and library panel connections.
+// Restoring a folder also restores the dashboards and folders deleted with it.
+//
+// Responses:
+// 200: restoreDeletedDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) RestoreDeletedDashboard(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	cmd := dtos.RestoreDeletedDashboardCommand{}
+	if c.Req.ContentLength > 0 {
+		if err := web.Bind(c.Req, &cmd); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	}
+
+	deleted, rsp := hs.getDeletedDashboard(c, uid)
+	if rsp != nil {
+		return rsp
+	}
+	folderUID := deleted.FolderUID
+	if cmd.FolderUID != nil {
+		folderUID = *cmd.FolderUID
+	}
+	if !hs.canRestoreDeletedDashboard(c, deleted, folderUID) {
+		return response.Error(http.StatusForbidden, "Access denied to the folder the dashboard is restored to", nil)
+	}
+
+	dash, err := hs.DashboardService.RestoreDeletedDashboard(c.Req.Context(), &dashboards.RestoreDeletedDashboardCommand{
+		OrgID:     c.SignedInUser.GetOrgID(),
+		UID:       uid,
+		FolderUID: cmd.FolderUID,
+	})
+	if err != nil {
+		var dashboardErr dashboards.DashboardErr
+		if errors.As(err, &dashboardErr) {
+			return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+		}
+		if errors.Is(err, dashboards.ErrFolderSameNameExists) {
+			return response.Error(http.StatusPreconditionFailed, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to restore dashboard", err)
+	}
+
+	url := dashboards.GetDashboardFolderURL(dash.IsFolder, dash.UID, dash.Slug)
+	return response.JSON(http.StatusOK, util.DynMap{
+		"id":      dash.ID,
+		"uid":     dash.UID,
+		"title":   dash.Title,
+		"url":     url,
+		"message": fmt.Sprintf("%s restored", dash.Title),
+	})
+}
+
+// swagger:route DELETE /dashboards/recently-deleted/{uid} dashboards purgeDeletedDashboard
+//
+// Permanently delete a deleted dashboard or folder.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) PurgeDeletedDashboard(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	deleted, rsp := hs.getDeletedDashboard(c, uid)
+	if rsp != nil {
+		return rsp
+	}
+
+	err := hs.DashboardService.PurgeDeletedDashboards(c.Req.Context(), &dashboards.PurgeDeletedDashboardsCommand{
+		OrgID: c.SignedInUser.GetOrgID(),
+		UID:   uid,
+	})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDeletedDashboardNotFound) {
+			return response.Error(http.StatusNotFound, err.Error(), err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to delete dashboard permanently", err)
+	}
+	return response.Success(fmt.Sprintf("%s deleted permanently", deleted.Title))
+}
+
+// swagger:parameters listDeletedDashboards
+type ListDeletedDashboardsParams struct {
+	// Type of the deleted items, dash-db or dash-folder
+	// in:query
+	// required:false
+	Type string `json:"type"`
+	// Only list items deleted from the folder, empty for the root
+	// in:query
+	// required:false
+	FolderUID string `json:"folderUid"`
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters restoreDeletedDashboard
+type RestoreDeletedDashboardParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:false
+	Body dtos.RestoreDeletedDashboardCommand
+}
+
+// swagger:parameters purgeDeletedDashboard
+type PurgeDeletedDashboardParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response listDeletedDashboardsResponse
+type ListDeletedDashboardsResponse struct {
+	// in: body
+	Body []*dashboards.DeletedDashboard `json:"body"`
+}
+
+// swagger:response restoreDeletedDashboardResponse
+type RestoreDeletedDashboardResponse struct {
+	// in: body
+	Body struct {
+		ID      int64  `json:"id"`
+		UID     string `json:"uid"`
+		Title   string `json:"title"`
+		URL     string `json:"url"`
+		Message string `json:"message"`
+	} `json:"body"`
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestRestoreDeletedDashboard(t *testing.T) {
+	canManageDeleted := []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsDelete, Scope: dashboards.ScopeDashboardsAll},
+		{Action: dashboards.ActionFoldersDelete, Scope: dashboards.ScopeFoldersAll},
+	}
+
+	type testCase struct {
+		desc         string
+		deleted      *dashboards.DeletedDashboard
+		body         string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}
+	tcs := []testCase{
+		{
+			desc:         "dashboard is restored to its folder",
+			deleted:      &dashboards.DeletedDashboard{UID: "dash", FolderUID: "folder"},
+			permissions:  append(canManageDeleted, accesscontrol.Permission{Action: dashboards.ActionDashboardsCreate, Scope: "folders:uid:folder"}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "dashboard is not restored without create permission in its folder",
+			deleted:      &dashboards.DeletedDashboard{UID: "dash", FolderUID: "folder"},
+			permissions:  append(canManageDeleted, accesscontrol.Permission{Action: dashboards.ActionDashboardsCreate, Scope: "folders:uid:other"}),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "dashboard is not restored to a folder without create permission",
+			deleted:      &dashboards.DeletedDashboard{UID: "dash", FolderUID: "folder"},
+			body:         `{"folderUid": "other"}`,
+			permissions:  append(canManageDeleted, accesscontrol.Permission{Action: dashboards.ActionDashboardsCreate, Scope: "folders:uid:folder"}),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "dashboard is restored to the general folder",
+			deleted:      &dashboards.DeletedDashboard{UID: "dash", FolderUID: "folder"},
+			body:         `{"folderUid": ""}`,
+			permissions:  append(canManageDeleted, accesscontrol.Permission{Action: dashboards.ActionDashboardsCreate, Scope: "folders:uid:general"}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "folder is not restored into a folder without write permission",
+			deleted:      &dashboards.DeletedDashboard{UID: "sub", FolderUID: "folder", IsFolder: true},
+			permissions:  append(canManageDeleted, accesscontrol.Permission{Action: dashboards.ActionFoldersCreate}),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:    "folder is restored into its parent folder",
+			deleted: &dashboards.DeletedDashboard{UID: "sub", FolderUID: "folder", IsFolder: true},
+			permissions: append(canManageDeleted,
+				accesscontrol.Permission{Action: dashboards.ActionFoldersCreate},
+				accesscontrol.Permission{Action: dashboards.ActionFoldersWrite, Scope: "folders:uid:folder"},
+			),
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.desc, func(t *testing.T) {
+			dashboardService := dashboards.NewFakeDashboardService(t)
+			dashboardService.On("GetDeletedDashboard", mock.Anything, mock.Anything).Return(tc.deleted, nil)
+			if tc.expectedCode == http.StatusOK {
+				dashboardService.On("RestoreDeletedDashboard", mock.Anything, mock.Anything).
+					Return(&dashboards.Dashboard{UID: tc.deleted.UID, IsFolder: tc.deleted.IsFolder}, nil)
+			}
+
+			srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.DashboardService = dashboardService
+			})
+
+			req := srv.NewRequest(http.MethodPost, "/api/dashboards/recently-deleted/"+tc.deleted.UID+"/restore", strings.NewReader(tc.body))
+			req = webtest.RequestWithSignedInUser(req, userWithPermissions(1, tc.permissions))
+			resp, err := srv.SendJSON(req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCode, resp.StatusCode)
+			require.NoError(t, resp.Body.Close())
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/uid/{uid}/lock dashboards getDashboardLock
+//
+// Get the edit lock of a dashboard.
+//
+// Returns who is currently editing the dashboard.
+//
+// Responses:
+// 200: dashboardLockResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardLock(c *contextmodel.ReqContext) response.Response {
+	dash, rsp := hs.getDashboardForLock(c, false)
+	if rsp != nil {
+		return rsp
+	}
+
+	lock, err := hs.dashboardLockService.GetLock(c.Req.Context(), &dashboardlock.GetLockQuery{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardUID: dash.UID,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard lock", err)
+	}
+	return response.JSON(http.StatusOK, hs.toDashboardLockDTO(c.Req.Context(), lock))
+}
+
+// swagger:route POST /dashboards/uid/{uid}/lock dashboards acquireDashboardLock
+//
+// Acquire the edit lock of a dashboard.
+//
+// Acquires the lock or renews the lease of a lock held by the signed in user.
+// The lock is advisory, it tells other editors who is editing the dashboard
+// but does not prevent saving. Set force to take over a lock held by another user.
+//
+// Responses:
+// 200: dashboardLockResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 500: internalServerError
+func (hs *HTTPServer) AcquireDashboardLock(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.AcquireDashboardLockCommand{}
+	if c.Req.ContentLength > 0 {
+		if err := web.Bind(c.Req, &cmd); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	}
+	if c.QueryBool("force") {
+		cmd.Force = true
+	}
+
+	userID, rsp := lockUserID(c)
+	if rsp != nil {
+		return rsp
+	}
+	dash, rsp := hs.getDashboardForLock(c, true)
+	if rsp != nil {
+		return rsp
+	}
+
+	lock, err := hs.dashboardLockService.AcquireLock(c.Req.Context(), &dashboardlock.AcquireLockCommand{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardUID: dash.UID,
+		UserID:       userID,
+		Lease:        time.Duration(cmd.LeaseSeconds) * time.Second,
+		Force:        cmd.Force,
+	})
+	if err != nil {
+		if errors.Is(err, dashboardlock.ErrLockedByAnotherUser) && lock != nil {
+			return response.JSON(http.StatusConflict, util.DynMap{
+				"status":  "locked",
+				"message": "Dashboard is being edited by another user",
+				"lock":    hs.toDashboardLockDTO(c.Req.Context(), lock),
+			})
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to acquire dashboard lock", err)
+	}
+
+	if hs.Live != nil {
+		userDTODisplay, err := user.NewUserDisplayDTOFromRequester(c.SignedInUser)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Error while parsing the user DTO model", err)
+		}
+		if err := hs.Live.GrafanaScope.Dashboards.DashboardLockAcquired(c.SignedInUser.GetOrgID(), userDTODisplay, lock); err != nil {
+			hs.log.Warn("Unable to broadcast dashboard lock event", "uid", dash.UID, "error", err)
+		}
+	}
+
+	return response.JSON(http.StatusOK, hs.toDashboardLockDTO(c.Req.Context(), lock))
+}
+
+// swagger:route DELETE /dashboards/uid/{uid}/lock dashboards releaseDashboardLock
+//
+// Release the edit lock of a dashboard.
+//
+// Releases the lock held by the signed in user. Set force to release a lock held by another user.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) ReleaseDashboardLock(c *contextmodel.ReqContext) response.Response {
+	userID, rsp := lockUserID(c)
+	if rsp != nil {
+		return rsp
+	}
+	dash, rsp := hs.getDashboardForLock(c, true)
+	if rsp != nil {
+		return rsp
+	}
+
+	err := hs.dashboardLockService.ReleaseLock(c.Req.Context(), &dashboardlock.ReleaseLockCommand{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardUID: dash.UID,
+		UserID:       userID,
+		Force:        c.QueryBool("force"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to release dashboard lock", err)
+	}
+
+	if hs.Live != nil {
+		userDTODisplay, err := user.NewUserDisplayDTOFromRequester(c.SignedInUser)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Error while parsing the user DTO model", err)
+		}
+		if err := hs.Live.GrafanaScope.Dashboards.DashboardLockReleased(c.SignedInUser.GetOrgID(), userDTODisplay, dash.UID); err != nil {
+			hs.log.Warn("Unable to broadcast dashboard lock event", "uid", dash.UID, "error", err)
+		}
+	}
+
+	return response.Success("Dashboard lock released")
+}
+
+// getDashboardForLock returns the dashboard if the user can view it, or edit
+// it when edit is set.
+func (hs *HTTPServer) getDashboardForLock(c *contextmodel.ReqContext, edit bool) (*dashboards.Dashboard, response.Response) {
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return nil, rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return nil, response.Err(err)
+	}
+	allowed, err := guardian.CanView()
+	if edit && err == nil && allowed {
+		allowed, err = guardian.CanEdit()
+	}
+	if err != nil || !allowed {
+		return nil, dashboardGuardianResponse(err)
+	}
+	return dash, nil
+}
+
+func lockUserID(c *contextmodel.ReqContext) (int64, response.Response) {
+	namespaceID, identifier := c.SignedInUser.GetNamespacedID()
+	if namespaceID != identity.NamespaceUser && namespaceID != identity.NamespaceServiceAccount {
+		return 0, response.Error(http.StatusBadRequest, "Dashboard locks can only be held by users and service accounts", nil)
+	}
+	userID, err := identity.IntIdentifier(namespaceID, identifier)
+	if err != nil {
+		return 0, response.Error(http.StatusInternalServerError, "Failed to parse user ID", err)
+	}
+	return userID, nil
+}
+
+func (hs *HTTPServer) toDashboardLockDTO(ctx context.Context, lock *dashboardlock.Lock) *dtos.DashboardLock {
+	dto := &dtos.DashboardLock{
+		DashboardUID: lock.DashboardUID,
+		User:         &user.UserDisplayDTO{ID: lock.UserID},
+		Acquired:     lock.Acquired,
+		Expires:      lock.Expires,
+	}
+	usr, err := hs.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: lock.UserID})
+	if err != nil {
+		hs.log.Debug("Failed to get user holding dashboard lock", "userID", lock.UserID, "error", err)
+		return dto
+	}
+	dto.User.UID = usr.UID
+	dto.User.Login = usr.Login
+	dto.User.Name = usr.Name
+	dto.User.AvatarURL = dtos.GetGravatarUrl(hs.Cfg, usr.Email)
+	return dto
+}
+
+// swagger:parameters getDashboardLock releaseDashboardLock
+type DashboardLockParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// Release a lock held by another user
+	// in:query
+	// required:false
+	Force bool `json:"force"`
+}
+
+// swagger:parameters acquireDashboardLock
+type AcquireDashboardLockParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:false
+	Body dtos.AcquireDashboardLockCommand
+}
+
+// swagger:response dashboardLockResponse
+type DashboardLockResponse struct {
+	// in: body
+	Body dtos.DashboardLock `json:"body"`
+}
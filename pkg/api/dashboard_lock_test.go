@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
+	"github.com/grafana/grafana/pkg/services/dashboardlock/dashboardlocktest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_DashboardLock(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	lock := &dashboardlock.Lock{OrgID: 1, DashboardUID: "dash", UserID: 2, Acquired: now, Expires: now.Add(dashboardlock.DefaultLease)}
+
+	setup := func(t *testing.T, lockService *dashboardlocktest.FakeDashboardLockService, canEdit bool) *webtest.Server {
+		t.Helper()
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{ID: 1, UID: "dash", OrgID: 1}, nil)
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true, CanEditValue: canEdit})
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.DashboardService = dashboardService
+			hs.dashboardLockService = lockService
+			hs.userService = &usertest.FakeUserService{ExpectedUser: &user.User{ID: 2, UID: "editor", Login: "editor"}}
+		})
+	}
+	editor := func(userID int64) *user.SignedInUser {
+		usr := userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeDashboardsAll},
+			{Action: dashboards.ActionDashboardsWrite, Scope: dashboards.ScopeDashboardsAll},
+		})
+		usr.IsAnonymous = false
+		usr.UserID = userID
+		return usr
+	}
+
+	t.Run("Should acquire the lock with the requested lease", func(t *testing.T) {
+		lockService := &dashboardlocktest.FakeDashboardLockService{ExpectedLock: lock}
+		server := setup(t, lockService, true)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			newAcquireLockRequest(server, "", `{"leaseSeconds": 60}`),
+			editor(2),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body dtos.DashboardLock
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, "dash", body.DashboardUID)
+		assert.Equal(t, "editor", body.User.Login)
+
+		require.NotNil(t, lockService.AcquireLockCmd)
+		assert.Equal(t, int64(2), lockService.AcquireLockCmd.UserID)
+		assert.Equal(t, time.Minute, lockService.AcquireLockCmd.Lease)
+		assert.False(t, lockService.AcquireLockCmd.Force)
+	})
+
+	t.Run("Should return conflict with the current lock when locked by another user", func(t *testing.T) {
+		lockService := &dashboardlocktest.FakeDashboardLockService{
+			ExpectedLock:  lock,
+			ExpectedError: dashboardlock.ErrLockedByAnotherUser.Errorf("locked"),
+		}
+		server := setup(t, lockService, true)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			newAcquireLockRequest(server, "", ""),
+			editor(3),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+
+		var body struct {
+			Status string             `json:"status"`
+			Lock   dtos.DashboardLock `json:"lock"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, "locked", body.Status)
+		assert.Equal(t, int64(2), body.Lock.User.ID)
+	})
+
+	t.Run("Should take over the lock with force", func(t *testing.T) {
+		lockService := &dashboardlocktest.FakeDashboardLockService{ExpectedLock: lock}
+		server := setup(t, lockService, true)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			newAcquireLockRequest(server, "?force=true", ""),
+			editor(3),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		assert.True(t, lockService.AcquireLockCmd.Force)
+	})
+
+	t.Run("Should reject a lease above the maximum", func(t *testing.T) {
+		lockService := &dashboardlocktest.FakeDashboardLockService{
+			ExpectedError: dashboardlock.ErrCommandValidationFailed.Errorf("lease too long"),
+		}
+		server := setup(t, lockService, true)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			newAcquireLockRequest(server, "", `{"leaseSeconds": 7200}`),
+			editor(2),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, 2*time.Hour, lockService.AcquireLockCmd.Lease)
+	})
+
+	t.Run("Should not acquire the lock without edit access to the dashboard", func(t *testing.T) {
+		lockService := &dashboardlocktest.FakeDashboardLockService{ExpectedLock: lock}
+		server := setup(t, lockService, false)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			newAcquireLockRequest(server, "", ""),
+			editor(2),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		assert.Nil(t, lockService.AcquireLockCmd)
+	})
+
+	t.Run("Should release the lock", func(t *testing.T) {
+		lockService := &dashboardlocktest.FakeDashboardLockService{}
+		server := setup(t, lockService, true)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodDelete, "/api/dashboards/uid/dash/lock", nil),
+			editor(2),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		require.NotNil(t, lockService.ReleaseLockCmd)
+		assert.Equal(t, int64(2), lockService.ReleaseLockCmd.UserID)
+		assert.Equal(t, "dash", lockService.ReleaseLockCmd.DashboardUID)
+	})
+
+	t.Run("Should not release a lock held by another user", func(t *testing.T) {
+		lockService := &dashboardlocktest.FakeDashboardLockService{
+			ExpectedError: dashboardlock.ErrLockNotOwned.Errorf("not owner"),
+		}
+		server := setup(t, lockService, true)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodDelete, "/api/dashboards/uid/dash/lock", nil),
+			editor(3),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should return the active lock", func(t *testing.T) {
+		server := setup(t, &dashboardlocktest.FakeDashboardLockService{ExpectedLock: lock}, false)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/dashboards/uid/dash/lock"),
+			editor(3),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body dtos.DashboardLock
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.NoError(t, res.Body.Close())
+		assert.True(t, body.Expires.Equal(lock.Expires))
+	})
+
+	t.Run("Should return not found when the lock expired", func(t *testing.T) {
+		server := setup(t, &dashboardlocktest.FakeDashboardLockService{
+			ExpectedError: dashboardlock.ErrLockNotFound.Errorf("lock expired"),
+		}, false)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/dashboards/uid/dash/lock"),
+			editor(3),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
+func newAcquireLockRequest(server *webtest.Server, query, body string) *http.Request {
+	req := server.NewPostRequest("/api/dashboards/uid/dash/lock"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
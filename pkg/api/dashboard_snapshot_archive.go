@@ -0,0 +1,362 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// maxSnapshotArchiveSize limits the size of imported snapshot archives.
+const maxSnapshotArchiveSize = 64 << 20
+
+// swagger:route GET /snapshots/{key}/export snapshots exportDashboardSnapshot
+//
+// Export a snapshot as a self-contained archive.
+//
+// The archive holds the dashboard including the frozen panel data and can be
+// imported into another Grafana instance. The HTML format additionally renders
+// the panel data as tables, so it can be viewed without Grafana.
+//
+// Responses:
+// 200: exportDashboardSnapshotResponse
+// 400: badRequestError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) ExportDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	snapshot, err := hs.dashboardsnapshotsService.GetDashboardSnapshot(c.Req.Context(), &dashboardsnapshots.GetDashboardSnapshotQuery{
+		Key: web.Params(c.Req)[":key"],
+	})
+	if err != nil {
+		return response.Err(err)
+	}
+	if snapshot.Expires.Before(time.Now()) {
+		return response.Error(http.StatusNotFound, "Dashboard snapshot not found", nil)
+	}
+
+	archive, err := dashboardsnapshots.NewArchive(snapshot)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to export dashboard snapshot", err)
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = dashboardsnapshots.ArchiveFormatJSON
+	}
+	data, err := archive.Export(format)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to export dashboard snapshot", err)
+	}
+
+	contentType := "application/json"
+	if format == dashboardsnapshots.ArchiveFormatHTML {
+		contentType = "text/html; charset=utf-8"
+	}
+	return response.Respond(http.StatusOK, data).
+		SetHeader("Content-Type", contentType).
+		SetHeader("Content-Disposition", fmt.Sprintf(`attachment;filename="snapshot-%s.%s"`, snapshot.Key, format))
+}
+
+// swagger:route POST /snapshots/import snapshots importDashboardSnapshot
+//
+// Import a snapshot archive.
+//
+// Creates a local snapshot from an archive exported with the JSON or HTML
+// format. Snapshot public mode should be enabled or authentication is required.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ImportDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Req.Body, maxSnapshotArchiveSize+1))
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to read snapshot archive", err)
+	}
+	if len(data) > maxSnapshotArchiveSize {
+		return response.Error(http.StatusRequestEntityTooLarge, "Snapshot archive is too large", nil)
+	}
+	archive, err := dashboardsnapshots.ReadArchive(data)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid snapshot archive", err)
+	}
+	dashboard, err := archive.Dashboard.Map()
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid snapshot archive", err)
+	}
+
+	userID, err := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to import snapshot", err)
+	}
+
+	name := c.Query("name")
+	if name == "" {
+		name = archive.Name
+	}
+	if name == "" {
+		name = "Imported snapshot"
+	}
+
+	cmd := &dashboardsnapshots.CreateDashboardSnapshotCommand{
+		DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+			Name:      name,
+			Expires:   c.QueryInt64("expires"),
+			Dashboard: &common.Unstructured{Object: dashboard},
+		},
+		OrgID:  c.SignedInUser.GetOrgID(),
+		UserID: userID,
+	}
+	if cmd.Key, err = util.GetRandomString(32); err != nil {
+		return response.Error(http.StatusInternalServerError, "Could not generate random string", err)
+	}
+	if cmd.DeleteKey, err = util.GetRandomString(32); err != nil {
+		return response.Error(http.StatusInternalServerError, "Could not generate random string", err)
+	}
+
+	result, err := hs.dashboardsnapshotsService.CreateDashboardSnapshot(c.Req.Context(), cmd)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to import snapshot", err)
+	}
+
+	return response.JSON(http.StatusOK, dashboardsnapshot.DashboardCreateResponse{
+		Key:       result.Key,
+		DeleteKey: result.DeleteKey,
+		URL:       setting.ToAbsUrl("dashboard/snapshot/" + result.Key),
+		DeleteURL: setting.ToAbsUrl("api/snapshots-delete/" + result.DeleteKey),
+	})
+}
+
+// swagger:route GET /snapshots/{key}/refresh snapshots getDashboardSnapshotRefreshSchedule
+//
+// Get the refresh schedule of a snapshot.
+//
+// Responses:
+// 200: dashboardSnapshotRefreshScheduleResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardSnapshotRefreshSchedule(c *contextmodel.ReqContext) response.Response {
+	snapshot, rsp := hs.getSnapshotForRefresh(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	schedule, err := hs.dashboardsnapshotsService.GetRefreshSchedule(c.Req.Context(), &dashboardsnapshots.GetSnapshotRefreshScheduleQuery{
+		OrgID:       snapshot.OrgID,
+		SnapshotKey: snapshot.Key,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get snapshot refresh schedule", err)
+	}
+	return response.JSON(http.StatusOK, schedule)
+}
+
+// swagger:route PUT /snapshots/{key}/refresh snapshots saveDashboardSnapshotRefreshSchedule
+//
+// Create or replace the refresh schedule of a snapshot.
+//
+// The snapshot data is re-created on the schedule from the dashboard it was
+// taken from, using the permissions of the signed in user.
+//
+// Responses:
+// 200: dashboardSnapshotRefreshScheduleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) SaveDashboardSnapshotRefreshSchedule(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.SaveSnapshotRefreshScheduleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	snapshot, rsp := hs.getSnapshotForRefresh(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	userID, err := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	if err != nil || userID == 0 {
+		return response.Error(http.StatusBadRequest, "Snapshot refresh schedules can only be owned by users and service accounts", err)
+	}
+
+	dashboardUID := cmd.DashboardUID
+	if dashboardUID == "" && snapshot.Dashboard != nil {
+		dashboardUID = snapshot.Dashboard.Get("uid").MustString()
+	}
+	if dashboardUID == "" {
+		return response.Error(http.StatusBadRequest, "Dashboard UID is required", nil)
+	}
+
+	// The snapshot is refreshed as the signed in user, who must be able to view the dashboard.
+	g, err := guardian.NewByUID(c.Req.Context(), dashboardUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	schedule, err := hs.dashboardsnapshotsService.SaveRefreshSchedule(c.Req.Context(), &dashboardsnapshots.SaveSnapshotRefreshScheduleCommand{
+		OrgID:        snapshot.OrgID,
+		SnapshotKey:  snapshot.Key,
+		DashboardUID: dashboardUID,
+		Schedule:     cmd.Schedule,
+		UserID:       userID,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save snapshot refresh schedule", err)
+	}
+	return response.JSON(http.StatusOK, schedule)
+}
+
+// swagger:route DELETE /snapshots/{key}/refresh snapshots deleteDashboardSnapshotRefreshSchedule
+//
+// Delete the refresh schedule of a snapshot.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteDashboardSnapshotRefreshSchedule(c *contextmodel.ReqContext) response.Response {
+	snapshot, rsp := hs.getSnapshotForRefresh(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	err := hs.dashboardsnapshotsService.DeleteRefreshSchedule(c.Req.Context(), &dashboardsnapshots.DeleteSnapshotRefreshScheduleCommand{
+		OrgID:       snapshot.OrgID,
+		SnapshotKey: snapshot.Key,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete snapshot refresh schedule", err)
+	}
+	return response.Success("Snapshot refresh schedule deleted")
+}
+
+// getSnapshotForRefresh returns the local snapshot, if the signed in user
+// created it or can edit the dashboard it was taken from.
+func (hs *HTTPServer) getSnapshotForRefresh(c *contextmodel.ReqContext) (*dashboardsnapshots.DashboardSnapshot, response.Response) {
+	if !hs.Cfg.SnapshotEnabled {
+		return nil, response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	snapshot, err := hs.dashboardsnapshotsService.GetDashboardSnapshot(c.Req.Context(), &dashboardsnapshots.GetDashboardSnapshotQuery{
+		Key: web.Params(c.Req)[":key"],
+	})
+	if err != nil {
+		return nil, response.Err(err)
+	}
+	if snapshot.OrgID != c.SignedInUser.GetOrgID() {
+		return nil, response.Error(http.StatusNotFound, "Snapshot not found", nil)
+	}
+	if snapshot.External {
+		return nil, response.Err(dashboardsnapshots.ErrExternalSnapshotNotSupported.Errorf("external snapshots can not be refreshed"))
+	}
+
+	userID, err := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	if err == nil && userID != 0 && snapshot.UserID == userID {
+		return snapshot, nil
+	}
+
+	dashboardUID := ""
+	if snapshot.Dashboard != nil {
+		dashboardUID = snapshot.Dashboard.Get("uid").MustString()
+	}
+	if dashboardUID == "" {
+		return nil, response.Error(http.StatusForbidden, "Access denied to this snapshot", nil)
+	}
+	g, err := guardian.NewByUID(c.Req.Context(), dashboardUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil, response.Error(http.StatusForbidden, "Access denied to this snapshot", nil)
+		}
+		return nil, response.Err(err)
+	}
+	if canEdit, err := g.CanEdit(); err != nil || !canEdit {
+		return nil, response.Error(http.StatusForbidden, "Access denied to this snapshot", err)
+	}
+	return snapshot, nil
+}
+
+// swagger:parameters exportDashboardSnapshot
+type ExportDashboardSnapshotParams struct {
+	// in:path
+	Key string `json:"key"`
+	// Archive format
+	// in:query
+	// enum: json,html
+	// default: json
+	Format string `json:"format"`
+}
+
+// swagger:parameters importDashboardSnapshot
+type ImportDashboardSnapshotParams struct {
+	// Snapshot name, defaults to the name stored in the archive
+	// in:query
+	Name string `json:"name"`
+	// When the snapshot should expire in seconds. 0 means never expire.
+	// in:query
+	Expires int64 `json:"expires"`
+	// The exported archive
+	// in:body
+	// required:true
+	Body dashboardsnapshots.Archive `json:"body"`
+}
+
+// swagger:parameters getDashboardSnapshotRefreshSchedule deleteDashboardSnapshotRefreshSchedule
+type DashboardSnapshotRefreshScheduleParams struct {
+	// in:path
+	Key string `json:"key"`
+}
+
+// swagger:parameters saveDashboardSnapshotRefreshSchedule
+type SaveDashboardSnapshotRefreshScheduleParams struct {
+	// in:path
+	Key string `json:"key"`
+	// in:body
+	// required:true
+	Body dtos.SaveSnapshotRefreshScheduleCommand `json:"body"`
+}
+
+// swagger:response exportDashboardSnapshotResponse
+type ExportDashboardSnapshotResponse struct {
+	// in:body
+	Body dashboardsnapshots.Archive `json:"body"`
+}
+
+// swagger:response dashboardSnapshotRefreshScheduleResponse
+type DashboardSnapshotRefreshScheduleResponse struct {
+	// in:body
+	Body dashboardsnapshots.SnapshotRefreshSchedule `json:"body"`
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestHTTPServer_ExportDashboardSnapshot(t *testing.T) {
+	setup := func(t *testing.T, snapshot *dashboardsnapshots.DashboardSnapshot, err error, enabled bool) *webtest.Server {
+		t.Helper()
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("GetDashboardSnapshot", mock.Anything, mock.Anything).Return(snapshot, err).Maybe()
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.Cfg.SnapshotEnabled = enabled
+			hs.dashboardsnapshotsService = svc
+		})
+	}
+	snapshot := &dashboardsnapshots.DashboardSnapshot{
+		OrgID:     1,
+		Key:       "12345",
+		Name:      "snap",
+		Dashboard: simplejson.MustJson([]byte(`{"uid": "dash", "title": "Snapshot"}`)),
+		Expires:   time.Now().Add(time.Hour),
+	}
+
+	t.Run("Should export the snapshot as json archive", func(t *testing.T) {
+		server := setup(t, snapshot, nil, true)
+
+		res, err := server.Send(server.NewGetRequest("/api/snapshots/12345/export"))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+		assert.Equal(t, `attachment;filename="snapshot-12345.json"`, res.Header.Get("Content-Disposition"))
+
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		archive, err := dashboardsnapshots.ReadArchive(body)
+		require.NoError(t, err)
+		assert.Equal(t, "snap", archive.Name)
+		assert.Equal(t, "Snapshot", archive.Dashboard.Get("title").MustString())
+	})
+
+	t.Run("Should return not found for unknown snapshot", func(t *testing.T) {
+		server := setup(t, nil, dashboardsnapshots.ErrBaseNotFound.Errorf("snapshot not found"), true)
+
+		res, err := server.Send(server.NewGetRequest("/api/snapshots/12345/export"))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should return not found for expired snapshot", func(t *testing.T) {
+		expired := *snapshot
+		expired.Expires = time.Now().Add(-time.Hour)
+		server := setup(t, &expired, nil, true)
+
+		res, err := server.Send(server.NewGetRequest("/api/snapshots/12345/export"))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should return forbidden when snapshots are disabled", func(t *testing.T) {
+		server := setup(t, snapshot, nil, false)
+
+		res, err := server.Send(server.NewGetRequest("/api/snapshots/12345/export"))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
+func TestHTTPServer_ImportDashboardSnapshot(t *testing.T) {
+	setup := func(t *testing.T, svc dashboardsnapshots.Service) *webtest.Server {
+		t.Helper()
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.Cfg.SnapshotEnabled = true
+			hs.dashboardsnapshotsService = svc
+		})
+	}
+
+	t.Run("Should create a snapshot from the archive", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("CreateDashboardSnapshot", mock.Anything, mock.MatchedBy(func(cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) bool {
+			return cmd.Name == "snap" && cmd.OrgID == 1 && cmd.UserID == 1 && cmd.Key != "" && cmd.DeleteKey != ""
+		})).Return(&dashboardsnapshots.DashboardSnapshot{Key: "imported", DeleteKey: "delete"}, nil)
+		server := setup(t, svc)
+
+		archive, err := json.Marshal(dashboardsnapshots.Archive{
+			Version:   dashboardsnapshots.ArchiveVersion,
+			Name:      "snap",
+			Dashboard: simplejson.MustJson([]byte(`{"title": "Snapshot"}`)),
+		})
+		require.NoError(t, err)
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewPostRequest("/api/snapshots/import", bytes.NewReader(archive)),
+			&user.SignedInUser{UserID: 1, OrgID: 1},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, "imported", body["key"])
+	})
+
+	t.Run("Should return bad request for invalid archive", func(t *testing.T) {
+		server := setup(t, dashboardsnapshots.NewMockService(t))
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewPostRequest("/api/snapshots/import", strings.NewReader("not an archive")),
+			&user.SignedInUser{UserID: 1, OrgID: 1},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
+func TestHTTPServer_GetDashboardSnapshotRefreshSchedule(t *testing.T) {
+	setup := func(t *testing.T, canEdit bool) *webtest.Server {
+		t.Helper()
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("GetDashboardSnapshot", mock.Anything, mock.Anything).Return(&dashboardsnapshots.DashboardSnapshot{
+			OrgID:     1,
+			Key:       "12345",
+			UserID:    1,
+			Dashboard: simplejson.MustJson([]byte(`{"uid": "dash"}`)),
+		}, nil)
+		svc.On("GetRefreshSchedule", mock.Anything, mock.Anything).Return(&dashboardsnapshots.SnapshotRefreshSchedule{
+			SnapshotKey:  "12345",
+			DashboardUID: "dash",
+			Schedule:     "@hourly",
+		}, nil).Maybe()
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanEditValue: canEdit})
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.Cfg.SnapshotEnabled = true
+			hs.dashboardsnapshotsService = svc
+		})
+	}
+
+	t.Run("Should return the schedule to the snapshot creator", func(t *testing.T) {
+		server := setup(t, false)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/snapshots/12345/refresh"),
+			&user.SignedInUser{UserID: 1, OrgID: 1},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should return the schedule to dashboard editors", func(t *testing.T) {
+		server := setup(t, true)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/snapshots/12345/refresh"),
+			&user.SignedInUser{UserID: 2, OrgID: 1},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should deny access to users who can not edit the dashboard", func(t *testing.T) {
+		server := setup(t, false)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/snapshots/12345/refresh"),
+			&user.SignedInUser{UserID: 2, OrgID: 1},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should return not found for snapshots of other orgs", func(t *testing.T) {
+		server := setup(t, true)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/snapshots/12345/refresh"),
+			&user.SignedInUser{UserID: 1, OrgID: 2},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	dashboardsV0 "github.com/grafana/grafana/pkg/apis/dashboard/v0alpha1"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 type DashboardMeta struct {
@@ -33,6 +34,7 @@ type DashboardMeta struct {
 	FolderUrl              string                             `json:"folderUrl"`
 	Provisioned            bool                               `json:"provisioned"`
 	ProvisionedExternalId  string                             `json:"provisionedExternalId"`
+	ProvisionedCommitSha   string                             `json:"provisionedCommitSha,omitempty"`
 	AnnotationsPermissions *dashboardsV0.AnnotationPermission `json:"annotationsPermissions"`
 	PublicDashboardUID     string                             `json:"publicDashboardUid,omitempty"`
 	PublicDashboardEnabled bool                               `json:"publicDashboardEnabled,omitempty"`
@@ -62,3 +64,23 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+type RestoreDeletedDashboardCommand struct {
+	// FolderUID is the folder to restore the dashboard to, defaults to the
+	// folder it was deleted from.
+	FolderUID *string `json:"folderUid"`
+}
+
+type AcquireDashboardLockCommand struct {
+	// Force takes over a lock held by another user.
+	Force bool `json:"force"`
+	// LeaseSeconds is how long the lock is held without renewal, defaults to 5 minutes.
+	LeaseSeconds int64 `json:"leaseSeconds"`
+}
+
+type DashboardLock struct {
+	DashboardUID string               `json:"dashboardUid"`
+	User         *user.UserDisplayDTO `json:"user"`
+	Acquired     time.Time            `json:"acquired"`
+	Expires      time.Time            `json:"expires"`
+}
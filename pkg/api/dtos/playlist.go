@@ -22,3 +22,10 @@ func (slice PlaylistDashboardsSlice) Less(i, j int) bool {
 func (slice PlaylistDashboardsSlice) Swap(i, j int) {
 	slice[i], slice[j] = slice[j], slice[i]
 }
+
+type PlaylistKioskToken struct {
+	// Token is only returned when it is created.
+	Token string `json:"token"`
+	// URL starts the playlist in kiosk mode on a device.
+	URL string `json:"url"`
+}
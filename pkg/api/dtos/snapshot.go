@@ -0,0 +1,9 @@
+package dtos
+
+type SaveSnapshotRefreshScheduleCommand struct {
+	// Schedule is a cron expression, for example "0 * * * *" or "@daily".
+	// required:true
+	Schedule string `json:"schedule"`
+	// DashboardUID of the dashboard the snapshot is refreshed from, defaults to the dashboard the snapshot was taken from.
+	DashboardUID string `json:"dashboardUid"`
+}
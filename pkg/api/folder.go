@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
@@ -231,6 +232,9 @@ func (hs *HTTPServer) MoveFolder(c *contextmodel.ReqContext) response.Response {
 		cmd.OrgID = c.SignedInUser.GetOrgID()
 		cmd.UID = web.Params(c.Req)[":uid"]
 		cmd.SignedInUser = c.SignedInUser
+		if err := provenance.EnsureNotProvisioned(c.Req.Context(), hs.provenanceService, cmd.OrgID, provenance.KindFolder, cmd.UID); err != nil {
+			return response.Err(err)
+		}
 		theFolder, err := hs.folderService.Move(c.Req.Context(), &cmd)
 		if err != nil {
 			return response.ErrOrFallback(http.StatusInternalServerError, "move folder failed", err)
@@ -268,6 +272,9 @@ func (hs *HTTPServer) UpdateFolder(c *contextmodel.ReqContext) response.Response
 	cmd.OrgID = c.SignedInUser.GetOrgID()
 	cmd.UID = web.Params(c.Req)[":uid"]
 	cmd.SignedInUser = c.SignedInUser
+	if err := provenance.EnsureNotProvisioned(c.Req.Context(), hs.provenanceService, cmd.OrgID, provenance.KindFolder, cmd.UID); err != nil {
+		return response.Err(err)
+	}
 	result, err := hs.folderService.Update(c.Req.Context(), &cmd)
 	if err != nil {
 		return apierrors.ToFolderErrorResponse(err)
@@ -295,6 +302,10 @@ func (hs *HTTPServer) UpdateFolder(c *contextmodel.ReqContext) response.Response
 // 404: notFoundError
 // 500: internalServerError
 func (hs *HTTPServer) DeleteFolder(c *contextmodel.ReqContext) response.Response { // temporarily adding this function to HTTPServer, will be removed from HTTPServer when librarypanels featuretoggle is removed
+	if err := hs.ensureFolderTreeNotProvisioned(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"]); err != nil {
+		return apierrors.ToFolderErrorResponse(err)
+	}
+
 	err := hs.LibraryElementService.DeleteLibraryElementsInFolder(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
 	if err != nil {
 		if errors.Is(err, model.ErrFolderHasConnectedLibraryElements) {
@@ -320,6 +331,26 @@ func (hs *HTTPServer) DeleteFolder(c *contextmodel.ReqContext) response.Response
 	})
 }
 
+// ensureFolderTreeNotProvisioned returns provenance.ErrProvisioned when the
+// folder or any of its descendants, which are deleted along with it, was
+// created from provisioning files.
+func (hs *HTTPServer) ensureFolderTreeNotProvisioned(ctx context.Context, user identity.Requester, uid string) error {
+	if err := provenance.EnsureNotProvisioned(ctx, hs.provenanceService, user.GetOrgID(), provenance.KindFolder, uid); err != nil {
+		return err
+	}
+
+	children, err := hs.folderService.GetChildren(ctx, &folder.GetChildrenQuery{UID: uid, OrgID: user.GetOrgID(), SignedInUser: user})
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := hs.ensureFolderTreeNotProvisioned(ctx, user, child.UID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // swagger:route GET /folders/{folder_uid}/counts folders getFolderDescendantCounts
 //
 // Gets the count of each descendant of a folder by kind. The folder is identified by UID.
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -20,6 +21,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/folder/foldertest"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/search/model"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -255,6 +258,72 @@ func TestFoldersUpdateAPIEndpoint(t *testing.T) {
 	}
 }
 
+func TestHTTPServer_ProvisionedFolder(t *testing.T) {
+	provenanceService := provenancetest.NewFakeService()
+	require.NoError(t, provenanceService.SetProvisioned(context.Background(), 1, provenance.KindFolder, "uid"))
+	folderService := &foldertest.FakeService{ExpectedFolder: &folder.Folder{UID: "uid", Title: "Folder"}}
+	srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.folderService = folderService
+		hs.provenanceService = provenanceService
+	})
+	permissions := []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
+		{Action: dashboards.ActionFoldersDelete, Scope: dashboards.ScopeFoldersAll},
+	}
+
+	t.Run("should not update a provisioned folder", func(t *testing.T) {
+		input := strings.NewReader("{ \"uid\": \"uid\", \"title\": \"Folder upd\" }")
+		req := srv.NewRequest(http.MethodPut, "/api/folders/uid", input)
+		req = webtest.RequestWithSignedInUser(req, userWithPermissions(1, permissions))
+		resp, err := srv.SendJSON(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	})
+
+	t.Run("should not delete a provisioned folder", func(t *testing.T) {
+		req := srv.NewRequest(http.MethodDelete, "/api/folders/uid", nil)
+		req = webtest.RequestWithSignedInUser(req, userWithPermissions(1, permissions))
+		resp, err := srv.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	})
+
+	t.Run("should not delete a folder with a provisioned descendant", func(t *testing.T) {
+		folderService := &folderTreeService{
+			FakeService: foldertest.FakeService{ExpectedFolder: &folder.Folder{UID: "parent", Title: "Parent"}},
+			children: map[string][]*folder.Folder{
+				"parent": {{UID: "child"}},
+				"child":  {{UID: "uid"}},
+			},
+		}
+		srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.folderService = folderService
+			hs.provenanceService = provenanceService
+		})
+
+		req := srv.NewRequest(http.MethodDelete, "/api/folders/parent", nil)
+		req = webtest.RequestWithSignedInUser(req, userWithPermissions(1, permissions))
+		resp, err := srv.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	})
+}
+
+// folderTreeService returns the children of each folder by its UID.
+type folderTreeService struct {
+	foldertest.FakeService
+	children map[string][]*folder.Folder
+}
+
+func (s *folderTreeService) GetChildren(_ context.Context, q *folder.GetChildrenQuery) ([]*folder.Folder, error) {
+	return s.children[q.UID], nil
+}
+
 func testDescription(description string, expectedErr error) string {
 	if expectedErr != nil {
 		return fmt.Sprintf(description, expectedErr.Error())
@@ -39,6 +39,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/login/saml"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/middleware/csrf"
@@ -55,6 +56,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/cleanup"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
@@ -84,6 +86,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	publicdashboardsApi "github.com/grafana/grafana/pkg/services/publicdashboards/api"
 	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
@@ -193,6 +196,7 @@ type HTTPServer struct {
 	folderPermissionsService     accesscontrol.FolderPermissionsService
 	dashboardPermissionsService  accesscontrol.DashboardPermissionsService
 	dashboardVersionService      dashver.Service
+	dashboardLockService         dashboardlock.Service
 	PublicDashboardsApi          *publicdashboardsApi.Api
 	starService                  star.Service
 	playlistService              playlist.Service
@@ -217,6 +221,8 @@ type HTTPServer struct {
 	clientConfigProvider grafanaapiserver.DirectRestConfigProvider
 	namespacer           request.NamespaceMapper
 	anonService          anonymous.Service
+	provenanceService    provenance.Service
+	samlService          saml.Service
 }
 
 type ServerOptions struct {
@@ -249,7 +255,7 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 	avatarCacheServer *avatar.AvatarCacheServer, preferenceService pref.Service,
 	folderPermissionsService accesscontrol.FolderPermissionsService,
 	dashboardPermissionsService accesscontrol.DashboardPermissionsService, dashboardVersionService dashver.Service,
-	starService star.Service, csrfService csrf.Service,
+	dashboardLockService dashboardlock.Service, starService star.Service, csrfService csrf.Service,
 	playlistService playlist.Service, apiKeyService apikey.Service, kvStore kvstore.KVStore,
 	secretsMigrator secrets.Migrator, secretsPluginManager plugins.SecretsPluginManager, secretsService secrets.Service,
 	secretsPluginMigrator spm.SecretMigrationProvider, secretsStore secretsKV.SecretsKVStore,
@@ -259,6 +265,7 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 	annotationRepo annotations.Repository, tagService tag.Service, searchv2HTTPService searchV2.SearchHTTPService, oauthTokenService oauthtoken.OAuthTokenService,
 	statsService stats.Service, authnService authn.Service, pluginsCDNService *pluginscdn.Service, promGatherer prometheus.Gatherer,
 	starApi *starApi.API, promRegister prometheus.Registerer, clientConfigProvider grafanaapiserver.DirectRestConfigProvider, anonService anonymous.Service,
+	provenanceService provenance.Service, samlService saml.Service,
 ) (*HTTPServer, error) {
 	web.Env = cfg.Env
 	m := web.New()
@@ -337,6 +344,7 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 		folderPermissionsService:     folderPermissionsService,
 		dashboardPermissionsService:  dashboardPermissionsService,
 		dashboardVersionService:      dashboardVersionService,
+		dashboardLockService:         dashboardLockService,
 		starService:                  starService,
 		playlistService:              playlistService,
 		apiKeyService:                apiKeyService,
@@ -361,6 +369,8 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 		clientConfigProvider:         clientConfigProvider,
 		namespacer:                   request.GetNamespaceMapper(cfg),
 		anonService:                  anonService,
+		provenanceService:            provenanceService,
+		samlService:                  samlService,
 	}
 	if hs.Listener != nil {
 		hs.log.Debug("Using provided listener")
@@ -575,6 +585,35 @@ func (hs *HTTPServer) tlsCertificates() ([]tls.Certificate, error) {
 	return []tls.Certificate{tlsCert}, nil
 }
 
+// configureClientAuth makes the server request client certificates signed by the
+// CA bundle configured in [auth.mtls], so they can be used to authenticate requests.
+func (hs *HTTPServer) configureClientAuth(tlsCfg *tls.Config) error {
+	if !hs.Cfg.MTLSAuth.Enabled {
+		return nil
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `ca_file` comes from grafana configuration file
+	caPEM, err := os.ReadFile(hs.Cfg.MTLSAuth.CAFile)
+	if err != nil {
+		return fmt.Errorf("could not read mTLS ca_file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no certificates found in mTLS ca_file %q", hs.Cfg.MTLSAuth.CAFile)
+	}
+
+	tlsCfg.ClientCAs = pool
+	tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	if hs.Cfg.MTLSAuth.ClientAuth == setting.MTLSClientAuthRequired {
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	hs.log.Info("HTTP Server client certificate authentication enabled", "client auth", hs.Cfg.MTLSAuth.ClientAuth)
+	return nil
+}
+
 func (hs *HTTPServer) configureHttps() error {
 	tlsCerts, err := hs.tlsCertificates()
 	if err != nil {
@@ -596,6 +635,9 @@ func (hs *HTTPServer) configureHttps() error {
 		MinVersion:   minTlsVersion,
 		CipherSuites: tlsCiphers,
 	}
+	if err := hs.configureClientAuth(tlsCfg); err != nil {
+		return err
+	}
 
 	hs.httpSrv.TLSConfig = tlsCfg
 	hs.httpSrv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
@@ -625,6 +667,9 @@ func (hs *HTTPServer) configureHttp2() error {
 		CipherSuites: tlsCiphers,
 		NextProtos:   []string{"h2", "http/1.1"},
 	}
+	if err := hs.configureClientAuth(tlsCfg); err != nil {
+		return err
+	}
 
 	hs.httpSrv.TLSConfig = tlsCfg
 
@@ -654,6 +699,13 @@ func (hs *HTTPServer) addMiddlewaresAndStaticRoutes() {
 	}
 
 	m.UseMiddleware(middleware.Recovery(hs.Cfg, hs.License))
+	// the IdP posts SAML responses cross-origin, they are protected by their signature instead
+	for _, endpoint := range []string{saml.ACSPath, saml.SLOPath} {
+		if hs.Cfg.ServeFromSubPath {
+			endpoint = hs.Cfg.AppSubURL + endpoint
+		}
+		hs.Csrf.AddSafeEndpoint(endpoint)
+	}
 	m.UseMiddleware(hs.Csrf.Middleware())
 
 	hs.mapStatic(m, hs.Cfg.StaticRootPath, "build", "public/build")
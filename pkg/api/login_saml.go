@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/services/authn"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+const (
+	samlRequestIDCookieName = "saml_request_id"
+	samlMetadataContentType = "application/samlmetadata+xml"
+)
+
+// SAMLLogin starts a service provider initiated login by redirecting the user
+// to the IdP with a signed AuthnRequest.
+func (hs *HTTPServer) SAMLLogin(c *contextmodel.ReqContext) {
+	req := &authn.Request{HTTPRequest: c.Req, Resp: c.Resp}
+	redirect, err := hs.authnService.RedirectURL(c.Req.Context(), authn.ClientSAML, req)
+	if err != nil {
+		c.Redirect(hs.redirectURLWithErrorCookie(c, err))
+		return
+	}
+
+	cookies.WriteCookie(c.Resp, samlRequestIDCookieName, redirect.Extra[authn.KeySAMLRequestID], hs.Cfg.OAuthCookieMaxAge, hs.samlCookieOptions)
+	c.Redirect(redirect.URL)
+}
+
+// SAMLACS is the assertion consumer service the IdP posts SAML responses to.
+func (hs *HTTPServer) SAMLACS(c *contextmodel.ReqContext) {
+	req := &authn.Request{HTTPRequest: c.Req, Resp: c.Resp}
+	identity, err := hs.authnService.Login(c.Req.Context(), authn.ClientSAML, req)
+	// NOTE: always delete the cookie, even if login failed
+	cookies.DeleteCookie(c.Resp, samlRequestIDCookieName, hs.samlCookieOptions)
+
+	if err != nil {
+		c.Redirect(hs.redirectURLWithErrorCookie(c, err))
+		return
+	}
+
+	metrics.MApiLoginSAML.Inc()
+	authn.HandleLoginRedirect(c.Req, c.Resp, hs.Cfg, identity, hs.ValidateRedirectTo)
+}
+
+// SAMLMetadata serves the service provider metadata to register Grafana in the IdP.
+func (hs *HTTPServer) SAMLMetadata(c *contextmodel.ReqContext) {
+	metadata, err := hs.samlService.Metadata()
+	if err != nil {
+		c.JsonApiErr(http.StatusNotFound, "SAML metadata not available", err)
+		return
+	}
+
+	c.Resp.Header().Set("Content-Type", samlMetadataContentType)
+	c.Resp.WriteHeader(http.StatusOK)
+	if _, err := c.Resp.Write(metadata); err != nil {
+		hs.log.Error("Failed to write SAML metadata", "error", err)
+	}
+}
+
+// SAMLSingleLogout receives the LogoutResponse of the IdP once a service
+// provider initiated single logout is completed. The Grafana session has
+// already been revoked when the user was redirected to the IdP.
+func (hs *HTTPServer) SAMLSingleLogout(c *contextmodel.ReqContext) {
+	if err := hs.samlService.ValidateLogoutResponse(c.Req); err != nil {
+		hs.redirectWithError(c, err)
+		return
+	}
+
+	c.Redirect(hs.Cfg.AppSubURL + "/login")
+}
+
+// samlCookieOptions returns the options of the cookie tracking the AuthnRequest.
+// The IdP posts its response cross-site so the cookie is only sent back with
+// SameSite=None, which browsers only accept for secure cookies.
+func (hs *HTTPServer) samlCookieOptions() cookies.CookieOptions {
+	options := hs.CookieOptionsFromCfg()
+	if strings.HasPrefix(hs.Cfg.AppURL, "https://") {
+		options.Secure = true
+		options.SameSiteDisabled = false
+		options.SameSiteMode = http.SameSiteNoneMode
+	}
+	return options
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/login/saml"
+	"github.com/grafana/grafana/pkg/login/saml/samltest"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/models/usertoken"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
@@ -29,9 +31,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/hooks"
 	"github.com/grafana/grafana/pkg/services/licensing"
-	"github.com/grafana/grafana/pkg/services/licensing/licensingtest"
 	loginservice "github.com/grafana/grafana/pkg/services/login"
-	"github.com/grafana/grafana/pkg/services/login/authinfotest"
 	"github.com/grafana/grafana/pkg/services/navtree"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
@@ -496,6 +496,7 @@ func TestLoginOAuthRedirect(t *testing.T) {
 		License:          &licensing.OSSLicensingService{},
 		SocialService:    mock,
 		Features:         featuremgmt.WithFeatures(),
+		samlService:      &samltest.FakeService{ExpectedInfo: &saml.Info{}},
 	}
 
 	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
@@ -649,25 +650,48 @@ func setupAuthProxyLoginTest(t *testing.T, enableLoginToken bool) *scenarioConte
 	return sc
 }
 
+func TestLoginSAMLAutoLogin(t *testing.T) {
+	fakeSetIndexViewData(t)
+	fakeViewIndex(t)
+	sc := setupScenarioContext(t, "/login")
+	cfg := setting.NewCfg()
+	hs := &HTTPServer{
+		Cfg:              cfg,
+		SettingsProvider: &setting.OSSImpl{Cfg: cfg},
+		License:          &licensing.OSSLicensingService{},
+		SocialService:    &mockSocialService{oAuthInfos: map[string]*social.OAuthInfo{}},
+		Features:         featuremgmt.WithFeatures(),
+		samlService:      &samltest.FakeService{ExpectedInfo: &saml.Info{Enabled: true, AutoLogin: true}},
+	}
+
+	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+		hs.LoginView(c)
+		return response.Empty(http.StatusOK)
+	})
+
+	sc.m.Get(sc.url, sc.defaultHandler)
+	sc.fakeReqNoAssertions("GET", sc.url).exec()
+
+	require.Equal(t, 307, sc.resp.Code)
+	assert.Equal(t, "/login/saml", sc.resp.Header().Get("Location"))
+}
+
 func TestLogoutSaml(t *testing.T) {
 	fakeSetIndexViewData(t)
 	fakeViewIndex(t)
 	sc := setupScenarioContextSamlLogout(t, "/logout")
-	license := licensingtest.NewFakeLicensing()
-	license.On("FeatureEnabled", "saml").Return(true)
 
+	idpLogoutURL := "https://idp.example.com/slo?SAMLRequest=request"
 	hs := &HTTPServer{
 		Cfg:              sc.cfg,
 		SettingsProvider: &setting.OSSImpl{Cfg: sc.cfg},
-		License:          license,
+		License:          &licensing.OSSLicensingService{},
 		SocialService:    &mockSocialService{},
 		Features:         featuremgmt.WithFeatures(),
-		authInfoService: &authinfotest.FakeService{
-			ExpectedUserAuth: &loginservice.UserAuth{AuthModule: loginservice.SAMLAuthModule},
-		},
+		log:              log.NewNopLogger(),
+		authnService:     &authntest.FakeService{ExpectedRedirect: &authn.Redirect{URL: idpLogoutURL}},
 	}
 
-	assert.Equal(t, true, hs.samlSingleLogoutEnabled())
 	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
 		c.SignedInUser = &user.SignedInUser{
 			UserID: 1,
@@ -678,6 +702,7 @@ func TestLogoutSaml(t *testing.T) {
 	sc.m.Get(sc.url, sc.defaultHandler)
 	sc.fakeReqNoAssertions("GET", sc.url).exec()
 	require.Equal(t, 302, sc.resp.Code)
+	assert.Equal(t, idpLogoutURL, sc.resp.Header().Get("Location"))
 }
 
 type mockSocialService struct {
@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/apis/playlist/v0alpha1"
 	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
 	internalplaylist "github.com/grafana/grafana/pkg/registry/apis/playlist"
 	grafanaapiserver "github.com/grafana/grafana/pkg/services/apiserver"
 	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
@@ -45,6 +46,10 @@ func (hs *HTTPServer) registerPlaylistAPI(apiRoute routing.RouteRegister) {
 			playlistRoute.Put("/:uid", middleware.ReqEditorRole, hs.validateOrgPlaylist, routing.Wrap(hs.UpdatePlaylist))
 			playlistRoute.Post("/", middleware.ReqEditorRole, routing.Wrap(hs.CreatePlaylist))
 		}
+
+		// Kiosk tokens are only kept in SQL
+		playlistRoute.Post("/:uid/kiosk-token", middleware.ReqEditorRole, hs.validateOrgPlaylist, routing.Wrap(hs.CreatePlaylistKioskToken))
+		playlistRoute.Delete("/:uid/kiosk-token", middleware.ReqEditorRole, hs.validateOrgPlaylist, routing.Wrap(hs.DeletePlaylistKioskToken))
 	})
 }
 
@@ -182,7 +187,7 @@ func (hs *HTTPServer) CreatePlaylist(c *contextmodel.ReqContext) response.Respon
 
 	p, err := hs.playlistService.Create(c.Req.Context(), &cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to create playlist", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create playlist", err)
 	}
 
 	return response.JSON(http.StatusOK, p)
@@ -208,7 +213,7 @@ func (hs *HTTPServer) UpdatePlaylist(c *contextmodel.ReqContext) response.Respon
 
 	_, err := hs.playlistService.Update(c.Req.Context(), &cmd)
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to save playlist", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save playlist", err)
 	}
 
 	dto, err := hs.playlistService.Get(c.Req.Context(), &playlist.GetPlaylistByUidQuery{
@@ -221,6 +226,74 @@ func (hs *HTTPServer) UpdatePlaylist(c *contextmodel.ReqContext) response.Respon
 	return response.JSON(http.StatusOK, dto)
 }
 
+// swagger:route POST /playlists/{uid}/kiosk-token playlists createPlaylistKioskToken
+//
+// Create a playlist kiosk token.
+//
+// The token lets a device run the playlist without a user login, by opening /playlists/kiosk/{token}.
+// The device can only view the dashboards of the playlist the creator of the token can view, and query the data sources of those dashboards the creator can query.
+// They are resolved when the token is created, create a new token after changing the playlist. Creating a token revokes the previous one.
+//
+// Responses:
+// 200: createPlaylistKioskTokenResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CreatePlaylistKioskToken(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	token, err := hs.playlistService.CreateKioskToken(c.Req.Context(), &playlist.CreateKioskTokenCommand{
+		UID:          uid,
+		OrgId:        c.SignedInUser.GetOrgID(),
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create playlist kiosk token", err)
+	}
+
+	return response.JSON(http.StatusOK, dtos.PlaylistKioskToken{
+		Token: token,
+		URL:   hs.Cfg.AppURL + "playlists/kiosk/" + token,
+	})
+}
+
+// swagger:route DELETE /playlists/{uid}/kiosk-token playlists deletePlaylistKioskToken
+//
+// Revoke the kiosk token of a playlist.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeletePlaylistKioskToken(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	err := hs.playlistService.DeleteKioskToken(c.Req.Context(), &playlist.DeleteKioskTokenCommand{
+		UID:   uid,
+		OrgId: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to revoke playlist kiosk token", err)
+	}
+
+	return response.Success("Playlist kiosk token revoked")
+}
+
+// StartPlaylistKiosk stores the kiosk token in a cookie, which authenticates
+// the following requests of the device, and starts the playlist in kiosk mode.
+func (hs *HTTPServer) StartPlaylistKiosk(c *contextmodel.ReqContext) {
+	token := web.Params(c.Req)[":kioskToken"]
+	p, err := hs.playlistService.GetByKioskToken(c.Req.Context(), &playlist.GetPlaylistByKioskTokenQuery{KioskToken: token})
+	if err != nil {
+		errhttp.Write(c.Req.Context(), err, c.Resp)
+		return
+	}
+
+	cookies.WriteCookie(c.Resp, playlist.KioskCookieName, token, 0, hs.CookieOptionsFromCfg)
+	c.Redirect(hs.Cfg.AppSubURL + "/playlists/play/" + p.Uid + "?kiosk")
+}
+
 // swagger:parameters searchPlaylists
 type SearchPlaylistsParams struct {
 	// in:query
@@ -252,6 +325,13 @@ type GetPlaylistDashboardsParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters createPlaylistKioskToken deletePlaylistKioskToken
+type PlaylistKioskTokenParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
 // swagger:parameters deletePlaylist
 type DeletePlaylistParams struct {
 	// in:path
@@ -311,6 +391,12 @@ type UpdatePlaylistResponse struct {
 	Body *playlist.PlaylistDTO `json:"body"`
 }
 
+// swagger:response createPlaylistKioskTokenResponse
+type CreatePlaylistKioskTokenResponse struct {
+	// in: body
+	Body dtos.PlaylistKioskToken `json:"body"`
+}
+
 // swagger:response createPlaylistResponse
 type CreatePlaylistResponse struct {
 	// The response message
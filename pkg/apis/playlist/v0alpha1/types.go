@@ -63,4 +63,20 @@ type Item struct {
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
 	Value string `json:"value"`
+
+	// Duration overrides the interval of the playlist for this item.
+	// +optional
+	Duration string `json:"duration,omitempty"`
+
+	// TimeFrom overrides the start of the time range of the dashboards of this item.
+	// +optional
+	TimeFrom string `json:"timeFrom,omitempty"`
+
+	// TimeTo overrides the end of the time range of the dashboards of this item.
+	// +optional
+	TimeTo string `json:"timeTo,omitempty"`
+
+	// Variables overrides the values of the template variables of the dashboards of this item.
+	// +optional
+	Variables map[string]string `json:"variables,omitempty"`
 }
@@ -14,6 +14,13 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Item) DeepCopyInto(out *Item) {
 	*out = *in
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -93,7 +100,9 @@ func (in *Spec) DeepCopyInto(out *Spec) {
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]Item, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
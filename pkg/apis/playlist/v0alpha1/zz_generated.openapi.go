@@ -47,6 +47,43 @@ func schema_pkg_apis_playlist_v0alpha1_Item(ref common.ReferenceCallback) common
 							Format:      "",
 						},
 					},
+					"duration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Duration overrides the interval of the playlist for this item.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeFrom overrides the start of the time range of the dashboards of this item.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"timeTo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeTo overrides the end of the time range of the dashboards of this item.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"variables": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Variables overrides the values of the template variables of the dashboards of this item.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"type", "value"},
 			},
@@ -167,6 +167,24 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "provisioning",
+		Usage: "Runs provisioning commands",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "dry-run",
+				Usage:  "Validates the provisioning files and prints the changes provisioning them would apply, without applying them. Exits with an error if a file is invalid.",
+				Action: runRunnerCommand(provisioningDryRunCommand),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the result as JSON",
+						Value: false,
+					},
+				},
+			},
+		},
+	},
 	{
 		Name:  "user-manager",
 		Usage: "Runs different helpful user commands",
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
+
+var errInvalidProvisioningFiles = errors.New("invalid provisioning files")
+
+func provisioningDryRunCommand(c utils.CommandLine, runner server.Runner) error {
+	result, err := runner.Provisioning.DryRun(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		logger.Info(string(b) + "\n")
+	} else {
+		printDryRunResult(result)
+	}
+
+	if !result.Valid {
+		return errInvalidProvisioningFiles
+	}
+	return nil
+}
+
+func printDryRunResult(result *dryrun.Result) {
+	if !result.HasChanges() {
+		logger.Infof("No changes %s\n", color.GreenString("✔"))
+	}
+
+	for _, resource := range result.ResourceTypes() {
+		summary := result.Summary[resource]
+		logger.Infof("\n%s: %d to create, %d to update, %d to delete\n", resource, summary.Create, summary.Update, summary.Delete)
+		for _, change := range result.Changes[resource] {
+			logger.Infof("  %s %s (org %d)%s\n", actionSymbol(change.Action), change.Name, change.OrgID, changedFields(change.Fields))
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		logger.Infof("\n%s\n", color.RedString("Invalid provisioning files:"))
+		for _, fileErr := range result.Errors {
+			file := fileErr.File
+			if file == "" {
+				file = string(fileErr.Resource)
+			}
+			logger.Infof("  %s: %s\n", file, fileErr.Error)
+		}
+	}
+}
+
+func actionSymbol(action dryrun.Action) string {
+	switch action {
+	case dryrun.ActionCreate:
+		return color.GreenString("+")
+	case dryrun.ActionUpdate:
+		return color.YellowString("~")
+	case dryrun.ActionDelete:
+		return color.RedString("-")
+	}
+	return string(action)
+}
+
+func changedFields(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" %v", fields)
+}
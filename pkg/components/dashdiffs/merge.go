@@ -0,0 +1,290 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// ErrInvalidMergeInput occurs when one of the merged documents is not a JSON object.
+var ErrInvalidMergeInput = errors.New("dashdiff: merge input must be a JSON object")
+
+// MergeConflict describes a path changed differently on both sides of a merge.
+type MergeConflict struct {
+	Path   string `json:"path"`
+	Base   any    `json:"base,omitempty"`
+	Ours   any    `json:"ours,omitempty"`
+	Theirs any    `json:"theirs,omitempty"`
+}
+
+// MergeResult is the result of a three-way dashboard merge. Dashboard holds the
+// merged model and is only valid when there are no conflicts.
+type MergeResult struct {
+	Dashboard *simplejson.Json
+	Conflicts []MergeConflict
+}
+
+// HasConflicts returns true if the dashboards could not be merged automatically.
+func (r *MergeResult) HasConflicts() bool {
+	return len(r.Conflicts) > 0
+}
+
+// missing marks a key that is not present in one of the merged objects.
+type missing struct{}
+
+// Merge computes a three-way merge of two dashboards derived from base. Objects
+// are merged key by key and panel arrays (including panels nested in rows) are
+// merged by panel id, so changes to different panels never conflict. Any other
+// value changed differently on both sides is reported as a conflict.
+func Merge(base, ours, theirs *simplejson.Json) (*MergeResult, error) {
+	if base == nil || ours == nil || theirs == nil {
+		return nil, ErrInvalidMergeInput
+	}
+
+	// Shortcut when only one side changed the dashboard.
+	if _, _, err := getDiff(base, ours); errors.Is(err, ErrNilDiff) {
+		return &MergeResult{Dashboard: theirs}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if _, _, err := getDiff(base, theirs); errors.Is(err, ErrNilDiff) {
+		return &MergeResult{Dashboard: ours}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	b, err := normalize(base)
+	if err != nil {
+		return nil, err
+	}
+	o, err := normalize(ours)
+	if err != nil {
+		return nil, err
+	}
+	t, err := normalize(theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &merger{}
+	merged := m.mergeObject("", b, o, t)
+	if m.conflicts != nil {
+		return &MergeResult{Conflicts: m.conflicts}, nil
+	}
+	return &MergeResult{Dashboard: simplejson.NewFromAny(merged)}, nil
+}
+
+// normalize converts the dashboard to plain JSON values, so numbers decoded
+// with and without json.Number compare equal.
+func normalize(js *simplejson.Json) (map[string]any, error) {
+	data, err := js.Encode()
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidMergeInput
+	}
+	return obj, nil
+}
+
+type merger struct {
+	conflicts []MergeConflict
+}
+
+func (m *merger) mergeValue(path string, key string, base, ours, theirs any) any {
+	if reflect.DeepEqual(ours, theirs) {
+		return ours
+	}
+	if reflect.DeepEqual(base, ours) {
+		return theirs
+	}
+	if reflect.DeepEqual(base, theirs) {
+		return ours
+	}
+
+	if b, o, t, ok := asObjects(base, ours, theirs); ok {
+		return m.mergeObject(path, b, o, t)
+	}
+	if key == "panels" {
+		if b, o, t, ok := asPanels(base, ours, theirs); ok {
+			return m.mergePanels(path, b, o, t)
+		}
+	}
+
+	m.conflicts = append(m.conflicts, MergeConflict{
+		Path:   path,
+		Base:   exported(base),
+		Ours:   exported(ours),
+		Theirs: exported(theirs),
+	})
+	return ours
+}
+
+func (m *merger) mergeObject(path string, base, ours, theirs map[string]any) map[string]any {
+	keys := map[string]struct{}{}
+	for _, obj := range []map[string]any{base, ours, theirs} {
+		for k := range obj {
+			keys[k] = struct{}{}
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	result := make(map[string]any, len(sorted))
+	for _, k := range sorted {
+		v := m.mergeValue(joinPath(path, k), k, lookup(base, k), lookup(ours, k), lookup(theirs, k))
+		if _, ok := v.(missing); !ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// mergePanels merges panel arrays by panel id. The order of their panels is
+// kept and panels added only on our side are appended.
+func (m *merger) mergePanels(path string, base, ours, theirs []map[string]any) []any {
+	baseByID := panelsByID(base)
+	oursByID := panelsByID(ours)
+	theirsByID := panelsByID(theirs)
+
+	result := make([]any, 0, len(theirs))
+	merge := func(id string) {
+		var b, o, t any = missing{}, missing{}, missing{}
+		if p, ok := baseByID[id]; ok {
+			b = p
+		}
+		if p, ok := oursByID[id]; ok {
+			o = p
+		}
+		if p, ok := theirsByID[id]; ok {
+			t = p
+		}
+		v := m.mergeValue(fmt.Sprintf("%s[id=%s]", path, id), "", b, o, t)
+		if _, ok := v.(missing); !ok {
+			result = append(result, v)
+		}
+	}
+
+	for _, p := range theirs {
+		merge(panelID(p))
+	}
+	for _, p := range ours {
+		id := panelID(p)
+		if _, ok := theirsByID[id]; !ok {
+			merge(id)
+		}
+	}
+	return result
+}
+
+func lookup(obj map[string]any, key string) any {
+	if v, ok := obj[key]; ok {
+		return v
+	}
+	return missing{}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func exported(v any) any {
+	if _, ok := v.(missing); ok {
+		return nil
+	}
+	return v
+}
+
+func asObjects(base, ours, theirs any) (map[string]any, map[string]any, map[string]any, bool) {
+	o, ok := ours.(map[string]any)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	t, ok := theirs.(map[string]any)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	b, ok := base.(map[string]any)
+	if !ok {
+		if _, isMissing := base.(missing); !isMissing {
+			return nil, nil, nil, false
+		}
+		b = map[string]any{}
+	}
+	return b, o, t, true
+}
+
+func asPanels(base, ours, theirs any) ([]map[string]any, []map[string]any, []map[string]any, bool) {
+	o, ok := toPanels(ours)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	t, ok := toPanels(theirs)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	var b []map[string]any
+	if _, isMissing := base.(missing); !isMissing {
+		if b, ok = toPanels(base); !ok {
+			return nil, nil, nil, false
+		}
+	}
+	return b, o, t, true
+}
+
+// toPanels returns the panels of the array, if all of them have a unique id.
+func toPanels(v any) ([]map[string]any, bool) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	panels := make([]map[string]any, 0, len(arr))
+	seen := make(map[string]struct{}, len(arr))
+	for _, item := range arr {
+		p, ok := item.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		id := panelID(p)
+		if id == "" {
+			return nil, false
+		}
+		if _, ok := seen[id]; ok {
+			return nil, false
+		}
+		seen[id] = struct{}{}
+		panels = append(panels, p)
+	}
+	return panels, true
+}
+
+func panelID(p map[string]any) string {
+	id, ok := p["id"]
+	if !ok || id == nil {
+		return ""
+	}
+	return fmt.Sprint(id)
+}
+
+func panelsByID(panels []map[string]any) map[string]any {
+	result := make(map[string]any, len(panels))
+	for _, p := range panels {
+		result[panelID(p)] = p
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package dashdiffs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func mustJSON(t *testing.T, s string) *simplejson.Json {
+	t.Helper()
+	js, err := simplejson.NewJson([]byte(s))
+	require.NoError(t, err)
+	return js
+}
+
+func TestMerge(t *testing.T) {
+	base := `{
+		"title": "dash",
+		"version": 1,
+		"tags": ["a"],
+		"panels": [
+			{"id": 1, "title": "one", "gridPos": {"x": 0, "y": 0}},
+			{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 0}},
+			{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four"}]}
+		]
+	}`
+
+	t.Run("non-overlapping panel edits are merged", func(t *testing.T) {
+		ours := `{
+			"title": "dash",
+			"version": 1,
+			"tags": ["a"],
+			"panels": [
+				{"id": 1, "title": "one edited", "gridPos": {"x": 0, "y": 0}},
+				{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 0}},
+				{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four"}, {"id": 6, "title": "six"}]}
+			]
+		}`
+		theirs := `{
+			"title": "dash",
+			"version": 2,
+			"tags": ["a"],
+			"panels": [
+				{"id": 1, "title": "one", "gridPos": {"x": 0, "y": 0}},
+				{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 8}},
+				{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four edited"}]},
+				{"id": 5, "title": "five"}
+			]
+		}`
+
+		result, err := Merge(mustJSON(t, base), mustJSON(t, ours), mustJSON(t, theirs))
+		require.NoError(t, err)
+		require.False(t, result.HasConflicts())
+
+		merged, err := result.Dashboard.Encode()
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"title": "dash",
+			"version": 2,
+			"tags": ["a"],
+			"panels": [
+				{"id": 1, "title": "one edited", "gridPos": {"x": 0, "y": 0}},
+				{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 8}},
+				{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four edited"}, {"id": 6, "title": "six"}]},
+				{"id": 5, "title": "five"}
+			]
+		}`, string(merged))
+	})
+
+	t.Run("deleted panel is removed when not changed by the other side", func(t *testing.T) {
+		ours := `{"title": "dash", "version": 1, "tags": ["a"], "panels": [
+			{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 0}},
+			{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four"}]}
+		]}`
+		theirs := `{"title": "dash title", "version": 2, "tags": ["a"], "panels": [
+			{"id": 1, "title": "one", "gridPos": {"x": 0, "y": 0}},
+			{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 0}},
+			{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four"}]}
+		]}`
+
+		result, err := Merge(mustJSON(t, base), mustJSON(t, ours), mustJSON(t, theirs))
+		require.NoError(t, err)
+		require.False(t, result.HasConflicts())
+		require.Equal(t, "dash title", result.Dashboard.Get("title").MustString())
+		require.Len(t, result.Dashboard.Get("panels").MustArray(), 2)
+	})
+
+	t.Run("overlapping edits conflict", func(t *testing.T) {
+		ours := `{"title": "dash", "version": 1, "tags": ["b"], "panels": [
+			{"id": 1, "title": "ours", "gridPos": {"x": 0, "y": 0}},
+			{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 0}},
+			{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four"}]}
+		]}`
+		theirs := `{"title": "dash", "version": 2, "tags": ["c"], "panels": [
+			{"id": 1, "title": "theirs", "gridPos": {"x": 0, "y": 0}},
+			{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 0}},
+			{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four"}]}
+		]}`
+
+		result, err := Merge(mustJSON(t, base), mustJSON(t, ours), mustJSON(t, theirs))
+		require.NoError(t, err)
+		require.True(t, result.HasConflicts())
+		require.Nil(t, result.Dashboard)
+		require.Len(t, result.Conflicts, 2)
+		require.Equal(t, "panels[id=1].title", result.Conflicts[0].Path)
+		require.Equal(t, "ours", result.Conflicts[0].Ours)
+		require.Equal(t, "theirs", result.Conflicts[0].Theirs)
+		require.Equal(t, "tags", result.Conflicts[1].Path)
+	})
+
+	t.Run("modified panel deleted by the other side conflicts", func(t *testing.T) {
+		ours := `{"title": "dash", "version": 1, "tags": ["a"], "panels": [
+			{"id": 1, "title": "one edited", "gridPos": {"x": 0, "y": 0}},
+			{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 0}},
+			{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four"}]}
+		]}`
+		theirs := `{"title": "dash", "version": 2, "tags": ["a"], "panels": [
+			{"id": 2, "title": "two", "gridPos": {"x": 12, "y": 0}},
+			{"id": 3, "type": "row", "panels": [{"id": 4, "title": "four"}]}
+		]}`
+
+		result, err := Merge(mustJSON(t, base), mustJSON(t, ours), mustJSON(t, theirs))
+		require.NoError(t, err)
+		require.Len(t, result.Conflicts, 1)
+		require.Equal(t, "panels[id=1]", result.Conflicts[0].Path)
+		require.Nil(t, result.Conflicts[0].Theirs)
+	})
+
+	t.Run("unchanged side returns the other side", func(t *testing.T) {
+		theirs := mustJSON(t, `{"title": "new", "version": 2}`)
+		result, err := Merge(mustJSON(t, base), mustJSON(t, base), theirs)
+		require.NoError(t, err)
+		require.Same(t, theirs, result.Dashboard)
+	})
+}
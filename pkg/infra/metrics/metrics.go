@@ -69,6 +69,15 @@ var (
 	// MApiDashboardSnapshotGet is a metric loaded dashboards
 	MApiDashboardSnapshotGet prometheus.Counter
 
+	// MDashboardSnapshotExpiredDeleted is a metric expired dashboard snapshots deleted by cleanup
+	MDashboardSnapshotExpiredDeleted *prometheus.CounterVec
+
+	// MDashboardSnapshotExpiredDeleteFailed is a metric failures to delete expired external snapshots
+	MDashboardSnapshotExpiredDeleteFailed prometheus.Counter
+
+	// MDashboardSnapshotRefresh is a metric scheduled snapshot refreshes
+	MDashboardSnapshotRefresh *prometheus.CounterVec
+
 	// MApiDashboardInsert is a metric dashboards inserted
 	MApiDashboardInsert prometheus.Counter
 
@@ -364,6 +373,26 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MDashboardSnapshotExpiredDeleted = metricutil.NewCounterVecStartingAtZero(
+		prometheus.CounterOpts{
+			Name:      "dashboard_snapshot_expired_deleted_total",
+			Help:      "expired dashboard snapshots deleted",
+			Namespace: ExporterName,
+		}, []string{"type"}, map[string][]string{"type": {"local", "external"}})
+
+	MDashboardSnapshotExpiredDeleteFailed = metricutil.NewCounterStartingAtZero(prometheus.CounterOpts{
+		Name:      "dashboard_snapshot_expired_delete_failed_total",
+		Help:      "failures to delete expired external dashboard snapshots",
+		Namespace: ExporterName,
+	})
+
+	MDashboardSnapshotRefresh = metricutil.NewCounterVecStartingAtZero(
+		prometheus.CounterOpts{
+			Name:      "dashboard_snapshot_refresh_total",
+			Help:      "scheduled dashboard snapshot refreshes",
+			Namespace: ExporterName,
+		}, []string{"status"}, map[string][]string{"status": {"success", "failure"}})
+
 	MApiDashboardInsert = metricutil.NewCounterStartingAtZero(prometheus.CounterOpts{
 		Name:      "api_models_dashboard_insert_total",
 		Help:      "dashboards inserted ",
@@ -732,6 +761,9 @@ func initMetricVars(reg prometheus.Registerer) {
 		MApiDashboardSnapshotCreate,
 		MApiDashboardSnapshotExternal,
 		MApiDashboardSnapshotGet,
+		MDashboardSnapshotExpiredDeleted,
+		MDashboardSnapshotExpiredDeleteFailed,
+		MDashboardSnapshotRefresh,
 		MApiDashboardInsert,
 		MAlertingResultState,
 		MAlertingNotificationSent,
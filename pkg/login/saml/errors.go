@@ -0,0 +1,10 @@
+package saml
+
+import "github.com/grafana/grafana/pkg/util/errutil"
+
+var (
+	ErrDisabled        = errutil.BadRequest("saml.disabled", errutil.WithPublicMessage("SAML authentication is disabled"))
+	ErrMisconfigured   = errutil.Internal("saml.misconfigured", errutil.WithPublicMessage("SAML authentication is not configured correctly"))
+	ErrInvalidResponse = errutil.Unauthorized("saml.invalid-response", errutil.WithPublicMessage("Invalid SAML response"))
+	ErrInvalidLogout   = errutil.BadRequest("saml.invalid-logout", errutil.WithPublicMessage("Invalid SAML logout response"))
+)
@@ -0,0 +1,127 @@
+package saml
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// ProviderName is the name SAML settings are stored under in the SSO settings service.
+	ProviderName = "saml"
+
+	MetadataPath = "/saml/metadata"
+	ACSPath      = "/saml/acs"
+	SLOPath      = "/saml/slo"
+
+	DefaultNameIDFormat = "urn:oasis:names:tc:SAML:2.0:nameid-format:transient"
+)
+
+// Service is the Grafana SAML 2.0 service provider.
+type Service interface {
+	// GetInfo returns the current SAML settings.
+	GetInfo() *Info
+	// Metadata returns the XML metadata of the Grafana service provider.
+	Metadata() ([]byte, error)
+	// AuthnRequestURL returns the IdP URL carrying a signed AuthnRequest and the ID of that request.
+	AuthnRequestURL(relayState string) (string, string, error)
+	// ParseResponse validates the SAML response posted to the assertion consumer service.
+	// possibleRequestIDs are the IDs of the AuthnRequests the response can answer.
+	ParseResponse(r *http.Request, possibleRequestIDs []string) (*Assertion, error)
+	// LogoutURL returns the IdP URL carrying a signed LogoutRequest for nameID.
+	LogoutURL(nameID, relayState string) (string, error)
+	// ValidateLogoutResponse validates the LogoutResponse sent back by the IdP.
+	ValidateLogoutResponse(r *http.Request) error
+}
+
+type Info struct {
+	AllowIDPInitiated        bool          `mapstructure:"allow_idp_initiated" toml:"allow_idp_initiated"`
+	AllowSignUp              bool          `mapstructure:"allow_sign_up" toml:"allow_sign_up"`
+	AssertionAttributeEmail  string        `mapstructure:"assertion_attribute_email" toml:"assertion_attribute_email"`
+	AssertionAttributeGroups string        `mapstructure:"assertion_attribute_groups" toml:"assertion_attribute_groups"`
+	AssertionAttributeLogin  string        `mapstructure:"assertion_attribute_login" toml:"assertion_attribute_login"`
+	AssertionAttributeName   string        `mapstructure:"assertion_attribute_name" toml:"assertion_attribute_name"`
+	AssertionAttributeRole   string        `mapstructure:"assertion_attribute_role" toml:"assertion_attribute_role"`
+	AutoLogin                bool          `mapstructure:"auto_login" toml:"auto_login"`
+	Certificate              string        `mapstructure:"certificate" toml:"certificate"`
+	CertificatePath          string        `mapstructure:"certificate_path" toml:"certificate_path"`
+	Enabled                  bool          `mapstructure:"enabled" toml:"enabled"`
+	IDPMetadata              string        `mapstructure:"idp_metadata" toml:"idp_metadata"`
+	IDPMetadataPath          string        `mapstructure:"idp_metadata_path" toml:"idp_metadata_path"`
+	IDPMetadataURL           string        `mapstructure:"idp_metadata_url" toml:"idp_metadata_url"`
+	MaxIssueDelay            time.Duration `mapstructure:"max_issue_delay" toml:"max_issue_delay"`
+	MetadataValidDuration    time.Duration `mapstructure:"metadata_valid_duration" toml:"metadata_valid_duration"`
+	Name                     string        `mapstructure:"name" toml:"name"`
+	NameIDFormat             string        `mapstructure:"name_id_format" toml:"name_id_format"`
+	PrivateKey               string        `mapstructure:"private_key" toml:"-"`
+	PrivateKeyPath           string        `mapstructure:"private_key_path" toml:"private_key_path"`
+	RelayState               string        `mapstructure:"relay_state" toml:"relay_state"`
+	RoleValuesAdmin          []string      `mapstructure:"role_values_admin" toml:"role_values_admin"`
+	RoleValuesEditor         []string      `mapstructure:"role_values_editor" toml:"role_values_editor"`
+	RoleValuesGrafanaAdmin   []string      `mapstructure:"role_values_grafana_admin" toml:"role_values_grafana_admin"`
+	RoleValuesNone           []string      `mapstructure:"role_values_none" toml:"role_values_none"`
+	SignatureAlgorithm       string        `mapstructure:"signature_algorithm" toml:"signature_algorithm"`
+	SingleLogout             bool          `mapstructure:"single_logout" toml:"single_logout"`
+	SkipOrgRoleSync          bool          `mapstructure:"skip_org_role_sync" toml:"skip_org_role_sync"`
+}
+
+// Assertion is the subset of a validated SAML assertion Grafana uses to
+// build an identity.
+type Assertion struct {
+	NameID       string
+	SessionIndex string
+	// Attributes are indexed by both the name and the friendly name of each attribute.
+	Attributes map[string][]string
+}
+
+// Attribute returns the first value of the attribute, or an empty string.
+func (a *Assertion) Attribute(name string) string {
+	if values := a.Attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// CreateInfoFromKeyValues decodes SSO settings into Info.
+func CreateInfoFromKeyValues(settingsKV map[string]any) (*Info, error) {
+	emptyStrToSliceDecodeHook := func(from reflect.Type, to reflect.Type, data any) (any, error) {
+		if from.Kind() == reflect.String && to.Kind() == reflect.Slice {
+			strData, _ := data.(string)
+			if strData == "" {
+				return []string{}, nil
+			}
+			return util.SplitString(strData), nil
+		}
+		return data, nil
+	}
+
+	var info Info
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			emptyStrToSliceDecodeHook,
+		),
+		Result:           &info,
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decoder.Decode(settingsKV); err != nil {
+		return nil, err
+	}
+
+	if info.Name == "" {
+		info.Name = "SAML"
+	}
+	if info.NameIDFormat == "" {
+		info.NameIDFormat = DefaultNameIDFormat
+	}
+
+	return &info, nil
+}
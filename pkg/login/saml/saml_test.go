@@ -0,0 +1,39 @@
+package saml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInfoFromKeyValues(t *testing.T) {
+	info, err := CreateInfoFromKeyValues(map[string]any{
+		"enabled":                   true,
+		"single_logout":             "true",
+		"max_issue_delay":           "90s",
+		"metadata_valid_duration":   "48h",
+		"role_values_editor":        "editor, developer",
+		"role_values_admin":         "",
+		"assertion_attribute_login": "mail",
+	})
+	require.NoError(t, err)
+
+	require.True(t, info.Enabled)
+	require.True(t, info.SingleLogout)
+	require.Equal(t, 90*time.Second, info.MaxIssueDelay)
+	require.Equal(t, 48*time.Hour, info.MetadataValidDuration)
+	require.Equal(t, []string{"editor", "developer"}, info.RoleValuesEditor)
+	require.Empty(t, info.RoleValuesAdmin)
+	require.Equal(t, "mail", info.AssertionAttributeLogin)
+	// defaults
+	require.Equal(t, "SAML", info.Name)
+	require.Equal(t, DefaultNameIDFormat, info.NameIDFormat)
+}
+
+func TestAssertion_Attribute(t *testing.T) {
+	assertion := &Assertion{Attributes: map[string][]string{"mail": {"first@example.com", "second@example.com"}}}
+
+	require.Equal(t, "first@example.com", assertion.Attribute("mail"))
+	require.Equal(t, "", assertion.Attribute("login"))
+}
@@ -0,0 +1,438 @@
+package samlimpl
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	crewjamsaml "github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+	dsig "github.com/russellhaering/goxmldsig"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/login/saml"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ssosettings"
+	ssoModels "github.com/grafana/grafana/pkg/services/ssosettings/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	defaultMetadataValidDuration = 48 * time.Hour
+	defaultMaxIssueDelay         = 90 * time.Second
+	defaultSignatureAlgorithm    = "rsa-sha256"
+	metadataFetchTimeout         = 10 * time.Second
+)
+
+var signatureMethods = map[string]string{
+	"rsa-sha1":   dsig.RSASHA1SignatureMethod,
+	"rsa-sha256": dsig.RSASHA256SignatureMethod,
+	"rsa-sha512": dsig.RSASHA512SignatureMethod,
+}
+
+var _ saml.Service = (*Service)(nil)
+var _ ssosettings.Reloadable = (*Service)(nil)
+
+type Service struct {
+	cfg        *setting.Cfg
+	log        log.Logger
+	httpClient *http.Client
+
+	// reloadMutex also guards crewjamsaml.MaxIssueDelay, a global of the SAML
+	// library that is read when responses are parsed. It is only written by
+	// Reload while holding the write lock, ParseResponse holds the read lock.
+	reloadMutex sync.RWMutex
+	info        *saml.Info
+	sp          *crewjamsaml.ServiceProvider
+}
+
+func ProvideService(cfg *setting.Cfg, ssoSettings ssosettings.Service) *Service {
+	s := &Service{
+		cfg:        cfg,
+		log:        log.New("saml"),
+		httpClient: &http.Client{Timeout: metadataFetchTimeout},
+		info:       &saml.Info{},
+	}
+
+	ssoSettings.RegisterReloadable(saml.ProviderName, s)
+
+	settings, err := ssoSettings.GetForProvider(context.Background(), saml.ProviderName)
+	if err != nil {
+		s.log.Error("Failed to get SAML settings", "error", err)
+		return s
+	}
+
+	if err := s.Reload(context.Background(), *settings); err != nil {
+		s.log.Error("Failed to configure SAML", "error", err)
+	}
+
+	return s
+}
+
+func (s *Service) Validate(ctx context.Context, settings ssoModels.SSOSettings, requester identity.Requester) error {
+	info, err := saml.CreateInfoFromKeyValues(settings.Settings)
+	if err != nil {
+		return ssosettings.ErrInvalidSettings.Errorf("SSO settings map cannot be converted to SAML settings: %v", err)
+	}
+
+	if !info.Enabled {
+		return nil
+	}
+
+	if _, err := s.newServiceProvider(ctx, info); err != nil {
+		base := ssosettings.ErrInvalidSettings.Errorf("invalid SAML settings: %v", err)
+		base.PublicMessage = err.Error()
+		return base
+	}
+
+	return nil
+}
+
+func (s *Service) Reload(ctx context.Context, settings ssoModels.SSOSettings) error {
+	info, err := saml.CreateInfoFromKeyValues(settings.Settings)
+	if err != nil {
+		return ssosettings.ErrInvalidSettings.Errorf("SSO settings map cannot be converted to SAML settings: %v", err)
+	}
+
+	var sp *crewjamsaml.ServiceProvider
+	if info.Enabled {
+		sp, err = s.newServiceProvider(ctx, info)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	crewjamsaml.MaxIssueDelay = defaultMaxIssueDelay
+	if info.MaxIssueDelay > 0 {
+		crewjamsaml.MaxIssueDelay = info.MaxIssueDelay
+	}
+	s.info = info
+	s.sp = sp
+
+	return nil
+}
+
+func (s *Service) GetInfo() *saml.Info {
+	s.reloadMutex.RLock()
+	defer s.reloadMutex.RUnlock()
+
+	info := *s.info
+	return &info
+}
+
+func (s *Service) Metadata() ([]byte, error) {
+	sp, err := s.getServiceProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := xml.MarshalIndent(sp.Metadata(), "", "  ")
+	if err != nil {
+		return nil, saml.ErrMisconfigured.Errorf("failed to marshal metadata: %w", err)
+	}
+	return append([]byte(xml.Header), metadata...), nil
+}
+
+func (s *Service) AuthnRequestURL(relayState string) (string, string, error) {
+	sp, err := s.getServiceProvider()
+	if err != nil {
+		return "", "", err
+	}
+
+	location := sp.GetSSOBindingLocation(crewjamsaml.HTTPRedirectBinding)
+	if location == "" {
+		return "", "", saml.ErrMisconfigured.Errorf("identity provider has no HTTP-Redirect single sign-on endpoint")
+	}
+
+	req, err := sp.MakeAuthenticationRequest(location, crewjamsaml.HTTPRedirectBinding, crewjamsaml.HTTPPostBinding)
+	if err != nil {
+		return "", "", saml.ErrMisconfigured.Errorf("failed to create authentication request: %w", err)
+	}
+
+	// the request is signed when a signature method is configured on the service provider
+	redirectURL, err := req.Redirect(relayState, sp)
+	if err != nil {
+		return "", "", saml.ErrMisconfigured.Errorf("failed to sign authentication request: %w", err)
+	}
+
+	return redirectURL.String(), req.ID, nil
+}
+
+func (s *Service) ParseResponse(r *http.Request, possibleRequestIDs []string) (*saml.Assertion, error) {
+	// the lock is held while parsing, as the max issue delay is read from a global
+	s.reloadMutex.RLock()
+	defer s.reloadMutex.RUnlock()
+
+	sp, err := s.serviceProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, err := sp.ParseResponse(r, possibleRequestIDs)
+	if err != nil {
+		// the public part of the error is deliberately vague, the reason is only in the private error
+		var invalidErr *crewjamsaml.InvalidResponseError
+		if errors.As(err, &invalidErr) {
+			return nil, saml.ErrInvalidResponse.Errorf("invalid SAML response: %w", invalidErr.PrivateErr)
+		}
+		return nil, saml.ErrInvalidResponse.Errorf("invalid SAML response: %w", err)
+	}
+
+	return toAssertion(assertion), nil
+}
+
+func (s *Service) LogoutURL(nameID, relayState string) (string, error) {
+	sp, err := s.getServiceProvider()
+	if err != nil {
+		return "", err
+	}
+
+	if sp.GetSLOBindingLocation(crewjamsaml.HTTPRedirectBinding) == "" {
+		return "", saml.ErrMisconfigured.Errorf("identity provider has no HTTP-Redirect single logout endpoint")
+	}
+
+	logoutURL, err := sp.MakeRedirectLogoutRequest(nameID, relayState)
+	if err != nil {
+		return "", saml.ErrMisconfigured.Errorf("failed to create logout request: %w", err)
+	}
+
+	return logoutURL.String(), nil
+}
+
+func (s *Service) ValidateLogoutResponse(r *http.Request) error {
+	sp, err := s.getServiceProvider()
+	if err != nil {
+		return err
+	}
+
+	if err := sp.ValidateLogoutResponseRequest(r); err != nil {
+		return saml.ErrInvalidLogout.Errorf("invalid logout response: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) getServiceProvider() (*crewjamsaml.ServiceProvider, error) {
+	s.reloadMutex.RLock()
+	defer s.reloadMutex.RUnlock()
+
+	return s.serviceProvider()
+}
+
+// serviceProvider expects the caller to hold reloadMutex.
+func (s *Service) serviceProvider() (*crewjamsaml.ServiceProvider, error) {
+	if !s.info.Enabled {
+		return nil, saml.ErrDisabled.Errorf("SAML is disabled")
+	}
+	if s.sp == nil {
+		return nil, saml.ErrMisconfigured.Errorf("SAML service provider is not configured")
+	}
+	return s.sp, nil
+}
+
+func (s *Service) newServiceProvider(ctx context.Context, info *saml.Info) (*crewjamsaml.ServiceProvider, error) {
+	cert, err := loadCertificate(info)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := loadPrivateKey(info)
+	if err != nil {
+		return nil, err
+	}
+
+	if pub, ok := cert.PublicKey.(*rsa.PublicKey); !ok || !pub.Equal(&key.PublicKey) {
+		return nil, errors.New("private key does not match the certificate")
+	}
+
+	signatureAlgorithm := info.SignatureAlgorithm
+	if signatureAlgorithm == "" {
+		signatureAlgorithm = defaultSignatureAlgorithm
+	}
+	signatureMethod, ok := signatureMethods[signatureAlgorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", info.SignatureAlgorithm)
+	}
+
+	idpMetadata, err := s.loadIDPMetadata(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	rootURL := strings.TrimSuffix(s.cfg.AppURL, "/")
+	metadataURL, err := url.Parse(rootURL + saml.MetadataPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root url %q: %w", s.cfg.AppURL, err)
+	}
+	acsURL, _ := url.Parse(rootURL + saml.ACSPath)
+	sloURL, _ := url.Parse(rootURL + saml.SLOPath)
+
+	metadataValidDuration := info.MetadataValidDuration
+	if metadataValidDuration <= 0 {
+		metadataValidDuration = defaultMetadataValidDuration
+	}
+
+	return &crewjamsaml.ServiceProvider{
+		EntityID:              metadataURL.String(),
+		Key:                   key,
+		Certificate:           cert,
+		MetadataURL:           *metadataURL,
+		AcsURL:                *acsURL,
+		SloURL:                *sloURL,
+		IDPMetadata:           idpMetadata,
+		AuthnNameIDFormat:     crewjamsaml.NameIDFormat(info.NameIDFormat),
+		MetadataValidDuration: metadataValidDuration,
+		SignatureMethod:       signatureMethod,
+		AllowIDPInitiated:     info.AllowIDPInitiated,
+		DefaultRedirectURI:    s.cfg.AppSubURL + "/",
+	}, nil
+}
+
+func (s *Service) loadIDPMetadata(ctx context.Context, info *saml.Info) (*crewjamsaml.EntityDescriptor, error) {
+	switch {
+	case info.IDPMetadata != "":
+		data, err := base64.StdEncoding.DecodeString(info.IDPMetadata)
+		if err != nil {
+			return nil, fmt.Errorf("idp_metadata is not valid base64: %w", err)
+		}
+		return parseIDPMetadata(data)
+	case info.IDPMetadataPath != "":
+		// nolint:gosec
+		// We can ignore the gosec G304 warning since the path is set by an administrator
+		data, err := os.ReadFile(info.IDPMetadataPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read idp_metadata_path: %w", err)
+		}
+		return parseIDPMetadata(data)
+	case info.IDPMetadataURL != "":
+		metadataURL, err := url.Parse(info.IDPMetadataURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid idp_metadata_url: %w", err)
+		}
+		metadata, err := samlsp.FetchMetadata(ctx, s.httpClient, *metadataURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch idp metadata: %w", err)
+		}
+		return metadata, nil
+	default:
+		return nil, errors.New("one of idp_metadata, idp_metadata_path or idp_metadata_url must be set")
+	}
+}
+
+func parseIDPMetadata(data []byte) (*crewjamsaml.EntityDescriptor, error) {
+	metadata, err := samlsp.ParseMetadata(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse idp metadata: %w", err)
+	}
+	if len(metadata.IDPSSODescriptors) == 0 {
+		return nil, errors.New("idp metadata has no IDPSSODescriptor")
+	}
+	return metadata, nil
+}
+
+func loadCertificate(info *saml.Info) (*x509.Certificate, error) {
+	block, err := loadPEM(info.Certificate, info.CertificatePath, "certificate")
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return cert, nil
+}
+
+func loadPrivateKey(info *saml.Info) (*rsa.PrivateKey, error) {
+	block, err := loadPEM(info.PrivateKey, info.PrivateKeyPath, "private_key")
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key must be an RSA key")
+	}
+	return key, nil
+}
+
+// loadPEM reads a PEM block either from a base64 encoded value or from a file.
+func loadPEM(value, path, name string) (*pem.Block, error) {
+	var data []byte
+	switch {
+	case value != "":
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not valid base64: %w", name, err)
+		}
+		data = decoded
+	case path != "":
+		// nolint:gosec
+		// We can ignore the gosec G304 warning since the path is set by an administrator
+		read, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s_path: %w", name, err)
+		}
+		data = read
+	default:
+		return nil, fmt.Errorf("%s or %s_path must be set", name, name)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", name)
+	}
+	return block, nil
+}
+
+func toAssertion(assertion *crewjamsaml.Assertion) *saml.Assertion {
+	result := &saml.Assertion{Attributes: map[string][]string{}}
+
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		result.NameID = assertion.Subject.NameID.Value
+	}
+
+	for _, statement := range assertion.AuthnStatements {
+		if statement.SessionIndex != "" {
+			result.SessionIndex = statement.SessionIndex
+			break
+		}
+	}
+
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			values := make([]string, 0, len(attr.Values))
+			for _, v := range attr.Values {
+				values = append(values, v.Value)
+			}
+
+			result.Attributes[attr.Name] = append(result.Attributes[attr.Name], values...)
+			if attr.FriendlyName != "" && attr.FriendlyName != attr.Name {
+				result.Attributes[attr.FriendlyName] = append(result.Attributes[attr.FriendlyName], values...)
+			}
+		}
+	}
+
+	return result
+}
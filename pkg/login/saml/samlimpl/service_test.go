@@ -0,0 +1,197 @@
+package samlimpl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/login/saml"
+	ssoModels "github.com/grafana/grafana/pkg/services/ssosettings/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testIDPMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com/metadata">
+  <IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <KeyDescriptor use="signing">
+      <KeyInfo xmlns="http://www.w3.org/2000/09/xmldsig#">
+        <X509Data><X509Certificate>%s</X509Certificate></X509Data>
+      </KeyInfo>
+    </KeyDescriptor>
+    <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/slo"/>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`
+
+func TestService_Reload(t *testing.T) {
+	s := newTestService()
+	settings := newTestSettings(t)
+
+	require.NoError(t, s.Reload(context.Background(), settings))
+
+	info := s.GetInfo()
+	assert.True(t, info.Enabled)
+	assert.Equal(t, "SAML", info.Name)
+
+	metadata, err := s.Metadata()
+	require.NoError(t, err)
+	assert.Contains(t, string(metadata), "https://grafana.example.com/saml/acs")
+	assert.Contains(t, string(metadata), "https://grafana.example.com/saml/slo")
+}
+
+func TestService_AuthnRequestURL(t *testing.T) {
+	s := newTestService()
+	require.NoError(t, s.Reload(context.Background(), newTestSettings(t)))
+
+	redirectURL, requestID, err := s.AuthnRequestURL("")
+	require.NoError(t, err)
+	assert.NotEmpty(t, requestID)
+
+	u, err := url.Parse(redirectURL)
+	require.NoError(t, err)
+	assert.Equal(t, "idp.example.com", u.Host)
+	assert.Equal(t, "/sso", u.Path)
+	assert.NotEmpty(t, u.Query().Get("SAMLRequest"))
+	assert.NotEmpty(t, u.Query().Get("Signature"))
+	assert.Equal(t, "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256", u.Query().Get("SigAlg"))
+}
+
+func TestService_LogoutURL(t *testing.T) {
+	s := newTestService()
+	require.NoError(t, s.Reload(context.Background(), newTestSettings(t)))
+
+	logoutURL, err := s.LogoutURL("name-id", "")
+	require.NoError(t, err)
+
+	u, err := url.Parse(logoutURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/slo", u.Path)
+	assert.NotEmpty(t, u.Query().Get("SAMLRequest"))
+}
+
+func TestService_Disabled(t *testing.T) {
+	s := newTestService()
+	require.NoError(t, s.Reload(context.Background(), ssoModels.SSOSettings{
+		Provider: saml.ProviderName,
+		Settings: map[string]any{"enabled": false},
+	}))
+
+	_, err := s.Metadata()
+	assert.ErrorIs(t, err, saml.ErrDisabled)
+
+	_, _, err = s.AuthnRequestURL("")
+	assert.ErrorIs(t, err, saml.ErrDisabled)
+
+	req, err := http.NewRequest(http.MethodGet, saml.SLOPath, nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.ValidateLogoutResponse(req), saml.ErrDisabled)
+}
+
+func TestService_Validate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		overrides map[string]any
+		wantErr   bool
+	}{
+		{
+			name: "valid settings",
+		},
+		{
+			name:      "disabled settings are not validated",
+			overrides: map[string]any{"enabled": false, "certificate": ""},
+		},
+		{
+			name:      "missing certificate",
+			overrides: map[string]any{"certificate": ""},
+			wantErr:   true,
+		},
+		{
+			name:      "unsupported signature algorithm",
+			overrides: map[string]any{"signature_algorithm": "hmac-sha1"},
+			wantErr:   true,
+		},
+		{
+			name:      "missing idp metadata",
+			overrides: map[string]any{"idp_metadata": ""},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid idp metadata",
+			overrides: map[string]any{"idp_metadata": base64.StdEncoding.EncodeToString([]byte("<invalid"))},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestService()
+			settings := newTestSettings(t)
+			for k, v := range tc.overrides {
+				settings.Settings[k] = v
+			}
+
+			err := s.Validate(context.Background(), settings, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func newTestService() *Service {
+	cfg := setting.NewCfg()
+	cfg.AppURL = "https://grafana.example.com/"
+
+	return &Service{
+		cfg:        cfg,
+		log:        log.NewNopLogger(),
+		httpClient: http.DefaultClient,
+		info:       &saml.Info{},
+	}
+}
+
+func newTestSettings(t *testing.T) ssoModels.SSOSettings {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "grafana.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	idpMetadata := fmt.Sprintf(testIDPMetadata, base64.StdEncoding.EncodeToString(der))
+
+	return ssoModels.SSOSettings{
+		Provider: saml.ProviderName,
+		Settings: map[string]any{
+			"enabled":      true,
+			"certificate":  base64.StdEncoding.EncodeToString(certPEM),
+			"private_key":  base64.StdEncoding.EncodeToString(keyPEM),
+			"idp_metadata": base64.StdEncoding.EncodeToString([]byte(idpMetadata)),
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package samltest
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/login/saml"
+)
+
+var _ saml.Service = (*FakeService)(nil)
+
+type FakeService struct {
+	ExpectedInfo        *saml.Info
+	ExpectedMetadata    []byte
+	ExpectedURL         string
+	ExpectedRequestID   string
+	ExpectedAssertion   *saml.Assertion
+	ExpectedError       error
+	ExpectedLogoutError error
+
+	// RequestIDs holds the possible request ids of the last parsed response.
+	RequestIDs []string
+}
+
+func (f *FakeService) GetInfo() *saml.Info {
+	return f.ExpectedInfo
+}
+
+func (f *FakeService) Metadata() ([]byte, error) {
+	return f.ExpectedMetadata, f.ExpectedError
+}
+
+func (f *FakeService) AuthnRequestURL(relayState string) (string, string, error) {
+	return f.ExpectedURL, f.ExpectedRequestID, f.ExpectedError
+}
+
+func (f *FakeService) ParseResponse(r *http.Request, possibleRequestIDs []string) (*saml.Assertion, error) {
+	f.RequestIDs = possibleRequestIDs
+	return f.ExpectedAssertion, f.ExpectedError
+}
+
+func (f *FakeService) LogoutURL(nameID, relayState string) (string, error) {
+	return f.ExpectedURL, f.ExpectedError
+}
+
+func (f *FakeService) ValidateLogoutResponse(r *http.Request) error {
+	return f.ExpectedLogoutError
+}
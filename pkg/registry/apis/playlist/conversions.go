@@ -17,12 +17,28 @@ import (
 )
 
 func LegacyUpdateCommandToUnstructured(cmd playlistsvc.UpdatePlaylistCommand) unstructured.Unstructured {
-	items := []map[string]string{}
+	items := []any{}
 	for _, item := range cmd.Items {
-		items = append(items, map[string]string{
+		v := map[string]any{
 			"type":  item.Type,
 			"value": item.Value,
-		})
+		}
+		if item.Duration != "" {
+			v["duration"] = item.Duration
+		}
+		if item.TimeFrom != "" {
+			v["timeFrom"] = item.TimeFrom
+			v["timeTo"] = item.TimeTo
+		}
+		if len(item.Variables) > 0 {
+			// unstructured objects only hold JSON compatible values
+			variables := map[string]any{}
+			for name, value := range item.Variables {
+				variables[name] = value
+			}
+			v["variables"] = variables
+		}
+		items = append(items, v)
 	}
 	obj := unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -72,8 +88,12 @@ func convertToK8sResource(v *playlistsvc.PlaylistDTO, namespacer request.Namespa
 	}
 	for _, item := range v.Items {
 		spec.Items = append(spec.Items, playlist.Item{
-			Type:  playlist.ItemType(item.Type),
-			Value: item.Value,
+			Type:      playlist.ItemType(item.Type),
+			Value:     item.Value,
+			Duration:  item.Duration,
+			TimeFrom:  item.TimeFrom,
+			TimeTo:    item.TimeTo,
+			Variables: item.Variables,
 		})
 	}
 
@@ -115,8 +135,12 @@ func convertToLegacyUpdateCommand(p *playlist.Playlist, orgId int64) (*playlists
 			return nil, fmt.Errorf("unsupported item type: %s", item.Type)
 		}
 		cmd.Items = append(cmd.Items, playlistsvc.PlaylistItem{
-			Type:  string(item.Type),
-			Value: item.Value,
+			Type:      string(item.Type),
+			Value:     item.Value,
+			Duration:  item.Duration,
+			TimeFrom:  item.TimeFrom,
+			TimeTo:    item.TimeTo,
+			Variables: item.Variables,
 		})
 	}
 	return cmd, nil
@@ -62,6 +62,51 @@ type Store interface {
 	DeleteExternalServiceRole(ctx context.Context, externalServiceID string) error
 }
 
+// RoleService manages custom roles and their assignments to users, service
+// accounts and teams. Service accounts are assigned roles as users.
+type RoleService interface {
+	// GetCustomRoles returns the custom roles of the organization, including global ones.
+	GetCustomRoles(ctx context.Context, orgID int64) ([]*RoleDTO, error)
+	GetCustomRole(ctx context.Context, orgID int64, uid string) (*RoleDTO, error)
+	CreateCustomRole(ctx context.Context, orgID int64, cmd CreateRoleCommand) (*RoleDTO, error)
+	UpdateCustomRole(ctx context.Context, orgID int64, uid string, cmd UpdateRoleCommand) (*RoleDTO, error)
+	// DeleteCustomRole removes a custom role along with its assignments.
+	DeleteCustomRole(ctx context.Context, orgID int64, uid string) error
+	GetUserRoles(ctx context.Context, orgID, userID int64) ([]*RoleDTO, error)
+	AddUserRole(ctx context.Context, orgID, userID int64, roleUID string) error
+	RemoveUserRole(ctx context.Context, orgID, userID int64, roleUID string) error
+	GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*RoleDTO, error)
+	AddTeamRole(ctx context.Context, orgID, teamID int64, roleUID string) error
+	RemoveTeamRole(ctx context.Context, orgID, teamID int64, roleUID string) error
+}
+
+// RoleStore persists roles with the given name prefix and their assignments.
+// Roles are looked up in the organization and among global roles.
+type RoleStore interface {
+	GetRoles(ctx context.Context, orgID int64, prefix string) ([]*RoleDTO, error)
+	GetRoleByUID(ctx context.Context, orgID int64, uid string) (*RoleDTO, error)
+	// SaveRole creates or updates the role identified by its uid, and replaces its permissions.
+	SaveRole(ctx context.Context, role *RoleDTO) (*RoleDTO, error)
+	DeleteRole(ctx context.Context, roleID int64) error
+	GetUserRoles(ctx context.Context, orgID, userID int64, prefix string) ([]*RoleDTO, error)
+	AddUserRole(ctx context.Context, orgID, userID, roleID int64) error
+	RemoveUserRole(ctx context.Context, orgID, userID, roleID int64) error
+	GetTeamRoles(ctx context.Context, orgID, teamID int64, prefix string) ([]*RoleDTO, error)
+	AddTeamRole(ctx context.Context, orgID, teamID, roleID int64) error
+	RemoveTeamRole(ctx context.Context, orgID, teamID, roleID int64) error
+	// GetPermissionGrants returns the permissions stored for the users of the organization, along with
+	// the user, team or basic role assignment granting them. All users are returned when userID is 0.
+	GetPermissionGrants(ctx context.Context, orgID, userID int64, rolePrefixes []string) ([]PermissionGrant, error)
+}
+
+// PermissionGrantService explains where the permissions of users and service accounts come from.
+type PermissionGrantService interface {
+	// GetUserPermissionGrants returns the permissions of the user in the organization and what grants them.
+	GetUserPermissionGrants(ctx context.Context, orgID, userID int64) ([]PermissionGrant, error)
+	// SearchUsersPermissionGrants returns the permission grants of all the users of the organization indexed by user ID.
+	SearchUsersPermissionGrants(ctx context.Context, orgID int64) (map[int64][]PermissionGrant, error)
+}
+
 type RoleRegistry interface {
 	// RegisterFixedRoles registers all roles declared to AccessControl
 	RegisterFixedRoles(ctx context.Context) error
@@ -300,6 +345,9 @@ func Reduce(ps []Permission) map[string][]string {
 }
 
 func ValidateScope(scope string) bool {
+	if scope == "" {
+		return false
+	}
 	prefix, last := scope[:len(scope)-1], scope[len(scope)-1]
 	// verify that last char is either ':' or '/' if last character of scope is '*'
 	if len(prefix) > 0 && last == '*' {
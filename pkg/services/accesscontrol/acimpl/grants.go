@@ -0,0 +1,78 @@
+package acimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+var _ accesscontrol.PermissionGrantService = &Service{}
+
+// GetUserPermissionGrants returns the permissions of the user in the organization and what grants them
+func (s *Service) GetUserPermissionGrants(ctx context.Context, orgID, userID int64) ([]accesscontrol.PermissionGrant, error) {
+	grants, err := s.getPermissionGrants(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return grants[userID], nil
+}
+
+// SearchUsersPermissionGrants returns the permission grants of all the users of the organization indexed by user ID
+func (s *Service) SearchUsersPermissionGrants(ctx context.Context, orgID int64) (map[int64][]accesscontrol.PermissionGrant, error) {
+	return s.getPermissionGrants(ctx, orgID, 0)
+}
+
+func (s *Service) getPermissionGrants(ctx context.Context, orgID, userID int64) (map[int64][]accesscontrol.PermissionGrant, error) {
+	var userFilter []int64
+	if userID != 0 {
+		userFilter = []int64{userID}
+	}
+
+	usersRoles, err := s.store.GetUsersBasicRoles(ctx, userFilter, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Permissions of fixed and plugin roles granted to basic roles are kept in RAM
+	res := make(map[int64][]accesscontrol.PermissionGrant, len(usersRoles))
+	for id, roles := range usersRoles {
+		res[id] = s.basicRoleGrants(id, roles)
+	}
+
+	// Stored permissions: managed permissions, custom and external service roles
+	dbGrants, err := s.roleStore.GetPermissionGrants(ctx, orgID, userID, OSSRolesPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	for _, grant := range dbGrants {
+		grant.Source = accesscontrol.GrantSource(grant.RoleName)
+		res[grant.UserID] = append(res[grant.UserID], grant)
+	}
+
+	return res, nil
+}
+
+func (s *Service) basicRoleGrants(userID int64, basicRoles []string) []accesscontrol.PermissionGrant {
+	grants := []accesscontrol.PermissionGrant{}
+	s.registrations.Range(func(registration accesscontrol.RoleRegistration) bool {
+		granted := accesscontrol.BuiltInRolesWithParents(registration.Grants)
+		for _, basicRole := range basicRoles {
+			if _, ok := granted[basicRole]; !ok {
+				continue
+			}
+			for _, p := range registration.Role.Permissions {
+				grants = append(grants, accesscontrol.PermissionGrant{
+					UserID:     userID,
+					Action:     p.Action,
+					Scope:      p.Scope,
+					Source:     accesscontrol.GrantSource(registration.Role.Name),
+					RoleName:   registration.Role.Name,
+					Assignment: accesscontrol.AssignmentBasicRole,
+					BasicRole:  basicRole,
+				})
+			}
+		}
+		return true
+	})
+	return grants
+}
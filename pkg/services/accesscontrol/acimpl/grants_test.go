@@ -0,0 +1,42 @@
+package acimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func TestService_basicRoleGrants(t *testing.T) {
+	ac := setupTestEnv(t)
+	require.NoError(t, ac.DeclareFixedRoles(
+		accesscontrol.RoleRegistration{
+			Role: accesscontrol.RoleDTO{
+				Name:        "fixed:test:reader",
+				Permissions: []accesscontrol.Permission{{Action: "test:read", Scope: "test:*"}},
+			},
+			Grants: []string{"Viewer"},
+		},
+		accesscontrol.RoleRegistration{
+			Role: accesscontrol.RoleDTO{
+				Name:        "fixed:test:writer",
+				Permissions: []accesscontrol.Permission{{Action: "test:write", Scope: "test:*"}},
+			},
+			Grants: []string{"Admin"},
+		},
+	))
+
+	grants := ac.basicRoleGrants(2, []string{"Editor"})
+	require.Equal(t, []accesscontrol.PermissionGrant{
+		{
+			UserID:     2,
+			Action:     "test:read",
+			Scope:      "test:*",
+			Source:     accesscontrol.GrantSourceFixedRole,
+			RoleName:   "fixed:test:reader",
+			Assignment: accesscontrol.AssignmentBasicRole,
+			BasicRole:  "Editor",
+		},
+	}, grants)
+}
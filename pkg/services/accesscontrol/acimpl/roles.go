@@ -0,0 +1,146 @@
+package acimpl
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var _ accesscontrol.RoleService = &Service{}
+
+// GetCustomRoles returns the custom roles of the organization, including global ones
+func (s *Service) GetCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.roleStore.GetRoles(ctx, orgID, accesscontrol.CustomRolePrefix)
+}
+
+func (s *Service) GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	role, err := s.roleStore.GetRoleByUID(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	// Only custom roles can be managed through this service
+	if !strings.HasPrefix(role.Name, accesscontrol.CustomRolePrefix) {
+		return nil, accesscontrol.ErrRoleNotFound
+	}
+	return role, nil
+}
+
+func (s *Service) CreateCustomRole(ctx context.Context, orgID int64, cmd accesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	permissions, err := accesscontrol.ValidateCustomRole(cmd.Name, cmd.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	if _, err := s.roleStore.GetRoleByUID(ctx, orgID, cmd.UID); err == nil {
+		return nil, accesscontrol.ErrRoleAlreadyExists
+	} else if !errors.Is(err, accesscontrol.ErrRoleNotFound) {
+		return nil, err
+	}
+
+	if cmd.Global {
+		orgID = accesscontrol.GlobalOrgID
+	}
+	if cmd.Version == 0 {
+		cmd.Version = 1
+	}
+
+	s.log.Debug("Creating custom role", "uid", cmd.UID, "name", cmd.Name, "orgID", orgID)
+	return s.roleStore.SaveRole(ctx, &accesscontrol.RoleDTO{
+		OrgID:       orgID,
+		Version:     cmd.Version,
+		UID:         cmd.UID,
+		Name:        cmd.Name,
+		DisplayName: cmd.DisplayName,
+		Description: cmd.Description,
+		Group:       cmd.Group,
+		Hidden:      cmd.Hidden,
+		Permissions: permissions,
+	})
+}
+
+func (s *Service) UpdateCustomRole(ctx context.Context, orgID int64, uid string, cmd accesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	permissions, err := accesscontrol.ValidateCustomRole(cmd.Name, cmd.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.GetCustomRole(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Version == 0 {
+		cmd.Version = existing.Version + 1
+	} else if cmd.Version <= existing.Version {
+		return nil, accesscontrol.ErrRoleVersionTooLow
+	}
+
+	s.log.Debug("Updating custom role", "uid", uid, "name", cmd.Name, "version", cmd.Version)
+	return s.roleStore.SaveRole(ctx, &accesscontrol.RoleDTO{
+		OrgID:       existing.OrgID,
+		Version:     cmd.Version,
+		UID:         existing.UID,
+		Name:        cmd.Name,
+		DisplayName: cmd.DisplayName,
+		Description: cmd.Description,
+		Group:       cmd.Group,
+		Hidden:      cmd.Hidden,
+		Permissions: permissions,
+	})
+}
+
+func (s *Service) DeleteCustomRole(ctx context.Context, orgID int64, uid string) error {
+	role, err := s.GetCustomRole(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+
+	s.log.Debug("Deleting custom role", "uid", uid, "name", role.Name)
+	return s.roleStore.DeleteRole(ctx, role.ID)
+}
+
+func (s *Service) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.roleStore.GetUserRoles(ctx, orgID, userID, accesscontrol.CustomRolePrefix)
+}
+
+func (s *Service) AddUserRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	role, err := s.GetCustomRole(ctx, orgID, roleUID)
+	if err != nil {
+		return err
+	}
+	return s.roleStore.AddUserRole(ctx, orgID, userID, role.ID)
+}
+
+func (s *Service) RemoveUserRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	role, err := s.GetCustomRole(ctx, orgID, roleUID)
+	if err != nil {
+		return err
+	}
+	return s.roleStore.RemoveUserRole(ctx, orgID, userID, role.ID)
+}
+
+func (s *Service) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.roleStore.GetTeamRoles(ctx, orgID, teamID, accesscontrol.CustomRolePrefix)
+}
+
+func (s *Service) AddTeamRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	role, err := s.GetCustomRole(ctx, orgID, roleUID)
+	if err != nil {
+		return err
+	}
+	return s.roleStore.AddTeamRole(ctx, orgID, teamID, role.ID)
+}
+
+func (s *Service) RemoveTeamRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	role, err := s.GetCustomRole(ctx, orgID, roleUID)
+	if err != nil {
+		return err
+	}
+	return s.roleStore.RemoveTeamRole(ctx, orgID, teamID, role.ID)
+}
@@ -0,0 +1,83 @@
+package acimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestService_CustomRoles(t *testing.T) {
+	ctx := context.Background()
+	ac := setupTestEnv(t)
+
+	t.Run("should validate the role", func(t *testing.T) {
+		_, err := ac.CreateCustomRole(ctx, 1, accesscontrol.CreateRoleCommand{Name: "reader"})
+		require.ErrorIs(t, err, accesscontrol.ErrCustomRolePrefix)
+
+		_, err = ac.CreateCustomRole(ctx, 1, accesscontrol.CreateRoleCommand{
+			Name:        "custom:reader",
+			Permissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:uid*"}},
+		})
+		require.ErrorIs(t, err, accesscontrol.ErrInvalidScope)
+	})
+
+	role, err := ac.CreateCustomRole(ctx, 1, accesscontrol.CreateRoleCommand{
+		UID:  "custom_reader",
+		Name: "custom:reader",
+		Permissions: []accesscontrol.Permission{
+			{Action: "dashboards:read", Scope: "dashboards:*"},
+			{Action: "dashboards:read", Scope: "dashboards:*"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), role.Version)
+	require.Len(t, role.Permissions, 1)
+
+	t.Run("should not create a role with an existing uid", func(t *testing.T) {
+		_, err := ac.CreateCustomRole(ctx, 1, accesscontrol.CreateRoleCommand{UID: "custom_reader", Name: "custom:other"})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAlreadyExists)
+	})
+
+	t.Run("should bump the version on update", func(t *testing.T) {
+		updated, err := ac.UpdateCustomRole(ctx, 1, "custom_reader", accesscontrol.UpdateRoleCommand{
+			Name:        "custom:reader",
+			Permissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:uid:1"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+
+		_, err = ac.UpdateCustomRole(ctx, 1, "custom_reader", accesscontrol.UpdateRoleCommand{Name: "custom:reader", Version: 2})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleVersionTooLow)
+	})
+
+	t.Run("should grant the permissions of assigned roles", func(t *testing.T) {
+		usr := &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleNone}
+		require.NoError(t, ac.AddUserRole(ctx, 1, 2, "custom_reader"))
+
+		permissions, err := ac.GetUserPermissions(ctx, usr, accesscontrol.Options{})
+		require.NoError(t, err)
+		assert.Contains(t, permissions, accesscontrol.Permission{Action: "dashboards:read", Scope: "dashboards:uid:1"})
+
+		require.NoError(t, ac.RemoveUserRole(ctx, 1, 2, "custom_reader"))
+		permissions, err = ac.GetUserPermissions(ctx, usr, accesscontrol.Options{})
+		require.NoError(t, err)
+		assert.NotContains(t, permissions, accesscontrol.Permission{Action: "dashboards:read", Scope: "dashboards:uid:1"})
+	})
+
+	t.Run("should delete the role", func(t *testing.T) {
+		require.NoError(t, ac.AddTeamRole(ctx, 1, 3, "custom_reader"))
+		require.NoError(t, ac.DeleteCustomRole(ctx, 1, "custom_reader"))
+
+		roles, err := ac.GetTeamRoles(ctx, 1, 3)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+		_, err = ac.GetCustomRole(ctx, 1, "custom_reader")
+		require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+	})
+}
@@ -31,6 +31,7 @@ func TestMain(m *testing.M) {
 func setupTestEnv(t testing.TB) *Service {
 	t.Helper()
 	cfg := setting.NewCfg()
+	store := database.ProvideService(db.InitTestDB(t))
 
 	ac := &Service{
 		cache:         localcache.ProvideService(),
@@ -39,7 +40,8 @@ func setupTestEnv(t testing.TB) *Service {
 		log:           log.New("accesscontrol"),
 		registrations: accesscontrol.RegistrationList{},
 		roles:         accesscontrol.BuildBasicRoleDefinitions(),
-		store:         database.ProvideService(db.InitTestDB(t)),
+		store:         store,
+		roleStore:     store,
 	}
 	require.NoError(t, ac.RegisterFixedRoles(context.Background()))
 	return ac
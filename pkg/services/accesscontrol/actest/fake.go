@@ -150,3 +150,71 @@ func (f *FakePermissionsService) DeleteResourcePermissions(ctx context.Context,
 func (f *FakePermissionsService) MapActions(permission accesscontrol.ResourcePermission) string {
 	return f.ExpectedMappedAction
 }
+
+var _ accesscontrol.RoleService = new(FakeRoleService)
+
+type FakeRoleService struct {
+	ExpectedErr   error
+	ExpectedRole  *accesscontrol.RoleDTO
+	ExpectedRoles []*accesscontrol.RoleDTO
+}
+
+func (f FakeRoleService) GetCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeRoleService) GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f FakeRoleService) CreateCustomRole(ctx context.Context, orgID int64, cmd accesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f FakeRoleService) UpdateCustomRole(ctx context.Context, orgID int64, uid string, cmd accesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f FakeRoleService) DeleteCustomRole(ctx context.Context, orgID int64, uid string) error {
+	return f.ExpectedErr
+}
+
+func (f FakeRoleService) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeRoleService) AddUserRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	return f.ExpectedErr
+}
+
+func (f FakeRoleService) RemoveUserRole(ctx context.Context, orgID, userID int64, roleUID string) error {
+	return f.ExpectedErr
+}
+
+func (f FakeRoleService) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeRoleService) AddTeamRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	return f.ExpectedErr
+}
+
+func (f FakeRoleService) RemoveTeamRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	return f.ExpectedErr
+}
+
+var _ accesscontrol.PermissionGrantService = new(FakePermissionGrantService)
+
+type FakePermissionGrantService struct {
+	ExpectedErr         error
+	ExpectedGrants      []accesscontrol.PermissionGrant
+	ExpectedUsersGrants map[int64][]accesscontrol.PermissionGrant
+}
+
+func (f FakePermissionGrantService) GetUserPermissionGrants(ctx context.Context, orgID, userID int64) ([]accesscontrol.PermissionGrant, error) {
+	return f.ExpectedGrants, f.ExpectedErr
+}
+
+func (f FakePermissionGrantService) SearchUsersPermissionGrants(ctx context.Context, orgID int64) (map[int64][]accesscontrol.PermissionGrant, error) {
+	return f.ExpectedUsersGrants, f.ExpectedErr
+}
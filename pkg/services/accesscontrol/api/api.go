@@ -13,10 +13,12 @@ import (
 )
 
 func NewAccessControlAPI(router routing.RouteRegister, accesscontrol ac.AccessControl, service ac.Service,
-	features featuremgmt.FeatureToggles) *AccessControlAPI {
+	roleService ac.RoleService, grantService ac.PermissionGrantService, features featuremgmt.FeatureToggles) *AccessControlAPI {
 	return &AccessControlAPI{
 		RouteRegister: router,
 		Service:       service,
+		RoleService:   roleService,
+		GrantService:  grantService,
 		AccessControl: accesscontrol,
 		features:      features,
 	}
@@ -24,6 +26,8 @@ func NewAccessControlAPI(router routing.RouteRegister, accesscontrol ac.AccessCo
 
 type AccessControlAPI struct {
 	Service       ac.Service
+	RoleService   ac.RoleService
+	GrantService  ac.PermissionGrantService
 	AccessControl ac.AccessControl
 	RouteRegister routing.RouteRegister
 	features      featuremgmt.FeatureToggles
@@ -38,6 +42,26 @@ func (api *AccessControlAPI) RegisterAPIEndpoints() {
 		if api.features.IsEnabledGlobally(featuremgmt.FlagAccessControlOnCall) {
 			rr.Get("/users/permissions/search", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead)), routing.Wrap(api.searchUsersPermissions))
 		}
+
+		// Access review
+		userScope := ac.Scope("users", "id", ac.Parameter(":userId"))
+		rr.Get("/users/:userId/permissions/explain", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead, userScope)), routing.Wrap(api.explainUserPermissions))
+		rr.Get("/users/permissions/export", authorize(ac.EvalPermission(ac.ActionUsersPermissionsRead, ac.ScopeUsersAll)), routing.Wrap(api.exportUsersPermissions))
+
+		// Custom roles
+		rr.Get("/roles", authorize(ac.EvalPermission(ac.ActionRolesRead, ac.ScopeRolesAll)), routing.Wrap(api.getRoles))
+		rr.Post("/roles", authorize(ac.EvalPermission(ac.ActionRolesWrite, ac.ScopeRolesAll)), routing.Wrap(api.createRole))
+		rr.Get("/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionRolesRead, ac.ScopeRolesUID)), routing.Wrap(api.getRole))
+		rr.Put("/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionRolesWrite, ac.ScopeRolesUID)), routing.Wrap(api.updateRole))
+		rr.Delete("/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionRolesDelete, ac.ScopeRolesUID)), routing.Wrap(api.deleteRole))
+
+		// Role assignments, service accounts are assigned roles as users
+		rr.Get("/users/:userId/roles", authorize(ac.EvalPermission(ac.ActionUsersRolesRead, userScope)), routing.Wrap(api.getUserRoles))
+		rr.Post("/users/:userId/roles", authorize(ac.EvalPermission(ac.ActionUsersRolesAdd, userScope)), routing.Wrap(api.addUserRole))
+		rr.Delete("/users/:userId/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionUsersRolesRemove, userScope)), routing.Wrap(api.removeUserRole))
+		rr.Get("/teams/:teamId/roles", authorize(ac.EvalPermission(ac.ActionTeamsRolesRead, ac.ScopeTeamsID)), routing.Wrap(api.getTeamRoles))
+		rr.Post("/teams/:teamId/roles", authorize(ac.EvalPermission(ac.ActionTeamsRolesAdd, ac.ScopeTeamsID)), routing.Wrap(api.addTeamRole))
+		rr.Delete("/teams/:teamId/roles/:roleUID", authorize(ac.EvalPermission(ac.ActionTeamsRolesRemove, ac.ScopeTeamsID)), routing.Wrap(api.removeTeamRole))
 	}, requestmeta.SetOwner(requestmeta.TeamAuth))
 }
 
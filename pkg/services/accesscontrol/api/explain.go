@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type permissionExplanation struct {
+	UserID  int64               `json:"userId"`
+	Scope   string              `json:"scope"`
+	Actions []actionExplanation `json:"actions"`
+}
+
+type actionExplanation struct {
+	Action  string               `json:"action"`
+	Granted bool                 `json:"granted"`
+	Grants  []ac.PermissionGrant `json:"grants"`
+}
+
+type userPermissionGrants struct {
+	UserID int64                `json:"userId"`
+	Grants []ac.PermissionGrant `json:"grants"`
+}
+
+// GET /api/access-control/users/:userId/permissions/explain
+// Explains which basic role, team, managed permission or role grants the user
+// each action on the scope. Service accounts are explained as users.
+func (api *AccessControlAPI) explainUserPermissions(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+	scope := c.Query("scope")
+	if scope == "" {
+		return response.Error(http.StatusBadRequest, "scope is required", nil)
+	}
+
+	ctx, orgID := c.Req.Context(), c.SignedInUser.GetOrgID()
+	grants, err := api.GrantService.GetUserPermissionGrants(ctx, orgID, userID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user permissions", err)
+	}
+
+	grantsByAction := map[string][]ac.PermissionGrant{}
+	for _, grant := range grants {
+		grantsByAction[grant.Action] = append(grantsByAction[grant.Action], grant)
+	}
+
+	// Without requested actions, only explain the actions granted on the scope
+	actions := c.QueryStrings("action")
+	onlyGranted := len(actions) == 0
+	if onlyGranted {
+		for action := range grantsByAction {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+	}
+
+	res := permissionExplanation{UserID: userID, Scope: scope, Actions: []actionExplanation{}}
+	for _, action := range actions {
+		explanation := actionExplanation{Action: action, Grants: []ac.PermissionGrant{}}
+		evaluator := ac.EvalPermission(action, scope)
+		for _, grant := range grantsByAction[action] {
+			// Evaluate each grant on its own, with scope resolution, to tell which ones apply to the scope
+			grantUser := ac.BackgroundUser("permission_explanation", orgID, org.RoleNone, []ac.Permission{{Action: grant.Action, Scope: grant.Scope}})
+			ok, err := api.AccessControl.Evaluate(ctx, grantUser, evaluator)
+			if err != nil {
+				return response.Error(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+			}
+			if ok {
+				explanation.Grants = append(explanation.Grants, grant)
+			}
+		}
+		explanation.Granted = len(explanation.Grants) > 0
+		if onlyGranted && !explanation.Granted {
+			continue
+		}
+		res.Actions = append(res.Actions, explanation)
+	}
+
+	return response.JSON(http.StatusOK, res)
+}
+
+// GET /api/access-control/users/permissions/export
+// Exports the effective permissions of all the users of the organization, and
+// what grants them, for audits. Use format=csv to get a CSV file.
+func (api *AccessControlAPI) exportUsersPermissions(c *contextmodel.ReqContext) response.Response {
+	grants, err := api.GrantService.SearchUsersPermissionGrants(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get users permissions", err)
+	}
+
+	res := make([]userPermissionGrants, 0, len(grants))
+	for userID, userGrants := range grants {
+		if len(userGrants) == 0 {
+			continue
+		}
+		res = append(res, userPermissionGrants{UserID: userID, Grants: userGrants})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].UserID < res[j].UserID })
+
+	if c.Query("format") != "csv" {
+		return response.JSON(http.StatusOK, res)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"user_id", "action", "scope", "source", "role", "assignment", "team_id", "basic_role"}); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to export users permissions", err)
+	}
+	for _, u := range res {
+		for _, g := range u.Grants {
+			teamID := ""
+			if g.TeamID != 0 {
+				teamID = strconv.FormatInt(g.TeamID, 10)
+			}
+			record := []string{strconv.FormatInt(u.UserID, 10), g.Action, g.Scope, g.Source, g.RoleName, g.Assignment, teamID, g.BasicRole}
+			if err := w.Write(record); err != nil {
+				return response.Error(http.StatusInternalServerError, "Failed to export users permissions", err)
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to export users permissions", err)
+	}
+
+	return response.Respond(http.StatusOK, buf.Bytes()).
+		SetHeader("Content-Type", "text/csv").
+		SetHeader("Content-Disposition", `attachment; filename="permissions.csv"`)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_explainUserPermissions(t *testing.T) {
+	grants := []ac.PermissionGrant{
+		{Action: "folders:write", Scope: "folders:*", Source: ac.GrantSourceFixedRole, RoleName: "fixed:folders:writer", Assignment: ac.AssignmentBasicRole, BasicRole: "Editor"},
+		{Action: "folders:write", Scope: "folders:uid:abc", Source: ac.GrantSourceManagedPermission, RoleName: "managed:teams:3:permissions", Assignment: ac.AssignmentTeam, TeamID: 3},
+		{Action: "folders:write", Scope: "folders:uid:other", Source: ac.GrantSourceManagedPermission, RoleName: "managed:users:2:permissions", Assignment: ac.AssignmentUser},
+		{Action: "folders:read", Scope: "folders:uid:other", Source: ac.GrantSourceManagedPermission, RoleName: "managed:users:2:permissions", Assignment: ac.AssignmentUser},
+	}
+
+	type testCase struct {
+		desc           string
+		query          string
+		expectedCode   int
+		expectedOutput permissionExplanation
+	}
+
+	tests := []testCase{
+		{
+			desc:         "Should require a scope",
+			query:        "",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "Should explain the actions granted on the scope",
+			query:        "?scope=folders:uid:abc",
+			expectedCode: http.StatusOK,
+			expectedOutput: permissionExplanation{UserID: 2, Scope: "folders:uid:abc", Actions: []actionExplanation{
+				{Action: "folders:write", Granted: true, Grants: grants[:2]},
+			}},
+		},
+		{
+			desc:         "Should explain the requested actions",
+			query:        "?scope=folders:uid:abc&action=folders:read&action=folders:write",
+			expectedCode: http.StatusOK,
+			expectedOutput: permissionExplanation{UserID: 2, Scope: "folders:uid:abc", Actions: []actionExplanation{
+				{Action: "folders:read", Granted: false, Grants: []ac.PermissionGrant{}},
+				{Action: "folders:write", Granted: true, Grants: grants[:2]},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			grantSvc := actest.FakePermissionGrantService{ExpectedGrants: grants}
+			api := NewAccessControlAPI(routing.NewRouteRegister(), evaluatingAccessControl{}, actest.FakeService{}, nil, grantSvc, featuremgmt.WithFeatures())
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewGetRequest("/api/access-control/users/2/permissions/explain" + tt.query)
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:       1,
+				Permissions: map[int64]map[string][]string{1: {ac.ActionUsersPermissionsRead: {ac.ScopeUsersAll}}},
+			})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, res.Body.Close()) }()
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+
+			if tt.expectedCode == http.StatusOK {
+				var output permissionExplanation
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&output))
+				require.Equal(t, tt.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestAPI_exportUsersPermissions(t *testing.T) {
+	grantSvc := actest.FakePermissionGrantService{ExpectedUsersGrants: map[int64][]ac.PermissionGrant{
+		3: {{Action: "teams:read", Scope: "teams:id:1", Source: ac.GrantSourceManagedPermission, RoleName: "managed:teams:1:permissions", Assignment: ac.AssignmentTeam, TeamID: 1}},
+		2: {{Action: "users:read", Scope: "users:*", Source: ac.GrantSourceFixedRole, RoleName: "fixed:users:reader", Assignment: ac.AssignmentBasicRole, BasicRole: "Grafana Admin"}},
+		4: {},
+	}}
+	api := NewAccessControlAPI(routing.NewRouteRegister(), evaluatingAccessControl{}, actest.FakeService{}, nil, grantSvc, featuremgmt.WithFeatures())
+	api.RegisterAPIEndpoints()
+	server := webtest.NewServer(t, api.RouteRegister)
+
+	send := func(t *testing.T, url string) *http.Response {
+		req := server.NewGetRequest(url)
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+			OrgID:       1,
+			Permissions: map[int64]map[string][]string{1: {ac.ActionUsersPermissionsRead: {ac.ScopeUsersAll}}},
+		})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		return res
+	}
+
+	t.Run("Should export permissions as JSON sorted by user", func(t *testing.T) {
+		res := send(t, "/api/access-control/users/permissions/export")
+		defer func() { require.NoError(t, res.Body.Close()) }()
+
+		var output []userPermissionGrants
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&output))
+		require.Len(t, output, 2)
+		require.Equal(t, int64(2), output[0].UserID)
+		require.Equal(t, int64(3), output[1].UserID)
+	})
+
+	t.Run("Should export permissions as CSV", func(t *testing.T) {
+		res := send(t, "/api/access-control/users/permissions/export?format=csv")
+		defer func() { require.NoError(t, res.Body.Close()) }()
+
+		require.Equal(t, "text/csv", res.Header.Get("Content-Type"))
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, "user_id,action,scope,source,role,assignment,team_id,basic_role\n"+
+			"2,users:read,users:*,fixed_role,fixed:users:reader,basic_role,,Grafana Admin\n"+
+			"3,teams:read,teams:id:1,managed_permission,managed:teams:1:permissions,team,1,\n", string(body))
+	})
+}
+
+// evaluatingAccessControl evaluates the permissions of the user without scope resolution
+type evaluatingAccessControl struct{}
+
+func (evaluatingAccessControl) Evaluate(ctx context.Context, user identity.Requester, evaluator ac.Evaluator) (bool, error) {
+	return evaluator.Evaluate(user.GetPermissions()), nil
+}
+
+func (evaluatingAccessControl) RegisterScopeAttributeResolver(prefix string, resolver ac.ScopeAttributeResolver) {
+}
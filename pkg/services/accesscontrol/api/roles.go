@@ -0,0 +1,242 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// GET /api/access-control/roles
+func (api *AccessControlAPI) getRoles(c *contextmodel.ReqContext) response.Response {
+	roles, err := api.RoleService.GetCustomRoles(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get roles", err)
+	}
+	return response.JSON(http.StatusOK, roles)
+}
+
+// GET /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) getRole(c *contextmodel.ReqContext) response.Response {
+	role, err := api.RoleService.GetCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":roleUID"])
+	if err != nil {
+		return roleErrorResponse(err, "Failed to get role")
+	}
+	return response.JSON(http.StatusOK, role)
+}
+
+// POST /api/access-control/roles
+func (api *AccessControlAPI) createRole(c *contextmodel.ReqContext) response.Response {
+	cmd := ac.CreateRoleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if cmd.Global && !c.SignedInUser.GetIsGrafanaAdmin() {
+		return response.Error(http.StatusForbidden, "Only server admins can create global roles", nil)
+	}
+	if !canDelegate(c, cmd.Permissions) {
+		return response.Error(http.StatusForbidden, "Cannot create a role with permissions you don't have", nil)
+	}
+
+	role, err := api.RoleService.CreateCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
+	if err != nil {
+		return roleErrorResponse(err, "Failed to create role")
+	}
+	return response.JSON(http.StatusCreated, role)
+}
+
+// PUT /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) updateRole(c *contextmodel.ReqContext) response.Response {
+	cmd := ac.UpdateRoleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	ctx, orgID, uid := c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":roleUID"]
+	existing, err := api.RoleService.GetCustomRole(ctx, orgID, uid)
+	if err != nil {
+		return roleErrorResponse(err, "Failed to update role")
+	}
+	if existing.Global() && !c.SignedInUser.GetIsGrafanaAdmin() {
+		return response.Error(http.StatusForbidden, "Only server admins can update global roles", nil)
+	}
+	if !canDelegate(c, cmd.Permissions) {
+		return response.Error(http.StatusForbidden, "Cannot update a role with permissions you don't have", nil)
+	}
+
+	role, err := api.RoleService.UpdateCustomRole(ctx, orgID, uid, cmd)
+	if err != nil {
+		return roleErrorResponse(err, "Failed to update role")
+	}
+	return response.JSON(http.StatusOK, role)
+}
+
+// DELETE /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) deleteRole(c *contextmodel.ReqContext) response.Response {
+	ctx, orgID, uid := c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":roleUID"]
+	existing, err := api.RoleService.GetCustomRole(ctx, orgID, uid)
+	if err != nil {
+		return roleErrorResponse(err, "Failed to delete role")
+	}
+	if existing.Global() && !c.SignedInUser.GetIsGrafanaAdmin() {
+		return response.Error(http.StatusForbidden, "Only server admins can delete global roles", nil)
+	}
+
+	if err := api.RoleService.DeleteCustomRole(ctx, orgID, uid); err != nil {
+		return roleErrorResponse(err, "Failed to delete role")
+	}
+	return response.Success("Role deleted")
+}
+
+// GET /api/access-control/users/:userId/roles
+func (api *AccessControlAPI) getUserRoles(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	roles, err := api.RoleService.GetUserRoles(c.Req.Context(), c.SignedInUser.GetOrgID(), userID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user roles", err)
+	}
+	return response.JSON(http.StatusOK, roles)
+}
+
+// POST /api/access-control/users/:userId/roles
+func (api *AccessControlAPI) addUserRole(c *contextmodel.ReqContext) response.Response {
+	cmd := addRoleAssignmentCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	if resp := api.checkAssignment(c, cmd.RoleUID); resp != nil {
+		return resp
+	}
+	if err := api.RoleService.AddUserRole(c.Req.Context(), c.SignedInUser.GetOrgID(), userID, cmd.RoleUID); err != nil {
+		return roleErrorResponse(err, "Failed to assign role")
+	}
+	return response.Success("Role assigned")
+}
+
+// DELETE /api/access-control/users/:userId/roles/:roleUID
+func (api *AccessControlAPI) removeUserRole(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "userId is invalid", err)
+	}
+
+	roleUID := web.Params(c.Req)[":roleUID"]
+	if resp := api.checkAssignment(c, roleUID); resp != nil {
+		return resp
+	}
+	if err := api.RoleService.RemoveUserRole(c.Req.Context(), c.SignedInUser.GetOrgID(), userID, roleUID); err != nil {
+		return roleErrorResponse(err, "Failed to unassign role")
+	}
+	return response.Success("Role unassigned")
+}
+
+// GET /api/access-control/teams/:teamId/roles
+func (api *AccessControlAPI) getTeamRoles(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	roles, err := api.RoleService.GetTeamRoles(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get team roles", err)
+	}
+	return response.JSON(http.StatusOK, roles)
+}
+
+// POST /api/access-control/teams/:teamId/roles
+func (api *AccessControlAPI) addTeamRole(c *contextmodel.ReqContext) response.Response {
+	cmd := addRoleAssignmentCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	if resp := api.checkAssignment(c, cmd.RoleUID); resp != nil {
+		return resp
+	}
+	if err := api.RoleService.AddTeamRole(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID, cmd.RoleUID); err != nil {
+		return roleErrorResponse(err, "Failed to assign role")
+	}
+	return response.Success("Role assigned")
+}
+
+// DELETE /api/access-control/teams/:teamId/roles/:roleUID
+func (api *AccessControlAPI) removeTeamRole(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	roleUID := web.Params(c.Req)[":roleUID"]
+	if resp := api.checkAssignment(c, roleUID); resp != nil {
+		return resp
+	}
+	if err := api.RoleService.RemoveTeamRole(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID, roleUID); err != nil {
+		return roleErrorResponse(err, "Failed to unassign role")
+	}
+	return response.Success("Role unassigned")
+}
+
+type addRoleAssignmentCommand struct {
+	RoleUID string `json:"roleUid"`
+}
+
+// checkAssignment prevents privilege escalation: the signed in user needs every
+// permission of the role to assign or unassign it.
+func (api *AccessControlAPI) checkAssignment(c *contextmodel.ReqContext, roleUID string) response.Response {
+	role, err := api.RoleService.GetCustomRole(c.Req.Context(), c.SignedInUser.GetOrgID(), roleUID)
+	if err != nil {
+		return roleErrorResponse(err, "Failed to get role")
+	}
+	if !canDelegate(c, role.Permissions) {
+		return response.Error(http.StatusForbidden, "Cannot manage assignments of a role with permissions you don't have", nil)
+	}
+	return nil
+}
+
+// canDelegate checks that the signed in user has all the given permissions
+func canDelegate(c *contextmodel.ReqContext, permissions []ac.Permission) bool {
+	if len(permissions) == 0 {
+		return true
+	}
+	evaluators := make([]ac.Evaluator, 0, len(permissions))
+	for _, p := range permissions {
+		if p.Scope == "" {
+			evaluators = append(evaluators, ac.EvalPermission(p.Action))
+			continue
+		}
+		evaluators = append(evaluators, ac.EvalPermission(p.Action, p.Scope))
+	}
+	return ac.EvalAll(evaluators...).Evaluate(c.SignedInUser.GetPermissions())
+}
+
+func roleErrorResponse(err error, message string) response.Response {
+	var invalidRole *ac.ErrorInvalidRole
+	switch {
+	case errors.Is(err, ac.ErrRoleNotFound):
+		return response.Error(http.StatusNotFound, "Role not found", err)
+	case errors.Is(err, ac.ErrRoleAlreadyExists), errors.Is(err, ac.ErrRoleAssignmentExists), errors.Is(err, ac.ErrRoleVersionTooLow):
+		return response.Error(http.StatusConflict, err.Error(), err)
+	case errors.Is(err, ac.ErrInvalidScope), errors.Is(err, ac.ErrCustomRolePrefix), errors.As(err, &invalidRole):
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
+	return response.Error(http.StatusInternalServerError, message, err)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_createRole(t *testing.T) {
+	type testCase struct {
+		desc         string
+		body         string
+		permissions  map[string][]string
+		isAdmin      bool
+		expectedErr  error
+		expectedCode int
+	}
+
+	tests := []testCase{
+		{
+			desc:         "Should create a role with permissions the user has",
+			body:         `{"name": "custom:reader", "permissions": [{"action": "dashboards:read", "scope": "dashboards:uid:1"}]}`,
+			permissions:  map[string][]string{"dashboards:read": {"dashboards:*"}},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			desc:         "Should not create a role with permissions the user doesn't have",
+			body:         `{"name": "custom:writer", "permissions": [{"action": "dashboards:write", "scope": "dashboards:*"}]}`,
+			permissions:  map[string][]string{"dashboards:read": {"dashboards:*"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "Should not create a global role without being server admin",
+			body:         `{"name": "custom:reader", "global": true}`,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "Should create a global role as server admin",
+			body:         `{"name": "custom:reader", "global": true}`,
+			isAdmin:      true,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			desc:         "Should return bad request on invalid role",
+			body:         `{"name": "reader"}`,
+			expectedErr:  ac.ErrCustomRolePrefix,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "Should return conflict when the role exists",
+			body:         `{"name": "custom:reader"}`,
+			expectedErr:  ac.ErrRoleAlreadyExists,
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			roleSvc := actest.FakeRoleService{ExpectedRole: &ac.RoleDTO{Name: "custom:reader"}, ExpectedErr: tt.expectedErr}
+			accessControl := actest.FakeAccessControl{ExpectedEvaluate: true} // Always allow access to the endpoint
+			api := NewAccessControlAPI(routing.NewRouteRegister(), accessControl, actest.FakeService{}, roleSvc, nil, featuremgmt.WithFeatures())
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewPostRequest("/api/access-control/roles", strings.NewReader(tt.body))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:          1,
+				IsGrafanaAdmin: tt.isAdmin,
+				Permissions:    map[int64]map[string][]string{1: tt.permissions},
+			})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestAPI_addUserRole(t *testing.T) {
+	type testCase struct {
+		desc         string
+		permissions  map[string][]string
+		expectedErr  error
+		expectedCode int
+	}
+
+	role := &ac.RoleDTO{
+		UID:         "custom_writer",
+		Name:        "custom:writer",
+		Permissions: []ac.Permission{{Action: "dashboards:write", Scope: "dashboards:*"}},
+	}
+
+	tests := []testCase{
+		{
+			desc:         "Should assign a role the user could have created",
+			permissions:  map[string][]string{"dashboards:write": {"dashboards:*"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "Should not assign a role with permissions the user doesn't have",
+			permissions:  map[string][]string{"dashboards:write": {"dashboards:uid:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "Should return not found when the role doesn't exist",
+			expectedErr:  ac.ErrRoleNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			roleSvc := actest.FakeRoleService{ExpectedRole: role, ExpectedErr: tt.expectedErr}
+			accessControl := actest.FakeAccessControl{ExpectedEvaluate: true} // Always allow access to the endpoint
+			api := NewAccessControlAPI(routing.NewRouteRegister(), accessControl, actest.FakeService{}, roleSvc, nil, featuremgmt.WithFeatures())
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewPostRequest("/api/access-control/users/2/roles", strings.NewReader(`{"roleUid": "custom_writer"}`))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:       1,
+				Permissions: map[int64]map[string][]string{1: tt.permissions},
+			})
+			res, err := server.SendJSON(req)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestAPI_removeRoleAssignment(t *testing.T) {
+	type testCase struct {
+		desc         string
+		permissions  map[string][]string
+		expectedErr  error
+		expectedCode int
+	}
+
+	role := &ac.RoleDTO{
+		UID:         "custom_writer",
+		Name:        "custom:writer",
+		Permissions: []ac.Permission{{Action: "dashboards:write", Scope: "dashboards:*"}},
+	}
+
+	tests := []testCase{
+		{
+			desc:         "Should unassign a role the user could have created",
+			permissions:  map[string][]string{"dashboards:write": {"dashboards:*"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "Should not unassign a role with permissions the user doesn't have",
+			permissions:  map[string][]string{"dashboards:write": {"dashboards:uid:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "Should return not found when the role doesn't exist",
+			expectedErr:  ac.ErrRoleNotFound,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, target := range []string{"/api/access-control/users/2/roles/custom_writer", "/api/access-control/teams/2/roles/custom_writer"} {
+		for _, tt := range tests {
+			t.Run(target+" "+tt.desc, func(t *testing.T) {
+				roleSvc := actest.FakeRoleService{ExpectedRole: role, ExpectedErr: tt.expectedErr}
+				accessControl := actest.FakeAccessControl{ExpectedEvaluate: true} // Always allow access to the endpoint
+				api := NewAccessControlAPI(routing.NewRouteRegister(), accessControl, actest.FakeService{}, roleSvc, nil, featuremgmt.WithFeatures())
+				api.RegisterAPIEndpoints()
+
+				server := webtest.NewServer(t, api.RouteRegister)
+				req := server.NewRequest(http.MethodDelete, target, nil)
+				webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+					OrgID:       1,
+					Permissions: map[int64]map[string][]string{1: tt.permissions},
+				})
+				res, err := server.Send(req)
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedCode, res.StatusCode)
+				require.NoError(t, res.Body.Close())
+			})
+		}
+	}
+}
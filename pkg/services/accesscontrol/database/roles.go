@@ -0,0 +1,298 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+var _ accesscontrol.RoleStore = &AccessControlStore{}
+
+func (s *AccessControlStore) GetRoles(ctx context.Context, orgID int64, prefix string) ([]*accesscontrol.RoleDTO, error) {
+	var result []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var roles []accesscontrol.Role
+		if err := sess.Where("(org_id = ? OR org_id = ?) AND name LIKE ?", orgID, accesscontrol.GlobalOrgID, prefix+"%").
+			OrderBy("name").
+			Find(&roles); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = toRoleDTOs(ctx, sess, roles)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) GetRoleByUID(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	var role *accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		role, err = getRoleDTOByUID(ctx, sess, orgID, uid)
+		return err
+	})
+	return role, err
+}
+
+func (s *AccessControlStore) SaveRole(ctx context.Context, role *accesscontrol.RoleDTO) (*accesscontrol.RoleDTO, error) {
+	var saved *accesscontrol.RoleDTO
+	err := s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing, err := getRoleByUID(ctx, sess, role.UID)
+		if err != nil && !errors.Is(err, accesscontrol.ErrRoleNotFound) {
+			return err
+		}
+
+		now := time.Now()
+		stored := &accesscontrol.Role{
+			OrgID:       role.OrgID,
+			Version:     role.Version,
+			UID:         role.UID,
+			Name:        role.Name,
+			DisplayName: role.DisplayName,
+			Group:       role.Group,
+			Description: role.Description,
+			Hidden:      role.Hidden,
+			Created:     now,
+			Updated:     now,
+		}
+
+		if existing == nil {
+			if _, err := sess.Insert(stored); err != nil {
+				return err
+			}
+		} else {
+			// Role uids are unique across organizations
+			if existing.OrgID != role.OrgID {
+				return accesscontrol.ErrRoleAlreadyExists
+			}
+			stored.ID = existing.ID
+			stored.Created = existing.Created
+			if _, err := sess.ID(existing.ID).AllCols().Update(stored); err != nil {
+				return err
+			}
+		}
+
+		permissions := make([]accesscontrol.Permission, 0, len(role.Permissions))
+		for _, p := range role.Permissions {
+			p.Kind, p.Attribute, p.Identifier = p.SplitScope()
+			permissions = append(permissions, p)
+		}
+		if err := s.savePermissions(ctx, sess, stored.ID, permissions); err != nil {
+			return err
+		}
+
+		saved, err = getRoleDTOByUID(ctx, sess, stored.OrgID, stored.UID)
+		return err
+	})
+	return saved, err
+}
+
+func (s *AccessControlStore) DeleteRole(ctx context.Context, roleID int64) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		// Delete the assignments and permissions before the role
+		for _, q := range []string{
+			"DELETE FROM user_role WHERE role_id = ?",
+			"DELETE FROM team_role WHERE role_id = ?",
+			"DELETE FROM builtin_role WHERE role_id = ?",
+			"DELETE FROM permission WHERE role_id = ?",
+			"DELETE FROM role WHERE id = ?",
+		} {
+			if _, err := sess.Exec(q, roleID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *AccessControlStore) GetUserRoles(ctx context.Context, orgID, userID int64, prefix string) ([]*accesscontrol.RoleDTO, error) {
+	var result []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var roles []accesscontrol.Role
+		q := `SELECT role.* FROM role
+			INNER JOIN user_role ON user_role.role_id = role.id
+			WHERE user_role.user_id = ? AND (user_role.org_id = ? OR user_role.org_id = ?) AND role.name LIKE ?
+			ORDER BY role.name`
+		if err := sess.SQL(q, userID, orgID, accesscontrol.GlobalOrgID, prefix+"%").Find(&roles); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = toRoleDTOs(ctx, sess, roles)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) AddUserRole(ctx context.Context, orgID, userID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND user_id = ? AND role_id = ?", orgID, userID, roleID).Exist(&accesscontrol.UserRole{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return accesscontrol.ErrRoleAssignmentExists
+		}
+		_, err = sess.Insert(&accesscontrol.UserRole{OrgID: orgID, UserID: userID, RoleID: roleID, Created: time.Now()})
+		return err
+	})
+}
+
+func (s *AccessControlStore) RemoveUserRole(ctx context.Context, orgID, userID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM user_role WHERE org_id = ? AND user_id = ? AND role_id = ?", orgID, userID, roleID)
+		return err
+	})
+}
+
+func (s *AccessControlStore) GetTeamRoles(ctx context.Context, orgID, teamID int64, prefix string) ([]*accesscontrol.RoleDTO, error) {
+	var result []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		var roles []accesscontrol.Role
+		q := `SELECT role.* FROM role
+			INNER JOIN team_role ON team_role.role_id = role.id
+			WHERE team_role.team_id = ? AND team_role.org_id = ? AND role.name LIKE ?
+			ORDER BY role.name`
+		if err := sess.SQL(q, teamID, orgID, prefix+"%").Find(&roles); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = toRoleDTOs(ctx, sess, roles)
+		return err
+	})
+	return result, err
+}
+
+func (s *AccessControlStore) AddTeamRole(ctx context.Context, orgID, teamID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND team_id = ? AND role_id = ?", orgID, teamID, roleID).Exist(&accesscontrol.TeamRole{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return accesscontrol.ErrRoleAssignmentExists
+		}
+		_, err = sess.Insert(&accesscontrol.TeamRole{OrgID: orgID, TeamID: teamID, RoleID: roleID, Created: time.Now()})
+		return err
+	})
+}
+
+func (s *AccessControlStore) RemoveTeamRole(ctx context.Context, orgID, teamID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM team_role WHERE org_id = ? AND team_id = ? AND role_id = ?", orgID, teamID, roleID)
+		return err
+	})
+}
+
+func (s *AccessControlStore) GetPermissionGrants(ctx context.Context, orgID, userID int64, rolePrefixes []string) ([]accesscontrol.PermissionGrant, error) {
+	grants := make([]accesscontrol.PermissionGrant, 0)
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		// Roles are assigned to users directly, through their teams or through their basic role.
+		// Only members of the org are granted permissions in it.
+		q := `
+		SELECT
+			up.user_id,
+			p.action,
+			p.scope,
+			r.name AS role_name,
+			up.assignment,
+			up.team_id,
+			up.basic_role
+		FROM (
+			SELECT ur.user_id, ur.role_id, '` + accesscontrol.AssignmentUser + `' AS assignment, 0 AS team_id, '' AS basic_role
+				FROM user_role AS ur
+				INNER JOIN org_user AS ou ON ou.user_id = ur.user_id AND ou.org_id = ?
+				WHERE (ur.org_id = ? OR ur.org_id = ?)
+			UNION ALL
+				SELECT tm.user_id, tr.role_id, '` + accesscontrol.AssignmentTeam + `', tr.team_id, ''
+					FROM team_role AS tr
+					INNER JOIN team_member AS tm ON tm.team_id = tr.team_id
+					INNER JOIN org_user AS ou ON ou.user_id = tm.user_id AND ou.org_id = ?
+					WHERE tr.org_id = ?
+			UNION ALL
+				SELECT ou.user_id, br.role_id, '` + accesscontrol.AssignmentBasicRole + `', 0, br.role
+					FROM builtin_role AS br
+					INNER JOIN org_user AS ou ON ou.role = br.role AND ou.org_id = ?
+					WHERE (br.org_id = ? OR br.org_id = ?)
+			UNION ALL
+				SELECT u.id, br.role_id, '` + accesscontrol.AssignmentBasicRole + `', 0, br.role
+					FROM builtin_role AS br
+					INNER JOIN ` + s.sql.GetDialect().Quote("user") + ` AS u ON u.is_admin
+					INNER JOIN org_user AS ou ON ou.user_id = u.id AND ou.org_id = ?
+					WHERE br.role = ? AND (br.org_id = ? OR br.org_id = ?)
+		) AS up
+		INNER JOIN role AS r ON r.id = up.role_id
+		INNER JOIN permission AS p ON p.role_id = up.role_id
+		WHERE 1 = 1`
+
+		params := []any{
+			orgID, orgID, accesscontrol.GlobalOrgID,
+			orgID, orgID,
+			orgID, orgID, accesscontrol.GlobalOrgID,
+			orgID, accesscontrol.RoleGrafanaAdmin, orgID, accesscontrol.GlobalOrgID,
+		}
+
+		if userID != 0 {
+			q += " AND up.user_id = ?"
+			params = append(params, userID)
+		}
+		if len(rolePrefixes) > 0 {
+			q += " AND ( " + strings.Repeat("r.name LIKE ? OR ", len(rolePrefixes)-1) + "r.name LIKE ? )"
+			for _, prefix := range rolePrefixes {
+				params = append(params, prefix+"%")
+			}
+		}
+		q += " ORDER BY up.user_id, p.action, p.scope"
+
+		return sess.SQL(q, params...).Find(&grants)
+	})
+	return grants, err
+}
+
+func getRoleDTOByUID(ctx context.Context, sess *db.Session, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	var role accesscontrol.Role
+	has, err := sess.Where("uid = ? AND (org_id = ? OR org_id = ?)", uid, orgID, accesscontrol.GlobalOrgID).Get(&role)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, accesscontrol.ErrRoleNotFound
+	}
+
+	roles, err := toRoleDTOs(ctx, sess, []accesscontrol.Role{role})
+	if err != nil {
+		return nil, err
+	}
+	return roles[0], nil
+}
+
+func toRoleDTOs(ctx context.Context, sess *db.Session, roles []accesscontrol.Role) ([]*accesscontrol.RoleDTO, error) {
+	result := make([]*accesscontrol.RoleDTO, 0, len(roles))
+	for _, role := range roles {
+		permissions, err := getRolePermissions(ctx, sess, role.ID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &accesscontrol.RoleDTO{
+			ID:          role.ID,
+			OrgID:       role.OrgID,
+			Version:     role.Version,
+			UID:         role.UID,
+			Name:        role.Name,
+			DisplayName: role.DisplayName,
+			Description: role.Description,
+			Group:       role.Group,
+			Hidden:      role.Hidden,
+			Permissions: permissions,
+			Updated:     role.Updated,
+			Created:     role.Created,
+		})
+	}
+	return result, nil
+}
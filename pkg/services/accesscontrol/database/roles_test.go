@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	rs "github.com/grafana/grafana/pkg/services/accesscontrol/resourcepermissions"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestAccessControlStore_SaveRole(t *testing.T) {
+	ctx := context.Background()
+	s := &AccessControlStore{sql: db.InitTestDB(t)}
+
+	role, err := s.SaveRole(ctx, &accesscontrol.RoleDTO{
+		OrgID:   1,
+		Version: 1,
+		UID:     "custom_reader",
+		Name:    "custom:reader",
+		Permissions: []accesscontrol.Permission{
+			{Action: "dashboards:read", Scope: "dashboards:uid:1"},
+			{Action: "users:read"},
+		},
+	})
+	require.NoError(t, err)
+	require.NotZero(t, role.ID)
+	require.Len(t, role.Permissions, 2)
+
+	updated, err := s.SaveRole(ctx, &accesscontrol.RoleDTO{
+		OrgID:       1,
+		Version:     2,
+		UID:         "custom_reader",
+		Name:        "custom:reader",
+		Description: "Read dashboards",
+		Permissions: []accesscontrol.Permission{
+			{Action: "dashboards:read", Scope: "dashboards:*"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, role.ID, updated.ID)
+	assert.Equal(t, int64(2), updated.Version)
+	assert.Equal(t, "Read dashboards", updated.Description)
+	require.Len(t, updated.Permissions, 1)
+	assert.Equal(t, "dashboards:*", updated.Permissions[0].Scope)
+	assert.Equal(t, "dashboards", updated.Permissions[0].Kind)
+
+	// uids are unique across organizations
+	_, err = s.SaveRole(ctx, &accesscontrol.RoleDTO{OrgID: 2, Version: 1, UID: "custom_reader", Name: "custom:reader"})
+	require.ErrorIs(t, err, accesscontrol.ErrRoleAlreadyExists)
+}
+
+func TestAccessControlStore_GetRoles(t *testing.T) {
+	ctx := context.Background()
+	s := &AccessControlStore{sql: db.InitTestDB(t)}
+
+	for _, r := range []accesscontrol.RoleDTO{
+		{OrgID: 1, UID: "a", Name: "custom:a"},
+		{OrgID: accesscontrol.GlobalOrgID, UID: "b", Name: "custom:b"},
+		{OrgID: 2, UID: "c", Name: "custom:c"},
+		{OrgID: 1, UID: "d", Name: "managed:users:1:permissions"},
+	} {
+		r := r
+		_, err := s.SaveRole(ctx, &r)
+		require.NoError(t, err)
+	}
+
+	roles, err := s.GetRoles(ctx, 1, accesscontrol.CustomRolePrefix)
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+	assert.Equal(t, "custom:a", roles[0].Name)
+	assert.Equal(t, "custom:b", roles[1].Name)
+
+	_, err = s.GetRoleByUID(ctx, 1, "c")
+	require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+
+	global, err := s.GetRoleByUID(ctx, 2, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "custom:b", global.Name)
+}
+
+func TestAccessControlStore_RoleAssignments(t *testing.T) {
+	ctx := context.Background()
+	s := &AccessControlStore{sql: db.InitTestDB(t)}
+
+	role, err := s.SaveRole(ctx, &accesscontrol.RoleDTO{
+		OrgID:       1,
+		UID:         "custom_writer",
+		Name:        "custom:writer",
+		Permissions: []accesscontrol.Permission{{Action: "dashboards:write", Scope: "dashboards:*"}},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.AddUserRole(ctx, 1, 10, role.ID))
+	require.ErrorIs(t, s.AddUserRole(ctx, 1, 10, role.ID), accesscontrol.ErrRoleAssignmentExists)
+	require.NoError(t, s.AddTeamRole(ctx, 1, 20, role.ID))
+	require.ErrorIs(t, s.AddTeamRole(ctx, 1, 20, role.ID), accesscontrol.ErrRoleAssignmentExists)
+
+	userRoles, err := s.GetUserRoles(ctx, 1, 10, accesscontrol.CustomRolePrefix)
+	require.NoError(t, err)
+	require.Len(t, userRoles, 1)
+	assert.Equal(t, role.UID, userRoles[0].UID)
+	require.Len(t, userRoles[0].Permissions, 1)
+
+	teamRoles, err := s.GetTeamRoles(ctx, 1, 20, accesscontrol.CustomRolePrefix)
+	require.NoError(t, err)
+	require.Len(t, teamRoles, 1)
+
+	permissions, err := s.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{
+		OrgID:        1,
+		UserID:       10,
+		RolePrefixes: []string{accesscontrol.CustomRolePrefix},
+	})
+	require.NoError(t, err)
+	require.Len(t, permissions, 1)
+
+	require.NoError(t, s.RemoveUserRole(ctx, 1, 10, role.ID))
+	userRoles, err = s.GetUserRoles(ctx, 1, 10, accesscontrol.CustomRolePrefix)
+	require.NoError(t, err)
+	require.Empty(t, userRoles)
+
+	require.NoError(t, s.DeleteRole(ctx, role.ID))
+	teamRoles, err = s.GetTeamRoles(ctx, 1, 20, accesscontrol.CustomRolePrefix)
+	require.NoError(t, err)
+	require.Empty(t, teamRoles)
+	_, err = s.GetRoleByUID(ctx, 1, role.UID)
+	require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+}
+
+func TestIntegrationAccessControlStore_GetPermissionGrants(t *testing.T) {
+	ctx := context.Background()
+	store, permissionStore, userSvc, teamSvc, orgSvc := setupTestEnv(t)
+	member, team := createUserAndTeam(t, userSvc, teamSvc, 1)
+
+	dashboardPermission := func(action, uid string) rs.SetResourcePermissionCommand {
+		return rs.SetResourcePermissionCommand{Actions: []string{action}, Resource: "dashboards", ResourceAttribute: "uid", ResourceID: uid}
+	}
+	_, err := permissionStore.SetUserResourcePermission(ctx, 1, accesscontrol.User{ID: member.ID}, dashboardPermission("dashboards:write", "1"), nil)
+	require.NoError(t, err)
+	_, err = permissionStore.SetTeamResourcePermission(ctx, 1, team.ID, dashboardPermission("dashboards:read", "2"), nil)
+	require.NoError(t, err)
+	_, err = permissionStore.SetBuiltInResourcePermission(ctx, 1, "Viewer", dashboardPermission("dashboards:read", "3"), nil)
+	require.NoError(t, err)
+	// Not granted to the user's basic role
+	_, err = permissionStore.SetBuiltInResourcePermission(ctx, 1, "Admin", dashboardPermission("dashboards:read", "4"), nil)
+	require.NoError(t, err)
+
+	role, err := store.SaveRole(ctx, &accesscontrol.RoleDTO{
+		OrgID:       1,
+		UID:         "custom_reader",
+		Name:        "custom:reader",
+		Permissions: []accesscontrol.Permission{{Action: "folders:read", Scope: "folders:*"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.AddUserRole(ctx, 1, member.ID, role.ID))
+
+	grants, err := store.GetPermissionGrants(ctx, 1, member.ID, []string{accesscontrol.ManagedRolePrefix, accesscontrol.CustomRolePrefix})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []accesscontrol.PermissionGrant{
+		{UserID: member.ID, Action: "dashboards:write", Scope: "dashboards:uid:1", RoleName: accesscontrol.ManagedUserRoleName(member.ID), Assignment: accesscontrol.AssignmentUser},
+		{UserID: member.ID, Action: "dashboards:read", Scope: "dashboards:uid:2", RoleName: accesscontrol.ManagedTeamRoleName(team.ID), Assignment: accesscontrol.AssignmentTeam, TeamID: team.ID},
+		{UserID: member.ID, Action: "dashboards:read", Scope: "dashboards:uid:3", RoleName: accesscontrol.ManagedBuiltInRoleName("Viewer"), Assignment: accesscontrol.AssignmentBasicRole, BasicRole: "Viewer"},
+		{UserID: member.ID, Action: "folders:read", Scope: "folders:*", RoleName: "custom:reader", Assignment: accesscontrol.AssignmentUser},
+	}, grants)
+
+	grants, err = store.GetPermissionGrants(ctx, 1, 0, []string{accesscontrol.CustomRolePrefix})
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+
+	t.Run("should not return grants of users outside of the org", func(t *testing.T) {
+		otherOrgID, err := orgSvc.GetOrCreate(ctx, "other")
+		require.NoError(t, err)
+		admin, err := userSvc.Create(ctx, &user.CreateUserCommand{Login: "admin", OrgID: otherOrgID, IsAdmin: true})
+		require.NoError(t, err)
+
+		globalRole, err := store.SaveRole(ctx, &accesscontrol.RoleDTO{
+			OrgID:       accesscontrol.GlobalOrgID,
+			UID:         "custom_global_reader",
+			Name:        "custom:global:reader",
+			Permissions: []accesscontrol.Permission{{Action: "folders:read", Scope: "folders:*"}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.AddUserRole(ctx, accesscontrol.GlobalOrgID, admin.ID, globalRole.ID))
+		_, err = permissionStore.SetBuiltInResourcePermission(ctx, 1, accesscontrol.RoleGrafanaAdmin, dashboardPermission("dashboards:read", "5"), nil)
+		require.NoError(t, err)
+
+		grants, err := store.GetPermissionGrants(ctx, 1, admin.ID, []string{accesscontrol.ManagedRolePrefix, accesscontrol.CustomRolePrefix})
+		require.NoError(t, err)
+		require.Empty(t, grants)
+	})
+}
@@ -13,6 +13,10 @@ var (
 	ErrResolverNotFound       = errors.New("no resolver found")
 	ErrPluginIDRequired       = errors.New("plugin ID is required")
 	ErrRoleNotFound           = errors.New("role not found")
+	ErrRoleAlreadyExists      = errors.New("role already exists")
+	ErrCustomRolePrefix       = errors.New("custom role should be prefixed with '" + CustomRolePrefix + "'")
+	ErrRoleVersionTooLow      = errors.New("role version should be greater than the current version")
+	ErrRoleAssignmentExists   = errors.New("role is already assigned")
 )
 
 type ErrorInvalidRole struct{}
@@ -328,6 +328,107 @@ func (cmd *SaveExternalServiceRoleCommand) Validate() error {
 	return nil
 }
 
+// CreateRoleCommand creates a custom role composed of actions and scopes.
+type CreateRoleCommand struct {
+	UID         string       `json:"uid"`
+	Name        string       `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Description string       `json:"description"`
+	Group       string       `json:"group"`
+	Hidden      bool         `json:"hidden"`
+	Global      bool         `json:"global"`
+	Version     int64        `json:"version"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// UpdateRoleCommand replaces the definition of a custom role. When set, the
+// version must be greater than the current version of the role.
+type UpdateRoleCommand struct {
+	Name        string       `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Description string       `json:"description"`
+	Group       string       `json:"group"`
+	Hidden      bool         `json:"hidden"`
+	Version     int64        `json:"version"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// ValidateCustomRole checks the name and permissions of a custom role and
+// deduplicates its permissions.
+func ValidateCustomRole(name string, permissions []Permission) ([]Permission, error) {
+	if name == "" {
+		return nil, &ErrorRoleNameMissing{}
+	}
+	if !strings.HasPrefix(name, CustomRolePrefix) {
+		return nil, ErrCustomRolePrefix
+	}
+
+	dedupMap := map[Permission]bool{}
+	dedup := make([]Permission, 0, len(permissions))
+	for i := range permissions {
+		p := Permission{Action: permissions[i].Action, Scope: permissions[i].Scope}
+		if p.Action == "" {
+			return nil, fmt.Errorf("%w: role %v has a permission with no action", &ErrorInvalidRole{}, name)
+		}
+		if p.Scope != "" && !ValidateScope(p.Scope) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidScope, p.Scope)
+		}
+		if dedupMap[p] {
+			continue
+		}
+		dedupMap[p] = true
+		dedup = append(dedup, p)
+	}
+	return dedup, nil
+}
+
+// Sources of a permission grant, derived from the name of the granting role
+const (
+	GrantSourceBasicRole           = "basic_role"
+	GrantSourceFixedRole           = "fixed_role"
+	GrantSourcePluginRole          = "plugin_role"
+	GrantSourceManagedPermission   = "managed_permission"
+	GrantSourceCustomRole          = "custom_role"
+	GrantSourceExternalServiceRole = "external_service_role"
+)
+
+// Ways a role granting a permission is assigned to a user
+const (
+	AssignmentUser      = "user"
+	AssignmentTeam      = "team"
+	AssignmentBasicRole = "basic_role"
+)
+
+// PermissionGrant is a permission of a user along with the role granting it
+// and how that role is assigned to the user.
+type PermissionGrant struct {
+	UserID     int64  `json:"-" xorm:"user_id"`
+	Action     string `json:"action" xorm:"action"`
+	Scope      string `json:"scope" xorm:"scope"`
+	Source     string `json:"source" xorm:"-"`
+	RoleName   string `json:"role" xorm:"role_name"`
+	Assignment string `json:"assignment" xorm:"assignment"`
+	TeamID     int64  `json:"teamId,omitempty" xorm:"team_id"`
+	BasicRole  string `json:"basicRole,omitempty" xorm:"basic_role"`
+}
+
+// GrantSource returns the source of the permissions granted by the role.
+func GrantSource(roleName string) string {
+	switch {
+	case strings.HasPrefix(roleName, FixedRolePrefix):
+		return GrantSourceFixedRole
+	case strings.HasPrefix(roleName, PluginRolePrefix):
+		return GrantSourcePluginRole
+	case strings.HasPrefix(roleName, ManagedRolePrefix):
+		return GrantSourceManagedPermission
+	case strings.HasPrefix(roleName, CustomRolePrefix):
+		return GrantSourceCustomRole
+	case strings.HasPrefix(roleName, ExternalServiceRolePrefix):
+		return GrantSourceExternalServiceRole
+	}
+	return GrantSourceBasicRole
+}
+
 const (
 	GlobalOrgID      = 0
 	NoOrgID          = int64(-1)
@@ -419,6 +520,22 @@ const (
 	// Team related scopes
 	ScopeTeamsAll = "teams:*"
 
+	// Custom role related actions
+	ActionRolesRead   = "roles:read"
+	ActionRolesWrite  = "roles:write"
+	ActionRolesDelete = "roles:delete"
+
+	// Role assignment related actions
+	ActionUsersRolesRead   = "users.roles:read"
+	ActionUsersRolesAdd    = "users.roles:add"
+	ActionUsersRolesRemove = "users.roles:remove"
+	ActionTeamsRolesRead   = "teams.roles:read"
+	ActionTeamsRolesAdd    = "teams.roles:add"
+	ActionTeamsRolesRemove = "teams.roles:remove"
+
+	// Custom role related scopes
+	ScopeRolesAll = "roles:*"
+
 	// Annotations related actions
 	ActionAnnotationsCreate = "annotations:create"
 	ActionAnnotationsDelete = "annotations:delete"
@@ -485,6 +602,9 @@ var (
 	// Team scope
 	ScopeTeamsID = Scope("teams", "id", Parameter(":teamId"))
 
+	// Custom role scope
+	ScopeRolesUID = Scope("roles", "uid", Parameter(":roleUID"))
+
 	ScopeSettingsOAuth = func(provider string) string {
 		return Scope("settings", "auth."+provider, "*")
 	}
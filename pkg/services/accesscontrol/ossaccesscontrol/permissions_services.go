@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/licensing"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/retriever"
 	"github.com/grafana/grafana/pkg/services/team"
@@ -26,6 +27,13 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+// provisionedResourceValidator prevents changes through the api to the permissions of provisioned resources.
+func provisionedResourceValidator(provenanceService provenance.Service, kind provenance.Kind) resourcepermissions.ResourceValidator {
+	return func(ctx context.Context, orgID int64, resourceID string) error {
+		return provenance.EnsureNotProvisioned(ctx, provenanceService, orgID, kind, resourceID)
+	}
+}
+
 type TeamPermissionsService struct {
 	*resourcepermissions.Service
 }
@@ -47,7 +55,7 @@ var (
 func ProvideTeamPermissions(
 	cfg *setting.Cfg, features featuremgmt.FeatureToggles, router routing.RouteRegister, sql db.DB,
 	ac accesscontrol.AccessControl, license licensing.Licensing, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, provenanceService provenance.Service,
 ) (*TeamPermissionsService, error) {
 	options := resourcepermissions.Options{
 		Resource:          "teams",
@@ -69,6 +77,7 @@ func ProvideTeamPermissions(
 
 			return nil
 		},
+		APIValidator: provisionedResourceValidator(provenanceService, provenance.KindTeam),
 		Assignments: resourcepermissions.Assignments{
 			Users:        true,
 			Teams:        false,
@@ -142,7 +151,7 @@ func getDashboardAdminActions(features featuremgmt.FeatureToggles) []string {
 func ProvideDashboardPermissions(
 	cfg *setting.Cfg, features featuremgmt.FeatureToggles, router routing.RouteRegister, sql db.DB, ac accesscontrol.AccessControl,
 	license licensing.Licensing, dashboardStore dashboards.Store, folderService folder.Service, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, provenanceService provenance.Service,
 ) (*DashboardPermissionsService, error) {
 	getDashboard := func(ctx context.Context, orgID int64, resourceID string) (*dashboards.Dashboard, error) {
 		query := &dashboards.GetDashboardQuery{UID: resourceID, OrgID: orgID}
@@ -168,6 +177,7 @@ func ProvideDashboardPermissions(
 
 			return nil
 		},
+		APIValidator: provisionedResourceValidator(provenanceService, provenance.KindDashboardPermissions),
 		InheritedScopesSolver: func(ctx context.Context, orgID int64, resourceID string) ([]string, error) {
 			dashboard, err := getDashboard(ctx, orgID, resourceID)
 			if err != nil {
@@ -235,7 +245,7 @@ var FolderAdminActions = append(FolderEditActions, []string{dashboards.ActionFol
 func ProvideFolderPermissions(
 	cfg *setting.Cfg, features featuremgmt.FeatureToggles, router routing.RouteRegister, sql db.DB, accesscontrol accesscontrol.AccessControl,
 	license licensing.Licensing, dashboardStore dashboards.Store, folderService folder.Service, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, provenanceService provenance.Service,
 ) (*FolderPermissionsService, error) {
 	options := resourcepermissions.Options{
 		Resource:          "folders",
@@ -253,6 +263,7 @@ func ProvideFolderPermissions(
 
 			return nil
 		},
+		APIValidator: provisionedResourceValidator(provenanceService, provenance.KindFolderPermissions),
 		InheritedScopesSolver: func(ctx context.Context, orgID int64, resourceID string) ([]string, error) {
 			return dashboards.GetInheritedScopes(ctx, orgID, resourceID, folderService)
 		},
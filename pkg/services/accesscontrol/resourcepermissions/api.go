@@ -238,6 +238,10 @@ func (a *api) setUserPermission(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if err := a.validateAPIChange(c, resourceID); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "failed to set user permission", err)
+	}
+
 	_, err = a.service.SetUserPermission(c.Req.Context(), c.SignedInUser.GetOrgID(), accesscontrol.User{ID: userID}, resourceID, cmd.Permission)
 	if err != nil {
 		return response.ErrOrFallback(http.StatusBadRequest, "failed to set user permission", err)
@@ -291,6 +295,10 @@ func (a *api) setTeamPermission(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if err := a.validateAPIChange(c, resourceID); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "failed to set team permission", err)
+	}
+
 	_, err = a.service.SetTeamPermission(c.Req.Context(), c.SignedInUser.GetOrgID(), teamID, resourceID, cmd.Permission)
 	if err != nil {
 		return response.ErrOrFallback(http.StatusBadRequest, "failed to set team permission", err)
@@ -341,6 +349,10 @@ func (a *api) setBuiltinRolePermission(c *contextmodel.ReqContext) response.Resp
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if err := a.validateAPIChange(c, resourceID); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "failed to set role permission", err)
+	}
+
 	_, err := a.service.SetBuiltInRolePermission(c.Req.Context(), c.SignedInUser.GetOrgID(), builtInRole, resourceID, cmd.Permission)
 	if err != nil {
 		return response.ErrOrFallback(http.StatusBadRequest, "failed to set role permission", err)
@@ -386,6 +398,10 @@ func (a *api) setPermissions(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if err := a.validateAPIChange(c, resourceID); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "failed to set permission", err)
+	}
+
 	_, err := a.service.SetPermissions(c.Req.Context(), c.SignedInUser.GetOrgID(), resourceID, cmd.Permissions...)
 	if err != nil {
 		return response.ErrOrFallback(http.StatusBadRequest, "failed to set permission", err)
@@ -394,6 +410,14 @@ func (a *api) setPermissions(c *contextmodel.ReqContext) response.Response {
 	return response.Success("Permissions updated")
 }
 
+// validateAPIChange calls the api validator of the resource, if any, before its permissions are changed.
+func (a *api) validateAPIChange(c *contextmodel.ReqContext, resourceID string) error {
+	if a.service.options.APIValidator == nil {
+		return nil
+	}
+	return a.service.options.APIValidator(c.Req.Context(), c.SignedInUser.GetOrgID(), resourceID)
+}
+
 func permissionSetResponse(cmd setPermissionCommand) response.Response {
 	message := "Permission updated"
 	if cmd.Permission == "" {
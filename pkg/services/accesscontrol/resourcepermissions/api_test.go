@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/team/teamimpl"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/userimpl"
+	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -428,6 +429,37 @@ func TestApi_setUserPermission(t *testing.T) {
 	}
 }
 
+func TestApi_APIValidator(t *testing.T) {
+	options := testOptions
+	options.APIValidator = func(ctx context.Context, orgID int64, resourceID string) error {
+		if resourceID == "2" {
+			return errutil.BadRequest("test.provisioned").Errorf("resource %s is provisioned", resourceID)
+		}
+		return nil
+	}
+	service, _, _ := setupTestEnvironment(t, options)
+	server := setupTestServer(t, &user.SignedInUser{
+		OrgID: 1,
+		Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction([]accesscontrol.Permission{
+			{Action: "dashboards.permissions:read", Scope: "dashboards:*"},
+			{Action: "dashboards.permissions:write", Scope: "dashboards:*"},
+		})},
+	}, service)
+
+	t.Run("should set permissions when the validator succeeds", func(t *testing.T) {
+		recorder := setPermission(t, server, testOptions.Resource, "1", "View", "builtInRoles", "Viewer")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("should not set permissions when the validator fails", func(t *testing.T) {
+		recorder := setPermission(t, server, testOptions.Resource, "2", "View", "builtInRoles", "Viewer")
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		permissions, _ := getPermission(t, server, testOptions.Resource, "2")
+		assert.Empty(t, permissions)
+	})
+}
+
 func setupTestServer(t *testing.T, user *user.SignedInUser, service *Service) *web.Mux {
 	server := web.New()
 	server.UseMiddleware(web.Renderer("views", "[[", "]]"))
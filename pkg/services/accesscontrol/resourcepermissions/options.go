@@ -21,6 +21,9 @@ type Options struct {
 	// ResourceValidator is a validator function that will be called before each assignment.
 	// If set to nil the validator will be skipped
 	ResourceValidator ResourceValidator
+	// APIValidator is a validator function that will be called before permissions are set through the api,
+	// e.g. to prevent changes to the permissions of provisioned resources. If set to nil the validator will be skipped
+	APIValidator ResourceValidator
 	// Assignments decides what we can assign permissions to (users/teams/builtInRoles)
 	Assignments Assignments
 	// PermissionsToAction is a map of friendly named permissions and what access control actions they should generate.
@@ -24,6 +24,8 @@ const (
 
 	ManagedRolePrefix = "managed:"
 
+	CustomRolePrefix = "custom:"
+
 	PluginRolePrefix = "plugins:"
 
 	BasicRoleNoneUID  = "basic_none"
@@ -264,6 +266,60 @@ var (
 		},
 	}
 
+	rolesReaderRole = RoleDTO{
+		Name:        "fixed:roles:reader",
+		DisplayName: "Role reader",
+		Description: "Read custom roles and the custom roles assigned to users, service accounts and teams.",
+		Group:       "Access control",
+		Permissions: []Permission{
+			{
+				Action: ActionRolesRead,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionUsersRolesRead,
+				Scope:  ScopeUsersAll,
+			},
+			{
+				Action: ActionTeamsRolesRead,
+				Scope:  ScopeTeamsAll,
+			},
+		},
+	}
+
+	rolesWriterRole = RoleDTO{
+		Name:        "fixed:roles:writer",
+		DisplayName: "Role writer",
+		Description: "Create, update and delete custom roles and assign them to users, service accounts and teams.",
+		Group:       "Access control",
+		Permissions: ConcatPermissions(rolesReaderRole.Permissions, []Permission{
+			{
+				Action: ActionRolesWrite,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionRolesDelete,
+				Scope:  ScopeRolesAll,
+			},
+			{
+				Action: ActionUsersRolesAdd,
+				Scope:  ScopeUsersAll,
+			},
+			{
+				Action: ActionUsersRolesRemove,
+				Scope:  ScopeUsersAll,
+			},
+			{
+				Action: ActionTeamsRolesAdd,
+				Scope:  ScopeTeamsAll,
+			},
+			{
+				Action: ActionTeamsRolesRemove,
+				Scope:  ScopeTeamsAll,
+			},
+		}),
+	}
+
 	generalAuthConfigWriterRole = RoleDTO{
 		Name:        "fixed:general.auth.config:writer",
 		DisplayName: "General authentication config writer",
@@ -316,6 +372,14 @@ func DeclareFixedRoles(service Service, cfg *setting.Cfg) error {
 		Role:   generalAuthConfigWriterRole,
 		Grants: []string{RoleGrafanaAdmin},
 	}
+	rolesReader := RoleRegistration{
+		Role:   rolesReaderRole,
+		Grants: []string{RoleGrafanaAdmin, string(org.RoleAdmin)},
+	}
+	rolesWriter := RoleRegistration{
+		Role:   rolesWriterRole,
+		Grants: []string{RoleGrafanaAdmin, string(org.RoleAdmin)},
+	}
 
 	// TODO: Move to own service when implemented
 	authenticationConfigWriter := RoleRegistration{
@@ -328,7 +392,8 @@ func DeclareFixedRoles(service Service, cfg *setting.Cfg) error {
 	}
 
 	return service.DeclareFixedRoles(ldapReader, ldapWriter, orgUsersReader, orgUsersWriter,
-		settingsReader, statsReader, usersReader, usersWriter, authenticationConfigWriter, generalAuthConfigWriter)
+		settingsReader, statsReader, usersReader, usersWriter, authenticationConfigWriter, generalAuthConfigWriter,
+		rolesReader, rolesWriter)
 }
 
 func ConcatPermissions(permissions ...[]Permission) []Permission {
@@ -22,12 +22,14 @@ const (
 	ClientAnonymous   = "auth.client.anonymous"
 	ClientBasic       = "auth.client.basic"
 	ClientJWT         = "auth.client.jwt"
+	ClientMTLS        = "auth.client.mtls"
 	ClientExtendedJWT = "auth.client.extended-jwt"
 	ClientRender      = "auth.client.render"
 	ClientSession     = "auth.client.session"
 	ClientForm        = "auth.client.form"
 	ClientProxy       = "auth.client.proxy"
 	ClientSAML        = "auth.client.saml"
+	ClientPlaylist    = "auth.client.playlist-kiosk"
 )
 
 const (
@@ -172,6 +174,8 @@ func (r *Request) GetMeta(k string) string {
 const (
 	KeyOAuthPKCE  = "pkce"
 	KeyOAuthState = "state"
+
+	KeySAMLRequestID = "saml_request_id"
 )
 
 type Redirect struct {
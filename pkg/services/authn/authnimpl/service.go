@@ -3,6 +3,7 @@ package authnimpl
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
+	"github.com/grafana/grafana/pkg/login/saml"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
@@ -30,9 +32,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/signingkeys"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/twofactor"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -72,8 +77,10 @@ func ProvideService(
 	socialService social.Service, cache *remotecache.RemoteCache,
 	ldapService service.LDAP, registerer prometheus.Registerer,
 	signingKeysService signingkeys.Service,
-	settingsProviderService setting.Provider,
-) *Service {
+	settingsProviderService setting.Provider, playlistService playlist.Service,
+	teamSyncService teamsync.Service, twoFactorService twofactor.Service,
+	samlService saml.Service,
+) (*Service, error) {
 	s := &Service{
 		log:             log.New("authn.service"),
 		cfg:             cfg,
@@ -91,9 +98,10 @@ func ProvideService(
 
 	s.RegisterClient(clients.ProvideRender(userService, renderService))
 	s.RegisterClient(clients.ProvideAPIKey(apikeyService, userService))
+	s.RegisterClient(clients.ProvidePlaylistKiosk(playlistService))
 
 	if cfg.LoginCookieName != "" {
-		s.RegisterClient(clients.ProvideSession(cfg, sessionService))
+		s.RegisterClient(clients.ProvideSession(cfg, sessionService, twoFactorService))
 	}
 
 	var proxyClients []authn.ProxyClient
@@ -114,11 +122,11 @@ func ProvideService(
 	if len(passwordClients) > 0 {
 		passwordClient := clients.ProvidePassword(loginAttempts, passwordClients...)
 		if s.cfg.BasicAuthEnabled {
-			s.RegisterClient(clients.ProvideBasic(passwordClient))
+			s.RegisterClient(clients.ProvideBasic(passwordClient, twoFactorService))
 		}
 
 		if !s.cfg.DisableLoginForm {
-			s.RegisterClient(clients.ProvideForm(passwordClient))
+			s.RegisterClient(clients.ProvideForm(passwordClient, loginAttempts, twoFactorService))
 		}
 	}
 
@@ -135,6 +143,14 @@ func ProvideService(
 		s.RegisterClient(clients.ProvideJWT(jwtService, cfg))
 	}
 
+	if s.cfg.MTLSAuth.Enabled {
+		mtls, err := clients.ProvideMTLS(cfg, userService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure mTLS authentication: %w", err)
+		}
+		s.RegisterClient(mtls)
+	}
+
 	// FIXME (gamab): Commenting that out for now as we want to re-use the client for external service auth
 	// if s.cfg.ExtendedJWTAuthEnabled && features.IsEnabledGlobally(featuremgmt.FlagExternalServiceAuth) {
 	// 	s.RegisterClient(clients.ProvideExtendedJWT(userService, cfg, signingKeysService, oauthServer))
@@ -145,12 +161,16 @@ func ProvideService(
 		s.RegisterClient(clients.ProvideOAuth(clientName, cfg, oauthTokenService, socialService, settingsProviderService))
 	}
 
+	// SAML can be enabled at runtime through the SSO settings, the client checks if it is enabled on each request
+	s.RegisterClient(clients.ProvideSAML(cfg, samlService))
+
 	// FIXME (jguer): move to User package
 	userSyncService := sync.ProvideUserSync(userService, userProtectionService, authInfoService, quotaService)
 	orgUserSyncService := sync.ProvideOrgSync(userService, orgService, accessControlService)
 	s.RegisterPostAuthHook(userSyncService.SyncUserHook, 10)
 	s.RegisterPostAuthHook(userSyncService.EnableUserHook, 20)
 	s.RegisterPostAuthHook(orgUserSyncService.SyncOrgRolesHook, 30)
+	s.RegisterPostAuthHook(sync.ProvideTeamSync(teamSyncService).SyncTeamsHook, 40)
 	s.RegisterPostAuthHook(userSyncService.SyncLastSeenHook, 130)
 	s.RegisterPostAuthHook(sync.ProvideOAuthTokenSync(oauthTokenService, sessionService, socialService).SyncOauthTokenHook, 60)
 	s.RegisterPostAuthHook(userSyncService.FetchSyncedUserHook, 100)
@@ -162,7 +182,7 @@ func ProvideService(
 
 	s.RegisterPostAuthHook(rbacSync.SyncPermissionsHook, 120)
 
-	return s
+	return s, nil
 }
 
 type Service struct {
@@ -359,7 +379,8 @@ func (s *Service) Logout(ctx context.Context, user identity.Requester, sessionTo
 
 	info, _ := s.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{UserId: userID})
 	if info != nil {
-		client := authn.ClientWithPrefix(strings.TrimPrefix(info.AuthModule, "oauth_"))
+		// auth modules are prefixed, e.g. oauth_github or auth.saml
+		client := authn.ClientWithPrefix(strings.TrimPrefix(strings.TrimPrefix(info.AuthModule, "oauth_"), "auth."))
 
 		c, ok := s.clients[client]
 		if !ok {
@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
+func ProvideTeamSync(teamSyncService teamsync.Service) *TeamSync {
+	return &TeamSync{teamSyncService, log.New("team.sync")}
+}
+
+type TeamSync struct {
+	teamSyncService teamsync.Service
+
+	log log.Logger
+}
+
+// SyncTeamsHook syncs the team memberships of the identity from the groups
+// provided by the identity provider, such as LDAP groups or OAuth group claims.
+func (s *TeamSync) SyncTeamsHook(ctx context.Context, id *authn.Identity, _ *authn.Request) error {
+	if !id.ClientParams.SyncTeams {
+		return nil
+	}
+
+	ctxLogger := s.log.FromContext(ctx)
+
+	namespace, identifier := id.GetNamespacedID()
+	if namespace != authn.NamespaceUser {
+		ctxLogger.Warn("Failed to sync teams, invalid namespace for identity", "id", id.ID, "namespace", namespace)
+		return nil
+	}
+
+	userID, err := identity.IntIdentifier(namespace, identifier)
+	if err != nil {
+		ctxLogger.Warn("Failed to sync teams, invalid ID for identity", "id", id.ID, "namespace", namespace, "err", err)
+		return nil
+	}
+
+	ctxLogger.Debug("Syncing teams", "id", id.ID, "groups", id.Groups)
+	if err := s.teamSyncService.SyncUserTeams(ctx, &teamsync.SyncUserTeamsCommand{UserID: userID, Groups: id.Groups}); err != nil {
+		ctxLogger.Error("Failed to sync teams", "id", id.ID, "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
+)
+
+func TestTeamSync_SyncTeamsHook(t *testing.T) {
+	groups := []string{"cn=editors,ou=groups,dc=grafana,dc=org"}
+
+	tests := []struct {
+		name           string
+		identity       *authn.Identity
+		syncError      error
+		expectedSynced []*teamsync.SyncUserTeamsCommand
+	}{
+		{
+			name:     "should sync teams of user identity",
+			identity: &authn.Identity{ID: "user:2", Groups: groups, ClientParams: authn.ClientParams{SyncTeams: true}},
+			expectedSynced: []*teamsync.SyncUserTeamsCommand{
+				{UserID: 2, Groups: groups},
+			},
+		},
+		{
+			name:     "should sync teams of user identity without groups",
+			identity: &authn.Identity{ID: "user:2", ClientParams: authn.ClientParams{SyncTeams: true}},
+			expectedSynced: []*teamsync.SyncUserTeamsCommand{
+				{UserID: 2},
+			},
+		},
+		{
+			name:     "should skip when team sync is not requested",
+			identity: &authn.Identity{ID: "user:2", Groups: groups},
+		},
+		{
+			name:     "should skip identities that are not users",
+			identity: &authn.Identity{ID: "service-account:2", Groups: groups, ClientParams: authn.ClientParams{SyncTeams: true}},
+		},
+		{
+			name:      "should not fail authentication when sync fails",
+			identity:  &authn.Identity{ID: "user:2", Groups: groups, ClientParams: authn.ClientParams{SyncTeams: true}},
+			syncError: errors.New("sync failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &teamsynctest.FakeService{ExpectedError: tt.syncError}
+			s := ProvideTeamSync(fake)
+
+			err := s.SyncTeamsHook(context.Background(), tt.identity, &authn.Request{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSynced, fake.SyncedUsers)
+		})
+	}
+}
@@ -4,21 +4,26 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/twofactor"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
 	errDecodingBasicAuthHeader = errutil.BadRequest("basic-auth.invalid-header", errutil.WithPublicMessage("Invalid Basic Auth Header"))
+	errBasicAuthTwoFactor      = errutil.Unauthorized("basic-auth.two-factor", errutil.WithPublicMessage("Basic auth is not available for users with two-factor authentication, use a service account token instead"))
 )
 
 var _ authn.ContextAwareClient = new(Basic)
+var _ authn.HookClient = new(Basic)
 
-func ProvideBasic(client authn.PasswordClient) *Basic {
-	return &Basic{client}
+func ProvideBasic(client authn.PasswordClient, twoFactorService twofactor.Service) *Basic {
+	return &Basic{client, twoFactorService}
 }
 
 type Basic struct {
-	client authn.PasswordClient
+	client           authn.PasswordClient
+	twoFactorService twofactor.Service
 }
 
 func (c *Basic) String() string {
@@ -49,6 +54,23 @@ func (c *Basic) Priority() uint {
 	return 40
 }
 
+// Hook rejects users that have, or are required to have, a second factor
+// since basic auth can not provide one.
+func (c *Basic) Hook(ctx context.Context, identity *authn.Identity, r *authn.Request) error {
+	if identity.AuthenticatedBy != login.PasswordAuthModule {
+		return nil
+	}
+
+	userID, status, err := getTwoFactorStatus(ctx, c.twoFactorService, identity)
+	if err != nil {
+		return err
+	}
+	if status != nil && (status.Enabled || status.Enforced) {
+		return errBasicAuthTwoFactor.Errorf("user %d requires two-factor authentication", userID)
+	}
+	return nil
+}
+
 func looksLikeBasicAuthRequest(r *authn.Request) bool {
 	_, _, ok := getBasicAuthFromRequest(r)
 	return ok
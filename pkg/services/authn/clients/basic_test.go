@@ -9,6 +9,9 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
 )
 
 func TestBasic_Authenticate(t *testing.T) {
@@ -36,7 +39,7 @@ func TestBasic_Authenticate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideBasic(tt.client)
+			c := ProvideBasic(tt.client, twofactortest.NewFakeService())
 
 			identity, err := c.Authenticate(context.Background(), tt.req)
 			if tt.expectedErr != nil {
@@ -89,8 +92,54 @@ func TestBasic_Test(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideBasic(authntest.FakePasswordClient{})
+			c := ProvideBasic(authntest.FakePasswordClient{}, twofactortest.NewFakeService())
 			assert.Equal(t, tt.expected, c.Test(context.Background(), tt.req))
 		})
 	}
 }
+
+func TestBasic_Hook(t *testing.T) {
+	type TestCase struct {
+		desc        string
+		identity    *authn.Identity
+		status      *twofactor.Status
+		expectedErr error
+	}
+
+	tests := []TestCase{
+		{
+			desc:     "should allow users without two-factor authentication",
+			identity: &authn.Identity{ID: "user:1", AuthenticatedBy: login.PasswordAuthModule},
+			status:   &twofactor.Status{},
+		},
+		{
+			desc:        "should reject users with two-factor authentication enabled",
+			identity:    &authn.Identity{ID: "user:1", AuthenticatedBy: login.PasswordAuthModule},
+			status:      &twofactor.Status{Enabled: true},
+			expectedErr: errBasicAuthTwoFactor,
+		},
+		{
+			desc:        "should reject users with two-factor authentication enforced",
+			identity:    &authn.Identity{ID: "user:1", AuthenticatedBy: login.PasswordAuthModule},
+			status:      &twofactor.Status{Enforced: true},
+			expectedErr: errBasicAuthTwoFactor,
+		},
+		{
+			desc:     "should skip users authenticated by ldap",
+			identity: &authn.Identity{ID: "user:1", AuthenticatedBy: login.LDAPAuthModule},
+			status:   &twofactor.Status{Enabled: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := ProvideBasic(authntest.FakePasswordClient{}, &twofactortest.FakeService{ExpectedStatus: tt.status})
+			err := c.Hook(context.Background(), tt.identity, &authn.Request{})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,8 +2,12 @@ package clients
 
 import (
 	"context"
+	"errors"
 
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/twofactor"
 	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -12,19 +16,26 @@ var (
 	errBadForm = errutil.BadRequest("form-auth.invalid", errutil.WithPublicMessage("bad login data"))
 )
 
-var _ authn.Client = new(Form)
+const metaKeyTwoFactorCode = "twoFactorCode"
 
-func ProvideForm(client authn.PasswordClient) *Form {
-	return &Form{client}
+var _ authn.HookClient = new(Form)
+
+func ProvideForm(client authn.PasswordClient, loginAttempts loginattempt.Service, twoFactorService twofactor.Service) *Form {
+	return &Form{client, loginAttempts, twoFactorService}
 }
 
 type Form struct {
-	client authn.PasswordClient
+	client           authn.PasswordClient
+	loginAttempts    loginattempt.Service
+	twoFactorService twofactor.Service
 }
 
 type loginForm struct {
 	Username string `json:"user" binding:"Required"`
 	Password string `json:"password" binding:"Required"`
+	// TwoFactorCode is a TOTP or recovery code, it is required for users that
+	// have two-factor authentication enabled.
+	TwoFactorCode string `json:"twoFactorCode"`
 }
 
 func (c *Form) Name() string {
@@ -36,5 +47,36 @@ func (c *Form) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ident
 	if err := web.Bind(r.HTTPRequest, &form); err != nil {
 		return nil, errBadForm.Errorf("failed to parse request: %w", err)
 	}
+	r.SetMeta(metaKeyTwoFactorCode, form.TwoFactorCode)
 	return c.client.AuthenticatePassword(ctx, r, form.Username, form.Password)
 }
+
+// Hook verifies the second factor of users logging in with a Grafana password
+// once the password has been checked and the user is synced.
+func (c *Form) Hook(ctx context.Context, identity *authn.Identity, r *authn.Request) error {
+	if identity.AuthenticatedBy != login.PasswordAuthModule {
+		return nil
+	}
+
+	userID, status, err := getTwoFactorStatus(ctx, c.twoFactorService, identity)
+	if err != nil {
+		return err
+	}
+	if status == nil || !status.Enabled {
+		return nil
+	}
+
+	code := r.GetMeta(metaKeyTwoFactorCode)
+	if code == "" {
+		return twofactor.ErrCodeRequired.Errorf("user %d has two-factor authentication enabled", userID)
+	}
+
+	if err := c.twoFactorService.Verify(ctx, &twofactor.VerifyCommand{UserID: userID, Code: code}); err != nil {
+		// count invalid codes as failed logins so they are rate limited like passwords
+		if errors.Is(err, twofactor.ErrInvalidCode) {
+			_ = c.loginAttempts.Add(ctx, r.GetMeta(authn.MetaKeyUsername), web.RemoteAddr(r.HTTPRequest))
+		}
+		return err
+	}
+	return nil
+}
@@ -11,6 +11,10 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
 )
 
 func TestForm_Authenticate(t *testing.T) {
@@ -40,9 +44,80 @@ func TestForm_Authenticate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideForm(&authntest.FakePasswordClient{})
+			c := ProvideForm(&authntest.FakePasswordClient{}, loginattempttest.FakeLoginAttemptService{}, twofactortest.NewFakeService())
 			_, err := c.Authenticate(context.Background(), tt.req)
 			assert.ErrorIs(t, err, tt.expectedErr)
 		})
 	}
 }
+
+func TestForm_Hook(t *testing.T) {
+	type testCase struct {
+		desc              string
+		body              string
+		status            *twofactor.Status
+		verifyErr         error
+		expectedErr       error
+		expectedVerified  []string
+		expectedAttempted bool
+	}
+
+	tests := []testCase{
+		{
+			desc:   "should not require a code for users without two-factor authentication",
+			body:   `{"user": "test", "password": "test"}`,
+			status: &twofactor.Status{},
+		},
+		{
+			desc:   "should not require a code for users that have not enrolled yet",
+			body:   `{"user": "test", "password": "test"}`,
+			status: &twofactor.Status{Enforced: true},
+		},
+		{
+			desc:        "should require a code for users with two-factor authentication",
+			body:        `{"user": "test", "password": "test"}`,
+			status:      &twofactor.Status{Enabled: true},
+			expectedErr: twofactor.ErrCodeRequired,
+		},
+		{
+			desc:             "should succeed with a valid code",
+			body:             `{"user": "test", "password": "test", "twoFactorCode": "123456"}`,
+			status:           &twofactor.Status{Enabled: true},
+			expectedVerified: []string{"123456"},
+		},
+		{
+			desc:              "should fail and count the attempt with an invalid code",
+			body:              `{"user": "test", "password": "test", "twoFactorCode": "654321"}`,
+			status:            &twofactor.Status{Enabled: true},
+			verifyErr:         twofactor.ErrInvalidCode.Errorf("invalid code"),
+			expectedErr:       twofactor.ErrInvalidCode,
+			expectedVerified:  []string{"654321"},
+			expectedAttempted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			identity := &authn.Identity{ID: "user:1", AuthenticatedBy: login.PasswordAuthModule}
+			loginAttempts := &loginattempttest.MockLoginAttemptService{}
+			twoFactorService := &twofactortest.FakeService{ExpectedStatus: tt.status, ExpectedVerifyError: tt.verifyErr}
+			c := ProvideForm(&authntest.FakePasswordClient{ExpectedIdentity: identity}, loginAttempts, twoFactorService)
+
+			req := &authn.Request{HTTPRequest: &http.Request{
+				Header: map[string][]string{"Content-Type": {"application/json"}},
+				Body:   io.NopCloser(strings.NewReader(tt.body)),
+			}}
+			_, err := c.Authenticate(context.Background(), req)
+			assert.NoError(t, err)
+
+			err = c.Hook(context.Background(), identity, req)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedVerified, twoFactorService.VerifiedCodes)
+			assert.Equal(t, tt.expectedAttempted, loginAttempts.AddCalled)
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	errMTLSUnverified   = errutil.Unauthorized("mtls.unverified", errutil.WithPublicMessage("Client certificate could not be verified"))
+	errMTLSRevoked      = errutil.Unauthorized("mtls.revoked", errutil.WithPublicMessage("Client certificate has been revoked"))
+	errMTLSNoMatch      = errutil.Unauthorized("mtls.no-match", errutil.WithPublicMessage("Client certificate is not mapped to any identity"))
+	errMTLSIdentityType = errutil.Unauthorized("mtls.identity-type", errutil.WithPublicMessage("Client certificate is mapped to an identity of the wrong type"))
+)
+
+var _ authn.ContextAwareClient = new(MTLS)
+
+func ProvideMTLS(cfg *setting.Cfg, userService user.Service) (*MTLS, error) {
+	crls, err := newCRLStore(cfg.MTLSAuth.CRLFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MTLS{
+		cfg:         cfg,
+		log:         log.New(authn.ClientMTLS),
+		userService: userService,
+		crls:        crls,
+	}, nil
+}
+
+// MTLS authenticates requests with the client certificate verified during the TLS
+// handshake. The certificate is mapped to a service account or user with the rules
+// configured in [auth.mtls.rule.<name>] sections.
+type MTLS struct {
+	cfg         *setting.Cfg
+	log         log.Logger
+	userService user.Service
+	crls        *crlStore
+}
+
+func (c *MTLS) Name() string {
+	return authn.ClientMTLS
+}
+
+func (c *MTLS) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	state := r.HTTPRequest.TLS
+	// the chains are only populated when the certificate was verified against the configured CA bundle
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return nil, errMTLSUnverified.Errorf("client certificate was not verified")
+	}
+
+	cert := state.VerifiedChains[0][0]
+	if err := c.crls.check(state.VerifiedChains[0]); err != nil {
+		return nil, err
+	}
+
+	rule, loginName, ok := matchMTLSRule(c.cfg.MTLSAuth.Rules, cert)
+	if !ok {
+		return nil, errMTLSNoMatch.Errorf("no mapping rule matches certificate %s", cert.Subject)
+	}
+
+	c.log.FromContext(ctx).Debug("Client certificate matched rule", "rule", rule.Name, "subject", cert.Subject.String(), "login", loginName)
+
+	usr, err := c.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: loginName})
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := authn.NamespaceUser
+	if rule.IdentityType == setting.MTLSIdentityServiceAccount {
+		namespace = authn.NamespaceServiceAccount
+	}
+	if usr.IsServiceAccount != (namespace == authn.NamespaceServiceAccount) {
+		return nil, errMTLSIdentityType.Errorf("rule %s expects a %s but %s is not one", rule.Name, rule.IdentityType, loginName)
+	}
+
+	orgID := r.OrgID
+	if orgID == 0 {
+		orgID = usr.OrgID
+	}
+
+	signedInUser, err := c.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{
+		UserID: usr.ID,
+		OrgID:  orgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	identity := authn.IdentityFromSignedInUser(authn.NamespacedID(namespace, signedInUser.UserID), signedInUser, authn.ClientParams{SyncPermissions: true}, login.MTLSAuthModule)
+	identity.AuthID = cert.Subject.String()
+	return identity, nil
+}
+
+func (c *MTLS) Test(ctx context.Context, r *authn.Request) bool {
+	if !c.cfg.MTLSAuth.Enabled || r.HTTPRequest == nil || r.HTTPRequest.TLS == nil {
+		return false
+	}
+	return len(r.HTTPRequest.TLS.PeerCertificates) > 0
+}
+
+func (c *MTLS) Priority() uint {
+	return 25
+}
+
+// matchMTLSRule returns the first rule matching the certificate together with the
+// login the rule expands to.
+func matchMTLSRule(rules []setting.MTLSMappingRule, cert *x509.Certificate) (setting.MTLSMappingRule, string, bool) {
+	for _, rule := range rules {
+		for _, value := range mtlsFieldValues(rule.Field, cert) {
+			match := rule.Pattern.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+
+			loginName := string(rule.Pattern.ExpandString(nil, rule.Login, value, match))
+			if loginName != "" {
+				return rule, loginName, true
+			}
+		}
+	}
+	return setting.MTLSMappingRule{}, "", false
+}
+
+func mtlsFieldValues(field string, cert *x509.Certificate) []string {
+	switch field {
+	case setting.MTLSFieldSubjectCN:
+		return []string{cert.Subject.CommonName}
+	case setting.MTLSFieldSubject:
+		return []string{cert.Subject.String()}
+	case setting.MTLSFieldSANDNS:
+		return cert.DNSNames
+	case setting.MTLSFieldSANEmail:
+		return cert.EmailAddresses
+	case setting.MTLSFieldSANURI:
+		uris := make([]string, 0, len(cert.URIs))
+		for _, u := range cert.URIs {
+			uris = append(uris, u.String())
+		}
+		return uris
+	default:
+		return nil
+	}
+}
+
+// crlStore holds the certificate revocation lists configured in auth.mtls.crl_files.
+// The files are reloaded when they are modified so that rotated lists are picked up
+// without a restart.
+type crlStore struct {
+	mu      sync.RWMutex
+	files   []string
+	modTime map[string]time.Time
+	lists   []*x509.RevocationList
+}
+
+func newCRLStore(files []string) (*crlStore, error) {
+	s := &crlStore{files: files, modTime: map[string]time.Time{}}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *crlStore) load() error {
+	lists := make([]*x509.RevocationList, 0, len(s.files))
+	modTime := make(map[string]time.Time, len(s.files))
+	for _, file := range s.files {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `file` comes from grafana configuration file
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read CRL %s: %w", file, err)
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to stat CRL %s: %w", file, err)
+		}
+		modTime[file] = info.ModTime()
+
+		parsed, err := parseCRLs(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse CRL %s: %w", file, err)
+		}
+		lists = append(lists, parsed...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists = lists
+	s.modTime = modTime
+	return nil
+}
+
+func (s *crlStore) stale() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, file := range s.files {
+		info, err := os.Stat(file)
+		if err != nil || !info.ModTime().Equal(s.modTime[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// check returns an error if any certificate of the chain, except the root, has been
+// revoked by a CRL signed by its issuer.
+func (s *crlStore) check(chain []*x509.Certificate) error {
+	if len(s.files) == 0 {
+		return nil
+	}
+
+	if s.stale() {
+		// fail closed, a list that cannot be read could hide revoked certificates
+		if err := s.load(); err != nil {
+			return errMTLSRevoked.Errorf("failed to reload certificate revocation lists: %w", err)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i := 0; i < len(chain)-1; i++ {
+		cert, issuer := chain[i], chain[i+1]
+		for _, crl := range s.lists {
+			if !bytes.Equal(crl.RawIssuer, cert.RawIssuer) || crl.CheckSignatureFrom(issuer) != nil {
+				continue
+			}
+			for _, revoked := range crl.RevokedCertificateEntries {
+				if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+					return errMTLSRevoked.Errorf("certificate %s with serial %s has been revoked", cert.Subject, cert.SerialNumber)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseCRLs parses PEM encoded revocation lists, or a single DER encoded one.
+func parseCRLs(data []byte) ([]*x509.RevocationList, error) {
+	var lists []*x509.RevocationList
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "X509 CRL" {
+			continue
+		}
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, crl)
+	}
+
+	if len(lists) > 0 {
+		return lists, nil
+	}
+
+	crl, err := x509.ParseRevocationList(data)
+	if err != nil {
+		return nil, err
+	}
+	return []*x509.RevocationList{crl}, nil
+}
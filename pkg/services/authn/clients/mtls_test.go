@@ -0,0 +1,221 @@
+package clients
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestMTLS_Authenticate(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	leaf := newTestLeaf(t, ca, caKey, 2)
+
+	type testCase struct {
+		desc             string
+		state            *tls.ConnectionState
+		rules            []setting.MTLSMappingRule
+		revoked          bool
+		expectedUser     *user.User
+		expectedErr      error
+		expectedIdentity *authn.Identity
+	}
+
+	ciRule := setting.MTLSMappingRule{
+		Name:         "ci",
+		Field:        setting.MTLSFieldSANURI,
+		Pattern:      regexp.MustCompile(`^spiffe://example.org/ci/(.+)$`),
+		IdentityType: setting.MTLSIdentityServiceAccount,
+		Login:        "sa-$1",
+	}
+
+	tests := []testCase{
+		{
+			desc:        "should return error when certificate is not verified",
+			state:       &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}},
+			rules:       []setting.MTLSMappingRule{ciRule},
+			expectedErr: errMTLSUnverified,
+		},
+		{
+			desc:        "should return error when no rule matches",
+			state:       verifiedState(leaf, ca),
+			rules:       []setting.MTLSMappingRule{{Name: "other", Field: setting.MTLSFieldSubjectCN, Pattern: regexp.MustCompile(`^other$`), Login: "$0"}},
+			expectedErr: errMTLSNoMatch,
+		},
+		{
+			desc:         "should return error when identity type does not match",
+			state:        verifiedState(leaf, ca),
+			rules:        []setting.MTLSMappingRule{ciRule},
+			expectedUser: &user.User{ID: 3, Login: "sa-deployer", OrgID: 1},
+			expectedErr:  errMTLSIdentityType,
+		},
+		{
+			desc:         "should return error when certificate is revoked",
+			state:        verifiedState(leaf, ca),
+			rules:        []setting.MTLSMappingRule{ciRule},
+			revoked:      true,
+			expectedUser: &user.User{ID: 3, Login: "sa-deployer", OrgID: 1, IsServiceAccount: true},
+			expectedErr:  errMTLSRevoked,
+		},
+		{
+			desc:         "should map certificate to service account",
+			state:        verifiedState(leaf, ca),
+			rules:        []setting.MTLSMappingRule{ciRule},
+			expectedUser: &user.User{ID: 3, Login: "sa-deployer", OrgID: 1, IsServiceAccount: true},
+			expectedIdentity: &authn.Identity{
+				ID:              "service-account:3",
+				OrgID:           1,
+				OrgRoles:        map[int64]org.RoleType{1: org.RoleEditor},
+				Login:           "sa-deployer",
+				AuthenticatedBy: login.MTLSAuthModule,
+				AuthID:          "CN=deployer",
+				IsGrafanaAdmin:  boolPtr(false),
+				ClientParams:    authn.ClientParams{SyncPermissions: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.MTLSAuth = setting.AuthMTLSSettings{Enabled: true, Rules: tt.rules}
+			if tt.revoked {
+				cfg.MTLSAuth.CRLFiles = []string{writeTestCRL(t, ca, caKey, leaf.SerialNumber)}
+			} else {
+				cfg.MTLSAuth.CRLFiles = []string{writeTestCRL(t, ca, caKey, big.NewInt(42))}
+			}
+
+			userService := &usertest.FakeUserService{ExpectedUser: tt.expectedUser}
+			if tt.expectedUser != nil {
+				userService.ExpectedSignedInUser = &user.SignedInUser{
+					UserID:           tt.expectedUser.ID,
+					OrgID:            tt.expectedUser.OrgID,
+					Login:            tt.expectedUser.Login,
+					OrgRole:          org.RoleEditor,
+					IsServiceAccount: tt.expectedUser.IsServiceAccount,
+				}
+			}
+
+			c, err := ProvideMTLS(cfg, userService)
+			require.NoError(t, err)
+
+			identity, err := c.Authenticate(context.Background(), &authn.Request{HTTPRequest: &http.Request{TLS: tt.state}})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.EqualValues(t, tt.expectedIdentity, identity)
+		})
+	}
+}
+
+func TestMTLS_Test(t *testing.T) {
+	ca, caKey := newTestCA(t)
+	leaf := newTestLeaf(t, ca, caKey, 2)
+
+	cfg := setting.NewCfg()
+	c, err := ProvideMTLS(cfg, &usertest.FakeUserService{})
+	require.NoError(t, err)
+
+	req := &authn.Request{HTTPRequest: &http.Request{TLS: verifiedState(leaf, ca)}}
+	assert.False(t, c.Test(context.Background(), req))
+
+	cfg.MTLSAuth.Enabled = true
+	assert.True(t, c.Test(context.Background(), req))
+	assert.False(t, c.Test(context.Background(), &authn.Request{HTTPRequest: &http.Request{}}))
+	assert.False(t, c.Test(context.Background(), &authn.Request{HTTPRequest: &http.Request{TLS: &tls.ConnectionState{}}}))
+}
+
+func verifiedState(leaf, ca *x509.Certificate) *tls.ConnectionState {
+	return &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{leaf},
+		VerifiedChains:   [][]*x509.Certificate{{leaf, ca}},
+	}
+}
+
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func newTestLeaf(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, serial int64) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "deployer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{{Scheme: "spiffe", Host: "example.org", Path: "/ci/deployer"}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func writeTestCRL(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, revoked *big.Int) string {
+	t.Helper()
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Hour),
+		NextUpdate: time.Now().Add(time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: revoked, RevocationTime: time.Now().Add(-time.Minute)},
+		},
+	}, ca, caKey)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "ca.crl")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0600))
+	return file
+}
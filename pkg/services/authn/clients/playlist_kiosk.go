@@ -0,0 +1,91 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	errInvalidKioskToken = errutil.Unauthorized("playlist-kiosk-auth.invalid-token", errutil.WithPublicMessage("Invalid playlist kiosk token"))
+)
+
+var _ authn.ContextAwareClient = new(PlaylistKiosk)
+
+func ProvidePlaylistKiosk(playlistService playlist.Service) *PlaylistKiosk {
+	return &PlaylistKiosk{playlistService}
+}
+
+// PlaylistKiosk authenticates devices that run a playlist with a kiosk token.
+// They can only view the dashboards and query the data sources granted to the
+// token when it was created, see playlist.CreateKioskTokenCommand.
+type PlaylistKiosk struct {
+	playlistService playlist.Service
+}
+
+func (c *PlaylistKiosk) Name() string {
+	return authn.ClientPlaylist
+}
+
+func (c *PlaylistKiosk) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	p, err := c.playlistService.GetByKioskToken(ctx, &playlist.GetPlaylistByKioskTokenQuery{KioskToken: getKioskToken(r)})
+	if err != nil {
+		if errors.Is(err, playlist.ErrKioskTokenNotFound) {
+			return nil, errInvalidKioskToken.Errorf("found no playlist for kiosk token")
+		}
+		return nil, err
+	}
+
+	scopes := make([]string, 0, len(p.KioskDashboardUIDs))
+	for _, uid := range p.KioskDashboardUIDs {
+		scopes = append(scopes, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(uid))
+	}
+
+	queryScopes := make([]string, 0, len(p.KioskDataSourceUIDs))
+	for _, uid := range p.KioskDataSourceUIDs {
+		queryScopes = append(queryScopes, datasources.ScopeProvider.GetResourceScopeUID(uid))
+	}
+
+	return &authn.Identity{
+		ID:       authn.AnonymousNamespaceID,
+		OrgID:    p.OrgID,
+		OrgRoles: map[int64]org.RoleType{p.OrgID: org.RoleNone},
+		Name:     p.Name,
+		Permissions: map[int64]map[string][]string{
+			p.OrgID: {
+				dashboards.ActionDashboardsRead: scopes,
+				datasources.ActionQuery:         queryScopes,
+			},
+		},
+		AuthenticatedBy: login.PlaylistKioskModule,
+		LastSeenAt:      time.Now(),
+	}, nil
+}
+
+func (c *PlaylistKiosk) Test(ctx context.Context, r *authn.Request) bool {
+	if r.HTTPRequest == nil {
+		return false
+	}
+	return getKioskToken(r) != ""
+}
+
+func (c *PlaylistKiosk) Priority() uint {
+	// after sessions, so a signed in user keeps their own identity
+	return 70
+}
+
+func getKioskToken(r *authn.Request) string {
+	cookie, err := r.HTTPRequest.Cookie(playlist.KioskCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/playlist/playlisttest"
+)
+
+func TestPlaylistKiosk_Authenticate(t *testing.T) {
+	req := &authn.Request{HTTPRequest: &http.Request{
+		Header: map[string][]string{"Cookie": {"grafana_playlist_kiosk=123"}},
+	}}
+
+	t.Run("should only grant what was resolved when the token was created", func(t *testing.T) {
+		c := ProvidePlaylistKiosk(&playlisttest.FakePlaylistService{
+			ExpectedPlaylistDTO: &playlist.PlaylistDTO{
+				Uid:                 "noc",
+				Name:                "NOC wall",
+				OrgID:               2,
+				KioskDashboardUIDs:  []string{"ops", "db"},
+				KioskDataSourceUIDs: []string{"prometheus", "loki"},
+			},
+			// resolving the playlist again must not widen the grants
+			ExpectedDashboardUIDs: []string{"ops", "db", "secret"},
+		})
+
+		identity, err := c.Authenticate(context.Background(), req)
+		require.NoError(t, err)
+		// ignore LastSeenAt
+		identity.LastSeenAt = time.Time{}
+		assert.EqualValues(t, authn.Identity{
+			ID:       "anonymous:0",
+			OrgID:    2,
+			OrgRoles: map[int64]org.RoleType{2: org.RoleNone},
+			Name:     "NOC wall",
+			Permissions: map[int64]map[string][]string{
+				2: {
+					"dashboards:read":   {"dashboards:uid:ops", "dashboards:uid:db"},
+					"datasources:query": {"datasources:uid:prometheus", "datasources:uid:loki"},
+				},
+			},
+			AuthenticatedBy: login.PlaylistKioskModule,
+		}, *identity)
+	})
+
+	t.Run("should fail when the kiosk token is unknown", func(t *testing.T) {
+		c := ProvidePlaylistKiosk(&playlisttest.FakePlaylistService{
+			ExpectedError: playlist.ErrKioskTokenNotFound.Errorf("not found"),
+		})
+
+		identity, err := c.Authenticate(context.Background(), req)
+		assert.ErrorIs(t, err, errInvalidKioskToken)
+		assert.Nil(t, identity)
+	})
+}
+
+func TestPlaylistKiosk_Test(t *testing.T) {
+	c := ProvidePlaylistKiosk(&playlisttest.FakePlaylistService{})
+
+	assert.True(t, c.Test(context.Background(), &authn.Request{HTTPRequest: &http.Request{
+		Header: map[string][]string{"Cookie": {"grafana_playlist_kiosk=123"}},
+	}}))
+	assert.False(t, c.Test(context.Background(), &authn.Request{}))
+	assert.False(t, c.Test(context.Background(), &authn.Request{HTTPRequest: &http.Request{
+		Header: map[string][]string{"Cookie": {"renderKey=123"}},
+	}}))
+}
@@ -0,0 +1,192 @@
+package clients
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/login/saml"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	samlRequestIDCookieName = "saml_request_id"
+	samlRelayStateParam     = "RelayState"
+)
+
+var (
+	errSAMLInvalidRelayState = errutil.Unauthorized("auth.saml.relay-state.invalid", errutil.WithPublicMessage("Invalid SAML relay state"))
+	errSAMLMissingLogin      = errutil.Unauthorized("auth.saml.login.missing", errutil.WithPublicMessage("SAML assertion does not contain a login or an email"))
+)
+
+// samlTemplateVariable matches $__saml{attribute} in assertion_attribute_name.
+var samlTemplateVariable = regexp.MustCompile(`\$__saml\{([^}]+)\}`)
+
+var _ authn.LogoutClient = new(SAML)
+var _ authn.RedirectClient = new(SAML)
+
+func ProvideSAML(cfg *setting.Cfg, samlService saml.Service) *SAML {
+	return &SAML{cfg: cfg, log: log.New(authn.ClientSAML), samlService: samlService}
+}
+
+type SAML struct {
+	cfg         *setting.Cfg
+	log         log.Logger
+	samlService saml.Service
+}
+
+func (c *SAML) Name() string {
+	return authn.ClientSAML
+}
+
+func (c *SAML) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	r.SetMeta(authn.MetaKeyAuthModule, login.SAMLAuthModule)
+
+	info := c.samlService.GetInfo()
+	if !info.Enabled {
+		return nil, saml.ErrDisabled.Errorf("saml client is disabled")
+	}
+
+	var requestIDs []string
+	if cookie, err := r.HTTPRequest.Cookie(samlRequestIDCookieName); err == nil && cookie.Value != "" {
+		requestIDs = append(requestIDs, cookie.Value)
+	} else if info.RelayState != "" && r.HTTPRequest.FormValue(samlRelayStateParam) != info.RelayState {
+		// without a stored request id the login was initiated by the IdP
+		return nil, errSAMLInvalidRelayState.Errorf("relay state of IdP initiated login does not match the configured relay state")
+	}
+
+	assertion, err := c.samlService.ParseResponse(r.HTTPRequest, requestIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &authn.Identity{
+		Login:           assertion.Attribute(info.AssertionAttributeLogin),
+		Email:           assertion.Attribute(info.AssertionAttributeEmail),
+		Name:            samlName(info.AssertionAttributeName, assertion),
+		AuthenticatedBy: login.SAMLAuthModule,
+		AuthID:          assertion.NameID,
+		ClientParams: authn.ClientParams{
+			SyncUser:        true,
+			SyncTeams:       true,
+			FetchSyncedUser: true,
+			SyncPermissions: true,
+			AllowSignUp:     info.AllowSignUp,
+		},
+	}
+
+	if id.Login == "" {
+		id.Login = id.Email
+	}
+	if id.Login == "" {
+		return nil, errSAMLMissingLogin.Errorf("assertion for %s has no login or email attribute", assertion.NameID)
+	}
+
+	if info.AssertionAttributeGroups != "" {
+		id.Groups = assertion.Attributes[info.AssertionAttributeGroups]
+	}
+
+	id.OrgRoles, id.IsGrafanaAdmin, _ = getRoles(c.cfg, func() (org.RoleType, *bool, error) {
+		if info.SkipOrgRoleSync || info.AssertionAttributeRole == "" {
+			return "", nil, nil
+		}
+		role, isGrafanaAdmin := samlRole(info, assertion.Attributes[info.AssertionAttributeRole])
+		return role, isGrafanaAdmin, nil
+	})
+	id.ClientParams.SyncOrgRoles = len(id.OrgRoles) > 0
+
+	id.ClientParams.LookUpParams.Login = &id.Login
+	if id.Email != "" {
+		id.ClientParams.LookUpParams.Email = &id.Email
+	}
+
+	return id, nil
+}
+
+func (c *SAML) RedirectURL(ctx context.Context, r *authn.Request) (*authn.Redirect, error) {
+	if !c.samlService.GetInfo().Enabled {
+		return nil, saml.ErrDisabled.Errorf("saml client is disabled")
+	}
+
+	redirectURL, requestID, err := c.samlService.AuthnRequestURL("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &authn.Redirect{
+		URL: redirectURL,
+		Extra: map[string]string{
+			authn.KeySAMLRequestID: requestID,
+		},
+	}, nil
+}
+
+func (c *SAML) Logout(ctx context.Context, user identity.Requester, info *login.UserAuth) (*authn.Redirect, bool) {
+	samlInfo := c.samlService.GetInfo()
+	if !samlInfo.Enabled || !samlInfo.SingleLogout {
+		return nil, false
+	}
+
+	redirectURL, err := c.samlService.LogoutURL(info.AuthId, "")
+	if err != nil {
+		namespace, id := user.GetNamespacedID()
+		c.log.FromContext(ctx).Error("Failed to create SAML logout request", "namespace", namespace, "id", id, "error", err)
+		return nil, false
+	}
+
+	return &authn.Redirect{URL: redirectURL}, true
+}
+
+// samlName resolves the display name, attribute can either be the name of an
+// attribute or a template like "$__saml{firstName} $__saml{lastName}".
+func samlName(attribute string, assertion *saml.Assertion) string {
+	if !strings.Contains(attribute, "$__saml{") {
+		return assertion.Attribute(attribute)
+	}
+
+	name := samlTemplateVariable.ReplaceAllStringFunc(attribute, func(variable string) string {
+		return assertion.Attribute(samlTemplateVariable.FindStringSubmatch(variable)[1])
+	})
+	return strings.TrimSpace(name)
+}
+
+// samlRole maps the values of the role attribute to an org role. Users with
+// a value that is not mapped to any role become viewers.
+func samlRole(info *saml.Info, values []string) (org.RoleType, *bool) {
+	var isGrafanaAdmin *bool
+	if len(info.RoleValuesGrafanaAdmin) > 0 {
+		isAdmin := containsAny(values, info.RoleValuesGrafanaAdmin)
+		isGrafanaAdmin = &isAdmin
+		if isAdmin {
+			return org.RoleAdmin, isGrafanaAdmin
+		}
+	}
+
+	switch {
+	case containsAny(values, info.RoleValuesAdmin):
+		return org.RoleAdmin, isGrafanaAdmin
+	case containsAny(values, info.RoleValuesEditor):
+		return org.RoleEditor, isGrafanaAdmin
+	case containsAny(values, info.RoleValuesNone):
+		return org.RoleNone, isGrafanaAdmin
+	default:
+		return org.RoleViewer, isGrafanaAdmin
+	}
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, v := range values {
+		for _, c := range candidates {
+			if v == c {
+				return true
+			}
+		}
+	}
+	return false
+}
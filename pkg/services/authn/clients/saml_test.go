@@ -0,0 +1,295 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/login/saml"
+	"github.com/grafana/grafana/pkg/login/saml/samltest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSAML_Authenticate(t *testing.T) {
+	defaultInfo := func() *saml.Info {
+		return &saml.Info{
+			Enabled:                 true,
+			AllowSignUp:             true,
+			AssertionAttributeLogin: "login",
+			AssertionAttributeEmail: "mail",
+			AssertionAttributeName:  "displayName",
+		}
+	}
+
+	type testCase struct {
+		desc              string
+		info              *saml.Info
+		requestID         string
+		relayState        string
+		assertion         *saml.Assertion
+		parseErr          error
+		expectedErr       error
+		expectedRequestID []string
+		expectedIdentity  *authn.Identity
+	}
+
+	tests := []testCase{
+		{
+			desc:        "should return error when saml is disabled",
+			info:        &saml.Info{},
+			expectedErr: saml.ErrDisabled,
+		},
+		{
+			desc:        "should return error when the response is invalid",
+			info:        defaultInfo(),
+			requestID:   "id-1",
+			parseErr:    saml.ErrInvalidResponse.Errorf("invalid signature"),
+			expectedErr: saml.ErrInvalidResponse,
+		},
+		{
+			desc:      "should map attributes to identity",
+			info:      defaultInfo(),
+			requestID: "id-1",
+			assertion: &saml.Assertion{
+				NameID: "name-id",
+				Attributes: map[string][]string{
+					"login":       {"jdoe"},
+					"mail":        {"jdoe@example.com"},
+					"displayName": {"John Doe"},
+				},
+			},
+			expectedRequestID: []string{"id-1"},
+			expectedIdentity: &authn.Identity{
+				Login:           "jdoe",
+				Email:           "jdoe@example.com",
+				Name:            "John Doe",
+				AuthenticatedBy: login.SAMLAuthModule,
+				AuthID:          "name-id",
+				OrgRoles:        map[int64]org.RoleType{},
+				ClientParams: authn.ClientParams{
+					SyncUser:        true,
+					SyncTeams:       true,
+					FetchSyncedUser: true,
+					SyncPermissions: true,
+					AllowSignUp:     true,
+					LookUpParams: login.UserLookupParams{
+						Login: strPtr("jdoe"),
+						Email: strPtr("jdoe@example.com"),
+					},
+				},
+			},
+		},
+		{
+			desc: "should use name template, groups and role mapping",
+			info: func() *saml.Info {
+				info := defaultInfo()
+				info.AssertionAttributeName = "$__saml{firstName} $__saml{lastName}"
+				info.AssertionAttributeGroups = "groups"
+				info.AssertionAttributeRole = "role"
+				info.RoleValuesEditor = []string{"developer"}
+				info.RoleValuesAdmin = []string{"admin"}
+				info.RoleValuesGrafanaAdmin = []string{"superadmin"}
+				return info
+			}(),
+			requestID: "id-1",
+			assertion: &saml.Assertion{
+				NameID: "name-id",
+				Attributes: map[string][]string{
+					"login":     {"jdoe"},
+					"mail":      {"jdoe@example.com"},
+					"firstName": {"John"},
+					"lastName":  {"Doe"},
+					"groups":    {"team-a", "team-b"},
+					"role":      {"developer"},
+				},
+			},
+			expectedRequestID: []string{"id-1"},
+			expectedIdentity: &authn.Identity{
+				Login:           "jdoe",
+				Email:           "jdoe@example.com",
+				Name:            "John Doe",
+				AuthenticatedBy: login.SAMLAuthModule,
+				AuthID:          "name-id",
+				Groups:          []string{"team-a", "team-b"},
+				OrgRoles:        map[int64]org.RoleType{1: org.RoleEditor},
+				IsGrafanaAdmin:  boolPtr(false),
+				ClientParams: authn.ClientParams{
+					SyncUser:        true,
+					SyncTeams:       true,
+					FetchSyncedUser: true,
+					SyncPermissions: true,
+					SyncOrgRoles:    true,
+					AllowSignUp:     true,
+					LookUpParams: login.UserLookupParams{
+						Login: strPtr("jdoe"),
+						Email: strPtr("jdoe@example.com"),
+					},
+				},
+			},
+		},
+		{
+			desc:      "should fall back to email as login",
+			info:      defaultInfo(),
+			requestID: "id-1",
+			assertion: &saml.Assertion{
+				NameID:     "name-id",
+				Attributes: map[string][]string{"mail": {"jdoe@example.com"}},
+			},
+			expectedRequestID: []string{"id-1"},
+			expectedIdentity: &authn.Identity{
+				Login:           "jdoe@example.com",
+				Email:           "jdoe@example.com",
+				AuthenticatedBy: login.SAMLAuthModule,
+				AuthID:          "name-id",
+				OrgRoles:        map[int64]org.RoleType{},
+				ClientParams: authn.ClientParams{
+					SyncUser:        true,
+					SyncTeams:       true,
+					FetchSyncedUser: true,
+					SyncPermissions: true,
+					AllowSignUp:     true,
+					LookUpParams: login.UserLookupParams{
+						Login: strPtr("jdoe@example.com"),
+						Email: strPtr("jdoe@example.com"),
+					},
+				},
+			},
+		},
+		{
+			desc:      "should return error when assertion has no login or email",
+			info:      defaultInfo(),
+			requestID: "id-1",
+			assertion: &saml.Assertion{
+				NameID:     "name-id",
+				Attributes: map[string][]string{"displayName": {"John Doe"}},
+			},
+			expectedErr: errSAMLMissingLogin,
+		},
+		{
+			desc: "should reject IdP initiated login with unexpected relay state",
+			info: func() *saml.Info {
+				info := defaultInfo()
+				info.AllowIDPInitiated = true
+				info.RelayState = "expected"
+				return info
+			}(),
+			relayState:  "other",
+			expectedErr: errSAMLInvalidRelayState,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			samlService := &samltest.FakeService{
+				ExpectedInfo:      tt.info,
+				ExpectedAssertion: tt.assertion,
+				ExpectedError:     tt.parseErr,
+			}
+			c := ProvideSAML(setting.NewCfg(), samlService)
+
+			form := url.Values{"SAMLResponse": {"response"}}
+			if tt.relayState != "" {
+				form.Set("RelayState", tt.relayState)
+			}
+			req := httptest.NewRequest(http.MethodPost, saml.ACSPath, strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.requestID != "" {
+				req.AddCookie(&http.Cookie{Name: samlRequestIDCookieName, Value: tt.requestID})
+			}
+
+			identity, err := c.Authenticate(context.Background(), &authn.Request{HTTPRequest: req})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRequestID, samlService.RequestIDs)
+			assert.EqualValues(t, tt.expectedIdentity, identity)
+		})
+	}
+}
+
+func TestSAML_RedirectURL(t *testing.T) {
+	samlService := &samltest.FakeService{
+		ExpectedInfo:      &saml.Info{Enabled: true},
+		ExpectedURL:       "https://idp.example.com/sso?SAMLRequest=request&Signature=signature",
+		ExpectedRequestID: "id-1",
+	}
+	c := ProvideSAML(setting.NewCfg(), samlService)
+
+	redirect, err := c.RedirectURL(context.Background(), &authn.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, samlService.ExpectedURL, redirect.URL)
+	assert.Equal(t, "id-1", redirect.Extra[authn.KeySAMLRequestID])
+
+	samlService.ExpectedInfo = &saml.Info{}
+	_, err = c.RedirectURL(context.Background(), &authn.Request{})
+	assert.ErrorIs(t, err, saml.ErrDisabled)
+}
+
+func TestSAML_Logout(t *testing.T) {
+	type testCase struct {
+		desc             string
+		info             *saml.Info
+		expectedOK       bool
+		expectedRedirect *authn.Redirect
+	}
+
+	tests := []testCase{
+		{
+			desc: "should not redirect when single logout is disabled",
+			info: &saml.Info{Enabled: true},
+		},
+		{
+			desc:             "should redirect to the IdP when single logout is enabled",
+			info:             &saml.Info{Enabled: true, SingleLogout: true},
+			expectedOK:       true,
+			expectedRedirect: &authn.Redirect{URL: "https://idp.example.com/slo?SAMLRequest=request"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			samlService := &samltest.FakeService{ExpectedInfo: tt.info, ExpectedURL: "https://idp.example.com/slo?SAMLRequest=request"}
+			c := ProvideSAML(setting.NewCfg(), samlService)
+
+			redirect, ok := c.Logout(context.Background(), &user.SignedInUser{UserID: 1}, &login.UserAuth{AuthId: "name-id"})
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedRedirect, redirect)
+		})
+	}
+}
+
+func TestSAMLRole(t *testing.T) {
+	info := &saml.Info{
+		RoleValuesNone:   []string{"guest"},
+		RoleValuesEditor: []string{"editor"},
+		RoleValuesAdmin:  []string{"admin"},
+	}
+
+	role, isGrafanaAdmin := samlRole(info, []string{"editor", "admin"})
+	assert.Equal(t, org.RoleAdmin, role)
+	assert.Nil(t, isGrafanaAdmin)
+
+	role, _ = samlRole(info, []string{"guest"})
+	assert.Equal(t, org.RoleNone, role)
+
+	role, _ = samlRole(info, []string{"unknown"})
+	assert.Equal(t, org.RoleViewer, role)
+
+	info.RoleValuesGrafanaAdmin = []string{"superadmin"}
+	role, isGrafanaAdmin = samlRole(info, []string{"superadmin"})
+	assert.Equal(t, org.RoleAdmin, role)
+	assert.Equal(t, boolPtr(true), isGrafanaAdmin)
+}
@@ -3,28 +3,33 @@ package clients
 import (
 	"context"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/twofactor"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 var _ authn.ContextAwareClient = new(Session)
+var _ authn.HookClient = new(Session)
 
-func ProvideSession(cfg *setting.Cfg, sessionService auth.UserTokenService) *Session {
+func ProvideSession(cfg *setting.Cfg, sessionService auth.UserTokenService, twoFactorService twofactor.Service) *Session {
 	return &Session{
-		cfg:            cfg,
-		sessionService: sessionService,
-		log:            log.New(authn.ClientSession),
+		cfg:              cfg,
+		sessionService:   sessionService,
+		twoFactorService: twoFactorService,
+		log:              log.New(authn.ClientSession),
 	}
 }
 
 type Session struct {
-	cfg            *setting.Cfg
-	sessionService auth.UserTokenService
-	log            log.Logger
+	cfg              *setting.Cfg
+	sessionService   auth.UserTokenService
+	twoFactorService twofactor.Service
+	log              log.Logger
 }
 
 func (s *Session) Name() string {
@@ -76,3 +81,26 @@ func (s *Session) Test(ctx context.Context, r *authn.Request) bool {
 func (s *Session) Priority() uint {
 	return 60
 }
+
+// Hook limits the API access of users that still have to set up an enforced
+// second factor to the endpoints needed to do so. Pages are still served so
+// the frontend can show the enrollment.
+func (s *Session) Hook(ctx context.Context, identity *authn.Identity, r *authn.Request) error {
+	if r.HTTPRequest == nil || r.HTTPRequest.URL == nil {
+		return nil
+	}
+
+	path := strings.TrimPrefix(r.HTTPRequest.URL.Path, s.cfg.AppSubURL)
+	if !strings.HasPrefix(path, "/api/") || isTwoFactorEnrollmentPath(path) {
+		return nil
+	}
+
+	userID, status, err := getTwoFactorStatus(ctx, s.twoFactorService, identity)
+	if err != nil {
+		return err
+	}
+	if status != nil && status.Enforced && !status.Enabled {
+		return twofactor.ErrEnrollmentRequired.Errorf("user %d has to set up two-factor authentication", userID)
+	}
+	return nil
+}
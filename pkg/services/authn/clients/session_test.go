@@ -13,6 +13,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -26,7 +28,7 @@ func TestSession_Test(t *testing.T) {
 	cfg := setting.NewCfg()
 	cfg.LoginCookieName = ""
 	cfg.LoginMaxLifetime = 20 * time.Second
-	s := ProvideSession(cfg, &authtest.FakeUserAuthTokenService{})
+	s := ProvideSession(cfg, &authtest.FakeUserAuthTokenService{}, twofactortest.NewFakeService())
 
 	disabled := s.Test(context.Background(), &authn.Request{HTTPRequest: validHTTPReq})
 	assert.False(t, disabled)
@@ -137,7 +139,7 @@ func TestSession_Authenticate(t *testing.T) {
 			cfg.LoginCookieName = cookieName
 			cfg.TokenRotationIntervalMinutes = 10
 			cfg.LoginMaxLifetime = 20 * time.Second
-			s := ProvideSession(cfg, tt.fields.sessionService)
+			s := ProvideSession(cfg, tt.fields.sessionService, twofactortest.NewFakeService())
 
 			got, err := s.Authenticate(context.Background(), tt.args.r)
 			require.True(t, (err != nil) == tt.wantErr, err)
@@ -149,3 +151,51 @@ func TestSession_Authenticate(t *testing.T) {
 		})
 	}
 }
+
+func TestSession_Hook(t *testing.T) {
+	type testCase struct {
+		desc        string
+		path        string
+		status      *twofactor.Status
+		expectedErr error
+	}
+
+	tests := []testCase{
+		{
+			desc:   "should allow api requests of users with two-factor authentication",
+			path:   "/api/dashboards/uid/abc",
+			status: &twofactor.Status{Enforced: true, Enabled: true},
+		},
+		{
+			desc:        "should reject api requests of users that have to enroll",
+			path:        "/api/dashboards/uid/abc",
+			status:      &twofactor.Status{Enforced: true},
+			expectedErr: twofactor.ErrEnrollmentRequired,
+		},
+		{
+			desc:   "should allow enrollment requests of users that have to enroll",
+			path:   "/api/user/two-factor/enroll",
+			status: &twofactor.Status{Enforced: true},
+		},
+		{
+			desc:   "should allow pages for users that have to enroll",
+			path:   "/d/abc",
+			status: &twofactor.Status{Enforced: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := ProvideSession(setting.NewCfg(), &authtest.FakeUserAuthTokenService{}, &twofactortest.FakeService{ExpectedStatus: tt.status})
+			req, err := http.NewRequest(http.MethodGet, tt.path, nil)
+			require.NoError(t, err)
+
+			err = s.Hook(context.Background(), &authn.Identity{ID: "user:1"}, &authn.Request{HTTPRequest: req})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"context"
+	"strings"
+
+	authidentity "github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+)
+
+// twoFactorEnrollmentPaths are the API endpoints a session can use while the
+// user still has to set up an enforced second factor.
+var twoFactorEnrollmentPaths = []string{
+	"/api/user/two-factor",
+	"/api/user/auth-tokens/rotate",
+	"/api/login/ping",
+	"/api/frontend/settings",
+}
+
+// getTwoFactorStatus returns the two-factor status of the user behind
+// identity, or nil when the identity is not a user.
+func getTwoFactorStatus(ctx context.Context, service twofactor.Service, identity *authn.Identity) (int64, *twofactor.Status, error) {
+	namespace, identifier := identity.GetNamespacedID()
+	if namespace != authn.NamespaceUser {
+		return 0, nil, nil
+	}
+
+	userID, err := authidentity.IntIdentifier(namespace, identifier)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	status, err := service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: userID})
+	if err != nil {
+		return 0, nil, err
+	}
+	return userID, status, nil
+}
+
+func isTwoFactorEnrollmentPath(path string) bool {
+	if path == "/api/user" {
+		return true
+	}
+	for _, p := range twoFactorEnrollmentPaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
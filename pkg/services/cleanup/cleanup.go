@@ -17,9 +17,11 @@ import (
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
@@ -29,7 +31,8 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	dashboardService dashboards.DashboardService, publicDashboardService publicdashboards.Service) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -43,6 +46,8 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		dashboardService:          dashboardService,
+		publicDashboardService:    publicDashboardService,
 	}
 	return s
 }
@@ -60,6 +65,8 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	dashboardService          dashboards.DashboardService
+	publicDashboardService    publicdashboards.Service
 }
 
 type cleanUpJob struct {
@@ -97,12 +104,14 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"clean up temporary files", srv.cleanUpTmpFiles},
 		{"delete expired snapshots", srv.deleteExpiredSnapshots},
 		{"delete expired dashboard versions", srv.deleteExpiredDashboardVersions},
+		{"purge deleted dashboards", srv.purgeDeletedDashboards},
 		{"delete expired images", srv.deleteExpiredImages},
 		{"cleanup old annotations", srv.cleanUpOldAnnotations},
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
 		{"expire old email verifications", srv.expireOldVerifications},
+		{"delete old public dashboard access logs", srv.deleteOldPublicDashboardAccessLogs},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -212,6 +221,18 @@ func (srv *CleanUpService) deleteExpiredDashboardVersions(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) purgeDeletedDashboards(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	cmd := dashboards.PurgeDeletedDashboardsCommand{
+		OlderThan: time.Now().Add(-srv.Cfg.DeletedDashboardsRetention),
+	}
+	if err := srv.dashboardService.PurgeDeletedDashboards(ctx, &cmd); err != nil {
+		logger.Error("Failed to purge deleted dashboards", "error", err.Error())
+	} else {
+		logger.Debug("Purged deleted dashboards", "rows affected", cmd.DeletedRows)
+	}
+}
+
 func (srv *CleanUpService) deleteExpiredImages(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	if !srv.Cfg.UnifiedAlerting.IsEnabled() {
@@ -266,6 +287,19 @@ func (srv *CleanUpService) deleteStaleShortURLs(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) deleteOldPublicDashboardAccessLogs(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if srv.Cfg.PublicDashboardsAccessLogRetention <= 0 {
+		return
+	}
+	olderThan := time.Now().Add(-srv.Cfg.PublicDashboardsAccessLogRetention)
+	if rowsAffected, err := srv.publicDashboardService.DeleteAccessLogBefore(ctx, olderThan); err != nil {
+		logger.Error("Problem deleting old public dashboard access logs", "error", err.Error())
+	} else {
+		logger.Debug("Deleted old public dashboard access logs", "rows affected", rowsAffected)
+	}
+}
+
 func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	// Delete query history from 14+ days ago with exception of starred queries
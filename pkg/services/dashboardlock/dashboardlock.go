@@ -0,0 +1,13 @@
+package dashboardlock
+
+import (
+	"context"
+)
+
+// Service manages advisory edit locks of dashboards. Locks are not enforced
+// on save, they only tell other editors who is currently editing a dashboard.
+type Service interface {
+	GetLock(context.Context, *GetLockQuery) (*Lock, error)
+	AcquireLock(context.Context, *AcquireLockCommand) (*Lock, error)
+	ReleaseLock(context.Context, *ReleaseLockCommand) error
+}
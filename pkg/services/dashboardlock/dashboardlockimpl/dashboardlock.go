@@ -0,0 +1,56 @@
+package dashboardlockimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
+)
+
+type Service struct {
+	store store
+	now   func() time.Time
+}
+
+func ProvideService(db db.DB) dashboardlock.Service {
+	return &Service{
+		store: &sqlStore{
+			db: db,
+		},
+		now: time.Now,
+	}
+}
+
+// GetLock returns the active lock of the dashboard. An expired lock is
+// reported as not found.
+func (s *Service) GetLock(ctx context.Context, query *dashboardlock.GetLockQuery) (*dashboardlock.Lock, error) {
+	lock, err := s.store.Get(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if lock.IsExpired(s.now()) {
+		return nil, dashboardlock.ErrLockNotFound.Errorf("lock of dashboard %s expired", query.DashboardUID)
+	}
+	return lock, nil
+}
+
+// AcquireLock acquires the lock, renews a lock held by the user or takes over
+// an expired one. When the dashboard is locked by another user the current
+// lock is returned together with dashboardlock.ErrLockedByAnotherUser.
+func (s *Service) AcquireLock(ctx context.Context, cmd *dashboardlock.AcquireLockCommand) (*dashboardlock.Lock, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+	if cmd.Lease == 0 {
+		cmd.Lease = dashboardlock.DefaultLease
+	}
+	return s.store.Acquire(ctx, cmd, s.now())
+}
+
+func (s *Service) ReleaseLock(ctx context.Context, cmd *dashboardlock.ReleaseLockCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+	return s.store.Release(ctx, cmd, s.now())
+}
@@ -0,0 +1,101 @@
+package dashboardlockimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationDashboardLock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	setup := func() *Service {
+		ss := db.InitTestDB(t)
+		return &Service{
+			store: &sqlStore{db: ss},
+			now:   func() time.Time { return now },
+		}
+	}
+
+	t.Run("lock can be acquired, renewed and released by the owner", func(t *testing.T) {
+		s := setup()
+		lock, err := s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 1})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), lock.UserID)
+		require.Equal(t, now.Add(dashboardlock.DefaultLease), lock.Expires)
+
+		now = now.Add(time.Minute)
+		lock, err = s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 1, Lease: 10 * time.Minute})
+		require.NoError(t, err)
+		require.Equal(t, now.Add(10*time.Minute), lock.Expires)
+
+		current, err := s.GetLock(ctx, &dashboardlock.GetLockQuery{OrgID: 1, DashboardUID: "dash"})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), current.UserID)
+		require.True(t, current.Acquired.Equal(now.Add(-time.Minute)))
+
+		err = s.ReleaseLock(ctx, &dashboardlock.ReleaseLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 1})
+		require.NoError(t, err)
+
+		_, err = s.GetLock(ctx, &dashboardlock.GetLockQuery{OrgID: 1, DashboardUID: "dash"})
+		require.ErrorIs(t, err, dashboardlock.ErrLockNotFound)
+	})
+
+	t.Run("lock held by another user can only be taken by force", func(t *testing.T) {
+		s := setup()
+		_, err := s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 1})
+		require.NoError(t, err)
+
+		lock, err := s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 2})
+		require.ErrorIs(t, err, dashboardlock.ErrLockedByAnotherUser)
+		require.Equal(t, int64(1), lock.UserID)
+
+		err = s.ReleaseLock(ctx, &dashboardlock.ReleaseLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 2})
+		require.ErrorIs(t, err, dashboardlock.ErrLockNotOwned)
+
+		lock, err = s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 2, Force: true})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), lock.UserID)
+
+		// Another org is not affected.
+		_, err = s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 2, DashboardUID: "dash", UserID: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("expired lock is not returned and can be taken over", func(t *testing.T) {
+		s := setup()
+		_, err := s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 1, Lease: time.Minute})
+		require.NoError(t, err)
+
+		now = now.Add(2 * time.Minute)
+		_, err = s.GetLock(ctx, &dashboardlock.GetLockQuery{OrgID: 1, DashboardUID: "dash"})
+		require.ErrorIs(t, err, dashboardlock.ErrLockNotFound)
+
+		lock, err := s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 2})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), lock.UserID)
+		require.Equal(t, now, lock.Acquired)
+	})
+
+	t.Run("invalid commands are rejected", func(t *testing.T) {
+		s := setup()
+		_, err := s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, UserID: 1})
+		require.ErrorIs(t, err, dashboardlock.ErrCommandValidationFailed)
+		_, err = s.AcquireLock(ctx, &dashboardlock.AcquireLockCommand{OrgID: 1, DashboardUID: "dash", UserID: 1, Lease: 2 * dashboardlock.MaxLease})
+		require.ErrorIs(t, err, dashboardlock.ErrCommandValidationFailed)
+	})
+}
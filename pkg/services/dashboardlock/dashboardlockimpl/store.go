@@ -0,0 +1,14 @@
+package dashboardlockimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
+)
+
+type store interface {
+	Get(context.Context, *dashboardlock.GetLockQuery) (*dashboardlock.Lock, error)
+	Acquire(context.Context, *dashboardlock.AcquireLockCommand, time.Time) (*dashboardlock.Lock, error)
+	Release(context.Context, *dashboardlock.ReleaseLockCommand, time.Time) error
+}
@@ -0,0 +1,92 @@
+package dashboardlockimpl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
+)
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (s *sqlStore) Get(ctx context.Context, query *dashboardlock.GetLockQuery) (*dashboardlock.Lock, error) {
+	var lock *dashboardlock.Lock
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		lock, err = getLock(sess, query.OrgID, query.DashboardUID)
+		return err
+	})
+	return lock, err
+}
+
+func (s *sqlStore) Acquire(ctx context.Context, cmd *dashboardlock.AcquireLockCommand, now time.Time) (*dashboardlock.Lock, error) {
+	var lock *dashboardlock.Lock
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing, err := getLock(sess, cmd.OrgID, cmd.DashboardUID)
+		if err != nil && !errors.Is(err, dashboardlock.ErrLockNotFound) {
+			return err
+		}
+
+		if existing != nil && !existing.IsExpired(now) && existing.UserID != cmd.UserID && !cmd.Force {
+			lock = existing
+			return dashboardlock.ErrLockedByAnotherUser.Errorf("dashboard %s is locked by user %d", cmd.DashboardUID, existing.UserID)
+		}
+
+		lock = &dashboardlock.Lock{
+			OrgID:        cmd.OrgID,
+			DashboardUID: cmd.DashboardUID,
+			UserID:       cmd.UserID,
+			Acquired:     now,
+			Expires:      now.Add(cmd.Lease),
+		}
+
+		if existing == nil {
+			_, err := sess.Insert(lock)
+			if s.db.GetDialect().IsUniqueConstraintViolation(err) {
+				// Someone acquired the lock concurrently.
+				return dashboardlock.ErrLockedByAnotherUser.Errorf("dashboard %s is locked by another user", cmd.DashboardUID)
+			}
+			return err
+		}
+
+		// Renewing the lease keeps the time the lock was acquired.
+		if !existing.IsExpired(now) && existing.UserID == cmd.UserID {
+			lock.Acquired = existing.Acquired
+		}
+		lock.ID = existing.ID
+		_, err = sess.ID(existing.ID).Cols("user_id", "acquired", "expires").Update(lock)
+		return err
+	})
+	return lock, err
+}
+
+func (s *sqlStore) Release(ctx context.Context, cmd *dashboardlock.ReleaseLockCommand, now time.Time) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing, err := getLock(sess, cmd.OrgID, cmd.DashboardUID)
+		if err != nil {
+			return err
+		}
+		if !existing.IsExpired(now) && existing.UserID != cmd.UserID && !cmd.Force {
+			return dashboardlock.ErrLockNotOwned.Errorf("dashboard %s is locked by user %d", cmd.DashboardUID, existing.UserID)
+		}
+		_, err = sess.Exec("DELETE FROM dashboard_edit_lock WHERE id = ?", existing.ID)
+		return err
+	})
+}
+
+// getLock returns the lock of the dashboard, including an expired one.
+func getLock(sess *db.Session, orgID int64, dashboardUID string) (*dashboardlock.Lock, error) {
+	lock := &dashboardlock.Lock{}
+	has, err := sess.Where("org_id = ? AND dashboard_uid = ?", orgID, dashboardUID).Get(lock)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, dashboardlock.ErrLockNotFound.Errorf("dashboard %s is not locked", dashboardUID)
+	}
+	return lock, nil
+}
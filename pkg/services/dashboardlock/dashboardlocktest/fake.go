@@ -0,0 +1,33 @@
+package dashboardlocktest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
+)
+
+type FakeDashboardLockService struct {
+	ExpectedLock  *dashboardlock.Lock
+	ExpectedError error
+
+	AcquireLockCmd *dashboardlock.AcquireLockCommand
+	ReleaseLockCmd *dashboardlock.ReleaseLockCommand
+}
+
+func NewDashboardLockServiceFake() *FakeDashboardLockService {
+	return &FakeDashboardLockService{}
+}
+
+func (f *FakeDashboardLockService) GetLock(ctx context.Context, query *dashboardlock.GetLockQuery) (*dashboardlock.Lock, error) {
+	return f.ExpectedLock, f.ExpectedError
+}
+
+func (f *FakeDashboardLockService) AcquireLock(ctx context.Context, cmd *dashboardlock.AcquireLockCommand) (*dashboardlock.Lock, error) {
+	f.AcquireLockCmd = cmd
+	return f.ExpectedLock, f.ExpectedError
+}
+
+func (f *FakeDashboardLockService) ReleaseLock(ctx context.Context, cmd *dashboardlock.ReleaseLockCommand) error {
+	f.ReleaseLockCmd = cmd
+	return f.ExpectedError
+}
@@ -0,0 +1,86 @@
+package dashboardlock
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrLockNotFound            = errutil.NotFound("dashboardlock.not-found", errutil.WithPublicMessage("Dashboard is not locked"))
+	ErrLockedByAnotherUser     = errutil.Conflict("dashboardlock.locked", errutil.WithPublicMessage("Dashboard is being edited by another user"))
+	ErrLockNotOwned            = errutil.Forbidden("dashboardlock.not-owner", errutil.WithPublicMessage("Dashboard edit lock is held by another user"))
+	ErrCommandValidationFailed = errutil.BadRequest("dashboardlock.validation-failed", errutil.WithPublicMessage("Command missing required fields"))
+)
+
+const (
+	// DefaultLease is used when the lease of the lock is not set.
+	DefaultLease = 5 * time.Minute
+	// MaxLease is the longest lease a lock can be acquired for.
+	MaxLease = time.Hour
+)
+
+type Lock struct {
+	ID           int64     `xorm:"pk autoincr 'id'" json:"-"`
+	OrgID        int64     `xorm:"org_id" json:"-"`
+	DashboardUID string    `xorm:"dashboard_uid" json:"dashboardUid"`
+	UserID       int64     `xorm:"user_id" json:"userId"`
+	Acquired     time.Time `json:"acquired"`
+	Expires      time.Time `json:"expires"`
+}
+
+func (l Lock) TableName() string {
+	return "dashboard_edit_lock"
+}
+
+// IsExpired returns true if the lease of the lock ended before t.
+func (l *Lock) IsExpired(t time.Time) bool {
+	return !l.Expires.After(t)
+}
+
+// ----------------------
+// COMMANDS
+
+// AcquireLockCommand acquires the lock or renews the lease of a lock already
+// held by the user. Force takes over a lock held by another user.
+type AcquireLockCommand struct {
+	OrgID        int64
+	DashboardUID string
+	UserID       int64
+	Lease        time.Duration
+	Force        bool
+}
+
+func (cmd *AcquireLockCommand) Validate() error {
+	if cmd.OrgID == 0 || cmd.DashboardUID == "" || cmd.UserID == 0 {
+		return ErrCommandValidationFailed.Errorf("org, dashboard and user are required")
+	}
+	if cmd.Lease < 0 || cmd.Lease > MaxLease {
+		return ErrCommandValidationFailed.Errorf("lease must be between 0 and %s", MaxLease)
+	}
+	return nil
+}
+
+// ReleaseLockCommand releases the lock held by the user. Force releases a
+// lock held by another user.
+type ReleaseLockCommand struct {
+	OrgID        int64
+	DashboardUID string
+	UserID       int64
+	Force        bool
+}
+
+func (cmd *ReleaseLockCommand) Validate() error {
+	if cmd.OrgID == 0 || cmd.DashboardUID == "" {
+		return ErrCommandValidationFailed.Errorf("org and dashboard are required")
+	}
+	return nil
+}
+
+// ---------------------
+// QUERIES
+
+type GetLockQuery struct {
+	OrgID        int64
+	DashboardUID string
+}
@@ -29,6 +29,10 @@ type DashboardService interface {
 	SearchDashboards(ctx context.Context, query *FindPersistedDashboardsQuery) (model.HitList, error)
 	CountInFolders(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester) (int64, error)
 	GetDashboardsSharedWithUser(ctx context.Context, user identity.Requester) ([]*Dashboard, error)
+	GetDeletedDashboard(ctx context.Context, query *GetDeletedDashboardQuery) (*DeletedDashboard, error)
+	ListDeletedDashboards(ctx context.Context, query *ListDeletedDashboardsQuery) ([]*DeletedDashboard, error)
+	RestoreDeletedDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) (*Dashboard, error)
+	PurgeDeletedDashboards(ctx context.Context, cmd *PurgeDeletedDashboardsCommand) error
 }
 
 // PluginService is a service for operating on plugin dashboards.
@@ -79,4 +83,15 @@ type Store interface {
 	// the given parent folder ID.
 	CountDashboardsInFolders(ctx context.Context, request *CountDashboardsInFolderRequest) (int64, error)
 	DeleteDashboardsInFolders(ctx context.Context, request *DeleteDashboardsInFolderRequest) error
+
+	// GetDeletedDashboard returns a deleted dashboard or folder by UID.
+	GetDeletedDashboard(ctx context.Context, query *GetDeletedDashboardQuery) (*DeletedDashboard, error)
+	// ListDeletedDashboards returns dashboards and folders that were deleted
+	// and can still be restored.
+	ListDeletedDashboards(ctx context.Context, query *ListDeletedDashboardsQuery) ([]*DeletedDashboard, error)
+	// RestoreDeletedDashboard restores a deleted dashboard, or a deleted
+	// folder together with everything that was deleted with it.
+	RestoreDeletedDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) (*Dashboard, error)
+	// PurgeDeletedDashboards permanently removes deleted dashboards.
+	PurgeDeletedDashboards(ctx context.Context, cmd *PurgeDeletedDashboardsCommand) error
 }
@@ -236,6 +236,32 @@ func (_m *FakeDashboardService) GetDashboardsSharedWithUser(ctx context.Context,
 	return r0, r1
 }
 
+// GetDeletedDashboard provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetDeletedDashboard(ctx context.Context, query *GetDeletedDashboardQuery) (*DeletedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *DeletedDashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDeletedDashboardQuery) (*DeletedDashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDeletedDashboardQuery) *DeletedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DeletedDashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDeletedDashboardQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImportDashboard provides a mock function with given fields: ctx, dto
 func (_m *FakeDashboardService) ImportDashboard(ctx context.Context, dto *SaveDashboardDTO) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto)
@@ -262,6 +288,72 @@ func (_m *FakeDashboardService) ImportDashboard(ctx context.Context, dto *SaveDa
 	return r0, r1
 }
 
+// ListDeletedDashboards provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) ListDeletedDashboards(ctx context.Context, query *ListDeletedDashboardsQuery) ([]*DeletedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*DeletedDashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDeletedDashboardsQuery) ([]*DeletedDashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDeletedDashboardsQuery) []*DeletedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DeletedDashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ListDeletedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeletedDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) PurgeDeletedDashboards(ctx context.Context, cmd *PurgeDeletedDashboardsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *PurgeDeletedDashboardsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreDeletedDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) RestoreDeletedDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) (*Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) (*Dashboard, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) *Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDeletedDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveDashboard provides a mock function with given fields: ctx, dto, allowUiUpdate
 func (_m *FakeDashboardService) SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*Dashboard, error) {
 	ret := _m.Called(ctx, dto, allowUiUpdate)
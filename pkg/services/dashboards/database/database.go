@@ -653,11 +653,11 @@ func (d *dashboardStore) GetDashboardsByPluginID(ctx context.Context, query *das
 
 func (d *dashboardStore) DeleteDashboard(ctx context.Context, cmd *dashboards.DeleteDashboardCommand) error {
 	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
-		return d.deleteDashboard(cmd, sess, d.emitEntityEvent())
+		return d.deleteDashboard(ctx, cmd, sess, d.emitEntityEvent())
 	})
 }
 
-func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand, sess *db.Session, emitEntityEvent bool) error {
+func (d *dashboardStore) deleteDashboard(ctx context.Context, cmd *dashboards.DeleteDashboardCommand, sess *db.Session, emitEntityEvent bool) error {
 	dashboard := dashboards.Dashboard{OrgID: cmd.OrgID}
 	if cmd.UID != "" {
 		dashboard.UID = cmd.UID
@@ -671,6 +671,13 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 		return dashboards.ErrDashboardNotFound
 	}
 
+	softDelete := d.softDeleteEnabled()
+	if softDelete {
+		if err := d.archiveDashboard(ctx, sess, &dashboard); err != nil {
+			return err
+		}
+	}
+
 	deletes := []string{
 		"DELETE FROM dashboard_tag WHERE dashboard_id = ? ",
 		"DELETE FROM star WHERE dashboard_id = ? ",
@@ -684,7 +691,7 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 	if dashboard.IsFolder {
 		deletes = append(deletes, "DELETE FROM dashboard WHERE folder_id = ?")
 
-		if err := d.deleteChildrenDashboardAssociations(sess, &dashboard); err != nil {
+		if err := d.deleteChildrenDashboardAssociations(ctx, sess, &dashboard); err != nil {
 			return err
 		}
 
@@ -703,9 +710,12 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 		return err
 	}
 
-	_, err = sess.Exec("DELETE FROM annotation WHERE dashboard_id = ? AND org_id = ?", dashboard.ID, dashboard.OrgID)
-	if err != nil {
-		return err
+	// annotations of soft deleted dashboards are kept until the dashboard is purged
+	if !softDelete {
+		_, err = sess.Exec("DELETE FROM annotation WHERE dashboard_id = ? AND org_id = ?", dashboard.ID, dashboard.OrgID)
+		if err != nil {
+			return err
+		}
 	}
 
 	for _, sql := range deletes {
@@ -715,6 +725,10 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 		}
 	}
 
+	if _, err := sess.Exec("DELETE FROM dashboard_edit_lock WHERE org_id = ? AND dashboard_uid = ?", dashboard.OrgID, dashboard.UID); err != nil {
+		return err
+	}
+
 	if emitEntityEvent {
 		_, err := sess.Insert(createEntityEvent(&dashboard, store.EntityEventTypeDelete))
 		if err != nil {
@@ -742,7 +756,7 @@ func (d *dashboardStore) deleteResourcePermissions(sess *db.Session, orgID int64
 	return err
 }
 
-func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, dashboard *dashboards.Dashboard) error {
+func (d *dashboardStore) deleteChildrenDashboardAssociations(ctx context.Context, sess *db.Session, dashboard *dashboards.Dashboard) error {
 	var dashIds []struct {
 		Id  int64
 		Uid string
@@ -752,8 +766,19 @@ func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, d
 		return err
 	}
 
+	softDelete := d.softDeleteEnabled()
 	if len(dashIds) > 0 {
 		for _, dash := range dashIds {
+			if softDelete {
+				child := dashboards.Dashboard{ID: dash.Id}
+				if _, err := sess.Get(&child); err != nil {
+					return err
+				}
+				if err := d.archiveDashboard(ctx, sess, &child); err != nil {
+					return err
+				}
+			}
+
 			if err := d.deleteAlertDefinition(dash.Id, sess); err != nil {
 				return err
 			}
@@ -772,9 +797,11 @@ func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, d
 			"DELETE FROM dashboard_acl WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 		}
 
-		_, err = sess.Exec("DELETE FROM annotation WHERE org_id = ? AND dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)", dashboard.OrgID, dashboard.OrgID, dashboard.ID)
-		if err != nil {
-			return err
+		if !softDelete {
+			_, err = sess.Exec("DELETE FROM annotation WHERE org_id = ? AND dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)", dashboard.OrgID, dashboard.OrgID, dashboard.ID)
+			if err != nil {
+				return err
+			}
 		}
 
 		for _, sql := range childrenDeletes {
@@ -783,6 +810,11 @@ func (d *dashboardStore) deleteChildrenDashboardAssociations(sess *db.Session, d
 				return err
 			}
 		}
+
+		_, err = sess.Exec("DELETE FROM dashboard_edit_lock WHERE org_id = ? AND dashboard_uid IN (SELECT uid FROM dashboard WHERE org_id = ? AND folder_id = ?)", dashboard.OrgID, dashboard.OrgID, dashboard.ID)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -1056,7 +1088,7 @@ func (d *dashboardStore) DeleteDashboardsInFolders(
 				return dashboards.ErrFolderNotFound
 			}
 
-			if err := d.deleteChildrenDashboardAssociations(sess, &dashboard); err != nil {
+			if err := d.deleteChildrenDashboardAssociations(ctx, sess, &dashboard); err != nil {
 				return err
 			}
 
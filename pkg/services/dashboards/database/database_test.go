@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -223,6 +224,35 @@ func TestIntegrationDashboardDataAccess(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Should delete the edit locks of deleted dashboards", func(t *testing.T) {
+		setup()
+		lockFolder := insertTestDashboard(t, dashboardStore, "lock folder", 1, 0, "", true)
+		child := insertTestDashboard(t, dashboardStore, "lock child", 1, lockFolder.ID, lockFolder.UID, false)
+		dash := insertTestDashboard(t, dashboardStore, "lock dash", 1, 0, "", false)
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			for _, uid := range []string{child.UID, dash.UID, savedDash2.UID} {
+				if _, err := sess.Insert(&dashboardlock.Lock{OrgID: 1, DashboardUID: uid, UserID: 1, Acquired: time.Now(), Expires: time.Now().Add(time.Minute)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: dash.ID, OrgID: 1})
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: lockFolder.ID, OrgID: 1})
+		require.NoError(t, err)
+
+		var locks []*dashboardlock.Lock
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			return sess.Find(&locks)
+		})
+		require.NoError(t, err)
+		require.Len(t, locks, 1)
+		require.Equal(t, savedDash2.UID, locks[0].DashboardUID)
+	})
+
 	t.Run("Should be able to create dashboard", func(t *testing.T) {
 		setup()
 		cmd := dashboards.SaveDashboardCommand{
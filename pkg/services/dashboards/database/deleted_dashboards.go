@@ -0,0 +1,473 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/store"
+)
+
+// deletedDashboardData holds the rows removed together with a dashboard,
+// which are put back when the dashboard is restored.
+type deletedDashboardData struct {
+	Dashboard          *dashboards.Dashboard
+	Versions           []*dashver.DashboardVersion
+	ACL                []*dashboards.DashboardACL
+	Permissions        []deletedPermission
+	LibraryConnections []*model.LibraryElementConnection
+	AlertRules         []*ngmodels.AlertRule
+	AlertRuleVersions  []*ngmodels.AlertRuleVersion
+}
+
+// deletedPermission is a managed permission on the dashboard or folder scope.
+type deletedPermission struct {
+	RoleID     int64
+	Action     string
+	Scope      string
+	Kind       string
+	Attribute  string
+	Identifier string
+}
+
+func (d *dashboardStore) softDeleteEnabled() bool {
+	return d.cfg != nil && d.cfg.DeletedDashboardsRetention > 0
+}
+
+// archiveDashboard stores the dashboard and the rows depending on it in the
+// deleted_dashboard table, before they are deleted.
+func (d *dashboardStore) archiveDashboard(ctx context.Context, sess *db.Session, dashboard *dashboards.Dashboard) error {
+	data := deletedDashboardData{Dashboard: dashboard}
+	if err := sess.Where("dashboard_id = ?", dashboard.ID).Asc("version").Find(&data.Versions); err != nil {
+		return err
+	}
+	if err := sess.Where("dashboard_id = ?", dashboard.ID).Find(&data.ACL); err != nil {
+		return err
+	}
+
+	scope := ac.GetResourceScopeUID("dashboards", dashboard.UID)
+	if dashboard.IsFolder {
+		scope = dashboards.ScopeFoldersProvider.GetResourceScopeUID(dashboard.UID)
+	}
+	var permissions []ac.Permission
+	err := sess.SQL("SELECT permission.* FROM permission INNER JOIN role ON permission.role_id = role.id WHERE permission.scope = ? AND role.org_id = ?", scope, dashboard.OrgID).Find(&permissions)
+	if err != nil {
+		return err
+	}
+	for _, p := range permissions {
+		data.Permissions = append(data.Permissions, deletedPermission{
+			RoleID:     p.RoleID,
+			Action:     p.Action,
+			Scope:      p.Scope,
+			Kind:       p.Kind,
+			Attribute:  p.Attribute,
+			Identifier: p.Identifier,
+		})
+	}
+
+	if dashboard.IsFolder {
+		if err := archiveAlertRules(sess, dashboard, &data); err != nil {
+			return err
+		}
+	} else {
+		if err := sess.Where("kind = ? AND connection_id = ?", model.Dashboard, dashboard.ID).Find(&data.LibraryConnections); err != nil {
+			return err
+		}
+		if _, err := sess.Exec("DELETE FROM library_element_connection WHERE kind = ? AND connection_id = ?", model.Dashboard, dashboard.ID); err != nil {
+			return err
+		}
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// a dashboard with the same uid may have been deleted before, its
+	// children are kept as they might have been archived by this delete
+	var existing dashboards.DeletedDashboard
+	has, err := sess.Where("org_id = ? AND uid = ?", dashboard.OrgID, dashboard.UID).Omit("data").Get(&existing)
+	if err != nil {
+		return err
+	}
+	if has {
+		if _, err := d.purgeDeletedDashboard(sess, &existing, false); err != nil {
+			return err
+		}
+	}
+
+	entry := &dashboards.DeletedDashboard{
+		OrgID:       dashboard.OrgID,
+		DashboardID: dashboard.ID,
+		UID:         dashboard.UID,
+		Title:       dashboard.Title,
+		IsFolder:    dashboard.IsFolder,
+		FolderUID:   dashboard.FolderUID,
+		Data:        string(b),
+		Deleted:     time.Now(),
+	}
+	if usr, err := appcontext.User(ctx); err == nil {
+		entry.DeletedBy = usr.UserID
+	}
+	_, err = sess.Insert(entry)
+	return err
+}
+
+// archiveAlertRules keeps the alert rules of a folder, they are deleted
+// together with the folder by the alerting store.
+func archiveAlertRules(sess *db.Session, folder *dashboards.Dashboard, data *deletedDashboardData) error {
+	if err := sess.Where("org_id = ? AND namespace_uid = ?", folder.OrgID, folder.UID).Find(&data.AlertRules); err != nil {
+		return err
+	}
+	if len(data.AlertRules) == 0 {
+		return nil
+	}
+	uids := make([]string, 0, len(data.AlertRules))
+	for _, rule := range data.AlertRules {
+		uids = append(uids, rule.UID)
+	}
+	return sess.Where("rule_org_id = ?", folder.OrgID).In("rule_uid", uids).Asc("id").Find(&data.AlertRuleVersions)
+}
+
+func (d *dashboardStore) GetDeletedDashboard(ctx context.Context, query *dashboards.GetDeletedDashboardQuery) (*dashboards.DeletedDashboard, error) {
+	var result dashboards.DeletedDashboard
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", query.OrgID, query.UID).Omit("data").Get(&result)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashboards.ErrDeletedDashboardNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (d *dashboardStore) ListDeletedDashboards(ctx context.Context, query *dashboards.ListDeletedDashboardsQuery) ([]*dashboards.DeletedDashboard, error) {
+	result := make([]*dashboards.DeletedDashboard, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID).Omit("data")
+		if query.IsFolder != nil {
+			q = q.And("is_folder = ?", *query.IsFolder)
+		}
+		if query.FolderUID != nil {
+			if *query.FolderUID == "" {
+				q = q.And("(folder_uid IS NULL OR folder_uid = '')")
+			} else {
+				q = q.And("folder_uid = ?", *query.FolderUID)
+			}
+		}
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Desc("deleted").Find(&result)
+	})
+	return result, err
+}
+
+func (d *dashboardStore) RestoreDeletedDashboard(ctx context.Context, cmd *dashboards.RestoreDeletedDashboardCommand) (*dashboards.Dashboard, error) {
+	var restored *dashboards.Dashboard
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var entry dashboards.DeletedDashboard
+		has, err := sess.Where("org_id = ? AND uid = ?", cmd.OrgID, cmd.UID).Get(&entry)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashboards.ErrDeletedDashboardNotFound
+		}
+
+		parentUID := entry.FolderUID
+		if cmd.FolderUID != nil {
+			parentUID = *cmd.FolderUID
+		}
+		var parent *dashboards.Dashboard
+		if parentUID != "" {
+			parent = &dashboards.Dashboard{}
+			has, err := sess.Where("org_id = ? AND uid = ? AND is_folder = ?", cmd.OrgID, parentUID, true).Get(parent)
+			if err != nil {
+				return err
+			}
+			if !has {
+				return dashboards.ErrDeletedDashboardParentNotFound
+			}
+		}
+
+		restored, err = d.restoreDeletedDashboard(sess, &entry, parent)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+func (d *dashboardStore) restoreDeletedDashboard(sess *db.Session, entry *dashboards.DeletedDashboard, parent *dashboards.Dashboard) (*dashboards.Dashboard, error) {
+	var data deletedDashboardData
+	if err := json.Unmarshal([]byte(entry.Data), &data); err != nil {
+		return nil, fmt.Errorf("failed to read deleted dashboard %s: %w", entry.UID, err)
+	}
+	dash := data.Dashboard
+	if dash == nil || dash.Data == nil {
+		return nil, dashboards.ErrDashboardCorrupt
+	}
+
+	if exists, err := sess.Where("org_id = ? AND uid = ?", entry.OrgID, entry.UID).Exist(&dashboards.Dashboard{}); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, dashboards.ErrDashboardWithSameUIDExists
+	}
+
+	dash.FolderID = 0 // nolint:staticcheck
+	dash.FolderUID = ""
+	if parent != nil {
+		dash.FolderID = parent.ID // nolint:staticcheck
+		dash.FolderUID = parent.UID
+	}
+
+	// nolint:staticcheck
+	if exists, err := sess.Where("org_id = ? AND folder_id = ? AND title = ?", dash.OrgID, dash.FolderID, dash.Title).Exist(&dashboards.Dashboard{}); err != nil {
+		return nil, err
+	} else if exists {
+		if dash.IsFolder {
+			return nil, dashboards.ErrFolderSameNameExists
+		}
+		return nil, dashboards.ErrDashboardWithSameNameInFolderExists
+	}
+
+	// ids are not reused, but the dashboard gets a new one if it happens
+	if exists, err := sess.Where("id = ?", dash.ID).Exist(&dashboards.Dashboard{}); err != nil {
+		return nil, err
+	} else if exists {
+		dash.ID = 0
+	}
+	if _, err := sess.Nullable("folder_uid").Insert(dash); err != nil {
+		return nil, err
+	}
+	dash.Data.Set("id", dash.ID)
+
+	for _, tag := range dash.GetTags() {
+		if _, err := sess.Insert(dashboardTag{DashboardId: dash.ID, Term: tag}); err != nil {
+			return nil, err
+		}
+	}
+	for _, version := range data.Versions {
+		version.ID = 0
+		version.DashboardID = dash.ID
+		if _, err := sess.Insert(version); err != nil {
+			return nil, err
+		}
+	}
+	for _, acl := range data.ACL {
+		acl.ID = 0
+		acl.DashboardID = dash.ID
+		if _, err := sess.Insert(acl); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for _, p := range data.Permissions {
+		// the role might have been removed in the meantime
+		if exists, err := sess.Table("role").Where("id = ?", p.RoleID).Exist(); err != nil {
+			return nil, err
+		} else if !exists {
+			continue
+		}
+		permission := &ac.Permission{
+			RoleID:     p.RoleID,
+			Action:     p.Action,
+			Scope:      p.Scope,
+			Kind:       p.Kind,
+			Attribute:  p.Attribute,
+			Identifier: p.Identifier,
+			Created:    now,
+			Updated:    now,
+		}
+		if _, err := sess.Insert(permission); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, connection := range data.LibraryConnections {
+		if exists, err := sess.Table("library_element").Where("id = ?", connection.ElementID).Exist(); err != nil {
+			return nil, err
+		} else if !exists {
+			continue
+		}
+		connection.ID = 0
+		connection.ConnectionID = dash.ID
+		if _, err := sess.Insert(connection); err != nil {
+			return nil, err
+		}
+	}
+
+	if dash.IsFolder {
+		if err := restoreFolderRow(sess, dash, now); err != nil {
+			return nil, err
+		}
+		if err := restoreAlertRules(sess, &data); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.emitEntityEvent() {
+		if _, err := sess.Insert(createEntityEvent(dash, store.EntityEventTypeCreate)); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := sess.Exec("DELETE FROM deleted_dashboard WHERE id = ?", entry.ID); err != nil {
+		return nil, err
+	}
+
+	// restore everything that was deleted together with the folder
+	if dash.IsFolder {
+		var children []*dashboards.DeletedDashboard
+		if err := sess.Where("org_id = ? AND folder_uid = ?", dash.OrgID, dash.UID).Find(&children); err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if _, err := d.restoreDeletedDashboard(sess, child, dash); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return dash, nil
+}
+
+// restoreAlertRules puts back the alert rules deleted with a folder, rules
+// whose UID has been taken in the meantime are skipped.
+func restoreAlertRules(sess *db.Session, data *deletedDashboardData) error {
+	restored := make(map[string]bool, len(data.AlertRules))
+	for _, rule := range data.AlertRules {
+		if exists, err := sess.Table("alert_rule").Where("org_id = ? AND uid = ?", rule.OrgID, rule.UID).Exist(); err != nil {
+			return err
+		} else if exists {
+			continue
+		}
+		rule.ID = 0
+		version := rule.Version
+		if _, err := sess.Insert(rule); err != nil {
+			return err
+		}
+		// xorm starts the version column of inserted rows at 1, the version
+		// is kept so it does not clash with the restored rule versions
+		if _, err := sess.Exec("UPDATE alert_rule SET version = ? WHERE id = ?", version, rule.ID); err != nil {
+			return err
+		}
+		restored[rule.UID] = true
+	}
+	for _, version := range data.AlertRuleVersions {
+		if !restored[version.RuleUID] {
+			continue
+		}
+		version.ID = 0
+		if _, err := sess.Insert(version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreFolderRow recreates the row of the folder table removed by the folder
+// service, the folder description is not kept.
+func restoreFolderRow(sess *db.Session, dash *dashboards.Dashboard, now time.Time) error {
+	exists, err := sess.Table("folder").Where("org_id = ? AND uid = ?", dash.OrgID, dash.UID).Exist()
+	if err != nil || exists {
+		return err
+	}
+	if dash.FolderUID == "" {
+		_, err = sess.Exec("INSERT INTO folder(org_id, uid, title, description, created, updated) VALUES(?, ?, ?, ?, ?, ?)",
+			dash.OrgID, dash.UID, dash.Title, "", dash.Created, now)
+		return err
+	}
+	_, err = sess.Exec("INSERT INTO folder(org_id, uid, parent_uid, title, description, created, updated) VALUES(?, ?, ?, ?, ?, ?, ?)",
+		dash.OrgID, dash.UID, dash.FolderUID, dash.Title, "", dash.Created, now)
+	return err
+}
+
+func (d *dashboardStore) PurgeDeletedDashboards(ctx context.Context, cmd *dashboards.PurgeDeletedDashboardsCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Omit("data")
+		if cmd.OrgID > 0 {
+			q = q.Where("org_id = ?", cmd.OrgID)
+		}
+		if cmd.UID != "" {
+			q = q.And("uid = ?", cmd.UID)
+		}
+		if !cmd.OlderThan.IsZero() {
+			q = q.And("deleted < ?", cmd.OlderThan)
+		}
+		var entries []*dashboards.DeletedDashboard
+		if err := q.Find(&entries); err != nil {
+			return err
+		}
+		if cmd.UID != "" && len(entries) == 0 {
+			return dashboards.ErrDeletedDashboardNotFound
+		}
+
+		for _, entry := range entries {
+			deleted, err := d.purgeDeletedDashboard(sess, entry, true)
+			if err != nil {
+				return err
+			}
+			cmd.DeletedRows += deleted
+		}
+		return nil
+	})
+}
+
+// purgeDeletedDashboard permanently deletes a deleted dashboard or folder,
+// optionally together with the deleted dashboards and folders it contained.
+func (d *dashboardStore) purgeDeletedDashboard(sess *db.Session, entry *dashboards.DeletedDashboard, withChildren bool) (int64, error) {
+	var count int64
+	if entry.IsFolder && withChildren {
+		var children []*dashboards.DeletedDashboard
+		if err := sess.Where("org_id = ? AND folder_uid = ?", entry.OrgID, entry.UID).Omit("data").Find(&children); err != nil {
+			return 0, err
+		}
+		for _, child := range children {
+			deleted, err := d.purgeDeletedDashboard(sess, child, true)
+			if err != nil {
+				return 0, err
+			}
+			count += deleted
+		}
+	}
+
+	res, err := sess.Exec("DELETE FROM deleted_dashboard WHERE id = ?", entry.ID)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if deleted == 0 {
+		// already purged together with its folder
+		return count, nil
+	}
+
+	if exists, err := sess.Where("id = ?", entry.DashboardID).Exist(&dashboards.Dashboard{}); err != nil {
+		return 0, err
+	} else if !exists {
+		if _, err := sess.Exec("DELETE FROM annotation WHERE dashboard_id = ? AND org_id = ?", entry.DashboardID, entry.OrgID); err != nil {
+			return 0, err
+		}
+	}
+	return count + deleted, nil
+}
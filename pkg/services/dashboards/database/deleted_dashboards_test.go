@@ -0,0 +1,276 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestIntegrationDeletedDashboards(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	var sqlStore *sqlstore.SQLStore
+	var dashboardStore dashboards.Store
+	var savedFolder, savedDash, savedDash2 *dashboards.Dashboard
+	ctx := context.Background()
+
+	setup := func() {
+		var cfg *setting.Cfg
+		sqlStore, cfg = db.InitTestDBwithCfg(t)
+		cfg.DeletedDashboardsRetention = time.Hour
+		var err error
+		dashboardStore, err = ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore), quotatest.New(false, nil))
+		require.NoError(t, err)
+		savedFolder = insertTestDashboard(t, dashboardStore, "deleted folder", 1, 0, "", true)
+		savedDash = insertTestDashboard(t, dashboardStore, "deleted dash", 1, savedFolder.ID, savedFolder.UID, false, "prod")
+		savedDash2 = insertTestDashboard(t, dashboardStore, "deleted dash 2", 1, 0, "", false)
+	}
+
+	countRows := func(t *testing.T, table string, where string, args ...any) int64 {
+		t.Helper()
+		var count int64
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			var err error
+			count, err = sess.Table(table).Where(where, args...).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Deleted dashboard can be restored with its versions, permissions and library panel connections", func(t *testing.T) {
+		setup()
+		scope := ac.GetResourceScopeUID("dashboards", savedDash2.UID)
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			role := &ac.Role{OrgID: 1, UID: "managed-role", Name: "managed:users:1:permissions", Updated: time.Now(), Created: time.Now()}
+			if _, err := sess.Insert(role); err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&ac.Permission{RoleID: role.ID, Action: dashboards.ActionDashboardsRead, Scope: scope, Updated: time.Now(), Created: time.Now()}); err != nil {
+				return err
+			}
+			element := &model.LibraryElement{OrgID: 1, UID: "lib", Name: "lib", Kind: 1, Type: "text", Model: []byte("{}"), Created: time.Now(), Updated: time.Now()}
+			if _, err := sess.Insert(element); err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&model.LibraryElementConnection{ElementID: element.ID, Kind: int64(model.Dashboard), ConnectionID: savedDash2.ID, Created: time.Now()}); err != nil {
+				return err
+			}
+			_, err := sess.Table("annotation").Insert(&annotations.Item{OrgID: 1, DashboardID: savedDash2.ID, Text: "annotation", Data: simplejson.New()})
+			return err
+		})
+		require.NoError(t, err)
+
+		err = dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, ID: savedDash2.ID})
+		require.NoError(t, err)
+
+		_, err = dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: 1, UID: savedDash2.UID})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+		require.Equal(t, int64(0), countRows(t, "permission", "scope = ?", scope))
+		require.Equal(t, int64(0), countRows(t, "library_element_connection", "connection_id = ?", savedDash2.ID))
+		require.Equal(t, int64(1), countRows(t, "annotation", "dashboard_id = ?", savedDash2.ID))
+
+		deleted, err := dashboardStore.ListDeletedDashboards(ctx, &dashboards.ListDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, savedDash2.UID, deleted[0].UID)
+		require.Equal(t, savedDash2.ID, deleted[0].DashboardID)
+		require.False(t, deleted[0].IsFolder)
+		require.Empty(t, deleted[0].Data)
+
+		restored, err := dashboardStore.RestoreDeletedDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{OrgID: 1, UID: savedDash2.UID})
+		require.NoError(t, err)
+		require.Equal(t, savedDash2.ID, restored.ID)
+
+		dash, err := dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: 1, UID: savedDash2.UID})
+		require.NoError(t, err)
+		require.Equal(t, savedDash2.Title, dash.Title)
+		require.Equal(t, int64(1), countRows(t, "dashboard_version", "dashboard_id = ?", savedDash2.ID))
+		require.Equal(t, int64(1), countRows(t, "permission", "scope = ?", scope))
+		require.Equal(t, int64(1), countRows(t, "library_element_connection", "connection_id = ?", savedDash2.ID))
+
+		deleted, err = dashboardStore.ListDeletedDashboards(ctx, &dashboards.ListDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+
+		_, err = dashboardStore.RestoreDeletedDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{OrgID: 1, UID: savedDash2.UID})
+		require.ErrorIs(t, err, dashboards.ErrDeletedDashboardNotFound)
+	})
+
+	t.Run("Restoring a folder restores dashboards deleted with it", func(t *testing.T) {
+		setup()
+		err := dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, UID: savedFolder.UID})
+		require.NoError(t, err)
+
+		deleted, err := dashboardStore.ListDeletedDashboards(ctx, &dashboards.ListDeletedDashboardsQuery{OrgID: 1, IsFolder: util.Pointer(false)})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, savedDash.UID, deleted[0].UID)
+		require.Equal(t, savedFolder.UID, deleted[0].FolderUID)
+
+		_, err = dashboardStore.RestoreDeletedDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{OrgID: 1, UID: savedDash.UID})
+		require.ErrorIs(t, err, dashboards.ErrDeletedDashboardParentNotFound)
+
+		_, err = dashboardStore.RestoreDeletedDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{OrgID: 1, UID: savedFolder.UID})
+		require.NoError(t, err)
+
+		dash, err := dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: 1, UID: savedDash.UID})
+		require.NoError(t, err)
+		require.Equal(t, savedFolder.UID, dash.FolderUID)
+		require.Equal(t, int64(1), countRows(t, "folder", "uid = ?", savedFolder.UID))
+		require.Equal(t, int64(1), countRows(t, "dashboard_tag", "dashboard_id = ? AND term = ?", savedDash.ID, "prod"))
+	})
+
+	t.Run("Restoring a folder restores its alert rules", func(t *testing.T) {
+		setup()
+		rule := &ngmodels.AlertRule{
+			OrgID:           1,
+			UID:             "folder-rule",
+			Title:           "folder rule",
+			Condition:       "A",
+			Data:            []ngmodels.AlertQuery{},
+			Updated:         time.Now(),
+			IntervalSeconds: 60,
+			Version:         3,
+			NamespaceUID:    savedFolder.UID,
+			RuleGroup:       "group",
+			NoDataState:     ngmodels.NoData,
+			ExecErrState:    ngmodels.AlertingErrState,
+		}
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			if _, err := sess.Insert(rule); err != nil {
+				return err
+			}
+			if _, err := sess.Exec("UPDATE alert_rule SET version = ? WHERE id = ?", 3, rule.ID); err != nil {
+				return err
+			}
+			_, err := sess.Insert(&ngmodels.AlertRuleVersion{
+				RuleOrgID:        1,
+				RuleUID:          rule.UID,
+				RuleNamespaceUID: savedFolder.UID,
+				RuleGroup:        "group",
+				Version:          3,
+				Created:          time.Now(),
+				Title:            rule.Title,
+				Condition:        "A",
+				Data:             []ngmodels.AlertQuery{},
+				IntervalSeconds:  60,
+				NoDataState:      ngmodels.NoData,
+				ExecErrState:     ngmodels.AlertingErrState,
+			})
+			return err
+		})
+		require.NoError(t, err)
+
+		err = dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, UID: savedFolder.UID})
+		require.NoError(t, err)
+		// the alerting store deletes the rules of deleted folders
+		err = sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			if _, err := sess.Exec("DELETE FROM alert_rule WHERE uid = ?", rule.UID); err != nil {
+				return err
+			}
+			_, err := sess.Exec("DELETE FROM alert_rule_version WHERE rule_uid = ?", rule.UID)
+			return err
+		})
+		require.NoError(t, err)
+
+		deleted, err := dashboardStore.GetDeletedDashboard(ctx, &dashboards.GetDeletedDashboardQuery{OrgID: 1, UID: savedFolder.UID})
+		require.NoError(t, err)
+		require.True(t, deleted.IsFolder)
+
+		_, err = dashboardStore.RestoreDeletedDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{OrgID: 1, UID: savedFolder.UID})
+		require.NoError(t, err)
+
+		require.Equal(t, int64(1), countRows(t, "alert_rule", "uid = ? AND namespace_uid = ? AND version = ?", rule.UID, savedFolder.UID, 3))
+		require.Equal(t, int64(1), countRows(t, "alert_rule_version", "rule_uid = ? AND version = ?", rule.UID, 3))
+
+		_, err = dashboardStore.GetDeletedDashboard(ctx, &dashboards.GetDeletedDashboardQuery{OrgID: 1, UID: savedFolder.UID})
+		require.ErrorIs(t, err, dashboards.ErrDeletedDashboardNotFound)
+	})
+
+	t.Run("Dashboard can be restored to another folder", func(t *testing.T) {
+		setup()
+		err := dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, UID: savedDash.UID})
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, UID: savedFolder.UID})
+		require.NoError(t, err)
+
+		restored, err := dashboardStore.RestoreDeletedDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{OrgID: 1, UID: savedDash.UID, FolderUID: util.Pointer("")})
+		require.NoError(t, err)
+		require.Empty(t, restored.FolderUID)
+
+		deleted, err := dashboardStore.ListDeletedDashboards(ctx, &dashboards.ListDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.True(t, deleted[0].IsFolder)
+	})
+
+	t.Run("Restoring fails if a dashboard with the same uid exists", func(t *testing.T) {
+		setup()
+		err := dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, UID: savedDash2.UID})
+		require.NoError(t, err)
+		insertTestDashboardWithUID(t, dashboardStore, "new dash", savedDash2.UID)
+
+		_, err = dashboardStore.RestoreDeletedDashboard(ctx, &dashboards.RestoreDeletedDashboardCommand{OrgID: 1, UID: savedDash2.UID})
+		require.ErrorIs(t, err, dashboards.ErrDashboardWithSameUIDExists)
+	})
+
+	t.Run("Purge removes deleted dashboards and their annotations", func(t *testing.T) {
+		setup()
+		err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Table("annotation").Insert(&annotations.Item{OrgID: 1, DashboardID: savedDash.ID, Text: "annotation", Data: simplejson.New()})
+			return err
+		})
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, UID: savedFolder.UID})
+		require.NoError(t, err)
+		err = dashboardStore.DeleteDashboard(ctx, &dashboards.DeleteDashboardCommand{OrgID: 1, UID: savedDash2.UID})
+		require.NoError(t, err)
+
+		cmd := &dashboards.PurgeDeletedDashboardsCommand{OlderThan: time.Now().Add(-time.Hour)}
+		require.NoError(t, dashboardStore.PurgeDeletedDashboards(ctx, cmd))
+		require.Equal(t, int64(0), cmd.DeletedRows)
+
+		cmd = &dashboards.PurgeDeletedDashboardsCommand{OrgID: 1, UID: savedFolder.UID}
+		require.NoError(t, dashboardStore.PurgeDeletedDashboards(ctx, cmd))
+		require.Equal(t, int64(2), cmd.DeletedRows)
+		require.Equal(t, int64(0), countRows(t, "annotation", "dashboard_id = ?", savedDash.ID))
+
+		cmd = &dashboards.PurgeDeletedDashboardsCommand{OlderThan: time.Now().Add(time.Second)}
+		require.NoError(t, dashboardStore.PurgeDeletedDashboards(ctx, cmd))
+		require.Equal(t, int64(1), cmd.DeletedRows)
+
+		deleted, err := dashboardStore.ListDeletedDashboards(ctx, &dashboards.ListDeletedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+}
+
+func insertTestDashboardWithUID(t *testing.T, dashboardStore dashboards.Store, title string, uid string) {
+	t.Helper()
+	_, err := dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+		OrgID: 1,
+		Dashboard: simplejson.NewFromAny(map[string]any{
+			"uid":   uid,
+			"title": title,
+		}),
+	})
+	require.NoError(t, err)
+}
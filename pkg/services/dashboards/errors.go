@@ -116,6 +116,16 @@ var (
 		StatusCode: 404,
 		Status:     "not-found",
 	}
+	ErrDeletedDashboardNotFound = DashboardErr{
+		Reason:     "Deleted dashboard not found",
+		StatusCode: 404,
+		Status:     "not-found",
+	}
+	ErrDeletedDashboardParentNotFound = DashboardErr{
+		Reason:     "The parent folder of the deleted dashboard does not exist, restore it first or choose another folder",
+		StatusCode: 412,
+		Status:     "parent-not-found",
+	}
 
 	ErrFolderNotFound          = errors.New("folder not found")
 	ErrFolderVersionMismatch   = errors.New("the folder has been changed by someone else")
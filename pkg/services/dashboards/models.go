@@ -216,6 +216,9 @@ type DashboardProvisioning struct {
 	ExternalID  string `xorm:"external_id"`
 	CheckSum    string
 	Updated     int64
+	// CommitSHA is the commit the dashboard was provisioned from, for
+	// dashboards provisioned from a git repository.
+	CommitSHA string `xorm:"commit_sha"`
 }
 
 type DeleteDashboardCommand struct {
@@ -349,6 +352,62 @@ type DeleteDashboardsInFolderRequest struct {
 	OrgID      int64
 }
 
+//
+// RECENTLY DELETED
+//
+
+// DeletedDashboard is a dashboard or folder which was deleted and can be
+// restored until the retention period configured by
+// [dashboards] deleted_dashboards_retention expires.
+type DeletedDashboard struct {
+	ID          int64     `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID       int64     `json:"orgId" xorm:"org_id"`
+	DashboardID int64     `json:"id" xorm:"dashboard_id"`
+	UID         string    `json:"uid" xorm:"uid"`
+	Title       string    `json:"title"`
+	IsFolder    bool      `json:"isFolder"`
+	FolderUID   string    `json:"folderUid" xorm:"folder_uid"`
+	Data        string    `json:"-"`
+	Deleted     time.Time `json:"deleted"`
+	DeletedBy   int64     `json:"deletedBy"`
+}
+
+func (d DeletedDashboard) TableName() string { return "deleted_dashboard" }
+
+type GetDeletedDashboardQuery struct {
+	OrgID int64
+	UID   string
+}
+
+type ListDeletedDashboardsQuery struct {
+	OrgID int64
+	// IsFolder filters the result by type if set.
+	IsFolder *bool
+	// FolderUID filters the result by parent folder if set.
+	FolderUID *string
+	Limit     int
+}
+
+type RestoreDeletedDashboardCommand struct {
+	OrgID int64
+	UID   string
+	// FolderUID overrides the folder the dashboard is restored to. The
+	// original folder is used if not set.
+	FolderUID *string
+}
+
+type PurgeDeletedDashboardsCommand struct {
+	// OrgID limits the purge to a single organization if set.
+	OrgID int64
+	// UID purges a single deleted dashboard or folder together with the
+	// deleted dashboards and folders it contained.
+	UID string
+	// OlderThan purges everything deleted before the given time if set.
+	OlderThan time.Time
+
+	DeletedRows int64
+}
+
 //
 // DASHBOARD ACL
 //
@@ -446,6 +446,24 @@ func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId
 	return dr.dashboardStore.DeleteDashboard(ctx, cmd)
 }
 
+func (dr *DashboardServiceImpl) GetDeletedDashboard(ctx context.Context, query *dashboards.GetDeletedDashboardQuery) (*dashboards.DeletedDashboard, error) {
+	return dr.dashboardStore.GetDeletedDashboard(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) ListDeletedDashboards(ctx context.Context, query *dashboards.ListDeletedDashboardsQuery) ([]*dashboards.DeletedDashboard, error) {
+	return dr.dashboardStore.ListDeletedDashboards(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) RestoreDeletedDashboard(ctx context.Context, cmd *dashboards.RestoreDeletedDashboardCommand) (*dashboards.Dashboard, error) {
+	return dr.dashboardStore.RestoreDeletedDashboard(ctx, cmd)
+}
+
+// PurgeDeletedDashboards removes deleted dashboards permanently, so they can
+// no longer be restored.
+func (dr *DashboardServiceImpl) PurgeDeletedDashboards(ctx context.Context, cmd *dashboards.PurgeDeletedDashboardsCommand) error {
+	return dr.dashboardStore.PurgeDeletedDashboards(ctx, cmd)
+}
+
 func (dr *DashboardServiceImpl) ImportDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO) (
 	*dashboards.Dashboard, error) {
 	if err := validateDashboardRefreshInterval(dr.cfg.MinRefreshInterval, dto.Dashboard); err != nil {
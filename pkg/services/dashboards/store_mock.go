@@ -264,6 +264,32 @@ func (_m *FakeDashboardStore) GetDashboardsByPluginID(ctx context.Context, query
 	return r0, r1
 }
 
+// GetDeletedDashboard provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetDeletedDashboard(ctx context.Context, query *GetDeletedDashboardQuery) (*DeletedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *DeletedDashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDeletedDashboardQuery) (*DeletedDashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetDeletedDashboardQuery) *DeletedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DeletedDashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetDeletedDashboardQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetProvisionedDashboardData provides a mock function with given fields: ctx, name
 func (_m *FakeDashboardStore) GetProvisionedDashboardData(ctx context.Context, name string) ([]*DashboardProvisioning, error) {
 	ret := _m.Called(ctx, name)
@@ -342,6 +368,72 @@ func (_m *FakeDashboardStore) GetProvisionedDataByDashboardUID(ctx context.Conte
 	return r0, r1
 }
 
+// ListDeletedDashboards provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) ListDeletedDashboards(ctx context.Context, query *ListDeletedDashboardsQuery) ([]*DeletedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*DeletedDashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDeletedDashboardsQuery) ([]*DeletedDashboard, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *ListDeletedDashboardsQuery) []*DeletedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DeletedDashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *ListDeletedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeletedDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) PurgeDeletedDashboards(ctx context.Context, cmd *PurgeDeletedDashboardsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *PurgeDeletedDashboardsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreDeletedDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) RestoreDeletedDashboard(ctx context.Context, cmd *RestoreDeletedDashboardCommand) (*Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *Dashboard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) (*Dashboard, error)); ok {
+		return rf(ctx, cmd)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreDeletedDashboardCommand) *Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Dashboard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreDeletedDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveAlerts provides a mock function with given fields: ctx, dashID, alerts
 func (_m *FakeDashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	ret := _m.Called(ctx, dashID, alerts)
@@ -0,0 +1,223 @@
+package dashboardsnapshots
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// ArchiveVersion is the version of the snapshot archive format.
+const ArchiveVersion = 1
+
+const (
+	ArchiveFormatJSON = "json"
+	ArchiveFormatHTML = "html"
+)
+
+const archiveScriptOpen = `<script type="application/json" id="grafana-snapshot-archive">`
+
+// Archive is a self-contained export of a local snapshot. The dashboard holds
+// the frozen panel data, so the archive can be imported into another Grafana
+// instance without access to the original data sources.
+type Archive struct {
+	Version   int              `json:"version"`
+	Name      string           `json:"name"`
+	Created   time.Time        `json:"created"`
+	Dashboard *simplejson.Json `json:"dashboard"`
+}
+
+// NewArchive creates an archive of the snapshot. The snapshot dashboard must
+// be decrypted.
+func NewArchive(snapshot *DashboardSnapshot) (*Archive, error) {
+	if snapshot.External {
+		return nil, ErrExternalSnapshotNotSupported.Errorf("external snapshots can not be exported")
+	}
+	if snapshot.Dashboard == nil {
+		return nil, ErrInvalidArchive.Errorf("snapshot has no dashboard")
+	}
+	return &Archive{
+		Version:   ArchiveVersion,
+		Name:      snapshot.Name,
+		Created:   snapshot.Created,
+		Dashboard: snapshot.Dashboard,
+	}, nil
+}
+
+// Export returns the archive encoded in the given format.
+func (a *Archive) Export(format string) ([]byte, error) {
+	switch format {
+	case ArchiveFormatJSON, "":
+		return json.Marshal(a)
+	case ArchiveFormatHTML:
+		return a.html()
+	default:
+		return nil, ErrInvalidArchive.Errorf("unsupported archive format %q", format)
+	}
+}
+
+// ReadArchive decodes an archive exported as JSON or HTML.
+func ReadArchive(data []byte) (*Archive, error) {
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte("{")) {
+		start := bytes.Index(data, []byte(archiveScriptOpen))
+		if start < 0 {
+			return nil, ErrInvalidArchive.Errorf("snapshot archive data not found")
+		}
+		data = data[start+len(archiveScriptOpen):]
+		end := bytes.Index(data, []byte("</script>"))
+		if end < 0 {
+			return nil, ErrInvalidArchive.Errorf("snapshot archive data not terminated")
+		}
+		data = data[:end]
+	}
+
+	archive := &Archive{}
+	if err := json.Unmarshal(data, archive); err != nil {
+		return nil, ErrInvalidArchive.Errorf("failed to decode snapshot archive: %w", err)
+	}
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, ErrInvalidArchive.Errorf("unsupported snapshot archive version %d", archive.Version)
+	}
+	if archive.Dashboard == nil {
+		return nil, ErrInvalidArchive.Errorf("snapshot archive has no dashboard")
+	}
+	return archive, nil
+}
+
+type archivePanel struct {
+	Title  string
+	Tables []archiveTable
+}
+
+type archiveTable struct {
+	Name    string
+	Columns []string
+	Rows    [][]string
+}
+
+func (a *Archive) html() ([]byte, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	title := a.Name
+	if title == "" {
+		title = a.Dashboard.Get("title").MustString()
+	}
+
+	var buf bytes.Buffer
+	err = archiveTemplate.Execute(&buf, map[string]any{
+		"Title":   title,
+		"Created": a.Created.UTC().Format(time.RFC3339),
+		"Panels":  archivePanels(a.Dashboard.Get("panels").MustArray()),
+		// json.Marshal escapes <, > and &, so the data can not close the script element.
+		"Data": template.JS(data), // #nosec G203
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func archivePanels(panels []any) []archivePanel {
+	result := make([]archivePanel, 0, len(panels))
+	for _, p := range panels {
+		panel := simplejson.NewFromAny(p)
+		if panel.Get("type").MustString() == "row" {
+			result = append(result, archivePanel{Title: panel.Get("title").MustString()})
+			result = append(result, archivePanels(panel.Get("panels").MustArray())...)
+			continue
+		}
+
+		ap := archivePanel{Title: panel.Get("title").MustString()}
+		for _, frame := range panel.Get("snapshotData").MustArray() {
+			if table, ok := archiveFrameTable(simplejson.NewFromAny(frame)); ok {
+				ap.Tables = append(ap.Tables, table)
+			}
+		}
+		result = append(result, ap)
+	}
+	return result
+}
+
+// archiveFrameTable renders a frame stored in snapshotData, which is either a
+// data frame DTO with values on the fields or a frame in the data frame JSON
+// format with separate schema and data.
+func archiveFrameTable(frame *simplejson.Json) (archiveTable, bool) {
+	table := archiveTable{Name: frame.Get("name").MustString()}
+
+	fields := frame.Get("fields").MustArray()
+	var values []any
+	if len(fields) > 0 {
+		for _, f := range fields {
+			field := simplejson.NewFromAny(f)
+			table.Columns = append(table.Columns, field.Get("name").MustString())
+			values = append(values, field.Get("values").Interface())
+		}
+	} else {
+		schema := frame.Get("schema")
+		if table.Name == "" {
+			table.Name = schema.Get("name").MustString()
+		}
+		for _, f := range schema.Get("fields").MustArray() {
+			table.Columns = append(table.Columns, simplejson.NewFromAny(f).Get("name").MustString())
+		}
+		values = frame.Get("data").Get("values").MustArray()
+	}
+	if len(table.Columns) == 0 {
+		return table, false
+	}
+
+	columns := make([][]any, len(table.Columns))
+	rows := 0
+	for i := range columns {
+		if i < len(values) {
+			columns[i], _ = values[i].([]any)
+		}
+		if len(columns[i]) > rows {
+			rows = len(columns[i])
+		}
+	}
+	for r := 0; r < rows; r++ {
+		row := make([]string, len(columns))
+		for c, col := range columns {
+			if r < len(col) && col[r] != nil {
+				row[c] = fmt.Sprint(col[r])
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, true
+}
+
+var archiveTemplate = template.Must(template.New("archive").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+<style>
+body { font-family: sans-serif; margin: 24px; color: #202226; }
+table { border-collapse: collapse; margin-bottom: 16px; }
+th, td { border: 1px solid #ccccdc; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+<p>Snapshot created {{ .Created }}</p>
+{{ range .Panels }}<section>
+<h2>{{ .Title }}</h2>
+{{ range .Tables }}<table>
+{{ if .Name }}<caption>{{ .Name }}</caption>{{ end }}
+<tr>{{ range .Columns }}<th>{{ . }}</th>{{ end }}</tr>
+{{ range .Rows }}<tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>
+{{ end }}</table>
+{{ end }}</section>
+{{ end }}` + archiveScriptOpen + `{{ .Data }}</script>
+</body>
+</html>
+`))
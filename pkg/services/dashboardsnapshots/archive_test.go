@@ -0,0 +1,79 @@
+package dashboardsnapshots
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestArchive(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(`{
+		"title": "Snapshot <dash>",
+		"panels": [
+			{"id": 1, "title": "CPU", "snapshotData": [
+				{"name": "A", "fields": [{"name": "time", "values": [1, 2]}, {"name": "value", "values": [0.5, "</script>"]}]}
+			]},
+			{"id": 2, "type": "row", "title": "Row", "panels": [
+				{"id": 3, "title": "Memory", "snapshotData": [
+					{"schema": {"name": "B", "fields": [{"name": "time"}, {"name": "used"}]}, "data": {"values": [[1], [42]]}}
+				]}
+			]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	archive, err := NewArchive(&DashboardSnapshot{
+		Name:      "snap",
+		Created:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Dashboard: dashboard,
+	})
+	require.NoError(t, err)
+
+	t.Run("json export can be read back", func(t *testing.T) {
+		data, err := archive.Export(ArchiveFormatJSON)
+		require.NoError(t, err)
+
+		read, err := ReadArchive(data)
+		require.NoError(t, err)
+		require.Equal(t, "snap", read.Name)
+		require.Equal(t, "Snapshot <dash>", read.Dashboard.Get("title").MustString())
+	})
+
+	t.Run("html export renders panel data and can be read back", func(t *testing.T) {
+		data, err := archive.Export(ArchiveFormatHTML)
+		require.NoError(t, err)
+
+		html := string(data)
+		require.Contains(t, html, "<h2>CPU</h2>")
+		require.Contains(t, html, "<h2>Memory</h2>")
+		require.Contains(t, html, "<td>42</td>")
+		require.Contains(t, html, "&lt;/script&gt;")
+		require.Equal(t, 1, strings.Count(html, "</script>"))
+
+		read, err := ReadArchive(data)
+		require.NoError(t, err)
+		require.Equal(t, "snap", read.Name)
+		frames := read.Dashboard.Get("panels").GetIndex(0).Get("snapshotData").MustArray()
+		require.Len(t, frames, 1)
+	})
+
+	t.Run("invalid archives are rejected", func(t *testing.T) {
+		_, err := ReadArchive([]byte("<html></html>"))
+		require.ErrorIs(t, err, ErrInvalidArchive)
+
+		_, err = ReadArchive([]byte(`{"version": 99, "dashboard": {}}`))
+		require.ErrorIs(t, err, ErrInvalidArchive)
+
+		_, err = archive.Export("pdf")
+		require.ErrorIs(t, err, ErrInvalidArchive)
+	})
+
+	t.Run("external snapshots can not be archived", func(t *testing.T) {
+		_, err := NewArchive(&DashboardSnapshot{External: true, Dashboard: dashboard})
+		require.ErrorIs(t, err, ErrExternalSnapshotNotSupported)
+	})
+}
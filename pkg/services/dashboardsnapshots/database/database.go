@@ -46,8 +46,17 @@ func (d *DashboardSnapshotStore) DeleteExpiredSnapshots(ctx context.Context, cmd
 		return nil
 	}
 	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
-		deleteExpiredSQL := "DELETE FROM dashboard_snapshot WHERE expires < ?"
-		expiredResponse, err := sess.Exec(deleteExpiredSQL, time.Now())
+		// External snapshots are removed with GetExpiredExternalSnapshots once
+		// they are deleted from the external server.
+		now := time.Now()
+		deleteSchedulesSQL := "DELETE FROM dashboard_snapshot_refresh WHERE snapshot_key IN (SELECT " +
+			d.store.GetDialect().Quote("key") + " FROM dashboard_snapshot WHERE expires < ? AND external = ?)"
+		if _, err := sess.Exec(deleteSchedulesSQL, now, false); err != nil {
+			return err
+		}
+
+		deleteExpiredSQL := "DELETE FROM dashboard_snapshot WHERE expires < ? AND external = ?"
+		expiredResponse, err := sess.Exec(deleteExpiredSQL, now, false)
 		if err != nil {
 			return err
 		}
@@ -57,6 +66,23 @@ func (d *DashboardSnapshotStore) DeleteExpiredSnapshots(ctx context.Context, cmd
 	})
 }
 
+// GetExpiredExternalSnapshots returns expired snapshots stored on an external
+// server, which must be deleted there before the local reference is removed.
+func (d *DashboardSnapshotStore) GetExpiredExternalSnapshots(ctx context.Context, query *dashboardsnapshots.GetExpiredExternalSnapshotsQuery) ([]*dashboardsnapshots.DashboardSnapshot, error) {
+	if d.skipDeleteExpired {
+		return nil, nil
+	}
+	snapshots := make([]*dashboardsnapshots.DashboardSnapshot, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("expires < ? AND external = ?", query.Now, true).Asc("expires")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit)
+		}
+		return sess.Find(&snapshots)
+	})
+	return snapshots, err
+}
+
 func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
 	var result *dashboardsnapshots.DashboardSnapshot
 	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
@@ -93,12 +119,33 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 
 func (d *DashboardSnapshotStore) DeleteDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.DeleteDashboardSnapshotCommand) error {
 	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		deleteScheduleSQL := "DELETE FROM dashboard_snapshot_refresh WHERE snapshot_key IN (SELECT " +
+			d.store.GetDialect().Quote("key") + " FROM dashboard_snapshot WHERE delete_key = ?)"
+		if _, err := sess.Exec(deleteScheduleSQL, cmd.DeleteKey); err != nil {
+			return err
+		}
+
 		var rawSQL = "DELETE FROM dashboard_snapshot WHERE delete_key=?"
 		_, err := sess.Exec(rawSQL, cmd.DeleteKey)
 		return err
 	})
 }
 
+// UpdateDashboardSnapshot replaces the dashboard data of a snapshot.
+func (d *DashboardSnapshotStore) UpdateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.UpdateDashboardSnapshotCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rawSQL := "UPDATE dashboard_snapshot SET dashboard_encrypted = ?, updated = ? WHERE " + d.store.GetDialect().Quote("key") + " = ?"
+		res, err := sess.Exec(rawSQL, cmd.DashboardEncrypted, time.Now(), cmd.Key)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot not found")
+		}
+		return nil
+	})
+}
+
 func (d *DashboardSnapshotStore) GetDashboardSnapshot(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotQuery) (*dashboardsnapshots.DashboardSnapshot, error) {
 	var queryResult *dashboardsnapshots.DashboardSnapshot
 	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
@@ -197,6 +197,25 @@ func TestIntegrationDeleteExpiredSnapshots(t *testing.T) {
 		require.Len(t, queryResult, 1)
 		require.Equal(t, nonExpiredSnapshot.Key, queryResult[0].Key)
 	})
+
+	t.Run("Expired external snapshots are not deleted with local snapshots", func(t *testing.T) {
+		external := createTestSnapshot(t, dashStore, "key4", -1200)
+		err := dashStore.store.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("UPDATE dashboard_snapshot SET external = ? WHERE id = ?", true, external.ID)
+			return err
+		})
+		require.NoError(t, err)
+
+		cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
+		err = dashStore.DeleteExpiredSnapshots(context.Background(), &cmd)
+		require.NoError(t, err)
+		require.Zero(t, cmd.DeletedRows)
+
+		expired, err := dashStore.GetExpiredExternalSnapshots(context.Background(), &dashboardsnapshots.GetExpiredExternalSnapshotsQuery{Now: time.Now()})
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		require.Equal(t, "key4", expired[0].Key)
+	})
 }
 
 func createTestSnapshot(t *testing.T, dashStore *DashboardSnapshotStore, key string, expires int64) *dashboardsnapshots.DashboardSnapshot {
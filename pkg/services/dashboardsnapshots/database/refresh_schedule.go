@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+)
+
+func (d *DashboardSnapshotStore) GetRefreshSchedule(ctx context.Context, query *dashboardsnapshots.GetSnapshotRefreshScheduleQuery) (*dashboardsnapshots.SnapshotRefreshSchedule, error) {
+	var result *dashboardsnapshots.SnapshotRefreshSchedule
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		result, err = getRefreshSchedule(sess, query.OrgID, query.SnapshotKey)
+		return err
+	})
+	return result, err
+}
+
+// SaveRefreshSchedule creates the refresh schedule of the snapshot or replaces
+// the existing one.
+func (d *DashboardSnapshotStore) SaveRefreshSchedule(ctx context.Context, cmd *dashboardsnapshots.SaveSnapshotRefreshScheduleCommand) (*dashboardsnapshots.SnapshotRefreshSchedule, error) {
+	var result *dashboardsnapshots.SnapshotRefreshSchedule
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing, err := getRefreshSchedule(sess, cmd.OrgID, cmd.SnapshotKey)
+		if err != nil && !errors.Is(err, dashboardsnapshots.ErrRefreshScheduleNotFound) {
+			return err
+		}
+
+		now := time.Now()
+		result = &dashboardsnapshots.SnapshotRefreshSchedule{
+			OrgID:        cmd.OrgID,
+			SnapshotKey:  cmd.SnapshotKey,
+			DashboardUID: cmd.DashboardUID,
+			Schedule:     cmd.Schedule,
+			UserID:       cmd.UserID,
+			NextRun:      cmd.NextRun,
+			Created:      now,
+			Updated:      now,
+		}
+		if existing == nil {
+			_, err = sess.Insert(result)
+			return err
+		}
+
+		result.ID = existing.ID
+		result.Created = existing.Created
+		result.LastRun = existing.LastRun
+		result.LastError = existing.LastError
+		_, err = sess.ID(existing.ID).Cols("dashboard_uid", "schedule", "user_id", "next_run", "updated").Update(result)
+		return err
+	})
+	return result, err
+}
+
+func (d *DashboardSnapshotStore) DeleteRefreshSchedule(ctx context.Context, cmd *dashboardsnapshots.DeleteSnapshotRefreshScheduleCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM dashboard_snapshot_refresh WHERE org_id = ? AND snapshot_key = ?", cmd.OrgID, cmd.SnapshotKey)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return dashboardsnapshots.ErrRefreshScheduleNotFound.Errorf("refresh schedule of snapshot not found")
+		}
+		return nil
+	})
+}
+
+func (d *DashboardSnapshotStore) GetDueRefreshSchedules(ctx context.Context, query *dashboardsnapshots.GetDueSnapshotRefreshSchedulesQuery) ([]*dashboardsnapshots.SnapshotRefreshSchedule, error) {
+	schedules := make([]*dashboardsnapshots.SnapshotRefreshSchedule, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("next_run <= ?", query.Now).Asc("next_run")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit)
+		}
+		return sess.Find(&schedules)
+	})
+	return schedules, err
+}
+
+// ClaimRefreshSchedule moves the next run of the schedule, if it was not moved
+// by another instance since the schedule was read.
+func (d *DashboardSnapshotStore) ClaimRefreshSchedule(ctx context.Context, cmd *dashboardsnapshots.ClaimSnapshotRefreshScheduleCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE dashboard_snapshot_refresh SET next_run = ? WHERE id = ? AND next_run = ?", cmd.NextRun, cmd.ID, cmd.DueRun)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		cmd.Claimed = affected == 1
+		return nil
+	})
+}
+
+func (d *DashboardSnapshotStore) UpdateRefreshResult(ctx context.Context, cmd *dashboardsnapshots.UpdateSnapshotRefreshResultCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard_snapshot_refresh SET last_run = ?, last_error = ? WHERE id = ?", cmd.LastRun, cmd.LastError, cmd.ID)
+		return err
+	})
+}
+
+func getRefreshSchedule(sess *db.Session, orgID int64, snapshotKey string) (*dashboardsnapshots.SnapshotRefreshSchedule, error) {
+	schedule := &dashboardsnapshots.SnapshotRefreshSchedule{}
+	has, err := sess.Where("org_id = ? AND snapshot_key = ?", orgID, snapshotKey).Get(schedule)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, dashboardsnapshots.ErrRefreshScheduleNotFound.Errorf("refresh schedule of snapshot not found")
+	}
+	return schedule, nil
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+)
+
+func TestIntegrationSnapshotRefreshSchedules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlstore := db.InitTestDB(t)
+	dashStore := NewStore(sqlstore, false)
+	ctx := context.Background()
+
+	snapshot := createTestSnapshot(t, dashStore, "refresh", 48000)
+	due := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	t.Run("Should save and replace refresh schedule", func(t *testing.T) {
+		_, err := dashStore.GetRefreshSchedule(ctx, &dashboardsnapshots.GetSnapshotRefreshScheduleQuery{OrgID: 1, SnapshotKey: snapshot.Key})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrRefreshScheduleNotFound)
+
+		created, err := dashStore.SaveRefreshSchedule(ctx, &dashboardsnapshots.SaveSnapshotRefreshScheduleCommand{
+			OrgID:        1,
+			SnapshotKey:  snapshot.Key,
+			DashboardUID: "dash",
+			Schedule:     "0 * * * *",
+			UserID:       1000,
+			NextRun:      time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		updated, err := dashStore.SaveRefreshSchedule(ctx, &dashboardsnapshots.SaveSnapshotRefreshScheduleCommand{
+			OrgID:        1,
+			SnapshotKey:  snapshot.Key,
+			DashboardUID: "dash",
+			Schedule:     "*/5 * * * *",
+			UserID:       1000,
+			NextRun:      due,
+		})
+		require.NoError(t, err)
+		require.Equal(t, created.ID, updated.ID)
+
+		schedule, err := dashStore.GetRefreshSchedule(ctx, &dashboardsnapshots.GetSnapshotRefreshScheduleQuery{OrgID: 1, SnapshotKey: snapshot.Key})
+		require.NoError(t, err)
+		require.Equal(t, "*/5 * * * *", schedule.Schedule)
+	})
+
+	t.Run("Should claim due schedule only once", func(t *testing.T) {
+		schedules, err := dashStore.GetDueRefreshSchedules(ctx, &dashboardsnapshots.GetDueSnapshotRefreshSchedulesQuery{Now: time.Now()})
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+
+		next := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+		first := &dashboardsnapshots.ClaimSnapshotRefreshScheduleCommand{ID: schedules[0].ID, DueRun: schedules[0].NextRun, NextRun: next}
+		require.NoError(t, dashStore.ClaimRefreshSchedule(ctx, first))
+		require.True(t, first.Claimed)
+
+		second := &dashboardsnapshots.ClaimSnapshotRefreshScheduleCommand{ID: schedules[0].ID, DueRun: schedules[0].NextRun, NextRun: next}
+		require.NoError(t, dashStore.ClaimRefreshSchedule(ctx, second))
+		require.False(t, second.Claimed)
+
+		schedules, err = dashStore.GetDueRefreshSchedules(ctx, &dashboardsnapshots.GetDueSnapshotRefreshSchedulesQuery{Now: time.Now()})
+		require.NoError(t, err)
+		require.Empty(t, schedules)
+	})
+
+	t.Run("Should delete refresh schedule with the snapshot", func(t *testing.T) {
+		err := dashStore.DeleteDashboardSnapshot(ctx, &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: snapshot.DeleteKey})
+		require.NoError(t, err)
+
+		_, err = dashStore.GetRefreshSchedule(ctx, &dashboardsnapshots.GetSnapshotRefreshScheduleQuery{OrgID: 1, SnapshotKey: snapshot.Key})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrRefreshScheduleNotFound)
+	})
+}
@@ -4,4 +4,10 @@ import (
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
-var ErrBaseNotFound = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+var (
+	ErrBaseNotFound                 = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+	ErrRefreshScheduleNotFound      = errutil.NotFound("dashboardsnapshots.refresh-schedule-not-found", errutil.WithPublicMessage("Snapshot refresh schedule not found"))
+	ErrInvalidRefreshSchedule       = errutil.BadRequest("dashboardsnapshots.invalid-refresh-schedule", errutil.WithPublicMessage("Invalid snapshot refresh schedule"))
+	ErrExternalSnapshotNotSupported = errutil.BadRequest("dashboardsnapshots.external-not-supported", errutil.WithPublicMessage("Operation is not supported for external snapshots"))
+	ErrInvalidArchive               = errutil.BadRequest("dashboardsnapshots.invalid-archive", errutil.WithPublicMessage("Invalid snapshot archive"))
+)
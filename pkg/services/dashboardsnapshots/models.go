@@ -3,6 +3,8 @@ package dashboardsnapshots
 import (
 	"time"
 
+	"github.com/robfig/cron/v3"
+
 	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
@@ -77,11 +79,23 @@ type DeleteExpiredSnapshotsCommand struct {
 	DeletedRows int64
 }
 
+type UpdateDashboardSnapshotCommand struct {
+	Key       string
+	Dashboard *simplejson.Json
+
+	DashboardEncrypted []byte
+}
+
 type GetDashboardSnapshotQuery struct {
 	Key       string
 	DeleteKey string
 }
 
+type GetExpiredExternalSnapshotsQuery struct {
+	Now   time.Time
+	Limit int
+}
+
 type DashboardSnapshotsList []*DashboardSnapshotDTO
 
 type GetDashboardSnapshotsQuery struct {
@@ -97,3 +111,73 @@ type CreateExternalSnapshotResponse struct {
 	Url       string `json:"url"`
 	DeleteUrl string `json:"deleteUrl"`
 }
+
+// SnapshotRefreshSchedule replaces the data of a snapshot with the current
+// data of its dashboard on a cron schedule.
+type SnapshotRefreshSchedule struct {
+	ID           int64     `xorm:"pk autoincr 'id'" json:"-"`
+	OrgID        int64     `xorm:"org_id" json:"-"`
+	SnapshotKey  string    `xorm:"snapshot_key" json:"snapshotKey"`
+	DashboardUID string    `xorm:"dashboard_uid" json:"dashboardUid"`
+	Schedule     string    `json:"schedule"`
+	UserID       int64     `xorm:"user_id" json:"userId"`
+	NextRun      time.Time `json:"nextRun"`
+	LastRun      time.Time `json:"lastRun"`
+	LastError    string    `json:"lastError"`
+	Created      time.Time `json:"created"`
+	Updated      time.Time `json:"updated"`
+}
+
+func (s SnapshotRefreshSchedule) TableName() string {
+	return "dashboard_snapshot_refresh"
+}
+
+// ParseRefreshSchedule parses a standard five field cron expression.
+func ParseRefreshSchedule(schedule string) (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, ErrInvalidRefreshSchedule.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	return sched, nil
+}
+
+type GetSnapshotRefreshScheduleQuery struct {
+	OrgID       int64
+	SnapshotKey string
+}
+
+type SaveSnapshotRefreshScheduleCommand struct {
+	OrgID        int64
+	SnapshotKey  string
+	DashboardUID string
+	Schedule     string
+	UserID       int64
+
+	NextRun time.Time
+}
+
+type DeleteSnapshotRefreshScheduleCommand struct {
+	OrgID       int64
+	SnapshotKey string
+}
+
+type GetDueSnapshotRefreshSchedulesQuery struct {
+	Now   time.Time
+	Limit int
+}
+
+// ClaimSnapshotRefreshScheduleCommand moves the next run of a due schedule,
+// so only one Grafana instance refreshes the snapshot.
+type ClaimSnapshotRefreshScheduleCommand struct {
+	ID      int64
+	DueRun  time.Time
+	NextRun time.Time
+
+	Claimed bool
+}
+
+type UpdateSnapshotRefreshResultCommand struct {
+	ID        int64
+	LastRun   time.Time
+	LastError string
+}
@@ -0,0 +1,267 @@
+// Package refresher re-creates the data of snapshots with a refresh schedule
+// from the dashboard they were taken from.
+package refresher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+const (
+	checkInterval = time.Minute
+	// maxRefreshesPerRun limits the number of snapshots refreshed by one
+	// instance per check, the remaining ones are picked up by the next check.
+	maxRefreshesPerRun = 50
+	refreshTimeout     = 2 * time.Minute
+)
+
+var errNoAccess = errors.New("schedule owner can not view the dashboard")
+
+type Service struct {
+	cfg              *setting.Cfg
+	store            dashboardsnapshots.Store
+	snapshotService  dashboardsnapshots.Service
+	dashboardService dashboards.DashboardService
+	queryService     query.Service
+	userService      user.Service
+	log              log.Logger
+	now              func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, store dashboardsnapshots.Store, snapshotService dashboardsnapshots.Service,
+	dashboardService dashboards.DashboardService, queryService query.Service, userService user.Service) *Service {
+	return &Service{
+		cfg:              cfg,
+		store:            store,
+		snapshotService:  snapshotService,
+		dashboardService: dashboardService,
+		queryService:     queryService,
+		userService:      userService,
+		log:              log.New("dashboardsnapshots.refresher"),
+		now:              time.Now,
+	}
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.SnapshotEnabled
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.refreshDue(ctx)
+		}
+	}
+}
+
+func (s *Service) refreshDue(ctx context.Context) {
+	now := s.now()
+	schedules, err := s.store.GetDueRefreshSchedules(ctx, &dashboardsnapshots.GetDueSnapshotRefreshSchedulesQuery{
+		Now:   now,
+		Limit: maxRefreshesPerRun,
+	})
+	if err != nil {
+		s.log.Error("Failed to get due snapshot refresh schedules", "error", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		cron, err := dashboardsnapshots.ParseRefreshSchedule(schedule.Schedule)
+		if err != nil {
+			s.log.Warn("Invalid snapshot refresh schedule", "key", schedule.SnapshotKey, "error", err)
+			continue
+		}
+
+		// Claiming moves the next run forward, so only one instance refreshes the snapshot.
+		claim := &dashboardsnapshots.ClaimSnapshotRefreshScheduleCommand{
+			ID:      schedule.ID,
+			DueRun:  schedule.NextRun,
+			NextRun: cron.Next(now).Truncate(time.Second),
+		}
+		if err := s.store.ClaimRefreshSchedule(ctx, claim); err != nil {
+			s.log.Error("Failed to claim snapshot refresh schedule", "key", schedule.SnapshotKey, "error", err)
+			continue
+		}
+		if !claim.Claimed {
+			continue
+		}
+
+		result := &dashboardsnapshots.UpdateSnapshotRefreshResultCommand{ID: schedule.ID, LastRun: now}
+		if err := s.refresh(ctx, schedule); err != nil {
+			s.log.Warn("Failed to refresh snapshot", "key", schedule.SnapshotKey, "dashboardUID", schedule.DashboardUID, "error", err)
+			metrics.MDashboardSnapshotRefresh.WithLabelValues("failure").Inc()
+			result.LastError = err.Error()
+		} else {
+			metrics.MDashboardSnapshotRefresh.WithLabelValues("success").Inc()
+		}
+		if err := s.store.UpdateRefreshResult(ctx, result); err != nil {
+			s.log.Error("Failed to save snapshot refresh result", "key", schedule.SnapshotKey, "error", err)
+		}
+	}
+}
+
+// refresh queries the panels of the source dashboard as the schedule owner
+// and replaces the snapshot dashboard with the result.
+func (s *Service) refresh(ctx context.Context, schedule *dashboardsnapshots.SnapshotRefreshSchedule) error {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	usr, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{
+		UserID: schedule.UserID,
+		OrgID:  schedule.OrgID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get schedule owner: %w", err)
+	}
+
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{
+		UID:   schedule.DashboardUID,
+		OrgID: schedule.OrgID,
+	})
+	if err != nil {
+		return err
+	}
+	g, err := guardian.NewByDashboard(ctx, dash, schedule.OrgID, usr)
+	if err != nil {
+		return err
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return errNoAccess
+	}
+
+	snapshot, err := s.snapshotService.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: schedule.SnapshotKey})
+	if err != nil {
+		return err
+	}
+
+	// Work on a copy, the dashboard may be cached.
+	encoded, err := dash.Data.Encode()
+	if err != nil {
+		return err
+	}
+	model, err := simplejson.NewJson(encoded)
+	if err != nil {
+		return err
+	}
+
+	timeRange := legacydata.NewDataTimeRange(
+		model.GetPath("time", "from").MustString("now-6h"),
+		model.GetPath("time", "to").MustString("now"),
+	)
+	from, err := timeRange.ParseFrom()
+	if err != nil {
+		return fmt.Errorf("invalid dashboard time range: %w", err)
+	}
+	to, err := timeRange.ParseTo()
+	if err != nil {
+		return fmt.Errorf("invalid dashboard time range: %w", err)
+	}
+
+	for _, panel := range model.Get("panels").MustArray() {
+		p := simplejson.NewFromAny(panel)
+		if p.Get("type").MustString() == "row" {
+			for _, nested := range p.Get("panels").MustArray() {
+				if err := s.refreshPanel(ctx, usr, simplejson.NewFromAny(nested), from, to); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := s.refreshPanel(ctx, usr, p, from, to); err != nil {
+			return err
+		}
+	}
+
+	model.Set("time", map[string]any{"from": from.UTC().Format(time.RFC3339Nano), "to": to.UTC().Format(time.RFC3339Nano)})
+	meta := map[string]any{"timestamp": s.now().UTC().Format(time.RFC3339)}
+	if snapshot.Dashboard != nil {
+		meta["originalUrl"] = snapshot.Dashboard.GetPath("snapshot", "originalUrl").MustString()
+	}
+	model.Set("snapshot", meta)
+
+	return s.snapshotService.UpdateDashboardSnapshot(ctx, &dashboardsnapshots.UpdateDashboardSnapshotCommand{
+		Key:       schedule.SnapshotKey,
+		Dashboard: model,
+	})
+}
+
+// refreshPanel runs the panel targets and stores the frames in the panel
+// snapshotData, the way the frontend does when a snapshot is created.
+func (s *Service) refreshPanel(ctx context.Context, usr *user.SignedInUser, panel *simplejson.Json, from, to time.Time) error {
+	targets := panel.Get("targets").MustArray()
+	if len(targets) == 0 {
+		return nil
+	}
+
+	queries := make([]*simplejson.Json, 0, len(targets))
+	for _, t := range targets {
+		target := simplejson.NewFromAny(t)
+		if target.Get("hide").MustBool() {
+			continue
+		}
+		if _, ok := target.CheckGet("datasource"); !ok {
+			target.Set("datasource", panel.Get("datasource").Interface())
+		}
+		queries = append(queries, target)
+	}
+
+	frames := make([]any, 0)
+	if len(queries) > 0 {
+		resp, err := s.queryService.QueryData(ctx, usr, false, dtos.MetricRequest{
+			From:    strconv.FormatInt(from.UnixMilli(), 10),
+			To:      strconv.FormatInt(to.UnixMilli(), 10),
+			Queries: queries,
+		})
+		if err != nil {
+			return fmt.Errorf("panel %d: %w", panel.Get("id").MustInt64(), err)
+		}
+		for _, q := range queries {
+			res, ok := resp.Responses[q.Get("refId").MustString("A")]
+			if !ok {
+				continue
+			}
+			if res.Error != nil {
+				return fmt.Errorf("panel %d: %w", panel.Get("id").MustInt64(), res.Error)
+			}
+			for _, frame := range res.Frames {
+				encoded, err := data.FrameToJSON(frame, data.IncludeAll)
+				if err != nil {
+					return err
+				}
+				var v any
+				if err := json.Unmarshal(encoded, &v); err != nil {
+					return err
+				}
+				frames = append(frames, v)
+			}
+		}
+	}
+
+	panel.Set("snapshotData", frames)
+	panel.Del("targets")
+	panel.Del("links")
+	return nil
+}
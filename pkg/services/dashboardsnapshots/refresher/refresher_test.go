@@ -0,0 +1,153 @@
+package refresher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_refreshDue(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	schedule := &dashboardsnapshots.SnapshotRefreshSchedule{
+		ID:           1,
+		OrgID:        1,
+		SnapshotKey:  "snap",
+		DashboardUID: "dash",
+		Schedule:     "0 * * * *",
+		UserID:       2,
+		NextRun:      now.Add(-time.Minute),
+	}
+
+	setup := func(t *testing.T, claimed bool) (*Service, *fakeStore, *dashboardsnapshots.MockService, *query.FakeQueryService) {
+		t.Helper()
+		store := &fakeStore{schedules: []*dashboardsnapshots.SnapshotRefreshSchedule{schedule}, claimed: claimed}
+		snapshotService := dashboardsnapshots.NewMockService(t)
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "dash", OrgID: 1}).Return(&dashboards.Dashboard{
+			UID: "dash",
+			Data: simplejson.MustJson([]byte(`{
+				"time": {"from": "now-1h", "to": "now"},
+				"panels": [
+					{"id": 1, "datasource": {"uid": "prometheus"}, "targets": [{"refId": "A"}]},
+					{"type": "row", "panels": [{"id": 2, "targets": [{"refId": "B", "hide": true}]}]}
+				]
+			}`)),
+		}, nil).Maybe()
+		queryService := query.NewFakeQueryService(t)
+		return &Service{
+			cfg:              setting.NewCfg(),
+			store:            store,
+			snapshotService:  snapshotService,
+			dashboardService: dashboardService,
+			queryService:     queryService,
+			userService:      &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 2, OrgID: 1}},
+			log:              log.NewNopLogger(),
+			now:              func() time.Time { return now },
+		}, store, snapshotService, queryService
+	}
+
+	t.Run("should replace the snapshot data with the current dashboard data", func(t *testing.T) {
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+		s, store, snapshotService, queryService := setup(t, true)
+
+		queryService.On("QueryData", mock.Anything, mock.Anything, false, mock.MatchedBy(func(req dtos.MetricRequest) bool {
+			return len(req.Queries) == 1 && req.Queries[0].Get("datasource").Get("uid").MustString() == "prometheus"
+		})).Return(&backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Frames: data.Frames{data.NewFrame("series", data.NewField("value", nil, []float64{1}))}},
+		}}, nil)
+		snapshotService.On("GetDashboardSnapshot", mock.Anything, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "snap"}).Return(&dashboardsnapshots.DashboardSnapshot{
+			Key:       "snap",
+			Dashboard: simplejson.MustJson([]byte(`{"snapshot": {"originalUrl": "http://grafana/d/dash"}}`)),
+		}, nil)
+		var updated *dashboardsnapshots.UpdateDashboardSnapshotCommand
+		snapshotService.On("UpdateDashboardSnapshot", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated = args.Get(1).(*dashboardsnapshots.UpdateDashboardSnapshotCommand)
+		}).Return(nil)
+
+		s.refreshDue(context.Background())
+
+		require.Len(t, store.claims, 1)
+		assert.Equal(t, schedule.NextRun, store.claims[0].DueRun)
+		assert.Equal(t, time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC), store.claims[0].NextRun.UTC())
+		require.Len(t, store.results, 1)
+		assert.Equal(t, now, store.results[0].LastRun)
+		assert.Empty(t, store.results[0].LastError)
+
+		require.NotNil(t, updated)
+		assert.Equal(t, "snap", updated.Key)
+		panels := updated.Dashboard.Get("panels")
+		assert.Len(t, panels.GetIndex(0).Get("snapshotData").MustArray(), 1)
+		assert.Nil(t, panels.GetIndex(0).Get("targets").Interface())
+		assert.Empty(t, panels.GetIndex(1).Get("panels").GetIndex(0).Get("snapshotData").MustArray())
+		assert.NotEmpty(t, updated.Dashboard.GetPath("time", "from").MustString())
+		assert.Equal(t, "2024-05-01T10:00:00Z", updated.Dashboard.GetPath("snapshot", "timestamp").MustString())
+		assert.Equal(t, "http://grafana/d/dash", updated.Dashboard.GetPath("snapshot", "originalUrl").MustString())
+	})
+
+	t.Run("should skip schedules claimed by another instance", func(t *testing.T) {
+		s, store, _, _ := setup(t, false)
+
+		s.refreshDue(context.Background())
+
+		require.Len(t, store.claims, 1)
+		assert.Empty(t, store.results)
+	})
+
+	t.Run("should record an error when the owner can not view the dashboard", func(t *testing.T) {
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: false})
+		s, store, _, _ := setup(t, true)
+
+		s.refreshDue(context.Background())
+
+		require.Len(t, store.results, 1)
+		assert.Equal(t, errNoAccess.Error(), store.results[0].LastError)
+	})
+}
+
+// fakeStore returns the due schedules and records the claims and results.
+type fakeStore struct {
+	dashboardsnapshots.Store
+	schedules []*dashboardsnapshots.SnapshotRefreshSchedule
+	claimed   bool
+
+	claims  []*dashboardsnapshots.ClaimSnapshotRefreshScheduleCommand
+	results []*dashboardsnapshots.UpdateSnapshotRefreshResultCommand
+}
+
+func (s *fakeStore) GetDueRefreshSchedules(_ context.Context, query *dashboardsnapshots.GetDueSnapshotRefreshSchedulesQuery) ([]*dashboardsnapshots.SnapshotRefreshSchedule, error) {
+	var due []*dashboardsnapshots.SnapshotRefreshSchedule
+	for _, schedule := range s.schedules {
+		if !schedule.NextRun.After(query.Now) {
+			due = append(due, schedule)
+		}
+	}
+	return due, nil
+}
+
+func (s *fakeStore) ClaimRefreshSchedule(_ context.Context, cmd *dashboardsnapshots.ClaimSnapshotRefreshScheduleCommand) error {
+	cmd.Claimed = s.claimed
+	s.claims = append(s.claims, cmd)
+	return nil
+}
+
+func (s *fakeStore) UpdateRefreshResult(_ context.Context, cmd *dashboardsnapshots.UpdateSnapshotRefreshResultCommand) error {
+	s.results = append(s.results, cmd)
+	return nil
+}
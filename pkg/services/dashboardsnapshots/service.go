@@ -27,6 +27,11 @@ type Service interface {
 	DeleteExpiredSnapshots(context.Context, *DeleteExpiredSnapshotsCommand) error
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) (*DashboardSnapshot, error)
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error)
+	UpdateDashboardSnapshot(context.Context, *UpdateDashboardSnapshotCommand) error
+
+	GetRefreshSchedule(context.Context, *GetSnapshotRefreshScheduleQuery) (*SnapshotRefreshSchedule, error)
+	SaveRefreshSchedule(context.Context, *SaveSnapshotRefreshScheduleCommand) (*SnapshotRefreshSchedule, error)
+	DeleteRefreshSchedule(context.Context, *DeleteSnapshotRefreshScheduleCommand) error
 }
 
 var client = &http.Client{
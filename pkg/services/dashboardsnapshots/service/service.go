@@ -2,15 +2,25 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/secrets"
 )
 
+// externalDeleteGracePeriod is how long the deletion of an expired external
+// snapshot is retried before only the local reference is removed.
+const externalDeleteGracePeriod = 7 * 24 * time.Hour
+
 type ServiceImpl struct {
 	store          dashboardsnapshots.Store
 	secretsService secrets.Service
+	log            log.Logger
+
+	deleteExternal func(externalDeleteURL string) error
 }
 
 // ServiceImpl implements the dashboardsnapshots Service interface
@@ -20,6 +30,8 @@ func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Servi
 	s := &ServiceImpl{
 		store:          store,
 		secretsService: secretsService,
+		log:            log.New("dashboardsnapshots.service"),
+		deleteExternal: dashboardsnapshots.DeleteExternalDashboardSnapshot,
 	}
 
 	return s
@@ -64,6 +76,22 @@ func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboard
 	return queryResult, err
 }
 
+func (s *ServiceImpl) UpdateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.UpdateDashboardSnapshotCommand) error {
+	marshalledData, err := cmd.Dashboard.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	encryptedDashboard, err := s.secretsService.Encrypt(ctx, marshalledData, secrets.WithoutScope())
+	if err != nil {
+		return err
+	}
+
+	cmd.DashboardEncrypted = encryptedDashboard
+
+	return s.store.UpdateDashboardSnapshot(ctx, cmd)
+}
+
 func (s *ServiceImpl) DeleteDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.DeleteDashboardSnapshotCommand) error {
 	return s.store.DeleteDashboardSnapshot(ctx, cmd)
 }
@@ -72,6 +100,70 @@ func (s *ServiceImpl) SearchDashboardSnapshots(ctx context.Context, query *dashb
 	return s.store.SearchDashboardSnapshots(ctx, query)
 }
 
+// DeleteExpiredSnapshots deletes expired snapshots. Expired external snapshots
+// are deleted from the external server first, and their local reference is
+// kept for a retry if that fails.
 func (s *ServiceImpl) DeleteExpiredSnapshots(ctx context.Context, cmd *dashboardsnapshots.DeleteExpiredSnapshotsCommand) error {
-	return s.store.DeleteExpiredSnapshots(ctx, cmd)
+	now := time.Now()
+	external, err := s.store.GetExpiredExternalSnapshots(ctx, &dashboardsnapshots.GetExpiredExternalSnapshotsQuery{Now: now})
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range external {
+		if err := s.deleteExternal(snapshot.ExternalDeleteURL); err != nil {
+			metrics.MDashboardSnapshotExpiredDeleteFailed.Inc()
+			if now.Sub(snapshot.Expires) < externalDeleteGracePeriod {
+				s.log.Warn("Failed to delete expired external snapshot, will retry", "key", snapshot.Key, "error", err)
+				continue
+			}
+			s.log.Warn("Failed to delete expired external snapshot, removing local reference", "key", snapshot.Key, "error", err)
+		}
+		if err := s.store.DeleteDashboardSnapshot(ctx, &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: snapshot.DeleteKey}); err != nil {
+			return err
+		}
+		cmd.DeletedRows++
+		metrics.MDashboardSnapshotExpiredDeleted.WithLabelValues("external").Inc()
+	}
+
+	local := &dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
+	if err := s.store.DeleteExpiredSnapshots(ctx, local); err != nil {
+		return err
+	}
+	cmd.DeletedRows += local.DeletedRows
+	metrics.MDashboardSnapshotExpiredDeleted.WithLabelValues("local").Add(float64(local.DeletedRows))
+	return nil
+}
+
+func (s *ServiceImpl) GetRefreshSchedule(ctx context.Context, query *dashboardsnapshots.GetSnapshotRefreshScheduleQuery) (*dashboardsnapshots.SnapshotRefreshSchedule, error) {
+	return s.store.GetRefreshSchedule(ctx, query)
+}
+
+// SaveRefreshSchedule creates or replaces the refresh schedule of a local
+// snapshot. The snapshot is refreshed with the permissions of cmd.UserID.
+func (s *ServiceImpl) SaveRefreshSchedule(ctx context.Context, cmd *dashboardsnapshots.SaveSnapshotRefreshScheduleCommand) (*dashboardsnapshots.SnapshotRefreshSchedule, error) {
+	schedule, err := dashboardsnapshots.ParseRefreshSchedule(cmd.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.DashboardUID == "" {
+		return nil, dashboardsnapshots.ErrInvalidRefreshSchedule.Errorf("dashboard uid is required")
+	}
+
+	snapshot, err := s.store.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: cmd.SnapshotKey})
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.OrgID != cmd.OrgID {
+		return nil, dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot not found")
+	}
+	if snapshot.External {
+		return nil, dashboardsnapshots.ErrExternalSnapshotNotSupported.Errorf("external snapshots can not be refreshed")
+	}
+
+	cmd.NextRun = schedule.Next(time.Now()).Truncate(time.Second)
+	return s.store.SaveRefreshSchedule(ctx, cmd)
+}
+
+func (s *ServiceImpl) DeleteRefreshSchedule(ctx context.Context, cmd *dashboardsnapshots.DeleteSnapshotRefreshScheduleCommand) error {
+	return s.store.DeleteRefreshSchedule(ctx, cmd)
 }
@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -79,3 +82,51 @@ func TestDashboardSnapshotsService(t *testing.T) {
 		require.Equal(t, rawDashboard, decrypted)
 	})
 }
+
+func TestDeleteExpiredSnapshots(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	dsStore := dashsnapdb.ProvideStore(sqlStore, setting.NewCfg())
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	s := ProvideService(dsStore, secretsService)
+	ctx := context.Background()
+
+	deleted := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/snapshots-delete/broken" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		deleted[r.URL.Path] = true
+	}))
+	t.Cleanup(server.Close)
+
+	createExpired := func(key string, external bool, expired time.Duration) {
+		_, err := dsStore.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:               key,
+			DeleteKey:         "delete-" + key,
+			External:          external,
+			ExternalDeleteURL: server.URL + "/api/snapshots-delete/" + key,
+			OrgID:             1,
+		})
+		require.NoError(t, err)
+		err = sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Exec("UPDATE dashboard_snapshot SET expires = ? WHERE delete_key = ?", time.Now().Add(-expired), "delete-"+key)
+			return err
+		})
+		require.NoError(t, err)
+	}
+	createExpired("local", false, time.Hour)
+	createExpired("external", true, time.Hour)
+	createExpired("broken", true, time.Hour)
+
+	cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
+	require.NoError(t, s.DeleteExpiredSnapshots(ctx, &cmd))
+	require.Equal(t, int64(2), cmd.DeletedRows)
+	require.True(t, deleted["/api/snapshots-delete/external"])
+
+	// the snapshot that failed to delete is kept for a retry
+	_, err := dsStore.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "broken"})
+	require.NoError(t, err)
+	_, err = dsStore.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "external"})
+	require.Error(t, err)
+}
@@ -64,6 +64,20 @@ func (_m *MockService) DeleteExpiredSnapshots(_a0 context.Context, _a1 *DeleteEx
 	return r0
 }
 
+// DeleteRefreshSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) DeleteRefreshSchedule(_a0 context.Context, _a1 *DeleteSnapshotRefreshScheduleCommand) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteSnapshotRefreshScheduleCommand) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetDashboardSnapshot provides a mock function with given fields: _a0, _a1
 func (_m *MockService) GetDashboardSnapshot(_a0 context.Context, _a1 *GetDashboardSnapshotQuery) (*DashboardSnapshot, error) {
 	ret := _m.Called(_a0, _a1)
@@ -87,6 +101,52 @@ func (_m *MockService) GetDashboardSnapshot(_a0 context.Context, _a1 *GetDashboa
 	return r0, r1
 }
 
+// GetRefreshSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) GetRefreshSchedule(_a0 context.Context, _a1 *GetSnapshotRefreshScheduleQuery) (*SnapshotRefreshSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *SnapshotRefreshSchedule
+	if rf, ok := ret.Get(0).(func(context.Context, *GetSnapshotRefreshScheduleQuery) *SnapshotRefreshSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*SnapshotRefreshSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *GetSnapshotRefreshScheduleQuery) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveRefreshSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) SaveRefreshSchedule(_a0 context.Context, _a1 *SaveSnapshotRefreshScheduleCommand) (*SnapshotRefreshSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *SnapshotRefreshSchedule
+	if rf, ok := ret.Get(0).(func(context.Context, *SaveSnapshotRefreshScheduleCommand) *SnapshotRefreshSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*SnapshotRefreshSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *SaveSnapshotRefreshScheduleCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SearchDashboardSnapshots provides a mock function with given fields: _a0, _a1
 func (_m *MockService) SearchDashboardSnapshots(_a0 context.Context, _a1 *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error) {
 	ret := _m.Called(_a0, _a1)
@@ -110,6 +170,20 @@ func (_m *MockService) SearchDashboardSnapshots(_a0 context.Context, _a1 *GetDas
 	return r0, r1
 }
 
+// UpdateDashboardSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *MockService) UpdateDashboardSnapshot(_a0 context.Context, _a1 *UpdateDashboardSnapshotCommand) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *UpdateDashboardSnapshotCommand) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewMockService interface {
 	mock.TestingT
 	Cleanup(func())
@@ -9,5 +9,14 @@ type Store interface {
 	DeleteDashboardSnapshot(context.Context, *DeleteDashboardSnapshotCommand) error
 	DeleteExpiredSnapshots(context.Context, *DeleteExpiredSnapshotsCommand) error
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) (*DashboardSnapshot, error)
+	GetExpiredExternalSnapshots(context.Context, *GetExpiredExternalSnapshotsQuery) ([]*DashboardSnapshot, error)
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error)
+	UpdateDashboardSnapshot(context.Context, *UpdateDashboardSnapshotCommand) error
+
+	GetRefreshSchedule(context.Context, *GetSnapshotRefreshScheduleQuery) (*SnapshotRefreshSchedule, error)
+	SaveRefreshSchedule(context.Context, *SaveSnapshotRefreshScheduleCommand) (*SnapshotRefreshSchedule, error)
+	DeleteRefreshSchedule(context.Context, *DeleteSnapshotRefreshScheduleCommand) error
+	GetDueRefreshSchedules(context.Context, *GetDueSnapshotRefreshSchedulesQuery) ([]*SnapshotRefreshSchedule, error)
+	ClaimRefreshSchedule(context.Context, *ClaimSnapshotRefreshScheduleCommand) error
+	UpdateRefreshResult(context.Context, *UpdateSnapshotRefreshResultCommand) error
 }
@@ -16,8 +16,10 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -36,14 +38,19 @@ type Store interface {
 }
 
 type SqlStore struct {
-	db     db.DB
-	logger log.Logger
+	db       db.DB
+	logger   log.Logger
+	features featuremgmt.FeatureToggles
 }
 
 func CreateStore(db db.DB, logger log.Logger) *SqlStore {
 	return &SqlStore{db: db, logger: logger}
 }
 
+func (ss *SqlStore) emitEntityEvent() bool {
+	return ss.features != nil && ss.features.IsEnabledGlobally(featuremgmt.FlagPanelTitleSearch)
+}
+
 // GetDataSource adds a datasource to the query model by querying by org_id as well as
 // either uid (preferred), id, or name and is added to the bus.
 func (ss *SqlStore) GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) (*datasources.DataSource, error) {
@@ -159,6 +166,12 @@ func (ss *SqlStore) DeleteDataSource(ctx context.Context, cmd *datasources.Delet
 
 			cmd.DeletedDatasourcesCount, _ = result.RowsAffected()
 
+			if ss.emitEntityEvent() {
+				if _, err := sess.Insert(store.NewDatabaseEntityEvent(ds.UID, ds.OrgID, store.EntityTypeDataSource, store.EntityEventTypeDelete)); err != nil {
+					return err
+				}
+			}
+
 			// Remove associated AccessControl permissions
 			if _, errDeletingPerms := sess.Exec("DELETE FROM permission WHERE scope=?",
 				ac.Scope(datasources.ScopeProvider.GetResourceScope(ds.UID))); errDeletingPerms != nil {
@@ -288,6 +301,12 @@ func (ss *SqlStore) AddDataSource(ctx context.Context, cmd *datasources.AddDataS
 			return err
 		}
 
+		if ss.emitEntityEvent() {
+			if _, err := sess.Insert(store.NewDatabaseEntityEvent(ds.UID, ds.OrgID, store.EntityTypeDataSource, store.EntityEventTypeCreate)); err != nil {
+				return err
+			}
+		}
+
 		if cmd.UpdateSecretFn != nil {
 			if err := cmd.UpdateSecretFn(); err != nil {
 				// ss.logger.Error("Failed to update datasource secrets -- rolling back update", "name", cmd.Name, "type", cmd.Type, "orgId", cmd.OrgID)
@@ -381,6 +400,12 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 
 		err = updateIsDefaultFlag(ds, sess)
 
+		if ss.emitEntityEvent() {
+			if _, err := sess.Insert(store.NewDatabaseEntityEvent(ds.UID, ds.OrgID, store.EntityTypeDataSource, store.EntityEventTypeUpdate)); err != nil {
+				return err
+			}
+		}
+
 		if cmd.UpdateSecretFn != nil {
 			if err := cmd.UpdateSecretFn(); err != nil {
 				ss.logger.Error("Failed to update datasource secrets -- rolling back update", "UID", cmd.UID, "name", cmd.Name, "type", cmd.Type, "orgId", cmd.OrgID)
@@ -819,14 +819,31 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 			return err
 		}
 
+		// alert rules are deleted after the folders, so they can be kept
+		// with deleted folders until these are purged
+		if cmd.ForceDeleteRules {
+			alertRuleSrv, ok := s.registry[entity.StandardKindAlertRule]
+			if !ok {
+				return folder.ErrInternal.Errorf("no alert rule service found in registry")
+			}
+			if err := alertRuleSrv.DeleteInFolders(ctx, cmd.OrgID, folders, cmd.SignedInUser); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 
 	return err
 }
 
+// deleteChildrenInFolder deletes the entities in the folders except for alert
+// rules, which are deleted by Delete once the folders are gone.
 func (s *Service) deleteChildrenInFolder(ctx context.Context, orgID int64, folderUIDs []string, user identity.Requester) error {
-	for _, v := range s.registry {
+	for kind, v := range s.registry {
+		if kind == entity.StandardKindAlertRule {
+			continue
+		}
 		if err := v.DeleteInFolders(ctx, orgID, folderUIDs, user); err != nil {
 			return err
 		}
@@ -0,0 +1,158 @@
+package keyringprovider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const keySize = 32
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// keyringProvider encrypts data keys with AES-256-GCM using keys read from a
+// local key file. Each line of the file holds a key as <key-id>=<base64 key>,
+// lines starting with # are ignored. Keys are rotated by adding a new key to
+// the file and pointing current_key to it, previous keys are kept to decrypt
+// the data keys that have not been re-encrypted yet.
+type keyringProvider struct {
+	keys    map[string][]byte
+	current string
+}
+
+// New returns a provider for the [security.encryption.keyring.<name>] section.
+func New(_ secrets.ProviderID, section *setting.DynamicSection) (secrets.Provider, error) {
+	keyFile := section.Key("key_file").MustString("")
+	if keyFile == "" {
+		return nil, errors.New("key_file is required")
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `key_file` comes from grafana configuration file
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key_file: %w", err)
+	}
+
+	return newProvider(data, section.Key("current_key").MustString(""))
+}
+
+func newProvider(data []byte, current string) (*keyringProvider, error) {
+	keys, last, err := parseKeys(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if current == "" {
+		current = last
+	}
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("current key %q not found in key_file", current)
+	}
+
+	return &keyringProvider{keys: keys, current: current}, nil
+}
+
+func parseKeys(data []byte) (map[string][]byte, string, error) {
+	keys := make(map[string][]byte)
+	var last string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(line, "=")
+		id = strings.TrimSpace(id)
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, "", fmt.Errorf("invalid key on line %d: expected <key-id>=<base64 key>", n)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != keySize {
+			return nil, "", fmt.Errorf("invalid key %q: expected %d base64 encoded bytes", id, keySize)
+		}
+		if _, ok := keys[id]; ok {
+			return nil, "", fmt.Errorf("duplicated key %q", id)
+		}
+
+		keys[id] = key
+		last = id
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(keys) == 0 {
+		return nil, "", errors.New("no keys found in key_file")
+	}
+
+	return keys, last, nil
+}
+
+// Encrypt returns len(key id) | key id | nonce | ciphertext, the key id is
+// authenticated as additional data.
+func (p *keyringProvider) Encrypt(_ context.Context, blob []byte) ([]byte, error) {
+	gcm, err := newGCM(p.keys[p.current])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, 1+len(p.current)+len(nonce)+len(blob)+gcm.Overhead())
+	out = append(out, byte(len(p.current)))
+	out = append(out, p.current...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, blob, []byte(p.current)), nil
+}
+
+func (p *keyringProvider) Decrypt(_ context.Context, blob []byte) ([]byte, error) {
+	if len(blob) < 1 || len(blob) < 1+int(blob[0]) {
+		return nil, errors.New("malformed payload")
+	}
+
+	id := string(blob[1 : 1+int(blob[0])])
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in key_file", id)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := blob[1+len(id):]
+	if len(payload) < gcm.NonceSize() {
+		return nil, errors.New("malformed payload")
+	}
+
+	return gcm.Open(nil, payload[:gcm.NonceSize()], payload[gcm.NonceSize():], []byte(id))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
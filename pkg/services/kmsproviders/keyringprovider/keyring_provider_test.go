@@ -0,0 +1,85 @@
+package keyringprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	key1 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", keySize)))
+	key2 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("2", keySize)))
+)
+
+func TestKeyringProvider_EncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+
+	p, err := newProvider([]byte("# keys\nk1="+key1+"\n"), "")
+	require.NoError(t, err)
+
+	encrypted, err := p.Encrypt(ctx, []byte("data key"))
+	require.NoError(t, err)
+
+	decrypted, err := p.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data key"), decrypted)
+
+	t.Run("previous keys can still decrypt after a rotation", func(t *testing.T) {
+		rotated, err := newProvider([]byte("k1="+key1+"\nk2="+key2+"\n"), "")
+		require.NoError(t, err)
+		assert.Equal(t, "k2", rotated.current)
+
+		decrypted, err := rotated.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), decrypted)
+	})
+
+	t.Run("tampered key id is rejected", func(t *testing.T) {
+		other, err := newProvider([]byte("k2="+key1+"\n"), "")
+		require.NoError(t, err)
+
+		tampered := append([]byte{2, 'k', '2'}, encrypted[3:]...)
+		_, err = other.Decrypt(ctx, tampered)
+		require.Error(t, err)
+	})
+
+	t.Run("missing key is rejected", func(t *testing.T) {
+		other, err := newProvider([]byte("k2="+key2+"\n"), "")
+		require.NoError(t, err)
+
+		_, err = other.Decrypt(ctx, encrypted)
+		require.Error(t, err)
+	})
+}
+
+func TestKeyringProvider_New(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		data    string
+		current string
+		wantErr bool
+	}{
+		{desc: "uses the configured current key", data: "k1=" + key1 + "\nk2=" + key2, current: "k1"},
+		{desc: "no keys", data: "# empty", wantErr: true},
+		{desc: "unknown current key", data: "k1=" + key1, current: "k3", wantErr: true},
+		{desc: "invalid key size", data: "k1=" + base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+		{desc: "invalid line", data: "k1", wantErr: true},
+		{desc: "duplicated key", data: "k1=" + key1 + "\nk1=" + key2, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p, err := newProvider([]byte(tc.data), tc.current)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.current, p.current)
+		})
+	}
+}
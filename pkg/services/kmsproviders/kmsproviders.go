@@ -12,6 +12,14 @@ const (
 	// which fallbacks to Grafana's secret key. See the
 	// defaultprovider package for further information.
 	Default = "secretKey.v1"
+
+	// VaultKind is the kind of the providers backed by the
+	// HashiCorp Vault transit secrets engine, e.g. vault.v1
+	VaultKind = "vault"
+
+	// KeyringKind is the kind of the providers backed by a
+	// local file holding AES-256 keys, e.g. keyring.v1
+	KeyringKind = "keyring"
 )
 
 type Service interface {
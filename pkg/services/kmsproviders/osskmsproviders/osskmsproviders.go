@@ -1,18 +1,28 @@
 package osskmsproviders
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/kmsproviders"
 	grafana "github.com/grafana/grafana/pkg/services/kmsproviders/defaultprovider"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/keyringprovider"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/vaultprovider"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
+var errUnsupportedKind = errors.New("unsupported encryption provider kind")
+
 type Service struct {
 	enc      encryption.Internal
 	cfg      *setting.Cfg
 	features featuremgmt.FeatureToggles
+	log      log.Logger
 }
 
 func ProvideService(enc encryption.Internal, cfg *setting.Cfg, features featuremgmt.FeatureToggles) Service {
@@ -20,11 +30,56 @@ func ProvideService(enc encryption.Internal, cfg *setting.Cfg, features featurem
 		enc:      enc,
 		cfg:      cfg,
 		features: features,
+		log:      log.New("kmsproviders"),
 	}
 }
 
+// Provide returns the default secret key provider together with the providers listed in
+// [security] available_encryption_providers and the current encryption_provider.
+// Each of them is configured in its own [security.encryption.<provider>] section.
 func (s Service) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
-	return map[secrets.ProviderID]secrets.Provider{
+	providers := map[secrets.ProviderID]secrets.Provider{
 		kmsproviders.Default: grafana.New(s.cfg, s.enc),
-	}, nil
+	}
+
+	security := s.cfg.SectionWithEnvOverrides("security")
+	ids := util.SplitString(security.Key("available_encryption_providers").MustString(""))
+	ids = append(ids, security.Key("encryption_provider").MustString(kmsproviders.Default))
+
+	for _, rawID := range ids {
+		id := kmsproviders.NormalizeProviderID(secrets.ProviderID(rawID))
+		if _, ok := providers[id]; ok {
+			continue
+		}
+
+		provider, err := s.newProvider(id)
+		if errors.Is(err, errUnsupportedKind) {
+			// providers of other kinds may be configured for Grafana Enterprise
+			s.log.Warn("Skipping encryption provider", "provider", id, "error", err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure encryption provider %s: %w", id, err)
+		}
+		providers[id] = provider
+	}
+
+	return providers, nil
+}
+
+func (s Service) newProvider(id secrets.ProviderID) (secrets.Provider, error) {
+	kind, err := id.Kind()
+	if err != nil {
+		return nil, err
+	}
+
+	section := s.cfg.SectionWithEnvOverrides(fmt.Sprintf("security.encryption.%s", id))
+	switch kind {
+	case kmsproviders.VaultKind:
+		return vaultprovider.New(id, section)
+	case kmsproviders.KeyringKind:
+		return keyringprovider.New(id, section)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedKind, kind)
+	}
 }
@@ -0,0 +1,359 @@
+package vaultprovider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	AuthMethodToken   = "token"
+	AuthMethodAppRole = "approle"
+
+	// tokenExpiryLeeway is how long before its expiry an AppRole token is replaced
+	tokenExpiryLeeway = time.Minute
+)
+
+var errForbidden = errors.New("permission denied")
+
+// Settings configure a provider encrypting data keys with a key of the
+// HashiCorp Vault transit secrets engine.
+type Settings struct {
+	URL          string
+	Namespace    string
+	TransitMount string
+	KeyName      string
+
+	AuthMethod   string
+	Token        string
+	AppRoleMount string
+	RoleID       string
+	SecretID     string
+
+	CACertFile       string
+	Timeout          time.Duration
+	KeyCheckInterval time.Duration
+}
+
+func readSettings(section *setting.DynamicSection) (Settings, error) {
+	s := Settings{
+		URL:              strings.TrimSuffix(section.Key("url").MustString(""), "/"),
+		Namespace:        section.Key("namespace").MustString(""),
+		TransitMount:     section.Key("transit_mount").MustString("transit"),
+		KeyName:          section.Key("key_name").MustString(""),
+		AuthMethod:       section.Key("auth_method").In(AuthMethodToken, []string{AuthMethodToken, AuthMethodAppRole}),
+		Token:            section.Key("token").MustString(""),
+		AppRoleMount:     section.Key("approle_mount").MustString("approle"),
+		RoleID:           section.Key("role_id").MustString(""),
+		SecretID:         section.Key("secret_id").MustString(""),
+		CACertFile:       section.Key("ca_cert").MustString(""),
+		Timeout:          section.Key("timeout").MustDuration(10 * time.Second),
+		KeyCheckInterval: section.Key("key_check_interval").MustDuration(time.Hour),
+	}
+
+	if s.URL == "" {
+		return s, errors.New("url is required")
+	}
+	if s.KeyName == "" {
+		return s, errors.New("key_name is required")
+	}
+	if s.KeyCheckInterval <= 0 {
+		return s, errors.New("key_check_interval must be positive")
+	}
+
+	switch s.AuthMethod {
+	case AuthMethodToken:
+		if s.Token == "" {
+			return s, errors.New("token is required with the token auth method")
+		}
+	case AuthMethodAppRole:
+		if s.RoleID == "" || s.SecretID == "" {
+			return s, errors.New("role_id and secret_id are required with the approle auth method")
+		}
+	}
+
+	return s, nil
+}
+
+type vaultProvider struct {
+	id       secrets.ProviderID
+	settings Settings
+	client   *http.Client
+	log      log.Logger
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+
+	keyMu                sync.RWMutex
+	latestVersion        int
+	minDecryptionVersion int
+}
+
+var _ secrets.BackgroundProvider = new(vaultProvider)
+
+// New returns a provider for the [security.encryption.vault.<name>] section.
+func New(id secrets.ProviderID, section *setting.DynamicSection) (secrets.Provider, error) {
+	settings, err := readSettings(section)
+	if err != nil {
+		return nil, err
+	}
+	return newProvider(id, settings)
+}
+
+func newProvider(id secrets.ProviderID, settings Settings) (*vaultProvider, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if settings.CACertFile != "" {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `ca_cert` comes from grafana configuration file
+		caPEM, err := os.ReadFile(settings.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in ca_cert %q", settings.CACertFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &vaultProvider{
+		id:       id,
+		settings: settings,
+		client:   &http.Client{Transport: transport, Timeout: settings.Timeout},
+		log:      log.New("kmsproviders.vault", "provider", id),
+		token:    settings.Token,
+	}, nil
+}
+
+func (p *vaultProvider) Encrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(blob)}
+	if err := p.request(ctx, http.MethodPost, p.transitPath("encrypt"), body, &resp); err != nil {
+		return nil, fmt.Errorf("vault transit encrypt: %w", err)
+	}
+
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+func (p *vaultProvider) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	ciphertext := string(blob)
+	if version, ok := ciphertextVersion(ciphertext); ok {
+		p.keyMu.RLock()
+		minVersion := p.minDecryptionVersion
+		p.keyMu.RUnlock()
+
+		if version < minVersion {
+			return nil, fmt.Errorf("data key was encrypted with version %d of transit key %s which is below its min_decryption_version %d", version, p.settings.KeyName, minVersion)
+		}
+	}
+
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+
+	body := map[string]string{"ciphertext": ciphertext}
+	if err := p.request(ctx, http.MethodPost, p.transitPath("decrypt"), body, &resp); err != nil {
+		return nil, fmt.Errorf("vault transit decrypt: %w", err)
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+// Run periodically checks the versions of the transit key, so that rotations
+// are reported and data keys encrypted with a version that can no longer be
+// used are rejected with a meaningful error.
+func (p *vaultProvider) Run(ctx context.Context) error {
+	p.checkKey(ctx)
+
+	ticker := time.NewTicker(p.settings.KeyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.checkKey(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *vaultProvider) checkKey(ctx context.Context) {
+	var resp struct {
+		Data struct {
+			LatestVersion        int `json:"latest_version"`
+			MinDecryptionVersion int `json:"min_decryption_version"`
+		} `json:"data"`
+	}
+
+	if err := p.request(ctx, http.MethodGet, p.transitPath("keys"), nil, &resp); err != nil {
+		p.log.Warn("Failed to read transit key", "key", p.settings.KeyName, "error", err)
+		return
+	}
+
+	p.keyMu.Lock()
+	previous := p.latestVersion
+	p.latestVersion = resp.Data.LatestVersion
+	p.minDecryptionVersion = resp.Data.MinDecryptionVersion
+	p.keyMu.Unlock()
+
+	if previous != 0 && resp.Data.LatestVersion > previous {
+		p.log.Info("Transit key has been rotated, re-encrypt the data keys to use the latest version",
+			"key", p.settings.KeyName, "previous version", previous, "latest version", resp.Data.LatestVersion)
+	}
+}
+
+func (p *vaultProvider) transitPath(operation string) string {
+	return path.Join("/v1", p.settings.TransitMount, operation, url.PathEscape(p.settings.KeyName))
+}
+
+// request sends an authenticated request to Vault. A token obtained with AppRole
+// is replaced once when Vault rejects it, e.g. after it has been revoked.
+func (p *vaultProvider) request(ctx context.Context, method, urlPath string, body any, out any) error {
+	token, err := p.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = p.do(ctx, method, urlPath, token, body, out)
+	if errors.Is(err, errForbidden) && p.settings.AuthMethod == AuthMethodAppRole {
+		p.invalidateToken(token)
+		if token, err = p.getToken(ctx); err != nil {
+			return err
+		}
+		err = p.do(ctx, method, urlPath, token, body, out)
+	}
+
+	return err
+}
+
+func (p *vaultProvider) getToken(ctx context.Context) (string, error) {
+	if p.settings.AuthMethod == AuthMethodToken {
+		return p.settings.Token, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && (p.tokenExpiry.IsZero() || time.Now().Add(tokenExpiryLeeway).Before(p.tokenExpiry)) {
+		return p.token, nil
+	}
+
+	var resp struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+
+	body := map[string]string{"role_id": p.settings.RoleID, "secret_id": p.settings.SecretID}
+	if err := p.do(ctx, http.MethodPost, path.Join("/v1/auth", p.settings.AppRoleMount, "login"), "", body, &resp); err != nil {
+		return "", fmt.Errorf("vault approle login: %w", err)
+	}
+
+	p.token = resp.Auth.ClientToken
+	p.tokenExpiry = time.Time{}
+	if resp.Auth.LeaseDuration > 0 {
+		p.tokenExpiry = time.Now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second)
+	}
+
+	return p.token, nil
+}
+
+func (p *vaultProvider) invalidateToken(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token == token {
+		p.token = ""
+	}
+}
+
+func (p *vaultProvider) do(ctx context.Context, method, urlPath, token string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.settings.URL+urlPath, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if p.settings.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.settings.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			p.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&vaultErr)
+
+		if resp.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("%w: %s", errForbidden, strings.Join(vaultErr.Errors, ", "))
+		}
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.Join(vaultErr.Errors, ", "))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ciphertextVersion returns the key version of a "vault:v<version>:<data>" ciphertext.
+func ciphertextVersion(ciphertext string) (int, bool) {
+	parts := strings.SplitN(ciphertext, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" || !strings.HasPrefix(parts[1], "v") {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
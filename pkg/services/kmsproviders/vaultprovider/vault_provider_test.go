@@ -0,0 +1,202 @@
+package vaultprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeTransit is a minimal Vault transit engine wrapping the plaintext with the key version.
+type fakeTransit struct {
+	mu            sync.Mutex
+	tokens        map[string]bool
+	logins        int
+	latestVersion int
+	minVersion    int
+}
+
+func (f *fakeTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var body map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.logins++
+		token := fmt.Sprintf("approle-token-%d", f.logins)
+		f.tokens[token] = true
+		writeJSON(w, map[string]any{"auth": map[string]any{"client_token": token, "lease_duration": 3600}})
+		return
+	}
+
+	if !f.tokens[r.Header.Get("X-Vault-Token")] {
+		w.WriteHeader(http.StatusForbidden)
+		writeJSON(w, map[string]any{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/transit/encrypt/grafana":
+		writeJSON(w, map[string]any{"data": map[string]any{
+			"ciphertext": fmt.Sprintf("vault:v%d:%s", f.latestVersion, body["plaintext"]),
+		}})
+	case "/v1/transit/decrypt/grafana":
+		parts := strings.SplitN(body["ciphertext"], ":", 3)
+		writeJSON(w, map[string]any{"data": map[string]any{"plaintext": parts[2]}})
+	case "/v1/transit/keys/grafana":
+		writeJSON(w, map[string]any{"data": map[string]any{
+			"latest_version":         f.latestVersion,
+			"min_decryption_version": f.minVersion,
+		}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func setupVault(t *testing.T, settings Settings) (*vaultProvider, *fakeTransit) {
+	t.Helper()
+
+	transit := &fakeTransit{tokens: map[string]bool{"root-token": true}, latestVersion: 1, minVersion: 1}
+	server := httptest.NewServer(transit)
+	t.Cleanup(server.Close)
+
+	settings.URL = server.URL
+	settings.TransitMount = "transit"
+	settings.KeyName = "grafana"
+	settings.AppRoleMount = "approle"
+	settings.Timeout = time.Second
+	settings.KeyCheckInterval = time.Hour
+
+	p, err := newProvider("vault.v1", settings)
+	require.NoError(t, err)
+	return p, transit
+}
+
+func TestVaultProvider_EncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("with token auth", func(t *testing.T) {
+		p, _ := setupVault(t, Settings{AuthMethod: AuthMethodToken, Token: "root-token"})
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		assert.Equal(t, "vault:v1:"+base64.StdEncoding.EncodeToString([]byte("data key")), string(encrypted))
+
+		decrypted, err := p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), decrypted)
+	})
+
+	t.Run("with approle auth the token is reused and replaced once revoked", func(t *testing.T) {
+		p, transit := setupVault(t, Settings{AuthMethod: AuthMethodAppRole, RoleID: "role", SecretID: "secret"})
+
+		encrypted, err := p.Encrypt(ctx, []byte("data key"))
+		require.NoError(t, err)
+		_, err = p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, 1, transit.logins)
+
+		transit.mu.Lock()
+		transit.tokens = map[string]bool{}
+		transit.mu.Unlock()
+
+		decrypted, err := p.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data key"), decrypted)
+		assert.Equal(t, 2, transit.logins)
+	})
+
+	t.Run("with an invalid token", func(t *testing.T) {
+		p, _ := setupVault(t, Settings{AuthMethod: AuthMethodToken, Token: "invalid"})
+
+		_, err := p.Encrypt(ctx, []byte("data key"))
+		assert.ErrorIs(t, err, errForbidden)
+	})
+}
+
+func TestVaultProvider_KeyRotation(t *testing.T) {
+	ctx := context.Background()
+	p, transit := setupVault(t, Settings{AuthMethod: AuthMethodToken, Token: "root-token"})
+
+	encrypted, err := p.Encrypt(ctx, []byte("data key"))
+	require.NoError(t, err)
+
+	p.checkKey(ctx)
+	assert.Equal(t, 1, p.latestVersion)
+
+	transit.mu.Lock()
+	transit.latestVersion = 3
+	transit.minVersion = 2
+	transit.mu.Unlock()
+
+	p.checkKey(ctx)
+	assert.Equal(t, 3, p.latestVersion)
+	assert.Equal(t, 2, p.minDecryptionVersion)
+
+	_, err = p.Decrypt(ctx, encrypted)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "below its min_decryption_version")
+
+	encrypted, err = p.Encrypt(ctx, []byte("data key"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(encrypted), "vault:v3:"))
+}
+
+func TestReadSettings(t *testing.T) {
+	section := func(t *testing.T, values map[string]string) *setting.DynamicSection {
+		t.Helper()
+		s := setting.NewCfg().SectionWithEnvOverrides("security.encryption.vault.v1")
+		for k, v := range values {
+			s.Key(k).SetValue(v)
+		}
+		return s
+	}
+
+	t.Run("defaults the key check interval", func(t *testing.T) {
+		settings, err := readSettings(section(t, map[string]string{"url": "https://vault/", "key_name": "grafana", "token": "token"}))
+		require.NoError(t, err)
+		assert.Equal(t, "https://vault", settings.URL)
+		assert.Equal(t, time.Hour, settings.KeyCheckInterval)
+	})
+
+	t.Run("rejects a key check interval that is not positive", func(t *testing.T) {
+		for _, interval := range []string{"0s", "-1m"} {
+			_, err := readSettings(section(t, map[string]string{"url": "https://vault", "key_name": "grafana", "token": "token", "key_check_interval": interval}))
+			assert.Error(t, err, interval)
+		}
+	})
+}
+
+func TestCiphertextVersion(t *testing.T) {
+	version, ok := ciphertextVersion("vault:v12:abc")
+	assert.True(t, ok)
+	assert.Equal(t, 12, version)
+
+	_, ok = ciphertextVersion("abc")
+	assert.False(t, ok)
+
+	_, ok = ciphertextVersion("vault:vx:abc")
+	assert.False(t, ok)
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
 	"github.com/grafana/grafana/pkg/services/supportbundles/supportbundlestest"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
@@ -68,7 +70,7 @@ func setupAPITest(t *testing.T, opts ...func(a *Service)) (*Service, *webtest.Se
 		acimpl.ProvideAccessControl(cfg),
 		usertest.NewUserServiceFake(),
 		&authinfotest.FakeService{},
-		ldap.ProvideGroupsService(),
+		ldap.ProvideGroupsService(teamsynctest.NewFakeService()),
 		&authntest.FakeService{},
 		&orgtest.FakeOrgService{},
 		service.NewLDAPFakeService(),
@@ -315,6 +317,12 @@ func TestGetUserFromLDAPAPIEndpoint_WithTeamHandler(t *testing.T) {
 			},
 			ExpectedConfig: &ldap.Config{},
 		}
+		a.ldapGroupsService = ldap.ProvideGroupsService(&teamsynctest.FakeService{
+			Groups: []*teamsync.TeamGroupDTO{
+				{OrgID: 1, OrgName: "Main Org.", TeamID: 1, TeamName: "Admins", GroupID: "cn=admins,ou=groups,dc=grafana,dc=org"},
+				{OrgID: 2, OrgName: "Other Org.", TeamID: 2, TeamName: "Admins", GroupID: "cn=admins,ou=groups,dc=grafana,dc=org"},
+			},
+		})
 	})
 
 	req := server.NewGetRequest("/api/admin/ldap/johndoe")
@@ -349,7 +357,9 @@ func TestGetUserFromLDAPAPIEndpoint_WithTeamHandler(t *testing.T) {
 			"roles": [
 				{ "orgId": 1, "orgRole": "Admin", "orgName": "Main Org.", "groupDN": "cn=admins,ou=groups,dc=grafana,dc=org" }
 			],
-			"teams": null
+			"teams": [
+				{ "teamName": "Admins", "orgName": "Main Org.", "groupDN": "cn=admins,ou=groups,dc=grafana,dc=org" }
+			]
 		}
 	`
 
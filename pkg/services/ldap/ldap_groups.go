@@ -1,15 +1,38 @@
 package ldap
 
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
 type Groups interface {
 	GetTeams(groups []string, orgIDs []int64) ([]TeamOrgGroupDTO, error)
 }
 
-type OSSGroups struct{}
+// OSSGroups resolves the teams of an LDAP user from the group mappings
+// managed by team sync.
+type OSSGroups struct {
+	teamSync teamsync.Service
+}
 
-func ProvideGroupsService() *OSSGroups {
-	return &OSSGroups{}
+func ProvideGroupsService(teamSync teamsync.Service) *OSSGroups {
+	return &OSSGroups{teamSync: teamSync}
 }
 
-func (*OSSGroups) GetTeams(_ []string, _ []int64) ([]TeamOrgGroupDTO, error) {
-	return nil, nil
+func (g *OSSGroups) GetTeams(groups []string, orgIDs []int64) ([]TeamOrgGroupDTO, error) {
+	if len(groups) == 0 || len(orgIDs) == 0 {
+		return nil, nil
+	}
+
+	mappings, err := g.teamSync.GetTeamsByGroups(context.Background(), &teamsync.GetTeamsByGroupsQuery{OrgIDs: orgIDs, Groups: groups})
+	if err != nil {
+		return nil, err
+	}
+
+	var teams []TeamOrgGroupDTO
+	for _, m := range mappings {
+		teams = append(teams, TeamOrgGroupDTO{TeamName: m.TeamName, OrgName: m.OrgName, GroupDN: m.GroupID})
+	}
+	return teams, nil
 }
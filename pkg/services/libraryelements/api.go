@@ -3,6 +3,7 @@ package libraryelements
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -30,6 +31,11 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 			entities.Get("/:uid/connections/", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getConnectionsHandler))
 			entities.Get("/name/:name", routing.Wrap(l.getByNameHandler))
 			entities.Patch("/:uid", authorize(ac.EvalPermission(ActionLibraryPanelsWrite, uidScope)), routing.Wrap(l.patchHandler))
+			entities.Get("/:uid/versions", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getVersionsHandler))
+			entities.Get("/:uid/versions/:version", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.getVersionHandler))
+			entities.Post("/:uid/versions/:version/restore", authorize(ac.EvalPermission(ActionLibraryPanelsWrite, uidScope)), routing.Wrap(l.restoreVersionHandler))
+			entities.Get("/:uid/diff", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.diffHandler))
+			entities.Post("/:uid/impact", authorize(ac.EvalPermission(ActionLibraryPanelsRead, uidScope)), routing.Wrap(l.impactHandler))
 		} else {
 			entities.Post("/", routing.Wrap(l.createHandler))
 			entities.Delete("/:uid", routing.Wrap(l.deleteHandler))
@@ -38,6 +44,11 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 			entities.Get("/:uid/connections/", routing.Wrap(l.getConnectionsHandler))
 			entities.Get("/name/:name", routing.Wrap(l.getByNameHandler))
 			entities.Patch("/:uid", routing.Wrap(l.patchHandler))
+			entities.Get("/:uid/versions", routing.Wrap(l.getVersionsHandler))
+			entities.Get("/:uid/versions/:version", routing.Wrap(l.getVersionHandler))
+			entities.Post("/:uid/versions/:version/restore", routing.Wrap(l.restoreVersionHandler))
+			entities.Get("/:uid/diff", routing.Wrap(l.diffHandler))
+			entities.Post("/:uid/impact", routing.Wrap(l.impactHandler))
 		}
 	})
 }
@@ -301,6 +312,139 @@ func (l *LibraryElementService) getByNameHandler(c *contextmodel.ReqContext) res
 	}
 }
 
+// swagger:route GET /library-elements/{library_element_uid}/versions library_elements getLibraryElementVersions
+//
+// Get library element versions.
+//
+// Returns the version history of a library element, latest version first.
+//
+// Responses:
+// 200: getLibraryElementVersionsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) getVersionsHandler(c *contextmodel.ReqContext) response.Response {
+	versions, err := l.getLibraryElementVersions(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element versions")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionsResponse{Result: versions})
+}
+
+// swagger:route GET /library-elements/{library_element_uid}/versions/{version} library_elements getLibraryElementVersion
+//
+// Get library element version.
+//
+// Returns a version of a library element, including its model.
+//
+// Responses:
+// 200: getLibraryElementVersionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) getVersionHandler(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.ParseInt(web.Params(c.Req)[":version"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	result, err := l.getLibraryElementVersion(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], version)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element version")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementVersionResponse{Result: result})
+}
+
+// swagger:route POST /library-elements/{library_element_uid}/versions/{version}/restore library_elements restoreLibraryElementVersion
+//
+// Restore library element version.
+//
+// Saves the name and model of a previous version as the new version of a library element.
+//
+// Responses:
+// 200: getLibraryElementResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) restoreVersionHandler(c *contextmodel.ReqContext) response.Response {
+	version, err := strconv.ParseInt(web.Params(c.Req)[":version"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	element, err := l.restoreLibraryElementVersion(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], version)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to restore library element version")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementResponse{Result: element})
+}
+
+// swagger:route GET /library-elements/{library_element_uid}/diff library_elements diffLibraryElementVersions
+//
+// Compare library element versions.
+//
+// Returns the changes between two versions of a library element. By default the latest
+// version is compared with the version before it.
+//
+// Responses:
+// 200: getLibraryElementDiffResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) diffHandler(c *contextmodel.ReqContext) response.Response {
+	from := c.QueryInt64("from")
+	to := c.QueryInt64("to")
+	if from < 0 || to < 0 {
+		return response.Error(http.StatusBadRequest, "version is invalid", nil)
+	}
+
+	diff, err := l.diffLibraryElementVersions(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], from, to)
+	if err != nil {
+		return toLibraryElementError(err, "Failed to compare library element versions")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementDiffResponse{Result: diff})
+}
+
+// swagger:route POST /library-elements/{library_element_uid}/impact library_elements getLibraryElementImpact
+//
+// Get library element update impact.
+//
+// Returns the changes of an update of a library element, and the connected dashboards
+// they may break, without saving the update.
+//
+// Responses:
+// 200: getLibraryElementImpactResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (l *LibraryElementService) impactHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := model.PatchLibraryElementCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	impact, err := l.getLibraryElementImpact(c.Req.Context(), c.SignedInUser, cmd, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return toLibraryElementError(err, "Failed to get library element update impact")
+	}
+
+	return response.JSON(http.StatusOK, model.LibraryElementImpactResponse{Result: impact})
+}
+
 func (l *LibraryElementService) filterLibraryPanelsByPermission(c *contextmodel.ReqContext, elements []model.LibraryElementDTO) ([]model.LibraryElementDTO, error) {
 	filteredPanels := make([]model.LibraryElementDTO, 0)
 	for _, p := range elements {
@@ -323,6 +467,9 @@ func toLibraryElementError(err error, message string) response.Response {
 	if errors.Is(err, model.ErrLibraryElementNotFound) {
 		return response.Error(http.StatusNotFound, model.ErrLibraryElementNotFound.Error(), err)
 	}
+	if errors.Is(err, model.ErrLibraryElementVersionNotFound) {
+		return response.Error(http.StatusNotFound, model.ErrLibraryElementVersionNotFound.Error(), err)
+	}
 	if errors.Is(err, model.ErrLibraryElementDashboardNotFound) {
 		return response.Error(http.StatusNotFound, model.ErrLibraryElementDashboardNotFound.Error(), err)
 	}
@@ -347,7 +494,7 @@ func toLibraryElementError(err error, message string) response.Response {
 	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
 }
 
-// swagger:parameters getLibraryElementByUID getLibraryElementConnections
+// swagger:parameters getLibraryElementByUID getLibraryElementConnections getLibraryElementVersions
 type LibraryElementByUID struct {
 	// in:path
 	// required:true
@@ -445,6 +592,41 @@ type UpdateLibraryElementParam struct {
 	UID string `json:"library_element_uid"`
 }
 
+// swagger:parameters getLibraryElementVersion restoreLibraryElementVersion
+type LibraryElementVersionParams struct {
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+	// in:path
+	// required:true
+	Version int64 `json:"version"`
+}
+
+// swagger:parameters diffLibraryElementVersions
+type DiffLibraryElementVersionsParams struct {
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+	// The version to compare from, defaults to the version before to.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// The version to compare to, defaults to the latest version.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+}
+
+// swagger:parameters getLibraryElementImpact
+type GetLibraryElementImpactParams struct {
+	// in:body
+	// required:true
+	Body model.PatchLibraryElementCommand `json:"body"`
+	// in:path
+	// required:true
+	UID string `json:"library_element_uid"`
+}
+
 // swagger:response getLibraryElementsResponse
 type GetLibraryElementsResponse struct {
 	// in: body
@@ -468,3 +650,27 @@ type GetLibraryElementConnectionsResponse struct {
 	// in: body
 	Body model.LibraryElementConnectionsResponse `json:"body"`
 }
+
+// swagger:response getLibraryElementVersionsResponse
+type GetLibraryElementVersionsResponse struct {
+	// in: body
+	Body model.LibraryElementVersionsResponse `json:"body"`
+}
+
+// swagger:response getLibraryElementVersionResponse
+type GetLibraryElementVersionResponse struct {
+	// in: body
+	Body model.LibraryElementVersionResponse `json:"body"`
+}
+
+// swagger:response getLibraryElementDiffResponse
+type GetLibraryElementDiffResponse struct {
+	// in: body
+	Body model.LibraryElementDiffResponse `json:"body"`
+}
+
+// swagger:response getLibraryElementImpactResponse
+type GetLibraryElementImpactResponse struct {
+	// in: body
+	Body model.LibraryElementImpactResponse `json:"body"`
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -191,7 +192,10 @@ func (l *LibraryElementService) createLibraryElement(c context.Context, signedIn
 			}
 			return err
 		}
-		return nil
+		if err := insertLibraryElementVersion(session, element, ""); err != nil {
+			return err
+		}
+		return l.insertEntityEvent(session, element.OrgID, element.UID, store.EntityEventTypeCreate)
 	})
 
 	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
@@ -255,6 +259,9 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 			return model.ErrLibraryElementHasConnections
 		}
 
+		if _, err := session.Exec("DELETE FROM "+model.LibraryElementVersionTableName+" WHERE element_id=?", element.ID); err != nil {
+			return err
+		}
 		result, err := session.Exec("DELETE FROM library_element WHERE id=?", element.ID)
 		if err != nil {
 			return err
@@ -266,11 +273,20 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 		}
 
 		elementID = element.ID
-		return nil
+		return l.insertEntityEvent(session, element.OrgID, element.UID, store.EntityEventTypeDelete)
 	})
 	return elementID, err
 }
 
+// insertEntityEvent records a change to a library element so the search index picks it up.
+func (l *LibraryElementService) insertEntityEvent(session *db.Session, orgID int64, uid string, eventType store.EntityEventType) error {
+	if l.features == nil || !l.features.IsEnabledGlobally(featuremgmt.FlagPanelTitleSearch) {
+		return nil
+	}
+	_, err := session.Insert(store.NewDatabaseEntityEvent(uid, orgID, store.EntityTypeLibraryPanel, eventType))
+	return err
+}
+
 // getLibraryElements gets a Library Element where param == value
 func (l *LibraryElementService) getLibraryElements(c context.Context, store db.DB, cfg *setting.Cfg, signedInUser identity.Requester, params []Pair, features featuremgmt.FeatureToggles, cmd model.GetLibraryElementCommand) ([]model.LibraryElementDTO, error) {
 	libraryElements := make([]model.LibraryElementWithMeta, 0)
@@ -627,6 +643,17 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		} else if rowsAffected != 1 {
 			return model.ErrLibraryElementNotFound
 		}
+		if err := insertLibraryElementVersion(session, libraryElement, cmd.Message); err != nil {
+			return err
+		}
+		if updateUID != uid {
+			if err := l.insertEntityEvent(session, libraryElement.OrgID, uid, store.EntityEventTypeDelete); err != nil {
+				return err
+			}
+		}
+		if err := l.insertEntityEvent(session, libraryElement.OrgID, updateUID, store.EntityEventTypeUpdate); err != nil {
+			return err
+		}
 
 		metrics.MFolderIDsServiceCount.WithLabelValues(metrics.LibraryElements).Inc()
 		dto = model.LibraryElementDTO{
@@ -876,6 +903,10 @@ func (l *LibraryElementService) deleteLibraryElementsInFolderUID(c context.Conte
 			if err != nil {
 				return err
 			}
+			_, err = session.Exec("DELETE FROM "+model.LibraryElementVersionTableName+" WHERE element_id=?", elementID.ID)
+			if err != nil {
+				return err
+			}
 		}
 		if _, err := session.Exec("DELETE FROM library_element WHERE folder_id=? AND org_id=?", folderID, signedInUser.GetOrgID()); err != nil {
 			return err
@@ -0,0 +1,185 @@
+package libraryelements
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+)
+
+// diffModels returns the changes between two models of a library element, the
+// values of objects and arrays are compared recursively.
+func diffModels(from json.RawMessage, to json.RawMessage) ([]model.LibraryElementChange, error) {
+	var fromValue, toValue any
+	if len(from) > 0 {
+		if err := json.Unmarshal(from, &fromValue); err != nil {
+			return nil, err
+		}
+	}
+	if len(to) > 0 {
+		if err := json.Unmarshal(to, &toValue); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make([]model.LibraryElementChange, 0)
+	diffValues("", fromValue, toValue, &changes)
+	for i := range changes {
+		flagBreakingChange(&changes[i])
+	}
+	return changes, nil
+}
+
+func diffValues(path string, from any, to any, changes *[]model.LibraryElementChange) {
+	switch fromValue := from.(type) {
+	case map[string]any:
+		if toValue, ok := to.(map[string]any); ok {
+			keys := make([]string, 0, len(fromValue)+len(toValue))
+			for k := range fromValue {
+				keys = append(keys, k)
+			}
+			for k := range toValue {
+				if _, ok := fromValue[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				fieldPath := k
+				if path != "" {
+					fieldPath = path + "." + k
+				}
+				diffField(fieldPath, fromValue, toValue, k, changes)
+			}
+			return
+		}
+	case []any:
+		if toValue, ok := to.([]any); ok {
+			for i := 0; i < len(fromValue) || i < len(toValue); i++ {
+				itemPath := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(toValue):
+					*changes = append(*changes, model.LibraryElementChange{Path: itemPath, Type: model.LibraryElementChangeRemoved, Old: fromValue[i]})
+				case i >= len(fromValue):
+					*changes = append(*changes, model.LibraryElementChange{Path: itemPath, Type: model.LibraryElementChangeAdded, New: toValue[i]})
+				default:
+					diffValues(itemPath, fromValue[i], toValue[i], changes)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, model.LibraryElementChange{Path: path, Type: model.LibraryElementChangeChanged, Old: from, New: to})
+	}
+}
+
+func diffField(path string, from map[string]any, to map[string]any, key string, changes *[]model.LibraryElementChange) {
+	fromValue, inFrom := from[key]
+	toValue, inTo := to[key]
+	switch {
+	case !inTo:
+		*changes = append(*changes, model.LibraryElementChange{Path: path, Type: model.LibraryElementChangeRemoved, Old: fromValue})
+	case !inFrom:
+		*changes = append(*changes, model.LibraryElementChange{Path: path, Type: model.LibraryElementChangeAdded, New: toValue})
+	default:
+		diffValues(path, fromValue, toValue, changes)
+	}
+}
+
+var targetPathRegex = regexp.MustCompile(`^targets\[\d+\]$`)
+
+// flagBreakingChange flags the changes that may break the dashboards a library
+// panel is connected to: changed data sources and panel types, removed fields
+// and removed queries.
+func flagBreakingChange(change *model.LibraryElementChange) {
+	switch {
+	case isDatasourcePath(change.Path):
+		change.Breaking = true
+		change.Reason = "datasource changed"
+	case change.Path == "type":
+		change.Breaking = true
+		change.Reason = "type changed"
+	case change.Type == model.LibraryElementChangeRemoved && targetPathRegex.MatchString(change.Path):
+		change.Breaking = true
+		change.Reason = "query removed"
+	case change.Type == model.LibraryElementChangeRemoved && !strings.HasSuffix(change.Path, "]"):
+		change.Breaking = true
+		change.Reason = "field removed"
+	}
+}
+
+// isDatasourcePath returns true for the data source of a panel, or of its queries.
+func isDatasourcePath(path string) bool {
+	for _, field := range strings.Split(path, ".") {
+		if idx := strings.Index(field, "["); idx >= 0 {
+			field = field[:idx]
+		}
+		if field == "datasource" {
+			return true
+		}
+	}
+	return false
+}
+
+// variableRegex matches the template variables syntaxes: $var, ${var:format} and [[var]].
+var variableRegex = regexp.MustCompile(`\$(\w+)|\$\{(\w+)[^}]*\}|\[\[(\w+)[^\]]*\]\]`)
+
+// builtInVariables are provided by Grafana, dashboards don't define them. Other
+// built-in variables start with two underscores.
+var builtInVariables = map[string]bool{
+	"timeFilter": true,
+	"interval":   true,
+}
+
+// templateVariables returns the names of the template variables used in a model.
+func templateVariables(js json.RawMessage) map[string]bool {
+	variables := make(map[string]bool)
+	for _, match := range variableRegex.FindAllStringSubmatch(string(js), -1) {
+		for _, name := range match[1:] {
+			if name != "" && !strings.HasPrefix(name, "__") && !builtInVariables[name] {
+				variables[name] = true
+			}
+		}
+	}
+	return variables
+}
+
+// newTemplateVariables returns the names of the template variables that are
+// used in the updated model, and not in the current one.
+func newTemplateVariables(from json.RawMessage, to json.RawMessage) []string {
+	current := templateVariables(from)
+	variables := make([]string, 0)
+	for name := range templateVariables(to) {
+		if !current[name] {
+			variables = append(variables, name)
+		}
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+// dashboardVariables returns the names of the template variables a dashboard defines.
+func dashboardVariables(data []byte) map[string]bool {
+	var dashboard struct {
+		Templating struct {
+			List []struct {
+				Name string `json:"name"`
+			} `json:"list"`
+		} `json:"templating"`
+	}
+	variables := make(map[string]bool)
+	if err := json.Unmarshal(data, &dashboard); err != nil {
+		return variables
+	}
+	for _, variable := range dashboard.Templating.List {
+		variables[variable.Name] = true
+	}
+	return variables
+}
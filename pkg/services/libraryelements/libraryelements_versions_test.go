@@ -0,0 +1,205 @@
+package libraryelements
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/web"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibraryElementVersions(t *testing.T) {
+	scenarioWithPanel(t, "When an admin gets the versions of a library panel that was patched, it should return all versions",
+		func(t *testing.T, sc scenarioContext) {
+			patchLibraryPanelModel(t, sc, `{"datasource":"${DS_GDEV-TESTDATA}","id":1,"title":"Text - Library Panel","type":"text","description":"Updated"}`, "Update description")
+
+			resp := sc.service.getVersionsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementVersionsResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Len(t, result.Result, 2)
+			require.Equal(t, int64(2), result.Result[0].Version)
+			require.Equal(t, "Update description", result.Result[0].Message)
+			require.Equal(t, "Updated", result.Result[0].Description)
+			require.Nil(t, result.Result[0].Model)
+			require.Equal(t, int64(1), result.Result[1].Version)
+			require.Equal(t, "A description", result.Result[1].Description)
+		})
+
+	scenarioWithPanel(t, "When an admin gets a version of a library panel, it should return the model of the version",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "1"})
+			resp := sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementVersionResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, sc.initialResult.Result.UID, result.Result.ElementUID)
+			require.JSONEq(t, `{"datasource":"${DS_GDEV-TESTDATA}","id":1,"title":"Text - Library Panel","type":"text","description":"A description"}`, string(result.Result.Model))
+		})
+
+	scenarioWithPanel(t, "When an admin gets a version of a library panel that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "5"})
+			resp := sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "latest"})
+			resp = sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin compares the versions of a library panel, it should return the changes",
+		func(t *testing.T, sc scenarioContext) {
+			patchLibraryPanelModel(t, sc, `{"datasource":{"uid":"other"},"id":1,"title":"Text - Library Panel","type":"text"}`, "")
+
+			resp := sc.service.diffHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementDiffResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.Equal(t, int64(1), result.Result.From)
+			require.Equal(t, int64(2), result.Result.To)
+			require.Equal(t, []model.LibraryElementChange{
+				{
+					Path:     "datasource",
+					Type:     model.LibraryElementChangeChanged,
+					Old:      "${DS_GDEV-TESTDATA}",
+					New:      map[string]any{"uid": "other"},
+					Breaking: true,
+					Reason:   "datasource changed",
+				},
+			}, result.Result.Changes)
+		})
+
+	scenarioWithPanel(t, "When an admin restores a version of a library panel, it should save it as a new version",
+		func(t *testing.T, sc scenarioContext) {
+			patchLibraryPanelModel(t, sc, `{"datasource":{"uid":"other"},"id":1,"title":"Text - Library Panel","type":"text"}`, "")
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "1"})
+			resp := sc.service.restoreVersionHandler(sc.reqContext)
+			var result = validateAndUnMarshalResponse(t, resp)
+			require.Equal(t, int64(3), result.Result.Version)
+			require.Equal(t, "${DS_GDEV-TESTDATA}", result.Result.Model["datasource"])
+			require.Equal(t, "A description", result.Result.Description)
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID, ":version": "3"})
+			resp = sc.service.getVersionHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var version model.LibraryElementVersionResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &version))
+			require.Equal(t, "Restored from version 1", version.Result.Message)
+		})
+
+	scenarioWithPanel(t, "When an admin gets the impact of an update of a connected library panel, it should flag the dashboards it may break",
+		func(t *testing.T, sc scenarioContext) {
+			dashJSON := map[string]any{
+				"panels": []any{
+					map[string]any{
+						"id": int64(1),
+						"libraryPanel": map[string]any{
+							"uid":  sc.initialResult.Result.UID,
+							"name": sc.initialResult.Result.Name,
+						},
+					},
+				},
+				"templating": map[string]any{
+					"list": []any{
+						map[string]any{"name": "job"},
+					},
+				},
+			}
+			dash := dashboards.Dashboard{
+				Title: "Testing impactHandler",
+				Data:  simplejson.NewFromAny(dashJSON),
+			}
+			// nolint:staticcheck
+			dashInDB := createDashboard(t, sc.sqlStore, sc.user, &dash, sc.folder.ID)
+			err := sc.service.ConnectElementsToDashboard(sc.reqContext.Req.Context(), sc.reqContext.SignedInUser, []string{sc.initialResult.Result.UID}, dashInDB.ID)
+			require.NoError(t, err)
+
+			cmd := model.PatchLibraryElementCommand{
+				FolderID: -1, // nolint:staticcheck
+				Model:    []byte(`{"datasource":{"uid":"other"},"id":1,"title":"Text - $job - $instance","type":"text","description":"A description"}`),
+				Kind:     int64(model.PanelElement),
+				Version:  1,
+			}
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(cmd)
+			resp := sc.service.impactHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			var result model.LibraryElementImpactResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+			require.True(t, result.Result.Breaking)
+			require.Equal(t, []string{"instance", "job"}, result.Result.Variables)
+			require.Equal(t, int64(1), result.Result.ConnectedDashboards)
+			require.Len(t, result.Result.Dashboards, 1)
+			require.Equal(t, dashInDB.UID, result.Result.Dashboards[0].UID)
+			require.Equal(t, []string{"instance"}, result.Result.Dashboards[0].MissingVariables)
+			require.True(t, result.Result.Dashboards[0].Breaking)
+
+			// the update is not saved
+			resp = sc.service.getHandler(sc.reqContext)
+			var element = validateAndUnMarshalResponse(t, resp)
+			require.Equal(t, int64(1), element.Result.Version)
+		})
+
+	scenarioWithPanel(t, "When an admin gets the impact of an update with an outdated version, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			patchLibraryPanelModel(t, sc, `{"datasource":"${DS_GDEV-TESTDATA}","id":1,"title":"Text - Library Panel","type":"text","description":"Updated"}`, "")
+
+			cmd := model.PatchLibraryElementCommand{
+				FolderID: -1, // nolint:staticcheck
+				Model:    []byte(`{"id":1,"title":"Text - Library Panel","type":"text"}`),
+				Kind:     int64(model.PanelElement),
+				Version:  1,
+			}
+			sc.reqContext.Req.Body = mockRequestBody(cmd)
+			resp := sc.service.impactHandler(sc.reqContext)
+			require.Equal(t, 412, resp.Status())
+		})
+}
+
+func TestDiffModels(t *testing.T) {
+	changes, err := diffModels(
+		[]byte(`{"type":"timeseries","title":"A","targets":[{"expr":"up","datasource":{"uid":"a"}},{"expr":"rate(x[5m])"}],"options":{"legend":true}}`),
+		[]byte(`{"type":"timeseries","title":"B","targets":[{"expr":"up","datasource":{"uid":"b"}}],"options":{},"links":[]}`),
+	)
+	require.NoError(t, err)
+	require.Equal(t, []model.LibraryElementChange{
+		{Path: "links", Type: model.LibraryElementChangeAdded, New: []any{}},
+		{Path: "options.legend", Type: model.LibraryElementChangeRemoved, Old: true, Breaking: true, Reason: "field removed"},
+		{Path: "targets[0].datasource.uid", Type: model.LibraryElementChangeChanged, Old: "a", New: "b", Breaking: true, Reason: "datasource changed"},
+		{Path: "targets[1]", Type: model.LibraryElementChangeRemoved, Old: map[string]any{"expr": "rate(x[5m])"}, Breaking: true, Reason: "query removed"},
+		{Path: "title", Type: model.LibraryElementChangeChanged, Old: "A", New: "B"},
+	}, changes)
+
+	changes, err = diffModels([]byte(`{"type":"text"}`), []byte(`{"type":"text"}`))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestNewTemplateVariables(t *testing.T) {
+	variables := newTemplateVariables(
+		[]byte(`{"targets":[{"expr":"up{job=\"$job\"}[$__rate_interval]"}]}`),
+		[]byte(`{"targets":[{"expr":"up{job=\"$job\", env=\"${env:regex}\"}[$__rate_interval]"},{"target":"[[host]].cpu"},{"rawSql":"WHERE $timeFilter"}]}`),
+	)
+	require.Equal(t, []string{"env", "host"}, variables)
+}
+
+func patchLibraryPanelModel(t *testing.T, sc scenarioContext, panelModel string, message string) {
+	t.Helper()
+
+	cmd := model.PatchLibraryElementCommand{
+		FolderID: -1, // nolint:staticcheck
+		Model:    []byte(panelModel),
+		Kind:     int64(model.PanelElement),
+		Version:  1,
+		Message:  message,
+	}
+	sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+	sc.reqContext.Req.Body = mockRequestBody(cmd)
+	resp := sc.service.patchHandler(sc.reqContext)
+	require.Equal(t, 200, resp.Status())
+}
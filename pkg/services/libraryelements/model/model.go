@@ -63,6 +63,42 @@ type LibraryElementWithMeta struct {
 	UpdatedByEmail      string
 }
 
+// LibraryElementVersion is the model for the versions of library elements, each
+// save of a library element stores a version.
+type LibraryElementVersion struct {
+	ID        int64 `xorm:"pk autoincr 'id'"`
+	OrgID     int64 `xorm:"org_id"`
+	ElementID int64 `xorm:"element_id"`
+	Version   int64
+	// Deprecated: use FolderUID instead
+	FolderID    int64 `xorm:"folder_id"`
+	Name        string
+	Type        string
+	Description string
+	Model       json.RawMessage
+	Message     string
+
+	Created   time.Time
+	CreatedBy int64
+}
+
+// LibraryElementVersionWithMeta is the model used to retrieve versions with the user who saved them.
+type LibraryElementVersionWithMeta struct {
+	ID          int64 `xorm:"pk autoincr 'id'"`
+	ElementID   int64 `xorm:"element_id"`
+	Version     int64
+	Name        string
+	Type        string
+	Description string
+	Model       json.RawMessage
+	Message     string
+
+	Created        time.Time
+	CreatedBy      int64
+	CreatedByName  string
+	CreatedByEmail string
+}
+
 // LibraryElementDTO is the frontend DTO for entities.
 type LibraryElementDTO struct {
 	ID    int64 `json:"id"`
@@ -81,6 +117,73 @@ type LibraryElementDTO struct {
 	SchemaVersion int64                 `json:"schemaVersion,omitempty"`
 }
 
+// LibraryElementVersionDTO is the frontend DTO for versions of library elements.
+type LibraryElementVersionDTO struct {
+	ID          int64  `json:"id"`
+	ElementUID  string `json:"elementUid"`
+	Version     int64  `json:"version"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// Model is not set when listing versions
+	Model     json.RawMessage                        `json:"model,omitempty"`
+	Message   string                                 `json:"message"`
+	Created   time.Time                              `json:"created"`
+	CreatedBy librarypanel.LibraryElementDTOMetaUser `json:"createdBy"`
+}
+
+// LibraryElementChangeType is the type of a change between two models of a library element.
+type LibraryElementChangeType string
+
+const (
+	LibraryElementChangeAdded   LibraryElementChangeType = "added"
+	LibraryElementChangeRemoved LibraryElementChangeType = "removed"
+	LibraryElementChangeChanged LibraryElementChangeType = "changed"
+)
+
+// LibraryElementChange is a change between two models of a library element.
+type LibraryElementChange struct {
+	// Path of the changed value in the model, e.g. targets[0].datasource.uid
+	Path string                   `json:"path"`
+	Type LibraryElementChangeType `json:"type"`
+	Old  any                      `json:"old,omitempty"`
+	New  any                      `json:"new,omitempty"`
+	// Breaking is set for changes that may break the connected dashboards
+	Breaking bool   `json:"breaking"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// LibraryElementDiff is the difference between two versions of a library element.
+type LibraryElementDiff struct {
+	From    int64                  `json:"from"`
+	To      int64                  `json:"to"`
+	Changes []LibraryElementChange `json:"changes"`
+}
+
+// LibraryElementImpact is the impact of an update of a library element on the connected dashboards.
+type LibraryElementImpact struct {
+	// Version is the current version of the library element
+	Version  int64                  `json:"version"`
+	Changes  []LibraryElementChange `json:"changes"`
+	Breaking bool                   `json:"breaking"`
+	// Variables are the template variables the update starts to use
+	Variables []string `json:"variables"`
+	// ConnectedDashboards counts all connected dashboards, Dashboards only
+	// lists the ones the user can view
+	ConnectedDashboards int64                           `json:"connectedDashboards"`
+	Dashboards          []LibraryElementImpactDashboard `json:"dashboards"`
+}
+
+// LibraryElementImpactDashboard is a dashboard connected to a library element.
+type LibraryElementImpactDashboard struct {
+	UID       string `json:"uid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	// MissingVariables are the variables of the update the dashboard does not define
+	MissingVariables []string `json:"missingVariables"`
+	Breaking         bool     `json:"breaking"`
+}
+
 // LibraryElementSearchResult is the search result for entities.
 type LibraryElementSearchResult struct {
 	TotalCount int64               `json:"totalCount"`
@@ -155,6 +258,8 @@ var (
 	ErrLibraryElementInvalidUID = errors.New("uid contains illegal characters")
 	// errLibraryElementUIDTooLong is an error for when the uid of a library element is invalid
 	ErrLibraryElementUIDTooLong = errors.New("uid too long, max 40 characters")
+	// ErrLibraryElementVersionNotFound is an error for when a version of a library element can't be found.
+	ErrLibraryElementVersionNotFound = errors.New("library element version could not be found")
 )
 
 // Commands
@@ -205,6 +310,9 @@ type PatchLibraryElementCommand struct {
 	Version int64 `json:"version" binding:"Required"`
 	// required: false
 	UID string `json:"uid"`
+	// Message describing the change, stored in the version history.
+	// required: false
+	Message string `json:"message"`
 }
 
 // GetLibraryElementCommand is the command for getting a library element.
@@ -248,6 +356,26 @@ type LibraryElementConnectionsResponse struct {
 	Result []LibraryElementConnectionDTO `json:"result"`
 }
 
+// LibraryElementVersionsResponse is a response struct for an array of LibraryElementVersionDTO.
+type LibraryElementVersionsResponse struct {
+	Result []LibraryElementVersionDTO `json:"result"`
+}
+
+// LibraryElementVersionResponse is a response struct for LibraryElementVersionDTO.
+type LibraryElementVersionResponse struct {
+	Result LibraryElementVersionDTO `json:"result"`
+}
+
+// LibraryElementDiffResponse is a response struct for LibraryElementDiff.
+type LibraryElementDiffResponse struct {
+	Result LibraryElementDiff `json:"result"`
+}
+
+// LibraryElementImpactResponse is a response struct for LibraryElementImpact.
+type LibraryElementImpactResponse struct {
+	Result LibraryElementImpact `json:"result"`
+}
+
 // DeleteLibraryElementResponse is the response struct for deleting a library element.
 type DeleteLibraryElementResponse struct {
 	ID      int64  `json:"id"`
@@ -265,3 +393,5 @@ const (
 )
 
 const LibraryElementConnectionTableName = "library_element_connection"
+
+const LibraryElementVersionTableName = "library_element_version"
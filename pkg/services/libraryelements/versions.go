@@ -0,0 +1,269 @@
+package libraryelements
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/kinds/librarypanel"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func getFromLibraryElementVersionWithMeta(dialect migrator.Dialect, withModel bool) string {
+	sql := "SELECT lev.id, lev.element_id, lev.version, lev.name, lev.type, lev.description, lev.message, lev.created, lev.created_by"
+	if withModel {
+		sql += ", lev.model"
+	}
+	sql += ", u.login AS created_by_name, u.email AS created_by_email" +
+		" FROM " + model.LibraryElementVersionTableName + " AS lev" +
+		" LEFT JOIN " + dialect.Quote("user") + " AS u ON lev.created_by = u.id"
+	return sql
+}
+
+// insertLibraryElementVersion stores a saved library element in its version history.
+func insertLibraryElementVersion(session *db.Session, element model.LibraryElement, message string) error {
+	version := model.LibraryElementVersion{
+		OrgID:       element.OrgID,
+		ElementID:   element.ID,
+		Version:     element.Version,
+		FolderID:    element.FolderID, // nolint:staticcheck
+		Name:        element.Name,
+		Type:        element.Type,
+		Description: element.Description,
+		Model:       element.Model,
+		Message:     message,
+		Created:     element.Updated,
+		CreatedBy:   element.UpdatedBy,
+	}
+	_, err := session.Insert(&version)
+	return err
+}
+
+func (l *LibraryElementService) toLibraryElementVersionDTO(elementUID string, version model.LibraryElementVersionWithMeta) model.LibraryElementVersionDTO {
+	return model.LibraryElementVersionDTO{
+		ID:          version.ID,
+		ElementUID:  elementUID,
+		Version:     version.Version,
+		Name:        version.Name,
+		Type:        version.Type,
+		Description: version.Description,
+		Model:       version.Model,
+		Message:     version.Message,
+		Created:     version.Created,
+		CreatedBy: librarypanel.LibraryElementDTOMetaUser{
+			Id:        version.CreatedBy,
+			Name:      version.CreatedByName,
+			AvatarUrl: dtos.GetGravatarUrl(l.Cfg, version.CreatedByEmail),
+		},
+	}
+}
+
+// getLibraryElementVersions gets the version history of a Library Element, latest version first.
+func (l *LibraryElementService) getLibraryElementVersions(c context.Context, signedInUser identity.Requester, uid string) ([]model.LibraryElementVersionDTO, error) {
+	// getting the element checks that the user can read it
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]model.LibraryElementVersionWithMeta, 0)
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		sql := getFromLibraryElementVersionWithMeta(l.SQLStore.GetDialect(), false) +
+			" WHERE lev.element_id=? ORDER BY lev.version DESC"
+		return session.SQL(sql, element.ID).Find(&versions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.LibraryElementVersionDTO, 0, len(versions))
+	for _, version := range versions {
+		result = append(result, l.toLibraryElementVersionDTO(element.UID, version))
+	}
+	return result, nil
+}
+
+// getLibraryElementVersion gets a version of a Library Element.
+func (l *LibraryElementService) getLibraryElementVersion(c context.Context, signedInUser identity.Requester, uid string, version int64) (model.LibraryElementVersionDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryElementVersionDTO{}, err
+	}
+	return l.getVersionOfLibraryElement(c, element, version)
+}
+
+func (l *LibraryElementService) getVersionOfLibraryElement(c context.Context, element model.LibraryElementDTO, version int64) (model.LibraryElementVersionDTO, error) {
+	versions := make([]model.LibraryElementVersionWithMeta, 0)
+	err := l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		sql := getFromLibraryElementVersionWithMeta(l.SQLStore.GetDialect(), true) +
+			" WHERE lev.element_id=? AND lev.version=?"
+		return session.SQL(sql, element.ID, version).Find(&versions)
+	})
+	if err != nil {
+		return model.LibraryElementVersionDTO{}, err
+	}
+	if len(versions) == 0 {
+		return model.LibraryElementVersionDTO{}, model.ErrLibraryElementVersionNotFound
+	}
+
+	return l.toLibraryElementVersionDTO(element.UID, versions[0]), nil
+}
+
+// diffLibraryElementVersions compares two versions of a Library Element. The
+// latest version is used when to is 0, and the version before to when from is 0.
+func (l *LibraryElementService) diffLibraryElementVersions(c context.Context, signedInUser identity.Requester, uid string, from int64, to int64) (model.LibraryElementDiff, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryElementDiff{}, err
+	}
+	if to == 0 {
+		to = element.Version
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	fromVersion, err := l.getVersionOfLibraryElement(c, element, from)
+	if err != nil {
+		return model.LibraryElementDiff{}, err
+	}
+	toVersion, err := l.getVersionOfLibraryElement(c, element, to)
+	if err != nil {
+		return model.LibraryElementDiff{}, err
+	}
+
+	changes, err := diffModels(fromVersion.Model, toVersion.Model)
+	if err != nil {
+		return model.LibraryElementDiff{}, err
+	}
+	return model.LibraryElementDiff{From: from, To: to, Changes: changes}, nil
+}
+
+// restoreLibraryElementVersion saves the name and model of a previous version
+// as the new version of a Library Element.
+func (l *LibraryElementService) restoreLibraryElementVersion(c context.Context, signedInUser identity.Requester, uid string, version int64) (model.LibraryElementDTO, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+	restored, err := l.getVersionOfLibraryElement(c, element, version)
+	if err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+
+	cmd := model.PatchLibraryElementCommand{
+		FolderID: -1, // nolint:staticcheck
+		Name:     restored.Name,
+		Model:    restored.Model,
+		Kind:     element.Kind,
+		Version:  element.Version,
+		Message:  fmt.Sprintf("Restored from version %d", version),
+	}
+	if _, err := l.patchLibraryElement(c, signedInUser, cmd, uid); err != nil {
+		return model.LibraryElementDTO{}, err
+	}
+
+	return l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+}
+
+type connectedDashboard struct {
+	UID       string `xorm:"uid"`
+	Title     string `xorm:"title"`
+	FolderUID string `xorm:"folder_uid"`
+	Data      []byte `xorm:"data"`
+}
+
+// getLibraryElementImpact reports the changes of an update of a Library Element,
+// and the connected dashboards they may break, without saving the update.
+func (l *LibraryElementService) getLibraryElementImpact(c context.Context, signedInUser identity.Requester, cmd model.PatchLibraryElementCommand, uid string) (model.LibraryElementImpact, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		return model.LibraryElementImpact{}, err
+	}
+	if cmd.Version != 0 && cmd.Version != element.Version {
+		return model.LibraryElementImpact{}, model.ErrLibraryElementVersionMismatch
+	}
+
+	impact := model.LibraryElementImpact{
+		Version:             element.Version,
+		Changes:             []model.LibraryElementChange{},
+		Variables:           []string{},
+		ConnectedDashboards: element.Meta.ConnectedDashboards,
+		Dashboards:          []model.LibraryElementImpactDashboard{},
+	}
+
+	if cmd.Model != nil {
+		// the model is synced with the element as it would be when saved
+		updated := model.LibraryElement{
+			Name:        element.Name,
+			Kind:        element.Kind,
+			Type:        element.Type,
+			Description: element.Description,
+			Model:       cmd.Model,
+		}
+		if cmd.Name != "" {
+			updated.Name = cmd.Name
+		}
+		if err := syncFieldsWithModel(&updated); err != nil {
+			return model.LibraryElementImpact{}, err
+		}
+
+		impact.Changes, err = diffModels(element.Model, updated.Model)
+		if err != nil {
+			return model.LibraryElementImpact{}, err
+		}
+		for _, change := range impact.Changes {
+			impact.Breaking = impact.Breaking || change.Breaking
+		}
+		impact.Variables = newTemplateVariables(element.Model, updated.Model)
+	}
+
+	recursiveQueriesAreSupported, err := l.SQLStore.RecursiveQueriesAreSupported()
+	if err != nil {
+		return model.LibraryElementImpact{}, err
+	}
+	connected := make([]connectedDashboard, 0)
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		builder := db.NewSqlBuilder(l.Cfg, l.features, l.SQLStore.GetDialect(), recursiveQueriesAreSupported)
+		builder.Write("SELECT dashboard.uid, dashboard.title, COALESCE(dashboard.folder_uid, '') AS folder_uid, dashboard.data")
+		builder.Write(" FROM " + model.LibraryElementConnectionTableName + " AS lec")
+		builder.Write(" INNER JOIN dashboard AS dashboard on lec.connection_id = dashboard.id")
+		builder.Write(` WHERE lec.element_id=?`, element.ID)
+		if signedInUser.GetOrgRole() != org.RoleAdmin {
+			builder.WriteDashboardPermissionFilter(signedInUser, dashboardaccess.PERMISSION_VIEW, "")
+		}
+		return session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&connected)
+	})
+	if err != nil {
+		return model.LibraryElementImpact{}, err
+	}
+
+	breakingChanges := impact.Breaking
+	for _, dash := range connected {
+		variables := dashboardVariables(dash.Data)
+		missing := make([]string, 0)
+		for _, name := range impact.Variables {
+			if !variables[name] {
+				missing = append(missing, name)
+			}
+		}
+		impact.Dashboards = append(impact.Dashboards, model.LibraryElementImpactDashboard{
+			UID:              dash.UID,
+			Title:            dash.Title,
+			FolderUID:        dash.FolderUID,
+			MissingVariables: missing,
+			Breaking:         breakingChanges || len(missing) > 0,
+		})
+		if len(missing) > 0 {
+			impact.Breaking = true
+		}
+	}
+
+	return impact, nil
+}
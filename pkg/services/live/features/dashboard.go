@@ -3,6 +3,7 @@ package features
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/live/model"
@@ -24,6 +26,8 @@ const (
 	ActionDeleted  actionType = "deleted"
 	EditingStarted actionType = "editing-started"
 	//EditingFinished actionType = "editing-finished"
+	ActionLockAcquired actionType = "lock-acquired"
+	ActionLockReleased actionType = "lock-released"
 
 	GitopsChannel = "grafana/dashboard/gitops"
 )
@@ -36,6 +40,7 @@ type dashboardEvent struct {
 	SessionID string                `json:"sessionId,omitempty"`
 	Message   string                `json:"message,omitempty"`
 	Dashboard *dashboards.Dashboard `json:"dashboard,omitempty"`
+	Lock      *dashboardlock.Lock   `json:"lock,omitempty"`
 	Error     string                `json:"error,omitempty"`
 }
 
@@ -45,6 +50,7 @@ type DashboardHandler struct {
 	ClientCount      model.ChannelClientCount
 	Store            db.DB
 	DashboardService dashboards.DashboardService
+	LockService      dashboardlock.Service
 }
 
 // GetHandlerForPath called on init
@@ -83,10 +89,25 @@ func (h *DashboardHandler) OnSubscribe(ctx context.Context, user identity.Reques
 			return model.SubscribeReply{}, backend.SubscribeStreamStatusPermissionDenied, nil
 		}
 
-		return model.SubscribeReply{
+		reply := model.SubscribeReply{
 			Presence:  true,
 			JoinLeave: true,
-		}, backend.SubscribeStreamStatusOK, nil
+		}
+
+		// Tell the new subscriber who is editing the dashboard
+		if h.LockService != nil {
+			lock, err := h.LockService.GetLock(ctx, &dashboardlock.GetLockQuery{OrgID: user.GetOrgID(), DashboardUID: dash.UID})
+			if err == nil {
+				reply.Data, err = json.Marshal(dashboardEvent{UID: dash.UID, Action: ActionLockAcquired, Lock: lock})
+				if err != nil {
+					logger.Warn("Failed to marshal dashboard lock", "uid", dash.UID, "error", err)
+				}
+			} else if !errors.Is(err, dashboardlock.ErrLockNotFound) {
+				logger.Warn("Failed to get dashboard lock", "uid", dash.UID, "error", err)
+			}
+		}
+
+		return reply, backend.SubscribeStreamStatusOK, nil
 	}
 
 	// Unknown path
@@ -206,6 +227,25 @@ func (h *DashboardHandler) DashboardDeleted(orgID int64, user *user.UserDisplayD
 	})
 }
 
+// DashboardLockAcquired will broadcast to all connected dashboards
+func (h *DashboardHandler) DashboardLockAcquired(orgID int64, user *user.UserDisplayDTO, lock *dashboardlock.Lock) error {
+	return h.publish(orgID, dashboardEvent{
+		UID:    lock.DashboardUID,
+		Action: ActionLockAcquired,
+		User:   user,
+		Lock:   lock,
+	})
+}
+
+// DashboardLockReleased will broadcast to all connected dashboards
+func (h *DashboardHandler) DashboardLockReleased(orgID int64, user *user.UserDisplayDTO, uid string) error {
+	return h.publish(orgID, dashboardEvent{
+		UID:    uid,
+		Action: ActionLockReleased,
+		User:   user,
+	})
+}
+
 // HasGitOpsObserver will return true if anyone is listening to the `gitops` channel
 func (h *DashboardHandler) HasGitOpsObserver(orgID int64) bool {
 	count, err := h.ClientCount(orgID, GitopsChannel)
@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/middleware/requestmeta"
@@ -35,6 +36,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboardlock"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
@@ -79,7 +81,14 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	dataSourceCache datasources.CacheService, sqlStore db.DB, secretsService secrets.Service,
 	usageStatsService usagestats.Service, queryDataService query.Service, toggles featuremgmt.FeatureToggles,
 	accessControl accesscontrol.AccessControl, dashboardService dashboards.DashboardService, annotationsRepo annotations.Repository,
-	orgService org.Service) (*GrafanaLive, error) {
+	orgService org.Service, dashboardLockService dashboardlock.Service,
+	serverLockService *serverlock.ServerLockService) (*GrafanaLive, error) {
+	pipelineStorage := pipeline.NewSQLStorage(sqlStore, secretsService)
+	// Pipeline configuration was kept in files before, import it once.
+	fileStorage := &pipeline.FileStorage{DataPath: cfg.DataPath, SecretsService: secretsService}
+	if err := pipelineStorage.ImportFileStorage(context.Background(), fileStorage); err != nil {
+		logger.Error("Failed to import pipeline configuration from files", "error", err)
+	}
 	g := &GrafanaLive{
 		Cfg:                   cfg,
 		Features:              toggles,
@@ -98,6 +107,8 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		},
 		usageStatsService: usageStatsService,
 		orgService:        orgService,
+		serverLockService: serverLockService,
+		pipelineStorage:   pipelineStorage,
 	}
 
 	logger.Debug("GrafanaLive initialization", "ha", g.IsHA())
@@ -165,6 +176,25 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	g.ManagedStreamRunner = managedStreamRunner
 
+	if g.Cfg.LivePipelineEnabled {
+		builder := &pipeline.StorageRuleBuilder{
+			Node:                 node,
+			ManagedStream:        g.ManagedStreamRunner,
+			FrameStorage:         pipeline.NewFrameStorage(),
+			Storage:              pipelineStorage,
+			ChannelHandlerGetter: g,
+			SecretsService:       g.SecretsService,
+		}
+		g.Pipeline, err = pipeline.New(pipeline.NewCacheSegmentedTree(builder, g.Cfg.LivePipelineRulesCheckInterval))
+		if err != nil {
+			return nil, err
+		}
+		g.pipelineInputBuilder = &pipeline.StorageInputBuilder{
+			Storage:        pipelineStorage,
+			SecretsService: g.SecretsService,
+		}
+	}
+
 	g.contextGetter = liveplugin.NewContextGetter(g.PluginContextProvider, g.DataSourceCache)
 	pipelinedChannelLocalPublisher := liveplugin.NewChannelLocalPublisher(node, g.Pipeline)
 	numLocalSubscribersGetter := liveplugin.NewNumLocalSubscribersGetter(node)
@@ -176,6 +206,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		ClientCount:      g.ClientCount,
 		Store:            sqlStore,
 		DashboardService: dashboardService,
+		LockService:      dashboardLockService,
 	}
 	g.storage = database.NewStorage(g.SQLStore, g.CacheService)
 	g.GrafanaScope.Dashboards = dash
@@ -380,6 +411,7 @@ type GrafanaLive struct {
 	pluginClient          plugins.Client
 	queryDataService      query.Service
 	orgService            org.Service
+	serverLockService     *serverlock.ServerLockService
 
 	node         *centrifuge.Node
 	surveyCaller *survey.Caller
@@ -396,9 +428,10 @@ type GrafanaLive struct {
 	// The core internal features
 	GrafanaScope CoreGrafanaScope
 
-	ManagedStreamRunner *managedstream.Runner
-	Pipeline            *pipeline.Pipeline
-	pipelineStorage     pipeline.Storage
+	ManagedStreamRunner  *managedstream.Runner
+	Pipeline             *pipeline.Pipeline
+	pipelineStorage      pipeline.Storage
+	pipelineInputBuilder *pipeline.StorageInputBuilder
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -419,6 +452,12 @@ type DashboardActivityChannel interface {
 	// Called when a dashboard is deleted
 	DashboardDeleted(orgID int64, user *user.UserDisplayDTO, uid string) error
 
+	// Called when someone acquires, renews or takes over the edit lock of a dashboard
+	DashboardLockAcquired(orgID int64, user *user.UserDisplayDTO, lock *dashboardlock.Lock) error
+
+	// Called when the edit lock of a dashboard is released
+	DashboardLockReleased(orgID int64, user *user.UserDisplayDTO, uid string) error
+
 	// Experimental! Indicate is GitOps is active.  This really means
 	// someone is subscribed to the `grafana/dashboards/gitops` channel
 	HasGitOpsObserver(orgID int64) bool
@@ -459,7 +498,98 @@ func (g *GrafanaLive) Run(ctx context.Context) error {
 		})
 	}
 
-	return eGroup.Wait()
+	if g.pipelineInputBuilder != nil {
+		eGroup.Go(func() error {
+			return g.runPipelineInputs(eCtx)
+		})
+	}
+
+	err := eGroup.Wait()
+	if g.Pipeline != nil {
+		// Flush frames buffered by pipeline outputs.
+		g.Pipeline.Close()
+	}
+	return err
+}
+
+const (
+	// pipelineInputsLockName is the server lock which makes a single Grafana
+	// instance run pipeline inputs.
+	pipelineInputsLockName = "live pipeline inputs"
+	// pipelineInputsLease is how long the lock is valid. Owner releases and
+	// acquires it again before that, other instances take inputs over once
+	// the lease of an owner which is gone expires.
+	pipelineInputsLease             = time.Minute
+	pipelineInputsLockRetryInterval = 10 * time.Second
+)
+
+// runPipelineInputs connects the data inputs of all organizations and passes
+// the messages they receive to the pipeline until context is done. In HA
+// setups inputs are run by the instance holding the server lock only.
+func (g *GrafanaLive) runPipelineInputs(ctx context.Context) error {
+	manager := pipeline.NewInputManager(g.Pipeline, g.pipelineInputBuilder)
+	defer manager.Stop()
+
+	for {
+		owner := false
+		err := g.serverLockService.LockExecuteAndRelease(ctx, pipelineInputsLockName, pipelineInputsLease, func(ctx context.Context) {
+			owner = true
+			g.applyPipelineInputs(ctx, manager, pipelineInputsLease-pipelineInputsLockRetryInterval)
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if owner {
+			// Acquire the lock again right away to keep inputs running.
+			continue
+		}
+
+		var lockExistsErr *serverlock.ServerLockExistsError
+		if !errors.As(err, &lockExistsErr) {
+			logger.Error("Failed to acquire pipeline inputs lock", "error", err)
+		}
+		// Inputs are run by another instance.
+		manager.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pipelineInputsLockRetryInterval):
+		}
+	}
+}
+
+// applyPipelineInputs keeps inputs of all organizations running for the
+// lease duration. Inputs of organizations whose configuration revision
+// changed are restarted.
+func (g *GrafanaLive) applyPipelineInputs(ctx context.Context, manager *pipeline.InputManager, lease time.Duration) {
+	checkInterval := g.Cfg.LivePipelineRulesCheckInterval
+	if checkInterval <= 0 {
+		checkInterval = pipeline.DefaultRuleRevisionCheckInterval
+	}
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	leaseEnd := time.After(lease)
+
+	for {
+		orgs, err := g.orgService.Search(ctx, &org.SearchOrgsQuery{})
+		if err != nil {
+			logger.Error("Failed to list organizations for pipeline inputs", "error", err)
+		} else {
+			orgIDs := make([]int64, 0, len(orgs))
+			for _, o := range orgs {
+				orgIDs = append(orgIDs, o.ID)
+			}
+			manager.Apply(ctx, orgIDs)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-leaseEnd:
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 func getCheckOriginFunc(appURL *url.URL, originPatterns []string, originGlobs []glob.Glob) func(r *http.Request) bool {
@@ -1130,7 +1260,7 @@ func (g *GrafanaLive) HandlePipelineConvertTestHTTP(c *contextmodel.ReqContext)
 		Storage:              storage,
 		ChannelHandlerGetter: g,
 	}
-	channelRuleGetter := pipeline.NewCacheSegmentedTree(builder)
+	channelRuleGetter := pipeline.NewCacheSegmentedTree(builder, g.Cfg.LivePipelineRulesCheckInterval)
 	pipe, err := pipeline.New(channelRuleGetter)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Error creating pipeline", err)
@@ -1190,6 +1320,9 @@ func (g *GrafanaLive) HandleChannelRulesPutHTTP(c *contextmodel.ReqContext) resp
 	}
 	rule, err := g.pipelineStorage.UpdateChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrVersionMismatch) {
+			return response.Error(http.StatusConflict, "Failed to update channel rule", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to update channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -1213,6 +1346,9 @@ func (g *GrafanaLive) HandleChannelRulesDeleteHTTP(c *contextmodel.ReqContext) r
 	}
 	err = g.pipelineStorage.DeleteChannelRule(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrChannelRuleNotFound) {
+			return response.Error(http.StatusNotFound, "Failed to delete channel rule", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to delete channel rule", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{})
@@ -1226,6 +1362,7 @@ func (g *GrafanaLive) HandlePipelineEntitiesListHTTP(_ *contextmodel.ReqContext)
 		"converters":      pipeline.ConvertersRegistry,
 		"frameProcessors": pipeline.FrameProcessorsRegistry,
 		"frameOutputs":    pipeline.FrameOutputsRegistry,
+		"inputs":          pipeline.InputsRegistry,
 	})
 }
 
@@ -1301,6 +1438,9 @@ func (g *GrafanaLive) HandleWriteConfigsPutHTTP(c *contextmodel.ReqContext) resp
 	}
 	result, err := g.pipelineStorage.UpdateWriteConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrVersionMismatch) {
+			return response.Error(http.StatusConflict, "Failed to update write config", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to update write config", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -1324,6 +1464,9 @@ func (g *GrafanaLive) HandleWriteConfigsDeleteHTTP(c *contextmodel.ReqContext) r
 	}
 	err = g.pipelineStorage.DeleteWriteConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), cmd)
 	if err != nil {
+		if errors.Is(err, pipeline.ErrWriteConfigNotFound) {
+			return response.Error(http.StatusNotFound, "Failed to delete write config", err)
+		}
 		return response.Error(http.StatusInternalServerError, "Failed to delete write config", err)
 	}
 	return response.JSON(http.StatusOK, util.DynMap{})
@@ -0,0 +1,212 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBatchMaxSize       = 1000
+	defaultBatchFlushInterval = time.Second
+	defaultBatchMaxRetries    = 3
+	defaultBatchMaxBufferSize = 100000
+
+	batchRetryBackoff    = 200 * time.Millisecond
+	batchMaxRetryBackoff = 5 * time.Second
+	batchFlushTimeout    = 10 * time.Second
+)
+
+type batchEntry struct {
+	key   []byte
+	value []byte
+	time  time.Time
+}
+
+// permanentError marks flush errors which won't go away on retry, batch
+// is dropped in this case.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+func isPermanentError(err error) bool {
+	var permanentErr permanentError
+	return errors.As(err, &permanentErr)
+}
+
+// batchWriter buffers entries and flushes them in batches from a background
+// goroutine. Failed batches are retried with backoff and kept in buffer if
+// retries are exhausted, buffer size is limited, the oldest entries are
+// dropped on overflow. Background goroutine exits when there is nothing to
+// flush, so writer of an unused output does not leak.
+type batchWriter struct {
+	name          string
+	maxSize       int
+	flushInterval time.Duration
+	maxRetries    int
+	maxBufferSize int
+
+	flush func(ctx context.Context, entries []batchEntry) error
+	// idle is called when writer has nothing more to flush.
+	idle func()
+
+	mu      sync.Mutex
+	buffer  []batchEntry
+	running bool
+	full    chan struct{}
+}
+
+func newBatchWriter(name string, config *BatchConfig, flush func(ctx context.Context, entries []batchEntry) error) *batchWriter {
+	w := &batchWriter{
+		name:          name,
+		maxSize:       defaultBatchMaxSize,
+		flushInterval: defaultBatchFlushInterval,
+		maxRetries:    defaultBatchMaxRetries,
+		maxBufferSize: defaultBatchMaxBufferSize,
+		flush:         flush,
+		full:          make(chan struct{}, 1),
+	}
+	if config != nil {
+		if config.MaxSize > 0 {
+			w.maxSize = config.MaxSize
+		}
+		if config.FlushIntervalMilliseconds > 0 {
+			w.flushInterval = time.Duration(config.FlushIntervalMilliseconds) * time.Millisecond
+		}
+		if config.MaxRetries > 0 {
+			w.maxRetries = config.MaxRetries
+		}
+		if config.MaxBufferSize > 0 {
+			w.maxBufferSize = config.MaxBufferSize
+		}
+	}
+	return w
+}
+
+func (w *batchWriter) write(entries ...batchEntry) {
+	w.mu.Lock()
+	w.buffer = append(w.buffer, entries...)
+	w.trimLocked()
+	full := len(w.buffer) >= w.maxSize
+	if !w.running {
+		w.running = true
+		go w.run()
+	}
+	w.mu.Unlock()
+	if full {
+		select {
+		case w.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// trimLocked drops the oldest entries exceeding buffer size limit.
+func (w *batchWriter) trimLocked() {
+	if overflow := len(w.buffer) - w.maxBufferSize; overflow > 0 {
+		logger.Warn("Output buffer is full, dropping oldest entries", "output", w.name, "numDropped", overflow)
+		w.buffer = w.buffer[overflow:]
+	}
+}
+
+func (w *batchWriter) run() {
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.full:
+		}
+		w.mu.Lock()
+		if len(w.buffer) == 0 {
+			w.running = false
+			// Called under lock, so a new run started by write can't use
+			// resources while idle releases them.
+			if w.idle != nil {
+				w.idle()
+			}
+			w.mu.Unlock()
+			return
+		}
+		w.mu.Unlock()
+		w.flushBuffer()
+	}
+}
+
+// close flushes buffered entries and calls idle unless background goroutine
+// is running, it calls idle itself then. Entries written after close are
+// flushed by a new background goroutine.
+func (w *batchWriter) close() {
+	w.flushBuffer()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.running && w.idle != nil {
+		w.idle()
+	}
+}
+
+// flushBuffer sends buffered entries batch by batch until buffer is empty
+// or a batch fails to be sent.
+func (w *batchWriter) flushBuffer() {
+	for {
+		w.mu.Lock()
+		n := len(w.buffer)
+		if n == 0 {
+			w.mu.Unlock()
+			return
+		}
+		if n > w.maxSize {
+			n = w.maxSize
+		}
+		batch := make([]batchEntry, n)
+		copy(batch, w.buffer)
+		w.buffer = w.buffer[n:]
+		w.mu.Unlock()
+
+		err := w.flushWithRetries(batch)
+		if err == nil {
+			continue
+		}
+		if isPermanentError(err) {
+			logger.Error("Error flushing output batch, dropping it", "output", w.name, "error", err, "numEntries", len(batch))
+			continue
+		}
+		logger.Error("Error flushing output batch", "output", w.name, "error", err, "numEntries", len(batch))
+		w.mu.Lock()
+		w.buffer = append(batch, w.buffer...)
+		w.trimLocked()
+		w.mu.Unlock()
+		return
+	}
+}
+
+func (w *batchWriter) flushWithRetries(batch []batchEntry) error {
+	backoff := batchRetryBackoff
+	var err error
+	for attempt := 0; attempt <= w.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > batchMaxRetryBackoff {
+				backoff = batchMaxRetryBackoff
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), batchFlushTimeout)
+		err = w.flush(ctx, batch)
+		cancel()
+		if err == nil || isPermanentError(err) {
+			return err
+		}
+		logger.Debug("Output batch flush attempt failed", "output", w.name, "attempt", attempt+1, "error", err)
+	}
+	return err
+}
@@ -32,6 +32,7 @@ type ChannelRule struct {
 	OrgId    int64               `json:"-"`
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	Version  int64               `json:"version,omitempty"`
 }
 
 type ConverterConfig struct {
@@ -50,11 +51,44 @@ type KeepFieldsFrameProcessorConfig struct {
 	FieldNames []string `json:"fieldNames"`
 }
 
+type CalculateFieldFrameProcessorConfig struct {
+	FieldName  string            `json:"fieldName"`
+	Expression string            `json:"expression"`
+	Config     *data.FieldConfig `json:"config,omitempty" ts_type:"FieldConfig"`
+}
+
+type RenameFieldsFrameProcessorConfig struct {
+	// Renames is a map of current field name to a new field name.
+	Renames map[string]string `json:"renames"`
+}
+
+type CastFieldsFrameProcessorConfig struct {
+	// Fields is a map of field name to a type to convert field values to.
+	Fields map[string]CastFieldType `json:"fields"`
+}
+
+type DownsampleFrameProcessorConfig struct {
+	IntervalMilliseconds int64                 `json:"intervalMilliseconds"`
+	Aggregation          DownsampleAggregation `json:"aggregation,omitempty"`
+	// MaxBufferedRows limits rows buffered in all windows, frames exceeding
+	// the limit are dropped. Defaults to 10000.
+	MaxBufferedRows int `json:"maxBufferedRows,omitempty"`
+}
+
+type RateLimitFrameProcessorConfig struct {
+	MaxFramesPerSecond float64 `json:"maxFramesPerSecond"`
+}
+
 type FrameProcessorConfig struct {
-	Type                      string                          `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
-	DropFieldsProcessorConfig *DropFieldsFrameProcessorConfig `json:"dropFields,omitempty"`
-	KeepFieldsProcessorConfig *KeepFieldsFrameProcessorConfig `json:"keepFields,omitempty"`
-	MultipleProcessorConfig   *MultipleFrameProcessorConfig   `json:"multiple,omitempty"`
+	Type                          string                              `json:"type" ts_type:"Omit<keyof FrameProcessorConfig, 'type'>"`
+	DropFieldsProcessorConfig     *DropFieldsFrameProcessorConfig     `json:"dropFields,omitempty"`
+	KeepFieldsProcessorConfig     *KeepFieldsFrameProcessorConfig     `json:"keepFields,omitempty"`
+	MultipleProcessorConfig       *MultipleFrameProcessorConfig       `json:"multiple,omitempty"`
+	CalculateFieldProcessorConfig *CalculateFieldFrameProcessorConfig `json:"calculate,omitempty"`
+	RenameFieldsProcessorConfig   *RenameFieldsFrameProcessorConfig   `json:"renameFields,omitempty"`
+	CastFieldsProcessorConfig     *CastFieldsFrameProcessorConfig     `json:"castFields,omitempty"`
+	DownsampleProcessorConfig     *DownsampleFrameProcessorConfig     `json:"downsample,omitempty"`
+	RateLimitProcessorConfig      *RateLimitFrameProcessorConfig      `json:"rateLimit,omitempty"`
 }
 
 type MultipleFrameProcessorConfig struct {
@@ -79,6 +113,43 @@ type LokiOutputConfig struct {
 	UID string `json:"uid"`
 }
 
+// BatchConfig controls batching of writes to external systems.
+type BatchConfig struct {
+	// MaxSize is a maximum number of entries sent in one request, 1000 by default.
+	MaxSize int `json:"maxSize,omitempty"`
+	// FlushIntervalMilliseconds is an interval to flush buffered entries, 1000 by default.
+	FlushIntervalMilliseconds int64 `json:"flushIntervalMilliseconds,omitempty"`
+	// MaxRetries is a number of retries of a failed request, 3 by default.
+	MaxRetries int `json:"maxRetries,omitempty"`
+	// MaxBufferSize limits number of entries kept in memory while endpoint
+	// is unavailable, 100000 by default. The oldest entries are dropped on overflow.
+	MaxBufferSize int `json:"maxBufferSize,omitempty"`
+}
+
+type InfluxOutputConfig struct {
+	UID string `json:"uid"`
+	// Measurement is an optional measurement name, frame name is used by default.
+	Measurement string       `json:"measurement,omitempty"`
+	Batch       *BatchConfig `json:"batch,omitempty"`
+}
+
+type KafkaFormat string
+
+const (
+	// KafkaFormatJSON encodes frame to JSON.
+	KafkaFormatJSON KafkaFormat = "json"
+	// KafkaFormatProtobuf encodes frame to Prometheus remote write protobuf message.
+	KafkaFormatProtobuf KafkaFormat = "protobuf"
+)
+
+type KafkaOutputConfig struct {
+	UID   string `json:"uid"`
+	Topic string `json:"topic"`
+	// Format of message value, json by default.
+	Format KafkaFormat  `json:"format,omitempty"`
+	Batch  *BatchConfig `json:"batch,omitempty"`
+}
+
 type MultipleSubscriberConfig struct {
 	Subscribers []SubscriberConfig `json:"subscribers"`
 }
@@ -109,6 +180,8 @@ type FrameOutputterConfig struct {
 	RemoteWriteOutputConfig *RemoteWriteOutputConfig   `json:"remoteWrite,omitempty"`
 	LokiOutputConfig        *LokiOutputConfig          `json:"loki,omitempty"`
 	ChangeLogOutputConfig   *ChangeLogOutputConfig     `json:"changeLog,omitempty"`
+	InfluxOutputConfig      *InfluxOutputConfig        `json:"influx,omitempty"`
+	KafkaOutputConfig       *KafkaOutputConfig         `json:"kafka,omitempty"`
 }
 
 type MultipleFrameConditionCheckerConfig struct {
@@ -153,3 +226,47 @@ type AutoInfluxConverterConfig struct {
 type JsonFrameConverterConfig struct{}
 
 type ManagedStreamOutputConfig struct{}
+
+type MQTTTopicConfig struct {
+	// Topic is an MQTT topic filter, may contain + and # wildcards.
+	Topic string `json:"topic"`
+	QoS   byte   `json:"qos,omitempty"`
+	// Channel to process messages with. {topic} placeholder is replaced
+	// with the topic name of a received message.
+	Channel string `json:"channel"`
+}
+
+type MQTTInputConfig struct {
+	URL      string            `json:"url"`
+	ClientID string            `json:"clientId,omitempty"`
+	Username string            `json:"username,omitempty"`
+	Topics   []MQTTTopicConfig `json:"topics"`
+}
+
+type NATSSubjectConfig struct {
+	// Subject to subscribe to, may contain * and > wildcards.
+	Subject string `json:"subject"`
+	// Queue is an optional queue group name, useful to share messages
+	// between Grafana instances in HA setup.
+	Queue string `json:"queue,omitempty"`
+	// Channel to process messages with. {topic} placeholder is replaced
+	// with the subject of a received message with dots converted to slashes.
+	Channel string `json:"channel"`
+}
+
+type NATSInputConfig struct {
+	URL      string              `json:"url"`
+	Name     string              `json:"name,omitempty"`
+	Username string              `json:"username,omitempty"`
+	Subjects []NATSSubjectConfig `json:"subjects"`
+}
+
+// InputConfig describes a data source which pushes data into pipeline channels.
+type InputConfig struct {
+	OrgId           int64             `json:"-"`
+	UID             string            `json:"uid"`
+	Type            string            `json:"type"`
+	MQTTInputConfig *MQTTInputConfig  `json:"mqtt,omitempty"`
+	NATSInputConfig *NATSInputConfig  `json:"nats,omitempty"`
+	SecureSettings  map[string][]byte `json:"secureSettings,omitempty"`
+}
@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InputProcessor processes raw data published into a channel. Implemented by Pipeline.
+type InputProcessor interface {
+	ProcessInput(ctx context.Context, orgID int64, channelID string, body []byte) (bool, error)
+}
+
+// DataInput receives data from an external system (like message broker)
+// and passes it to InputProcessor. Run should block until context is
+// done or connection to the external system is lost.
+type DataInput interface {
+	Type() string
+	Run(ctx context.Context, processor InputProcessor) error
+}
+
+const (
+	inputMinReconnectDelay = time.Second
+	inputMaxReconnectDelay = 30 * time.Second
+)
+
+// InputRunner runs a set of DataInputs and reconnects them with backoff
+// upon errors.
+type InputRunner struct {
+	processor InputProcessor
+	inputs    []DataInput
+}
+
+func NewInputRunner(processor InputProcessor, inputs ...DataInput) *InputRunner {
+	return &InputRunner{processor: processor, inputs: inputs}
+}
+
+// Run inputs until context is done.
+func (r *InputRunner) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, in := range r.inputs {
+		wg.Add(1)
+		go func(in DataInput) {
+			defer wg.Done()
+			r.runInput(ctx, in)
+		}(in)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (r *InputRunner) runInput(ctx context.Context, in DataInput) {
+	delay := inputMinReconnectDelay
+	for {
+		started := time.Now()
+		err := in.Run(ctx, r.processor)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > inputMaxReconnectDelay {
+			// Connection was healthy for a while, start backoff from scratch.
+			delay = inputMinReconnectDelay
+		}
+		logger.Error("Pipeline input stopped, reconnecting", "type", in.Type(), "error", err, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > inputMaxReconnectDelay {
+			delay = inputMaxReconnectDelay
+		}
+	}
+}
+
+const inputTopicPlaceholder = "{topic}"
+
+// inputChannel builds channel for a message received from topic.
+func inputChannel(channelTemplate string, topic string) string {
+	return strings.ReplaceAll(channelTemplate, inputTopicPlaceholder, topic)
+}
+
+func processInputMessage(ctx context.Context, processor InputProcessor, inputType string, orgID int64, channel string, body []byte) {
+	ok, err := processor.ProcessInput(ctx, orgID, channel, body)
+	if err != nil {
+		logger.Error("Error processing input message", "type", inputType, "channel", channel, "error", err)
+		return
+	}
+	if !ok {
+		logger.Debug("No channel rule for input message", "type", inputType, "channel", channel)
+	}
+}
@@ -0,0 +1,58 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt"
+)
+
+// MQTTDataInput subscribes to MQTT topics and passes received messages
+// to channels according to topic configuration.
+type MQTTDataInput struct {
+	orgID    int64
+	config   MQTTInputConfig
+	password string
+}
+
+func NewMQTTDataInput(orgID int64, config MQTTInputConfig, password string) *MQTTDataInput {
+	return &MQTTDataInput{orgID: orgID, config: config, password: password}
+}
+
+const DataInputTypeMQTT = "mqtt"
+
+func (in *MQTTDataInput) Type() string {
+	return DataInputTypeMQTT
+}
+
+func (in *MQTTDataInput) Run(ctx context.Context, processor InputProcessor) error {
+	client, err := mqtt.Dial(ctx, mqtt.Options{
+		URL:      in.config.URL,
+		ClientID: in.config.ClientID,
+		Username: in.config.Username,
+		Password: in.password,
+	})
+	if err != nil {
+		return fmt.Errorf("error connecting to mqtt broker: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	subs := make([]mqtt.Subscription, 0, len(in.config.Topics))
+	for _, t := range in.config.Topics {
+		subs = append(subs, mqtt.Subscription{Filter: t.Topic, QoS: t.QoS})
+	}
+	if err := client.Subscribe(subs...); err != nil {
+		return fmt.Errorf("error subscribing to mqtt topics: %w", err)
+	}
+	logger.Info("Subscribed to MQTT topics", "url", in.config.URL, "numTopics", len(subs))
+
+	return client.Run(ctx, func(msg mqtt.Message) {
+		for _, t := range in.config.Topics {
+			if !mqtt.MatchTopic(t.Topic, msg.Topic) {
+				continue
+			}
+			channel := inputChannel(t.Channel, msg.Topic)
+			processInputMessage(ctx, processor, DataInputTypeMQTT, in.orgID, channel, msg.Payload)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/nats"
+)
+
+// NATSDataInput subscribes to NATS subjects and passes received messages
+// to channels according to subject configuration.
+type NATSDataInput struct {
+	orgID    int64
+	config   NATSInputConfig
+	password string
+	token    string
+}
+
+func NewNATSDataInput(orgID int64, config NATSInputConfig, password string, token string) *NATSDataInput {
+	return &NATSDataInput{orgID: orgID, config: config, password: password, token: token}
+}
+
+const DataInputTypeNATS = "nats"
+
+func (in *NATSDataInput) Type() string {
+	return DataInputTypeNATS
+}
+
+func (in *NATSDataInput) Run(ctx context.Context, processor InputProcessor) error {
+	client, err := nats.Dial(ctx, nats.Options{
+		URL:      in.config.URL,
+		Name:     in.config.Name,
+		Username: in.config.Username,
+		Password: in.password,
+		Token:    in.token,
+	})
+	if err != nil {
+		return fmt.Errorf("error connecting to nats server: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	// Server delivers a message once for each matching subscription, so
+	// messages are routed by subscription ID.
+	subjects := make(map[int]NATSSubjectConfig, len(in.config.Subjects))
+	for _, s := range in.config.Subjects {
+		sid, err := client.Subscribe(s.Subject, s.Queue)
+		if err != nil {
+			return fmt.Errorf("error subscribing to nats subject %s: %w", s.Subject, err)
+		}
+		subjects[sid] = s
+	}
+	logger.Info("Subscribed to NATS subjects", "url", in.config.URL, "numSubjects", len(subjects))
+
+	return client.Run(ctx, func(msg nats.Message) {
+		s, ok := subjects[msg.SID]
+		if !ok {
+			return
+		}
+		channel := inputChannel(s.Channel, strings.ReplaceAll(msg.Subject, ".", "/"))
+		processInputMessage(ctx, processor, DataInputTypeNATS, in.orgID, channel, msg.Data)
+	})
+}
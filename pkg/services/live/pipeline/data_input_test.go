@@ -0,0 +1,165 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt/mqtttest"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/nats/natstest"
+)
+
+type testChanOutputter struct {
+	frames chan *data.Frame
+}
+
+func (t *testChanOutputter) Type() string {
+	return "test"
+}
+
+func (t *testChanOutputter) OutputFrame(_ context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	frame.Name = vars.Channel
+	t.frames <- frame
+	return nil, nil
+}
+
+func newInputTestPipeline(t *testing.T, channel string) (*Pipeline, chan *data.Frame) {
+	t.Helper()
+	frames := make(chan *data.Frame, 10)
+	p, err := New(&testRuleGetter{
+		rules: map[string]*LiveChannelRule{
+			channel: {
+				Converter:       NewAutoJsonConverter(AutoJsonConverterConfig{}),
+				FrameOutputters: []FrameOutputter{&testChanOutputter{frames: frames}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return p, frames
+}
+
+func waitInputFrame(t *testing.T, ctx context.Context, frames chan *data.Frame) *data.Frame {
+	t.Helper()
+	select {
+	case frame := <-frames:
+		return frame
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for frame")
+		return nil
+	}
+}
+
+func TestMQTTDataInput(t *testing.T) {
+	broker, err := mqtttest.NewBroker("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = broker.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, frames := newInputTestPipeline(t, "stream/mqtt/sensors/kitchen")
+
+	in := NewMQTTDataInput(1, MQTTInputConfig{
+		URL:      broker.URL(),
+		ClientID: "grafana",
+		Topics:   []MQTTTopicConfig{{Topic: "sensors/+", Channel: "stream/mqtt/{topic}"}},
+	}, "")
+	go func() { _ = NewInputRunner(p, in).Run(ctx) }()
+
+	// Input subscribes asynchronously, so keep publishing until frame arrives.
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				broker.Publish("sensors/kitchen", []byte(`{"temperature": 21.5}`))
+			}
+		}
+	}()
+
+	frame := waitInputFrame(t, ctx, frames)
+	require.Equal(t, "stream/mqtt/sensors/kitchen", frame.Name)
+	field, _ := frame.FieldByName("temperature")
+	require.NotNil(t, field)
+	v, ok := field.ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, 21.5, v)
+}
+
+func TestNATSDataInput(t *testing.T) {
+	server, err := natstest.NewServer("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, frames := newInputTestPipeline(t, "stream/nats/sensors/kitchen")
+
+	in := NewNATSDataInput(1, NATSInputConfig{
+		URL: server.URL(),
+		Subjects: []NATSSubjectConfig{
+			{Subject: "sensors.*", Channel: "stream/nats/{topic}"},
+			// Overlapping subject must not result into duplicate processing.
+			{Subject: "sensors.>", Channel: "stream/nats/all"},
+		},
+	}, "", "")
+	go func() { _ = NewInputRunner(p, in).Run(ctx) }()
+
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				server.Publish("sensors.kitchen", "", []byte(`{"temperature": 21.5}`))
+			}
+		}
+	}()
+
+	frame := waitInputFrame(t, ctx, frames)
+	require.Equal(t, "stream/nats/sensors/kitchen", frame.Name)
+	field, _ := frame.FieldByName("temperature")
+	require.NotNil(t, field)
+}
+
+func TestInputConfig_Valid(t *testing.T) {
+	ok, _ := InputConfig{
+		UID:  "test",
+		Type: DataInputTypeMQTT,
+		MQTTInputConfig: &MQTTInputConfig{
+			URL:    "tcp://localhost:1883",
+			Topics: []MQTTTopicConfig{{Topic: "sensors/#", Channel: "stream/mqtt/{topic}"}},
+		},
+	}.Valid()
+	require.True(t, ok)
+
+	ok, reason := InputConfig{
+		UID:  "test",
+		Type: DataInputTypeMQTT,
+		MQTTInputConfig: &MQTTInputConfig{
+			URL:    "tcp://localhost:1883",
+			Topics: []MQTTTopicConfig{{Topic: "sensors/#/temp", Channel: "stream/mqtt/{topic}"}},
+		},
+	}.Valid()
+	require.False(t, ok)
+	require.Contains(t, reason, "invalid topic filter")
+
+	ok, _ = InputConfig{
+		UID:             "test",
+		Type:            DataInputTypeNATS,
+		NATSInputConfig: &NATSInputConfig{URL: "nats://localhost:4222"},
+	}.Valid()
+	require.False(t, ok)
+
+	ok, _ = InputConfig{UID: "test", Type: "kafka"}.Valid()
+	require.False(t, ok)
+}
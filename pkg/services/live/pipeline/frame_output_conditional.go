@@ -31,3 +31,8 @@ func (out ConditionalOutput) OutputFrame(ctx context.Context, vars Vars, frame *
 	}
 	return out.Outputter.OutputFrame(ctx, vars, frame)
 }
+
+func (out *ConditionalOutput) Close() error {
+	closeFrameOutputters(out.Outputter)
+	return nil
+}
@@ -0,0 +1,229 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	influx "github.com/influxdata/line-protocol"
+)
+
+// InfluxFrameOutput writes frames to InfluxDB HTTP write endpoint encoded to
+// line protocol. Endpoint is a full write URL, e.g.
+// http://localhost:8086/api/v2/write?org=main&bucket=live for InfluxDB 2.x or
+// http://localhost:8086/write?db=live for InfluxDB 1.x, timestamps are sent
+// with nanosecond precision.
+type InfluxFrameOutput struct {
+	endpoint    string
+	basicAuth   *BasicAuth
+	token       string
+	measurement string
+	httpClient  *http.Client
+	writer      *batchWriter
+}
+
+func NewInfluxFrameOutput(endpoint string, basicAuth *BasicAuth, token string, config InfluxOutputConfig) *InfluxFrameOutput {
+	out := &InfluxFrameOutput{
+		endpoint:    endpoint,
+		basicAuth:   basicAuth,
+		token:       token,
+		measurement: config.Measurement,
+		httpClient:  &http.Client{Timeout: 5 * time.Second},
+	}
+	out.writer = newBatchWriter(FrameOutputTypeInflux, config.Batch, out.flush)
+	return out
+}
+
+const FrameOutputTypeInflux = "influx"
+
+func (out *InfluxFrameOutput) Type() string {
+	return FrameOutputTypeInflux
+}
+
+// Close flushes buffered frames.
+func (out *InfluxFrameOutput) Close() error {
+	out.writer.close()
+	return nil
+}
+
+func (out *InfluxFrameOutput) OutputFrame(_ context.Context, _ Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	if out.endpoint == "" {
+		logger.Debug("Skip sending to InfluxDB: no url")
+		return nil, nil
+	}
+	measurement := out.measurement
+	if measurement == "" {
+		measurement = frame.Name
+	}
+	lines, err := frameToLineProtocol(measurement, frame)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]batchEntry, 0, len(lines))
+	for _, line := range lines {
+		entries = append(entries, batchEntry{value: line})
+	}
+	out.writer.write(entries...)
+	return nil, nil
+}
+
+func (out *InfluxFrameOutput) flush(ctx context.Context, entries []batchEntry) error {
+	var body bytes.Buffer
+	for _, e := range entries {
+		body.Write(e.value)
+	}
+	logger.Debug("Sending to InfluxDB endpoint", "url", out.endpoint, "numLines", len(entries), "bodyLength", body.Len())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, out.endpoint, &body)
+	if err != nil {
+		return permanentError{fmt.Errorf("error constructing InfluxDB write request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if out.token != "" {
+		req.Header.Set("Authorization", "Token "+out.token)
+	} else if out.basicAuth != nil {
+		req.SetBasicAuth(out.basicAuth.User, out.basicAuth.Password)
+	}
+
+	started := time.Now()
+	resp, err := out.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending InfluxDB write request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("unexpected response code from InfluxDB endpoint: %d, %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+			// Request is malformed or not authorized, retries won't help.
+			return permanentError{err}
+		}
+		return err
+	}
+	logger.Debug("Successfully sent to InfluxDB", "elapsed", time.Since(started))
+	return nil
+}
+
+// frameToLineProtocol converts frame to lines of InfluxDB line protocol, one
+// line per row and distinct field label set. Field labels become tags, first
+// time field is used as line timestamp.
+func frameToLineProtocol(measurement string, frame *data.Frame) ([][]byte, error) {
+	if measurement == "" {
+		return nil, errors.New("measurement name required: set frame name or measurement in output config")
+	}
+	timeIndex := -1
+	for i, f := range frame.Fields {
+		if f.Type().Time() {
+			timeIndex = i
+			break
+		}
+	}
+	numRows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines [][]byte
+	now := time.Now()
+	for row := 0; row < numRows; row++ {
+		ts := now
+		if timeIndex >= 0 {
+			if v, ok := frame.Fields[timeIndex].ConcreteAt(row); ok {
+				ts = v.(time.Time)
+			}
+		}
+
+		var groupKeys []string
+		groups := map[string]map[string]any{}
+		groupTags := map[string]map[string]string{}
+		for i, f := range frame.Fields {
+			if i == timeIndex {
+				continue
+			}
+			value, ok := lineProtocolFieldValue(f, row)
+			if !ok {
+				continue
+			}
+			key := f.Labels.String()
+			fields, ok := groups[key]
+			if !ok {
+				fields = map[string]any{}
+				groups[key] = fields
+				groupTags[key] = f.Labels
+				groupKeys = append(groupKeys, key)
+			}
+			fields[f.Name] = value
+		}
+		sort.Strings(groupKeys)
+
+		for _, key := range groupKeys {
+			metric, err := influx.New(measurement, groupTags[key], groups[key], ts)
+			if err != nil {
+				return nil, err
+			}
+			var buf bytes.Buffer
+			encoder := influx.NewEncoder(&buf)
+			encoder.SetFieldSortOrder(influx.SortFields)
+			encoder.SetFieldTypeSupport(influx.UintSupport)
+			encoder.FailOnFieldErr(false)
+			if _, err := encoder.Encode(metric); err != nil {
+				if errors.Is(err, influx.ErrNoFields) {
+					continue
+				}
+				return nil, err
+			}
+			lines = append(lines, buf.Bytes())
+		}
+	}
+	return lines, nil
+}
+
+// lineProtocolFieldValue returns a value of a field row supported by line
+// protocol, null and NaN values are skipped.
+func lineProtocolFieldValue(f *data.Field, idx int) (any, bool) {
+	v, ok := f.ConcreteAt(idx)
+	if !ok {
+		return nil, false
+	}
+	switch value := v.(type) {
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, false
+		}
+		return value, true
+	case float32:
+		if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+			return nil, false
+		}
+		return float64(value), true
+	case int8:
+		return int64(value), true
+	case int16:
+		return int64(value), true
+	case int32:
+		return int64(value), true
+	case int64:
+		return value, true
+	case uint8:
+		return uint64(value), true
+	case uint16:
+		return uint64(value), true
+	case uint32:
+		return uint64(value), true
+	case uint64:
+		return value, true
+	case bool:
+		return value, true
+	case string:
+		return value, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,115 @@
+package pipeline
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameToLineProtocol(t *testing.T) {
+	ts := time.Unix(1, 0)
+	frame := data.NewFrame("cpu",
+		data.NewField("time", nil, []time.Time{ts}),
+		data.NewField("usage", data.Labels{"host": "a"}, []float64{1.5}),
+		data.NewField("cores", data.Labels{"host": "a"}, []int64{4}),
+		data.NewField("usage", data.Labels{"host": "b"}, []*float64{nil}),
+		data.NewField("state", nil, []string{"ok"}),
+	)
+	lines, err := frameToLineProtocol("cpu", frame)
+	require.NoError(t, err)
+	var result []string
+	for _, line := range lines {
+		result = append(result, string(line))
+	}
+	require.Equal(t, []string{
+		"cpu state=\"ok\" 1000000000\n",
+		"cpu,host=a cores=4i,usage=1.5 1000000000\n",
+	}, result)
+
+	_, err = frameToLineProtocol("", frame)
+	require.Error(t, err)
+}
+
+type influxTestServer struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (s *influxTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, string(body))
+	status := http.StatusNoContent
+	if len(s.statuses) > 0 {
+		status = s.statuses[0]
+		s.statuses = s.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (s *influxTestServer) numRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+func TestInfluxFrameOutput(t *testing.T) {
+	handler := &influxTestServer{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	out := NewInfluxFrameOutput(server.URL+"/api/v2/write?org=main&bucket=live", nil, "secret", InfluxOutputConfig{
+		Measurement: "sensors",
+		Batch:       &BatchConfig{FlushIntervalMilliseconds: 10, MaxRetries: 2},
+	})
+	for _, v := range []float64{1, 2} {
+		frame := data.NewFrame("test",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("value", nil, []float64{v}),
+		)
+		_, err := out.OutputFrame(context.Background(), Vars{}, frame)
+		require.NoError(t, err)
+	}
+
+	// First request fails and is retried.
+	require.Eventually(t, func() bool { return handler.numRequests() == 2 }, 5*time.Second, 10*time.Millisecond)
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	require.Equal(t, "sensors value=1 1000000000\nsensors value=2 1000000000\n", handler.bodies[1])
+	require.Equal(t, "Token secret", handler.requests[1].Header.Get("Authorization"))
+}
+
+func TestInfluxFrameOutput_DropsRejectedBatch(t *testing.T) {
+	handler := &influxTestServer{statuses: []int{http.StatusBadRequest}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	out := NewInfluxFrameOutput(server.URL, &BasicAuth{User: "user", Password: "pass"}, "", InfluxOutputConfig{
+		Batch: &BatchConfig{FlushIntervalMilliseconds: 10, MaxRetries: 2},
+	})
+	frame := data.NewFrame("test", data.NewField("value", nil, []float64{1}))
+	_, err := out.OutputFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		out.writer.mu.Lock()
+		defer out.writer.mu.Unlock()
+		return !out.writer.running
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, handler.numRequests(), "rejected batch must not be retried")
+	user, password, ok := handler.requests[0].BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "user", user)
+	require.Equal(t, "pass", password)
+}
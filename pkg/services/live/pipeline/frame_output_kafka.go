@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/kafka"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+)
+
+// KafkaFrameOutput produces frames to a Kafka topic. Each frame becomes a
+// message keyed by channel, so frames of a channel keep their order.
+type KafkaFrameOutput struct {
+	topic    string
+	format   KafkaFormat
+	producer *kafka.Producer
+	writer   *batchWriter
+}
+
+// NewKafkaFrameOutput creates Kafka output, brokers is a comma separated list
+// of bootstrap broker addresses. Non-nil basicAuth enables SASL/PLAIN.
+func NewKafkaFrameOutput(brokers string, basicAuth *BasicAuth, useTLS bool, config KafkaOutputConfig) (*KafkaFrameOutput, error) {
+	if config.Topic == "" {
+		return nil, errors.New("kafka topic required")
+	}
+	switch config.Format {
+	case "":
+		config.Format = KafkaFormatJSON
+	case KafkaFormatJSON, KafkaFormatProtobuf:
+	default:
+		return nil, fmt.Errorf("unsupported kafka format: %s", config.Format)
+	}
+	opts := kafka.Options{ClientID: "grafana-live"}
+	for _, addr := range strings.Split(brokers, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			opts.Brokers = append(opts.Brokers, addr)
+		}
+	}
+	if basicAuth != nil {
+		opts.Username = basicAuth.User
+		opts.Password = basicAuth.Password
+	}
+	if useTLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	producer, err := kafka.NewProducer(opts)
+	if err != nil {
+		return nil, err
+	}
+	out := &KafkaFrameOutput{
+		topic:    config.Topic,
+		format:   config.Format,
+		producer: producer,
+	}
+	out.writer = newBatchWriter(FrameOutputTypeKafka, config.Batch, out.flush)
+	out.writer.idle = func() { _ = producer.Close() }
+	return out, nil
+}
+
+const FrameOutputTypeKafka = "kafka"
+
+func (out *KafkaFrameOutput) Type() string {
+	return FrameOutputTypeKafka
+}
+
+func (out *KafkaFrameOutput) OutputFrame(_ context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	var value []byte
+	var err error
+	switch out.format {
+	case KafkaFormatProtobuf:
+		value, err = (&prompb.WriteRequest{
+			Timeseries: remotewrite.TimeSeriesFromFramesLabelsColumn(frame),
+		}).Marshal()
+	default:
+		value, err = data.FrameToJSON(frame, data.IncludeAll)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error encoding frame for kafka: %w", err)
+	}
+	out.writer.write(batchEntry{key: []byte(vars.Channel), value: value, time: time.Now()})
+	return nil, nil
+}
+
+// Close flushes buffered frames and closes broker connections.
+func (out *KafkaFrameOutput) Close() error {
+	out.writer.close()
+	return nil
+}
+
+func (out *KafkaFrameOutput) flush(ctx context.Context, entries []batchEntry) error {
+	messages := make([]kafka.Message, 0, len(entries))
+	for _, e := range entries {
+		messages = append(messages, kafka.Message{Key: e.key, Value: e.value, Timestamp: e.time})
+	}
+	logger.Debug("Sending to Kafka", "topic", out.topic, "numMessages", len(messages))
+	err := out.producer.Produce(ctx, out.topic, messages)
+	var kafkaErr kafka.Error
+	if errors.As(err, &kafkaErr) && !kafkaErr.Retriable() {
+		return permanentError{err}
+	}
+	return err
+}
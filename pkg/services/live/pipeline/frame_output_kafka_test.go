@@ -0,0 +1,103 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/kafka/kafkatest"
+)
+
+func kafkaTestFrame() *data.Frame {
+	return data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+		data.NewField("value", data.Labels{"host": "a"}, []float64{1}),
+	)
+}
+
+func TestKafkaFrameOutput(t *testing.T) {
+	broker, err := kafkatest.NewBroker("127.0.0.1:0", 2)
+	require.NoError(t, err)
+	defer func() { _ = broker.Close() }()
+	broker.RequireSASLPlain("user", "secret")
+
+	out, err := NewKafkaFrameOutput(broker.Addr(), &BasicAuth{User: "user", Password: "secret"}, false, KafkaOutputConfig{
+		Topic: "live",
+		Batch: &BatchConfig{FlushIntervalMilliseconds: 10},
+	})
+	require.NoError(t, err)
+
+	_, err = out.OutputFrame(context.Background(), Vars{Channel: "stream/test/a"}, kafkaTestFrame())
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(broker.Messages("live")) == 1 }, 5*time.Second, 10*time.Millisecond)
+	message := broker.Messages("live")[0]
+	require.Equal(t, "stream/test/a", string(message.Key))
+	var frame data.Frame
+	require.NoError(t, json.Unmarshal(message.Value, &frame))
+	require.Equal(t, "test", frame.Name)
+	require.Len(t, frame.Fields, 2)
+}
+
+func TestKafkaFrameOutput_Protobuf(t *testing.T) {
+	broker, err := kafkatest.NewBroker("127.0.0.1:0", 1)
+	require.NoError(t, err)
+	defer func() { _ = broker.Close() }()
+	// Retriable broker errors must be retried.
+	broker.FailProduce(1)
+
+	out, err := NewKafkaFrameOutput(broker.Addr(), nil, false, KafkaOutputConfig{
+		Topic:  "live",
+		Format: KafkaFormatProtobuf,
+		Batch:  &BatchConfig{FlushIntervalMilliseconds: 10},
+	})
+	require.NoError(t, err)
+
+	_, err = out.OutputFrame(context.Background(), Vars{Channel: "stream/test/a"}, kafkaTestFrame())
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(broker.Messages("live")) == 1 }, 5*time.Second, 10*time.Millisecond)
+	var req prompb.WriteRequest
+	require.NoError(t, req.Unmarshal(broker.Messages("live")[0].Value))
+	require.Len(t, req.Timeseries, 1)
+	require.Equal(t, float64(1), req.Timeseries[0].Samples[0].Value)
+}
+
+func TestKafkaFrameOutput_CloseFlushesBuffer(t *testing.T) {
+	broker, err := kafkatest.NewBroker("127.0.0.1:0", 1)
+	require.NoError(t, err)
+	defer func() { _ = broker.Close() }()
+
+	out, err := NewKafkaFrameOutput(broker.Addr(), nil, false, KafkaOutputConfig{
+		Topic: "live",
+		Batch: &BatchConfig{FlushIntervalMilliseconds: int64(time.Hour / time.Millisecond)},
+	})
+	require.NoError(t, err)
+
+	_, err = out.OutputFrame(context.Background(), Vars{Channel: "stream/test/a"}, kafkaTestFrame())
+	require.NoError(t, err)
+	require.Empty(t, broker.Messages("live"))
+
+	require.NoError(t, out.Close())
+	require.Len(t, broker.Messages("live"), 1)
+
+	// Output keeps working after close.
+	_, err = out.OutputFrame(context.Background(), Vars{Channel: "stream/test/a"}, kafkaTestFrame())
+	require.NoError(t, err)
+	require.NoError(t, out.Close())
+	require.Len(t, broker.Messages("live"), 2)
+}
+
+func TestNewKafkaFrameOutput_Validation(t *testing.T) {
+	_, err := NewKafkaFrameOutput("localhost:9092", nil, false, KafkaOutputConfig{})
+	require.Error(t, err)
+	_, err = NewKafkaFrameOutput("localhost:9092", nil, false, KafkaOutputConfig{Topic: "live", Format: "avro"})
+	require.Error(t, err)
+	_, err = NewKafkaFrameOutput(" , ", nil, false, KafkaOutputConfig{Topic: "live"})
+	require.Error(t, err)
+}
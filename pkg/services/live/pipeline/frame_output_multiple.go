@@ -31,6 +31,11 @@ func (out MultipleFrameOutput) OutputFrame(ctx context.Context, vars Vars, frame
 	return frames, nil
 }
 
+func (out *MultipleFrameOutput) Close() error {
+	closeFrameOutputters(out.Outputters...)
+	return nil
+}
+
 func NewMultipleFrameOutput(outputters ...FrameOutputter) *MultipleFrameOutput {
 	return &MultipleFrameOutput{Outputters: outputters}
 }
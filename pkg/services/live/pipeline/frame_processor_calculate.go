@@ -0,0 +1,214 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// CalculateFieldFrameProcessor appends a field with values computed by a math
+// expression evaluated for every frame row. Other fields are referenced in an
+// expression by name: $temperature, or ${field name} if name contains spaces.
+// Expression syntax is the same as used by server-side math expressions.
+type CalculateFieldFrameProcessor struct {
+	config CalculateFieldFrameProcessorConfig
+	tree   *parse.Tree
+}
+
+var calculateFuncs = map[string]parse.Func{
+	"abs":   calculateFunc(math.Abs),
+	"ceil":  calculateFunc(math.Ceil),
+	"floor": calculateFunc(math.Floor),
+	"log":   calculateFunc(math.Log),
+	"round": calculateFunc(math.Round),
+	"sqrt":  calculateFunc(math.Sqrt),
+}
+
+func calculateFunc(f func(float64) float64) parse.Func {
+	return parse.Func{
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             f,
+	}
+}
+
+func NewCalculateFieldFrameProcessor(config CalculateFieldFrameProcessorConfig) (*CalculateFieldFrameProcessor, error) {
+	if config.FieldName == "" {
+		return nil, fmt.Errorf("calculate field name required")
+	}
+	tree, err := parse.Parse(config.Expression, calculateFuncs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return &CalculateFieldFrameProcessor{config: config, tree: tree}, nil
+}
+
+const FrameProcessorTypeCalculate = "calculate"
+
+func (p *CalculateFieldFrameProcessor) Type() string {
+	return FrameProcessorTypeCalculate
+}
+
+func (p *CalculateFieldFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	fields := make(map[string]*data.Field, len(frame.Fields))
+	for _, f := range frame.Fields {
+		fields[f.Name] = f
+	}
+	for _, name := range p.tree.VarNames {
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("field referenced in expression not found: %s", name)
+		}
+	}
+
+	numRows, err := frame.RowLen()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]*float64, numRows)
+	for i := 0; i < numRows; i++ {
+		v, ok, err := evalCalculateNode(p.tree.Root, func(name string) (float64, bool) {
+			return fieldFloatAt(fields[name], i)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			val := v
+			values[i] = &val
+		}
+	}
+
+	field := data.NewField(p.config.FieldName, nil, values)
+	field.Config = p.config.Config
+
+	// Calculated field replaces existing field with the same name.
+	resultFields := make([]*data.Field, 0, len(frame.Fields)+1)
+	for _, f := range frame.Fields {
+		if f.Name != p.config.FieldName {
+			resultFields = append(resultFields, f)
+		}
+	}
+	resultFields = append(resultFields, field)
+	return data.NewFrame(frame.Name, resultFields...), nil
+}
+
+// fieldFloatAt returns numeric representation of a field value, bool values
+// are converted to 1 and 0, time values to Unix milliseconds.
+func fieldFloatAt(field *data.Field, idx int) (float64, bool) {
+	v, ok := field.ConcreteAt(idx)
+	if !ok {
+		return 0, false
+	}
+	switch val := v.(type) {
+	case bool:
+		if val {
+			return 1, true
+		}
+		return 0, true
+	case time.Time:
+		return float64(val.UnixMilli()), true
+	case string:
+		return 0, false
+	}
+	if !field.Type().Numeric() {
+		return 0, false
+	}
+	f, err := field.FloatAt(idx)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// evalCalculateNode evaluates an expression node for a single row. Second
+// returned value is false when the result is null (some operand is null).
+func evalCalculateNode(node parse.Node, getVar func(name string) (float64, bool)) (float64, bool, error) {
+	switch n := node.(type) {
+	case *parse.ScalarNode:
+		return n.Float64, true, nil
+	case *parse.VarNode:
+		v, ok := getVar(n.Name)
+		return v, ok, nil
+	case *parse.UnaryNode:
+		v, ok, err := evalCalculateNode(n.Arg, getVar)
+		if err != nil || !ok {
+			return 0, ok, err
+		}
+		switch n.OpStr {
+		case "-":
+			return -v, true, nil
+		case "!":
+			return boolToFloat(v == 0), true, nil
+		default:
+			return 0, false, fmt.Errorf("unsupported unary operator: %s", n.OpStr)
+		}
+	case *parse.BinaryNode:
+		a, aOk, err := evalCalculateNode(n.Args[0], getVar)
+		if err != nil {
+			return 0, false, err
+		}
+		b, bOk, err := evalCalculateNode(n.Args[1], getVar)
+		if err != nil {
+			return 0, false, err
+		}
+		if !aOk || !bOk {
+			return 0, false, nil
+		}
+		switch n.OpStr {
+		case "+":
+			return a + b, true, nil
+		case "-":
+			return a - b, true, nil
+		case "*":
+			return a * b, true, nil
+		case "/":
+			return a / b, true, nil
+		case "%":
+			return math.Mod(a, b), true, nil
+		case "**":
+			return math.Pow(a, b), true, nil
+		case "==":
+			return boolToFloat(a == b), true, nil
+		case "!=":
+			return boolToFloat(a != b), true, nil
+		case ">":
+			return boolToFloat(a > b), true, nil
+		case ">=":
+			return boolToFloat(a >= b), true, nil
+		case "<":
+			return boolToFloat(a < b), true, nil
+		case "<=":
+			return boolToFloat(a <= b), true, nil
+		case "&&":
+			return boolToFloat(a != 0 && b != 0), true, nil
+		case "||":
+			return boolToFloat(a != 0 || b != 0), true, nil
+		default:
+			return 0, false, fmt.Errorf("unsupported binary operator: %s", n.OpStr)
+		}
+	case *parse.FuncNode:
+		f, ok := n.F.F.(func(float64) float64)
+		if !ok || len(n.Args) != 1 {
+			return 0, false, fmt.Errorf("unsupported function: %s", n.Name)
+		}
+		v, ok, err := evalCalculateNode(n.Args[0], getVar)
+		if err != nil || !ok {
+			return 0, ok, err
+		}
+		return f(v), true, nil
+	default:
+		return 0, false, fmt.Errorf("unsupported expression: %s", node.String())
+	}
+}
@@ -0,0 +1,85 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateFieldFrameProcessor(t *testing.T) {
+	p, err := NewCalculateFieldFrameProcessor(CalculateFieldFrameProcessorConfig{
+		FieldName:  "fahrenheit",
+		Expression: "round(${temp c} * 9 / 5 + 32)",
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("test",
+		data.NewField("temp c", nil, []*float64{float64Ptr(20), nil}),
+	)
+	result, err := p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Len(t, result.Fields, 2)
+
+	field := result.Fields[1]
+	require.Equal(t, "fahrenheit", field.Name)
+	v, ok := field.ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, 68.0, v)
+	_, ok = field.ConcreteAt(1)
+	require.False(t, ok, "null operand must result into null value")
+}
+
+func TestCalculateFieldFrameProcessor_Comparison(t *testing.T) {
+	p, err := NewCalculateFieldFrameProcessor(CalculateFieldFrameProcessorConfig{
+		FieldName:  "alarm",
+		Expression: "$value > 10 && $enabled",
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("test",
+		data.NewField("value", nil, []float64{5, 15, 15}),
+		data.NewField("enabled", nil, []bool{true, true, false}),
+	)
+	result, err := p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+
+	field, _ := result.FieldByName("alarm")
+	require.NotNil(t, field)
+	var values []float64
+	for i := 0; i < field.Len(); i++ {
+		v, ok := field.ConcreteAt(i)
+		require.True(t, ok)
+		values = append(values, v.(float64))
+	}
+	require.Equal(t, []float64{0, 1, 0}, values)
+}
+
+func TestCalculateFieldFrameProcessor_Errors(t *testing.T) {
+	_, err := NewCalculateFieldFrameProcessor(CalculateFieldFrameProcessorConfig{
+		FieldName:  "x",
+		Expression: "$a +",
+	})
+	require.Error(t, err)
+
+	_, err = NewCalculateFieldFrameProcessor(CalculateFieldFrameProcessorConfig{
+		FieldName:  "x",
+		Expression: "unknown($a)",
+	})
+	require.Error(t, err)
+
+	p, err := NewCalculateFieldFrameProcessor(CalculateFieldFrameProcessorConfig{
+		FieldName:  "x",
+		Expression: "$missing * 2",
+	})
+	require.NoError(t, err)
+	_, err = p.ProcessFrame(context.Background(), Vars{}, data.NewFrame("test",
+		data.NewField("value", nil, []float64{1}),
+	))
+	require.Error(t, err)
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
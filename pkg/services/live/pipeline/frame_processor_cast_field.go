@@ -0,0 +1,167 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// CastFieldType is a type to convert field values to.
+type CastFieldType string
+
+const (
+	CastFieldTypeNumber  CastFieldType = "number"
+	CastFieldTypeString  CastFieldType = "string"
+	CastFieldTypeBoolean CastFieldType = "boolean"
+	CastFieldTypeTime    CastFieldType = "time"
+)
+
+// CastFieldsFrameProcessor converts values of fields to another type. Values
+// which can't be converted become null.
+type CastFieldsFrameProcessor struct {
+	config CastFieldsFrameProcessorConfig
+}
+
+func NewCastFieldsFrameProcessor(config CastFieldsFrameProcessorConfig) (*CastFieldsFrameProcessor, error) {
+	for name, castType := range config.Fields {
+		switch castType {
+		case CastFieldTypeNumber, CastFieldTypeString, CastFieldTypeBoolean, CastFieldTypeTime:
+		default:
+			return nil, fmt.Errorf("unsupported cast type for field %s: %s", name, castType)
+		}
+	}
+	return &CastFieldsFrameProcessor{config: config}, nil
+}
+
+const FrameProcessorTypeCastFields = "castFields"
+
+func (p *CastFieldsFrameProcessor) Type() string {
+	return FrameProcessorTypeCastFields
+}
+
+func (p *CastFieldsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	fields := make([]*data.Field, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		castType, ok := p.config.Fields[field.Name]
+		if !ok {
+			fields = append(fields, field)
+			continue
+		}
+		castField, err := castField(field, castType)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, castField)
+	}
+	return data.NewFrame(frame.Name, fields...), nil
+}
+
+func castField(field *data.Field, castType CastFieldType) (*data.Field, error) {
+	var values any
+	switch castType {
+	case CastFieldTypeNumber:
+		v := make([]*float64, field.Len())
+		for i := range v {
+			if f, ok := castToFloat(field, i); ok {
+				v[i] = &f
+			}
+		}
+		values = v
+	case CastFieldTypeString:
+		v := make([]*string, field.Len())
+		for i := range v {
+			if s, ok := castToString(field, i); ok {
+				v[i] = &s
+			}
+		}
+		values = v
+	case CastFieldTypeBoolean:
+		v := make([]*bool, field.Len())
+		for i := range v {
+			if b, ok := castToBool(field, i); ok {
+				v[i] = &b
+			}
+		}
+		values = v
+	case CastFieldTypeTime:
+		v := make([]*time.Time, field.Len())
+		for i := range v {
+			if t, ok := castToTime(field, i); ok {
+				v[i] = &t
+			}
+		}
+		values = v
+	default:
+		return nil, fmt.Errorf("unsupported cast type: %s", castType)
+	}
+	result := data.NewField(field.Name, field.Labels, values)
+	result.Config = field.Config
+	return result, nil
+}
+
+func castToFloat(field *data.Field, idx int) (float64, bool) {
+	if v, ok := field.ConcreteAt(idx); ok {
+		if s, ok := v.(string); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			return f, err == nil
+		}
+	}
+	return fieldFloatAt(field, idx)
+}
+
+func castToString(field *data.Field, idx int) (string, bool) {
+	v, ok := field.ConcreteAt(idx)
+	if !ok {
+		return "", false
+	}
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case time.Time:
+		return val.Format(time.RFC3339Nano), true
+	case bool:
+		return strconv.FormatBool(val), true
+	}
+	if f, ok := fieldFloatAt(field, idx); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64), true
+	}
+	return fmt.Sprintf("%v", v), true
+}
+
+func castToBool(field *data.Field, idx int) (bool, bool) {
+	v, ok := field.ConcreteAt(idx)
+	if !ok {
+		return false, false
+	}
+	if s, ok := v.(string); ok {
+		b, err := strconv.ParseBool(s)
+		return b, err == nil
+	}
+	f, ok := fieldFloatAt(field, idx)
+	return f != 0, ok
+}
+
+// castToTime converts RFC3339 strings and Unix milliseconds to time.
+func castToTime(field *data.Field, idx int) (time.Time, bool) {
+	v, ok := field.ConcreteAt(idx)
+	if !ok {
+		return time.Time{}, false
+	}
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, val)
+		return t, err == nil
+	case bool:
+		return time.Time{}, false
+	}
+	f, ok := fieldFloatAt(field, idx)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(f)), true
+}
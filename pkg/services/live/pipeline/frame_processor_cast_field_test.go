@@ -0,0 +1,65 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCastFieldsFrameProcessor(t *testing.T) {
+	p, err := NewCastFieldsFrameProcessor(CastFieldsFrameProcessorConfig{
+		Fields: map[string]CastFieldType{
+			"value":  CastFieldTypeNumber,
+			"status": CastFieldTypeBoolean,
+			"code":   CastFieldTypeString,
+			"ts":     CastFieldTypeTime,
+		},
+	})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("test",
+		data.NewField("value", nil, []string{"1.5", "oops"}),
+		data.NewField("status", nil, []string{"true", "false"}),
+		data.NewField("code", nil, []float64{200, 404}),
+		data.NewField("ts", nil, []string{"2023-01-02T03:04:05Z", "yesterday"}),
+		data.NewField("other", nil, []string{"a", "b"}),
+	)
+	result, err := p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+
+	require.Equal(t, data.FieldTypeNullableFloat64, result.Fields[0].Type())
+	v, ok := result.Fields[0].ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, 1.5, v)
+	_, ok = result.Fields[0].ConcreteAt(1)
+	require.False(t, ok, "value which can't be converted must become null")
+
+	require.Equal(t, data.FieldTypeNullableBool, result.Fields[1].Type())
+	v, ok = result.Fields[1].ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, true, v)
+
+	require.Equal(t, data.FieldTypeNullableString, result.Fields[2].Type())
+	v, ok = result.Fields[2].ConcreteAt(1)
+	require.True(t, ok)
+	require.Equal(t, "404", v)
+
+	require.Equal(t, data.FieldTypeNullableTime, result.Fields[3].Type())
+	v, ok = result.Fields[3].ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), v)
+	_, ok = result.Fields[3].ConcreteAt(1)
+	require.False(t, ok)
+
+	require.Same(t, frame.Fields[4], result.Fields[4], "fields without cast must be kept as is")
+}
+
+func TestCastFieldsFrameProcessor_InvalidConfig(t *testing.T) {
+	_, err := NewCastFieldsFrameProcessor(CastFieldsFrameProcessorConfig{
+		Fields: map[string]CastFieldType{"value": "decimal"},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,298 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// DownsampleAggregation is a function to aggregate numeric field values
+// inside a time window.
+type DownsampleAggregation string
+
+const (
+	DownsampleAggregationAvg  DownsampleAggregation = "avg"
+	DownsampleAggregationMin  DownsampleAggregation = "min"
+	DownsampleAggregationMax  DownsampleAggregation = "max"
+	DownsampleAggregationSum  DownsampleAggregation = "sum"
+	DownsampleAggregationLast DownsampleAggregation = "last"
+)
+
+const defaultDownsampleMaxBufferedRows = 10000
+
+// DownsampleFrameProcessor aggregates frames of a channel over a time window
+// into a single row frame. Numeric fields are aggregated with configured
+// function, time field is set to window start, other fields keep the last
+// value. Frames inside a window are dropped, aggregated frame of a window is
+// passed further when the first frame of the next window arrives. Windows
+// which got no frames for an interval are flushed and evicted by a
+// background goroutine, it exits when there are no windows left.
+type DownsampleFrameProcessor struct {
+	config          DownsampleFrameProcessorConfig
+	interval        time.Duration
+	maxBufferedRows int
+
+	mu           sync.Mutex
+	windows      map[string]*downsampleWindow
+	bufferedRows int
+	flushHandler FrameFlushHandler
+	running      bool
+}
+
+type downsampleWindow struct {
+	vars    Vars
+	start   time.Time
+	updated time.Time
+	frames  []*data.Frame
+	rows    int
+}
+
+func NewDownsampleFrameProcessor(config DownsampleFrameProcessorConfig) (*DownsampleFrameProcessor, error) {
+	if config.IntervalMilliseconds <= 0 {
+		return nil, fmt.Errorf("downsample interval must be positive")
+	}
+	switch config.Aggregation {
+	case "":
+		config.Aggregation = DownsampleAggregationAvg
+	case DownsampleAggregationAvg, DownsampleAggregationMin, DownsampleAggregationMax, DownsampleAggregationSum, DownsampleAggregationLast:
+	default:
+		return nil, fmt.Errorf("unsupported downsample aggregation: %s", config.Aggregation)
+	}
+	maxBufferedRows := defaultDownsampleMaxBufferedRows
+	if config.MaxBufferedRows > 0 {
+		maxBufferedRows = config.MaxBufferedRows
+	}
+	return &DownsampleFrameProcessor{
+		config:          config,
+		interval:        time.Duration(config.IntervalMilliseconds) * time.Millisecond,
+		maxBufferedRows: maxBufferedRows,
+		windows:         map[string]*downsampleWindow{},
+	}, nil
+}
+
+const FrameProcessorTypeDownsample = "downsample"
+
+func (p *DownsampleFrameProcessor) Type() string {
+	return FrameProcessorTypeDownsample
+}
+
+// SetFlushHandler sets handler for windows flushed in background.
+func (p *DownsampleFrameProcessor) SetFlushHandler(handler FrameFlushHandler) {
+	p.mu.Lock()
+	p.flushHandler = handler
+	p.mu.Unlock()
+}
+
+// frameTime returns the time of the first row, falls back to current time
+// if frame has no time field.
+func frameTime(frame *data.Frame) time.Time {
+	for _, f := range frame.Fields {
+		if !f.Type().Time() || f.Len() == 0 {
+			continue
+		}
+		if v, ok := f.ConcreteAt(0); ok {
+			if t, ok := v.(time.Time); ok {
+				return t
+			}
+		}
+	}
+	return time.Now()
+}
+
+func (p *DownsampleFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	start := frameTime(frame).Truncate(p.interval)
+	key := orgChannelKey(vars.OrgID, vars.Channel)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	window, ok := p.windows[key]
+	if ok && !start.After(window.start) {
+		// Same window or late frame.
+		p.bufferLocked(window, frame)
+		return nil, nil
+	}
+
+	var result *data.Frame
+	if ok {
+		p.removeLocked(key)
+		var err error
+		result, err = p.aggregate(window)
+		if err != nil {
+			return nil, err
+		}
+	}
+	window = &downsampleWindow{vars: vars, start: start}
+	if p.bufferLocked(window, frame) {
+		p.windows[key] = window
+		if !p.running {
+			p.running = true
+			go p.run()
+		}
+	}
+	return result, nil
+}
+
+// bufferLocked adds frame to the window unless buffered rows limit is
+// exceeded, returns whether frame was added.
+func (p *DownsampleFrameProcessor) bufferLocked(window *downsampleWindow, frame *data.Frame) bool {
+	rows, _ := frame.RowLen()
+	if p.bufferedRows+rows > p.maxBufferedRows {
+		logger.Warn("Downsample buffer is full, dropping frame", "channel", window.vars.Channel, "maxBufferedRows", p.maxBufferedRows)
+		return false
+	}
+	window.frames = append(window.frames, frame)
+	window.rows += rows
+	window.updated = time.Now()
+	p.bufferedRows += rows
+	return true
+}
+
+func (p *DownsampleFrameProcessor) removeLocked(key string) {
+	p.bufferedRows -= p.windows[key].rows
+	delete(p.windows, key)
+}
+
+func (p *DownsampleFrameProcessor) run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		p.flushIdle(now)
+		p.mu.Lock()
+		if len(p.windows) == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		p.mu.Unlock()
+	}
+}
+
+// flushIdle flushes and evicts windows which got no frames for an interval.
+func (p *DownsampleFrameProcessor) flushIdle(now time.Time) {
+	p.mu.Lock()
+	var idle []*downsampleWindow
+	for key, window := range p.windows {
+		if now.Sub(window.updated) >= p.interval {
+			idle = append(idle, window)
+			p.removeLocked(key)
+		}
+	}
+	handler := p.flushHandler
+	p.mu.Unlock()
+
+	p.flush(handler, idle)
+}
+
+// Close flushes all windows.
+func (p *DownsampleFrameProcessor) Close() error {
+	p.mu.Lock()
+	windows := make([]*downsampleWindow, 0, len(p.windows))
+	for key, window := range p.windows {
+		windows = append(windows, window)
+		p.removeLocked(key)
+	}
+	handler := p.flushHandler
+	p.mu.Unlock()
+
+	p.flush(handler, windows)
+	return nil
+}
+
+func (p *DownsampleFrameProcessor) flush(handler FrameFlushHandler, windows []*downsampleWindow) {
+	for _, window := range windows {
+		if handler == nil {
+			logger.Debug("Dropping downsample window, no flush handler", "channel", window.vars.Channel)
+			continue
+		}
+		frame, err := p.aggregate(window)
+		if err != nil {
+			logger.Error("Error aggregating downsample window", "error", err, "channel", window.vars.Channel)
+			continue
+		}
+		handler(window.vars, frame)
+	}
+}
+
+func orgChannelKey(orgID int64, channel string) string {
+	return fmt.Sprintf("%d/%s", orgID, channel)
+}
+
+func (p *DownsampleFrameProcessor) aggregate(window *downsampleWindow) (*data.Frame, error) {
+	// The schema of the last frame in a window wins.
+	last := window.frames[len(window.frames)-1]
+	fields := make([]*data.Field, 0, len(last.Fields))
+	for _, f := range last.Fields {
+		switch {
+		case f.Type().Time():
+			t := window.start
+			fields = append(fields, data.NewField(f.Name, f.Labels, []*time.Time{&t}))
+		case f.Type().Numeric():
+			var values []float64
+			for _, frame := range window.frames {
+				field, _ := frame.FieldByName(f.Name)
+				if field == nil {
+					continue
+				}
+				for i := 0; i < field.Len(); i++ {
+					if v, ok := fieldFloatAt(field, i); ok {
+						values = append(values, v)
+					}
+				}
+			}
+			var value *float64
+			if len(values) > 0 {
+				v := aggregateValues(p.config.Aggregation, values)
+				value = &v
+			}
+			aggField := data.NewField(f.Name, f.Labels, []*float64{value})
+			aggField.Config = f.Config
+			fields = append(fields, aggField)
+		default:
+			lastField := data.NewFieldFromFieldType(f.Type(), 1)
+			lastField.Name = f.Name
+			lastField.Labels = f.Labels
+			lastField.Config = f.Config
+			if f.Len() > 0 {
+				lastField.Set(0, f.At(f.Len()-1))
+			}
+			fields = append(fields, lastField)
+		}
+	}
+	return data.NewFrame(last.Name, fields...), nil
+}
+
+func aggregateValues(aggregation DownsampleAggregation, values []float64) float64 {
+	switch aggregation {
+	case DownsampleAggregationMin:
+		result := math.Inf(1)
+		for _, v := range values {
+			result = math.Min(result, v)
+		}
+		return result
+	case DownsampleAggregationMax:
+		result := math.Inf(-1)
+		for _, v := range values {
+			result = math.Max(result, v)
+		}
+		return result
+	case DownsampleAggregationSum:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	case DownsampleAggregationLast:
+		return values[len(values)-1]
+	default:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	}
+}
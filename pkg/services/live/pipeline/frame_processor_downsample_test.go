@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func downsampleTestFrame(ts time.Time, value float64, state string) *data.Frame {
+	return data.NewFrame("test",
+		data.NewField("time", nil, []time.Time{ts}),
+		data.NewField("value", nil, []float64{value}),
+		data.NewField("state", nil, []string{state}),
+	)
+}
+
+func TestDownsampleFrameProcessor(t *testing.T) {
+	testCases := []struct {
+		aggregation DownsampleAggregation
+		expected    float64
+	}{
+		{DownsampleAggregationAvg, 2},
+		{DownsampleAggregationMin, 1},
+		{DownsampleAggregationMax, 3},
+		{DownsampleAggregationSum, 6},
+		{DownsampleAggregationLast, 2},
+	}
+	for _, tt := range testCases {
+		t.Run(string(tt.aggregation), func(t *testing.T) {
+			p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{
+				IntervalMilliseconds: 1000,
+				Aggregation:          tt.aggregation,
+			})
+			require.NoError(t, err)
+
+			vars := Vars{OrgID: 1, Channel: "stream/test/downsample"}
+			start := time.Unix(100, 0)
+			for i, v := range []float64{1, 3, 2} {
+				frame := downsampleTestFrame(start.Add(time.Duration(i)*100*time.Millisecond), v, "ok")
+				result, err := p.ProcessFrame(context.Background(), vars, frame)
+				require.NoError(t, err)
+				require.Nil(t, result)
+			}
+
+			result, err := p.ProcessFrame(context.Background(), vars, downsampleTestFrame(start.Add(time.Second), 10, "alerting"))
+			require.NoError(t, err)
+			require.NotNil(t, result)
+
+			ts, ok := result.Fields[0].ConcreteAt(0)
+			require.True(t, ok)
+			require.Equal(t, start, ts)
+			v, ok := result.Fields[1].ConcreteAt(0)
+			require.True(t, ok)
+			require.Equal(t, tt.expected, v)
+			state, ok := result.Fields[2].ConcreteAt(0)
+			require.True(t, ok)
+			require.Equal(t, "ok", state)
+		})
+	}
+}
+
+func TestDownsampleFrameProcessor_InvalidConfig(t *testing.T) {
+	_, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{})
+	require.Error(t, err)
+	_, err = NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000, Aggregation: "median"})
+	require.Error(t, err)
+}
+
+type flushedFrames struct {
+	vars   []Vars
+	frames []*data.Frame
+}
+
+func (f *flushedFrames) handle(vars Vars, frame *data.Frame) {
+	f.vars = append(f.vars, vars)
+	f.frames = append(f.frames, frame)
+}
+
+func TestDownsampleFrameProcessor_FlushIdle(t *testing.T) {
+	p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000})
+	require.NoError(t, err)
+	flushed := &flushedFrames{}
+	p.SetFlushHandler(flushed.handle)
+
+	vars := Vars{OrgID: 1, Channel: "stream/test/downsample"}
+	start := time.Unix(100, 0)
+	for _, v := range []float64{1, 3} {
+		result, err := p.ProcessFrame(context.Background(), vars, downsampleTestFrame(start, v, "ok"))
+		require.NoError(t, err)
+		require.Nil(t, result)
+	}
+
+	p.flushIdle(time.Now())
+	require.Empty(t, flushed.frames, "window is not idle yet")
+
+	p.flushIdle(time.Now().Add(time.Second))
+	require.Len(t, flushed.frames, 1)
+	require.Equal(t, vars, flushed.vars[0])
+	v, ok := flushed.frames[0].Fields[1].ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, 2.0, v)
+
+	// Flushed window is evicted.
+	require.Empty(t, p.windows)
+	require.Zero(t, p.bufferedRows)
+}
+
+func TestDownsampleFrameProcessor_Close(t *testing.T) {
+	p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{IntervalMilliseconds: 60000})
+	require.NoError(t, err)
+	flushed := &flushedFrames{}
+	p.SetFlushHandler(flushed.handle)
+
+	for _, channel := range []string{"stream/test/a", "stream/test/b"} {
+		_, err := p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: channel}, downsampleTestFrame(time.Unix(100, 0), 1, "ok"))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, p.Close())
+	require.Len(t, flushed.frames, 2)
+	require.Empty(t, p.windows)
+}
+
+func TestDownsampleFrameProcessor_MaxBufferedRows(t *testing.T) {
+	p, err := NewDownsampleFrameProcessor(DownsampleFrameProcessorConfig{IntervalMilliseconds: 1000, MaxBufferedRows: 2})
+	require.NoError(t, err)
+
+	vars := Vars{OrgID: 1, Channel: "stream/test/downsample"}
+	start := time.Unix(100, 0)
+	for _, v := range []float64{1, 3, 100} {
+		_, err := p.ProcessFrame(context.Background(), vars, downsampleTestFrame(start, v, "ok"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, p.bufferedRows)
+
+	// Frame over the limit was dropped.
+	result, err := p.ProcessFrame(context.Background(), vars, downsampleTestFrame(start.Add(time.Second), 10, "ok"))
+	require.NoError(t, err)
+	v, ok := result.Fields[1].ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, 2.0, v)
+	require.Equal(t, 1, p.bufferedRows)
+}
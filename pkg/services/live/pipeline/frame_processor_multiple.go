@@ -19,17 +19,50 @@ func (p *MultipleFrameProcessor) Type() string {
 }
 
 func (p *MultipleFrameProcessor) ProcessFrame(ctx context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
-	for _, p := range p.Processors {
+	return p.processFrom(ctx, 0, vars, frame)
+}
+
+func (p *MultipleFrameProcessor) processFrom(ctx context.Context, procIndex int, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	for _, p := range p.Processors[procIndex:] {
 		var err error
 		frame, err = p.ProcessFrame(ctx, vars, frame)
 		if err != nil {
 			logger.Error("Error processing frame", "error", err)
 			return nil, err
 		}
+		if frame == nil {
+			// Frame dropped by processor.
+			return nil, nil
+		}
 	}
 	return frame, nil
 }
 
+// SetFlushHandler passes frames flushed by nested processors through the
+// processors following them and then to handler.
+func (p *MultipleFrameProcessor) SetFlushHandler(handler FrameFlushHandler) {
+	for i, proc := range p.Processors {
+		flusher, ok := proc.(FrameProcessorFlusher)
+		if !ok {
+			continue
+		}
+		next := i + 1
+		flusher.SetFlushHandler(func(vars Vars, frame *data.Frame) {
+			frame, err := p.processFrom(context.Background(), next, vars, frame)
+			if err != nil || frame == nil {
+				return
+			}
+			handler(vars, frame)
+		})
+	}
+}
+
+// Close closes nested processors.
+func (p *MultipleFrameProcessor) Close() error {
+	closeFrameProcessors(p.Processors...)
+	return nil
+}
+
 func NewMultipleFrameProcessor(processors ...FrameProcessor) *MultipleFrameProcessor {
 	return &MultipleFrameProcessor{Processors: processors}
 }
@@ -0,0 +1,50 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitFrameProcessor drops frames of a channel coming faster than
+// configured rate.
+type RateLimitFrameProcessor struct {
+	config RateLimitFrameProcessorConfig
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func NewRateLimitFrameProcessor(config RateLimitFrameProcessorConfig) (*RateLimitFrameProcessor, error) {
+	if config.MaxFramesPerSecond <= 0 {
+		return nil, fmt.Errorf("max frames per second must be positive")
+	}
+	return &RateLimitFrameProcessor{
+		config:   config,
+		limiters: map[string]*rate.Limiter{},
+	}, nil
+}
+
+const FrameProcessorTypeRateLimit = "rateLimit"
+
+func (p *RateLimitFrameProcessor) Type() string {
+	return FrameProcessorTypeRateLimit
+}
+
+func (p *RateLimitFrameProcessor) ProcessFrame(_ context.Context, vars Vars, frame *data.Frame) (*data.Frame, error) {
+	key := orgChannelKey(vars.OrgID, vars.Channel)
+	p.mu.Lock()
+	limiter, ok := p.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(p.config.MaxFramesPerSecond), 1)
+		p.limiters[key] = limiter
+	}
+	p.mu.Unlock()
+	if !limiter.Allow() {
+		return nil, nil
+	}
+	return frame, nil
+}
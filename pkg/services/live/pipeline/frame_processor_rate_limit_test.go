@@ -0,0 +1,42 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitFrameProcessor(t *testing.T) {
+	p, err := NewRateLimitFrameProcessor(RateLimitFrameProcessorConfig{MaxFramesPerSecond: 1})
+	require.NoError(t, err)
+
+	frame := data.NewFrame("test", data.NewField("value", nil, []float64{1}))
+	vars := Vars{OrgID: 1, Channel: "stream/test/a"}
+
+	result, err := p.ProcessFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	result, err = p.ProcessFrame(context.Background(), vars, frame)
+	require.NoError(t, err)
+	require.Nil(t, result, "frame over the limit must be dropped")
+
+	// Limits are tracked per channel.
+	result, err = p.ProcessFrame(context.Background(), Vars{OrgID: 1, Channel: "stream/test/b"}, frame)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// And per org.
+	result, err = p.ProcessFrame(context.Background(), Vars{OrgID: 2, Channel: "stream/test/a"}, frame)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+}
+
+func TestRateLimitFrameProcessor_InvalidConfig(t *testing.T) {
+	_, err := NewRateLimitFrameProcessor(RateLimitFrameProcessorConfig{})
+	require.Error(t, err)
+	_, err = NewRateLimitFrameProcessor(RateLimitFrameProcessorConfig{MaxFramesPerSecond: -1})
+	require.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// RenameFieldsFrameProcessor renames data.Frame fields.
+type RenameFieldsFrameProcessor struct {
+	config RenameFieldsFrameProcessorConfig
+}
+
+func NewRenameFieldsFrameProcessor(config RenameFieldsFrameProcessorConfig) *RenameFieldsFrameProcessor {
+	return &RenameFieldsFrameProcessor{config: config}
+}
+
+const FrameProcessorTypeRenameFields = "renameFields"
+
+func (p *RenameFieldsFrameProcessor) Type() string {
+	return FrameProcessorTypeRenameFields
+}
+
+func (p *RenameFieldsFrameProcessor) ProcessFrame(_ context.Context, _ Vars, frame *data.Frame) (*data.Frame, error) {
+	fields := make([]*data.Field, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		if newName, ok := p.config.Renames[field.Name]; ok && newName != "" {
+			// Copy field header to not modify the original frame which may be
+			// processed by other rules.
+			renamed := *field
+			renamed.Name = newName
+			field = &renamed
+		}
+		fields = append(fields, field)
+	}
+	return data.NewFrame(frame.Name, fields...), nil
+}
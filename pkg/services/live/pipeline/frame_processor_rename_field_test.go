@@ -0,0 +1,29 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameFieldsFrameProcessor(t *testing.T) {
+	p := NewRenameFieldsFrameProcessor(RenameFieldsFrameProcessorConfig{
+		Renames: map[string]string{"t": "temperature", "h": ""},
+	})
+	frame := data.NewFrame("test",
+		data.NewField("t", nil, []float64{1}),
+		data.NewField("h", nil, []float64{2}),
+	)
+	result, err := p.ProcessFrame(context.Background(), Vars{}, frame)
+	require.NoError(t, err)
+	require.Equal(t, "test", result.Name)
+	require.Equal(t, "temperature", result.Fields[0].Name)
+	require.Equal(t, "h", result.Fields[1].Name, "empty new name must keep the field name")
+	require.Equal(t, "t", frame.Fields[0].Name, "original frame must stay untouched")
+
+	v, ok := result.Fields[0].ConcreteAt(0)
+	require.True(t, ok)
+	require.Equal(t, 1.0, v)
+}
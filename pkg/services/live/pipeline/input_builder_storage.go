@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// StorageInputBuilder builds DataInputs from configurations kept in InputStorage.
+type StorageInputBuilder struct {
+	Storage        InputStorage
+	SecretsService secrets.Service
+}
+
+func (f *StorageInputBuilder) decryptSecureSetting(ctx context.Context, config InputConfig, key string) (string, error) {
+	encrypted, ok := config.SecureSettings[key]
+	if !ok || len(encrypted) == 0 {
+		return "", nil
+	}
+	decrypted, err := f.SecretsService.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", fmt.Errorf("%s can't be decrypted: %w", key, err)
+	}
+	return string(decrypted), nil
+}
+
+func (f *StorageInputBuilder) extractDataInput(ctx context.Context, config InputConfig) (DataInput, error) {
+	ok, reason := config.Valid()
+	if !ok {
+		return nil, fmt.Errorf("invalid input config %s: %s", config.UID, reason)
+	}
+	password, err := f.decryptSecureSetting(ctx, config, "password")
+	if err != nil {
+		return nil, err
+	}
+	switch config.Type {
+	case DataInputTypeMQTT:
+		return NewMQTTDataInput(config.OrgId, *config.MQTTInputConfig, password), nil
+	case DataInputTypeNATS:
+		token, err := f.decryptSecureSetting(ctx, config, "token")
+		if err != nil {
+			return nil, err
+		}
+		return NewNATSDataInput(config.OrgId, *config.NATSInputConfig, password, token), nil
+	default:
+		return nil, fmt.Errorf("unknown input type: %s", config.Type)
+	}
+}
+
+// BuildInputs builds all DataInputs configured for an organization.
+func (f *StorageInputBuilder) BuildInputs(ctx context.Context, orgID int64) ([]DataInput, error) {
+	inputConfigs, err := f.Storage.ListInputConfigs(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]DataInput, 0, len(inputConfigs))
+	for _, config := range inputConfigs {
+		// Inputs publish into channels of the organization they are listed for.
+		config.OrgId = orgID
+		in, err := f.extractDataInput(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+// InputRevision returns org configuration revision if Storage supports
+// revisions, zero otherwise.
+func (f *StorageInputBuilder) InputRevision(ctx context.Context, orgID int64) (int64, error) {
+	revisionStorage, ok := f.Storage.(RevisionStorage)
+	if !ok {
+		return 0, nil
+	}
+	return revisionStorage.Revision(ctx, orgID)
+}
@@ -0,0 +1,123 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// InputBuilder builds DataInputs configured for an organization.
+type InputBuilder interface {
+	BuildInputs(ctx context.Context, orgID int64) ([]DataInput, error)
+}
+
+// InputRevisionGetter may be optionally implemented by InputBuilder to let
+// InputManager restart inputs of an organization only when its
+// configuration changes.
+type InputRevisionGetter interface {
+	InputRevision(ctx context.Context, orgID int64) (int64, error)
+}
+
+// InputManager runs DataInputs of organizations. Inputs of an organization
+// are restarted when its configuration revision changes.
+type InputManager struct {
+	processor InputProcessor
+	builder   InputBuilder
+
+	mu   sync.Mutex
+	orgs map[int64]*orgInputs
+}
+
+// orgInputs are inputs of an organization, cancel is nil if organization
+// has no inputs.
+type orgInputs struct {
+	revision int64
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func (in *orgInputs) stop() {
+	if in.cancel == nil {
+		return
+	}
+	in.cancel()
+	<-in.done
+}
+
+func NewInputManager(processor InputProcessor, builder InputBuilder) *InputManager {
+	return &InputManager{
+		processor: processor,
+		builder:   builder,
+		orgs:      map[int64]*orgInputs{},
+	}
+}
+
+// Apply starts inputs of organizations which are not running yet, restarts
+// inputs of organizations which revision changed and stops inputs of
+// organizations not listed. Inputs run until ctx is done or Stop is called.
+func (m *InputManager) Apply(ctx context.Context, orgIDs []int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	listed := make(map[int64]struct{}, len(orgIDs))
+	for _, orgID := range orgIDs {
+		listed[orgID] = struct{}{}
+		m.applyOrgLocked(ctx, orgID)
+	}
+	for orgID, running := range m.orgs {
+		if _, ok := listed[orgID]; !ok {
+			running.stop()
+			delete(m.orgs, orgID)
+		}
+	}
+}
+
+func (m *InputManager) applyOrgLocked(ctx context.Context, orgID int64) {
+	var revision int64
+	if revisionGetter, ok := m.builder.(InputRevisionGetter); ok {
+		var err error
+		revision, err = revisionGetter.InputRevision(ctx, orgID)
+		if err != nil {
+			logger.Error("Error getting input revision", "error", err, "orgId", orgID)
+			return
+		}
+	}
+	running, ok := m.orgs[orgID]
+	if ok && running.revision == revision {
+		return
+	}
+
+	inputs, err := m.builder.BuildInputs(ctx, orgID)
+	if err != nil {
+		// Inputs are built again on the next Apply.
+		logger.Error("Failed to build pipeline inputs", "orgId", orgID, "error", err)
+		return
+	}
+	if ok {
+		logger.Info("Pipeline inputs changed, restarting", "orgId", orgID, "revision", revision)
+		running.stop()
+		delete(m.orgs, orgID)
+	}
+	if len(inputs) == 0 {
+		m.orgs[orgID] = &orgInputs{revision: revision}
+		return
+	}
+
+	logger.Info("Starting pipeline inputs", "orgId", orgID, "count", len(inputs))
+	orgCtx, cancel := context.WithCancel(ctx)
+	running = &orgInputs{revision: revision, cancel: cancel, done: make(chan struct{})}
+	m.orgs[orgID] = running
+	go func() {
+		defer close(running.done)
+		_ = NewInputRunner(m.processor, inputs...).Run(orgCtx)
+	}()
+}
+
+// Stop stops inputs of all organizations and waits for them to exit.
+func (m *InputManager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for orgID, running := range m.orgs {
+		running.stop()
+		delete(m.orgs, orgID)
+	}
+}
@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testInput struct {
+	mu      sync.Mutex
+	started int
+	stopped int
+}
+
+func (in *testInput) Type() string {
+	return "test"
+}
+
+func (in *testInput) Run(ctx context.Context, _ InputProcessor) error {
+	in.mu.Lock()
+	in.started++
+	in.mu.Unlock()
+	<-ctx.Done()
+	in.mu.Lock()
+	in.stopped++
+	in.mu.Unlock()
+	return ctx.Err()
+}
+
+func (in *testInput) counts() (int, int) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.started, in.stopped
+}
+
+type testInputBuilder struct {
+	revisions map[int64]int64
+	inputs    map[int64]*testInput
+	builds    int
+}
+
+func (b *testInputBuilder) BuildInputs(_ context.Context, orgID int64) ([]DataInput, error) {
+	b.builds++
+	in := &testInput{}
+	b.inputs[orgID] = in
+	return []DataInput{in}, nil
+}
+
+func (b *testInputBuilder) InputRevision(_ context.Context, orgID int64) (int64, error) {
+	return b.revisions[orgID], nil
+}
+
+func TestInputManager_Apply(t *testing.T) {
+	builder := &testInputBuilder{revisions: map[int64]int64{1: 1, 2: 1}, inputs: map[int64]*testInput{}}
+	m := NewInputManager(nil, builder)
+	defer m.Stop()
+	ctx := context.Background()
+
+	m.Apply(ctx, []int64{1, 2})
+	require.Equal(t, 2, builder.builds)
+	first := builder.inputs[1]
+	require.Eventually(t, func() bool {
+		started, _ := first.counts()
+		return started == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Unchanged revisions keep inputs running.
+	m.Apply(ctx, []int64{1, 2})
+	require.Equal(t, 2, builder.builds)
+
+	// Changed revision restarts inputs of the org.
+	builder.revisions[1] = 2
+	m.Apply(ctx, []int64{1, 2})
+	require.Equal(t, 3, builder.builds)
+	_, stopped := first.counts()
+	require.Equal(t, 1, stopped)
+
+	// Inputs of orgs which are not listed anymore are stopped.
+	second := builder.inputs[2]
+	m.Apply(ctx, []int64{1})
+	_, stopped = second.counts()
+	require.Equal(t, 1, stopped)
+	require.NotContains(t, m.orgs, int64(2))
+}
@@ -0,0 +1,422 @@
+// Package kafka implements a minimal Kafka producer sufficient to publish
+// uncompressed record batches, optionally over TLS and with SASL/PLAIN
+// authentication.
+package kafka
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/kafka/internal/wire"
+)
+
+const (
+	defaultDialTimeout    = 5 * time.Second
+	defaultRequestTimeout = 10 * time.Second
+)
+
+// Options to create Producer.
+type Options struct {
+	// Brokers is a list of bootstrap broker addresses in host:port form.
+	Brokers  []string
+	ClientID string
+	// Username and Password enable SASL/PLAIN authentication when Username
+	// is not empty. Should be used together with TLSConfig.
+	Username  string
+	Password  string
+	TLSConfig *tls.Config
+	// RequiredAcks is a number of acknowledgements the leader must receive
+	// before responding: 1 for leader only, -1 for all in-sync replicas.
+	// Defaults to -1.
+	RequiredAcks int16
+	DialTimeout  time.Duration
+	// RequestTimeout limits time to wait for a broker response.
+	RequestTimeout time.Duration
+}
+
+type partitionMetadata struct {
+	id     int32
+	leader int32
+}
+
+// Producer publishes messages to Kafka topics. Messages with a key are
+// assigned to partitions by key hash, messages without a key are spread
+// over partitions in round-robin.
+type Producer struct {
+	opts Options
+
+	mu         sync.Mutex
+	brokers    map[int32]string
+	conns      map[int32]*conn
+	partitions map[string][]partitionMetadata
+	counter    uint32
+}
+
+func NewProducer(opts Options) (*Producer, error) {
+	if len(opts.Brokers) == 0 {
+		return nil, errors.New("kafka: at least one broker required")
+	}
+	if opts.RequiredAcks == 0 {
+		opts.RequiredAcks = -1
+	}
+	if opts.RequiredAcks != 1 && opts.RequiredAcks != -1 {
+		return nil, fmt.Errorf("kafka: unsupported required acks: %d", opts.RequiredAcks)
+	}
+	if opts.ClientID == "" {
+		opts.ClientID = "grafana"
+	}
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = defaultDialTimeout
+	}
+	if opts.RequestTimeout == 0 {
+		opts.RequestTimeout = defaultRequestTimeout
+	}
+	return &Producer{
+		opts:       opts,
+		brokers:    map[int32]string{},
+		conns:      map[int32]*conn{},
+		partitions: map[string][]partitionMetadata{},
+	}, nil
+}
+
+// Produce publishes messages to a topic and waits for acknowledgement.
+// Metadata is refreshed on the next call if a broker reports leadership
+// change, so callers are expected to retry on errors for which
+// Error.Retriable returns true.
+func (p *Producer) Produce(ctx context.Context, topic string, messages []Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	partitions, err := p.topicPartitions(ctx, topic)
+	if err != nil {
+		return err
+	}
+
+	byPartition := map[int32][]wire.Record{}
+	for _, m := range messages {
+		if m.Timestamp.IsZero() {
+			m.Timestamp = time.Now()
+		}
+		partition := p.partition(m.Key, partitions)
+		byPartition[partition.id] = append(byPartition[partition.id], wire.Record(m))
+	}
+	byLeader := map[int32][]int32{}
+	for _, partition := range partitions {
+		if _, ok := byPartition[partition.id]; ok {
+			byLeader[partition.leader] = append(byLeader[partition.leader], partition.id)
+		}
+	}
+
+	for leader, ids := range byLeader {
+		if err := p.produce(ctx, leader, topic, ids, byPartition); err != nil {
+			var kafkaErr Error
+			if !errors.As(err, &kafkaErr) {
+				// Connection is in unknown state after network failure.
+				p.closeConn(leader)
+			}
+			delete(p.partitions, topic)
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Producer) partition(key []byte, partitions []partitionMetadata) partitionMetadata {
+	if key == nil {
+		p.counter++
+		return partitions[int(p.counter%uint32(len(partitions)))]
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	return partitions[int(h.Sum32()%uint32(len(partitions)))]
+}
+
+func (p *Producer) produce(ctx context.Context, leader int32, topic string, ids []int32, byPartition map[int32][]wire.Record) error {
+	c, err := p.nodeConn(ctx, leader)
+	if err != nil {
+		return err
+	}
+	req := &wire.Encoder{}
+	req.NullString() // Transactional ID.
+	req.Int16(p.opts.RequiredAcks)
+	req.Int32(int32(p.opts.RequestTimeout / time.Millisecond))
+	req.ArrayLen(1)
+	req.String(topic)
+	req.ArrayLen(len(ids))
+	for _, id := range ids {
+		req.Int32(id)
+		req.Bytes(wire.EncodeRecordBatch(byPartition[id]))
+	}
+	resp, err := c.roundTrip(ctx, wire.APIKeyProduce, wire.APIVersionProduce, req.Buffer())
+	if err != nil {
+		return err
+	}
+	numTopics := resp.ArrayLen()
+	for i := 0; i < numTopics; i++ {
+		_ = resp.String()
+		numPartitions := resp.ArrayLen()
+		for j := 0; j < numPartitions; j++ {
+			_ = resp.Int32()
+			code := Error(resp.Int16())
+			_ = resp.Int64() // Base offset.
+			_ = resp.Int64() // Log append time.
+			if resp.Err() == nil && code != ErrNone {
+				return code
+			}
+		}
+	}
+	return resp.Err()
+}
+
+// topicPartitions returns cached topic partitions or loads them from
+// cluster metadata.
+func (p *Producer) topicPartitions(ctx context.Context, topic string) ([]partitionMetadata, error) {
+	if partitions, ok := p.partitions[topic]; ok {
+		return partitions, nil
+	}
+	var lastErr error
+	for _, addr := range p.metadataBrokers() {
+		partitions, err := p.loadMetadata(ctx, addr, topic)
+		if err == nil {
+			p.partitions[topic] = partitions
+			return partitions, nil
+		}
+		var kafkaErr Error
+		if errors.As(err, &kafkaErr) {
+			// Broker answered, no sense to ask others.
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("kafka: can't load metadata: %w", lastErr)
+}
+
+// metadataBrokers returns known brokers followed by bootstrap ones.
+func (p *Producer) metadataBrokers() []string {
+	addrs := make([]string, 0, len(p.brokers)+len(p.opts.Brokers))
+	for _, addr := range p.brokers {
+		addrs = append(addrs, addr)
+	}
+	return append(addrs, p.opts.Brokers...)
+}
+
+func (p *Producer) loadMetadata(ctx context.Context, addr string, topic string) ([]partitionMetadata, error) {
+	c, err := p.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = c.close() }()
+
+	req := &wire.Encoder{}
+	req.ArrayLen(1)
+	req.String(topic)
+	resp, err := c.roundTrip(ctx, wire.APIKeyMetadata, wire.APIVersionMetadata, req.Buffer())
+	if err != nil {
+		return nil, err
+	}
+
+	brokers := map[int32]string{}
+	numBrokers := resp.ArrayLen()
+	for i := 0; i < numBrokers; i++ {
+		nodeID := resp.Int32()
+		host := resp.String()
+		port := resp.Int32()
+		_ = resp.String() // Rack.
+		brokers[nodeID] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	_ = resp.Int32() // Controller ID.
+
+	var partitions []partitionMetadata
+	numTopics := resp.ArrayLen()
+	for i := 0; i < numTopics; i++ {
+		topicErr := Error(resp.Int16())
+		name := resp.String()
+		_ = resp.Bool() // Is internal.
+		numPartitions := resp.ArrayLen()
+		for j := 0; j < numPartitions; j++ {
+			partitionErr := Error(resp.Int16())
+			partition := partitionMetadata{id: resp.Int32(), leader: resp.Int32()}
+			for k, n := 0, resp.ArrayLen(); k < n; k++ {
+				_ = resp.Int32() // Replica.
+			}
+			for k, n := 0, resp.ArrayLen(); k < n; k++ {
+				_ = resp.Int32() // In-sync replica.
+			}
+			if name == topic && partitionErr == ErrNone && partition.leader >= 0 {
+				partitions = append(partitions, partition)
+			}
+		}
+		if resp.Err() == nil && name == topic && topicErr != ErrNone {
+			return nil, topicErr
+		}
+	}
+	if resp.Err() != nil {
+		return nil, resp.Err()
+	}
+	if len(partitions) == 0 {
+		return nil, ErrLeaderNotAvailable
+	}
+	for nodeID, brokerAddr := range brokers {
+		if current, ok := p.brokers[nodeID]; ok && current != brokerAddr {
+			p.closeConn(nodeID)
+		}
+		p.brokers[nodeID] = brokerAddr
+	}
+	return partitions, nil
+}
+
+func (p *Producer) nodeConn(ctx context.Context, nodeID int32) (*conn, error) {
+	if c, ok := p.conns[nodeID]; ok {
+		return c, nil
+	}
+	addr, ok := p.brokers[nodeID]
+	if !ok {
+		return nil, ErrLeaderNotAvailable
+	}
+	c, err := p.dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[nodeID] = c
+	return c, nil
+}
+
+func (p *Producer) closeConn(nodeID int32) {
+	if c, ok := p.conns[nodeID]; ok {
+		_ = c.close()
+		delete(p.conns, nodeID)
+	}
+}
+
+// Close closes all broker connections. Producer may still be used after
+// Close, connections are established again on demand.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for nodeID := range p.conns {
+		p.closeConn(nodeID)
+	}
+	return nil
+}
+
+func (p *Producer) dial(ctx context.Context, addr string) (*conn, error) {
+	dialer := &net.Dialer{Timeout: p.opts.DialTimeout}
+	nc, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if p.opts.TLSConfig != nil {
+		tlsConfig := p.opts.TLSConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			host, _, _ := net.SplitHostPort(addr)
+			tlsConfig.ServerName = host
+		}
+		tlsConn := tls.Client(nc, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = nc.Close()
+			return nil, err
+		}
+		nc = tlsConn
+	}
+	c := &conn{
+		nc:       nc,
+		r:        bufio.NewReader(nc),
+		clientID: p.opts.ClientID,
+		timeout:  p.opts.RequestTimeout,
+	}
+	if p.opts.Username != "" {
+		if err := c.authenticate(ctx, p.opts.Username, p.opts.Password); err != nil {
+			_ = c.close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+type conn struct {
+	nc            net.Conn
+	r             *bufio.Reader
+	clientID      string
+	timeout       time.Duration
+	correlationID int32
+}
+
+func (c *conn) roundTrip(ctx context.Context, apiKey, apiVersion int16, body []byte) (*wire.Decoder, error) {
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.nc.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	c.correlationID++
+	req := &wire.Encoder{}
+	req.Int16(apiKey)
+	req.Int16(apiVersion)
+	req.Int32(c.correlationID)
+	req.String(c.clientID)
+	req.Raw(body)
+	if err := wire.WriteFrame(c.nc, req.Buffer()); err != nil {
+		return nil, err
+	}
+
+	payload, err := wire.ReadFrame(c.r)
+	if err != nil {
+		return nil, err
+	}
+	resp := wire.NewDecoder(payload)
+	if correlationID := resp.Int32(); correlationID != c.correlationID {
+		return nil, fmt.Errorf("kafka: unexpected correlation id %d, expected %d", correlationID, c.correlationID)
+	}
+	return resp, nil
+}
+
+func (c *conn) authenticate(ctx context.Context, username, password string) error {
+	req := &wire.Encoder{}
+	req.String("PLAIN")
+	resp, err := c.roundTrip(ctx, wire.APIKeySaslHandshake, wire.APIVersionSaslHandshake, req.Buffer())
+	if err != nil {
+		return err
+	}
+	if code := Error(resp.Int16()); resp.Err() == nil && code != ErrNone {
+		return code
+	}
+	if resp.Err() != nil {
+		return resp.Err()
+	}
+
+	req = &wire.Encoder{}
+	req.Bytes([]byte("\x00" + username + "\x00" + password))
+	resp, err = c.roundTrip(ctx, wire.APIKeySaslAuthenticate, wire.APIVersionSaslAuthenticate, req.Buffer())
+	if err != nil {
+		return err
+	}
+	code := Error(resp.Int16())
+	message := resp.String()
+	if resp.Err() != nil {
+		return resp.Err()
+	}
+	if code != ErrNone {
+		if message != "" {
+			return fmt.Errorf("%w: %s", code, message)
+		}
+		return code
+	}
+	return nil
+}
+
+func (c *conn) close() error {
+	return c.nc.Close()
+}
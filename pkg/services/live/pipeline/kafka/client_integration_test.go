@@ -0,0 +1,52 @@
+package kafka_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/kafka"
+)
+
+// TestIntegrationProducer produces to a real broker, e.g. the one from
+// devenv/docker/blocks/kafka with KAFKA_BROKERS=localhost:9092. Broker must
+// allow topic auto creation.
+func TestIntegrationProducer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	brokers, ok := os.LookupEnv("KAFKA_BROKERS")
+	if !ok || brokers == "" {
+		t.Skip("No Kafka brokers supplied")
+	}
+
+	producer, err := kafka.NewProducer(kafka.Options{Brokers: strings.Split(brokers, ","), ClientID: "grafana-test"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = producer.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	topic := fmt.Sprintf("grafana-test-%d", time.Now().UnixNano())
+	messages := []kafka.Message{
+		{Key: []byte("kitchen"), Value: []byte(`{"value":21.5}`), Timestamp: time.Now()},
+		{Value: []byte(`{"value":50}`)},
+		{Key: []byte("hall"), Value: make([]byte, 70000), Timestamp: time.Now()},
+	}
+	// The first metadata request creates the topic, leader may be not
+	// available for a moment then.
+	require.Eventually(t, func() bool {
+		return producer.Produce(ctx, topic, messages) == nil
+	}, 20*time.Second, 500*time.Millisecond)
+
+	require.NoError(t, producer.Produce(ctx, topic, messages[:1]))
+
+	var kafkaErr kafka.Error
+	err = producer.Produce(ctx, "invalid topic name", messages[:1])
+	require.ErrorAs(t, err, &kafkaErr)
+}
@@ -0,0 +1,81 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/kafka"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/kafka/kafkatest"
+)
+
+func TestProducer(t *testing.T) {
+	broker, err := kafkatest.NewBroker("127.0.0.1:0", 3)
+	require.NoError(t, err)
+	defer func() { _ = broker.Close() }()
+
+	producer, err := kafka.NewProducer(kafka.Options{Brokers: []string{broker.Addr()}})
+	require.NoError(t, err)
+	defer func() { _ = producer.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var messages []kafka.Message
+	for i := 0; i < 10; i++ {
+		messages = append(messages, kafka.Message{Key: []byte("key"), Value: []byte{byte(i)}})
+	}
+	require.NoError(t, producer.Produce(ctx, "frames", messages))
+
+	received := broker.Messages("frames")
+	require.Len(t, received, 10)
+	for i, m := range received {
+		// All messages with the same key go to the same partition, so order is kept.
+		require.Equal(t, []byte{byte(i)}, m.Value)
+		require.Equal(t, []byte("key"), m.Key)
+	}
+
+	broker.FailProduce(1)
+	err = producer.Produce(ctx, "frames", messages[:1])
+	var kafkaErr kafka.Error
+	require.True(t, errors.As(err, &kafkaErr))
+	require.True(t, kafkaErr.Retriable())
+	require.NoError(t, producer.Produce(ctx, "frames", messages[:1]))
+	require.Len(t, broker.Messages("frames"), 11)
+
+	// Producer reconnects after Close.
+	require.NoError(t, producer.Close())
+	require.NoError(t, producer.Produce(ctx, "frames", messages[:1]))
+	require.Len(t, broker.Messages("frames"), 12)
+}
+
+func TestProducer_SASLPlain(t *testing.T) {
+	broker, err := kafkatest.NewBroker("127.0.0.1:0", 1)
+	require.NoError(t, err)
+	defer func() { _ = broker.Close() }()
+	broker.RequireSASLPlain("user", "secret")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	producer, err := kafka.NewProducer(kafka.Options{Brokers: []string{broker.Addr()}, Username: "user", Password: "wrong"})
+	require.NoError(t, err)
+	err = producer.Produce(ctx, "frames", []kafka.Message{{Value: []byte("x")}})
+	require.ErrorIs(t, err, kafka.ErrSaslAuthenticationFailed)
+
+	producer, err = kafka.NewProducer(kafka.Options{Brokers: []string{broker.Addr()}, Username: "user", Password: "secret"})
+	require.NoError(t, err)
+	defer func() { _ = producer.Close() }()
+	require.NoError(t, producer.Produce(ctx, "frames", []kafka.Message{{Value: []byte("x")}}))
+	require.Len(t, broker.Messages("frames"), 1)
+}
+
+func TestNewProducer_Validation(t *testing.T) {
+	_, err := kafka.NewProducer(kafka.Options{})
+	require.Error(t, err)
+	_, err = kafka.NewProducer(kafka.Options{Brokers: []string{"localhost:9092"}, RequiredAcks: 2})
+	require.Error(t, err)
+}
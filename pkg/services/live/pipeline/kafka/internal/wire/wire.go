@@ -0,0 +1,365 @@
+// Package wire encodes and decodes the subset of Kafka protocol used by the
+// kafka producer and the test broker in kafkatest.
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// API keys and versions of the implemented requests, see
+// https://kafka.apache.org/protocol.
+const (
+	APIKeyProduce          int16 = 0
+	APIKeyMetadata         int16 = 3
+	APIKeySaslHandshake    int16 = 17
+	APIKeySaslAuthenticate int16 = 36
+
+	APIVersionProduce          int16 = 3
+	APIVersionMetadata         int16 = 1
+	APIVersionSaslHandshake    int16 = 1
+	APIVersionSaslAuthenticate int16 = 0
+)
+
+const maxMessageSize = 100 * 1024 * 1024
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+var errShortBuffer = errors.New("kafka: short buffer")
+
+// Record is a single record of a record batch.
+type Record struct {
+	Key       []byte
+	Value     []byte
+	Timestamp time.Time
+}
+
+// Encoder appends protocol primitives to a buffer.
+type Encoder struct {
+	buf []byte
+}
+
+// Buffer returns encoded data.
+func (e *Encoder) Buffer() []byte {
+	return e.buf
+}
+
+func (e *Encoder) Int8(v int8) {
+	e.buf = append(e.buf, byte(v))
+}
+
+func (e *Encoder) Int16(v int16) {
+	e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v))
+}
+
+func (e *Encoder) Int32(v int32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v))
+}
+
+func (e *Encoder) Uint32(v uint32) {
+	e.buf = binary.BigEndian.AppendUint32(e.buf, v)
+}
+
+func (e *Encoder) Int64(v int64) {
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v))
+}
+
+func (e *Encoder) Bool(v bool) {
+	if v {
+		e.Int8(1)
+		return
+	}
+	e.Int8(0)
+}
+
+func (e *Encoder) String(s string) {
+	e.Int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *Encoder) NullString() {
+	e.Int16(-1)
+}
+
+func (e *Encoder) Bytes(b []byte) {
+	if b == nil {
+		e.Int32(-1)
+		return
+	}
+	e.Int32(int32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *Encoder) ArrayLen(n int) {
+	e.Int32(int32(n))
+}
+
+func (e *Encoder) Varint(v int64) {
+	e.buf = binary.AppendVarint(e.buf, v)
+}
+
+func (e *Encoder) VarBytes(b []byte) {
+	if b == nil {
+		e.Varint(-1)
+		return
+	}
+	e.Varint(int64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *Encoder) Raw(b []byte) {
+	e.buf = append(e.buf, b...)
+}
+
+// Decoder reads protocol primitives, the first error is kept and all
+// subsequent reads return zero values.
+type Decoder struct {
+	buf []byte
+	off int
+	err error
+}
+
+func NewDecoder(buf []byte) *Decoder {
+	return &Decoder{buf: buf}
+}
+
+// Err returns the first error met while decoding.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+func (d *Decoder) Next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.off+n > len(d.buf) {
+		d.err = errShortBuffer
+		return nil
+	}
+	b := d.buf[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+func (d *Decoder) Remaining() int {
+	return len(d.buf) - d.off
+}
+
+func (d *Decoder) Int8() int8 {
+	b := d.Next(1)
+	if b == nil {
+		return 0
+	}
+	return int8(b[0])
+}
+
+func (d *Decoder) Int16() int16 {
+	b := d.Next(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (d *Decoder) Int32() int32 {
+	b := d.Next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *Decoder) Uint32() uint32 {
+	b := d.Next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (d *Decoder) Int64() int64 {
+	b := d.Next(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func (d *Decoder) Bool() bool {
+	return d.Int8() != 0
+}
+
+func (d *Decoder) String() string {
+	n := d.Int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.Next(int(n)))
+}
+
+func (d *Decoder) Bytes() []byte {
+	n := d.Int32()
+	if n < 0 {
+		return nil
+	}
+	return d.Next(int(n))
+}
+
+func (d *Decoder) ArrayLen() int {
+	n := d.Int32()
+	if d.err == nil && (n < -1 || int(n) > d.Remaining()) {
+		d.err = errShortBuffer
+		return 0
+	}
+	return int(n)
+}
+
+func (d *Decoder) Varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf[d.off:])
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+func (d *Decoder) VarBytes() []byte {
+	n := d.Varint()
+	if n < 0 {
+		return nil
+	}
+	return d.Next(int(n))
+}
+
+func WriteFrame(w io.Writer, payload []byte) error {
+	buf := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(buf, uint32(len(payload)))
+	copy(buf[4:], payload)
+	_, err := w.Write(buf)
+	return err
+}
+
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxMessageSize {
+		return nil, fmt.Errorf("kafka: message size %d exceeds limit", n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// EncodeRecordBatch encodes records as uncompressed record batch of
+// message format v2 (magic 2).
+func EncodeRecordBatch(messages []Record) []byte {
+	firstTimestamp := messages[0].Timestamp.UnixMilli()
+	maxTimestamp := firstTimestamp
+	records := &Encoder{}
+	for i, m := range messages {
+		ts := m.Timestamp.UnixMilli()
+		if ts > maxTimestamp {
+			maxTimestamp = ts
+		}
+		r := &Encoder{}
+		r.Int8(0) // Attributes, unused.
+		r.Varint(ts - firstTimestamp)
+		r.Varint(int64(i))
+		r.VarBytes(m.Key)
+		r.VarBytes(m.Value)
+		r.Varint(0) // No headers.
+		records.Varint(int64(len(r.buf)))
+		records.Raw(r.buf)
+	}
+
+	// Part of a batch covered by CRC.
+	body := &Encoder{}
+	body.Int16(0) // Attributes: no compression, no transactions.
+	body.Int32(int32(len(messages) - 1))
+	body.Int64(firstTimestamp)
+	body.Int64(maxTimestamp)
+	body.Int64(-1) // Producer ID.
+	body.Int16(-1) // Producer epoch.
+	body.Int32(-1) // Base sequence.
+	body.ArrayLen(len(messages))
+	body.Raw(records.buf)
+
+	batch := &Encoder{}
+	batch.Int64(0) // Base offset, assigned by broker.
+	batch.Int32(int32(4 + 1 + 4 + len(body.buf)))
+	batch.Int32(-1) // Partition leader epoch.
+	batch.Int8(2)   // Magic.
+	batch.Uint32(crc32.Checksum(body.buf, castagnoliTable))
+	batch.Raw(body.buf)
+	return batch.buf
+}
+
+// DecodeRecordBatches decodes uncompressed record batches of message
+// format v2.
+func DecodeRecordBatches(data []byte) ([]Record, error) {
+	var messages []Record
+	d := NewDecoder(data)
+	for d.Remaining() > 0 {
+		_ = d.Int64() // Base offset.
+		length := d.Int32()
+		batch := NewDecoder(d.Next(int(length)))
+		if d.err != nil {
+			return nil, d.err
+		}
+		_ = batch.Int32() // Partition leader epoch.
+		if magic := batch.Int8(); magic != 2 {
+			return nil, fmt.Errorf("kafka: unsupported message format %d", magic)
+		}
+		crc := batch.Uint32()
+		if batch.err == nil && crc32.Checksum(batch.buf[batch.off:], castagnoliTable) != crc {
+			return nil, errors.New("kafka: record batch crc mismatch")
+		}
+		if attributes := batch.Int16(); attributes&0x07 != 0 {
+			return nil, errors.New("kafka: compressed record batches are not supported")
+		}
+		_ = batch.Int32() // Last offset delta.
+		firstTimestamp := batch.Int64()
+		_ = batch.Int64() // Max timestamp.
+		_ = batch.Int64() // Producer ID.
+		_ = batch.Int16() // Producer epoch.
+		_ = batch.Int32() // Base sequence.
+		count := batch.ArrayLen()
+		for i := 0; i < count; i++ {
+			record := NewDecoder(batch.Next(int(batch.Varint())))
+			_ = record.Int8() // Attributes.
+			timestampDelta := record.Varint()
+			_ = record.Varint() // Offset delta.
+			m := Record{
+				Key:       record.VarBytes(),
+				Value:     record.VarBytes(),
+				Timestamp: time.UnixMilli(firstTimestamp + timestampDelta),
+			}
+			headers := record.Varint()
+			for j := int64(0); j < headers; j++ {
+				_ = record.VarBytes()
+				_ = record.VarBytes()
+			}
+			if record.err != nil {
+				return nil, record.err
+			}
+			messages = append(messages, m)
+		}
+		if batch.err != nil {
+			return nil, batch.err
+		}
+	}
+	return messages, d.err
+}
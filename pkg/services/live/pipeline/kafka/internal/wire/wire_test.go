@@ -0,0 +1,25 @@
+package wire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordBatch(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	messages := []Record{
+		{Key: []byte("a"), Value: []byte("1"), Timestamp: now},
+		{Value: []byte("2"), Timestamp: now.Add(time.Second)},
+		{Key: []byte("c"), Value: nil, Timestamp: now.Add(2 * time.Second)},
+	}
+	decoded, err := DecodeRecordBatches(EncodeRecordBatch(messages))
+	require.NoError(t, err)
+	require.Equal(t, messages, decoded)
+
+	batch := EncodeRecordBatch(messages)
+	batch[len(batch)-1] ^= 0xff
+	_, err = DecodeRecordBatches(batch)
+	require.Error(t, err)
+}
@@ -0,0 +1,265 @@
+// Package kafkatest contains an in-memory Kafka broker to test Kafka producers.
+package kafkatest
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/kafka"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/kafka/internal/wire"
+)
+
+// Broker is a minimal single node in-memory Kafka broker. It supports only
+// requests sent by kafka.Producer.
+type Broker struct {
+	listener   net.Listener
+	partitions int
+
+	mu          sync.Mutex
+	username    string
+	password    string
+	messages    map[string][]kafka.Message
+	failProduce int
+	conns       map[net.Conn]struct{}
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+// NewBroker starts a broker listening on addr, use "127.0.0.1:0" to pick
+// a free port. Every topic has the given number of partitions.
+func NewBroker(addr string, partitions int) (*Broker, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if partitions <= 0 {
+		partitions = 1
+	}
+	b := &Broker{
+		listener:   l,
+		partitions: partitions,
+		messages:   map[string][]kafka.Message{},
+		conns:      map[net.Conn]struct{}{},
+	}
+	b.wg.Add(1)
+	go b.serve()
+	return b, nil
+}
+
+// Addr returns broker address in host:port form.
+func (b *Broker) Addr() string {
+	return b.listener.Addr().String()
+}
+
+// RequireSASLPlain makes broker require SASL/PLAIN authentication with given
+// credentials for new connections.
+func (b *Broker) RequireSASLPlain(username, password string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.username = username
+	b.password = password
+}
+
+// FailProduce makes next n produce requests fail with
+// kafka.ErrNotLeaderForPartition.
+func (b *Broker) FailProduce(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failProduce = n
+}
+
+// Messages returns all messages produced to a topic.
+func (b *Broker) Messages(topic string) []kafka.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]kafka.Message(nil), b.messages[topic]...)
+}
+
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	for c := range b.conns {
+		_ = c.Close()
+	}
+	b.mu.Unlock()
+	err := b.listener.Close()
+	b.wg.Wait()
+	return err
+}
+
+func (b *Broker) serve() {
+	defer b.wg.Done()
+	for {
+		c, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			_ = c.Close()
+			return
+		}
+		b.conns[c] = struct{}{}
+		b.mu.Unlock()
+		b.wg.Add(1)
+		go b.handle(c)
+	}
+}
+
+func (b *Broker) handle(c net.Conn) {
+	defer b.wg.Done()
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, c)
+		b.mu.Unlock()
+		_ = c.Close()
+	}()
+
+	b.mu.Lock()
+	authenticated := b.username == ""
+	b.mu.Unlock()
+
+	r := bufio.NewReader(c)
+	for {
+		payload, err := wire.ReadFrame(r)
+		if err != nil {
+			return
+		}
+		req := wire.NewDecoder(payload)
+		apiKey := req.Int16()
+		_ = req.Int16() // API version, only versions used by Producer are supported.
+		correlationID := req.Int32()
+		_ = req.String() // Client ID.
+		if req.Err() != nil {
+			return
+		}
+
+		resp := &wire.Encoder{}
+		resp.Int32(correlationID)
+		closeAfter := false
+		switch apiKey {
+		case wire.APIKeySaslHandshake:
+			if req.String() != "PLAIN" {
+				resp.Int16(int16(kafka.ErrUnsupportedSaslMechanism))
+				closeAfter = true
+			} else {
+				resp.Int16(int16(kafka.ErrNone))
+			}
+			resp.ArrayLen(1)
+			resp.String("PLAIN")
+		case wire.APIKeySaslAuthenticate:
+			authBytes := req.Bytes()
+			parts := bytes.Split(authBytes, []byte{0})
+			b.mu.Lock()
+			ok := len(parts) == 3 && string(parts[1]) == b.username && string(parts[2]) == b.password
+			b.mu.Unlock()
+			if ok {
+				authenticated = true
+				resp.Int16(int16(kafka.ErrNone))
+				resp.NullString()
+			} else {
+				resp.Int16(int16(kafka.ErrSaslAuthenticationFailed))
+				resp.String("invalid credentials")
+				closeAfter = true
+			}
+			resp.Bytes([]byte{})
+		case wire.APIKeyMetadata:
+			if !authenticated {
+				return
+			}
+			b.handleMetadata(req, resp)
+		case wire.APIKeyProduce:
+			if !authenticated {
+				return
+			}
+			b.handleProduce(req, resp)
+		default:
+			return
+		}
+		if req.Err() != nil {
+			return
+		}
+		if err := wire.WriteFrame(c, resp.Buffer()); err != nil || closeAfter {
+			return
+		}
+	}
+}
+
+func (b *Broker) handleMetadata(req *wire.Decoder, resp *wire.Encoder) {
+	var topics []string
+	numTopics := req.ArrayLen()
+	for i := 0; i < numTopics; i++ {
+		topics = append(topics, req.String())
+	}
+
+	host, portStr, _ := net.SplitHostPort(b.Addr())
+	port, _ := strconv.Atoi(portStr)
+	resp.ArrayLen(1)
+	resp.Int32(0)
+	resp.String(host)
+	resp.Int32(int32(port))
+	resp.NullString() // Rack.
+	resp.Int32(0)     // Controller ID.
+
+	resp.ArrayLen(len(topics))
+	for _, topic := range topics {
+		resp.Int16(int16(kafka.ErrNone))
+		resp.String(topic)
+		resp.Bool(false)
+		resp.ArrayLen(b.partitions)
+		for p := 0; p < b.partitions; p++ {
+			resp.Int16(int16(kafka.ErrNone))
+			resp.Int32(int32(p))
+			resp.Int32(0) // Leader.
+			resp.ArrayLen(1)
+			resp.Int32(0)
+			resp.ArrayLen(1)
+			resp.Int32(0)
+		}
+	}
+}
+
+func (b *Broker) handleProduce(req *wire.Decoder, resp *wire.Encoder) {
+	_ = req.String() // Transactional ID.
+	_ = req.Int16()  // Acks.
+	_ = req.Int32()  // Timeout.
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fail := b.failProduce > 0
+	if fail {
+		b.failProduce--
+	}
+
+	numTopics := req.ArrayLen()
+	resp.ArrayLen(numTopics)
+	for i := 0; i < numTopics; i++ {
+		topic := req.String()
+		resp.String(topic)
+		numPartitions := req.ArrayLen()
+		resp.ArrayLen(numPartitions)
+		for j := 0; j < numPartitions; j++ {
+			partition := req.Int32()
+			records := req.Bytes()
+			code := kafka.ErrNone
+			if fail {
+				code = kafka.ErrNotLeaderForPartition
+			} else if decoded, err := wire.DecodeRecordBatches(records); err != nil {
+				code = kafka.ErrUnknownServerError
+			} else {
+				for _, r := range decoded {
+					b.messages[topic] = append(b.messages[topic], kafka.Message(r))
+				}
+			}
+			resp.Int32(partition)
+			resp.Int16(int16(code))
+			resp.Int64(int64(len(b.messages[topic])))
+			resp.Int64(-1)
+		}
+	}
+	resp.Int32(0) // Throttle time.
+}
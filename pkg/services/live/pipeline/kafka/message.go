@@ -0,0 +1,63 @@
+package kafka
+
+import (
+	"fmt"
+	"time"
+)
+
+// Message is a single record produced to a topic.
+type Message struct {
+	Key       []byte
+	Value     []byte
+	Timestamp time.Time
+}
+
+// Error is an error code returned by a Kafka broker.
+type Error int16
+
+const (
+	ErrNone                     Error = 0
+	ErrUnknownTopicOrPartition  Error = 3
+	ErrLeaderNotAvailable       Error = 5
+	ErrNotLeaderForPartition    Error = 6
+	ErrRequestTimedOut          Error = 7
+	ErrMessageTooLarge          Error = 10
+	ErrInvalidTopic             Error = 17
+	ErrNotEnoughReplicas        Error = 19
+	ErrTopicAuthorizationFailed Error = 29
+	ErrUnsupportedSaslMechanism Error = 33
+	ErrSaslAuthenticationFailed Error = 58
+	ErrUnknownServerError       Error = -1
+)
+
+var errorNames = map[Error]string{
+	ErrUnknownTopicOrPartition:  "unknown topic or partition",
+	ErrLeaderNotAvailable:       "leader not available",
+	ErrNotLeaderForPartition:    "not leader for partition",
+	ErrRequestTimedOut:          "request timed out",
+	ErrMessageTooLarge:          "message too large",
+	ErrInvalidTopic:             "invalid topic",
+	ErrNotEnoughReplicas:        "not enough replicas",
+	ErrTopicAuthorizationFailed: "topic authorization failed",
+	ErrUnsupportedSaslMechanism: "unsupported sasl mechanism",
+	ErrSaslAuthenticationFailed: "sasl authentication failed",
+	ErrUnknownServerError:       "unknown server error",
+}
+
+func (e Error) Error() string {
+	if name, ok := errorNames[e]; ok {
+		return "kafka: " + name
+	}
+	return fmt.Sprintf("kafka: error code %d", int16(e))
+}
+
+// Retriable reports whether request may succeed if retried, possibly after
+// refreshing cluster metadata.
+func (e Error) Retriable() bool {
+	switch e {
+	case ErrUnknownTopicOrPartition, ErrLeaderNotAvailable, ErrNotLeaderForPartition,
+		ErrRequestTimedOut, ErrNotEnoughReplicas, ErrUnknownServerError:
+		return true
+	}
+	return false
+}
@@ -3,6 +3,8 @@ package pipeline
 import (
 	"fmt"
 
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/nats"
 	"github.com/grafana/grafana/pkg/services/live/pipeline/pattern"
 	"github.com/grafana/grafana/pkg/services/live/pipeline/tree"
 )
@@ -59,6 +61,7 @@ func WriteConfigToDto(b WriteConfig) WriteConfigDto {
 		UID:          b.UID,
 		Settings:     b.Settings,
 		SecureFields: secureFields,
+		Version:      b.Version,
 	}
 }
 
@@ -66,6 +69,7 @@ type WriteConfigDto struct {
 	UID          string          `json:"uid"`
 	Settings     WriteSettings   `json:"settings"`
 	SecureFields map[string]bool `json:"secureFields"`
+	Version      int64           `json:"version,omitempty"`
 }
 
 type WriteConfigGetCmd struct {
@@ -78,11 +82,13 @@ type WriteConfigCreateCmd struct {
 	SecureSettings map[string]string `json:"secureSettings"`
 }
 
-// TODO: add version field later.
 type WriteConfigUpdateCmd struct {
 	UID            string            `json:"uid"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string]string `json:"secureSettings"`
+	// Version is an optional version of write config being updated, update
+	// fails if it does not match the stored one.
+	Version int64 `json:"version,omitempty"`
 }
 
 type WriteConfigDeleteCmd struct {
@@ -94,6 +100,7 @@ type WriteConfig struct {
 	UID            string            `json:"uid"`
 	Settings       WriteSettings     `json:"settings"`
 	SecureSettings map[string][]byte `json:"secureSettings,omitempty"`
+	Version        int64             `json:"version,omitempty"`
 }
 
 func (r WriteConfig) Valid() (bool, string) {
@@ -115,10 +122,14 @@ type BasicAuth struct {
 }
 
 type WriteSettings struct {
-	// Endpoint to send streaming frames to.
+	// Endpoint to send streaming frames to. For Kafka it's a comma separated
+	// list of bootstrap broker addresses.
 	Endpoint string `json:"endpoint"`
-	// BasicAuth is an optional basic auth settings.
+	// BasicAuth is an optional basic auth settings. For Kafka used as
+	// SASL/PLAIN credentials.
 	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
+	// TLS enables TLS for endpoints which are not URLs, i.e. Kafka brokers.
+	TLS bool `json:"tls,omitempty"`
 }
 
 type WriteConfigs struct {
@@ -129,6 +140,60 @@ type ChannelRules struct {
 	Rules []ChannelRule `json:"rules"`
 }
 
+type InputConfigs struct {
+	Configs []InputConfig `json:"inputConfigs"`
+}
+
+func (r InputConfig) Valid() (bool, string) {
+	if r.UID == "" {
+		return false, "uid required"
+	}
+	switch r.Type {
+	case DataInputTypeMQTT:
+		if r.MQTTInputConfig == nil {
+			return false, "missing mqtt configuration"
+		}
+		if r.MQTTInputConfig.URL == "" {
+			return false, "url required"
+		}
+		if len(r.MQTTInputConfig.Topics) == 0 {
+			return false, "at least one topic required"
+		}
+		for _, t := range r.MQTTInputConfig.Topics {
+			if err := mqtt.ValidateFilter(t.Topic); err != nil {
+				return false, err.Error()
+			}
+			if t.QoS > 1 {
+				return false, fmt.Sprintf("unsupported qos level: %d", t.QoS)
+			}
+			if t.Channel == "" {
+				return false, fmt.Sprintf("channel required for topic %s", t.Topic)
+			}
+		}
+	case DataInputTypeNATS:
+		if r.NATSInputConfig == nil {
+			return false, "missing nats configuration"
+		}
+		if r.NATSInputConfig.URL == "" {
+			return false, "url required"
+		}
+		if len(r.NATSInputConfig.Subjects) == 0 {
+			return false, "at least one subject required"
+		}
+		for _, s := range r.NATSInputConfig.Subjects {
+			if err := nats.ValidateSubject(s.Subject); err != nil {
+				return false, err.Error()
+			}
+			if s.Channel == "" {
+				return false, fmt.Sprintf("channel required for subject %s", s.Subject)
+			}
+		}
+	default:
+		return false, fmt.Sprintf("unknown input type: %s", r.Type)
+	}
+	return true, ""
+}
+
 func checkRulesValid(orgID int64, rules []ChannelRule) (ok bool, reason string) {
 	t := tree.New()
 	defer func() {
@@ -154,6 +219,9 @@ type ChannelRuleCreateCmd struct {
 type ChannelRuleUpdateCmd struct {
 	Pattern  string              `json:"pattern"`
 	Settings ChannelRuleSettings `json:"settings"`
+	// Version is an optional version of channel rule being updated, update
+	// fails if it does not match the stored one.
+	Version int64 `json:"version,omitempty"`
 }
 
 type ChannelRuleDeleteCmd struct {
@@ -0,0 +1,291 @@
+// Package mqtt contains a minimal MQTT 3.1.1 client. Only the subset of the
+// protocol required by Live pipeline inputs is implemented: QoS 0 and 1
+// delivery, topic wildcards and keep alive.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt/internal/wire"
+)
+
+// Options to connect to MQTT broker.
+type Options struct {
+	// URL of the broker, for example tcp://localhost:1883. Supported schemes
+	// are tcp, mqtt, ssl, tls and mqtts.
+	URL      string
+	ClientID string
+	Username string
+	Password string
+	// KeepAlive is an interval to send PINGREQ packets with. Defaults to 30s.
+	KeepAlive time.Duration
+	// DialTimeout defaults to 10s.
+	DialTimeout time.Duration
+	TLSConfig   *tls.Config
+}
+
+// Subscription is a topic filter with requested QoS.
+type Subscription struct {
+	Filter string
+	QoS    byte
+}
+
+// Message received from a broker.
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// Client is a minimal MQTT 3.1.1 client. Subscribe must be called before Run.
+type Client struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	keepAlive time.Duration
+
+	writeMu  sync.Mutex
+	packetID uint16
+	running  bool
+}
+
+// ErrConnectionRefused returned when broker rejects connection.
+var ErrConnectionRefused = errors.New("mqtt connection refused")
+
+var connAckReasons = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// Dial connects to a broker and performs MQTT handshake.
+func Dial(ctx context.Context, opts Options) (*Client, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mqtt url: %w", err)
+	}
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = 10 * time.Second
+	}
+	if opts.KeepAlive == 0 {
+		opts.KeepAlive = 30 * time.Second
+	}
+
+	host := u.Host
+	dialer := &net.Dialer{Timeout: opts.DialTimeout}
+	var conn net.Conn
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "1883")
+		}
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	case "ssl", "tls", "mqtts":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "8883")
+		}
+		tlsConfig := opts.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+		}
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: tlsConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", host)
+	default:
+		return nil, fmt.Errorf("unsupported mqtt url scheme: %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:      conn,
+		reader:    bufio.NewReader(conn),
+		keepAlive: opts.KeepAlive,
+	}
+	if err := c.connect(opts); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) connect(opts Options) error {
+	var flags byte = 0x02 // Clean session.
+	if opts.Username != "" {
+		flags |= 0x80
+		if opts.Password != "" {
+			flags |= 0x40
+		}
+	}
+	body := wire.AppendString(nil, wire.ProtocolName)
+	body = append(body, wire.ProtocolLevel, flags)
+	body = wire.AppendUint16(body, uint16(opts.KeepAlive/time.Second))
+	body = wire.AppendString(body, opts.ClientID)
+	if opts.Username != "" {
+		body = wire.AppendString(body, opts.Username)
+		if opts.Password != "" {
+			body = wire.AppendString(body, opts.Password)
+		}
+	}
+
+	_ = c.conn.SetDeadline(time.Now().Add(opts.DialTimeout))
+	defer func() { _ = c.conn.SetDeadline(time.Time{}) }()
+
+	if err := c.write(wire.PacketConnect, 0, body); err != nil {
+		return err
+	}
+	p, err := wire.ReadPacket(c.reader)
+	if err != nil {
+		return fmt.Errorf("error reading connack: %w", err)
+	}
+	if p.Kind != wire.PacketConnAck || len(p.Body) != 2 {
+		return fmt.Errorf("unexpected packet instead of connack: %d", p.Kind)
+	}
+	if code := p.Body[1]; code != 0 {
+		reason, ok := connAckReasons[code]
+		if !ok {
+			reason = fmt.Sprintf("code %d", code)
+		}
+		return fmt.Errorf("%w: %s", ErrConnectionRefused, reason)
+	}
+	return nil
+}
+
+func (c *Client) write(kind byte, flags byte, body []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return wire.WritePacket(c.conn, kind, flags, body)
+}
+
+func (c *Client) nextPacketID() uint16 {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.packetID++
+	if c.packetID == 0 {
+		c.packetID = 1
+	}
+	return c.packetID
+}
+
+// Subscribe to topic filters and wait for broker acknowledgement.
+func (c *Client) Subscribe(subs ...Subscription) error {
+	if c.running {
+		return errors.New("mqtt subscribe must be called before run")
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+	id := c.nextPacketID()
+	body := wire.AppendUint16(nil, id)
+	for _, sub := range subs {
+		if err := ValidateFilter(sub.Filter); err != nil {
+			return err
+		}
+		if sub.QoS > 1 {
+			return fmt.Errorf("unsupported mqtt qos level: %d", sub.QoS)
+		}
+		body = wire.AppendString(body, sub.Filter)
+		body = append(body, sub.QoS)
+	}
+	if err := c.write(wire.PacketSubscribe, 0x02, body); err != nil {
+		return err
+	}
+	for {
+		p, err := wire.ReadPacket(c.reader)
+		if err != nil {
+			return fmt.Errorf("error reading suback: %w", err)
+		}
+		if p.Kind != wire.PacketSubAck {
+			// Retained messages may arrive before SUBACK, they can be safely skipped here.
+			continue
+		}
+		d := wire.NewDecoder(p.Body)
+		if d.Uint16() != id {
+			return wire.ErrMalformedPacket
+		}
+		codes := d.Rest()
+		if len(codes) != len(subs) {
+			return wire.ErrMalformedPacket
+		}
+		for i, code := range codes {
+			if code == 0x80 {
+				return fmt.Errorf("subscription to %s rejected by broker", subs[i].Filter)
+			}
+		}
+		return nil
+	}
+}
+
+// Publish a message with QoS 0.
+func (c *Client) Publish(topic string, payload []byte) error {
+	flags, body := wire.EncodePublish(topic, 0, 0, payload)
+	return c.write(wire.PacketPublish, flags, body)
+}
+
+// Run reads incoming messages and calls handler for each until context
+// is done or connection is closed. Handler is called synchronously.
+func (c *Client) Run(ctx context.Context, handler func(Message)) error {
+	c.running = true
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(c.keepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				_ = c.Close()
+				return
+			case <-ticker.C:
+				if err := c.write(wire.PacketPingReq, 0, nil); err != nil {
+					_ = c.conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		// Broker must answer our PINGREQ within keep alive interval.
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.keepAlive * 2))
+		p, err := wire.ReadPacket(c.reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		switch p.Kind {
+		case wire.PacketPublish:
+			pub, err := wire.DecodePublish(p)
+			if err != nil {
+				return err
+			}
+			if pub.QoS == 1 {
+				if err := c.write(wire.PacketPubAck, 0, wire.AppendUint16(nil, pub.PacketID)); err != nil {
+					return err
+				}
+			}
+			handler(Message{Topic: pub.Topic, Payload: pub.Payload})
+		case wire.PacketPingResp, wire.PacketPubAck, wire.PacketSubAck:
+		default:
+			return fmt.Errorf("unexpected mqtt packet type: %d", p.Kind)
+		}
+	}
+}
+
+// Close sends DISCONNECT and closes connection.
+func (c *Client) Close() error {
+	_ = c.write(wire.PacketDisconnect, 0, nil)
+	return c.conn.Close()
+}
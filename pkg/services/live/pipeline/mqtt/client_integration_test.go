@@ -0,0 +1,76 @@
+package mqtt_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt"
+)
+
+// TestIntegrationClient runs the client against a real broker, e.g. the one
+// from devenv/docker/blocks/mosquitto with MQTT_URL=tcp://localhost:1883.
+func TestIntegrationClient(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	url, ok := os.LookupEnv("MQTT_URL")
+	if !ok || url == "" {
+		t.Skip("No MQTT broker URL supplied")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	prefix := fmt.Sprintf("grafana-test/%d", time.Now().UnixNano())
+	sub, err := mqtt.Dial(ctx, mqtt.Options{URL: url, ClientID: "grafana-test-sub", KeepAlive: time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sub.Close() })
+	require.NoError(t, sub.Subscribe(
+		mqtt.Subscription{Filter: prefix + "/+/temp", QoS: 1},
+		mqtt.Subscription{Filter: prefix + "/alerts/#", QoS: 0},
+	))
+
+	received := make(chan mqtt.Message, 10)
+	go func() {
+		_ = sub.Run(ctx, func(m mqtt.Message) {
+			received <- m
+		})
+	}()
+
+	pub, err := mqtt.Dial(ctx, mqtt.Options{URL: url, ClientID: "grafana-test-pub"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pub.Close() })
+	require.NoError(t, pub.Publish(prefix+"/kitchen/humidity", []byte(`{"value":50}`)))
+	require.NoError(t, pub.Publish(prefix+"/kitchen/temp", []byte(`{"value":21.5}`)))
+	// Payload larger than 127 bytes needs multi byte remaining length.
+	large := make([]byte, 70000)
+	require.NoError(t, pub.Publish(prefix+"/alerts/kitchen/fire", large))
+
+	for _, expected := range []mqtt.Message{
+		{Topic: prefix + "/kitchen/temp", Payload: []byte(`{"value":21.5}`)},
+		{Topic: prefix + "/alerts/kitchen/fire", Payload: large},
+	} {
+		select {
+		case m := <-received:
+			require.Equal(t, expected.Topic, m.Topic)
+			require.Equal(t, expected.Payload, m.Payload)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for message")
+		}
+	}
+
+	// Keep alive pings keep the connection open longer than keep alive interval.
+	time.Sleep(3 * time.Second)
+	require.NoError(t, pub.Publish(prefix+"/kitchen/temp", []byte(`{"value":22}`)))
+	select {
+	case m := <-received:
+		require.Equal(t, `{"value":22}`, string(m.Payload))
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for message after keep alive")
+	}
+}
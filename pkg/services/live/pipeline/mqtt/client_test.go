@@ -0,0 +1,62 @@
+package mqtt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt/mqtttest"
+)
+
+func TestClient_SubscribeAndReceive(t *testing.T) {
+	broker, err := mqtttest.NewBroker("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = broker.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sub, err := mqtt.Dial(ctx, mqtt.Options{URL: broker.URL(), ClientID: "sub"})
+	require.NoError(t, err)
+	require.NoError(t, sub.Subscribe(mqtt.Subscription{Filter: "sensors/+/temp", QoS: 1}))
+
+	received := make(chan mqtt.Message, 1)
+	go func() {
+		_ = sub.Run(ctx, func(m mqtt.Message) {
+			received <- m
+		})
+	}()
+
+	pub, err := mqtt.Dial(ctx, mqtt.Options{URL: broker.URL(), ClientID: "pub"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pub.Close() })
+	require.NoError(t, pub.Publish("sensors/kitchen/humidity", []byte(`{"value":50}`)))
+	require.NoError(t, pub.Publish("sensors/kitchen/temp", []byte(`{"value":21.5}`)))
+
+	select {
+	case m := <-received:
+		require.Equal(t, "sensors/kitchen/temp", m.Topic)
+		require.Equal(t, `{"value":21.5}`, string(m.Payload))
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestClient_InvalidFilter(t *testing.T) {
+	broker, err := mqtttest.NewBroker("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = broker.Close() })
+
+	c, err := mqtt.Dial(context.Background(), mqtt.Options{URL: broker.URL(), ClientID: "test"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	require.Error(t, c.Subscribe(mqtt.Subscription{Filter: "sensors/#/temp"}))
+}
+
+func TestDial_UnsupportedScheme(t *testing.T) {
+	_, err := mqtt.Dial(context.Background(), mqtt.Options{URL: "ws://localhost:1883"})
+	require.Error(t, err)
+}
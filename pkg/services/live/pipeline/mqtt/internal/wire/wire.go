@@ -0,0 +1,208 @@
+// Package wire encodes and decodes MQTT 3.1.1 control packets. It is shared
+// by the mqtt client and the test broker in mqtttest.
+package wire
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	PacketConnect     byte = 1
+	PacketConnAck     byte = 2
+	PacketPublish     byte = 3
+	PacketPubAck      byte = 4
+	PacketSubscribe   byte = 8
+	PacketSubAck      byte = 9
+	PacketPingReq     byte = 12
+	PacketPingResp    byte = 13
+	PacketDisconnect  byte = 14
+	maxRemainingBytes      = 268435455
+)
+
+const (
+	ProtocolName  = "MQTT"
+	ProtocolLevel = 4
+)
+
+var ErrMalformedPacket = errors.New("malformed mqtt packet")
+
+// Packet is an MQTT control packet with its fixed header split into the
+// packet type and flags.
+type Packet struct {
+	Kind  byte
+	Flags byte
+	Body  []byte
+}
+
+func ReadPacket(r *bufio.Reader) (Packet, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return Packet{}, err
+	}
+	length, err := readRemainingLength(r)
+	if err != nil {
+		return Packet{}, err
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return Packet{}, err
+	}
+	return Packet{Kind: header >> 4, Flags: header & 0x0f, Body: body}, nil
+}
+
+func readRemainingLength(r io.ByteReader) (int, error) {
+	var (
+		value      int
+		multiplier = 1
+	)
+	for i := 0; i < 4; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value += int(b&127) * multiplier
+		if b&128 == 0 {
+			return value, nil
+		}
+		multiplier *= 128
+	}
+	return 0, ErrMalformedPacket
+}
+
+func WritePacket(w io.Writer, kind byte, flags byte, body []byte) error {
+	if len(body) > maxRemainingBytes {
+		return fmt.Errorf("mqtt packet too large: %d bytes", len(body))
+	}
+	buf := make([]byte, 0, len(body)+5)
+	buf = append(buf, kind<<4|flags&0x0f)
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 128
+		}
+		buf = append(buf, b)
+		if length == 0 {
+			break
+		}
+	}
+	buf = append(buf, body...)
+	_, err := w.Write(buf)
+	return err
+}
+
+func AppendString(buf []byte, s string) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(s)))
+	return append(buf, s...)
+}
+
+func AppendUint16(buf []byte, v uint16) []byte {
+	return binary.BigEndian.AppendUint16(buf, v)
+}
+
+// Decoder reads MQTT primitive values from a packet body. The first error is
+// kept and makes all subsequent reads return zero values.
+type Decoder struct {
+	buf []byte
+	err error
+}
+
+func NewDecoder(body []byte) *Decoder {
+	return &Decoder{buf: body}
+}
+
+// Err returns the first error met while decoding.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+func (d *Decoder) Uint16() uint16 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 2 {
+		d.err = ErrMalformedPacket
+		return 0
+	}
+	v := binary.BigEndian.Uint16(d.buf)
+	d.buf = d.buf[2:]
+	return v
+}
+
+func (d *Decoder) Byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 1 {
+		d.err = ErrMalformedPacket
+		return 0
+	}
+	v := d.buf[0]
+	d.buf = d.buf[1:]
+	return v
+}
+
+func (d *Decoder) String() string {
+	n := int(d.Uint16())
+	if d.err != nil {
+		return ""
+	}
+	if len(d.buf) < n {
+		d.err = ErrMalformedPacket
+		return ""
+	}
+	v := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *Decoder) Rest() []byte {
+	v := d.buf
+	d.buf = nil
+	return v
+}
+
+func (d *Decoder) Empty() bool {
+	return len(d.buf) == 0
+}
+
+// PublishPacket is a decoded PUBLISH packet.
+type PublishPacket struct {
+	Topic    string
+	QoS      byte
+	PacketID uint16
+	Payload  []byte
+}
+
+func DecodePublish(p Packet) (PublishPacket, error) {
+	d := NewDecoder(p.Body)
+	pub := PublishPacket{
+		QoS:   (p.Flags >> 1) & 0x03,
+		Topic: d.String(),
+	}
+	if pub.QoS > 0 {
+		pub.PacketID = d.Uint16()
+	}
+	pub.Payload = d.Rest()
+	if d.Err() != nil {
+		return PublishPacket{}, d.Err()
+	}
+	if pub.QoS > 1 {
+		return PublishPacket{}, fmt.Errorf("unsupported mqtt qos level: %d", pub.QoS)
+	}
+	return pub, nil
+}
+
+func EncodePublish(topic string, qos byte, packetID uint16, payload []byte) (byte, []byte) {
+	body := AppendString(nil, topic)
+	if qos > 0 {
+		body = AppendUint16(body, packetID)
+	}
+	body = append(body, payload...)
+	return qos << 1, body
+}
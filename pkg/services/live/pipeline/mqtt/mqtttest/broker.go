@@ -0,0 +1,202 @@
+// Package mqtttest contains an in-process MQTT broker to test MQTT clients.
+package mqtttest
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/mqtt/internal/wire"
+)
+
+// Broker is a small in-process MQTT broker. It delivers every message with
+// QoS 0 to all matching subscribers and keeps no sessions or retained
+// messages.
+type Broker struct {
+	listener net.Listener
+
+	mu      sync.RWMutex
+	clients map[*brokerClient]struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+type brokerClient struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+
+	mu      sync.RWMutex
+	filters []string
+}
+
+// NewBroker starts a broker listening on addr, use "127.0.0.1:0" to pick
+// a free port.
+func NewBroker(addr string) (*Broker, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	b := &Broker{
+		listener: ln,
+		clients:  map[*brokerClient]struct{}{},
+	}
+	b.wg.Add(1)
+	go b.acceptLoop()
+	return b, nil
+}
+
+// URL to use in client Options.
+func (b *Broker) URL() string {
+	return "tcp://" + b.listener.Addr().String()
+}
+
+// Close stops the broker and disconnects all clients.
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	for c := range b.clients {
+		_ = c.conn.Close()
+	}
+	b.mu.Unlock()
+	err := b.listener.Close()
+	b.wg.Wait()
+	return err
+}
+
+func (b *Broker) acceptLoop() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &brokerClient{conn: conn}
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		b.clients[c] = struct{}{}
+		b.mu.Unlock()
+
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.serve(c)
+			b.mu.Lock()
+			delete(b.clients, c)
+			b.mu.Unlock()
+			_ = conn.Close()
+		}()
+	}
+}
+
+func (c *brokerClient) write(kind byte, flags byte, body []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return wire.WritePacket(c.conn, kind, flags, body)
+}
+
+func (b *Broker) serve(c *brokerClient) {
+	reader := bufio.NewReader(c.conn)
+
+	p, err := wire.ReadPacket(reader)
+	if err != nil || p.Kind != wire.PacketConnect {
+		return
+	}
+	d := wire.NewDecoder(p.Body)
+	if d.String() != wire.ProtocolName || d.Byte() != wire.ProtocolLevel || d.Err() != nil {
+		_ = c.write(wire.PacketConnAck, 0, []byte{0, 1})
+		return
+	}
+	if err := c.write(wire.PacketConnAck, 0, []byte{0, 0}); err != nil {
+		return
+	}
+
+	for {
+		p, err := wire.ReadPacket(reader)
+		if err != nil {
+			return
+		}
+		switch p.Kind {
+		case wire.PacketSubscribe:
+			if err := b.handleSubscribe(c, p); err != nil {
+				return
+			}
+		case wire.PacketPublish:
+			pub, err := wire.DecodePublish(p)
+			if err != nil {
+				return
+			}
+			if pub.QoS == 1 {
+				if err := c.write(wire.PacketPubAck, 0, wire.AppendUint16(nil, pub.PacketID)); err != nil {
+					return
+				}
+			}
+			b.Publish(pub.Topic, pub.Payload)
+		case wire.PacketPingReq:
+			if err := c.write(wire.PacketPingResp, 0, nil); err != nil {
+				return
+			}
+		case wire.PacketPubAck:
+		case wire.PacketDisconnect:
+			return
+		default:
+			return
+		}
+	}
+}
+
+func (b *Broker) handleSubscribe(c *brokerClient, p wire.Packet) error {
+	d := wire.NewDecoder(p.Body)
+	id := d.Uint16()
+	var codes []byte
+	for d.Err() == nil && !d.Empty() {
+		filter := d.String()
+		_ = d.Byte()
+		if d.Err() != nil {
+			break
+		}
+		if mqtt.ValidateFilter(filter) != nil {
+			codes = append(codes, 0x80)
+			continue
+		}
+		c.mu.Lock()
+		c.filters = append(c.filters, filter)
+		c.mu.Unlock()
+		codes = append(codes, 0)
+	}
+	if d.Err() != nil {
+		return d.Err()
+	}
+	if len(codes) == 0 {
+		return errors.New("subscribe without topic filters")
+	}
+	return c.write(wire.PacketSubAck, 0, append(wire.AppendUint16(nil, id), codes...))
+}
+
+// Publish delivers a message to all subscribers with a matching filter.
+func (b *Broker) Publish(topic string, payload []byte) {
+	flags, body := wire.EncodePublish(topic, 0, 0, payload)
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for c := range b.clients {
+		if c.matches(topic) {
+			_ = c.write(wire.PacketPublish, flags, body)
+		}
+	}
+}
+
+func (c *brokerClient) matches(topic string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, filter := range c.filters {
+		if mqtt.MatchTopic(filter, topic) {
+			return true
+		}
+	}
+	return false
+}
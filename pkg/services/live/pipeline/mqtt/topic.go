@@ -0,0 +1,50 @@
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatchTopic reports whether a topic name matches a topic filter. Filters
+// may contain the single level wildcard "+" and the multi level wildcard "#"
+// (which must be the last level).
+func MatchTopic(filter string, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	// Topics starting with $ are reserved for the broker and are not
+	// matched by filters starting with a wildcard.
+	if strings.HasPrefix(topic, "$") && (filterLevels[0] == "+" || filterLevels[0] == "#") {
+		return false
+	}
+
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
+
+// ValidateFilter checks that a topic filter is well-formed.
+func ValidateFilter(filter string) error {
+	if filter == "" {
+		return fmt.Errorf("empty topic filter")
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return fmt.Errorf("invalid topic filter %q: # must be the last level", filter)
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return fmt.Errorf("invalid topic filter %q: + must occupy an entire level", filter)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		filter string
+		topic  string
+		match  bool
+	}{
+		{"sensors/temp", "sensors/temp", true},
+		{"sensors/temp", "sensors/humidity", false},
+		{"sensors/+", "sensors/temp", true},
+		{"sensors/+", "sensors/temp/1", false},
+		{"sensors/+/1", "sensors/temp/1", true},
+		{"sensors/#", "sensors", true},
+		{"sensors/#", "sensors/temp/1", true},
+		{"#", "sensors/temp", true},
+		{"#", "$SYS/broker", false},
+		{"+/temp", "$SYS/temp", false},
+		{"sensors/temp", "sensors/temp/1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter+"_"+tt.topic, func(t *testing.T) {
+			require.Equal(t, tt.match, MatchTopic(tt.filter, tt.topic))
+		})
+	}
+}
+
+func TestValidateFilter(t *testing.T) {
+	require.NoError(t, ValidateFilter("sensors/+/temp"))
+	require.NoError(t, ValidateFilter("sensors/#"))
+	require.Error(t, ValidateFilter(""))
+	require.Error(t, ValidateFilter("sensors/#/temp"))
+	require.Error(t, ValidateFilter("sensors/te+"))
+	require.Error(t, ValidateFilter("sensors/te#"))
+}
@@ -0,0 +1,342 @@
+package nats
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options to connect to NATS server.
+type Options struct {
+	// URL of the server, for example nats://localhost:4222. Supported schemes
+	// are nats and tls.
+	URL      string
+	Name     string
+	Username string
+	Password string
+	Token    string
+	// PingInterval defaults to 30s.
+	PingInterval time.Duration
+	// DialTimeout defaults to 10s.
+	DialTimeout time.Duration
+	TLSConfig   *tls.Config
+}
+
+// Message received from a server.
+type Message struct {
+	// SID is an identifier of subscription returned by Client.Subscribe.
+	SID     int
+	Subject string
+	Reply   string
+	Data    []byte
+}
+
+// Client is a minimal NATS client. Subscribe must be called before Run.
+type Client struct {
+	conn         net.Conn
+	reader       *bufio.Reader
+	pingInterval time.Duration
+
+	writeMu sync.Mutex
+	nextSID int
+	running bool
+}
+
+// ErrServer wraps -ERR messages sent by server.
+var ErrServer = errors.New("nats server error")
+
+type serverInfo struct {
+	ServerID     string `json:"server_id"`
+	Version      string `json:"version"`
+	AuthRequired bool   `json:"auth_required"`
+	TLSRequired  bool   `json:"tls_required"`
+	MaxPayload   int64  `json:"max_payload"`
+}
+
+type connectOptions struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name,omitempty"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+	Protocol int    `json:"protocol"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+}
+
+// Dial connects to a server and performs NATS handshake.
+func Dial(ctx context.Context, opts Options) (*Client, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nats url: %w", err)
+	}
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = 10 * time.Second
+	}
+	if opts.PingInterval == 0 {
+		opts.PingInterval = 30 * time.Second
+	}
+	if u.User != nil && opts.Username == "" {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	var tlsConfig *tls.Config
+	switch u.Scheme {
+	case "nats":
+	case "tls":
+		tlsConfig = opts.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported nats url scheme: %s", u.Scheme)
+	}
+	dialer := &net.Dialer{Timeout: opts.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:         conn,
+		reader:       bufio.NewReader(conn),
+		pingInterval: opts.PingInterval,
+	}
+	_ = conn.SetDeadline(time.Now().Add(opts.DialTimeout))
+	if err := c.connect(opts, tlsConfig); err != nil {
+		_ = c.conn.Close()
+		return nil, err
+	}
+	_ = c.conn.SetDeadline(time.Time{})
+	return c, nil
+}
+
+func (c *Client) connect(opts Options, tlsConfig *tls.Config) error {
+	line, err := c.readLine()
+	if err != nil {
+		return fmt.Errorf("error reading info: %w", err)
+	}
+	op, args := splitOp(line)
+	if op != "INFO" {
+		return fmt.Errorf("unexpected nats op instead of INFO: %s", op)
+	}
+	var info serverInfo
+	if err := json.Unmarshal([]byte(args), &info); err != nil {
+		return fmt.Errorf("error decoding server info: %w", err)
+	}
+	if info.TLSRequired && tlsConfig == nil {
+		return errors.New("nats server requires tls, use tls:// url scheme")
+	}
+	if tlsConfig != nil {
+		// Server sends INFO in plain text, connection is upgraded to TLS after that.
+		tlsConn := tls.Client(c.conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return fmt.Errorf("tls handshake error: %w", err)
+		}
+		c.conn = tlsConn
+		c.reader = bufio.NewReader(tlsConn)
+	}
+
+	connectJSON, err := json.Marshal(connectOptions{
+		Name:     opts.Name,
+		Lang:     "go",
+		Version:  "grafana-live",
+		Protocol: 1,
+		User:     opts.Username,
+		Pass:     opts.Password,
+		Token:    opts.Token,
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.write("CONNECT " + string(connectJSON) + "\r\n"); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// flush sends PING and waits for PONG so that all previously sent
+// commands are known to be processed by the server.
+func (c *Client) flush() error {
+	if err := c.write("PING\r\n"); err != nil {
+		return err
+	}
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return err
+		}
+		op, args := splitOp(line)
+		switch op {
+		case "PONG":
+			return nil
+		case "-ERR":
+			return fmt.Errorf("%w: %s", ErrServer, args)
+		case "MSG":
+			// Skip messages for subscriptions made before Run.
+			if _, err := c.readMsg(args); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *Client) write(s string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := io.WriteString(c.conn, s)
+	return err
+}
+
+func (c *Client) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func splitOp(line string) (string, string) {
+	op, args, _ := strings.Cut(line, " ")
+	return strings.ToUpper(op), strings.TrimSpace(args)
+}
+
+// Subscribe to a subject, queue is optional. Returns subscription
+// identifier which is then set to Message.SID of matching messages.
+func (c *Client) Subscribe(subject string, queue string) (int, error) {
+	if c.running {
+		return 0, errors.New("nats subscribe must be called before run")
+	}
+	if err := ValidateSubject(subject); err != nil {
+		return 0, err
+	}
+	if strings.ContainsAny(queue, " \t\r\n") {
+		return 0, fmt.Errorf("invalid queue group: %q", queue)
+	}
+	c.nextSID++
+	cmd := "SUB " + subject + " "
+	if queue != "" {
+		cmd += queue + " "
+	}
+	cmd += strconv.Itoa(c.nextSID) + "\r\n"
+	if err := c.write(cmd); err != nil {
+		return 0, err
+	}
+	return c.nextSID, c.flush()
+}
+
+// Publish a message to a subject.
+func (c *Client) Publish(subject string, data []byte) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n*>") {
+		return fmt.Errorf("invalid publish subject: %q", subject)
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	buf := make([]byte, 0, len(subject)+len(data)+32)
+	buf = append(buf, "PUB "+subject+" "+strconv.Itoa(len(data))+"\r\n"...)
+	buf = append(buf, data...)
+	buf = append(buf, "\r\n"...)
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+func (c *Client) readMsg(args string) (Message, error) {
+	// MSG <subject> <sid> [reply-to] <#bytes>
+	fields := strings.Fields(args)
+	if len(fields) != 3 && len(fields) != 4 {
+		return Message{}, fmt.Errorf("malformed nats MSG: %s", args)
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || size < 0 {
+		return Message{}, fmt.Errorf("malformed nats MSG size: %s", args)
+	}
+	sid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Message{}, fmt.Errorf("malformed nats MSG sid: %s", args)
+	}
+	payload := make([]byte, size+2)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return Message{}, err
+	}
+	msg := Message{SID: sid, Subject: fields[0], Data: payload[:size]}
+	if len(fields) == 4 {
+		msg.Reply = fields[2]
+	}
+	return msg, nil
+}
+
+// Run reads incoming messages and calls handler for each until context
+// is done or connection is closed. Handler is called synchronously.
+func (c *Client) Run(ctx context.Context, handler func(Message)) error {
+	c.running = true
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(c.pingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				_ = c.Close()
+				return
+			case <-ticker.C:
+				if err := c.write("PING\r\n"); err != nil {
+					_ = c.conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.pingInterval * 2))
+		line, err := c.readLine()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		op, args := splitOp(line)
+		switch op {
+		case "MSG":
+			msg, err := c.readMsg(args)
+			if err != nil {
+				return err
+			}
+			handler(msg)
+		case "PING":
+			if err := c.write("PONG\r\n"); err != nil {
+				return err
+			}
+		case "-ERR":
+			return fmt.Errorf("%w: %s", ErrServer, args)
+		case "PONG", "+OK", "INFO":
+		default:
+			return fmt.Errorf("unexpected nats op: %s", op)
+		}
+	}
+}
+
+// Close closes connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
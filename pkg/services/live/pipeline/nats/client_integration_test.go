@@ -0,0 +1,76 @@
+package nats_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/nats"
+)
+
+// TestIntegrationClient runs the client against a real server, e.g. the one
+// from devenv/docker/blocks/nats with NATS_URL=nats://localhost:4222.
+func TestIntegrationClient(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	url, ok := os.LookupEnv("NATS_URL")
+	if !ok || url == "" {
+		t.Skip("No NATS server URL supplied")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	prefix := fmt.Sprintf("grafana-test.%d", time.Now().UnixNano())
+	sub, err := nats.Dial(ctx, nats.Options{URL: url, Name: "grafana-test-sub", PingInterval: time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sub.Close() })
+	tempSID, err := sub.Subscribe(prefix+".*.temp", "")
+	require.NoError(t, err)
+	alertsSID, err := sub.Subscribe(prefix+".alerts.>", "workers")
+	require.NoError(t, err)
+
+	received := make(chan nats.Message, 10)
+	go func() {
+		_ = sub.Run(ctx, func(m nats.Message) {
+			received <- m
+		})
+	}()
+
+	pub, err := nats.Dial(ctx, nats.Options{URL: url, Name: "grafana-test-pub"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pub.Close() })
+	require.NoError(t, pub.Publish(prefix+".kitchen.humidity", []byte(`{"value":50}`)))
+	require.NoError(t, pub.Publish(prefix+".kitchen.temp", []byte(`{"value":21.5}`)))
+	large := make([]byte, 70000)
+	require.NoError(t, pub.Publish(prefix+".alerts.kitchen.fire", large))
+
+	for _, expected := range []nats.Message{
+		{SID: tempSID, Subject: prefix + ".kitchen.temp", Data: []byte(`{"value":21.5}`)},
+		{SID: alertsSID, Subject: prefix + ".alerts.kitchen.fire", Data: large},
+	} {
+		select {
+		case m := <-received:
+			require.Equal(t, expected.SID, m.SID)
+			require.Equal(t, expected.Subject, m.Subject)
+			require.Equal(t, expected.Data, m.Data)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for message")
+		}
+	}
+
+	// Server pings are answered, so connection outlives ping interval.
+	time.Sleep(3 * time.Second)
+	require.NoError(t, pub.Publish(prefix+".kitchen.temp", []byte(`{"value":22}`)))
+	select {
+	case m := <-received:
+		require.Equal(t, `{"value":22}`, string(m.Data))
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for message after ping interval")
+	}
+}
@@ -0,0 +1,123 @@
+package nats_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/nats"
+	"github.com/grafana/grafana/pkg/services/live/pipeline/nats/natstest"
+)
+
+func TestMatchSubject(t *testing.T) {
+	tests := []struct {
+		pattern string
+		subject string
+		match   bool
+	}{
+		{"sensors.temp", "sensors.temp", true},
+		{"sensors.temp", "sensors.humidity", false},
+		{"sensors.*", "sensors.temp", true},
+		{"sensors.*", "sensors.temp.1", false},
+		{"sensors.*.1", "sensors.temp.1", true},
+		{"sensors.>", "sensors.temp.1", true},
+		{"sensors.>", "sensors", false},
+		{">", "sensors", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.subject, func(t *testing.T) {
+			require.Equal(t, tt.match, nats.MatchSubject(tt.pattern, tt.subject))
+		})
+	}
+}
+
+func TestValidateSubject(t *testing.T) {
+	require.NoError(t, nats.ValidateSubject("sensors.*.temp"))
+	require.NoError(t, nats.ValidateSubject("sensors.>"))
+	require.Error(t, nats.ValidateSubject(""))
+	require.Error(t, nats.ValidateSubject("sensors..temp"))
+	require.Error(t, nats.ValidateSubject("sensors.>.temp"))
+	require.Error(t, nats.ValidateSubject("sensors.te*"))
+	require.Error(t, nats.ValidateSubject("sensors temp"))
+}
+
+func TestClient_SubscribeAndReceive(t *testing.T) {
+	server, err := natstest.NewServer("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sub, err := nats.Dial(ctx, nats.Options{URL: server.URL(), Name: "sub"})
+	require.NoError(t, err)
+	sid, err := sub.Subscribe("sensors.*.temp", "")
+	require.NoError(t, err)
+
+	received := make(chan nats.Message, 1)
+	go func() {
+		_ = sub.Run(ctx, func(m nats.Message) {
+			received <- m
+		})
+	}()
+
+	pub, err := nats.Dial(ctx, nats.Options{URL: server.URL(), Name: "pub"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pub.Close() })
+	require.NoError(t, pub.Publish("sensors.kitchen.humidity", []byte(`{"value":50}`)))
+	require.NoError(t, pub.Publish("sensors.kitchen.temp", []byte(`{"value":21.5}`)))
+
+	select {
+	case m := <-received:
+		require.Equal(t, sid, m.SID)
+		require.Equal(t, "sensors.kitchen.temp", m.Subject)
+		require.Equal(t, `{"value":21.5}`, string(m.Data))
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for message")
+	}
+}
+
+func TestClient_QueueGroup(t *testing.T) {
+	server, err := natstest.NewServer("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan string, 10)
+	for _, name := range []string{"a", "b"} {
+		name := name
+		c, err := nats.Dial(ctx, nats.Options{URL: server.URL(), Name: name})
+		require.NoError(t, err)
+		_, err = c.Subscribe("jobs", "workers")
+		require.NoError(t, err)
+		go func() {
+			_ = c.Run(ctx, func(m nats.Message) {
+				received <- name
+			})
+		}()
+	}
+
+	pub, err := nats.Dial(ctx, nats.Options{URL: server.URL()})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pub.Close() })
+	for i := 0; i < 4; i++ {
+		require.NoError(t, pub.Publish("jobs", []byte("x")))
+	}
+
+	for i := 0; i < 4; i++ {
+		select {
+		case <-received:
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for message")
+		}
+	}
+	select {
+	case <-received:
+		t.Fatal("queue group message delivered more than once")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,248 @@
+// Package natstest contains an in-process NATS server to test NATS clients.
+package natstest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/nats"
+)
+
+const serverMaxPayload = 1 << 20
+
+// Server is a small in-process NATS server. It supports plain and queue
+// subscriptions without authentication or clustering.
+type Server struct {
+	listener net.Listener
+
+	mu         sync.RWMutex
+	clients    map[*serverClient]struct{}
+	closed     bool
+	queueIndex uint64
+	wg         sync.WaitGroup
+}
+
+type serverClient struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+
+	mu   sync.RWMutex
+	subs map[string]serverSubscription
+}
+
+type serverSubscription struct {
+	subject string
+	queue   string
+}
+
+// NewServer starts a server listening on addr, use "127.0.0.1:0" to pick
+// a free port.
+func NewServer(addr string) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		listener: ln,
+		clients:  map[*serverClient]struct{}{},
+	}
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return s, nil
+}
+
+// URL to use in client Options.
+func (s *Server) URL() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+// Close stops the server and disconnects all clients.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.clients {
+		_ = c.conn.Close()
+	}
+	s.mu.Unlock()
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &serverClient{conn: conn, subs: map[string]serverSubscription{}}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.clients[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serve(c)
+			s.mu.Lock()
+			delete(s.clients, c)
+			s.mu.Unlock()
+			_ = conn.Close()
+		}()
+	}
+}
+
+func (c *serverClient) write(b []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(b)
+	return err
+}
+
+func (s *Server) serve(c *serverClient) {
+	info := fmt.Sprintf(`INFO {"server_id":"grafana-test","version":"2.10.0","proto":1,"max_payload":%d}`+"\r\n", serverMaxPayload)
+	if err := c.write([]byte(info)); err != nil {
+		return
+	}
+	reader := bufio.NewReader(c.conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		op, args, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		args = strings.TrimSpace(args)
+		switch strings.ToUpper(op) {
+		case "CONNECT":
+		case "PING":
+			err = c.write([]byte("PONG\r\n"))
+		case "PONG":
+		case "SUB":
+			err = c.subscribe(args)
+		case "UNSUB":
+			fields := strings.Fields(args)
+			if len(fields) > 0 {
+				c.mu.Lock()
+				delete(c.subs, fields[0])
+				c.mu.Unlock()
+			}
+		case "PUB":
+			err = s.handlePublish(reader, args)
+		default:
+			_ = c.write([]byte("-ERR 'Unknown Protocol Operation'\r\n"))
+			return
+		}
+		if err != nil {
+			_ = c.write([]byte(fmt.Sprintf("-ERR '%s'\r\n", err.Error())))
+			return
+		}
+	}
+}
+
+func (c *serverClient) subscribe(args string) error {
+	// SUB <subject> [queue group] <sid>
+	fields := strings.Fields(args)
+	var sub serverSubscription
+	var sid string
+	switch len(fields) {
+	case 2:
+		sub.subject, sid = fields[0], fields[1]
+	case 3:
+		sub.subject, sub.queue, sid = fields[0], fields[1], fields[2]
+	default:
+		return fmt.Errorf("invalid subscription")
+	}
+	if err := nats.ValidateSubject(sub.subject); err != nil {
+		return fmt.Errorf("invalid subject")
+	}
+	c.mu.Lock()
+	c.subs[sid] = sub
+	c.mu.Unlock()
+	return nil
+}
+
+func (s *Server) handlePublish(reader *bufio.Reader, args string) error {
+	// PUB <subject> [reply-to] <#bytes>
+	fields := strings.Fields(args)
+	if len(fields) != 2 && len(fields) != 3 {
+		return fmt.Errorf("invalid publish")
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid publish size")
+	}
+	if size > serverMaxPayload {
+		return fmt.Errorf("maximum payload exceeded")
+	}
+	payload := make([]byte, size+2)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return err
+	}
+	reply := ""
+	if len(fields) == 3 {
+		reply = fields[1]
+	}
+	s.Publish(fields[0], reply, payload[:size])
+	return nil
+}
+
+type delivery struct {
+	client *serverClient
+	sid    string
+}
+
+// Publish delivers a message to all matching subscriptions. For queue
+// subscriptions only one member of each queue group receives a message.
+func (s *Server) Publish(subject string, reply string, data []byte) {
+	s.mu.Lock()
+	s.queueIndex++
+	queueIndex := s.queueIndex
+	s.mu.Unlock()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var deliveries []delivery
+	queues := map[string][]delivery{}
+	for c := range s.clients {
+		c.mu.RLock()
+		for sid, sub := range c.subs {
+			if !nats.MatchSubject(sub.subject, subject) {
+				continue
+			}
+			if sub.queue != "" {
+				queues[sub.queue] = append(queues[sub.queue], delivery{client: c, sid: sid})
+				continue
+			}
+			deliveries = append(deliveries, delivery{client: c, sid: sid})
+		}
+		c.mu.RUnlock()
+	}
+	for _, members := range queues {
+		deliveries = append(deliveries, members[queueIndex%uint64(len(members))])
+	}
+
+	for _, d := range deliveries {
+		header := "MSG " + subject + " " + d.sid + " "
+		if reply != "" {
+			header += reply + " "
+		}
+		header += strconv.Itoa(len(data)) + "\r\n"
+		buf := make([]byte, 0, len(header)+len(data)+2)
+		buf = append(buf, header...)
+		buf = append(buf, data...)
+		buf = append(buf, "\r\n"...)
+		_ = d.client.write(buf)
+	}
+}
@@ -0,0 +1,53 @@
+// Package nats contains a minimal NATS core protocol client. Only the subset
+// of the protocol required by Live pipeline inputs is implemented: plain
+// subscriptions, queue groups and subject wildcards. JetStream is not
+// supported.
+package nats
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatchSubject reports whether a subject matches a subscription subject
+// which may contain "*" (single token) and ">" (one or more trailing tokens)
+// wildcards.
+func MatchSubject(pattern string, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) {
+			return false
+		}
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
+
+// ValidateSubject checks that a subscription subject is well-formed.
+func ValidateSubject(subject string) error {
+	if subject == "" {
+		return fmt.Errorf("empty subject")
+	}
+	if strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("invalid subject %q: whitespace not allowed", subject)
+	}
+	tokens := strings.Split(subject, ".")
+	for i, token := range tokens {
+		if token == "" {
+			return fmt.Errorf("invalid subject %q: empty token", subject)
+		}
+		if strings.Contains(token, ">") && (token != ">" || i != len(tokens)-1) {
+			return fmt.Errorf("invalid subject %q: > must be the last token", subject)
+		}
+		if strings.Contains(token, "*") && token != "*" {
+			return fmt.Errorf("invalid subject %q: * must occupy an entire token", subject)
+		}
+	}
+	return nil
+}
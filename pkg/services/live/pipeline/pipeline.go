@@ -98,6 +98,24 @@ type FrameProcessor interface {
 	ProcessFrame(ctx context.Context, vars Vars, frame *data.Frame) (*data.Frame, error)
 }
 
+// FrameFlushHandler passes a frame flushed by FrameProcessor to the rest of
+// the rule.
+type FrameFlushHandler func(vars Vars, frame *data.Frame)
+
+// FrameProcessorFlusher may be implemented by FrameProcessor which holds
+// frames back and passes them further later, e.g. when frames stop coming.
+// Pipeline sets the handler before each ProcessFrame call.
+type FrameProcessorFlusher interface {
+	SetFlushHandler(handler FrameFlushHandler)
+}
+
+// FrameProcessorCloser may be implemented by FrameProcessor which holds
+// frames back. Close flushes them, it's called when rules using the
+// processor are replaced and when Pipeline is closed.
+type FrameProcessorCloser interface {
+	Close() error
+}
+
 // FrameOutputter outputs data.Frame to a custom destination. Or simply
 // do nothing if some conditions not met.
 type FrameOutputter interface {
@@ -105,6 +123,33 @@ type FrameOutputter interface {
 	OutputFrame(ctx context.Context, vars Vars, frame *data.Frame) ([]*ChannelFrame, error)
 }
 
+// FrameOutputCloser may be implemented by FrameOutputter which buffers frames
+// or keeps connections. Close flushes buffered frames, it's called when rules
+// using the output are replaced and when Pipeline is closed.
+type FrameOutputCloser interface {
+	Close() error
+}
+
+func closeFrameProcessors(processors ...FrameProcessor) {
+	for _, proc := range processors {
+		if closer, ok := proc.(FrameProcessorCloser); ok {
+			if err := closer.Close(); err != nil {
+				logger.Error("Error closing frame processor", "type", proc.Type(), "error", err)
+			}
+		}
+	}
+}
+
+func closeFrameOutputters(outputters ...FrameOutputter) {
+	for _, out := range outputters {
+		if closer, ok := out.(FrameOutputCloser); ok {
+			if err := closer.Close(); err != nil {
+				logger.Error("Error closing frame output", "type", out.Type(), "error", err)
+			}
+		}
+	}
+}
+
 // Subscriber can handle channel subscribe events.
 type Subscriber interface {
 	Type() string
@@ -210,6 +255,13 @@ func New(ruleGetter ChannelRuleGetter) (*Pipeline, error) {
 	return p, nil
 }
 
+// Close closes rule outputs if ChannelRuleGetter supports it.
+func (p *Pipeline) Close() {
+	if closer, ok := p.ruleGetter.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
 func (p *Pipeline) Get(orgID int64, channel string) (*LiveChannelRule, bool, error) {
 	return p.ruleGetter.Get(orgID, channel)
 }
@@ -405,16 +457,25 @@ func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID stri
 		Path:      ch.Path,
 	}
 
-	if len(rule.FrameProcessors) > 0 {
-		for _, proc := range rule.FrameProcessors {
-			frame, err = p.execProcessor(ctx, proc, vars, frame)
-			if err != nil {
-				logger.Error("Error processing frame", "error", err)
-				return nil, err
-			}
-			if frame == nil {
-				return nil, nil
-			}
+	return p.processRuleFrame(ctx, rule, 0, vars, frame)
+}
+
+// processRuleFrame applies rule processors starting from procIndex and
+// outputters to the frame.
+func (p *Pipeline) processRuleFrame(ctx context.Context, rule *LiveChannelRule, procIndex int, vars Vars, frame *data.Frame) ([]*ChannelFrame, error) {
+	for i := procIndex; i < len(rule.FrameProcessors); i++ {
+		proc := rule.FrameProcessors[i]
+		if flusher, ok := proc.(FrameProcessorFlusher); ok {
+			flusher.SetFlushHandler(p.flushHandler(rule, i+1))
+		}
+		var err error
+		frame, err = p.execProcessor(ctx, proc, vars, frame)
+		if err != nil {
+			logger.Error("Error processing frame", "error", err)
+			return nil, err
+		}
+		if frame == nil {
+			return nil, nil
 		}
 	}
 
@@ -434,6 +495,25 @@ func (p *Pipeline) processFrame(ctx context.Context, orgID int64, channelID stri
 	return nil, nil
 }
 
+// flushHandler returns a handler which passes flushed frames to rule
+// processors starting from procIndex and outputters.
+func (p *Pipeline) flushHandler(rule *LiveChannelRule, procIndex int) FrameFlushHandler {
+	return func(vars Vars, frame *data.Frame) {
+		ctx := context.Background()
+		frames, err := p.processRuleFrame(ctx, rule, procIndex, vars, frame)
+		if err != nil {
+			logger.Error("Error processing flushed frame", "error", err, "channel", vars.Channel)
+			return
+		}
+		if len(frames) > 0 {
+			visitedChannels := map[string]struct{}{vars.Channel: {}}
+			if err := p.processChannelFrames(ctx, vars.OrgID, vars.Channel, frames, visitedChannels); err != nil {
+				logger.Error("Error processing flushed frame", "error", err, "channel", vars.Channel)
+			}
+		}
+	}
+}
+
 func (p *Pipeline) execProcessor(ctx context.Context, proc FrameProcessor, vars Vars, frame *data.Frame) (*data.Frame, error) {
 	var span trace.Span
 	if p.tracer != nil {
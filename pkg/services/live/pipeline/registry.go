@@ -48,6 +48,14 @@ var FrameOutputsRegistry = []EntityInfo{
 		Type:        FrameOutputTypeLoki,
 		Description: "output frame as JSON to Loki",
 	},
+	{
+		Type:        FrameOutputTypeInflux,
+		Description: "output frame as line protocol to InfluxDB",
+	},
+	{
+		Type:        FrameOutputTypeKafka,
+		Description: "output frame as JSON or protobuf to Kafka topic",
+	},
 }
 
 var ConvertersRegistry = []EntityInfo{
@@ -79,6 +87,39 @@ var FrameProcessorsRegistry = []EntityInfo{
 		Description: "list the fields that should be removed",
 		Example:     DropFieldsFrameProcessorConfig{},
 	},
+	{
+		Type:        FrameProcessorTypeCalculate,
+		Description: "add a field computed with a math expression over other fields",
+		Example: CalculateFieldFrameProcessorConfig{
+			FieldName:  "fahrenheit",
+			Expression: "$celsius * 9 / 5 + 32",
+		},
+	},
+	{
+		Type:        FrameProcessorTypeRenameFields,
+		Description: "rename fields",
+		Example:     RenameFieldsFrameProcessorConfig{},
+	},
+	{
+		Type:        FrameProcessorTypeCastFields,
+		Description: "convert field values to number, string, boolean or time",
+		Example:     CastFieldsFrameProcessorConfig{},
+	},
+	{
+		Type:        FrameProcessorTypeDownsample,
+		Description: "aggregate frames over a time window (avg, min, max, sum or last)",
+		Example: DownsampleFrameProcessorConfig{
+			IntervalMilliseconds: 1000,
+			Aggregation:          DownsampleAggregationAvg,
+		},
+	},
+	{
+		Type:        FrameProcessorTypeRateLimit,
+		Description: "drop frames coming faster than the configured rate",
+		Example: RateLimitFrameProcessorConfig{
+			MaxFramesPerSecond: 10,
+		},
+	},
 }
 
 var DataOutputsRegistry = []EntityInfo{
@@ -95,3 +136,22 @@ var DataOutputsRegistry = []EntityInfo{
 		Description: "output data to Loki as logs",
 	},
 }
+
+var InputsRegistry = []EntityInfo{
+	{
+		Type:        DataInputTypeMQTT,
+		Description: "subscribe to MQTT topics and publish messages into channels",
+		Example: MQTTInputConfig{
+			URL:    "tcp://localhost:1883",
+			Topics: []MQTTTopicConfig{{Topic: "sensors/#", Channel: "stream/mqtt/{topic}"}},
+		},
+	},
+	{
+		Type:        DataInputTypeNATS,
+		Description: "subscribe to NATS subjects and publish messages into channels",
+		Example: NATSInputConfig{
+			URL:      "nats://localhost:4222",
+			Subjects: []NATSSubjectConfig{{Subject: "sensors.>", Channel: "stream/nats/{topic}"}},
+		},
+	},
+}
@@ -90,6 +90,47 @@ func (f *StorageRuleBuilder) extractFrameProcessor(config *FrameProcessorConfig)
 			return nil, missingConfiguration
 		}
 		return NewKeepFieldsFrameProcessor(*config.KeepFieldsProcessorConfig), nil
+	case FrameProcessorTypeCalculate:
+		if config.CalculateFieldProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		proc, err := NewCalculateFieldFrameProcessor(*config.CalculateFieldProcessorConfig)
+		if err != nil {
+			return nil, err
+		}
+		return proc, nil
+	case FrameProcessorTypeRenameFields:
+		if config.RenameFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		return NewRenameFieldsFrameProcessor(*config.RenameFieldsProcessorConfig), nil
+	case FrameProcessorTypeCastFields:
+		if config.CastFieldsProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		proc, err := NewCastFieldsFrameProcessor(*config.CastFieldsProcessorConfig)
+		if err != nil {
+			return nil, err
+		}
+		return proc, nil
+	case FrameProcessorTypeDownsample:
+		if config.DownsampleProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		proc, err := NewDownsampleFrameProcessor(*config.DownsampleProcessorConfig)
+		if err != nil {
+			return nil, err
+		}
+		return proc, nil
+	case FrameProcessorTypeRateLimit:
+		if config.RateLimitProcessorConfig == nil {
+			return nil, missingConfiguration
+		}
+		proc, err := NewRateLimitFrameProcessor(*config.RateLimitProcessorConfig)
+		if err != nil {
+			return nil, err
+		}
+		return proc, nil
 	case FrameProcessorTypeMultiple:
 		if config.MultipleProcessorConfig == nil {
 			return nil, missingConfiguration
@@ -164,6 +205,20 @@ func (f *StorageRuleBuilder) constructBasicAuth(writeConfig WriteConfig) (*Basic
 	}, nil
 }
 
+// decryptSecureSetting returns decrypted value of write config secure
+// setting, empty string if setting is not set.
+func (f *StorageRuleBuilder) decryptSecureSetting(writeConfig WriteConfig, key string) (string, error) {
+	encrypted := writeConfig.SecureSettings[key]
+	if len(encrypted) == 0 {
+		return "", nil
+	}
+	decrypted, err := f.SecretsService.Decrypt(context.Background(), encrypted)
+	if err != nil {
+		return "", fmt.Errorf("%s can't be decrypted: %w", key, err)
+	}
+	return string(decrypted), nil
+}
+
 func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig, writeConfigs []WriteConfig) (FrameOutputter, error) {
 	if config == nil {
 		return nil, nil
@@ -249,6 +304,50 @@ func (f *StorageRuleBuilder) extractFrameOutputter(config *FrameOutputterConfig,
 			return nil, missingConfiguration
 		}
 		return NewChangeLogFrameOutput(f.FrameStorage, *config.ChangeLogOutputConfig), nil
+	case FrameOutputTypeInflux:
+		if config.InfluxOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		writeConfig, ok := f.getWriteConfig(config.InfluxOutputConfig.UID, writeConfigs)
+		if !ok {
+			return nil, fmt.Errorf("unknown write config uid: %s", config.InfluxOutputConfig.UID)
+		}
+		basicAuth, err := f.constructBasicAuth(writeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error getting password: %w", err)
+		}
+		token, err := f.decryptSecureSetting(writeConfig, "token")
+		if err != nil {
+			return nil, err
+		}
+		return NewInfluxFrameOutput(
+			writeConfig.Settings.Endpoint,
+			basicAuth,
+			token,
+			*config.InfluxOutputConfig,
+		), nil
+	case FrameOutputTypeKafka:
+		if config.KafkaOutputConfig == nil {
+			return nil, missingConfiguration
+		}
+		writeConfig, ok := f.getWriteConfig(config.KafkaOutputConfig.UID, writeConfigs)
+		if !ok {
+			return nil, fmt.Errorf("unknown write config uid: %s", config.KafkaOutputConfig.UID)
+		}
+		basicAuth, err := f.constructBasicAuth(writeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error getting password: %w", err)
+		}
+		out, err := NewKafkaFrameOutput(
+			writeConfig.Settings.Endpoint,
+			basicAuth,
+			writeConfig.Settings.TLS,
+			*config.KafkaOutputConfig,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return out, nil
 	default:
 		return nil, fmt.Errorf("unknown output type: %s", config.Type)
 	}
@@ -299,6 +398,16 @@ func (f *StorageRuleBuilder) getWriteConfig(uid string, writeConfigs []WriteConf
 	return WriteConfig{}, false
 }
 
+// RuleRevision returns org rules revision if Storage supports revisions,
+// zero otherwise.
+func (f *StorageRuleBuilder) RuleRevision(ctx context.Context, orgID int64) (int64, error) {
+	revisionStorage, ok := f.Storage.(RevisionStorage)
+	if !ok {
+		return 0, nil
+	}
+	return revisionStorage.Revision(ctx, orgID)
+}
+
 func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*LiveChannelRule, error) {
 	channelRules, err := f.Storage.ListChannelRules(ctx, orgID)
 	if err != nil {
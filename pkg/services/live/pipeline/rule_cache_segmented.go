@@ -9,56 +9,168 @@ import (
 	"github.com/grafana/grafana/pkg/services/live/pipeline/tree"
 )
 
+// RuleRevisionGetter may be optionally implemented by RuleBuilder to let
+// cache detect rule changes made by other Grafana instances and rebuild only
+// orgs which rules changed.
+type RuleRevisionGetter interface {
+	RuleRevision(ctx context.Context, orgID int64) (int64, error)
+}
+
+const (
+	// DefaultRuleRevisionCheckInterval is used when no revision check
+	// interval is passed to NewCacheSegmentedTree.
+	DefaultRuleRevisionCheckInterval = 2 * time.Second
+	// ruleFullRefreshInterval is used for RuleBuilder which does not
+	// implement RuleRevisionGetter.
+	ruleFullRefreshInterval = 20 * time.Second
+)
+
 // CacheSegmentedTree provides a fast access to channel rule configuration.
 type CacheSegmentedTree struct {
 	radixMu     sync.RWMutex
 	radix       map[int64]*tree.Node
+	rules       map[int64][]*LiveChannelRule
+	revisions   map[int64]int64
 	ruleBuilder RuleBuilder
+
+	revisionCheckInterval time.Duration
 }
 
-func NewCacheSegmentedTree(storage RuleBuilder) *CacheSegmentedTree {
+// NewCacheSegmentedTree creates a cache which checks rule revisions of
+// cached orgs every revisionCheckInterval and rebuilds orgs which revision
+// changed. If storage does not implement RuleRevisionGetter all cached orgs
+// are rebuilt periodically instead. Zero revisionCheckInterval means the
+// default one.
+func NewCacheSegmentedTree(storage RuleBuilder, revisionCheckInterval time.Duration) *CacheSegmentedTree {
+	if revisionCheckInterval <= 0 {
+		revisionCheckInterval = DefaultRuleRevisionCheckInterval
+	}
 	s := &CacheSegmentedTree{
-		radix:       map[int64]*tree.Node{},
-		ruleBuilder: storage,
+		radix:                 map[int64]*tree.Node{},
+		rules:                 map[int64][]*LiveChannelRule{},
+		revisions:             map[int64]int64{},
+		ruleBuilder:           storage,
+		revisionCheckInterval: revisionCheckInterval,
 	}
 	go s.updatePeriodically()
 	return s
 }
 
+func (s *CacheSegmentedTree) orgIDs() []int64 {
+	s.radixMu.RLock()
+	defer s.radixMu.RUnlock()
+	orgIDs := make([]int64, 0, len(s.radix))
+	for orgID := range s.radix {
+		orgIDs = append(orgIDs, orgID)
+	}
+	return orgIDs
+}
+
 func (s *CacheSegmentedTree) updatePeriodically() {
-	for {
-		var orgIDs []int64
-		s.radixMu.Lock()
-		for orgID := range s.radix {
-			orgIDs = append(orgIDs, orgID)
+	if _, ok := s.ruleBuilder.(RuleRevisionGetter); ok {
+		// Rebuilding replaces rules and closes their outputs, so orgs are
+		// only rebuilt when their revision changes.
+		for {
+			time.Sleep(s.revisionCheckInterval)
+			s.refreshChanged()
 		}
-		s.radixMu.Unlock()
-		for _, orgID := range orgIDs {
+	}
+	// Changes can not be detected without revisions, so all cached orgs are
+	// rebuilt periodically.
+	for {
+		time.Sleep(ruleFullRefreshInterval)
+		for _, orgID := range s.orgIDs() {
 			err := s.fillOrg(orgID)
 			if err != nil {
 				logger.Error("Error filling orgId", "error", err, "orgId", orgID)
 			}
 		}
-		time.Sleep(20 * time.Second)
+	}
+}
+
+// refreshChanged rebuilds rules of orgs which revision changed since the
+// last fill.
+func (s *CacheSegmentedTree) refreshChanged() {
+	revisionGetter, ok := s.ruleBuilder.(RuleRevisionGetter)
+	if !ok {
+		return
+	}
+	for _, orgID := range s.orgIDs() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		revision, err := revisionGetter.RuleRevision(ctx, orgID)
+		cancel()
+		if err != nil {
+			logger.Error("Error getting rule revision", "error", err, "orgId", orgID)
+			continue
+		}
+		s.radixMu.RLock()
+		current := s.revisions[orgID]
+		s.radixMu.RUnlock()
+		if revision == current {
+			continue
+		}
+		logger.Debug("Channel rules changed, refreshing", "orgId", orgID, "revision", revision)
+		if err := s.fillOrg(orgID); err != nil {
+			logger.Error("Error filling orgId", "error", err, "orgId", orgID)
+		}
 	}
 }
 
 func (s *CacheSegmentedTree) fillOrg(orgID int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	var revision int64
+	if revisionGetter, ok := s.ruleBuilder.(RuleRevisionGetter); ok {
+		// Revision is loaded before rules, so concurrent change results into
+		// one more refresh rather than into a missed one.
+		var err error
+		revision, err = revisionGetter.RuleRevision(ctx, orgID)
+		if err != nil {
+			return err
+		}
+	}
 	channels, err := s.ruleBuilder.BuildRules(ctx, orgID)
 	if err != nil {
 		return err
 	}
 	s.radixMu.Lock()
-	defer s.radixMu.Unlock()
+	previous := s.rules[orgID]
 	s.radix[orgID] = tree.New()
 	for _, ch := range channels {
 		s.radix[orgID].AddRoute("/"+ch.Pattern, ch)
 	}
+	s.rules[orgID] = channels
+	s.revisions[orgID] = revision
+	s.radixMu.Unlock()
+
+	// Frames being processed by replaced rules at the moment are still
+	// flushed, as outputs keep accepting frames after close.
+	closeRules(previous)
 	return nil
 }
 
+// Close closes outputs of all cached rules.
+func (s *CacheSegmentedTree) Close() {
+	s.radixMu.Lock()
+	rules := s.rules
+	s.radix = map[int64]*tree.Node{}
+	s.rules = map[int64][]*LiveChannelRule{}
+	s.revisions = map[int64]int64{}
+	s.radixMu.Unlock()
+
+	for _, orgRules := range rules {
+		closeRules(orgRules)
+	}
+}
+
+func closeRules(rules []*LiveChannelRule) {
+	for _, rule := range rules {
+		// Processors are closed first, so frames they flush reach outputs.
+		closeFrameProcessors(rule.FrameProcessors...)
+		closeFrameOutputters(rule.FrameOutputters...)
+	}
+}
+
 func (s *CacheSegmentedTree) Get(orgID int64, channel string) (*LiveChannelRule, bool, error) {
 	s.radixMu.RLock()
 	_, ok := s.radix[orgID]
@@ -4,7 +4,10 @@ import (
 	"context"
 	"testing"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline/tree"
 )
 
 type testBuilder struct{}
@@ -31,7 +34,7 @@ func (t *testBuilder) BuildRules(_ context.Context, _ int64) ([]*LiveChannelRule
 }
 
 func TestStorage_Get(t *testing.T) {
-	s := NewCacheSegmentedTree(&testBuilder{})
+	s := NewCacheSegmentedTree(&testBuilder{}, 0)
 	rule, ok, err := s.Get(1, "stream/telegraf/cpu")
 	require.NoError(t, err)
 	require.True(t, ok)
@@ -53,8 +56,74 @@ func TestStorage_Get(t *testing.T) {
 	require.Equal(t, "stream/boom:er", rule.Pattern)
 }
 
+type revisionTestBuilder struct {
+	revision int64
+	patterns []string
+	output   *closeCountingOutput
+}
+
+type closeCountingOutput struct {
+	closed int
+}
+
+func (o *closeCountingOutput) Type() string { return "test" }
+
+func (o *closeCountingOutput) OutputFrame(_ context.Context, _ Vars, _ *data.Frame) ([]*ChannelFrame, error) {
+	return nil, nil
+}
+
+func (o *closeCountingOutput) Close() error {
+	o.closed++
+	return nil
+}
+
+func (b *revisionTestBuilder) RuleRevision(_ context.Context, _ int64) (int64, error) {
+	return b.revision, nil
+}
+
+func (b *revisionTestBuilder) BuildRules(_ context.Context, orgID int64) ([]*LiveChannelRule, error) {
+	rules := make([]*LiveChannelRule, 0, len(b.patterns))
+	for _, p := range b.patterns {
+		rules = append(rules, &LiveChannelRule{OrgId: orgID, Pattern: p, FrameOutputters: []FrameOutputter{b.output}})
+	}
+	return rules, nil
+}
+
+func TestStorage_RefreshChanged(t *testing.T) {
+	output := &closeCountingOutput{}
+	builder := &revisionTestBuilder{revision: 1, patterns: []string{"stream/a/:path"}, output: output}
+	s := &CacheSegmentedTree{
+		radix:       map[int64]*tree.Node{},
+		rules:       map[int64][]*LiveChannelRule{},
+		revisions:   map[int64]int64{},
+		ruleBuilder: builder,
+	}
+	_, ok, err := s.Get(1, "stream/a/x")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Rules changed without revision change are not picked up.
+	builder.patterns = []string{"stream/b/:path"}
+	s.refreshChanged()
+	_, ok, err = s.Get(1, "stream/b/x")
+	require.NoError(t, err)
+	require.False(t, ok)
+	// Unchanged rules keep their outputs open.
+	require.Equal(t, 0, output.closed)
+
+	builder.revision = 2
+	s.refreshChanged()
+	_, ok, err = s.Get(1, "stream/b/x")
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, ok, err = s.Get(1, "stream/a/x")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 1, output.closed)
+}
+
 func BenchmarkRuleGet(b *testing.B) {
-	s := NewCacheSegmentedTree(&testBuilder{})
+	s := NewCacheSegmentedTree(&testBuilder{}, 0)
 	for i := 0; i < b.N; i++ {
 		_, ok, err := s.Get(1, "stream/telegraf/cpu")
 		if err != nil || !ok {
@@ -1,6 +1,15 @@
 package pipeline
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrChannelRuleNotFound = errors.New("channel rule not found")
+	ErrWriteConfigNotFound = errors.New("write config not found")
+	ErrVersionMismatch     = errors.New("version mismatch, configuration was changed by someone else")
+)
 
 // Storage describes all methods to manage Live pipeline persistent data.
 type Storage interface {
@@ -14,3 +23,14 @@ type Storage interface {
 	UpdateChannelRule(_ context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error)
 	DeleteChannelRule(_ context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error
 }
+
+// InputStorage describes methods to load pipeline data input configurations.
+type InputStorage interface {
+	ListInputConfigs(_ context.Context, orgID int64) ([]InputConfig, error)
+}
+
+// RevisionStorage may be optionally implemented by Storage shared between
+// Grafana instances. Revision must change on every org configuration change.
+type RevisionStorage interface {
+	Revision(_ context.Context, orgID int64) (int64, error)
+}
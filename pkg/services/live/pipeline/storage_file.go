@@ -114,7 +114,11 @@ func (f *FileStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd Wr
 	if index > -1 {
 		writeConfigs.Configs[index] = backend
 	} else {
-		return f.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd(cmd))
+		return f.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd{
+			UID:            cmd.UID,
+			Settings:       cmd.Settings,
+			SecureSettings: cmd.SecureSettings,
+		})
 	}
 
 	err = f.saveWriteConfigs(orgID, writeConfigs)
@@ -220,7 +224,10 @@ func (f *FileStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd Ch
 	if index > -1 {
 		channelRules.Rules[index] = rule
 	} else {
-		return f.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd(cmd))
+		return f.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd{
+			Pattern:  cmd.Pattern,
+			Settings: cmd.Settings,
+		})
 	}
 
 	err = f.saveChannelRules(orgID, channelRules)
@@ -337,3 +344,41 @@ func (f *FileStorage) saveWriteConfigs(_ int64, writeConfigs WriteConfigs) error
 	}
 	return nil
 }
+
+func (f *FileStorage) ListInputConfigs(_ context.Context, orgID int64) ([]InputConfig, error) {
+	inputConfigs, err := f.readInputConfigs()
+	if err != nil {
+		return nil, fmt.Errorf("can't read input configs: %w", err)
+	}
+	var orgConfigs []InputConfig
+	for _, c := range inputConfigs.Configs {
+		if c.OrgId == orgID || (orgID == 1 && c.OrgId == 0) {
+			orgConfigs = append(orgConfigs, c)
+		}
+	}
+	return orgConfigs, nil
+}
+
+func (f *FileStorage) inputConfigsFilePath() string {
+	return filepath.Join(f.DataPath, "pipeline", "input-configs.json")
+}
+
+func (f *FileStorage) readInputConfigs() (InputConfigs, error) {
+	filePath := f.inputConfigsFilePath()
+	// Safe to ignore gosec warning G304.
+	// nolint:gosec
+	bytes, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Inputs are optional.
+			return InputConfigs{}, nil
+		}
+		return InputConfigs{}, fmt.Errorf("can't read %s file: %w", filePath, err)
+	}
+	var inputConfigs InputConfigs
+	err = json.Unmarshal(bytes, &inputConfigs)
+	if err != nil {
+		return InputConfigs{}, fmt.Errorf("can't unmarshal %s data: %w", filePath, err)
+	}
+	return inputConfigs, nil
+}
@@ -0,0 +1,735 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// SQLStorage keeps channel rules, write and input configs in the database.
+// Every change increments org pipeline revision which allows Grafana
+// instances to detect changes made on other instances.
+type SQLStorage struct {
+	store          db.DB
+	secretsService secrets.Service
+}
+
+func NewSQLStorage(store db.DB, secretsService secrets.Service) *SQLStorage {
+	return &SQLStorage{store: store, secretsService: secretsService}
+}
+
+type liveChannelRule struct {
+	Id       int64
+	OrgId    int64
+	Pattern  string
+	Settings string
+	Version  int64
+	Created  time.Time
+	Updated  time.Time
+}
+
+func (r *liveChannelRule) TableName() string {
+	return "live_channel_rule"
+}
+
+type liveWriteConfig struct {
+	Id             int64
+	OrgId          int64
+	Uid            string
+	Settings       string
+	SecureSettings string
+	Version        int64
+	Created        time.Time
+	Updated        time.Time
+}
+
+func (r *liveWriteConfig) TableName() string {
+	return "live_write_config"
+}
+
+type liveInputConfig struct {
+	Id             int64
+	OrgId          int64
+	Uid            string
+	Type           string
+	Settings       string
+	SecureSettings string
+	Version        int64
+	Created        time.Time
+	Updated        time.Time
+}
+
+func (r *liveInputConfig) TableName() string {
+	return "live_input_config"
+}
+
+type livePipelineRevision struct {
+	OrgId    int64 `xorm:"pk"`
+	Revision int64
+	Updated  time.Time
+}
+
+func (r *livePipelineRevision) TableName() string {
+	return "live_pipeline_revision"
+}
+
+// inputConfigSettings is a JSON representation of type specific input config.
+type inputConfigSettings struct {
+	MQTTInputConfig *MQTTInputConfig `json:"mqtt,omitempty"`
+	NATSInputConfig *NATSInputConfig `json:"nats,omitempty"`
+}
+
+func marshalSecureSettings(secureSettings map[string][]byte) (string, error) {
+	if len(secureSettings) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(secureSettings)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func unmarshalSecureSettings(s string) (map[string][]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var secureSettings map[string][]byte
+	if err := json.Unmarshal([]byte(s), &secureSettings); err != nil {
+		return nil, err
+	}
+	return secureSettings, nil
+}
+
+func (r *liveChannelRule) toChannelRule() (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:   r.OrgId,
+		Pattern: r.Pattern,
+		Version: r.Version,
+	}
+	if err := json.Unmarshal([]byte(r.Settings), &rule.Settings); err != nil {
+		return ChannelRule{}, fmt.Errorf("can't unmarshal channel rule %s settings: %w", r.Pattern, err)
+	}
+	return rule, nil
+}
+
+func (r *liveWriteConfig) toWriteConfig() (WriteConfig, error) {
+	writeConfig := WriteConfig{
+		OrgId:   r.OrgId,
+		UID:     r.Uid,
+		Version: r.Version,
+	}
+	if err := json.Unmarshal([]byte(r.Settings), &writeConfig.Settings); err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal write config %s settings: %w", r.Uid, err)
+	}
+	secureSettings, err := unmarshalSecureSettings(r.SecureSettings)
+	if err != nil {
+		return WriteConfig{}, fmt.Errorf("can't unmarshal write config %s secure settings: %w", r.Uid, err)
+	}
+	writeConfig.SecureSettings = secureSettings
+	return writeConfig, nil
+}
+
+func (r *liveInputConfig) toInputConfig() (InputConfig, error) {
+	var settings inputConfigSettings
+	if err := json.Unmarshal([]byte(r.Settings), &settings); err != nil {
+		return InputConfig{}, fmt.Errorf("can't unmarshal input config %s settings: %w", r.Uid, err)
+	}
+	secureSettings, err := unmarshalSecureSettings(r.SecureSettings)
+	if err != nil {
+		return InputConfig{}, fmt.Errorf("can't unmarshal input config %s secure settings: %w", r.Uid, err)
+	}
+	return InputConfig{
+		OrgId:           r.OrgId,
+		UID:             r.Uid,
+		Type:            r.Type,
+		MQTTInputConfig: settings.MQTTInputConfig,
+		NATSInputConfig: settings.NATSInputConfig,
+		SecureSettings:  secureSettings,
+	}, nil
+}
+
+// incrementRevision must be called inside transaction modifying org pipeline configuration.
+func incrementRevision(sess *db.Session, orgID int64, now time.Time) error {
+	res, err := sess.Exec("UPDATE live_pipeline_revision SET revision = revision + 1, updated = ? WHERE org_id = ?", now, orgID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+	_, err = sess.Insert(&livePipelineRevision{OrgId: orgID, Revision: 1, Updated: now})
+	return err
+}
+
+// Revision returns current revision of org pipeline configuration.
+func (s *SQLStorage) Revision(ctx context.Context, orgID int64) (int64, error) {
+	var revision livePipelineRevision
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ?", orgID).Get(&revision)
+		return err
+	})
+	return revision.Revision, err
+}
+
+func (s *SQLStorage) ListChannelRules(ctx context.Context, orgID int64) ([]ChannelRule, error) {
+	var rows []*liveChannelRule
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("pattern").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]ChannelRule, 0, len(rows))
+	for _, row := range rows {
+		rule, err := row.toChannelRule()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (s *SQLStorage) CreateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleCreateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+		Version:  1,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return ChannelRule{}, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	settings, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND pattern = ?", orgID, rule.Pattern).Exist(&liveChannelRule{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("pattern already exists in org: %s", rule.Pattern)
+		}
+		if err := s.checkRulesValid(sess, orgID, rule); err != nil {
+			return err
+		}
+		now := time.Now()
+		_, err = sess.Insert(&liveChannelRule{
+			OrgId:    orgID,
+			Pattern:  rule.Pattern,
+			Settings: string(settings),
+			Version:  rule.Version,
+			Created:  now,
+			Updated:  now,
+		})
+		if err != nil {
+			return err
+		}
+		return incrementRevision(sess, orgID, now)
+	})
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	return rule, nil
+}
+
+// checkRulesValid checks that a new or updated rule pattern does not conflict
+// with patterns of other org rules.
+func (s *SQLStorage) checkRulesValid(sess *db.Session, orgID int64, rule ChannelRule) error {
+	var rows []*liveChannelRule
+	if err := sess.Where("org_id = ? AND pattern != ?", orgID, rule.Pattern).Cols("org_id", "pattern").Find(&rows); err != nil {
+		return err
+	}
+	rules := make([]ChannelRule, 0, len(rows)+1)
+	for _, row := range rows {
+		rules = append(rules, ChannelRule{OrgId: row.OrgId, Pattern: row.Pattern})
+	}
+	rules = append(rules, rule)
+	ok, reason := checkRulesValid(orgID, rules)
+	if !ok {
+		return errors.New(reason)
+	}
+	return nil
+}
+
+func (s *SQLStorage) UpdateChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleUpdateCmd) (ChannelRule, error) {
+	rule := ChannelRule{
+		OrgId:    orgID,
+		Pattern:  cmd.Pattern,
+		Settings: cmd.Settings,
+	}
+	ok, reason := rule.Valid()
+	if !ok {
+		return ChannelRule{}, fmt.Errorf("invalid channel rule: %s", reason)
+	}
+	settings, err := json.Marshal(rule.Settings)
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	var created bool
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing liveChannelRule
+		exists, err := sess.Where("org_id = ? AND pattern = ?", orgID, rule.Pattern).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			created = true
+			return nil
+		}
+		if cmd.Version > 0 && cmd.Version != existing.Version {
+			return ErrVersionMismatch
+		}
+		now := time.Now()
+		rule.Version = existing.Version + 1
+		_, err = sess.Exec(
+			"UPDATE live_channel_rule SET settings = ?, version = ?, updated = ? WHERE id = ?",
+			string(settings), rule.Version, now, existing.Id,
+		)
+		if err != nil {
+			return err
+		}
+		return incrementRevision(sess, orgID, now)
+	})
+	if err != nil {
+		return ChannelRule{}, err
+	}
+	if created {
+		return s.CreateChannelRule(ctx, orgID, ChannelRuleCreateCmd{Pattern: cmd.Pattern, Settings: cmd.Settings})
+	}
+	return rule, nil
+}
+
+func (s *SQLStorage) DeleteChannelRule(ctx context.Context, orgID int64, cmd ChannelRuleDeleteCmd) error {
+	return s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM live_channel_rule WHERE org_id = ? AND pattern = ?", orgID, cmd.Pattern)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrChannelRuleNotFound
+		}
+		return incrementRevision(sess, orgID, time.Now())
+	})
+}
+
+func (s *SQLStorage) ListWriteConfigs(ctx context.Context, orgID int64) ([]WriteConfig, error) {
+	var rows []*liveWriteConfig
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	writeConfigs := make([]WriteConfig, 0, len(rows))
+	for _, row := range rows {
+		writeConfig, err := row.toWriteConfig()
+		if err != nil {
+			return nil, err
+		}
+		writeConfigs = append(writeConfigs, writeConfig)
+	}
+	return writeConfigs, nil
+}
+
+func (s *SQLStorage) GetWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigGetCmd) (WriteConfig, bool, error) {
+	var row liveWriteConfig
+	var exists bool
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		exists, err = sess.Where("org_id = ? AND uid = ?", orgID, cmd.UID).Get(&row)
+		return err
+	})
+	if err != nil || !exists {
+		return WriteConfig{}, false, err
+	}
+	writeConfig, err := row.toWriteConfig()
+	if err != nil {
+		return WriteConfig{}, false, err
+	}
+	return writeConfig, true, nil
+}
+
+func (s *SQLStorage) CreateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigCreateCmd) (WriteConfig, error) {
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	secureSettings, err := s.secretsService.EncryptJsonData(ctx, cmd.SecureSettings, secrets.WithoutScope())
+	if err != nil {
+		return WriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	writeConfig := WriteConfig{
+		OrgId:          orgID,
+		UID:            cmd.UID,
+		Settings:       cmd.Settings,
+		SecureSettings: secureSettings,
+		Version:        1,
+	}
+	ok, reason := writeConfig.Valid()
+	if !ok {
+		return WriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+	}
+	settings, err := json.Marshal(writeConfig.Settings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	secureSettingsJSON, err := marshalSecureSettings(secureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, writeConfig.UID).Exist(&liveWriteConfig{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("backend already exists in org: %s", writeConfig.UID)
+		}
+		now := time.Now()
+		_, err = sess.Insert(&liveWriteConfig{
+			OrgId:          orgID,
+			Uid:            writeConfig.UID,
+			Settings:       string(settings),
+			SecureSettings: secureSettingsJSON,
+			Version:        writeConfig.Version,
+			Created:        now,
+			Updated:        now,
+		})
+		if err != nil {
+			return err
+		}
+		return incrementRevision(sess, orgID, now)
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) UpdateWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigUpdateCmd) (WriteConfig, error) {
+	secureSettings, err := s.secretsService.EncryptJsonData(ctx, cmd.SecureSettings, secrets.WithoutScope())
+	if err != nil {
+		return WriteConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	writeConfig := WriteConfig{
+		OrgId:          orgID,
+		UID:            cmd.UID,
+		Settings:       cmd.Settings,
+		SecureSettings: secureSettings,
+	}
+	ok, reason := writeConfig.Valid()
+	if !ok {
+		return WriteConfig{}, fmt.Errorf("invalid write config: %s", reason)
+	}
+	settings, err := json.Marshal(writeConfig.Settings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	secureSettingsJSON, err := marshalSecureSettings(secureSettings)
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	var created bool
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing liveWriteConfig
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, writeConfig.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			created = true
+			return nil
+		}
+		if cmd.Version > 0 && cmd.Version != existing.Version {
+			return ErrVersionMismatch
+		}
+		now := time.Now()
+		writeConfig.Version = existing.Version + 1
+		_, err = sess.Exec(
+			"UPDATE live_write_config SET settings = ?, secure_settings = ?, version = ?, updated = ? WHERE id = ?",
+			string(settings), secureSettingsJSON, writeConfig.Version, now, existing.Id,
+		)
+		if err != nil {
+			return err
+		}
+		return incrementRevision(sess, orgID, now)
+	})
+	if err != nil {
+		return WriteConfig{}, err
+	}
+	if created {
+		return s.CreateWriteConfig(ctx, orgID, WriteConfigCreateCmd{
+			UID:            cmd.UID,
+			Settings:       cmd.Settings,
+			SecureSettings: cmd.SecureSettings,
+		})
+	}
+	return writeConfig, nil
+}
+
+func (s *SQLStorage) DeleteWriteConfig(ctx context.Context, orgID int64, cmd WriteConfigDeleteCmd) error {
+	return s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM live_write_config WHERE org_id = ? AND uid = ?", orgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrWriteConfigNotFound
+		}
+		return incrementRevision(sess, orgID, time.Now())
+	})
+}
+
+func (s *SQLStorage) ListInputConfigs(ctx context.Context, orgID int64) ([]InputConfig, error) {
+	var rows []*liveInputConfig
+	err := s.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+	inputConfigs := make([]InputConfig, 0, len(rows))
+	for _, row := range rows {
+		inputConfig, err := row.toInputConfig()
+		if err != nil {
+			return nil, err
+		}
+		inputConfigs = append(inputConfigs, inputConfig)
+	}
+	return inputConfigs, nil
+}
+
+// SaveInputConfig creates or replaces input config, secureSettings are
+// encrypted before saving.
+func (s *SQLStorage) SaveInputConfig(ctx context.Context, orgID int64, config InputConfig, secureSettings map[string]string) (InputConfig, error) {
+	if config.UID == "" {
+		config.UID = util.GenerateShortUID()
+	}
+	config.OrgId = orgID
+	ok, reason := config.Valid()
+	if !ok {
+		return InputConfig{}, fmt.Errorf("invalid input config: %s", reason)
+	}
+	encrypted, err := s.secretsService.EncryptJsonData(ctx, secureSettings, secrets.WithoutScope())
+	if err != nil {
+		return InputConfig{}, fmt.Errorf("error encrypting data: %w", err)
+	}
+	config.SecureSettings = encrypted
+	settings, err := json.Marshal(inputConfigSettings{
+		MQTTInputConfig: config.MQTTInputConfig,
+		NATSInputConfig: config.NATSInputConfig,
+	})
+	if err != nil {
+		return InputConfig{}, err
+	}
+	secureSettingsJSON, err := marshalSecureSettings(encrypted)
+	if err != nil {
+		return InputConfig{}, err
+	}
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		now := time.Now()
+		var existing liveInputConfig
+		exists, err := sess.Where("org_id = ? AND uid = ?", orgID, config.UID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if exists {
+			_, err = sess.Exec(
+				"UPDATE live_input_config SET type = ?, settings = ?, secure_settings = ?, version = ?, updated = ? WHERE id = ?",
+				config.Type, string(settings), secureSettingsJSON, existing.Version+1, now, existing.Id,
+			)
+		} else {
+			_, err = sess.Insert(&liveInputConfig{
+				OrgId:          orgID,
+				Uid:            config.UID,
+				Type:           config.Type,
+				Settings:       string(settings),
+				SecureSettings: secureSettingsJSON,
+				Version:        1,
+				Created:        now,
+				Updated:        now,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		return incrementRevision(sess, orgID, now)
+	})
+	if err != nil {
+		return InputConfig{}, err
+	}
+	return config, nil
+}
+
+// ImportFileStorage copies channel rules, write and input configs kept by
+// FileStorage into the database. Entries which already exist in the database
+// are left untouched. Imported files are renamed with an .imported suffix, so
+// the import runs once.
+func (s *SQLStorage) ImportFileStorage(ctx context.Context, f *FileStorage) error {
+	channelRules, err := f.readRules()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	writeConfigs, err := f.readWriteConfigs()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	inputConfigs, err := f.readInputConfigs()
+	if err != nil {
+		return err
+	}
+
+	var imported int
+	err = s.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		now := time.Now()
+		changedOrgs := map[int64]struct{}{}
+
+		for _, rule := range channelRules.Rules {
+			orgID := fileStorageOrgID(rule.OrgId)
+			exists, err := sess.Where("org_id = ? AND pattern = ?", orgID, rule.Pattern).Exist(&liveChannelRule{})
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			settings, err := json.Marshal(rule.Settings)
+			if err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&liveChannelRule{
+				OrgId:    orgID,
+				Pattern:  rule.Pattern,
+				Settings: string(settings),
+				Version:  1,
+				Created:  now,
+				Updated:  now,
+			}); err != nil {
+				return fmt.Errorf("can't import channel rule %s: %w", rule.Pattern, err)
+			}
+			changedOrgs[orgID] = struct{}{}
+			imported++
+		}
+
+		for _, writeConfig := range writeConfigs.Configs {
+			orgID := fileStorageOrgID(writeConfig.OrgId)
+			exists, err := sess.Where("org_id = ? AND uid = ?", orgID, writeConfig.UID).Exist(&liveWriteConfig{})
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			settings, err := json.Marshal(writeConfig.Settings)
+			if err != nil {
+				return err
+			}
+			// Secure settings are encrypted the same way by both storages.
+			secureSettings, err := marshalSecureSettings(writeConfig.SecureSettings)
+			if err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&liveWriteConfig{
+				OrgId:          orgID,
+				Uid:            writeConfig.UID,
+				Settings:       string(settings),
+				SecureSettings: secureSettings,
+				Version:        1,
+				Created:        now,
+				Updated:        now,
+			}); err != nil {
+				return fmt.Errorf("can't import write config %s: %w", writeConfig.UID, err)
+			}
+			changedOrgs[orgID] = struct{}{}
+			imported++
+		}
+
+		for _, inputConfig := range inputConfigs.Configs {
+			orgID := fileStorageOrgID(inputConfig.OrgId)
+			exists, err := sess.Where("org_id = ? AND uid = ?", orgID, inputConfig.UID).Exist(&liveInputConfig{})
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			settings, err := json.Marshal(inputConfigSettings{
+				MQTTInputConfig: inputConfig.MQTTInputConfig,
+				NATSInputConfig: inputConfig.NATSInputConfig,
+			})
+			if err != nil {
+				return err
+			}
+			secureSettings, err := marshalSecureSettings(inputConfig.SecureSettings)
+			if err != nil {
+				return err
+			}
+			if _, err := sess.Insert(&liveInputConfig{
+				OrgId:          orgID,
+				Uid:            inputConfig.UID,
+				Type:           inputConfig.Type,
+				Settings:       string(settings),
+				SecureSettings: secureSettings,
+				Version:        1,
+				Created:        now,
+				Updated:        now,
+			}); err != nil {
+				return fmt.Errorf("can't import input config %s: %w", inputConfig.UID, err)
+			}
+			changedOrgs[orgID] = struct{}{}
+			imported++
+		}
+
+		for orgID := range changedOrgs {
+			if err := incrementRevision(sess, orgID, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, filePath := range []string{f.ruleFilePath(), f.writeConfigsFilePath(), f.inputConfigsFilePath()} {
+		if err := os.Rename(filePath, filePath+".imported"); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("can't rename imported file %s: %w", filePath, err)
+		}
+	}
+	if imported > 0 {
+		logger.Info("Imported pipeline configuration from files", "dataPath", f.DataPath, "count", imported)
+	}
+	return nil
+}
+
+// fileStorageOrgID returns org of FileStorage entry. FileStorage does not
+// persist org, so its entries belong to the main org.
+func fileStorageOrgID(orgID int64) int64 {
+	if orgID == 0 {
+		return 1
+	}
+	return orgID
+}
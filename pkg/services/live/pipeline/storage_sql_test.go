@@ -0,0 +1,227 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSQLStorage_ChannelRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := NewSQLStorage(db.InitTestDB(t), fakes.NewFakeSecretsService())
+
+	revision, err := storage.Revision(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), revision)
+
+	rule, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{
+		Pattern: "stream/test/:path",
+		Settings: ChannelRuleSettings{
+			Converter: &ConverterConfig{Type: ConverterTypeJsonAuto},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rule.Version)
+
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/:path"})
+	require.Error(t, err, "duplicate pattern must be rejected")
+	_, err = storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{Pattern: "stream/test/:other"})
+	require.Error(t, err, "conflicting pattern must be rejected")
+
+	// Rules are isolated per org.
+	_, err = storage.CreateChannelRule(ctx, 2, ChannelRuleCreateCmd{Pattern: "stream/test/:path"})
+	require.NoError(t, err)
+
+	rules, err := storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, int64(1), rules[0].OrgId)
+	require.Equal(t, ConverterTypeJsonAuto, rules[0].Settings.Converter.Type)
+
+	rule, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{
+		Pattern: "stream/test/:path",
+		Version: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), rule.Version)
+
+	_, err = storage.UpdateChannelRule(ctx, 1, ChannelRuleUpdateCmd{
+		Pattern: "stream/test/:path",
+		Version: 1,
+	})
+	require.ErrorIs(t, err, ErrVersionMismatch)
+
+	revision, err = storage.Revision(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), revision)
+
+	require.NoError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/test/:path"}))
+	require.ErrorIs(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/test/:path"}), ErrChannelRuleNotFound)
+
+	rules, err = storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 0)
+	rules, err = storage.ListChannelRules(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	revision, err = storage.Revision(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), revision)
+}
+
+func TestIntegrationSQLStorage_WriteConfigs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	storage := NewSQLStorage(sqlStore, secretsService)
+
+	created, err := storage.CreateWriteConfig(ctx, 1, WriteConfigCreateCmd{
+		UID:            "remote",
+		Settings:       WriteSettings{Endpoint: "http://localhost:9090/api/v1/write"},
+		SecureSettings: map[string]string{"basicAuthPassword": "secret"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), created.Version)
+
+	writeConfig, ok, err := storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: "remote"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "http://localhost:9090/api/v1/write", writeConfig.Settings.Endpoint)
+	require.NotEqual(t, []byte("secret"), writeConfig.SecureSettings["basicAuthPassword"])
+	decrypted, err := secretsService.DecryptJsonData(ctx, writeConfig.SecureSettings)
+	require.NoError(t, err)
+	require.Equal(t, "secret", decrypted["basicAuthPassword"])
+
+	_, ok, err = storage.GetWriteConfig(ctx, 2, WriteConfigGetCmd{UID: "remote"})
+	require.NoError(t, err)
+	require.False(t, ok, "write config must not be visible in another org")
+
+	updated, err := storage.UpdateWriteConfig(ctx, 1, WriteConfigUpdateCmd{
+		UID:      "remote",
+		Settings: WriteSettings{Endpoint: "http://localhost:9091/api/v1/write"},
+		Version:  1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), updated.Version)
+
+	writeConfigs, err := storage.ListWriteConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, writeConfigs, 1)
+	require.Equal(t, "http://localhost:9091/api/v1/write", writeConfigs[0].Settings.Endpoint)
+	require.Empty(t, writeConfigs[0].SecureSettings)
+
+	require.NoError(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: "remote"}))
+	require.ErrorIs(t, storage.DeleteWriteConfig(ctx, 1, WriteConfigDeleteCmd{UID: "remote"}), ErrWriteConfigNotFound)
+}
+
+func TestIntegrationSQLStorage_InputConfigs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	secretsService := fakes.NewFakeSecretsService()
+	storage := NewSQLStorage(db.InitTestDB(t), secretsService)
+
+	saved, err := storage.SaveInputConfig(ctx, 1, InputConfig{
+		Type: DataInputTypeMQTT,
+		MQTTInputConfig: &MQTTInputConfig{
+			URL:    "tcp://localhost:1883",
+			Topics: []MQTTTopicConfig{{Topic: "sensors/#", Channel: "stream/sensors/{topic}"}},
+		},
+	}, map[string]string{"password": "secret"})
+	require.NoError(t, err)
+	require.NotEmpty(t, saved.UID)
+
+	inputConfigs, err := storage.ListInputConfigs(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, inputConfigs, 1)
+	require.Equal(t, "tcp://localhost:1883", inputConfigs[0].MQTTInputConfig.URL)
+	password, err := secretsService.Decrypt(ctx, inputConfigs[0].SecureSettings["password"])
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(password))
+
+	inputConfigs, err = storage.ListInputConfigs(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, inputConfigs, 0)
+}
+
+func TestIntegrationSQLStorage_ImportFileStorage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	storage := NewSQLStorage(db.InitTestDB(t), fakes.NewFakeSecretsService())
+
+	dataPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataPath, "pipeline"), 0750))
+	writeFile := func(name string, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dataPath, "pipeline", name), []byte(content), 0600))
+	}
+	writeFile("live-channel-rules.json", `{"rules": [
+		{"pattern": "stream/test/:path", "settings": {"converter": {"type": "jsonAuto"}}},
+		{"pattern": "stream/other/:path"}
+	]}`)
+	writeFile("write-configs.json", `{"writeConfigs": [
+		{"uid": "remote", "settings": {"endpoint": "http://localhost:9090/api/v1/write"}, "secureSettings": {"basicAuthPassword": "ZW5jcnlwdGVk"}}
+	]}`)
+
+	// Entries existing in the database are kept.
+	_, err := storage.CreateChannelRule(ctx, 1, ChannelRuleCreateCmd{
+		Pattern:  "stream/other/:path",
+		Settings: ChannelRuleSettings{Converter: &ConverterConfig{Type: ConverterTypeJsonFrame}},
+	})
+	require.NoError(t, err)
+
+	fileStorage := &FileStorage{DataPath: dataPath}
+	require.NoError(t, storage.ImportFileStorage(ctx, fileStorage))
+
+	// File storage entries belong to the main org.
+	rules, err := storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "stream/other/:path", rules[0].Pattern)
+	require.Equal(t, ConverterTypeJsonFrame, rules[0].Settings.Converter.Type)
+	require.Equal(t, "stream/test/:path", rules[1].Pattern)
+	require.Equal(t, ConverterTypeJsonAuto, rules[1].Settings.Converter.Type)
+
+	writeConfig, ok, err := storage.GetWriteConfig(ctx, 1, WriteConfigGetCmd{UID: "remote"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "http://localhost:9090/api/v1/write", writeConfig.Settings.Endpoint)
+	require.Equal(t, []byte("encrypted"), writeConfig.SecureSettings["basicAuthPassword"])
+
+	revision, err := storage.Revision(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), revision)
+
+	require.NoFileExists(t, filepath.Join(dataPath, "pipeline", "live-channel-rules.json"))
+	require.FileExists(t, filepath.Join(dataPath, "pipeline", "live-channel-rules.json.imported"))
+	require.FileExists(t, filepath.Join(dataPath, "pipeline", "write-configs.json.imported"))
+
+	// Import runs once.
+	require.NoError(t, storage.DeleteChannelRule(ctx, 1, ChannelRuleDeleteCmd{Pattern: "stream/test/:path"}))
+	require.NoError(t, storage.ImportFileStorage(ctx, fileStorage))
+	rules, err = storage.ListChannelRules(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+}
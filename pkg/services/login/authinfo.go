@@ -31,8 +31,10 @@ const (
 	LDAPAuthModule      = "ldap"
 	AuthProxyAuthModule = "authproxy"
 	JWTModule           = "jwt"
+	MTLSAuthModule      = "mtls"
 	ExtendedJWTModule   = "extendedjwt"
 	RenderModule        = "render"
+	PlaylistKioskModule = "playlist_kiosk"
 	// OAuth provider modules
 	AzureADAuthModule    = "oauth_azuread"
 	GoogleAuthModule     = "oauth_google"
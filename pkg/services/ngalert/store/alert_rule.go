@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	storesrv "github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/store/entity"
 	"github.com/grafana/grafana/pkg/util"
 	"xorm.io/xorm"
@@ -71,6 +72,14 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("Deleted alert instances", "count", rows)
+
+		if st.emitEntityEvent() {
+			for _, uid := range ruleUID {
+				if _, err := sess.Insert(storesrv.NewDatabaseEntityEvent(uid, orgID, storesrv.EntityTypeAlertRule, storesrv.EntityEventTypeDelete)); err != nil {
+					return err
+				}
+			}
+		}
 		return nil
 	})
 }
@@ -178,6 +187,11 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 					AlertRuleKey: newRules[i].GetKey(),
 					ID:           newRules[i].ID,
 				})
+				if st.emitEntityEvent() {
+					if _, err := sess.Insert(storesrv.NewDatabaseEntityEvent(newRules[i].UID, newRules[i].OrgID, storesrv.EntityTypeAlertRule, storesrv.EntityEventTypeCreate)); err != nil {
+						return err
+					}
+				}
 			}
 		}
 
@@ -219,6 +233,11 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				}
 				return fmt.Errorf("%w: alert rule UID %s version %d", ErrOptimisticLock, r.New.UID, r.New.Version)
 			}
+			if st.emitEntityEvent() {
+				if _, err := sess.Insert(storesrv.NewDatabaseEntityEvent(r.New.UID, r.New.OrgID, storesrv.EntityTypeAlertRule, storesrv.EntityEventTypeUpdate)); err != nil {
+					return err
+				}
+			}
 			parentVersion = r.Existing.Version
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
 				RuleOrgID:            r.New.OrgID,
@@ -250,6 +269,11 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 	})
 }
 
+// emitEntityEvent returns true if changes to alert rules are recorded as entity events for the search index.
+func (st DBstore) emitEntityEvent() bool {
+	return st.FeatureToggles != nil && st.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagPanelTitleSearch)
+}
+
 // preventIntermediateUniqueConstraintViolations prevents unique constraint violations caused by an intermediate update.
 // The uniqueness constraint for titles within an org+folder is enforced on every update within a transaction
 // instead of on commit (deferred constraint). This means that there could be a set of updates that will throw
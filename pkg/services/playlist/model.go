@@ -2,14 +2,23 @@ package playlist
 
 import (
 	"errors"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 // Typed errors
 var (
 	ErrPlaylistNotFound        = errors.New("Playlist not found")
 	ErrCommandValidationFailed = errors.New("command missing required fields")
+	ErrInvalidItem             = errutil.BadRequest("playlist.invalid-item", errutil.WithPublicMessage("Invalid playlist item"))
+	ErrKioskTokenNotFound      = errutil.NotFound("playlist.kiosk-token-not-found", errutil.WithPublicMessage("Playlist kiosk token not found"))
 )
 
+// KioskCookieName is the cookie that holds the kiosk token of the playlist
+// a device runs.
+const KioskCookieName = "grafana_playlist_kiosk"
+
 // Playlist model
 type Playlist struct {
 	Id       int64  `json:"id,omitempty" db:"id"`
@@ -18,6 +27,16 @@ type Playlist struct {
 	Interval string `json:"interval" db:"interval"`
 	OrgId    int64  `json:"-" db:"org_id"`
 
+	// KioskToken is the hash of the token that lets a device run the
+	// playlist without a user login. Empty when kiosk access is disabled.
+	KioskToken string `json:"-" xorm:"kiosk_token" db:"kiosk_token"`
+	// KioskDashboardUIDs and KioskDataSourceUIDs are what the kiosk token
+	// grants access to. They are resolved when the token is created, from
+	// the dashboards of the playlist its creator could view and the data
+	// sources of those dashboards its creator could query.
+	KioskDashboardUIDs  []string `json:"-" xorm:"kiosk_dashboard_uids" db:"kiosk_dashboard_uids"`
+	KioskDataSourceUIDs []string `json:"-" xorm:"kiosk_datasource_uids" db:"kiosk_datasource_uids"`
+
 	// Added for kubernetes migration + synchronization
 	// Hidden from json because this is used for openapi generation
 	// Using int64 rather than time.Time to avoid database issues with time support
@@ -39,6 +58,14 @@ type PlaylistDTO struct {
 	// The ordered list of items that the playlist will iterate over.
 	Items []PlaylistItemDTO `json:"items,omitempty"`
 
+	// KioskEnabled is true when the playlist has a kiosk token.
+	KioskEnabled bool `json:"kioskEnabled,omitempty" db:"-"`
+
+	// KioskDashboardUIDs and KioskDataSourceUIDs are only set when the
+	// playlist is fetched by its kiosk token.
+	KioskDashboardUIDs  []string `json:"-" db:"-"`
+	KioskDataSourceUIDs []string `json:"-" db:"-"`
+
 	// Returned for k8s
 	CreatedAt int64 `json:"-" db:"created_at"`
 
@@ -68,15 +95,31 @@ type PlaylistItemDTO struct {
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
 	Value string `json:"value"`
+
+	// Duration overrides the interval of the playlist for this item.
+	Duration string `json:"duration,omitempty"`
+
+	// TimeFrom and TimeTo override the time range of the dashboards of this item,
+	// for example now-24h and now.
+	TimeFrom string `json:"timeFrom,omitempty"`
+	TimeTo   string `json:"timeTo,omitempty"`
+
+	// Variables overrides the values of the template variables of the dashboards
+	// of this item, by variable name.
+	Variables map[string]string `json:"variables,omitempty"`
 }
 
 type PlaylistItem struct {
-	Id         int64  `db:"id"`
-	PlaylistId int64  `db:"playlist_id"`
-	Type       string `json:"type" db:"type"`
-	Value      string `json:"value" db:"value"`
-	Order      int    `json:"order" db:"order"`
-	Title      string `json:"title" db:"title"`
+	Id         int64             `db:"id"`
+	PlaylistId int64             `db:"playlist_id"`
+	Type       string            `json:"type" db:"type"`
+	Value      string            `json:"value" db:"value"`
+	Order      int               `json:"order" db:"order"`
+	Title      string            `json:"title" db:"title"`
+	Duration   string            `json:"duration,omitempty" db:"duration"`
+	TimeFrom   string            `json:"timeFrom,omitempty" db:"time_from"`
+	TimeTo     string            `json:"timeTo,omitempty" db:"time_to"`
+	Variables  map[string]string `json:"variables,omitempty" db:"variables"`
 }
 
 type Playlists []*Playlist
@@ -107,6 +150,19 @@ type DeletePlaylistCommand struct {
 	OrgId int64
 }
 
+// CreateKioskTokenCommand replaces the kiosk token of a playlist with a new
+// one. The token only grants access to what SignedInUser can view and query.
+type CreateKioskTokenCommand struct {
+	UID          string
+	OrgId        int64
+	SignedInUser identity.Requester
+}
+
+type DeleteKioskTokenCommand struct {
+	UID   string
+	OrgId int64
+}
+
 //
 // QUERIES
 //
@@ -127,3 +183,14 @@ type GetPlaylistItemsByUidQuery struct {
 	PlaylistUID string
 	OrgId       int64
 }
+
+type GetPlaylistByKioskTokenQuery struct {
+	KioskToken string
+}
+
+// GetPlaylistDashboardUIDsQuery resolves the items of a playlist to the uids
+// of the dashboards they show.
+type GetPlaylistDashboardUIDsQuery struct {
+	PlaylistUID string
+	OrgId       int64
+}
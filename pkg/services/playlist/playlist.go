@@ -12,6 +12,13 @@ type Service interface {
 	Search(context.Context, *GetPlaylistsQuery) (Playlists, error)
 	Delete(ctx context.Context, cmd *DeletePlaylistCommand) error
 
+	// CreateKioskToken returns a new token that lets a device run the playlist
+	// without a user login. Any previous token of the playlist stops working.
+	CreateKioskToken(ctx context.Context, cmd *CreateKioskTokenCommand) (string, error)
+	DeleteKioskToken(ctx context.Context, cmd *DeleteKioskTokenCommand) error
+	GetByKioskToken(ctx context.Context, q *GetPlaylistByKioskTokenQuery) (*PlaylistDTO, error)
+	GetDashboardUIDs(ctx context.Context, q *GetPlaylistDashboardUIDsQuery) ([]string, error)
+
 	// This is optimized for the kubernetes list command that returns full bodies in the list
 	List(ctx context.Context, orgId int64) ([]PlaylistDTO, error)
 }
@@ -0,0 +1,182 @@
+package playlistimpl
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/playlist"
+)
+
+const mixedDataSourceUID = "-- Mixed --"
+
+// resolveKioskGrants returns the dashboards of the playlist the user can view
+// and the data sources used by those dashboards the user can query, so a kiosk
+// token never grants more than its creator has.
+func (s *Service) resolveKioskGrants(ctx context.Context, cmd *playlist.CreateKioskTokenCommand) ([]string, []string, error) {
+	uids, err := s.store.GetDashboardUIDs(ctx, &playlist.GetPlaylistDashboardUIDsQuery{PlaylistUID: cmd.UID, OrgId: cmd.OrgId})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dashboardUIDs := make([]string, 0, len(uids))
+	viewable := make([]*dashboards.Dashboard, 0, len(uids))
+	for _, uid := range uids {
+		dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: uid, OrgID: cmd.OrgId})
+		if err != nil {
+			if errors.Is(err, dashboards.ErrDashboardNotFound) {
+				continue
+			}
+			return nil, nil, err
+		}
+
+		g, err := guardian.NewByDashboard(ctx, dash, cmd.OrgId, cmd.SignedInUser)
+		if err != nil {
+			return nil, nil, err
+		}
+		canView, err := g.CanView()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !canView {
+			continue
+		}
+
+		dashboardUIDs = append(dashboardUIDs, uid)
+		viewable = append(viewable, dash)
+	}
+
+	used, err := s.getDataSourceUIDs(ctx, cmd.OrgId, viewable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	permissions := cmd.SignedInUser.GetPermissions()
+	dataSourceUIDs := make([]string, 0, len(used))
+	for _, uid := range used {
+		evaluator := accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(uid))
+		if evaluator.Evaluate(permissions) {
+			dataSourceUIDs = append(dataSourceUIDs, uid)
+		}
+	}
+
+	return dashboardUIDs, dataSourceUIDs, nil
+}
+
+// getDataSourceUIDs returns the uids of the data sources used by the panels,
+// variables and annotations of the dashboards. Panels without a data source
+// use the default data source and data source variables can select any data
+// source of their type.
+func (s *Service) getDataSourceUIDs(ctx context.Context, orgID int64, dashes []*dashboards.Dashboard) ([]string, error) {
+	var uids []string
+	seen := map[string]bool{}
+	add := func(uid string) {
+		if !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+
+	var types []string
+	useDefault := false
+	for _, dash := range dashes {
+		if dash.Data == nil {
+			continue
+		}
+
+		var sources []*simplejson.Json
+		for _, panel := range flattenPanels(dash.Data.Get("panels").MustArray()) {
+			ref := panel.Get("datasource")
+			targets := panel.Get("targets").MustArray()
+			switch {
+			case ref.Get("uid").MustString() == mixedDataSourceUID || ref.MustString() == mixedDataSourceUID:
+				for _, target := range targets {
+					sources = append(sources, simplejson.NewFromAny(target))
+				}
+			case ref.Interface() != nil || len(targets) > 0:
+				sources = append(sources, panel)
+			}
+		}
+		for _, variable := range dash.Data.Get("templating").Get("list").MustArray() {
+			v := simplejson.NewFromAny(variable)
+			if v.Get("type").MustString() == "datasource" {
+				types = append(types, v.Get("query").MustString())
+				continue
+			}
+			if _, ok := v.CheckGet("datasource"); ok {
+				sources = append(sources, v)
+			}
+		}
+		for _, annotation := range dash.Data.Get("annotations").Get("list").MustArray() {
+			sources = append(sources, simplejson.NewFromAny(annotation))
+		}
+
+		for _, source := range sources {
+			ref := source.Get("datasource")
+			uid := ref.Get("uid").MustString()
+			if name, ok := ref.Interface().(string); ok {
+				// before 8.3 data sources were referenced by name
+				uid = name
+				if name != "" && !strings.HasPrefix(name, "$") {
+					ds, err := s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{Name: name, OrgID: orgID})
+					if err != nil && !errors.Is(err, datasources.ErrDataSourceNotFound) {
+						return nil, err
+					}
+					if ds != nil {
+						uid = ds.UID
+					}
+				}
+			}
+
+			switch {
+			case uid == "":
+				useDefault = true
+			case strings.HasPrefix(uid, "$"):
+				// resolved by a data source variable
+			default:
+				add(uid)
+			}
+		}
+	}
+
+	if useDefault {
+		ds, err := s.dataSourceService.GetDefaultDataSource(ctx, &datasources.GetDefaultDataSourceQuery{OrgID: orgID})
+		if err != nil && !errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return nil, err
+		}
+		if ds != nil {
+			add(ds.UID)
+		}
+	}
+
+	for _, dsType := range types {
+		result, err := s.dataSourceService.GetDataSourcesByType(ctx, &datasources.GetDataSourcesByTypeQuery{OrgID: orgID, Type: dsType})
+		if err != nil {
+			return nil, err
+		}
+		for _, ds := range result {
+			add(ds.UID)
+		}
+	}
+
+	return uids, nil
+}
+
+// flattenPanels returns the panels including those of collapsed rows.
+func flattenPanels(panels []any) []*simplejson.Json {
+	var result []*simplejson.Json
+	for _, p := range panels {
+		panel := simplejson.NewFromAny(p)
+		if panel.Get("type").MustString() == "row" {
+			result = append(result, flattenPanels(panel.Get("panels").MustArray())...)
+			continue
+		}
+		result = append(result, panel)
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package playlistimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestService_CreateKioskToken(t *testing.T) {
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "ops", OrgID: 2}).Return(&dashboards.Dashboard{
+		UID: "ops",
+		Data: simplejson.MustJson([]byte(`{
+			"panels": [
+				{"datasource": {"uid": "prometheus"}, "targets": [{"refId": "A"}]},
+				{"type": "row", "collapsed": true, "panels": [
+					{"datasource": {"uid": "-- Mixed --"}, "targets": [{"datasource": {"uid": "loki"}}, {"datasource": {"uid": "${ds}"}}]}
+				]},
+				{"type": "text"}
+			],
+			"templating": {"list": [{"type": "datasource", "name": "ds", "query": "tempo"}]}
+		}`)),
+	}, nil)
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "db", OrgID: 2}).Return(&dashboards.Dashboard{
+		UID: "db",
+		Data: simplejson.MustJson([]byte(`{
+			"panels": [{"datasource": "MySQL", "targets": [{"refId": "A"}]}],
+			"annotations": {"list": [{"datasource": {"uid": "loki"}}]}
+		}`)),
+	}, nil)
+	// tagged like the playlist dashboards, but the creator can not view it
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "secret", OrgID: 2}).Return(&dashboards.Dashboard{
+		UID:  "secret",
+		Data: simplejson.MustJson([]byte(`{"panels": [{"datasource": {"uid": "payroll"}, "targets": [{"refId": "A"}]}]}`)),
+	}, nil)
+	dataSourceService := &fakes.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		{UID: "mysql", Name: "MySQL", Type: "mysql", OrgID: 2},
+		{UID: "tempo", Name: "Tempo", Type: "tempo", OrgID: 2},
+		{UID: "other", Name: "Other", Type: "postgres", OrgID: 2},
+	}}
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewUIDs: []string{"ops", "db"}})
+
+	setup := func(t *testing.T) (*Service, *kioskStore) {
+		t.Helper()
+		store := &kioskStore{dashboardUIDs: []string{"ops", "db", "secret"}}
+		return &Service{
+			store:             store,
+			tracer:            tracing.InitializeTracerForTest(),
+			dashboardService:  dashboardService,
+			dataSourceService: dataSourceService,
+		}, store
+	}
+	creator := func(dataSourceScopes ...string) *user.SignedInUser {
+		return &user.SignedInUser{UserID: 1, OrgID: 2, Permissions: map[int64]map[string][]string{
+			2: {datasources.ActionQuery: dataSourceScopes},
+		}}
+	}
+
+	t.Run("should only grant the dashboards and data sources the creator can access", func(t *testing.T) {
+		s, store := setup(t)
+
+		token, err := s.CreateKioskToken(context.Background(), &playlist.CreateKioskTokenCommand{UID: "noc", OrgId: 2, SignedInUser: creator(datasources.ScopeAll)})
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+		require.Equal(t, hashKioskToken(token), store.hashedToken)
+
+		require.Equal(t, []string{"ops", "db"}, store.grantedDashboards)
+		require.Equal(t, []string{"prometheus", "loki", "mysql", "tempo"}, store.grantedDataSources)
+	})
+
+	t.Run("should not grant data sources the creator can not query", func(t *testing.T) {
+		s, store := setup(t)
+
+		_, err := s.CreateKioskToken(context.Background(), &playlist.CreateKioskTokenCommand{UID: "noc", OrgId: 2, SignedInUser: creator(
+			datasources.ScopeProvider.GetResourceScopeUID("prometheus"),
+			datasources.ScopeProvider.GetResourceScopeUID("payroll"),
+		)})
+		require.NoError(t, err)
+
+		// payroll is only used by the dashboard the creator can not view
+		require.Equal(t, []string{"prometheus"}, store.grantedDataSources)
+	})
+}
+
+// kioskStore resolves the dashboards of any playlist and records the grants
+// stored with the kiosk token.
+type kioskStore struct {
+	store
+	dashboardUIDs []string
+
+	hashedToken        string
+	grantedDashboards  []string
+	grantedDataSources []string
+}
+
+func (s *kioskStore) GetDashboardUIDs(context.Context, *playlist.GetPlaylistDashboardUIDsQuery) ([]string, error) {
+	return s.dashboardUIDs, nil
+}
+
+func (s *kioskStore) SetKioskToken(_ context.Context, _ int64, _ string, hashedToken string, dashboardUIDs, dataSourceUIDs []string) error {
+	s.hashedToken = hashedToken
+	s.grantedDashboards = dashboardUIDs
+	s.grantedDataSources = dataSourceUIDs
+	return nil
+}
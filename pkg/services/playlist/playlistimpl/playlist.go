@@ -2,37 +2,60 @@ package playlistimpl
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util"
 )
 
+const kioskTokenLength = 32
+
+var variableNameRegex = regexp.MustCompile(`^\w+$`)
+
 type Service struct {
-	store  store
-	tracer tracing.Tracer
+	store             store
+	tracer            tracing.Tracer
+	dashboardService  dashboards.DashboardService
+	dataSourceService datasources.DataSourceService
 }
 
 var _ playlist.Service = &Service{}
 
-func ProvideService(db db.DB, tracer tracing.Tracer) playlist.Service {
+func ProvideService(db db.DB, tracer tracing.Tracer, dashboardService dashboards.DashboardService, dataSourceService datasources.DataSourceService) playlist.Service {
 	return &Service{
 		tracer: tracer,
 		store: &sqlStore{
 			db: db,
 		},
+		dashboardService:  dashboardService,
+		dataSourceService: dataSourceService,
 	}
 }
 
 func (s *Service) Create(ctx context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
 	ctx, span := s.tracer.Start(ctx, "playlists.Create")
 	defer span.End()
+	if err := validateItems(cmd.Items); err != nil {
+		return nil, err
+	}
 	return s.store.Insert(ctx, cmd)
 }
 
 func (s *Service) Update(ctx context.Context, cmd *playlist.UpdatePlaylistCommand) (*playlist.PlaylistDTO, error) {
 	ctx, span := s.tracer.Start(ctx, "playlists.Update")
 	defer span.End()
+	if err := validateItems(cmd.Items); err != nil {
+		return nil, err
+	}
 	return s.store.Update(ctx, cmd)
 }
 
@@ -49,9 +72,13 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 	if err != nil {
 		return nil, err
 	}
+	return s.toDTO(ctx, v)
+}
+
+func (s *Service) toDTO(ctx context.Context, v *playlist.Playlist) (*playlist.PlaylistDTO, error) {
 	rawItems, err := s.store.GetItems(ctx, &playlist.GetPlaylistItemsByUidQuery{
 		PlaylistUID: v.UID,
-		OrgId:       q.OrgId,
+		OrgId:       v.OrgId,
 	})
 	if err != nil {
 		return nil, err
@@ -60,6 +87,10 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 	for i := 0; i < len(rawItems); i++ {
 		items[i].Type = rawItems[i].Type
 		items[i].Value = rawItems[i].Value
+		items[i].Duration = rawItems[i].Duration
+		items[i].TimeFrom = rawItems[i].TimeFrom
+		items[i].TimeTo = rawItems[i].TimeTo
+		items[i].Variables = rawItems[i].Variables
 
 		// Add the unused title to the result
 		title := rawItems[i].Title
@@ -68,14 +99,15 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 		}
 	}
 	return &playlist.PlaylistDTO{
-		Id:        v.Id,
-		Uid:       v.UID,
-		Name:      v.Name,
-		Interval:  v.Interval,
-		Items:     items,
-		CreatedAt: v.CreatedAt,
-		UpdatedAt: v.UpdatedAt,
-		OrgID:     v.OrgId,
+		Id:           v.Id,
+		Uid:          v.UID,
+		Name:         v.Name,
+		Interval:     v.Interval,
+		Items:        items,
+		KioskEnabled: v.KioskToken != "",
+		CreatedAt:    v.CreatedAt,
+		UpdatedAt:    v.UpdatedAt,
+		OrgID:        v.OrgId,
 	}, nil
 }
 
@@ -96,3 +128,93 @@ func (s *Service) List(ctx context.Context, orgId int64) ([]playlist.PlaylistDTO
 	defer span.End()
 	return s.store.ListAll(ctx, orgId)
 }
+
+func (s *Service) CreateKioskToken(ctx context.Context, cmd *playlist.CreateKioskTokenCommand) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "playlists.CreateKioskToken")
+	defer span.End()
+	if cmd.SignedInUser == nil {
+		return "", playlist.ErrCommandValidationFailed
+	}
+
+	dashboardUIDs, dataSourceUIDs, err := s.resolveKioskGrants(ctx, cmd)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := util.GetRandomString(kioskTokenLength)
+	if err != nil {
+		return "", err
+	}
+	if err := s.store.SetKioskToken(ctx, cmd.OrgId, cmd.UID, hashKioskToken(token), dashboardUIDs, dataSourceUIDs); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *Service) DeleteKioskToken(ctx context.Context, cmd *playlist.DeleteKioskTokenCommand) error {
+	ctx, span := s.tracer.Start(ctx, "playlists.DeleteKioskToken")
+	defer span.End()
+	return s.store.SetKioskToken(ctx, cmd.OrgId, cmd.UID, "", nil, nil)
+}
+
+func (s *Service) GetByKioskToken(ctx context.Context, q *playlist.GetPlaylistByKioskTokenQuery) (*playlist.PlaylistDTO, error) {
+	ctx, span := s.tracer.Start(ctx, "playlists.GetByKioskToken")
+	defer span.End()
+	if q.KioskToken == "" {
+		return nil, playlist.ErrKioskTokenNotFound.Errorf("empty kiosk token")
+	}
+	v, err := s.store.GetByKioskToken(ctx, hashKioskToken(q.KioskToken))
+	if err != nil {
+		return nil, err
+	}
+	dto, err := s.toDTO(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	dto.KioskDashboardUIDs = v.KioskDashboardUIDs
+	dto.KioskDataSourceUIDs = v.KioskDataSourceUIDs
+	return dto, nil
+}
+
+func (s *Service) GetDashboardUIDs(ctx context.Context, q *playlist.GetPlaylistDashboardUIDsQuery) ([]string, error) {
+	ctx, span := s.tracer.Start(ctx, "playlists.GetDashboardUIDs")
+	defer span.End()
+	return s.store.GetDashboardUIDs(ctx, q)
+}
+
+// hashKioskToken hashes kiosk tokens before they are stored, the token itself
+// is only returned when it is created.
+func hashKioskToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func validateItems(items []playlist.PlaylistItem) error {
+	for i, item := range items {
+		if item.Duration != "" {
+			if d, err := gtime.ParseDuration(item.Duration); err != nil || d <= 0 {
+				return playlist.ErrInvalidItem.Errorf("item %d has an invalid duration %q", i, item.Duration)
+			}
+		}
+
+		if (item.TimeFrom == "") != (item.TimeTo == "") {
+			return playlist.ErrInvalidItem.Errorf("item %d needs both the start and the end of its time range", i)
+		}
+		if item.TimeFrom != "" {
+			timeRange := legacydata.NewDataTimeRange(item.TimeFrom, item.TimeTo)
+			if _, err := timeRange.ParseFrom(); err != nil {
+				return playlist.ErrInvalidItem.Errorf("item %d has an invalid time range start %q", i, item.TimeFrom)
+			}
+			if _, err := timeRange.ParseTo(); err != nil {
+				return playlist.ErrInvalidItem.Errorf("item %d has an invalid time range end %q", i, item.TimeTo)
+			}
+		}
+
+		for name := range item.Variables {
+			if !variableNameRegex.MatchString(name) {
+				return playlist.ErrInvalidItem.Errorf("item %d has an invalid variable name %q", i, name)
+			}
+		}
+	}
+	return nil
+}
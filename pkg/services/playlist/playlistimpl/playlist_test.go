@@ -0,0 +1,43 @@
+package playlistimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/playlist"
+)
+
+func TestValidateItems(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		item  playlist.PlaylistItem
+		valid bool
+	}{
+		{desc: "no overrides", item: playlist.PlaylistItem{Type: "dashboard_by_uid", Value: "ops"}, valid: true},
+		{desc: "all overrides", item: playlist.PlaylistItem{Type: "dashboard_by_uid", Value: "ops", Duration: "1d",
+			TimeFrom: "now-24h", TimeTo: "now", Variables: map[string]string{"env": "prod"}}, valid: true},
+		{desc: "invalid duration", item: playlist.PlaylistItem{Duration: "often"}},
+		{desc: "negative duration", item: playlist.PlaylistItem{Duration: "-5m"}},
+		{desc: "time range without end", item: playlist.PlaylistItem{TimeFrom: "now-24h"}},
+		{desc: "invalid time range", item: playlist.PlaylistItem{TimeFrom: "yesterday", TimeTo: "now"}},
+		{desc: "invalid variable name", item: playlist.PlaylistItem{Variables: map[string]string{"my env": "prod"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := validateItems([]playlist.PlaylistItem{tc.item})
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, playlist.ErrInvalidItem)
+			}
+		})
+	}
+}
+
+func TestHashKioskToken(t *testing.T) {
+	require.Equal(t, hashKioskToken("token"), hashKioskToken("token"))
+	require.NotEqual(t, hashKioskToken("token"), hashKioskToken("other"))
+	require.Len(t, hashKioskToken("token"), 64)
+}
@@ -13,6 +13,11 @@ type store interface {
 	GetItems(context.Context, *playlist.GetPlaylistItemsByUidQuery) ([]playlist.PlaylistItem, error)
 	List(context.Context, *playlist.GetPlaylistsQuery) (playlist.Playlists, error)
 	Update(context.Context, *playlist.UpdatePlaylistCommand) (*playlist.PlaylistDTO, error)
+	// SetKioskToken replaces the kiosk token of a playlist along with the
+	// dashboards and data sources it grants access to.
+	SetKioskToken(ctx context.Context, orgId int64, uid string, hashedToken string, dashboardUIDs, dataSourceUIDs []string) error
+	GetByKioskToken(ctx context.Context, hashedToken string) (*playlist.Playlist, error)
+	GetDashboardUIDs(context.Context, *playlist.GetPlaylistDashboardUIDsQuery) ([]string, error)
 
 	// This is optimized for the kubernetes list command that returns full bodies in the list
 	ListAll(ctx context.Context, orgId int64) ([]playlist.PlaylistDTO, error)
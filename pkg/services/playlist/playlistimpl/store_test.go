@@ -9,7 +9,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/tests/testsuite"
 )
@@ -206,6 +208,95 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 		})
 	})
 
+	t.Run("Can store item overrides", func(t *testing.T) {
+		items := []playlist.PlaylistItem{
+			{Value: "ops", Type: "dashboard_by_uid", Duration: "2m", TimeFrom: "now-24h", TimeTo: "now",
+				Variables: map[string]string{"env": "prod"}},
+			{Value: "graphite", Type: "dashboard_by_tag"},
+		}
+		cmd := playlist.CreatePlaylistCommand{Name: "NOC wall", Interval: "5m", OrgId: 3, Items: items}
+		p, err := playlistStore.Insert(context.Background(), &cmd)
+		require.NoError(t, err)
+
+		stored, err := playlistStore.GetItems(context.Background(), &playlist.GetPlaylistItemsByUidQuery{PlaylistUID: p.UID, OrgId: 3})
+		require.NoError(t, err)
+		require.Len(t, stored, 2)
+		require.Equal(t, "2m", stored[0].Duration)
+		require.Equal(t, "now-24h", stored[0].TimeFrom)
+		require.Equal(t, "now", stored[0].TimeTo)
+		require.Equal(t, map[string]string{"env": "prod"}, stored[0].Variables)
+		require.Empty(t, stored[1].Duration)
+		require.Empty(t, stored[1].Variables)
+
+		all, err := playlistStore.ListAll(context.Background(), 3)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, playlist.PlaylistItemDTO{
+			Type: "dashboard_by_uid", Value: "ops", Duration: "2m", TimeFrom: "now-24h", TimeTo: "now",
+			Variables: map[string]string{"env": "prod"},
+		}, all[0].Items[0])
+	})
+
+	t.Run("Can find playlist by kiosk token", func(t *testing.T) {
+		cmd := playlist.CreatePlaylistCommand{Name: "Kiosk", Interval: "5m", OrgId: 1, UID: "kiosk"}
+		_, err := playlistStore.Insert(context.Background(), &cmd)
+		require.NoError(t, err)
+
+		_, err = playlistStore.GetByKioskToken(context.Background(), "hashed")
+		require.ErrorIs(t, err, playlist.ErrKioskTokenNotFound)
+
+		err = playlistStore.SetKioskToken(context.Background(), 1, "kiosk", "hashed", []string{"ops"}, []string{"prometheus"})
+		require.NoError(t, err)
+		p, err := playlistStore.GetByKioskToken(context.Background(), "hashed")
+		require.NoError(t, err)
+		require.Equal(t, "kiosk", p.UID)
+		require.Equal(t, []string{"ops"}, p.KioskDashboardUIDs)
+		require.Equal(t, []string{"prometheus"}, p.KioskDataSourceUIDs)
+
+		err = playlistStore.SetKioskToken(context.Background(), 1, "kiosk", "", nil, nil)
+		require.NoError(t, err)
+		_, err = playlistStore.GetByKioskToken(context.Background(), "hashed")
+		require.ErrorIs(t, err, playlist.ErrKioskTokenNotFound)
+
+		err = playlistStore.SetKioskToken(context.Background(), 1, "missing", "hashed", nil, nil)
+		require.ErrorIs(t, err, playlist.ErrPlaylistNotFound)
+	})
+
+	t.Run("Can resolve the dashboards of a playlist", func(t *testing.T) {
+		var ids []int64
+		err := ss.WithDbSession(context.Background(), func(sess *db.Session) error {
+			for _, uid := range []string{"b-tagged", "a-tagged", "by-id"} {
+				dash := &dashboards.Dashboard{UID: uid, OrgID: 4, Slug: uid, Title: uid, Data: simplejson.New(),
+					Created: time.Now(), Updated: time.Now()}
+				if _, err := sess.Insert(dash); err != nil {
+					return err
+				}
+				ids = append(ids, dash.ID)
+			}
+			for _, id := range ids[:2] {
+				if _, err := sess.Exec("INSERT INTO dashboard_tag (dashboard_id, term) VALUES (?, ?)", id, "noc"); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		items := []playlist.PlaylistItem{
+			{Value: "by-uid", Type: "dashboard_by_uid"},
+			{Value: "noc", Type: "dashboard_by_tag"},
+			{Value: fmt.Sprintf("%d", ids[2]), Type: "dashboard_by_id"},
+			{Value: "a-tagged", Type: "dashboard_by_uid"},
+		}
+		cmd := playlist.CreatePlaylistCommand{Name: "NOC", Interval: "5m", OrgId: 4, Items: items}
+		p, err := playlistStore.Insert(context.Background(), &cmd)
+		require.NoError(t, err)
+
+		uids, err := playlistStore.GetDashboardUIDs(context.Background(), &playlist.GetPlaylistDashboardUIDsQuery{PlaylistUID: p.UID, OrgId: 4})
+		require.NoError(t, err)
+		require.Equal(t, []string{"by-uid", "a-tagged", "b-tagged", "by-id"}, uids)
+	})
+
 	t.Run("Delete playlist that doesn't exist, should not return error", func(t *testing.T) {
 		deleteQuery := playlist.DeletePlaylistCommand{UID: "654312", OrgId: 1}
 		err := playlistStore.Delete(context.Background(), &deleteQuery)
@@ -2,7 +2,10 @@ package playlistimpl
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
@@ -52,6 +55,10 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 				Value:      item.Value,
 				Order:      order + 1,
 				Title:      item.Title,
+				Duration:   item.Duration,
+				TimeFrom:   item.TimeFrom,
+				TimeTo:     item.TimeTo,
+				Variables:  item.Variables,
 			})
 		}
 
@@ -108,6 +115,10 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 				Value:      item.Value,
 				Order:      index + 1,
 				Title:      item.Title,
+				Duration:   item.Duration,
+				TimeFrom:   item.TimeFrom,
+				TimeTo:     item.TimeTo,
+				Variables:  item.Variables,
 			})
 		}
 
@@ -185,7 +196,8 @@ func (s *sqlStore) ListAll(ctx context.Context, orgId int64) ([]playlist.Playlis
 	db := s.db.GetSqlxSession() // OK because dates are numbers!
 
 	playlists := []playlist.PlaylistDTO{}
-	err := db.Select(ctx, &playlists, "SELECT * FROM playlist WHERE org_id=? ORDER BY created_at asc", orgId)
+	err := db.Select(ctx, &playlists, `SELECT id,uid,name,`+s.db.Quote("interval")+`,org_id,created_at,updated_at
+		FROM playlist WHERE org_id=? ORDER BY created_at asc`, orgId)
 	if err != nil {
 		return nil, err
 	}
@@ -199,8 +211,10 @@ func (s *sqlStore) ListAll(ctx context.Context, orgId int64) ([]playlist.Playlis
 	var playlistId int64
 	var itemType string
 	var itemValue string
+	var itemDuration, itemTimeFrom, itemTimeTo, itemVariables sql.NullString
 
-	rows, err := db.Query(ctx, `SELECT playlist.id,playlist_item.type,playlist_item.value
+	rows, err := db.Query(ctx, `SELECT playlist.id,playlist_item.type,playlist_item.value,
+		playlist_item.duration,playlist_item.time_from,playlist_item.time_to,playlist_item.variables
 		FROM playlist_item 
 		JOIN playlist ON playlist_item.playlist_id = playlist.id
 		WHERE playlist.org_id = ?
@@ -209,7 +223,7 @@ func (s *sqlStore) ListAll(ctx context.Context, orgId int64) ([]playlist.Playlis
 		return nil, err
 	}
 	for rows.Next() {
-		err = rows.Scan(&playlistId, &itemType, &itemValue)
+		err = rows.Scan(&playlistId, &itemType, &itemValue, &itemDuration, &itemTimeFrom, &itemTimeTo, &itemVariables)
 		if err != nil {
 			return nil, err
 		}
@@ -217,10 +231,19 @@ func (s *sqlStore) ListAll(ctx context.Context, orgId int64) ([]playlist.Playlis
 		if !ok {
 			return nil, fmt.Errorf("could not find playlist by id")
 		}
-		items := append(playlists[idx].Items, playlist.PlaylistItemDTO{
-			Type:  itemType,
-			Value: itemValue,
-		})
+		item := playlist.PlaylistItemDTO{
+			Type:     itemType,
+			Value:    itemValue,
+			Duration: itemDuration.String,
+			TimeFrom: itemTimeFrom.String,
+			TimeTo:   itemTimeTo.String,
+		}
+		if itemVariables.String != "" {
+			if err := json.Unmarshal([]byte(itemVariables.String), &item.Variables); err != nil {
+				return nil, err
+			}
+		}
+		items := append(playlists[idx].Items, item)
 		playlists[idx].Items = items
 	}
 	return playlists, err
@@ -239,9 +262,106 @@ func (s *sqlStore) GetItems(ctx context.Context, query *playlist.GetPlaylistItem
 			return err
 		}
 
-		err = sess.Where("playlist_id=?", p.Id).Find(&playlistItems)
+		err = sess.Where("playlist_id=?", p.Id).OrderBy(s.db.Quote("order")).Find(&playlistItems)
 
 		return err
 	})
 	return playlistItems, err
 }
+
+func (s *sqlStore) SetKioskToken(ctx context.Context, orgId int64, uid string, hashedToken string, dashboardUIDs, dataSourceUIDs []string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		p := playlist.Playlist{
+			KioskToken:          hashedToken,
+			KioskDashboardUIDs:  dashboardUIDs,
+			KioskDataSourceUIDs: dataSourceUIDs,
+		}
+		affected, err := sess.Where("org_id = ? AND uid = ?", orgId, uid).
+			Cols("kiosk_token", "kiosk_dashboard_uids", "kiosk_datasource_uids").
+			Update(&p)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return playlist.ErrPlaylistNotFound
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) GetByKioskToken(ctx context.Context, hashedToken string) (*playlist.Playlist, error) {
+	if hashedToken == "" {
+		return nil, playlist.ErrCommandValidationFailed
+	}
+
+	p := playlist.Playlist{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("kiosk_token = ?", hashedToken).Get(&p)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return playlist.ErrKioskTokenNotFound.Errorf("no playlist found for kiosk token")
+		}
+		return nil
+	})
+	return &p, err
+}
+
+type dashboardUID struct {
+	UID string `xorm:"uid"`
+}
+
+// GetDashboardUIDs resolves the items of a playlist to the uids of the
+// dashboards they currently show, in the order of the items.
+func (s *sqlStore) GetDashboardUIDs(ctx context.Context, query *playlist.GetPlaylistDashboardUIDsQuery) ([]string, error) {
+	items, err := s.GetItems(ctx, &playlist.GetPlaylistItemsByUidQuery{PlaylistUID: query.PlaylistUID, OrgId: query.OrgId})
+	if err != nil {
+		return nil, err
+	}
+
+	uids := make([]string, 0, len(items))
+	seen := map[string]bool{}
+	add := func(uid string) {
+		if !seen[uid] {
+			seen[uid] = true
+			uids = append(uids, uid)
+		}
+	}
+
+	isNotFolder := "dashboard.is_folder = " + s.db.GetDialect().BooleanStr(false)
+	err = s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, item := range items {
+			var rows []dashboardUID
+			switch item.Type {
+			case "dashboard_by_uid":
+				add(item.Value)
+				continue
+			case "dashboard_by_id":
+				id, err := strconv.ParseInt(item.Value, 10, 64)
+				if err != nil {
+					continue
+				}
+				err = sess.SQL("SELECT dashboard.uid FROM dashboard WHERE dashboard.org_id = ? AND dashboard.id = ? AND "+isNotFolder,
+					query.OrgId, id).Find(&rows)
+				if err != nil {
+					return err
+				}
+			case "dashboard_by_tag":
+				err := sess.SQL(`SELECT dashboard.uid FROM dashboard
+					INNER JOIN dashboard_tag ON dashboard_tag.dashboard_id = dashboard.id
+					WHERE dashboard.org_id = ? AND dashboard_tag.term = ? AND `+isNotFolder+`
+					ORDER BY dashboard.title ASC`,
+					query.OrgId, item.Value).Find(&rows)
+				if err != nil {
+					return err
+				}
+			}
+			for _, row := range rows {
+				add(row.UID)
+			}
+		}
+		return nil
+	})
+	return uids, err
+}
@@ -11,9 +11,14 @@ type FakePlaylistService struct {
 	ExpectedPlaylistDTO   *playlist.PlaylistDTO
 	ExpectedPlaylistItems []playlist.PlaylistItem
 	ExpectedPlaylists     playlist.Playlists
+	ExpectedPlaylistDTOs  []playlist.PlaylistDTO
+	ExpectedKioskToken    string
+	ExpectedDashboardUIDs []string
 	ExpectedError         error
 }
 
+var _ playlist.Service = &FakePlaylistService{}
+
 func NewPlaylistServiveFake() *FakePlaylistService {
 	return &FakePlaylistService{}
 }
@@ -26,10 +31,14 @@ func (f *FakePlaylistService) Update(context.Context, *playlist.UpdatePlaylistCo
 	return f.ExpectedPlaylistDTO, f.ExpectedError
 }
 
-func (f *FakePlaylistService) Get(context.Context, *playlist.GetPlaylistByUidQuery) (*playlist.Playlist, error) {
+func (f *FakePlaylistService) GetWithoutItems(context.Context, *playlist.GetPlaylistByUidQuery) (*playlist.Playlist, error) {
 	return f.ExpectedPlaylist, f.ExpectedError
 }
 
+func (f *FakePlaylistService) Get(context.Context, *playlist.GetPlaylistByUidQuery) (*playlist.PlaylistDTO, error) {
+	return f.ExpectedPlaylistDTO, f.ExpectedError
+}
+
 func (f *FakePlaylistService) GetItems(context.Context, *playlist.GetPlaylistItemsByUidQuery) ([]playlist.PlaylistItem, error) {
 	return f.ExpectedPlaylistItems, f.ExpectedError
 }
@@ -41,3 +50,23 @@ func (f *FakePlaylistService) Search(context.Context, *playlist.GetPlaylistsQuer
 func (f *FakePlaylistService) Delete(ctx context.Context, cmd *playlist.DeletePlaylistCommand) error {
 	return f.ExpectedError
 }
+
+func (f *FakePlaylistService) List(ctx context.Context, orgId int64) ([]playlist.PlaylistDTO, error) {
+	return f.ExpectedPlaylistDTOs, f.ExpectedError
+}
+
+func (f *FakePlaylistService) CreateKioskToken(context.Context, *playlist.CreateKioskTokenCommand) (string, error) {
+	return f.ExpectedKioskToken, f.ExpectedError
+}
+
+func (f *FakePlaylistService) DeleteKioskToken(context.Context, *playlist.DeleteKioskTokenCommand) error {
+	return f.ExpectedError
+}
+
+func (f *FakePlaylistService) GetByKioskToken(context.Context, *playlist.GetPlaylistByKioskTokenQuery) (*playlist.PlaylistDTO, error) {
+	return f.ExpectedPlaylistDTO, f.ExpectedError
+}
+
+func (f *FakePlaylistService) GetDashboardUIDs(context.Context, *playlist.GetPlaylistDashboardUIDsQuery) ([]string, error) {
+	return f.ExpectedDashboardUIDs, f.ExpectedError
+}
@@ -0,0 +1,373 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alert_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// DryRun validates the alerting provisioning files and adds the changes
+// provisioning them would apply to the result, without applying them.
+func DryRun(ctx context.Context, cfg ProvisionerConfig, result *dryrun.Result) error {
+	logger := log.New("provisioning.alerting")
+	cfgReader := newRulesConfigReader(logger)
+	files := cfgReader.dryRun(cfg.Path, result)
+
+	d := &alertingDryRun{cfg: cfg, result: result}
+	steps := []struct {
+		name string
+		fn   func(context.Context, *AlertingFile) error
+	}{
+		{"contact points", d.contactPoints},
+		{"mute times", d.muteTimes},
+		{"text templates", d.templates},
+		{"notification policies", d.policies},
+		{"alert rules", d.rules},
+	}
+	for _, file := range files {
+		for _, step := range steps {
+			if err := step.fn(ctx, file); err != nil {
+				return fmt.Errorf("%s: %w", step.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// dryRun reads the alerting files like readConfig, adding the errors of the
+// invalid files to the result instead of failing.
+func (cr *rulesConfigReader) dryRun(path string, result *dryrun.Result) []*AlertingFile {
+	var alertFiles []*AlertingFile
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		return alertFiles
+	}
+
+	for _, file := range files {
+		if !cr.isYAML(file.Name()) && !cr.isJSON(file.Name()) {
+			continue
+		}
+		filename := filepath.Join(path, file.Name())
+		alertFileV1, err := cr.parseConfig(path, file)
+		if err != nil {
+			result.AddError(dryrun.ResourceAlertRule, filename, fmt.Errorf("failure to parse file %s: %w", file.Name(), err))
+			continue
+		}
+		if alertFileV1 == nil {
+			continue
+		}
+		alertFileV1.Filename = filename
+		alertFile, err := alertFileV1.MapToModel()
+		if err != nil {
+			result.AddError(dryrun.ResourceAlertRule, filename, fmt.Errorf("failure to map file %s: %w", file.Name(), err))
+			continue
+		}
+		alertFiles = append(alertFiles, &alertFile)
+	}
+	return alertFiles
+}
+
+var errNotAFolder = errors.New("got invalid response. expected folder, found dashboard")
+
+type alertingDryRun struct {
+	cfg    ProvisionerConfig
+	result *dryrun.Result
+
+	contactPointsByOrg map[int64][]definitions.EmbeddedContactPoint
+	muteTimesByOrg     map[int64]map[string]definitions.MuteTimeInterval
+	templatesByOrg     map[int64]map[string]definitions.NotificationTemplate
+	folders            map[string]bool
+}
+
+func (d *alertingDryRun) contactPoints(ctx context.Context, file *AlertingFile) error {
+	if d.contactPointsByOrg == nil {
+		d.contactPointsByOrg = map[int64][]definitions.EmbeddedContactPoint{}
+	}
+	getContactPoints := func(orgID int64) ([]definitions.EmbeddedContactPoint, error) {
+		if _, exists := d.contactPointsByOrg[orgID]; !exists {
+			cps, err := d.cfg.ContactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{OrgID: orgID}, nil)
+			if err != nil {
+				return nil, err
+			}
+			d.contactPointsByOrg[orgID] = cps
+		}
+		return d.contactPointsByOrg[orgID], nil
+	}
+
+	for _, contactPointsConfig := range file.ContactPoints {
+		existing, err := getContactPoints(contactPointsConfig.OrgID)
+		if err != nil {
+			return err
+		}
+	outer:
+		for _, contactPoint := range contactPointsConfig.ContactPoints {
+			change := dryrun.Change{OrgID: contactPointsConfig.OrgID, UID: contactPoint.UID, Name: contactPoint.Name, File: file.Filename}
+			for _, fetchedCP := range existing {
+				if fetchedCP.UID != contactPoint.UID {
+					continue
+				}
+				change.Fields = dryrun.Fields(
+					dryrun.Field("name", fetchedCP.Name, contactPoint.Name),
+					dryrun.Field("type", fetchedCP.Type, contactPoint.Type),
+					dryrun.Field("disableResolveMessage", fetchedCP.DisableResolveMessage, contactPoint.DisableResolveMessage),
+					dryrun.Field("settings", fetchedCP.Settings, redactSettings(fetchedCP.Settings, contactPoint.Settings)),
+				)
+				if len(change.Fields) > 0 {
+					change.Action = dryrun.ActionUpdate
+					d.result.Add(dryrun.ResourceContactPoint, change)
+				}
+				continue outer
+			}
+			change.Action = dryrun.ActionCreate
+			d.result.Add(dryrun.ResourceContactPoint, change)
+		}
+	}
+
+	for _, cp := range file.DeleteContactPoints {
+		existing, err := getContactPoints(cp.OrgID)
+		if err != nil {
+			return err
+		}
+		for _, fetchedCP := range existing {
+			if fetchedCP.UID == cp.UID {
+				d.result.Add(dryrun.ResourceContactPoint, dryrun.Change{Action: dryrun.ActionDelete, OrgID: cp.OrgID, UID: cp.UID, Name: fetchedCP.Name, File: file.Filename})
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// redactSettings redacts the provisioned settings that are redacted in the
+// current ones, since secure settings can't be compared.
+func redactSettings(current *simplejson.Json, provisioned *simplejson.Json) *simplejson.Json {
+	if current == nil || provisioned == nil {
+		return provisioned
+	}
+	redacted := simplejson.New()
+	for k, v := range provisioned.MustMap() {
+		if current.Get(k).MustString() == definitions.RedactedValue {
+			v = definitions.RedactedValue
+		}
+		redacted.Set(k, v)
+	}
+	return redacted
+}
+
+func (d *alertingDryRun) muteTimes(ctx context.Context, file *AlertingFile) error {
+	if d.muteTimesByOrg == nil {
+		d.muteTimesByOrg = map[int64]map[string]definitions.MuteTimeInterval{}
+	}
+	getMuteTimes := func(orgID int64) (map[string]definitions.MuteTimeInterval, error) {
+		if _, exists := d.muteTimesByOrg[orgID]; !exists {
+			intervals, err := d.cfg.MuteTimingService.GetMuteTimings(ctx, orgID)
+			if err != nil {
+				return nil, err
+			}
+			d.muteTimesByOrg[orgID] = make(map[string]definitions.MuteTimeInterval, len(intervals))
+			for _, interval := range intervals {
+				d.muteTimesByOrg[orgID][interval.Name] = interval
+			}
+		}
+		return d.muteTimesByOrg[orgID], nil
+	}
+
+	for _, muteTiming := range file.MuteTimes {
+		existing, err := getMuteTimes(muteTiming.OrgID)
+		if err != nil {
+			return err
+		}
+		change := dryrun.Change{OrgID: muteTiming.OrgID, Name: muteTiming.MuteTime.Name, File: file.Filename}
+		current, exists := existing[muteTiming.MuteTime.Name]
+		if !exists {
+			change.Action = dryrun.ActionCreate
+			d.result.Add(dryrun.ResourceMuteTiming, change)
+			continue
+		}
+		change.Fields = dryrun.Fields(dryrun.Field("timeIntervals", current.TimeIntervals, muteTiming.MuteTime.TimeIntervals))
+		if len(change.Fields) > 0 {
+			change.Action = dryrun.ActionUpdate
+			d.result.Add(dryrun.ResourceMuteTiming, change)
+		}
+	}
+
+	for _, deleteMuteTime := range file.DeleteMuteTimes {
+		existing, err := getMuteTimes(deleteMuteTime.OrgID)
+		if err != nil {
+			return err
+		}
+		if _, exists := existing[deleteMuteTime.Name]; exists {
+			d.result.Add(dryrun.ResourceMuteTiming, dryrun.Change{Action: dryrun.ActionDelete, OrgID: deleteMuteTime.OrgID, Name: deleteMuteTime.Name, File: file.Filename})
+		}
+	}
+	return nil
+}
+
+func (d *alertingDryRun) templates(ctx context.Context, file *AlertingFile) error {
+	if d.templatesByOrg == nil {
+		d.templatesByOrg = map[int64]map[string]definitions.NotificationTemplate{}
+	}
+	getTemplates := func(orgID int64) (map[string]definitions.NotificationTemplate, error) {
+		if _, exists := d.templatesByOrg[orgID]; !exists {
+			templates, err := d.cfg.TemplateService.GetTemplates(ctx, orgID)
+			if err != nil {
+				return nil, err
+			}
+			d.templatesByOrg[orgID] = make(map[string]definitions.NotificationTemplate, len(templates))
+			for _, template := range templates {
+				d.templatesByOrg[orgID][template.Name] = template
+			}
+		}
+		return d.templatesByOrg[orgID], nil
+	}
+
+	for _, template := range file.Templates {
+		existing, err := getTemplates(template.OrgID)
+		if err != nil {
+			return err
+		}
+		change := dryrun.Change{OrgID: template.OrgID, Name: template.Data.Name, File: file.Filename}
+		current, exists := existing[template.Data.Name]
+		if !exists {
+			change.Action = dryrun.ActionCreate
+			d.result.Add(dryrun.ResourceTemplate, change)
+			continue
+		}
+		change.Fields = dryrun.Fields(dryrun.Field("template", current.Template, template.Data.Template))
+		if len(change.Fields) > 0 {
+			change.Action = dryrun.ActionUpdate
+			d.result.Add(dryrun.ResourceTemplate, change)
+		}
+	}
+
+	for _, deleteTemplate := range file.DeleteTemplates {
+		existing, err := getTemplates(deleteTemplate.OrgID)
+		if err != nil {
+			return err
+		}
+		if _, exists := existing[deleteTemplate.Name]; exists {
+			d.result.Add(dryrun.ResourceTemplate, dryrun.Change{Action: dryrun.ActionDelete, OrgID: deleteTemplate.OrgID, Name: deleteTemplate.Name, File: file.Filename})
+		}
+	}
+	return nil
+}
+
+// policies reports the provisioned policy trees that differ from the current
+// ones, and the reset ones. The policy tree of an organization always exists.
+func (d *alertingDryRun) policies(ctx context.Context, file *AlertingFile) error {
+	for _, np := range file.Policies {
+		current, err := d.cfg.NotificiationPolicyService.GetPolicyTree(ctx, np.OrgID)
+		if err != nil {
+			return err
+		}
+		current.Provenance = ""
+		provisioned := np.Policy
+		provisioned.Provenance = ""
+		if fields := dryrun.Fields(dryrun.Field("policy", current, provisioned)); len(fields) > 0 {
+			d.result.Add(dryrun.ResourceNotificationPolicy, dryrun.Change{Action: dryrun.ActionUpdate, OrgID: np.OrgID, Name: "policy tree", File: file.Filename, Fields: fields})
+		}
+	}
+	for _, orgID := range file.ResetPolicies {
+		d.result.Add(dryrun.ResourceNotificationPolicy, dryrun.Change{Action: dryrun.ActionUpdate, OrgID: int64(orgID), Name: "policy tree", File: file.Filename, Fields: []string{"reset"}})
+	}
+	return nil
+}
+
+func (d *alertingDryRun) rules(ctx context.Context, file *AlertingFile) error {
+	for _, group := range file.Groups {
+		folderUID, err := d.folderUID(ctx, group.FolderTitle, group.OrgID, file.Filename)
+		if err != nil {
+			if errors.Is(err, errNotAFolder) {
+				d.result.AddError(dryrun.ResourceAlertRule, file.Filename, fmt.Errorf("rule group %q: %w", group.Title, err))
+				continue
+			}
+			return err
+		}
+
+		for _, rule := range group.Rules {
+			change := dryrun.Change{OrgID: group.OrgID, UID: rule.UID, Name: rule.Title, File: file.Filename}
+			current, _, err := d.cfg.RuleService.GetAlertRule(ctx, group.OrgID, rule.UID)
+			if err != nil {
+				if !errors.Is(err, alert_models.ErrAlertRuleNotFound) {
+					return err
+				}
+				change.Action = dryrun.ActionCreate
+				d.result.Add(dryrun.ResourceAlertRule, change)
+				continue
+			}
+
+			change.Fields = dryrun.Fields(
+				dryrun.Field("title", current.Title, rule.Title),
+				dryrun.Field("folderUID", current.NamespaceUID, folderUID),
+				dryrun.Field("ruleGroup", current.RuleGroup, group.Title),
+				dryrun.Field("interval", current.IntervalSeconds, group.Interval),
+				dryrun.Field("condition", current.Condition, rule.Condition),
+				dryrun.Field("data", current.Data, rule.Data),
+				dryrun.Field("for", current.For, rule.For),
+				dryrun.Field("annotations", current.Annotations, rule.Annotations),
+				dryrun.Field("labels", current.Labels, rule.Labels),
+				dryrun.Field("noDataState", current.NoDataState, rule.NoDataState),
+				dryrun.Field("execErrState", current.ExecErrState, rule.ExecErrState),
+				dryrun.Field("isPaused", current.IsPaused, rule.IsPaused),
+				dryrun.Field("notificationSettings", current.NotificationSettings, rule.NotificationSettings),
+			)
+			if len(change.Fields) > 0 {
+				change.Action = dryrun.ActionUpdate
+				d.result.Add(dryrun.ResourceAlertRule, change)
+			}
+		}
+	}
+
+	for _, deleteRule := range file.DeleteRules {
+		current, _, err := d.cfg.RuleService.GetAlertRule(ctx, deleteRule.OrgID, deleteRule.UID)
+		if err != nil {
+			if errors.Is(err, alert_models.ErrAlertRuleNotFound) {
+				continue
+			}
+			return err
+		}
+		d.result.Add(dryrun.ResourceAlertRule, dryrun.Change{Action: dryrun.ActionDelete, OrgID: deleteRule.OrgID, UID: deleteRule.UID, Name: current.Title, File: file.Filename})
+	}
+	return nil
+}
+
+// folderUID returns the UID of the folder of a rule group, and adds the folder
+// to the result when the provisioner would create it.
+func (d *alertingDryRun) folderUID(ctx context.Context, folderName string, orgID int64, filename string) (string, error) {
+	cmdResult, err := d.cfg.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{
+		Title:    &folderName,
+		FolderID: util.Pointer(int64(0)), // nolint:staticcheck
+		OrgID:    orgID,
+	})
+	if err != nil {
+		if !errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return "", err
+		}
+		if d.folders == nil {
+			d.folders = map[string]bool{}
+		}
+		key := fmt.Sprintf("%d/%s", orgID, folderName)
+		if !d.folders[key] {
+			d.folders[key] = true
+			d.result.Add(dryrun.ResourceFolder, dryrun.Change{Action: dryrun.ActionCreate, OrgID: orgID, Name: folderName, File: filename})
+		}
+		return "", nil
+	}
+
+	if !cmdResult.IsFolder {
+		return "", errNotAFolder
+	}
+	return cmdResult.UID, nil
+}
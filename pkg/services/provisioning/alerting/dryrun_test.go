@@ -0,0 +1,240 @@
+package alerting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	secrets_fakes "github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const dryRunAlertmanagerConfig = `{
+	"template_files": {
+		"existing_template": "old",
+		"old_template": "old"
+	},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "grafana-default-email",
+			"group_by": ["grafana_folder", "alertname"]
+		},
+		"receivers": [{
+			"name": "grafana-default-email",
+			"grafana_managed_receiver_configs": [{
+				"uid": "default",
+				"name": "email receiver",
+				"type": "email",
+				"settings": {
+					"addresses": "<example@email.com>"
+				}
+			}]
+		}],
+		"mute_time_intervals": [{
+			"name": "existing_mute",
+			"time_intervals": [{"times": [{"start_time": "06:00", "end_time": "12:00"}]}]
+		}, {
+			"name": "old_mute",
+			"time_intervals": [{"times": [{"start_time": "06:00", "end_time": "12:00"}]}]
+		}]
+	}
+}`
+
+const dryRunFile = `apiVersion: 1
+contactPoints:
+  - orgId: 1
+    name: cp
+    receivers:
+      - uid: existing_cp
+        type: webhook
+        settings:
+          url: http://new
+      - uid: new_cp
+        type: webhook
+        settings:
+          url: http://new
+deleteContactPoints:
+  - orgId: 1
+    uid: old_cp
+muteTimes:
+  - orgId: 1
+    name: existing_mute
+    time_intervals:
+      - times:
+          - start_time: '06:00'
+            end_time: '23:59'
+  - orgId: 1
+    name: new_mute
+    time_intervals:
+      - times:
+          - start_time: '06:00'
+            end_time: '23:59'
+deleteMuteTimes:
+  - orgId: 1
+    name: old_mute
+templates:
+  - orgId: 1
+    name: existing_template
+    template: new
+  - orgId: 1
+    name: new_template
+    template: new
+deleteTemplates:
+  - orgId: 1
+    name: old_template
+policies:
+  - orgId: 1
+    receiver: grafana-default-email
+    group_by:
+      - alertname
+groups:
+  - orgId: 1
+    name: my_group
+    folder: my_folder
+    interval: 1m
+    rules:
+      - uid: existing_rule
+        title: renamed rule
+        condition: A
+        for: 1m
+        data:
+          - refId: A
+            datasourceUid: __expr__
+            model:
+              type: math
+              expression: "1"
+      - uid: new_rule
+        title: new rule
+        condition: A
+        for: 1m
+        data:
+          - refId: A
+            datasourceUid: __expr__
+            model:
+              type: math
+              expression: "1"
+deleteRules:
+  - orgId: 1
+    uid: old_rule
+`
+
+func TestIntegrationDryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+
+	ruleStore := store.DBstore{
+		Logger:   logger,
+		SQLStore: db.InitTestDB(t),
+		Cfg:      setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second},
+	}
+	rule := func(uid, title string) models.AlertRule {
+		return models.AlertRule{
+			OrgID:           1,
+			UID:             uid,
+			Title:           title,
+			Condition:       "A",
+			Data:            []models.AlertQuery{models.GenerateAlertQuery()},
+			IntervalSeconds: 60,
+			NamespaceUID:    "folder-uid",
+			RuleGroup:       "my_group",
+			NoDataState:     models.NoData,
+			ExecErrState:    models.AlertingErrState,
+		}
+	}
+	_, err := ruleStore.InsertAlertRules(ctx, []models.AlertRule{rule("existing_rule", "existing rule"), rule("old_rule", "old rule")})
+	require.NoError(t, err)
+
+	configStore := fakes.NewFakeAlertmanagerConfigStore(dryRunAlertmanagerConfig)
+	provenanceStore := fakes.NewFakeProvisioningStore()
+	xact := &provisioning.NopTransactionManager{}
+	receivers := fakes.NewFakeReceiverService()
+	receivers.GetReceiversFn = func(context.Context, models.GetReceiversQuery, identity.Requester) ([]definitions.GettableApiReceiver, error) {
+		return []definitions.GettableApiReceiver{{
+			GettableGrafanaReceivers: definitions.GettableGrafanaReceivers{
+				GrafanaManagedReceivers: []*definitions.GettableGrafanaReceiver{
+					{UID: "existing_cp", Name: "cp", Type: "webhook", Settings: definitions.RawMessage(`{"url": "http://old"}`)},
+					{UID: "old_cp", Name: "old", Type: "webhook", Settings: definitions.RawMessage(`{"url": "http://old"}`)},
+				},
+			},
+		}}, nil
+	}
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{UID: "folder-uid", Title: "my_folder", IsFolder: true}, nil)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "alerting.yaml"), []byte(dryRunFile), 0600))
+
+	result := dryrun.NewResult()
+	err = DryRun(ctx, ProvisionerConfig{
+		Path:                       dir,
+		DashboardService:           dashboardService,
+		RuleService:                provisioning.NewAlertRuleService(ruleStore, provenanceStore, dashboardService, nil, xact, 60, 10, 100, logger, &provisioning.NotificationSettingsValidatorProviderFake{}),
+		ContactPointService:        provisioning.NewContactPointService(configStore, secrets_fakes.NewFakeSecretsService(), provenanceStore, xact, receivers, logger, ruleStore),
+		NotificiationPolicyService: provisioning.NewNotificationPolicyService(configStore, provenanceStore, xact, setting.UnifiedAlertingSettings{}, logger),
+		MuteTimingService:          provisioning.NewMuteTimingService(configStore, provenanceStore, xact, logger),
+		TemplateService:            provisioning.NewTemplateService(configStore, provenanceStore, xact, logger),
+	}, result)
+	require.NoError(t, err)
+	require.True(t, result.Valid, result.Errors)
+
+	t.Run("should report created, updated and deleted resources", func(t *testing.T) {
+		for _, resource := range []dryrun.ResourceType{
+			dryrun.ResourceContactPoint,
+			dryrun.ResourceMuteTiming,
+			dryrun.ResourceTemplate,
+			dryrun.ResourceAlertRule,
+		} {
+			require.Equal(t, dryrun.Summary{Create: 1, Update: 1, Delete: 1}, result.Summary[resource], resource)
+		}
+		require.Equal(t, dryrun.Summary{Update: 1}, result.Summary[dryrun.ResourceNotificationPolicy])
+		require.Empty(t, result.Changes[dryrun.ResourceFolder])
+
+		updated := map[dryrun.ResourceType]dryrun.Change{}
+		for resource, changes := range result.Changes {
+			for _, change := range changes {
+				if change.Action == dryrun.ActionUpdate {
+					updated[resource] = change
+				}
+			}
+		}
+		require.Equal(t, []string{"settings"}, updated[dryrun.ResourceContactPoint].Fields)
+		require.Equal(t, []string{"timeIntervals"}, updated[dryrun.ResourceMuteTiming].Fields)
+		require.Equal(t, []string{"template"}, updated[dryrun.ResourceTemplate].Fields)
+		require.Equal(t, []string{"policy"}, updated[dryrun.ResourceNotificationPolicy].Fields)
+		require.Equal(t, "existing_rule", updated[dryrun.ResourceAlertRule].UID)
+		require.Contains(t, updated[dryrun.ResourceAlertRule].Fields, "title")
+	})
+
+	t.Run("should not change the database", func(t *testing.T) {
+		rules, err := ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: 1})
+		require.NoError(t, err)
+		titles := map[string]string{}
+		for _, r := range rules {
+			titles[r.UID] = r.Title
+		}
+		require.Equal(t, map[string]string{"existing_rule": "existing rule", "old_rule": "old rule"}, titles)
+
+		require.Nil(t, configStore.LastSaveCommand)
+		require.Equal(t, dryRunAlertmanagerConfig, configStore.Config.AlertmanagerConfiguration)
+		require.Empty(t, provenanceStore.Records)
+	})
+}
@@ -105,7 +105,7 @@ func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
 		}
 
 		if dashboard.UpdateIntervalSeconds == 0 {
-			dashboard.UpdateIntervalSeconds = 10
+			dashboard.UpdateIntervalSeconds = defaultUpdateIntervalSeconds(dashboard.Type)
 		}
 		if len(dashboard.FolderUID) > 0 {
 			uidUsage[dashboard.FolderUID]++
@@ -120,3 +120,12 @@ func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
 
 	return dashboards, nil
 }
+
+// defaultUpdateIntervalSeconds returns the poll interval of the providers that
+// don't set one.
+func defaultUpdateIntervalSeconds(providerType string) int64 {
+	if providerType == "git" {
+		return defaultGitUpdateIntervalSeconds
+	}
+	return 10
+}
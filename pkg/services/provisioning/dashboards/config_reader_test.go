@@ -18,6 +18,7 @@ var (
 	oldVersion            = "./testdata/test-configs/version-0"
 	brokenConfigs         = "./testdata/test-configs/broken-configs"
 	appliedDefaults       = "./testdata/test-configs/applied-defaults"
+	gitDefaults           = "./testdata/test-configs/git-defaults"
 )
 
 func TestDashboardsAsConfig(t *testing.T) {
@@ -45,6 +46,15 @@ func TestDashboardsAsConfig(t *testing.T) {
 			require.Equal(t, int64(10), cfg[0].UpdateIntervalSeconds)
 		})
 
+		t.Run("git providers should poll every minute by default", func(t *testing.T) {
+			cfgProvider := configReader{path: gitDefaults, log: logger, orgService: orgFake}
+			cfg, err := cfgProvider.readConfig(context.Background())
+			require.NoError(t, err)
+
+			require.Equal(t, "git", cfg[0].Type)
+			require.Equal(t, int64(60), cfg[0].UpdateIntervalSeconds)
+		})
+
 		t.Run("Can read config file version 1 format", func(t *testing.T) {
 			t.Setenv("TEST_VAR", "general")
 			cfgProvider := configReader{path: simpleDashboardConfig, log: logger, orgService: orgFake}
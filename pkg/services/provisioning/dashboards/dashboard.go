@@ -21,6 +21,7 @@ type DashboardProvisioner interface {
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
+	HandleGitWebhook(name string, payload []byte, signature string, token string) error
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
@@ -130,6 +131,22 @@ func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// HandleGitWebhook verifies the webhook of the git provider with the specified
+// name, and triggers the sync of its repository.
+func (provider *Provisioner) HandleGitWebhook(name string, payload []byte, signature string, token string) error {
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name != name || reader.git == nil {
+			continue
+		}
+		if err := reader.git.verifyWebhook(payload, signature, token); err != nil {
+			return err
+		}
+		reader.git.triggerSync()
+		return nil
+	}
+	return ErrGitProviderNotFound
+}
+
 func getFileReaders(
 	configs []*config,
 	logger log.Logger,
@@ -153,6 +170,18 @@ func getFileReaders(
 				return nil, fmt.Errorf("failed to create file reader for config %v: %w", config.Name, err)
 			}
 			readers = append(readers, fileReader)
+		case "git":
+			gitReader, err := NewDashboardGitReader(
+				config,
+				logger.New("type", config.Type, "name", config.Name),
+				service,
+				store,
+				folderService,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create git reader for config %v: %w", config.Name, err)
+			}
+			readers = append(readers, gitReader)
 		default:
 			return nil, fmt.Errorf("type %s is not supported", config.Type)
 		}
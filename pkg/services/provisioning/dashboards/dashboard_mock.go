@@ -8,6 +8,7 @@ type calls struct {
 	PollChanges                 []any
 	GetProvisionerResolvedPath  []any
 	GetAllowUIUpdatesFromConfig []any
+	HandleGitWebhook            []any
 }
 
 // ProvisionerMock is a mock implementation of `Provisioner`
@@ -17,6 +18,7 @@ type ProvisionerMock struct {
 	PollChangesFunc                 func(ctx context.Context)
 	GetProvisionerResolvedPathFunc  func(name string) string
 	GetAllowUIUpdatesFromConfigFunc func(name string) bool
+	HandleGitWebhookFunc            func(name string, payload []byte, signature string, token string) error
 }
 
 // NewDashboardProvisionerMock returns a new dashboardprovisionermock
@@ -67,3 +69,12 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
+
+// HandleGitWebhook is a mock implementation of `Provisioner.HandleGitWebhook`
+func (dpm *ProvisionerMock) HandleGitWebhook(name string, payload []byte, signature string, token string) error {
+	dpm.Calls.HandleGitWebhook = append(dpm.Calls.HandleGitWebhook, name)
+	if dpm.HandleGitWebhookFunc != nil {
+		return dpm.HandleGitWebhookFunc(name, payload, signature, token)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// DryRun validates the dashboard providers configured in a directory and the
+// dashboard files they read, and adds the changes provisioning them would apply
+// to the result, without applying them. The dashboards of removed providers
+// are not reported.
+func DryRun(ctx context.Context, configDirectory string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore, folderService folder.Service, result *dryrun.Result) error {
+	logger := log.New("provisioning.dashboard")
+	cfgReader := &configReader{path: configDirectory, log: logger, orgService: orgService}
+	configs := cfgReader.dryRun(ctx, result)
+
+	for _, cfg := range configs {
+		readers, err := getFileReaders([]*config{cfg}, logger, provisioner, dashboardStore, folderService)
+		if err != nil {
+			result.AddError(dryrun.ResourceDashboard, configDirectory, err)
+			continue
+		}
+		for _, reader := range readers {
+			if err := reader.dryRun(ctx, result); err != nil {
+				return fmt.Errorf("failed to dry-run config %v: %w", reader.Cfg.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// dryRun reads the dashboard providers like readConfig, adding the errors of
+// the invalid config files to the result instead of failing.
+func (cr *configReader) dryRun(ctx context.Context, result *dryrun.Result) []*config {
+	var configs []*config
+
+	files, err := os.ReadDir(cr.path)
+	if err != nil {
+		return configs
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(cr.path, file.Name())
+		parsedDashboards, err := cr.parseConfigs(file)
+		if err != nil {
+			result.AddError(dryrun.ResourceDashboard, filename, err)
+			continue
+		}
+
+		for _, dashboard := range parsedDashboards {
+			if dashboard.OrgID == 0 {
+				dashboard.OrgID = 1
+			}
+			if err := utils.CheckOrgExists(ctx, cr.orgService, dashboard.OrgID); err != nil {
+				result.AddError(dryrun.ResourceDashboard, filename, fmt.Errorf("failed to provision dashboards with %q reader: %w", dashboard.Name, err))
+				continue
+			}
+			if dashboard.Type == "" {
+				dashboard.Type = "file"
+			}
+			if dashboard.UpdateIntervalSeconds == 0 {
+				dashboard.UpdateIntervalSeconds = defaultUpdateIntervalSeconds(dashboard.Type)
+			}
+			configs = append(configs, dashboard)
+		}
+	}
+
+	return configs
+}
+
+// dryRun walks the file system like walkDisk, adding the changes to the result
+// instead of applying them. Git providers are read from their local clone as
+// it is, the remote branch isn't fetched.
+func (fr *FileReader) dryRun(ctx context.Context, result *dryrun.Result) error {
+	resolvedPath := fr.resolvedPath()
+	if _, err := os.Stat(resolvedPath); err != nil {
+		if os.IsNotExist(err) {
+			// the provisioner doesn't fail for a missing folder either
+			return nil
+		}
+		return err
+	}
+
+	provisionedDashboardRefs, err := getProvisionedDashboardsByPath(ctx, fr.dashboardProvisioningService, fr.Cfg.Name)
+	if err != nil {
+		return err
+	}
+
+	filesFoundOnDisk := map[string]os.FileInfo{}
+	if err := filepath.Walk(resolvedPath, createWalkFn(filesFoundOnDisk)); err != nil {
+		return err
+	}
+
+	// dashboards missing on disk are only unprovisioned when deletion is disabled
+	if !fr.Cfg.DisableDeletion {
+		if err := fr.dryRunMissingDashboardFiles(ctx, provisionedDashboardRefs, filesFoundOnDisk, result); err != nil {
+			return err
+		}
+	}
+
+	paths := make([]string, 0, len(filesFoundOnDisk))
+	for path := range filesFoundOnDisk {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// the folders are created once, the dashboards of a folder that can't be
+	// created are skipped
+	folderErrors := map[string]error{}
+	for _, path := range paths {
+		folderName := fr.Cfg.Folder
+		if fr.FoldersFromFilesStructure {
+			folderName = ""
+			if dashboardsFolder := filepath.Dir(path); dashboardsFolder != resolvedPath {
+				folderName = filepath.Base(dashboardsFolder)
+			}
+		}
+		if folderName != "" {
+			err, checked := folderErrors[folderName]
+			if !checked {
+				err = fr.dryRunFolder(ctx, folderName, result)
+				folderErrors[folderName] = err
+			}
+			if err != nil {
+				result.AddError(dryrun.ResourceFolder, path, err)
+				continue
+			}
+		}
+
+		resolvedFileInfo, err := resolveSymlink(filesFoundOnDisk[path], path)
+		if err != nil {
+			result.AddError(dryrun.ResourceDashboard, path, err)
+			continue
+		}
+		jsonFile, err := fr.readDashboardFromFile(path, resolvedFileInfo.ModTime(), 0, "")
+		if err != nil {
+			result.AddError(dryrun.ResourceDashboard, path, err)
+			continue
+		}
+
+		change := dryrun.Change{
+			OrgID: fr.Cfg.OrgID,
+			UID:   jsonFile.dashboard.Dashboard.UID,
+			Name:  jsonFile.dashboard.Dashboard.Title,
+			File:  path,
+		}
+		provisionedData, alreadyProvisioned := provisionedDashboardRefs[path]
+		switch {
+		case !alreadyProvisioned:
+			change.Action = dryrun.ActionCreate
+		case provisionedData.CheckSum != jsonFile.checkSum:
+			change.Action = dryrun.ActionUpdate
+		default:
+			continue
+		}
+		result.Add(dryrun.ResourceDashboard, change)
+	}
+
+	return nil
+}
+
+func (fr *FileReader) dryRunMissingDashboardFiles(ctx context.Context, provisionedDashboardRefs map[string]*dashboards.DashboardProvisioning,
+	filesFoundOnDisk map[string]os.FileInfo, result *dryrun.Result) error {
+	paths := make([]string, 0, len(provisionedDashboardRefs))
+	for path := range provisionedDashboardRefs {
+		if _, existsOnDisk := filesFoundOnDisk[path]; !existsOnDisk {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		change := dryrun.Change{Action: dryrun.ActionDelete, OrgID: fr.Cfg.OrgID, Name: path, File: path}
+		dash, err := fr.dashboardStore.GetDashboard(ctx, &dashboards.GetDashboardQuery{
+			ID:    provisionedDashboardRefs[path].DashboardID,
+			OrgID: fr.Cfg.OrgID,
+		})
+		if err != nil {
+			if !errors.Is(err, dashboards.ErrDashboardNotFound) {
+				return err
+			}
+			continue
+		}
+		change.UID = dash.UID
+		change.Name = dash.Title
+		result.Add(dryrun.ResourceDashboard, change)
+	}
+	return nil
+}
+
+// dryRunFolder adds the folder to the result when getOrCreateFolder would
+// create it.
+func (fr *FileReader) dryRunFolder(ctx context.Context, folderName string, result *dryrun.Result) error {
+	query := &dashboards.GetDashboardQuery{
+		Title:    &folderName,
+		FolderID: util.Pointer(int64(0)), // nolint:staticcheck
+		OrgID:    fr.Cfg.OrgID,
+	}
+	existing, err := fr.dashboardStore.GetDashboard(ctx, query)
+	if err != nil {
+		if !errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return err
+		}
+		if fr.Cfg.FolderUID == accesscontrol.GeneralFolderUID {
+			return dashboards.ErrFolderInvalidUID
+		}
+		result.Add(dryrun.ResourceFolder, dryrun.Change{
+			Action: dryrun.ActionCreate,
+			OrgID:  fr.Cfg.OrgID,
+			UID:    fr.Cfg.FolderUID,
+			Name:   folderName,
+		})
+		return nil
+	}
+
+	if !existing.IsFolder {
+		return fmt.Errorf("got invalid response. expected folder, found dashboard")
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package dashboards
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+func TestFileReaderDryRun(t *testing.T) {
+	path, err := filepath.Abs(oneDashboard)
+	require.NoError(t, err)
+	path, err = filepath.EvalSymlinks(path)
+	require.NoError(t, err)
+	dashboardFile := filepath.Join(path, "dashboard1.json")
+
+	setup := func(t *testing.T, provisioned []*dashboards.DashboardProvisioning, store utils.DashboardStore) *FileReader {
+		t.Helper()
+		service := &dashboards.FakeDashboardProvisioning{}
+		service.On("GetProvisionedDashboardData", mock.Anything, configName).Return(provisioned, nil).Once()
+		t.Cleanup(func() {
+			// only the provisioned dashboards are read, nothing is written
+			service.AssertExpectations(t)
+			service.AssertNotCalled(t, "SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+			service.AssertNotCalled(t, "SaveFolderForProvisionedDashboards", mock.Anything, mock.Anything)
+			service.AssertNotCalled(t, "UnprovisionDashboard", mock.Anything, mock.Anything)
+			service.AssertNotCalled(t, "DeleteProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		cfg := &config{Name: configName, Type: "file", OrgID: 1, Folder: "Team A", Options: map[string]any{"path": oneDashboard}}
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, store, nil)
+		require.NoError(t, err)
+		reader.dashboardProvisioningService = service
+		return reader
+	}
+
+	t.Run("should report new dashboards and folders", func(t *testing.T) {
+		reader := setup(t, nil, &fakeDashboardStore{})
+
+		result := dryrun.NewResult()
+		require.NoError(t, reader.dryRun(context.Background(), result))
+
+		require.True(t, result.Valid)
+		require.Equal(t, []dryrun.Change{{Action: dryrun.ActionCreate, OrgID: 1, Name: "Team A"}}, result.Changes[dryrun.ResourceFolder])
+		require.Equal(t, []dryrun.Change{{Action: dryrun.ActionCreate, OrgID: 1, Name: "Grafana", File: dashboardFile}}, result.Changes[dryrun.ResourceDashboard])
+	})
+
+	t.Run("should report changed dashboards", func(t *testing.T) {
+		reader := setup(t, []*dashboards.DashboardProvisioning{
+			{DashboardID: 1, Name: configName, ExternalID: dashboardFile, CheckSum: "outdated"},
+		}, &dryRunDashboardStore{folder: &dashboards.Dashboard{ID: 2, UID: "team-a", Title: "Team A", IsFolder: true}})
+
+		result := dryrun.NewResult()
+		require.NoError(t, reader.dryRun(context.Background(), result))
+
+		require.Empty(t, result.Changes[dryrun.ResourceFolder])
+		require.Equal(t, dryrun.Summary{Update: 1}, result.Summary[dryrun.ResourceDashboard])
+		require.Equal(t, dashboardFile, result.Changes[dryrun.ResourceDashboard][0].File)
+	})
+
+	t.Run("should report dashboards missing on disk as deleted", func(t *testing.T) {
+		store := &dryRunDashboardStore{
+			folder:    &dashboards.Dashboard{ID: 2, UID: "team-a", Title: "Team A", IsFolder: true},
+			dashboard: &dashboards.Dashboard{ID: 3, UID: "removed", Title: "Removed"},
+		}
+		reader := setup(t, []*dashboards.DashboardProvisioning{
+			{DashboardID: 3, Name: configName, ExternalID: filepath.Join(path, "removed.json")},
+		}, store)
+
+		result := dryrun.NewResult()
+		require.NoError(t, reader.dryRun(context.Background(), result))
+
+		require.Equal(t, dryrun.Summary{Create: 1, Delete: 1}, result.Summary[dryrun.ResourceDashboard])
+		require.Contains(t, result.Changes[dryrun.ResourceDashboard], dryrun.Change{
+			Action: dryrun.ActionDelete,
+			OrgID:  1,
+			UID:    "removed",
+			Name:   "Removed",
+			File:   filepath.Join(path, "removed.json"),
+		})
+	})
+
+	t.Run("should not report deleted dashboards when deletion is disabled", func(t *testing.T) {
+		reader := setup(t, []*dashboards.DashboardProvisioning{
+			{DashboardID: 3, Name: configName, ExternalID: filepath.Join(path, "removed.json")},
+		}, &dryRunDashboardStore{dashboard: &dashboards.Dashboard{ID: 3, UID: "removed", Title: "Removed"}})
+		reader.Cfg.DisableDeletion = true
+
+		result := dryrun.NewResult()
+		require.NoError(t, reader.dryRun(context.Background(), result))
+
+		require.Equal(t, dryrun.Summary{Create: 1}, result.Summary[dryrun.ResourceDashboard])
+	})
+}
+
+// dryRunDashboardStore returns the folder when it is looked up by title and
+// the dashboard when it is looked up by id.
+type dryRunDashboardStore struct {
+	folder    *dashboards.Dashboard
+	dashboard *dashboards.Dashboard
+}
+
+func (s *dryRunDashboardStore) GetDashboard(_ context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+	if query.Title != nil && s.folder != nil && *query.Title == s.folder.Title {
+		return s.folder, nil
+	}
+	if query.ID != 0 && s.dashboard != nil && query.ID == s.dashboard.ID {
+		return s.dashboard, nil
+	}
+	return nil, dashboards.ErrDashboardNotFound
+}
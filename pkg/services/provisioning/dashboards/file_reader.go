@@ -41,6 +41,9 @@ type FileReader struct {
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
 	dbWriteAccessRestricted bool
+
+	// git is the repository the dashboards are read from, for git providers
+	git *gitRepository
 }
 
 // NewDashboardFileReader returns a new filereader based on `config`
@@ -75,14 +78,23 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 }
 
 // pollChanges periodically runs walkDisk based on interval specified in the config.
+// Git providers also run it when a webhook is received.
 func (fr *FileReader) pollChanges(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
+	var trigger chan struct{}
+	if fr.git != nil {
+		trigger = fr.git.trigger
+	}
 	for {
 		select {
 		case <-ticker.C:
 			if err := fr.walkDisk(ctx); err != nil {
 				fr.log.Error("failed to search for dashboards", "error", err)
 			}
+		case <-trigger:
+			if err := fr.walkDisk(ctx); err != nil {
+				fr.log.Error("failed to search for dashboards", "error", err)
+			}
 		case <-ctx.Done():
 			return
 		}
@@ -92,6 +104,8 @@ func (fr *FileReader) pollChanges(ctx context.Context) {
 // walkDisk traverses the file system for the defined path, reading dashboard definition files,
 // and applies any change to the database.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
+	fr.syncGitRepository(ctx)
+
 	fr.log.Debug("Start walking disk", "path", fr.Path)
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
@@ -302,6 +316,7 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 			Name:       fr.Cfg.Name,
 			Updated:    resolvedFileInfo.ModTime().Unix(),
 			CheckSum:   jsonFile.checkSum,
+			CommitSHA:  fr.commitSHA(),
 		}
 		_, err := fr.dashboardProvisioningService.SaveProvisionedDashboard(ctx, dash, dp)
 		if err != nil {
@@ -491,6 +506,25 @@ func (fr *FileReader) resolvedPath() string {
 	return path
 }
 
+// syncGitRepository updates the local clone of git providers. The dashboards of
+// the last synced commit are still provisioned when the sync fails.
+func (fr *FileReader) syncGitRepository(ctx context.Context) {
+	if fr.git == nil {
+		return
+	}
+	if err := fr.git.sync(ctx); err != nil {
+		fr.log.Error("Failed to sync git repository", "url", fr.git.url, "branch", fr.git.branch, "error", err)
+	}
+}
+
+// commitSHA returns the commit checked out by git providers.
+func (fr *FileReader) commitSHA() string {
+	if fr.git == nil {
+		return ""
+	}
+	return fr.git.getCommit()
+}
+
 func (fr *FileReader) getUsageTracker() *usageTracker {
 	fr.mux.RLock()
 	defer fr.mux.RUnlock()
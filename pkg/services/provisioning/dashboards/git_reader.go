@@ -0,0 +1,325 @@
+package dashboards
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+const (
+	defaultGitBranch = "main"
+	// defaultGitUpdateIntervalSeconds is longer than the default interval of
+	// file providers, since every poll of a git provider fetches the remote.
+	defaultGitUpdateIntervalSeconds = 60
+)
+
+var (
+	// ErrGitProviderNotFound is returned when no git provider has the name of a webhook.
+	ErrGitProviderNotFound = errors.New("git dashboard provider not found")
+	// ErrGitWebhookDisabled is returned for the webhooks of a git provider without webhook secret.
+	ErrGitWebhookDisabled = errors.New("git dashboard provider has no webhook secret")
+	// ErrGitWebhookInvalidSignature is returned when the signature of a webhook doesn't match its payload.
+	ErrGitWebhookInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// gitLocks serializes the git commands run on the same local clone, since the
+// readers of a provider are recreated on every provisioning reload.
+var gitLocks sync.Map
+
+// gitRepository keeps the local clone of the git repository of a dashboard
+// provider in sync with the remote branch.
+type gitRepository struct {
+	url           string
+	branch        string
+	localPath     string
+	username      string
+	password      string
+	sshPrivateKey string
+	sshKnownHosts string
+	webhookSecret string
+	log           log.Logger
+
+	// trigger wakes the polling of the provider up when a webhook is received
+	trigger chan struct{}
+
+	mux    sync.RWMutex
+	commit string
+}
+
+// NewDashboardGitReader returns a new reader provisioning the dashboards of a
+// git repository. The repository is cloned into a local directory, and the
+// dashboards are read from there like for a file provider.
+func NewDashboardGitReader(cfg *config, log log.Logger, service dashboards.DashboardProvisioningService,
+	dashboardStore utils.DashboardStore, folderService folder.Service) (*FileReader, error) {
+	repoURL, _ := cfg.Options["url"].(string)
+	if repoURL == "" {
+		return nil, fmt.Errorf("failed to load dashboards, url param is not a string")
+	}
+	if err := validateGitURL(repoURL); err != nil {
+		return nil, err
+	}
+
+	branch, _ := cfg.Options["branch"].(string)
+	if branch == "" {
+		branch = defaultGitBranch
+	}
+	if strings.HasPrefix(branch, "-") {
+		return nil, fmt.Errorf("invalid git branch %q", branch)
+	}
+
+	subPath, _ := cfg.Options["path"].(string)
+	if filepath.IsAbs(subPath) || strings.HasPrefix(filepath.Clean(subPath), "..") {
+		return nil, fmt.Errorf("git path %q must be relative to the repository root", subPath)
+	}
+
+	// the name is part of the default local path
+	if strings.ContainsAny(cfg.Name, `/\`) {
+		return nil, fmt.Errorf("git provider name %q must not contain path separators", cfg.Name)
+	}
+
+	localPath, _ := cfg.Options["localPath"].(string)
+	if localPath == "" {
+		localPath = filepath.Join(os.TempDir(), "grafana-provisioning-git", fmt.Sprintf("%d-%s", cfg.OrgID, cfg.Name))
+	}
+
+	// the directories of the repository are mapped to folders, unless the
+	// provider sets a folder
+	foldersFromFilesStructure, ok := cfg.Options["foldersFromFilesStructure"].(bool)
+	if !ok {
+		foldersFromFilesStructure = cfg.Folder == "" && cfg.FolderUID == ""
+	}
+	if foldersFromFilesStructure && cfg.Folder != "" && cfg.FolderUID != "" {
+		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
+	}
+
+	sshKnownHosts, _ := cfg.Options["sshKnownHosts"].(string)
+
+	return &FileReader{
+		Cfg:                          cfg,
+		Path:                         filepath.Join(localPath, subPath),
+		log:                          log,
+		dashboardProvisioningService: service,
+		dashboardStore:               dashboardStore,
+		folderService:                folderService,
+		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		usageTracker:                 newUsageTracker(),
+		git: &gitRepository{
+			url:           repoURL,
+			branch:        branch,
+			localPath:     localPath,
+			username:      cfg.SecureOptions["username"],
+			password:      cfg.SecureOptions["password"],
+			sshPrivateKey: cfg.SecureOptions["sshPrivateKey"],
+			sshKnownHosts: sshKnownHosts,
+			webhookSecret: cfg.SecureOptions["webhookSecret"],
+			log:           log,
+			trigger:       make(chan struct{}, 1),
+		},
+	}, nil
+}
+
+// validateGitURL rejects the URLs git would read as options, and the URLs
+// embedding credentials, which belong in the secure options.
+func validateGitURL(repoURL string) error {
+	if strings.HasPrefix(repoURL, "-") {
+		return fmt.Errorf("invalid git url %q", repoURL)
+	}
+	if u, err := url.Parse(repoURL); err == nil && u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return fmt.Errorf("git url must not contain a password, use the password secure option instead")
+		}
+	}
+	return nil
+}
+
+// sync clones the repository, or fetches the branch and checks it out when it
+// was already cloned, and records the checked out commit. It doesn't clone
+// into a directory that isn't empty, since the local path may have been set to
+// a directory holding other files.
+func (r *gitRepository) sync(ctx context.Context) error {
+	lock, _ := gitLocks.LoadOrStore(r.localPath, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	env, cleanup, err := r.env()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if _, err := os.Stat(filepath.Join(r.localPath, ".git")); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		entries, err := os.ReadDir(r.localPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(entries) > 0 {
+			return fmt.Errorf("local path %q is not a git repository and is not empty", r.localPath)
+		}
+		r.log.Info("Cloning git repository", "url", r.url, "branch", r.branch)
+		if err := os.MkdirAll(filepath.Dir(r.localPath), 0750); err != nil {
+			return err
+		}
+		if _, err := runGit(ctx, "", env, "clone", "--quiet", "--depth", "1", "--single-branch", "--branch", r.branch, "--", r.url, r.localPath); err != nil {
+			return err
+		}
+	} else {
+		// fetching the url rather than origin follows the changes of the url
+		if _, err := runGit(ctx, r.localPath, env, "fetch", "--quiet", "--depth", "1", "--", r.url, r.branch); err != nil {
+			return err
+		}
+		if _, err := runGit(ctx, r.localPath, env, "reset", "--quiet", "--hard", "FETCH_HEAD"); err != nil {
+			return err
+		}
+		if _, err := runGit(ctx, r.localPath, env, "clean", "--quiet", "-fd"); err != nil {
+			return err
+		}
+	}
+
+	commit, err := runGit(ctx, r.localPath, env, "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.commit != commit {
+		r.log.Debug("Checked out git commit", "commit", commit)
+	}
+	r.commit = commit
+	return nil
+}
+
+func (r *gitRepository) getCommit() string {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	return r.commit
+}
+
+// env returns the environment of the git commands, with the credentials of
+// the repository. The credentials are passed through the environment so they
+// are neither visible in the process list nor stored in the local clone, and
+// the basic auth header is scoped to the url of the repository so it isn't
+// sent to the other hosts git requests, like the targets of redirects.
+func (r *gitRepository) env() ([]string, func(), error) {
+	env := append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_ALLOW_PROTOCOL=file:git:http:https:ssh",
+	)
+	cleanup := func() {}
+
+	if r.username != "" || r.password != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(r.username + ":" + r.password))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http."+r.url+".extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+		)
+	}
+
+	if r.sshPrivateKey != "" {
+		dir, err := os.MkdirTemp("", "grafana-git-ssh")
+		if err != nil {
+			return nil, nil, err
+		}
+		cleanup = func() {
+			if err := os.RemoveAll(dir); err != nil {
+				r.log.Warn("Failed to remove git ssh credentials", "error", err)
+			}
+		}
+
+		keyFile := filepath.Join(dir, "id")
+		key := strings.TrimSpace(r.sshPrivateKey) + "\n"
+		if err := os.WriteFile(keyFile, []byte(key), 0600); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+
+		sshCommand := fmt.Sprintf("ssh -i '%s' -o IdentitiesOnly=yes -o StrictHostKeyChecking=accept-new", keyFile)
+		if r.sshKnownHosts != "" {
+			knownHostsFile := filepath.Join(dir, "known_hosts")
+			if err := os.WriteFile(knownHostsFile, []byte(r.sshKnownHosts), 0600); err != nil {
+				cleanup()
+				return nil, nil, err
+			}
+			sshCommand = fmt.Sprintf("ssh -i '%s' -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes -o UserKnownHostsFile='%s'", keyFile, knownHostsFile)
+		}
+		env = append(env, "GIT_SSH_COMMAND="+sshCommand)
+	}
+
+	return env, cleanup, nil
+}
+
+// runGit runs a git command and returns its trimmed output.
+func runGit(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	// nolint:gosec
+	// We can ignore the gosec G204 warning on this one because the arguments come from the provisioning configuration file.
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// verifyWebhook checks the signature of a webhook payload, sent either as the
+// hex encoded HMAC-SHA256 of the payload ("sha256=<hex>", like GitHub and
+// Gitea) or as the secret token itself (like GitLab).
+func (r *gitRepository) verifyWebhook(payload []byte, signature string, token string) error {
+	if r.webhookSecret == "" {
+		return ErrGitWebhookDisabled
+	}
+
+	if token != "" {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(r.webhookSecret)) == 1 {
+			return nil
+		}
+		return ErrGitWebhookInvalidSignature
+	}
+
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return ErrGitWebhookInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(r.webhookSecret))
+	mac.Write(payload)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return ErrGitWebhookInvalidSignature
+	}
+	return nil
+}
+
+// triggerSync requests the polling of the provider to sync the repository
+// without waiting for the next interval.
+func (r *gitRepository) triggerSync() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+		// a sync is already pending
+	}
+}
@@ -0,0 +1,150 @@
+package datasources
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
+
+// DryRun validates the provisioning config files in a directory and adds the
+// changes provisioning them would apply to the result, without applying them.
+func DryRun(ctx context.Context, configDirectory string, store Store, orgService org.Service, result *dryrun.Result) error {
+	dc := newDatasourceProvisioner(log.New("provisioning.datasources"), store, nil, orgService)
+	return dc.dryRun(ctx, configDirectory, result)
+}
+
+func (dc *DatasourceProvisioner) dryRun(ctx context.Context, configPath string, result *dryrun.Result) error {
+	files, err := os.ReadDir(configPath)
+	if err != nil {
+		// like readConfig, a missing directory means there is nothing to provision
+		return nil
+	}
+
+	var valid []*configs
+	filenames := map[*configs]string{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(configPath, file.Name())
+		cfg, err := dc.cfgProvider.parseDatasourceConfig(configPath, file)
+		if err != nil {
+			result.AddError(dryrun.ResourceDatasource, filename, err)
+			continue
+		}
+		if cfg == nil {
+			continue
+		}
+		if err := dc.cfgProvider.validateDefaultUniqueness(ctx, []*configs{cfg}); err != nil {
+			result.AddError(dryrun.ResourceDatasource, filename, err)
+			continue
+		}
+		filenames[cfg] = filename
+		valid = append(valid, cfg)
+	}
+
+	// the default data sources are unique per organization across all the files
+	if err := dc.cfgProvider.validateDefaultUniqueness(ctx, valid); err != nil {
+		result.AddError(dryrun.ResourceDatasource, "", err)
+	}
+
+	willExistAfterProvisioning := map[DataSourceMapKey]bool{}
+	for _, cfg := range valid {
+		for _, ds := range cfg.DeleteDatasources {
+			willExistAfterProvisioning[DataSourceMapKey{Name: ds.Name, OrgId: ds.OrgID}] = false
+		}
+		for _, ds := range cfg.Datasources {
+			willExistAfterProvisioning[DataSourceMapKey{Name: ds.Name, OrgId: ds.OrgID}] = true
+		}
+	}
+
+	deleted := map[DataSourceMapKey]bool{}
+	for _, cfg := range valid {
+		for _, ds := range cfg.DeleteDatasources {
+			key := DataSourceMapKey{Name: ds.Name, OrgId: ds.OrgID}
+			dataSource, err := dc.store.GetDataSource(ctx, &datasources.GetDataSourceQuery{OrgID: ds.OrgID, Name: ds.Name})
+			if err != nil {
+				if errors.Is(err, datasources.ErrDataSourceNotFound) {
+					continue
+				}
+				return err
+			}
+			deleted[key] = true
+			// data sources deleted and provisioned again are reported as created
+			if willExistAfterProvisioning[key] {
+				continue
+			}
+			result.Add(dryrun.ResourceDatasource, dryrun.Change{
+				Action: dryrun.ActionDelete,
+				OrgID:  ds.OrgID,
+				UID:    dataSource.UID,
+				Name:   ds.Name,
+				File:   filenames[cfg],
+			})
+		}
+
+		for _, ds := range cfg.Datasources {
+			change, err := dc.datasourceChange(ctx, ds, deleted[DataSourceMapKey{Name: ds.Name, OrgId: ds.OrgID}])
+			if err != nil {
+				return err
+			}
+			if change != nil {
+				change.File = filenames[cfg]
+				result.Add(dryrun.ResourceDatasource, *change)
+			}
+		}
+	}
+
+	return nil
+}
+
+// datasourceChange returns the change provisioning a data source would apply,
+// or nil when the data source is up to date. Secure JSON data is encrypted, it
+// is not compared.
+func (dc *DatasourceProvisioner) datasourceChange(ctx context.Context, ds *upsertDataSourceFromConfig, deleted bool) (*dryrun.Change, error) {
+	dataSource, err := dc.store.GetDataSource(ctx, &datasources.GetDataSourceQuery{OrgID: ds.OrgID, Name: ds.Name})
+	if err != nil && !errors.Is(err, datasources.ErrDataSourceNotFound) {
+		return nil, err
+	}
+
+	if deleted || errors.Is(err, datasources.ErrDataSourceNotFound) {
+		return &dryrun.Change{Action: dryrun.ActionCreate, OrgID: ds.OrgID, UID: ds.UID, Name: ds.Name}, nil
+	}
+
+	// the store ignores updates to an older version
+	if ds.Version != 0 && dataSource.Version >= ds.Version {
+		return nil, nil
+	}
+
+	updateCmd := createUpdateCommand(ds, dataSource.ID)
+	values := []dryrun.FieldValues{
+		dryrun.Field("type", dataSource.Type, updateCmd.Type),
+		dryrun.Field("access", dataSource.Access, updateCmd.Access),
+		dryrun.Field("url", dataSource.URL, updateCmd.URL),
+		dryrun.Field("user", dataSource.User, updateCmd.User),
+		dryrun.Field("database", dataSource.Database, updateCmd.Database),
+		dryrun.Field("basicAuth", dataSource.BasicAuth, updateCmd.BasicAuth),
+		dryrun.Field("basicAuthUser", dataSource.BasicAuthUser, updateCmd.BasicAuthUser),
+		dryrun.Field("withCredentials", dataSource.WithCredentials, updateCmd.WithCredentials),
+		dryrun.Field("isDefault", dataSource.IsDefault, updateCmd.IsDefault),
+		dryrun.Field("jsonData", dataSource.JsonData, updateCmd.JsonData),
+		dryrun.Field("readOnly", dataSource.ReadOnly, updateCmd.ReadOnly),
+	}
+	if updateCmd.UID != "" {
+		values = append(values, dryrun.Field("uid", dataSource.UID, updateCmd.UID))
+	}
+
+	fields := dryrun.Fields(values...)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return &dryrun.Change{Action: dryrun.ActionUpdate, OrgID: ds.OrgID, UID: dataSource.UID, Name: ds.Name, Fields: fields}, nil
+}
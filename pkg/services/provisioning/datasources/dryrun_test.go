@@ -0,0 +1,73 @@
+package datasources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Run("should report new data sources without inserting them", func(t *testing.T) {
+		store := &spyStore{}
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), twoDatasourcesConfig, store, &orgtest.FakeOrgService{}, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		require.Equal(t, dryrun.Summary{Create: 2}, result.Summary[dryrun.ResourceDatasource])
+		require.Equal(t, "Graphite", result.Changes[dryrun.ResourceDatasource][0].Name)
+		require.Equal(t, "Prometheus", result.Changes[dryrun.ResourceDatasource][1].Name)
+		require.Empty(t, store.inserted)
+		require.Empty(t, store.updated)
+	})
+
+	t.Run("should report the updated fields of existing data sources", func(t *testing.T) {
+		store := &spyStore{
+			items: []*datasources.DataSource{
+				{Name: "Graphite", OrgID: 1, ID: 1, UID: "graphite", Type: "graphite", Access: datasources.DS_ACCESS_PROXY, URL: "http://localhost:8080", ReadOnly: true},
+				{Name: "Prometheus", OrgID: 1, ID: 2, UID: "prometheus", Type: "prometheus", Access: datasources.DS_ACCESS_PROXY, URL: "http://localhost:9091", ReadOnly: true},
+			},
+		}
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), twoDatasourcesConfig, store, &orgtest.FakeOrgService{}, result)
+		require.NoError(t, err)
+
+		require.Equal(t, []dryrun.Change{{
+			Action: dryrun.ActionUpdate,
+			OrgID:  1,
+			UID:    "prometheus",
+			Name:   "Prometheus",
+			File:   "testdata/two-datasources/two-datasources.yaml",
+			Fields: []string{"url"},
+		}}, result.Changes[dryrun.ResourceDatasource])
+		require.Empty(t, store.updated)
+	})
+
+	t.Run("should report deleted data sources without deleting them", func(t *testing.T) {
+		store := &spyStore{
+			items: []*datasources.DataSource{{Name: "old-data-source", OrgID: 1, ID: 1, UID: "old"}},
+		}
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), deleteOneDatasource, store, &orgtest.FakeOrgService{}, result)
+		require.NoError(t, err)
+
+		require.Equal(t, dryrun.Summary{Delete: 1}, result.Summary[dryrun.ResourceDatasource])
+		require.Equal(t, "old", result.Changes[dryrun.ResourceDatasource][0].UID)
+		require.Empty(t, store.deleted)
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), brokenYaml, &spyStore{}, &orgtest.FakeOrgService{}, result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.NotEmpty(t, result.Errors)
+		require.Equal(t, dryrun.ResourceDatasource, result.Errors[0].Resource)
+	})
+}
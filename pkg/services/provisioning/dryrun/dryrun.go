@@ -0,0 +1,174 @@
+// Package dryrun holds the result of a provisioning dry-run: the changes the
+// provisioners would apply to the database, and the invalid provisioning files.
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Action is the change a provisioner would apply to a resource.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// ResourceType is the type of provisioned resource.
+type ResourceType string
+
+const (
+	ResourceDatasource         ResourceType = "datasources"
+	ResourcePlugin             ResourceType = "plugins"
+	ResourceDashboard          ResourceType = "dashboards"
+	ResourceFolder             ResourceType = "folders"
+	ResourceAlertRule          ResourceType = "alertRules"
+	ResourceContactPoint       ResourceType = "contactPoints"
+	ResourceNotificationPolicy ResourceType = "notificationPolicies"
+	ResourceMuteTiming         ResourceType = "muteTimings"
+	ResourceTemplate           ResourceType = "templates"
+	ResourceReport             ResourceType = "reports"
+	ResourceTeam               ResourceType = "teams"
+	ResourceServiceAccount     ResourceType = "serviceAccounts"
+	ResourcePermission         ResourceType = "permissions"
+	ResourceRole               ResourceType = "roles"
+)
+
+// Change is a change of a provisioned resource.
+type Change struct {
+	Action Action `json:"action"`
+	OrgID  int64  `json:"orgId"`
+	UID    string `json:"uid,omitempty"`
+	Name   string `json:"name"`
+	// File is the provisioning file the change comes from, if known.
+	File string `json:"file,omitempty"`
+	// Fields are the updated fields, when the provisioner can compare them.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// FileError is a provisioning file that failed validation.
+type FileError struct {
+	Resource ResourceType `json:"resource"`
+	File     string       `json:"file,omitempty"`
+	Error    string       `json:"error"`
+}
+
+// Summary counts the changes of a resource type.
+type Summary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+// Result is the result of a provisioning dry-run.
+type Result struct {
+	// Valid is false when a provisioning file failed validation.
+	Valid   bool                      `json:"valid"`
+	Summary map[ResourceType]Summary  `json:"summary"`
+	Changes map[ResourceType][]Change `json:"changes"`
+	Errors  []FileError               `json:"errors"`
+}
+
+func NewResult() *Result {
+	return &Result{
+		Valid:   true,
+		Summary: map[ResourceType]Summary{},
+		Changes: map[ResourceType][]Change{},
+		Errors:  []FileError{},
+	}
+}
+
+// Add adds the change of a resource.
+func (r *Result) Add(resource ResourceType, change Change) {
+	r.Changes[resource] = append(r.Changes[resource], change)
+
+	summary := r.Summary[resource]
+	switch change.Action {
+	case ActionCreate:
+		summary.Create++
+	case ActionUpdate:
+		summary.Update++
+	case ActionDelete:
+		summary.Delete++
+	}
+	r.Summary[resource] = summary
+}
+
+// AddError adds an invalid provisioning file, the file is empty when the error
+// isn't specific to a file.
+func (r *Result) AddError(resource ResourceType, file string, err error) {
+	r.Valid = false
+	r.Errors = append(r.Errors, FileError{Resource: resource, File: file, Error: err.Error()})
+}
+
+// HasChanges returns true when the provisioners would change the database.
+func (r *Result) HasChanges() bool {
+	for _, changes := range r.Changes {
+		if len(changes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceTypes returns the resource types with changes, sorted by name.
+func (r *Result) ResourceTypes() []ResourceType {
+	types := make([]ResourceType, 0, len(r.Changes))
+	for resource, changes := range r.Changes {
+		if len(changes) > 0 {
+			types = append(types, resource)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// FieldValues are the current and provisioned values of a field.
+type FieldValues struct {
+	Name        string
+	Current     any
+	Provisioned any
+}
+
+func Field(name string, current any, provisioned any) FieldValues {
+	return FieldValues{Name: name, Current: current, Provisioned: provisioned}
+}
+
+// equal compares the JSON encoding of the values, since the values read from
+// the database and from the provisioning files are often of different types,
+// like *simplejson.Json and map[string]any.
+func (v FieldValues) equal() bool {
+	current, err := json.Marshal(v.Current)
+	if err != nil {
+		return false
+	}
+	provisioned, err := json.Marshal(v.Provisioned)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(normalize(current), normalize(provisioned))
+}
+
+// normalize treats empty values and null as equal.
+func normalize(b []byte) []byte {
+	switch string(b) {
+	case "null", "{}", "[]", `""`, "0", "false":
+		return nil
+	}
+	return b
+}
+
+// Fields returns the sorted names of the fields whose values differ.
+func Fields(values ...FieldValues) []string {
+	fields := make([]string, 0)
+	for _, v := range values {
+		if !v.equal() {
+			fields = append(fields, v.Name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,56 @@
+package dryrun
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResult(t *testing.T) {
+	result := NewResult()
+	require.True(t, result.Valid)
+	require.False(t, result.HasChanges())
+
+	result.Add(ResourceDatasource, Change{Action: ActionCreate, OrgID: 1, Name: "Prometheus"})
+	result.Add(ResourceDatasource, Change{Action: ActionDelete, OrgID: 1, Name: "Graphite"})
+	result.Add(ResourceAlertRule, Change{Action: ActionUpdate, OrgID: 1, Name: "High CPU"})
+
+	require.True(t, result.HasChanges())
+	require.Equal(t, Summary{Create: 1, Delete: 1}, result.Summary[ResourceDatasource])
+	require.Equal(t, Summary{Update: 1}, result.Summary[ResourceAlertRule])
+	require.Equal(t, []ResourceType{ResourceAlertRule, ResourceDatasource}, result.ResourceTypes())
+
+	result.AddError(ResourceDashboard, "dashboards/broken.yaml", errors.New("invalid yaml"))
+	require.False(t, result.Valid)
+	require.Equal(t, []FileError{{Resource: ResourceDashboard, File: "dashboards/broken.yaml", Error: "invalid yaml"}}, result.Errors)
+}
+
+func TestFields(t *testing.T) {
+	t.Run("should return the sorted names of the changed fields", func(t *testing.T) {
+		fields := Fields(
+			Field("url", "http://localhost:9090", "http://localhost:9091"),
+			Field("type", "prometheus", "prometheus"),
+			Field("jsonData", map[string]any{"httpMethod": "GET"}, map[string]any{"httpMethod": "POST"}),
+		)
+		require.Equal(t, []string{"jsonData", "url"}, fields)
+	})
+
+	t.Run("should compare values of different types by their JSON encoding", func(t *testing.T) {
+		type settings struct {
+			HTTPMethod string `json:"httpMethod"`
+		}
+		fields := Fields(Field("jsonData", settings{HTTPMethod: "GET"}, map[string]any{"httpMethod": "GET"}))
+		require.Empty(t, fields)
+	})
+
+	t.Run("should treat empty values as equal", func(t *testing.T) {
+		fields := Fields(
+			Field("jsonData", nil, map[string]any{}),
+			Field("labels", []string{}, nil),
+			Field("user", "", nil),
+			Field("basicAuth", false, nil),
+		)
+		require.Empty(t, fields)
+	})
+}
@@ -0,0 +1,143 @@
+package folders
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*foldersAsConfig, error) {
+	var configs []*foldersAsConfig
+	cr.log.Debug("Looking for folder provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read folder provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing folder provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseFolderConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	if err := validateRequiredFields(configs); err != nil {
+		return nil, err
+	}
+
+	checkOrgIDAndOrgName(configs)
+
+	for _, cfg := range configs {
+		sorted, err := sortByHierarchy(cfg.Folders)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Folders = sorted
+	}
+
+	return configs, nil
+}
+
+func (cr *configReader) parseFolderConfig(path string, file fs.DirEntry) (*foldersAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *foldersAsConfigV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToFoldersFromConfig(), nil
+}
+
+func validateRequiredFields(configs []*foldersAsConfig) error {
+	for i := range configs {
+		var errStrings []string
+		for index, f := range configs[i].Folders {
+			if f.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("folder item %d in configuration doesn't contain required field uid", index+1))
+			}
+			if f.Title == "" {
+				errStrings = append(errStrings, fmt.Sprintf("folder item %d in configuration doesn't contain required field title", index+1))
+			}
+		}
+		for index, f := range configs[i].DeleteFolders {
+			if f.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("delete folder item %d in configuration doesn't contain required field uid", index+1))
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf("%s", strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+// sortByHierarchy orders folders so that parents defined in the same file
+// are provisioned before their children.
+func sortByHierarchy(folders []*folderFromConfig) ([]*folderFromConfig, error) {
+	pending := make(map[string]bool, len(folders))
+	for _, f := range folders {
+		pending[f.UID] = true
+	}
+
+	sorted := make([]*folderFromConfig, 0, len(folders))
+	for len(sorted) < len(folders) {
+		progressed := false
+		for _, f := range folders {
+			if !pending[f.UID] || pending[f.ParentUID] {
+				continue
+			}
+			sorted = append(sorted, f)
+			delete(pending, f.UID)
+			progressed = true
+		}
+		if !progressed {
+			return nil, fmt.Errorf("folders in configuration contain a parent cycle")
+		}
+	}
+
+	return sorted, nil
+}
+
+func checkOrgIDAndOrgName(configs []*foldersAsConfig) {
+	for i := range configs {
+		for _, f := range configs[i].Folders {
+			f.OrgID = utils.OrgIDOrDefault(f.OrgID, f.OrgName)
+		}
+		for _, f := range configs[i].DeleteFolders {
+			f.OrgID = utils.OrgIDOrDefault(f.OrgID, f.OrgName)
+		}
+	}
+}
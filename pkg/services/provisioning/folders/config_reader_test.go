@@ -0,0 +1,85 @@
+package folders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	correctProperties = "./testdata/test-configs/correct-properties"
+	brokenYaml        = "./testdata/test-configs/broken-yaml"
+	missingUID        = "./testdata/test-configs/missing-uid"
+	emptyFolder       = "./testdata/test-configs/empty_folder"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Folder without uid should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(missingUID)
+		require.Error(t, err)
+		require.Equal(t, "folder item 1 in configuration doesn't contain required field uid", err.Error())
+	})
+
+	t.Run("Can read correct properties and orders parents first", func(t *testing.T) {
+		t.Setenv("FOLDER_TITLE", "Team A")
+
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+		require.Len(t, cfg[0].Folders, 3)
+
+		teamA := cfg[0].Folders[0]
+		require.Equal(t, "team-a", teamA.UID)
+		require.Equal(t, "Team A", teamA.Title)
+		require.Equal(t, "Dashboards owned by team A", teamA.Description)
+		require.Equal(t, int64(2), teamA.OrgID)
+
+		require.Equal(t, "shared", cfg[0].Folders[1].UID)
+		require.Equal(t, "Org 3", cfg[0].Folders[1].OrgName)
+		require.Equal(t, int64(0), cfg[0].Folders[1].OrgID)
+
+		require.Equal(t, "team-a-alerts", cfg[0].Folders[2].UID)
+		require.Equal(t, "team-a", cfg[0].Folders[2].ParentUID)
+
+		require.Len(t, cfg[0].DeleteFolders, 1)
+		require.Equal(t, "legacy", cfg[0].DeleteFolders[0].UID)
+		require.Equal(t, int64(1), cfg[0].DeleteFolders[0].OrgID)
+	})
+}
+
+func TestSortByHierarchy(t *testing.T) {
+	t.Run("Returns an error for parent cycles", func(t *testing.T) {
+		_, err := sortByHierarchy([]*folderFromConfig{
+			{UID: "a", ParentUID: "b"},
+			{UID: "b", ParentUID: "a"},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Keeps folders with parents outside the configuration", func(t *testing.T) {
+		sorted, err := sortByHierarchy([]*folderFromConfig{
+			{UID: "b", ParentUID: "a"},
+			{UID: "c", ParentUID: "existing"},
+			{UID: "a"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"c", "a", "b"}, []string{sorted[0].UID, sorted[1].UID, sorted[2].UID})
+	})
+}
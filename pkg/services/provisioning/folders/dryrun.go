@@ -0,0 +1,124 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
+
+// DryRun validates the provisioning config files in a directory and adds the
+// changes provisioning them would apply to the result, without applying them.
+func DryRun(ctx context.Context, configDirectory string, folderService folder.Service, orgService org.Service, result *dryrun.Result) error {
+	logger := log.New("provisioning.folders")
+	fp := FolderProvisioner{
+		log:           logger,
+		cfgProvider:   &configReader{log: logger},
+		folderService: folderService,
+		orgService:    orgService,
+	}
+	return fp.dryRun(ctx, configDirectory, result)
+}
+
+func (fp *FolderProvisioner) dryRun(ctx context.Context, configPath string, result *dryrun.Result) error {
+	files, err := os.ReadDir(configPath)
+	if err != nil {
+		// like readConfig, a missing directory means there is nothing to provision
+		return nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(configPath, file.Name())
+		cfg, err := fp.cfgProvider.parseFolderConfig(configPath, file)
+		if err != nil {
+			result.AddError(dryrun.ResourceFolder, filename, err)
+			continue
+		}
+
+		cfgs := []*foldersAsConfig{cfg}
+		if err := validateRequiredFields(cfgs); err != nil {
+			result.AddError(dryrun.ResourceFolder, filename, err)
+			continue
+		}
+		checkOrgIDAndOrgName(cfgs)
+		sorted, err := sortByHierarchy(cfg.Folders)
+		if err != nil {
+			result.AddError(dryrun.ResourceFolder, filename, err)
+			continue
+		}
+		cfg.Folders = sorted
+
+		changes, err := fp.changes(ctx, cfg, filename)
+		if err != nil {
+			if errors.Is(err, org.ErrOrgNotFound) {
+				result.AddError(dryrun.ResourceFolder, filename, err)
+				continue
+			}
+			return err
+		}
+		for _, change := range changes {
+			result.Add(dryrun.ResourceFolder, change)
+		}
+	}
+
+	return nil
+}
+
+func (fp *FolderProvisioner) changes(ctx context.Context, cfg *foldersAsConfig, filename string) ([]dryrun.Change, error) {
+	var changes []dryrun.Change
+	for _, f := range cfg.DeleteFolders {
+		orgID, err := fp.orgID(ctx, f.OrgID, f.OrgName)
+		if err != nil {
+			return nil, err
+		}
+
+		uid := f.UID
+		existing, err := fp.folderService.Get(ctx, &folder.GetFolderQuery{UID: &uid, OrgID: orgID, SignedInUser: provisioningUser(orgID)})
+		if isFolderNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionDelete, OrgID: orgID, UID: f.UID, Name: existing.Title, File: filename})
+	}
+
+	for _, f := range cfg.Folders {
+		orgID, err := fp.orgID(ctx, f.OrgID, f.OrgName)
+		if err != nil {
+			return nil, err
+		}
+
+		uid := f.UID
+		existing, err := fp.folderService.Get(ctx, &folder.GetFolderQuery{UID: &uid, OrgID: orgID, SignedInUser: provisioningUser(orgID)})
+		if isFolderNotFound(err) {
+			changes = append(changes, dryrun.Change{Action: dryrun.ActionCreate, OrgID: orgID, UID: f.UID, Name: f.Title, File: filename})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fields := dryrun.Fields(
+			dryrun.Field("title", existing.Title, f.Title),
+			dryrun.Field("description", existing.Description, f.Description),
+			dryrun.Field("parentUid", existing.ParentUID, f.ParentUID),
+		)
+		if len(fields) == 0 {
+			continue
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionUpdate, OrgID: orgID, UID: f.UID, Name: f.Title, File: filename, Fields: fields})
+	}
+
+	return changes, nil
+}
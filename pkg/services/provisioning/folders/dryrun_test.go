@@ -0,0 +1,67 @@
+package folders
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("FOLDER_TITLE", "Team A")
+
+	t.Run("should report new, updated and deleted folders without changing them", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+		folderService := newSpyFolderService()
+		folderService.folders["legacy"] = &folder.Folder{UID: "legacy", OrgID: 1, Title: "Legacy"}
+		folderService.folders["team-a"] = &folder.Folder{UID: "team-a", OrgID: 2, Title: "Old title", Description: "Dashboards owned by team A"}
+		folderService.folders["team-a-alerts"] = &folder.Folder{UID: "team-a-alerts", OrgID: 2, Title: "Alerts"}
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, folderService, orgService, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		file := filepath.Join(correctProperties, "folders.yaml")
+		require.Equal(t, []dryrun.Change{
+			{Action: dryrun.ActionDelete, OrgID: 1, UID: "legacy", Name: "Legacy", File: file},
+			{Action: dryrun.ActionUpdate, OrgID: 2, UID: "team-a", Name: "Team A", File: file, Fields: []string{"title"}},
+			{Action: dryrun.ActionCreate, OrgID: 3, UID: "shared", Name: "Shared", File: file},
+			{Action: dryrun.ActionUpdate, OrgID: 2, UID: "team-a-alerts", Name: "Alerts", File: file, Fields: []string{"parentUid"}},
+		}, result.Changes[dryrun.ResourceFolder])
+		require.Contains(t, folderService.folders, "legacy")
+		require.Equal(t, "Old title", folderService.folders["team-a"].Title)
+		require.Empty(t, folderService.folders["team-a-alerts"].ParentUID)
+		require.Empty(t, folderService.created)
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), missingUID, newSpyFolderService(), orgtest.NewOrgServiceFake(), result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, dryrun.ResourceFolder, result.Errors[0].Resource)
+	})
+
+	t.Run("should report files with unknown organizations", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedError = org.ErrOrgNotFound
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, newSpyFolderService(), orgService, result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Empty(t, result.Changes[dryrun.ResourceFolder])
+	})
+}
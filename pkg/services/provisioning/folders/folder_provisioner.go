@@ -0,0 +1,170 @@
+package folders
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+)
+
+var provisionerPermissions = []accesscontrol.Permission{
+	{Action: dashboards.ActionFoldersCreate},
+	{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersDelete, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionDashboardsWrite, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionDashboardsDelete, Scope: dashboards.ScopeFoldersAll},
+}
+
+// Provision scans a directory for provisioning config files
+// and provisions the folders in those files.
+func Provision(
+	ctx context.Context,
+	configDirectory string,
+	folderService folder.Service,
+	dashboardProvisioningService dashboards.DashboardProvisioningService,
+	orgService org.Service,
+	provenanceService provenance.Service,
+) error {
+	logger := log.New("provisioning.folders")
+	fp := FolderProvisioner{
+		log:                          logger,
+		cfgProvider:                  &configReader{log: logger},
+		folderService:                folderService,
+		dashboardProvisioningService: dashboardProvisioningService,
+		orgService:                   orgService,
+		provenanceService:            provenanceService,
+	}
+	return fp.applyChanges(ctx, configDirectory)
+}
+
+// FolderProvisioner is responsible for provisioning folders based on
+// configuration read by the `configReader`
+type FolderProvisioner struct {
+	log                          log.Logger
+	cfgProvider                  *configReader
+	folderService                folder.Service
+	dashboardProvisioningService dashboards.DashboardProvisioningService
+	orgService                   org.Service
+	provenanceService            provenance.Service
+}
+
+func (fp *FolderProvisioner) apply(ctx context.Context, cfg *foldersAsConfig) error {
+	for _, f := range cfg.DeleteFolders {
+		orgID, err := fp.orgID(ctx, f.OrgID, f.OrgName)
+		if err != nil {
+			return err
+		}
+
+		fp.log.Debug("Deleting folder from configuration", "uid", f.UID, "orgId", orgID)
+		err = fp.folderService.Delete(ctx, &folder.DeleteFolderCommand{UID: f.UID, OrgID: orgID, SignedInUser: provisioningUser(orgID)})
+		if err != nil && !isFolderNotFound(err) {
+			return err
+		}
+		if err := fp.provenanceService.DeleteProvisioned(ctx, orgID, provenance.KindFolder, f.UID); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range cfg.Folders {
+		orgID, err := fp.orgID(ctx, f.OrgID, f.OrgName)
+		if err != nil {
+			return err
+		}
+
+		user := provisioningUser(orgID)
+		uid := f.UID
+		existing, err := fp.folderService.Get(ctx, &folder.GetFolderQuery{UID: &uid, OrgID: orgID, SignedInUser: user})
+		switch {
+		case isFolderNotFound(err):
+			fp.log.Info("Inserting folder from configuration", "uid", f.UID, "title", f.Title)
+			_, err := fp.dashboardProvisioningService.SaveFolderForProvisionedDashboards(ctx, &folder.CreateFolderCommand{
+				UID:         f.UID,
+				OrgID:       orgID,
+				Title:       f.Title,
+				Description: f.Description,
+				ParentUID:   f.ParentUID,
+			})
+			if err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			fp.log.Debug("Updating folder from configuration", "uid", f.UID, "title", f.Title)
+			title, description := f.Title, f.Description
+			_, err := fp.folderService.Update(ctx, &folder.UpdateFolderCommand{
+				UID:            f.UID,
+				OrgID:          orgID,
+				NewTitle:       &title,
+				NewDescription: &description,
+				Overwrite:      true,
+				SignedInUser:   user,
+			})
+			if err != nil {
+				return err
+			}
+
+			if existing.ParentUID != f.ParentUID {
+				fp.log.Debug("Moving folder from configuration", "uid", f.UID, "parentUid", f.ParentUID)
+				_, err := fp.folderService.Move(ctx, &folder.MoveFolderCommand{
+					UID:          f.UID,
+					NewParentUID: f.ParentUID,
+					OrgID:        orgID,
+					SignedInUser: user,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := fp.provenanceService.SetProvisioned(ctx, orgID, provenance.KindFolder, f.UID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fp *FolderProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := fp.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := fp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fp *FolderProvisioner) orgID(ctx context.Context, orgID int64, orgName string) (int64, error) {
+	if orgID != 0 {
+		return orgID, nil
+	}
+
+	res, err := fp.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: orgName})
+	if err != nil {
+		return 0, err
+	}
+	return res.ID, nil
+}
+
+func isFolderNotFound(err error) bool {
+	return errors.Is(err, dashboards.ErrFolderNotFound) || errors.Is(err, folder.ErrFolderNotFound)
+}
+
+func provisioningUser(orgID int64) identity.Requester {
+	return accesscontrol.BackgroundUser("folder_provisioning", orgID, org.RoleAdmin, provisionerPermissions)
+}
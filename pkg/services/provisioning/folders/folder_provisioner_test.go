@@ -0,0 +1,106 @@
+package folders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
+)
+
+func TestFolderProvisioner(t *testing.T) {
+	t.Setenv("FOLDER_TITLE", "Team A")
+
+	orgService := orgtest.NewOrgServiceFake()
+	orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+
+	t.Run("Creates nested folders and deletes folders", func(t *testing.T) {
+		folderService := newSpyFolderService()
+		folderService.folders["legacy"] = &folder.Folder{UID: "legacy", OrgID: 1}
+		provenanceService := provenancetest.NewFakeService()
+
+		err := Provision(context.Background(), correctProperties, folderService, folderService, orgService, provenanceService)
+		require.NoError(t, err)
+
+		require.NotContains(t, folderService.folders, "legacy")
+		require.Equal(t, []string{"team-a", "shared", "team-a-alerts"}, folderService.created)
+		require.Equal(t, "team-a", folderService.folders["team-a-alerts"].ParentUID)
+		require.Equal(t, int64(3), folderService.folders["shared"].OrgID)
+
+		provisioned, err := provenanceService.IsProvisioned(context.Background(), 2, provenance.KindFolder, "team-a")
+		require.NoError(t, err)
+		require.True(t, provisioned)
+	})
+
+	t.Run("Updates and moves existing folders", func(t *testing.T) {
+		folderService := newSpyFolderService()
+		folderService.folders["team-a"] = &folder.Folder{UID: "team-a", OrgID: 2, Title: "Old title"}
+		folderService.folders["team-a-alerts"] = &folder.Folder{UID: "team-a-alerts", OrgID: 2, Title: "Alerts"}
+
+		err := Provision(context.Background(), correctProperties, folderService, folderService, orgService, provenancetest.NewFakeService())
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"shared"}, folderService.created)
+		require.Equal(t, "Team A", folderService.folders["team-a"].Title)
+		require.Equal(t, "Dashboards owned by team A", folderService.folders["team-a"].Description)
+		require.Equal(t, "team-a", folderService.folders["team-a-alerts"].ParentUID)
+	})
+}
+
+// spyFolderService keeps folders in memory and implements the parts of the
+// folder and dashboard provisioning services used by the provisioner.
+type spyFolderService struct {
+	*foldertest.FakeService
+	dashboards.DashboardProvisioningService
+	folders map[string]*folder.Folder
+	created []string
+}
+
+func newSpyFolderService() *spyFolderService {
+	return &spyFolderService{
+		FakeService: foldertest.NewFakeService(),
+		folders:     map[string]*folder.Folder{},
+	}
+}
+
+func (s *spyFolderService) Get(ctx context.Context, q *folder.GetFolderQuery) (*folder.Folder, error) {
+	f, ok := s.folders[*q.UID]
+	if !ok {
+		return nil, dashboards.ErrFolderNotFound
+	}
+	return f, nil
+}
+
+func (s *spyFolderService) SaveFolderForProvisionedDashboards(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error) {
+	f := &folder.Folder{UID: cmd.UID, OrgID: cmd.OrgID, Title: cmd.Title, Description: cmd.Description, ParentUID: cmd.ParentUID}
+	s.folders[cmd.UID] = f
+	s.created = append(s.created, cmd.UID)
+	return f, nil
+}
+
+func (s *spyFolderService) Update(ctx context.Context, cmd *folder.UpdateFolderCommand) (*folder.Folder, error) {
+	f := s.folders[cmd.UID]
+	f.Title, f.Description = *cmd.NewTitle, *cmd.NewDescription
+	return f, nil
+}
+
+func (s *spyFolderService) Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*folder.Folder, error) {
+	f := s.folders[cmd.UID]
+	f.ParentUID = cmd.NewParentUID
+	return f, nil
+}
+
+func (s *spyFolderService) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) error {
+	if _, ok := s.folders[cmd.UID]; !ok {
+		return dashboards.ErrFolderNotFound
+	}
+	delete(s.folders, cmd.UID)
+	return nil
+}
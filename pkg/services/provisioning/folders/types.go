@@ -0,0 +1,79 @@
+package folders
+
+import (
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// foldersAsConfig is a normalized data object for folders config data. Any config version should be mappable
+// to this type.
+type foldersAsConfig struct {
+	Folders       []*folderFromConfig
+	DeleteFolders []*deleteFolderConfig
+}
+
+type folderFromConfig struct {
+	UID         string
+	Title       string
+	Description string
+	ParentUID   string
+	OrgID       int64
+	OrgName     string
+}
+
+type deleteFolderConfig struct {
+	UID     string
+	OrgID   int64
+	OrgName string
+}
+
+type folderFromConfigV1 struct {
+	UID         values.StringValue `json:"uid" yaml:"uid"`
+	Title       values.StringValue `json:"title" yaml:"title"`
+	Description values.StringValue `json:"description" yaml:"description"`
+	ParentUID   values.StringValue `json:"parentUid" yaml:"parentUid"`
+	OrgID       values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName     values.StringValue `json:"orgName" yaml:"orgName"`
+}
+
+type deleteFolderConfigV1 struct {
+	UID     values.StringValue `json:"uid" yaml:"uid"`
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue `json:"orgName" yaml:"orgName"`
+}
+
+// foldersAsConfigV1 is a mapping for version 1 configs. This is mapped to its normalised version.
+type foldersAsConfigV1 struct {
+	APIVersion    values.Int64Value       `json:"apiVersion" yaml:"apiVersion"`
+	Folders       []*folderFromConfigV1   `json:"folders" yaml:"folders"`
+	DeleteFolders []*deleteFolderConfigV1 `json:"deleteFolders" yaml:"deleteFolders"`
+}
+
+// mapToFoldersFromConfig maps config syntax to a normalized foldersAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *foldersAsConfigV1) mapToFoldersFromConfig() *foldersAsConfig {
+	r := &foldersAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, f := range cfg.Folders {
+		r.Folders = append(r.Folders, &folderFromConfig{
+			UID:         f.UID.Value(),
+			Title:       f.Title.Value(),
+			Description: f.Description.Value(),
+			ParentUID:   f.ParentUID.Value(),
+			OrgID:       f.OrgID.Value(),
+			OrgName:     f.OrgName.Value(),
+		})
+	}
+
+	for _, f := range cfg.DeleteFolders {
+		r.DeleteFolders = append(r.DeleteFolders, &deleteFolderConfig{
+			UID:     f.UID.Value(),
+			OrgID:   f.OrgID.Value(),
+			OrgName: f.OrgName.Value(),
+		})
+	}
+
+	return r
+}
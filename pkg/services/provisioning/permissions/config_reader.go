@@ -0,0 +1,130 @@
+package permissions
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*permissionsAsConfig, error) {
+	var configs []*permissionsAsConfig
+	cr.log.Debug("Looking for permission provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read permission provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing permission provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parsePermissionConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	if err := validatePermissions(configs); err != nil {
+		return nil, err
+	}
+
+	checkOrgIDAndOrgName(configs)
+
+	return configs, nil
+}
+
+func (cr *configReader) parsePermissionConfig(path string, file fs.DirEntry) (*permissionsAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *permissionsAsConfigV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToPermissionsFromConfig(), nil
+}
+
+// validatePermissions checks that every entry targets exactly one folder or
+// dashboard and that every item grants a known permission to exactly one subject.
+func validatePermissions(configs []*permissionsAsConfig) error {
+	for i := range configs {
+		var errStrings []string
+		for index, p := range configs[i].Permissions {
+			if (p.FolderUID == "") == (p.DashboardUID == "") {
+				errStrings = append(errStrings, fmt.Sprintf("permissions item %d in configuration must contain exactly one of folderUid or dashboardUid", index+1))
+			}
+			for itemIndex, item := range p.Items {
+				if countSet(item.Team, item.User, item.ServiceAccount, item.Role) != 1 {
+					errStrings = append(errStrings, fmt.Sprintf("permissions item %d entry %d must contain exactly one of team, user, serviceAccount or role", index+1, itemIndex+1))
+				}
+				if item.Role != "" && !org.RoleType(item.Role).IsValid() {
+					errStrings = append(errStrings, fmt.Sprintf("permissions item %d entry %d has invalid role %q", index+1, itemIndex+1, item.Role))
+				}
+				if item.Permission != permissionView && item.Permission != permissionEdit && item.Permission != permissionAdmin {
+					errStrings = append(errStrings, fmt.Sprintf("permissions item %d entry %d has invalid permission %q, expected %s, %s or %s",
+						index+1, itemIndex+1, item.Permission, permissionView, permissionEdit, permissionAdmin))
+				}
+			}
+		}
+		for index, p := range configs[i].DeletePermissions {
+			if (p.FolderUID == "") == (p.DashboardUID == "") {
+				errStrings = append(errStrings, fmt.Sprintf("delete permissions item %d in configuration must contain exactly one of folderUid or dashboardUid", index+1))
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf("%s", strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+func countSet(values ...string) int {
+	count := 0
+	for _, v := range values {
+		if v != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func checkOrgIDAndOrgName(configs []*permissionsAsConfig) {
+	for i := range configs {
+		for _, p := range configs[i].Permissions {
+			p.OrgID = utils.OrgIDOrDefault(p.OrgID, p.OrgName)
+		}
+		for _, p := range configs[i].DeletePermissions {
+			p.OrgID = utils.OrgIDOrDefault(p.OrgID, p.OrgName)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	correctProperties = "./testdata/test-configs/correct-properties"
+	brokenYaml        = "./testdata/test-configs/broken-yaml"
+	missingResource   = "./testdata/test-configs/missing-resource"
+	emptyFolder       = "./testdata/test-configs/empty_folder"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Permissions without folder or dashboard should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(missingResource)
+		require.Error(t, err)
+		require.Equal(t, "permissions item 1 in configuration must contain exactly one of folderUid or dashboardUid", err.Error())
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("PERMISSION_TEAM", "Platform")
+
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+		require.Len(t, cfg[0].Permissions, 2)
+
+		folder := cfg[0].Permissions[0]
+		require.Equal(t, "team-a", folder.FolderUID)
+		require.Equal(t, int64(2), folder.OrgID)
+		require.Equal(t, []*permissionItemFromConfig{
+			{Team: "Platform", Permission: permissionEdit},
+			{User: "alice", Permission: permissionAdmin},
+			{ServiceAccount: "ci", Permission: permissionView},
+			{Role: "Viewer", Permission: permissionView},
+		}, folder.Items)
+
+		dashboard := cfg[0].Permissions[1]
+		require.Equal(t, "overview", dashboard.DashboardUID)
+		require.Equal(t, int64(0), dashboard.OrgID)
+		require.Equal(t, "Org 3", dashboard.OrgName)
+
+		require.Len(t, cfg[0].DeletePermissions, 1)
+		require.Equal(t, "legacy", cfg[0].DeletePermissions[0].FolderUID)
+		require.Equal(t, int64(1), cfg[0].DeletePermissions[0].OrgID)
+	})
+}
@@ -0,0 +1,187 @@
+package permissions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	sa "github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// DryRun validates the provisioning config files in a directory and adds the
+// changes provisioning them would apply to the result, without applying them.
+func DryRun(
+	ctx context.Context,
+	configDirectory string,
+	folderPermissionsService accesscontrol.FolderPermissionsService,
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService,
+	teamService team.Service,
+	userService user.Service,
+	serviceAccountsService sa.Service,
+	orgService org.Service,
+	result *dryrun.Result,
+) error {
+	logger := log.New("provisioning.permissions")
+	pp := PermissionProvisioner{
+		log:                         logger,
+		cfgProvider:                 &configReader{log: logger},
+		folderPermissionsService:    folderPermissionsService,
+		dashboardPermissionsService: dashboardPermissionsService,
+		teamService:                 teamService,
+		userService:                 userService,
+		serviceAccountsService:      serviceAccountsService,
+		orgService:                  orgService,
+	}
+	return pp.dryRun(ctx, configDirectory, result)
+}
+
+func (pp *PermissionProvisioner) dryRun(ctx context.Context, configPath string, result *dryrun.Result) error {
+	files, err := os.ReadDir(configPath)
+	if err != nil {
+		// like readConfig, a missing directory means there is nothing to provision
+		return nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(configPath, file.Name())
+		cfg, err := pp.cfgProvider.parsePermissionConfig(configPath, file)
+		if err != nil {
+			result.AddError(dryrun.ResourcePermission, filename, err)
+			continue
+		}
+
+		cfgs := []*permissionsAsConfig{cfg}
+		if err := validatePermissions(cfgs); err != nil {
+			result.AddError(dryrun.ResourcePermission, filename, err)
+			continue
+		}
+		checkOrgIDAndOrgName(cfgs)
+
+		changes, err := pp.changes(ctx, cfg, filename)
+		if err != nil {
+			if isUnresolved(err) {
+				result.AddError(dryrun.ResourcePermission, filename, err)
+				continue
+			}
+			return err
+		}
+		for _, change := range changes {
+			result.Add(dryrun.ResourcePermission, change)
+		}
+	}
+
+	return nil
+}
+
+func (pp *PermissionProvisioner) changes(ctx context.Context, cfg *permissionsAsConfig, filename string) ([]dryrun.Change, error) {
+	var changes []dryrun.Change
+	for _, p := range cfg.DeletePermissions {
+		orgID, err := pp.orgID(ctx, p.OrgID, p.OrgName)
+		if err != nil {
+			return nil, err
+		}
+
+		service, _, resourceID := pp.resource(p.FolderUID, p.DashboardUID)
+		current, err := pp.managedPermissions(ctx, orgID, service, resourceID)
+		if err != nil {
+			return nil, err
+		}
+		if len(current) == 0 {
+			continue
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionDelete, OrgID: orgID, UID: resourceID, Name: resourceName(p.FolderUID, p.DashboardUID), File: filename})
+	}
+
+	for _, p := range cfg.Permissions {
+		orgID, err := pp.orgID(ctx, p.OrgID, p.OrgName)
+		if err != nil {
+			return nil, err
+		}
+
+		service, kind, resourceID := pp.resource(p.FolderUID, p.DashboardUID)
+		desired := make(map[string]string, len(p.Items))
+		for _, item := range p.Items {
+			cmd, err := pp.command(ctx, orgID, item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to provision permissions for %s %s: %w", kind, resourceID, err)
+			}
+			desired[principal(cmd.UserID, cmd.TeamID, cmd.BuiltinRole)] = cmd.Permission
+		}
+
+		current, err := pp.managedPermissions(ctx, orgID, service, resourceID)
+		if err != nil {
+			return nil, err
+		}
+		if len(current) == 0 {
+			changes = append(changes, dryrun.Change{Action: dryrun.ActionCreate, OrgID: orgID, UID: resourceID, Name: resourceName(p.FolderUID, p.DashboardUID), File: filename})
+			continue
+		}
+
+		fields := dryrun.Fields(dryrun.Field("items", current, desired))
+		if len(fields) == 0 {
+			continue
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionUpdate, OrgID: orgID, UID: resourceID, Name: resourceName(p.FolderUID, p.DashboardUID), File: filename, Fields: fields})
+	}
+
+	return changes, nil
+}
+
+// managedPermissions returns the permission of the principals with managed
+// permissions set directly on the resource, which provisioning replaces.
+func (pp *PermissionProvisioner) managedPermissions(ctx context.Context, orgID int64, service accesscontrol.PermissionsService, resourceID string) (map[string]string, error) {
+	permissions, err := service.GetPermissions(ctx, provisioningUser(orgID), resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]string, len(permissions))
+	for _, permission := range permissions {
+		if !permission.IsManaged || permission.IsInherited {
+			continue
+		}
+		current[principal(permission.UserId, permission.TeamId, permission.BuiltInRole)] = service.MapActions(permission)
+	}
+	return current, nil
+}
+
+// resourceName names the folder or dashboard of the permissions in the changes.
+func resourceName(folderUID, dashboardUID string) string {
+	if folderUID != "" {
+		return "folder " + folderUID
+	}
+	return "dashboard " + dashboardUID
+}
+
+func principal(userID, teamID int64, builtinRole string) string {
+	switch {
+	case userID != 0:
+		return fmt.Sprintf("user:%d", userID)
+	case teamID != 0:
+		return fmt.Sprintf("team:%d", teamID)
+	default:
+		return "role:" + builtinRole
+	}
+}
+
+// isUnresolved returns true when the organization or a principal of a
+// provisioning file doesn't exist.
+func isUnresolved(err error) bool {
+	return errors.Is(err, org.ErrOrgNotFound) ||
+		errors.Is(err, team.ErrTeamNotFound) ||
+		errors.Is(err, user.ErrUserNotFound) ||
+		errors.Is(err, sa.ErrServiceAccountNotFound)
+}
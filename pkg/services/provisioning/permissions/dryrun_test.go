@@ -0,0 +1,85 @@
+package permissions
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("PERMISSION_TEAM", "Platform")
+
+	orgService := orgtest.NewOrgServiceFake()
+	orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+	teamService := &spyTeamService{FakeService: teamtest.NewFakeService(), teams: map[string]int64{"Platform": 5}}
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedUser = &user.User{ID: 10, Login: "alice"}
+	saService := &tests.FakeServiceAccountService{ExpectedServiceAccountID: 20}
+
+	t.Run("should report new, updated and deleted permissions without changing them", func(t *testing.T) {
+		folderPermissions := newSpyPermissionsService()
+		folderPermissions.current["legacy"] = []accesscontrol.ResourcePermission{
+			{TeamId: 5, Actions: []string{permissionView}, IsManaged: true},
+		}
+		folderPermissions.current["team-a"] = []accesscontrol.ResourcePermission{
+			{TeamId: 5, Actions: []string{permissionEdit}, IsManaged: true},
+			{UserId: 10, Actions: []string{permissionAdmin}, IsManaged: true},
+			{UserId: 20, Actions: []string{permissionView}, IsManaged: true},
+			{BuiltInRole: string(org.RoleViewer), Actions: []string{permissionView}, IsManaged: true},
+			{UserId: 11, Actions: []string{permissionView}, IsManaged: true},
+			{BuiltInRole: string(org.RoleAdmin), Actions: []string{permissionAdmin}, IsManaged: true, IsInherited: true},
+		}
+		dashboardPermissions := newSpyPermissionsService()
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, folderPermissions, dashboardPermissions,
+			teamService, userService, saService, orgService, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		file := filepath.Join(correctProperties, "permissions.yaml")
+		require.Equal(t, []dryrun.Change{
+			{Action: dryrun.ActionDelete, OrgID: 1, UID: "legacy", Name: "folder legacy", File: file},
+			{Action: dryrun.ActionUpdate, OrgID: 2, UID: "team-a", Name: "folder team-a", File: file, Fields: []string{"items"}},
+			{Action: dryrun.ActionCreate, OrgID: 3, UID: "overview", Name: "dashboard overview", File: file},
+		}, result.Changes[dryrun.ResourcePermission])
+		require.Empty(t, folderPermissions.set)
+		require.Empty(t, folderPermissions.deleted)
+		require.Empty(t, dashboardPermissions.set)
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), missingResource, newSpyPermissionsService(), newSpyPermissionsService(),
+			teamService, userService, saService, orgService, result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, dryrun.ResourcePermission, result.Errors[0].Resource)
+	})
+
+	t.Run("should report files with unknown teams", func(t *testing.T) {
+		t.Setenv("PERMISSION_TEAM", "Unknown")
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, newSpyPermissionsService(), newSpyPermissionsService(),
+			teamService, userService, saService, orgService, result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Empty(t, result.Changes[dryrun.ResourcePermission])
+	})
+}
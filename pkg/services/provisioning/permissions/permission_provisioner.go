@@ -0,0 +1,213 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	sa "github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the folder and dashboard permissions in those files.
+func Provision(
+	ctx context.Context,
+	configDirectory string,
+	folderPermissionsService accesscontrol.FolderPermissionsService,
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService,
+	teamService team.Service,
+	userService user.Service,
+	serviceAccountsService sa.Service,
+	orgService org.Service,
+	provenanceService provenance.Service,
+) error {
+	logger := log.New("provisioning.permissions")
+	pp := PermissionProvisioner{
+		log:                         logger,
+		cfgProvider:                 &configReader{log: logger},
+		folderPermissionsService:    folderPermissionsService,
+		dashboardPermissionsService: dashboardPermissionsService,
+		teamService:                 teamService,
+		userService:                 userService,
+		serviceAccountsService:      serviceAccountsService,
+		orgService:                  orgService,
+		provenanceService:           provenanceService,
+	}
+	return pp.applyChanges(ctx, configDirectory)
+}
+
+// PermissionProvisioner is responsible for provisioning folder and dashboard
+// permissions based on configuration read by the `configReader`. Provisioned
+// permissions replace all managed permissions set directly on the resource.
+type PermissionProvisioner struct {
+	log                         log.Logger
+	cfgProvider                 *configReader
+	folderPermissionsService    accesscontrol.FolderPermissionsService
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService
+	teamService                 team.Service
+	userService                 user.Service
+	serviceAccountsService      sa.Service
+	orgService                  org.Service
+	provenanceService           provenance.Service
+}
+
+func (pp *PermissionProvisioner) apply(ctx context.Context, cfg *permissionsAsConfig) error {
+	for _, p := range cfg.DeletePermissions {
+		orgID, err := pp.orgID(ctx, p.OrgID, p.OrgName)
+		if err != nil {
+			return err
+		}
+
+		service, kind, resourceID := pp.resource(p.FolderUID, p.DashboardUID)
+		pp.log.Debug("Deleting permissions from configuration", "kind", kind, "uid", resourceID, "orgId", orgID)
+		if err := service.DeleteResourcePermissions(ctx, orgID, resourceID); err != nil {
+			return err
+		}
+		if err := pp.provenanceService.DeleteProvisioned(ctx, orgID, kind, resourceID); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range cfg.Permissions {
+		orgID, err := pp.orgID(ctx, p.OrgID, p.OrgName)
+		if err != nil {
+			return err
+		}
+
+		service, kind, resourceID := pp.resource(p.FolderUID, p.DashboardUID)
+		commands, err := pp.commands(ctx, orgID, service, resourceID, p.Items)
+		if err != nil {
+			return fmt.Errorf("failed to provision permissions for %s %s: %w", kind, resourceID, err)
+		}
+
+		pp.log.Debug("Setting permissions from configuration", "kind", kind, "uid", resourceID, "orgId", orgID)
+		if _, err := service.SetPermissions(ctx, orgID, resourceID, commands...); err != nil {
+			return err
+		}
+		if err := pp.provenanceService.SetProvisioned(ctx, orgID, kind, resourceID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commands resolves the configured items and removes managed permissions
+// on the resource that are not part of the configuration.
+func (pp *PermissionProvisioner) commands(ctx context.Context, orgID int64, service accesscontrol.PermissionsService,
+	resourceID string, items []*permissionItemFromConfig) ([]accesscontrol.SetResourcePermissionCommand, error) {
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(items))
+	desired := make(map[accesscontrol.SetResourcePermissionCommand]bool, len(items))
+	for _, item := range items {
+		cmd, err := pp.command(ctx, orgID, item)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, cmd)
+		cmd.Permission = ""
+		desired[cmd] = true
+	}
+
+	current, err := service.GetPermissions(ctx, provisioningUser(orgID), resourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range current {
+		if !permission.IsManaged || permission.IsInherited {
+			continue
+		}
+		cmd := accesscontrol.SetResourcePermissionCommand{
+			UserID:      permission.UserId,
+			TeamID:      permission.TeamId,
+			BuiltinRole: permission.BuiltInRole,
+		}
+		if !desired[cmd] {
+			commands = append(commands, cmd)
+		}
+	}
+
+	return commands, nil
+}
+
+func (pp *PermissionProvisioner) command(ctx context.Context, orgID int64, item *permissionItemFromConfig) (accesscontrol.SetResourcePermissionCommand, error) {
+	cmd := accesscontrol.SetResourcePermissionCommand{Permission: item.Permission}
+	switch {
+	case item.Team != "":
+		res, err := pp.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+			OrgID:        orgID,
+			Name:         item.Team,
+			Limit:        1,
+			Page:         1,
+			SignedInUser: provisioningUser(orgID),
+		})
+		if err != nil {
+			return cmd, err
+		}
+		if len(res.Teams) == 0 {
+			return cmd, fmt.Errorf("team %q: %w", item.Team, team.ErrTeamNotFound)
+		}
+		cmd.TeamID = res.Teams[0].ID
+	case item.User != "":
+		u, err := pp.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: item.User})
+		if err != nil {
+			return cmd, fmt.Errorf("user %q: %w", item.User, err)
+		}
+		cmd.UserID = u.ID
+	case item.ServiceAccount != "":
+		id, err := pp.serviceAccountsService.RetrieveServiceAccountIdByName(ctx, orgID, item.ServiceAccount)
+		if err != nil {
+			return cmd, fmt.Errorf("service account %q: %w", item.ServiceAccount, err)
+		}
+		cmd.UserID = id
+	default:
+		cmd.BuiltinRole = item.Role
+	}
+	return cmd, nil
+}
+
+func (pp *PermissionProvisioner) resource(folderUID, dashboardUID string) (accesscontrol.PermissionsService, provenance.Kind, string) {
+	if folderUID != "" {
+		return pp.folderPermissionsService, provenance.KindFolderPermissions, folderUID
+	}
+	return pp.dashboardPermissionsService, provenance.KindDashboardPermissions, dashboardUID
+}
+
+func (pp *PermissionProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := pp.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := pp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pp *PermissionProvisioner) orgID(ctx context.Context, orgID int64, orgName string) (int64, error) {
+	if orgID != 0 {
+		return orgID, nil
+	}
+
+	res, err := pp.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: orgName})
+	if err != nil {
+		return 0, err
+	}
+	return res.ID, nil
+}
+
+func provisioningUser(orgID int64) identity.Requester {
+	return accesscontrol.BackgroundUser("permission_provisioning", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+	})
+}
@@ -0,0 +1,129 @@
+package permissions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestPermissionProvisioner(t *testing.T) {
+	t.Setenv("PERMISSION_TEAM", "Platform")
+
+	orgService := orgtest.NewOrgServiceFake()
+	orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+	teamService := &spyTeamService{FakeService: teamtest.NewFakeService(), teams: map[string]int64{"Platform": 5}}
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedUser = &user.User{ID: 10, Login: "alice"}
+	saService := &tests.FakeServiceAccountService{ExpectedServiceAccountID: 20}
+
+	t.Run("Sets permissions and removes managed permissions not in configuration", func(t *testing.T) {
+		folderPermissions := newSpyPermissionsService()
+		folderPermissions.current["team-a"] = []accesscontrol.ResourcePermission{
+			{TeamId: 5, IsManaged: true},
+			{UserId: 11, IsManaged: true},
+			{BuiltInRole: string(org.RoleEditor), IsManaged: true},
+			{BuiltInRole: string(org.RoleAdmin), IsManaged: true, IsInherited: true},
+			{RoleName: "fixed:folders:reader"},
+		}
+		dashboardPermissions := newSpyPermissionsService()
+		provenanceService := provenancetest.NewFakeService()
+
+		err := Provision(context.Background(), correctProperties, folderPermissions, dashboardPermissions,
+			teamService, userService, saService, orgService, provenanceService)
+		require.NoError(t, err)
+
+		require.ElementsMatch(t, []accesscontrol.SetResourcePermissionCommand{
+			{TeamID: 5, Permission: permissionEdit},
+			{UserID: 10, Permission: permissionAdmin},
+			{UserID: 20, Permission: permissionView},
+			{BuiltinRole: string(org.RoleViewer), Permission: permissionView},
+			{UserID: 11},
+			{BuiltinRole: string(org.RoleEditor)},
+		}, folderPermissions.set[2]["team-a"])
+		require.Equal(t, []accesscontrol.SetResourcePermissionCommand{
+			{BuiltinRole: string(org.RoleEditor), Permission: permissionEdit},
+		}, dashboardPermissions.set[3]["overview"])
+		require.Equal(t, []string{"legacy"}, folderPermissions.deleted)
+
+		provisioned, err := provenanceService.IsProvisioned(context.Background(), 2, provenance.KindFolderPermissions, "team-a")
+		require.NoError(t, err)
+		require.True(t, provisioned)
+		provisioned, err = provenanceService.IsProvisioned(context.Background(), 3, provenance.KindDashboardPermissions, "overview")
+		require.NoError(t, err)
+		require.True(t, provisioned)
+	})
+
+	t.Run("Returns an error for unknown teams", func(t *testing.T) {
+		t.Setenv("PERMISSION_TEAM", "Unknown")
+
+		err := Provision(context.Background(), correctProperties, newSpyPermissionsService(), newSpyPermissionsService(),
+			teamService, userService, saService, orgService, provenancetest.NewFakeService())
+		require.ErrorIs(t, err, team.ErrTeamNotFound)
+	})
+}
+
+type spyTeamService struct {
+	*teamtest.FakeService
+	teams map[string]int64
+}
+
+func (s *spyTeamService) SearchTeams(ctx context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	res := team.SearchTeamQueryResult{}
+	if id, ok := s.teams[query.Name]; ok {
+		res.Teams = append(res.Teams, &team.TeamDTO{ID: id, OrgID: query.OrgID, Name: query.Name})
+	}
+	return res, nil
+}
+
+type spyPermissionsService struct {
+	*actest.FakePermissionsService
+	current map[string][]accesscontrol.ResourcePermission
+	set     map[int64]map[string][]accesscontrol.SetResourcePermissionCommand
+	deleted []string
+}
+
+func newSpyPermissionsService() *spyPermissionsService {
+	return &spyPermissionsService{
+		FakePermissionsService: &actest.FakePermissionsService{},
+		current:                map[string][]accesscontrol.ResourcePermission{},
+		set:                    map[int64]map[string][]accesscontrol.SetResourcePermissionCommand{},
+	}
+}
+
+func (s *spyPermissionsService) GetPermissions(ctx context.Context, user identity.Requester, resourceID string) ([]accesscontrol.ResourcePermission, error) {
+	return s.current[resourceID], nil
+}
+
+func (s *spyPermissionsService) SetPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
+	if s.set[orgID] == nil {
+		s.set[orgID] = map[string][]accesscontrol.SetResourcePermissionCommand{}
+	}
+	s.set[orgID][resourceID] = commands
+	return nil, nil
+}
+
+func (s *spyPermissionsService) DeleteResourcePermissions(ctx context.Context, orgID int64, resourceID string) error {
+	s.deleted = append(s.deleted, resourceID)
+	return nil
+}
+
+func (s *spyPermissionsService) MapActions(permission accesscontrol.ResourcePermission) string {
+	if len(permission.Actions) == 0 {
+		return ""
+	}
+	return permission.Actions[0]
+}
@@ -0,0 +1,115 @@
+package permissions
+
+import (
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+const (
+	permissionView  = "View"
+	permissionEdit  = "Edit"
+	permissionAdmin = "Admin"
+)
+
+// permissionsAsConfig is a normalized data object for permissions config data. Any config version should be mappable
+// to this type.
+type permissionsAsConfig struct {
+	Permissions       []*permissionsFromConfig
+	DeletePermissions []*deletePermissionsConfig
+}
+
+// permissionsFromConfig lists the permissions of either a folder or a dashboard.
+type permissionsFromConfig struct {
+	FolderUID    string
+	DashboardUID string
+	OrgID        int64
+	OrgName      string
+	Items        []*permissionItemFromConfig
+}
+
+// permissionItemFromConfig grants a permission to exactly one of a team,
+// a user, a service account or a basic role.
+type permissionItemFromConfig struct {
+	Team           string
+	User           string
+	ServiceAccount string
+	Role           string
+	Permission     string
+}
+
+type deletePermissionsConfig struct {
+	FolderUID    string
+	DashboardUID string
+	OrgID        int64
+	OrgName      string
+}
+
+type permissionsFromConfigV1 struct {
+	FolderUID    values.StringValue            `json:"folderUid" yaml:"folderUid"`
+	DashboardUID values.StringValue            `json:"dashboardUid" yaml:"dashboardUid"`
+	OrgID        values.Int64Value             `json:"orgId" yaml:"orgId"`
+	OrgName      values.StringValue            `json:"orgName" yaml:"orgName"`
+	Items        []*permissionItemFromConfigV1 `json:"items" yaml:"items"`
+}
+
+type permissionItemFromConfigV1 struct {
+	Team           values.StringValue `json:"team" yaml:"team"`
+	User           values.StringValue `json:"user" yaml:"user"`
+	ServiceAccount values.StringValue `json:"serviceAccount" yaml:"serviceAccount"`
+	Role           values.StringValue `json:"role" yaml:"role"`
+	Permission     values.StringValue `json:"permission" yaml:"permission"`
+}
+
+type deletePermissionsConfigV1 struct {
+	FolderUID    values.StringValue `json:"folderUid" yaml:"folderUid"`
+	DashboardUID values.StringValue `json:"dashboardUid" yaml:"dashboardUid"`
+	OrgID        values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName      values.StringValue `json:"orgName" yaml:"orgName"`
+}
+
+// permissionsAsConfigV1 is a mapping for version 1 configs. This is mapped to its normalised version.
+type permissionsAsConfigV1 struct {
+	APIVersion        values.Int64Value            `json:"apiVersion" yaml:"apiVersion"`
+	Permissions       []*permissionsFromConfigV1   `json:"permissions" yaml:"permissions"`
+	DeletePermissions []*deletePermissionsConfigV1 `json:"deletePermissions" yaml:"deletePermissions"`
+}
+
+// mapToPermissionsFromConfig maps config syntax to a normalized permissionsAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *permissionsAsConfigV1) mapToPermissionsFromConfig() *permissionsAsConfig {
+	r := &permissionsAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, p := range cfg.Permissions {
+		items := make([]*permissionItemFromConfig, 0, len(p.Items))
+		for _, item := range p.Items {
+			items = append(items, &permissionItemFromConfig{
+				Team:           item.Team.Value(),
+				User:           item.User.Value(),
+				ServiceAccount: item.ServiceAccount.Value(),
+				Role:           item.Role.Value(),
+				Permission:     item.Permission.Value(),
+			})
+		}
+
+		r.Permissions = append(r.Permissions, &permissionsFromConfig{
+			FolderUID:    p.FolderUID.Value(),
+			DashboardUID: p.DashboardUID.Value(),
+			OrgID:        p.OrgID.Value(),
+			OrgName:      p.OrgName.Value(),
+			Items:        items,
+		})
+	}
+
+	for _, p := range cfg.DeletePermissions {
+		r.DeletePermissions = append(r.DeletePermissions, &deletePermissionsConfig{
+			FolderUID:    p.FolderUID.Value(),
+			DashboardUID: p.DashboardUID.Value(),
+			OrgID:        p.OrgID.Value(),
+			OrgName:      p.OrgName.Value(),
+		})
+	}
+
+	return r
+}
@@ -0,0 +1,115 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
+
+// DryRun validates the provisioning config files in a directory and adds the
+// changes provisioning them would apply to the result, without applying them.
+func DryRun(ctx context.Context, configDirectory string, pluginStore pluginstore.Store, pluginSettings pluginsettings.Service, orgService org.Service, result *dryrun.Result) error {
+	logger := log.New("provisioning.plugins")
+	cr := &configReaderImpl{log: logger, pluginStore: pluginStore}
+	ap := PluginProvisioner{
+		log:            logger,
+		cfgProvider:    cr,
+		pluginSettings: pluginSettings,
+		orgService:     orgService,
+	}
+	return ap.dryRun(ctx, configDirectory, cr, result)
+}
+
+func (ap *PluginProvisioner) dryRun(ctx context.Context, configPath string, cr *configReaderImpl, result *dryrun.Result) error {
+	files, err := os.ReadDir(configPath)
+	if err != nil {
+		// like readConfig, a missing directory means there is nothing to provision
+		return nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(configPath, file.Name())
+		cfg, err := cr.parsePluginConfig(configPath, file)
+		if err != nil {
+			result.AddError(dryrun.ResourcePlugin, filename, err)
+			continue
+		}
+
+		apps := []*pluginsAsConfig{cfg}
+		if err := validateRequiredField(apps); err != nil {
+			result.AddError(dryrun.ResourcePlugin, filename, err)
+			continue
+		}
+		checkOrgIDAndOrgName(apps)
+		if err := cr.validatePluginsConfig(ctx, apps); err != nil {
+			result.AddError(dryrun.ResourcePlugin, filename, err)
+			continue
+		}
+
+		for _, app := range cfg.Apps {
+			change, err := ap.appChange(ctx, app)
+			if err != nil {
+				if errors.Is(err, org.ErrOrgNotFound) {
+					result.AddError(dryrun.ResourcePlugin, filename, err)
+					continue
+				}
+				return err
+			}
+			if change != nil {
+				change.File = filename
+				result.Add(dryrun.ResourcePlugin, *change)
+			}
+		}
+	}
+
+	return nil
+}
+
+// appChange returns the change provisioning an app would apply, or nil when
+// the app settings are up to date. Secure JSON data is encrypted, it is not
+// compared.
+func (ap *PluginProvisioner) appChange(ctx context.Context, app *appFromConfig) (*dryrun.Change, error) {
+	orgID := app.OrgID
+	if orgID == 0 && app.OrgName != "" {
+		res, err := ap.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: app.OrgName})
+		if err != nil {
+			return nil, err
+		}
+		orgID = res.ID
+	} else if orgID < 0 {
+		orgID = 1
+	}
+
+	ps, err := ap.pluginSettings.GetPluginSettingByPluginID(ctx, &pluginsettings.GetByPluginIDArgs{
+		OrgID:    orgID,
+		PluginID: app.PluginID,
+	})
+	if err != nil {
+		if !errors.Is(err, pluginsettings.ErrPluginSettingNotFound) {
+			return nil, err
+		}
+		return &dryrun.Change{Action: dryrun.ActionCreate, OrgID: orgID, UID: app.PluginID, Name: app.PluginID}, nil
+	}
+
+	fields := dryrun.Fields(
+		dryrun.Field("enabled", ps.Enabled, app.Enabled),
+		dryrun.Field("pinned", ps.Pinned, app.Pinned),
+		dryrun.Field("jsonData", ps.JSONData, app.JSONData),
+	)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return &dryrun.Change{Action: dryrun.ActionUpdate, OrgID: orgID, UID: app.PluginID, Name: app.PluginID, Fields: fields}, nil
+}
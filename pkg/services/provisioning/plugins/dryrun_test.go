@@ -0,0 +1,57 @@
+package plugins
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
+
+func TestDryRun(t *testing.T) {
+	pluginStore := &pluginstore.FakePluginStore{
+		PluginList: []pluginstore.Plugin{
+			{JSONData: plugins.JSONData{ID: "test-plugin"}},
+			{JSONData: plugins.JSONData{ID: "test-plugin-2"}},
+		},
+	}
+
+	t.Run("should report new and updated apps without updating them", func(t *testing.T) {
+		t.Setenv("ENABLE_PLUGIN_VAR", "test-plugin")
+		store := &mockStore{}
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedOrg = &org.Org{ID: 4}
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, pluginStore, store, orgService, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		require.Equal(t, dryrun.Summary{Create: 3, Update: 1}, result.Summary[dryrun.ResourcePlugin])
+		file := filepath.Join(correctProperties, "correct-properties.yaml")
+		require.Equal(t, []dryrun.Change{
+			{Action: dryrun.ActionUpdate, OrgID: 2, UID: "test-plugin", Name: "test-plugin", File: file, Fields: []string{"enabled"}},
+			{Action: dryrun.ActionCreate, OrgID: 3, UID: "test-plugin-2", Name: "test-plugin-2", File: file},
+			{Action: dryrun.ActionCreate, OrgID: 4, UID: "test-plugin", Name: "test-plugin", File: file},
+			{Action: dryrun.ActionCreate, OrgID: 1, UID: "test-plugin-2", Name: "test-plugin-2", File: file},
+		}, result.Changes[dryrun.ResourcePlugin])
+		require.Empty(t, store.updateRequests)
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), unknownApp, pluginStore, &mockStore{}, orgtest.NewOrgServiceFake(), result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, dryrun.ResourcePlugin, result.Errors[0].Resource)
+		require.Empty(t, result.Changes[dryrun.ResourcePlugin])
+	})
+}
@@ -0,0 +1,42 @@
+package provenance
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// Kind is the kind of a provisioned resource.
+type Kind string
+
+const (
+	KindTeam                 Kind = "team"
+	KindFolder               Kind = "folder"
+	KindFolderPermissions    Kind = "folder_permissions"
+	KindDashboardPermissions Kind = "dashboard_permissions"
+	KindServiceAccount       Kind = "service_account"
+)
+
+var ErrProvisioned = errutil.BadRequest("provisioning.provisioned-resource", errutil.WithPublicMessage("Provisioned resources can not be changed, update the provisioning files instead"))
+
+// Service records the resources created from provisioning files, which can
+// not be changed through the API. Resources are identified by their kind and
+// key, the id or uid the API uses for them.
+type Service interface {
+	SetProvisioned(ctx context.Context, orgID int64, kind Kind, key string) error
+	DeleteProvisioned(ctx context.Context, orgID int64, kind Kind, key string) error
+	IsProvisioned(ctx context.Context, orgID int64, kind Kind, key string) (bool, error)
+}
+
+// EnsureNotProvisioned returns ErrProvisioned when the resource was
+// created from provisioning files.
+func EnsureNotProvisioned(ctx context.Context, s Service, orgID int64, kind Kind, key string) error {
+	provisioned, err := s.IsProvisioned(ctx, orgID, kind, key)
+	if err != nil {
+		return err
+	}
+	if provisioned {
+		return ErrProvisioned.Errorf("%s %s is provisioned", kind, key)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package provenanceimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+)
+
+type provisionedResource struct {
+	ID          int64           `xorm:"pk autoincr 'id'"`
+	OrgID       int64           `xorm:"org_id"`
+	Kind        provenance.Kind `xorm:"kind"`
+	ResourceKey string          `xorm:"resource_key"`
+}
+
+func (provisionedResource) TableName() string {
+	return "provisioned_resource"
+}
+
+type Service struct {
+	db db.DB
+}
+
+var _ provenance.Service = &Service{}
+
+func ProvideService(db db.DB) *Service {
+	return &Service{db: db}
+}
+
+func (s *Service) SetProvisioned(ctx context.Context, orgID int64, kind provenance.Kind, key string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		resource := &provisionedResource{OrgID: orgID, Kind: kind, ResourceKey: key}
+		exists, err := sess.Where("org_id = ? AND kind = ? AND resource_key = ?", orgID, kind, key).Exist(&provisionedResource{})
+		if err != nil || exists {
+			return err
+		}
+		_, err = sess.Insert(resource)
+		return err
+	})
+}
+
+func (s *Service) DeleteProvisioned(ctx context.Context, orgID int64, kind provenance.Kind, key string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND kind = ? AND resource_key = ?", orgID, kind, key).Delete(&provisionedResource{})
+		return err
+	})
+}
+
+func (s *Service) IsProvisioned(ctx context.Context, orgID int64, kind provenance.Kind, key string) (bool, error) {
+	var exists bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		exists, err = sess.Where("org_id = ? AND kind = ? AND resource_key = ?", orgID, kind, key).Exist(&provisionedResource{})
+		return err
+	})
+	return exists, err
+}
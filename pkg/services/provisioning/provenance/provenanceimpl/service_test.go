@@ -0,0 +1,52 @@
+package provenanceimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationProvenanceService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := ProvideService(db.InitTestDB(t))
+
+	provisioned, err := s.IsProvisioned(ctx, 1, provenance.KindTeam, "1")
+	require.NoError(t, err)
+	require.False(t, provisioned)
+
+	// setting a resource twice is a no-op
+	require.NoError(t, s.SetProvisioned(ctx, 1, provenance.KindTeam, "1"))
+	require.NoError(t, s.SetProvisioned(ctx, 1, provenance.KindTeam, "1"))
+
+	provisioned, err = s.IsProvisioned(ctx, 1, provenance.KindTeam, "1")
+	require.NoError(t, err)
+	require.True(t, provisioned)
+
+	for _, other := range []struct {
+		orgID int64
+		kind  provenance.Kind
+	}{{2, provenance.KindTeam}, {1, provenance.KindServiceAccount}} {
+		provisioned, err = s.IsProvisioned(ctx, other.orgID, other.kind, "1")
+		require.NoError(t, err)
+		require.False(t, provisioned)
+	}
+
+	err = provenance.EnsureNotProvisioned(ctx, s, 1, provenance.KindTeam, "1")
+	require.ErrorIs(t, err, provenance.ErrProvisioned)
+
+	require.NoError(t, s.DeleteProvisioned(ctx, 1, provenance.KindTeam, "1"))
+	require.NoError(t, provenance.EnsureNotProvisioned(ctx, s, 1, provenance.KindTeam, "1"))
+}
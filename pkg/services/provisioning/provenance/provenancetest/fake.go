@@ -0,0 +1,48 @@
+package provenancetest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+)
+
+var _ provenance.Service = &FakeService{}
+
+type resource struct {
+	orgID int64
+	kind  provenance.Kind
+	key   string
+}
+
+// FakeService keeps the provisioned resources in memory.
+type FakeService struct {
+	resources     map[resource]bool
+	ExpectedError error
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{resources: map[resource]bool{}}
+}
+
+func (f *FakeService) SetProvisioned(_ context.Context, orgID int64, kind provenance.Kind, key string) error {
+	if f.ExpectedError != nil {
+		return f.ExpectedError
+	}
+	f.resources[resource{orgID, kind, key}] = true
+	return nil
+}
+
+func (f *FakeService) DeleteProvisioned(_ context.Context, orgID int64, kind provenance.Kind, key string) error {
+	if f.ExpectedError != nil {
+		return f.ExpectedError
+	}
+	delete(f.resources, resource{orgID, kind, key})
+	return nil
+}
+
+func (f *FakeService) IsProvisioned(_ context.Context, orgID int64, kind provenance.Kind, key string) (bool, error) {
+	if f.ExpectedError != nil {
+		return false, f.ExpectedError
+	}
+	return f.resources[resource{orgID, kind, key}], nil
+}
@@ -26,11 +26,24 @@ import (
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	prov_folders "github.com/grafana/grafana/pkg/services/provisioning/folders"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
+	prov_permissions "github.com/grafana/grafana/pkg/services/provisioning/permissions"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	prov_reports "github.com/grafana/grafana/pkg/services/provisioning/reports"
+	prov_roles "github.com/grafana/grafana/pkg/services/provisioning/roles"
+	prov_serviceaccounts "github.com/grafana/grafana/pkg/services/provisioning/serviceaccounts"
+	prov_teams "github.com/grafana/grafana/pkg/services/provisioning/teams"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/reports"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -52,6 +65,17 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	reportService reports.Service,
+	teamService team.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService,
+	acService accesscontrol.Service,
+	userService user.Service,
+	serviceAccountsService serviceaccounts.Service,
+	folderPermissionsService accesscontrol.FolderPermissionsService,
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService,
+	provenanceService provenance.Service,
+	roleService accesscontrol.RoleService,
+	teamSyncService teamsync.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -65,6 +89,12 @@ func ProvideService(
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
 		provisionAlerting:            prov_alerting.Provision,
+		provisionReports:             prov_reports.Provision,
+		provisionTeams:               prov_teams.Provision,
+		provisionServiceAccounts:     prov_serviceaccounts.Provision,
+		provisionFolders:             prov_folders.Provision,
+		provisionPermissions:         prov_permissions.Provision,
+		provisionRoles:               prov_roles.Provision,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
 		datasourceService:            datasourceService,
@@ -77,6 +107,17 @@ func ProvideService(
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
 		folderService:                folderService,
+		reportService:                reportService,
+		teamService:                  teamService,
+		teamPermissionsService:       teamPermissionsService,
+		acService:                    acService,
+		userService:                  userService,
+		serviceAccountsService:       serviceAccountsService,
+		folderPermissionsService:     folderPermissionsService,
+		dashboardPermissionsService:  dashboardPermissionsService,
+		provenanceService:            provenanceService,
+		roleService:                  roleService,
+		teamSyncService:              teamSyncService,
 	}
 	return s, nil
 }
@@ -89,8 +130,16 @@ type ProvisioningService interface {
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	ProvisionAlerting(ctx context.Context) error
+	ProvisionReports(ctx context.Context) error
+	ProvisionTeams(ctx context.Context) error
+	ProvisionServiceAccounts(ctx context.Context) error
+	ProvisionFolders(ctx context.Context) error
+	ProvisionPermissions(ctx context.Context) error
+	ProvisionRoles(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	DryRun(ctx context.Context) (*dryrun.Result, error)
+	HandleDashboardGitWebhook(name string, payload []byte, signature string, token string) error
 }
 
 // Add a public constructor for overriding service to be able to instantiate OSS as fallback
@@ -137,6 +186,12 @@ type ProvisioningServiceImpl struct {
 	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionReports             func(context.Context, string, reports.Service, org.Service) error
+	provisionTeams               func(context.Context, string, team.Service, accesscontrol.TeamPermissionsService, accesscontrol.Service, user.Service, org.Service, provenance.Service, teamsync.Service) error
+	provisionServiceAccounts     func(context.Context, string, serviceaccounts.Service, org.Service, provenance.Service) error
+	provisionFolders             func(context.Context, string, folder.Service, dashboardservice.DashboardProvisioningService, org.Service, provenance.Service) error
+	provisionPermissions         func(context.Context, string, accesscontrol.FolderPermissionsService, accesscontrol.DashboardPermissionsService, team.Service, user.Service, serviceaccounts.Service, org.Service, provenance.Service) error
+	provisionRoles               func(context.Context, string, accesscontrol.RoleService, team.Service) error
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
@@ -148,6 +203,17 @@ type ProvisioningServiceImpl struct {
 	quotaService                 quota.Service
 	secretService                secrets.Service
 	folderService                folder.Service
+	reportService                reports.Service
+	teamService                  team.Service
+	teamPermissionsService       accesscontrol.TeamPermissionsService
+	acService                    accesscontrol.Service
+	userService                  user.Service
+	serviceAccountsService       serviceaccounts.Service
+	folderPermissionsService     accesscontrol.FolderPermissionsService
+	dashboardPermissionsService  accesscontrol.DashboardPermissionsService
+	provenanceService            provenance.Service
+	roleService                  accesscontrol.RoleService
+	teamSyncService              teamsync.Service
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -175,6 +241,37 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	err = ps.ProvisionReports(ctx)
+	if err != nil {
+		ps.log.Error("Failed to provision reports", "error", err)
+		return err
+	}
+
+	err = ps.ProvisionTeams(ctx)
+	if err != nil {
+		ps.log.Error("Failed to provision teams", "error", err)
+		return err
+	}
+
+	// Roles are provisioned after teams so they can be assigned to them
+	err = ps.ProvisionRoles(ctx)
+	if err != nil {
+		ps.log.Error("Failed to provision roles", "error", err)
+		return err
+	}
+
+	err = ps.ProvisionServiceAccounts(ctx)
+	if err != nil {
+		ps.log.Error("Failed to provision service accounts", "error", err)
+		return err
+	}
+
+	err = ps.ProvisionFolders(ctx)
+	if err != nil {
+		ps.log.Error("Failed to provision folders", "error", err)
+		return err
+	}
+
 	return nil
 }
 
@@ -188,6 +285,14 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 		ps.searchService.TriggerReIndex()
 	}
 
+	// Permissions are provisioned after dashboards so they can refer to
+	// provisioned dashboards.
+	err = ps.ProvisionPermissions(ctx)
+	if err != nil {
+		ps.log.Error("Failed to provision permissions", "error", err)
+		return err
+	}
+
 	for {
 		// Wait for unlock. This is tied to new dashboardProvisioner to be instantiated before we start polling.
 		ps.mutex.Lock()
@@ -264,6 +369,10 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
+	return ps.provisionAlerting(ctx, ps.alertingProvisionerConfig())
+}
+
+func (ps *ProvisioningServiceImpl) alertingProvisionerConfig() prov_alerting.ProvisionerConfig {
 	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
 	st := store.DBstore{
 		Cfg:              ps.Cfg.UnifiedAlerting,
@@ -289,7 +398,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
 	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
-	cfg := prov_alerting.ProvisionerConfig{
+	return prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
 		DashboardService:           ps.dashboardService,
@@ -299,7 +408,176 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
 	}
-	return ps.provisionAlerting(ctx, cfg)
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionReports(ctx context.Context) error {
+	if ps.provisionReports == nil || !ps.Cfg.ReportsEnabled {
+		return nil
+	}
+
+	reportsPath := filepath.Join(ps.Cfg.ProvisioningPath, "reports")
+	if err := ps.provisionReports(ctx, reportsPath, ps.reportService, ps.orgService); err != nil {
+		err = fmt.Errorf("%v: %w", "Report provisioning error", err)
+		ps.log.Error("Failed to provision reports", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionTeams(ctx context.Context) error {
+	if ps.provisionTeams == nil {
+		return nil
+	}
+
+	teamsPath := filepath.Join(ps.Cfg.ProvisioningPath, "teams")
+	if err := ps.provisionTeams(ctx, teamsPath, ps.teamService, ps.teamPermissionsService, ps.acService, ps.userService, ps.orgService, ps.provenanceService, ps.teamSyncService); err != nil {
+		err = fmt.Errorf("%v: %w", "Team provisioning error", err)
+		ps.log.Error("Failed to provision teams", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionServiceAccounts(ctx context.Context) error {
+	if ps.provisionServiceAccounts == nil {
+		return nil
+	}
+
+	serviceAccountsPath := filepath.Join(ps.Cfg.ProvisioningPath, "serviceaccounts")
+	if err := ps.provisionServiceAccounts(ctx, serviceAccountsPath, ps.serviceAccountsService, ps.orgService, ps.provenanceService); err != nil {
+		err = fmt.Errorf("%v: %w", "Service account provisioning error", err)
+		ps.log.Error("Failed to provision service accounts", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionFolders(ctx context.Context) error {
+	if ps.provisionFolders == nil {
+		return nil
+	}
+
+	foldersPath := filepath.Join(ps.Cfg.ProvisioningPath, "folders")
+	if err := ps.provisionFolders(ctx, foldersPath, ps.folderService, ps.dashboardProvisioningService, ps.orgService, ps.provenanceService); err != nil {
+		err = fmt.Errorf("%v: %w", "Folder provisioning error", err)
+		ps.log.Error("Failed to provision folders", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionPermissions(ctx context.Context) error {
+	if ps.provisionPermissions == nil {
+		return nil
+	}
+
+	permissionsPath := filepath.Join(ps.Cfg.ProvisioningPath, "permissions")
+	if err := ps.provisionPermissions(ctx, permissionsPath, ps.folderPermissionsService, ps.dashboardPermissionsService,
+		ps.teamService, ps.userService, ps.serviceAccountsService, ps.orgService, ps.provenanceService); err != nil {
+		err = fmt.Errorf("%v: %w", "Permission provisioning error", err)
+		ps.log.Error("Failed to provision permissions", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionRoles(ctx context.Context) error {
+	if ps.provisionRoles == nil {
+		return nil
+	}
+
+	rolesPath := filepath.Join(ps.Cfg.ProvisioningPath, "access-control")
+	if err := ps.provisionRoles(ctx, rolesPath, ps.roleService, ps.teamService); err != nil {
+		err = fmt.Errorf("%v: %w", "Role provisioning error", err)
+		ps.log.Error("Failed to provision roles", "error", err)
+		return err
+	}
+	return nil
+}
+
+// DryRun validates the provisioning files of the data sources, plugins,
+// dashboards, alerting resources, reports, teams, roles, service accounts,
+// folders and permissions, and returns the changes provisioning them would
+// apply, without applying them.
+func (ps *ProvisioningServiceImpl) DryRun(ctx context.Context) (*dryrun.Result, error) {
+	result := dryrun.NewResult()
+
+	datasourcePath := filepath.Join(ps.Cfg.ProvisioningPath, "datasources")
+	if err := datasources.DryRun(ctx, datasourcePath, ps.datasourceService, ps.orgService, result); err != nil {
+		return nil, fmt.Errorf("%v: %w", "Datasource provisioning dry-run error", err)
+	}
+
+	appPath := filepath.Join(ps.Cfg.ProvisioningPath, "plugins")
+	if err := plugins.DryRun(ctx, appPath, ps.pluginStore, ps.pluginsSettings, ps.orgService, result); err != nil {
+		return nil, fmt.Errorf("%v: %w", "app provisioning dry-run error", err)
+	}
+
+	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
+	if err := dashboards.DryRun(ctx, dashboardPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService, ps.folderService, result); err != nil {
+		return nil, fmt.Errorf("%v: %w", "Dashboard provisioning dry-run error", err)
+	}
+
+	if err := prov_alerting.DryRun(ctx, ps.alertingProvisionerConfig(), result); err != nil {
+		return nil, fmt.Errorf("%v: %w", "Alerting provisioning dry-run error", err)
+	}
+
+	// like their Provision methods, the provisioners that aren't set are skipped
+	if ps.provisionReports != nil && ps.Cfg.ReportsEnabled {
+		reportsPath := filepath.Join(ps.Cfg.ProvisioningPath, "reports")
+		if err := prov_reports.DryRun(ctx, reportsPath, ps.reportService, ps.orgService, result); err != nil {
+			return nil, fmt.Errorf("%v: %w", "Report provisioning dry-run error", err)
+		}
+	}
+
+	if ps.provisionTeams != nil {
+		teamsPath := filepath.Join(ps.Cfg.ProvisioningPath, "teams")
+		if err := prov_teams.DryRun(ctx, teamsPath, ps.teamService, ps.userService, ps.orgService, ps.teamSyncService, result); err != nil {
+			return nil, fmt.Errorf("%v: %w", "Team provisioning dry-run error", err)
+		}
+	}
+
+	if ps.provisionRoles != nil {
+		rolesPath := filepath.Join(ps.Cfg.ProvisioningPath, "access-control")
+		if err := prov_roles.DryRun(ctx, rolesPath, ps.roleService, ps.teamService, result); err != nil {
+			return nil, fmt.Errorf("%v: %w", "Role provisioning dry-run error", err)
+		}
+	}
+
+	if ps.provisionServiceAccounts != nil {
+		serviceAccountsPath := filepath.Join(ps.Cfg.ProvisioningPath, "serviceaccounts")
+		if err := prov_serviceaccounts.DryRun(ctx, serviceAccountsPath, ps.serviceAccountsService, ps.orgService, result); err != nil {
+			return nil, fmt.Errorf("%v: %w", "Service account provisioning dry-run error", err)
+		}
+	}
+
+	if ps.provisionFolders != nil {
+		foldersPath := filepath.Join(ps.Cfg.ProvisioningPath, "folders")
+		if err := prov_folders.DryRun(ctx, foldersPath, ps.folderService, ps.orgService, result); err != nil {
+			return nil, fmt.Errorf("%v: %w", "Folder provisioning dry-run error", err)
+		}
+	}
+
+	if ps.provisionPermissions != nil {
+		permissionsPath := filepath.Join(ps.Cfg.ProvisioningPath, "permissions")
+		if err := prov_permissions.DryRun(ctx, permissionsPath, ps.folderPermissionsService, ps.dashboardPermissionsService,
+			ps.teamService, ps.userService, ps.serviceAccountsService, ps.orgService, result); err != nil {
+			return nil, fmt.Errorf("%v: %w", "Permission provisioning dry-run error", err)
+		}
+	}
+
+	return result, nil
+}
+
+// HandleDashboardGitWebhook triggers the sync of the repository of a git
+// dashboard provider, after verifying the webhook signature or token.
+func (ps *ProvisioningServiceImpl) HandleDashboardGitWebhook(name string, payload []byte, signature string, token string) error {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	if ps.dashboardProvisioner == nil {
+		return dashboards.ErrGitProviderNotFound
+	}
+	return ps.dashboardProvisioner.HandleGitWebhook(name, payload, signature, token)
 }
 
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
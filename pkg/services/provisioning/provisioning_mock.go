@@ -1,6 +1,10 @@
 package provisioning
 
-import "context"
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
 
 type Calls struct {
 	RunInitProvisioners                 []any
@@ -9,8 +13,16 @@ type Calls struct {
 	ProvisionNotifications              []any
 	ProvisionDashboards                 []any
 	ProvisionAlerting                   []any
+	ProvisionReports                    []any
+	ProvisionTeams                      []any
+	ProvisionServiceAccounts            []any
+	ProvisionFolders                    []any
+	ProvisionPermissions                []any
+	ProvisionRoles                      []any
 	GetDashboardProvisionerResolvedPath []any
 	GetAllowUIUpdatesFromConfig         []any
+	DryRun                              []any
+	HandleDashboardGitWebhook           []any
 	Run                                 []any
 }
 
@@ -23,6 +35,8 @@ type ProvisioningServiceMock struct {
 	ProvisionDashboardsFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
+	DryRunFunc                              func(ctx context.Context) (*dryrun.Result, error)
+	HandleDashboardGitWebhookFunc           func(name string, payload []byte, signature string, token string) error
 	RunFunc                                 func(ctx context.Context) error
 }
 
@@ -77,6 +91,36 @@ func (mock *ProvisioningServiceMock) ProvisionAlerting(ctx context.Context) erro
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionReports(ctx context.Context) error {
+	mock.Calls.ProvisionReports = append(mock.Calls.ProvisionReports, nil)
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionTeams(ctx context.Context) error {
+	mock.Calls.ProvisionTeams = append(mock.Calls.ProvisionTeams, nil)
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionServiceAccounts(ctx context.Context) error {
+	mock.Calls.ProvisionServiceAccounts = append(mock.Calls.ProvisionServiceAccounts, nil)
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionFolders(ctx context.Context) error {
+	mock.Calls.ProvisionFolders = append(mock.Calls.ProvisionFolders, nil)
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionPermissions(ctx context.Context) error {
+	mock.Calls.ProvisionPermissions = append(mock.Calls.ProvisionPermissions, nil)
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionRoles(ctx context.Context) error {
+	mock.Calls.ProvisionRoles = append(mock.Calls.ProvisionRoles, nil)
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) GetDashboardProvisionerResolvedPath(name string) string {
 	mock.Calls.GetDashboardProvisionerResolvedPath = append(mock.Calls.GetDashboardProvisionerResolvedPath, name)
 	if mock.GetDashboardProvisionerResolvedPathFunc != nil {
@@ -93,6 +137,22 @@ func (mock *ProvisioningServiceMock) GetAllowUIUpdatesFromConfig(name string) bo
 	return false
 }
 
+func (mock *ProvisioningServiceMock) DryRun(ctx context.Context) (*dryrun.Result, error) {
+	mock.Calls.DryRun = append(mock.Calls.DryRun, nil)
+	if mock.DryRunFunc != nil {
+		return mock.DryRunFunc(ctx)
+	}
+	return dryrun.NewResult(), nil
+}
+
+func (mock *ProvisioningServiceMock) HandleDashboardGitWebhook(name string, payload []byte, signature string, token string) error {
+	mock.Calls.HandleDashboardGitWebhook = append(mock.Calls.HandleDashboardGitWebhook, name)
+	if mock.HandleDashboardGitWebhookFunc != nil {
+		return mock.HandleDashboardGitWebhookFunc(name, payload, signature, token)
+	}
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) Run(ctx context.Context) error {
 	mock.Calls.Run = append(mock.Calls.Run, nil)
 	if mock.RunFunc != nil {
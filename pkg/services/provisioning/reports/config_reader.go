@@ -0,0 +1,108 @@
+package reports
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*reportsAsConfig, error) {
+	var configs []*reportsAsConfig
+	cr.log.Debug("Looking for report provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read report provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing report provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseReportConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	if err := validateRequiredFields(configs); err != nil {
+		return nil, err
+	}
+
+	checkOrgIDAndOrgName(configs)
+
+	return configs, nil
+}
+
+func (cr *configReader) parseReportConfig(path string, file fs.DirEntry) (*reportsAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *reportsAsConfigV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToReportsFromConfig(), nil
+}
+
+// validateRequiredFields checks that reports have a uid, which is how
+// provisioned reports are matched to existing ones. The other settings
+// are validated when the reports are saved.
+func validateRequiredFields(configs []*reportsAsConfig) error {
+	for i := range configs {
+		var errStrings []string
+		for index, report := range configs[i].Reports {
+			if report.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("report item %d in configuration doesn't contain required field uid", index+1))
+			}
+		}
+		for index, report := range configs[i].DeleteReports {
+			if report.UID == "" {
+				errStrings = append(errStrings, fmt.Sprintf("delete report item %d in configuration doesn't contain required field uid", index+1))
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf("%s", strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+func checkOrgIDAndOrgName(configs []*reportsAsConfig) {
+	for i := range configs {
+		for _, report := range configs[i].Reports {
+			report.OrgID = utils.OrgIDOrDefault(report.OrgID, report.OrgName)
+		}
+		for _, report := range configs[i].DeleteReports {
+			report.OrgID = utils.OrgIDOrDefault(report.OrgID, report.OrgName)
+		}
+	}
+}
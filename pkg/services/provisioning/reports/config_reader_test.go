@@ -0,0 +1,73 @@
+package reports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+const (
+	correctProperties = "./testdata/test-configs/correct-properties"
+	brokenYaml        = "./testdata/test-configs/broken-yaml"
+	missingUID        = "./testdata/test-configs/missing-uid"
+	emptyFolder       = "./testdata/test-configs/empty_folder"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Report without uid should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(missingUID)
+		require.Error(t, err)
+		require.Equal(t, "report item 1 in configuration doesn't contain required field uid", err.Error())
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("REPORT_DASHBOARD_UID", "ops")
+
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+		require.Len(t, cfg[0].Reports, 2)
+
+		weekly := cfg[0].Reports[0]
+		require.Equal(t, "weekly-overview", weekly.UID)
+		require.Equal(t, int64(2), weekly.OrgID)
+		require.Equal(t, reports.ReportSettings{
+			Name:         "Weekly overview",
+			DashboardUID: "ops",
+			Schedule:     "0 8 * * 1",
+			Recipients:   []string{"ops@example.com", "team@example.com"},
+			Format:       reports.FormatPNG,
+			TimeFrom:     "now-7d",
+			TimeTo:       "now",
+			Variables:    map[string]string{"env": "prod"},
+			Enabled:      true,
+		}, weekly.ReportSettings)
+
+		daily := cfg[0].Reports[1]
+		require.Equal(t, int64(0), daily.OrgID)
+		require.Equal(t, "Org 3", daily.OrgName)
+		require.False(t, daily.Enabled)
+
+		require.Len(t, cfg[0].DeleteReports, 1)
+		require.Equal(t, "old-report", cfg[0].DeleteReports[0].UID)
+		require.Equal(t, int64(1), cfg[0].DeleteReports[0].OrgID)
+	})
+}
@@ -0,0 +1,139 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+// DryRun validates the provisioning config files in a directory and adds the
+// changes provisioning them would apply to the result, without applying them.
+func DryRun(ctx context.Context, configDirectory string, reportService reports.Service, orgService org.Service, result *dryrun.Result) error {
+	logger := log.New("provisioning.reports")
+	rp := ReportProvisioner{
+		log:           logger,
+		cfgProvider:   &configReader{log: logger},
+		reportService: reportService,
+		orgService:    orgService,
+	}
+	return rp.dryRun(ctx, configDirectory, result)
+}
+
+func (rp *ReportProvisioner) dryRun(ctx context.Context, configPath string, result *dryrun.Result) error {
+	files, err := os.ReadDir(configPath)
+	if err != nil {
+		// like readConfig, a missing directory means there is nothing to provision
+		return nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(configPath, file.Name())
+		cfg, err := rp.cfgProvider.parseReportConfig(configPath, file)
+		if err != nil {
+			result.AddError(dryrun.ResourceReport, filename, err)
+			continue
+		}
+
+		cfgs := []*reportsAsConfig{cfg}
+		if err := validateRequiredFields(cfgs); err != nil {
+			result.AddError(dryrun.ResourceReport, filename, err)
+			continue
+		}
+		checkOrgIDAndOrgName(cfgs)
+		if err := validateSettings(cfg); err != nil {
+			result.AddError(dryrun.ResourceReport, filename, err)
+			continue
+		}
+
+		if err := rp.dryRunFile(ctx, cfg, filename, result); err != nil {
+			if errors.Is(err, org.ErrOrgNotFound) {
+				result.AddError(dryrun.ResourceReport, filename, err)
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rp *ReportProvisioner) dryRunFile(ctx context.Context, cfg *reportsAsConfig, filename string, result *dryrun.Result) error {
+	var changes []dryrun.Change
+	for _, report := range cfg.DeleteReports {
+		orgID, err := rp.orgID(ctx, report.OrgID, report.OrgName)
+		if err != nil {
+			return err
+		}
+
+		existing, err := rp.reportService.Get(ctx, &reports.GetReportQuery{UID: report.UID, OrgID: orgID})
+		if errors.Is(err, reports.ErrReportNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionDelete, OrgID: orgID, UID: report.UID, Name: existing.Name, File: filename})
+	}
+
+	for _, report := range cfg.Reports {
+		orgID, err := rp.orgID(ctx, report.OrgID, report.OrgName)
+		if err != nil {
+			return err
+		}
+
+		existing, err := rp.reportService.Get(ctx, &reports.GetReportQuery{UID: report.UID, OrgID: orgID})
+		if errors.Is(err, reports.ErrReportNotFound) {
+			changes = append(changes, dryrun.Change{Action: dryrun.ActionCreate, OrgID: orgID, UID: report.UID, Name: report.Name, File: filename})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		fields := dryrun.Fields(
+			dryrun.Field("name", existing.Name, report.Name),
+			dryrun.Field("dashboardUid", existing.DashboardUID, report.DashboardUID),
+			dryrun.Field("schedule", existing.Schedule, report.Schedule),
+			dryrun.Field("recipients", existing.Recipients, report.Recipients),
+			dryrun.Field("format", existing.Format, report.Format),
+			dryrun.Field("timeFrom", existing.TimeFrom, report.TimeFrom),
+			dryrun.Field("timeTo", existing.TimeTo, report.TimeTo),
+			dryrun.Field("variables", existing.Variables, report.Variables),
+			dryrun.Field("enabled", existing.Enabled, report.Enabled),
+			// reports created in the UI become provisioned
+			dryrun.Field("provisioned", existing.Provisioned, true),
+		)
+		if len(fields) == 0 {
+			continue
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionUpdate, OrgID: orgID, UID: report.UID, Name: report.Name, File: filename, Fields: fields})
+	}
+
+	// changes are only added once the organizations of all the reports of the file are found
+	for _, change := range changes {
+		result.Add(dryrun.ResourceReport, change)
+	}
+	return nil
+}
+
+// validateSettings checks the settings the report service validates when
+// the reports are saved.
+func validateSettings(cfg *reportsAsConfig) error {
+	for _, report := range cfg.Reports {
+		if err := report.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package reports
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/reports/reportstest"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("REPORT_DASHBOARD_UID", "ops")
+
+	t.Run("should report new, updated and deleted reports without changing them", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+		reportService := reportstest.NewFakeReportService()
+		_, err := reportService.Create(context.Background(), &reports.CreateReportCommand{
+			ReportSettings: reports.ReportSettings{Name: "Old report"},
+			UID:            "old-report",
+			OrgID:          1,
+			Provisioned:    true,
+		})
+		require.NoError(t, err)
+		_, err = reportService.Create(context.Background(), &reports.CreateReportCommand{
+			ReportSettings: reports.ReportSettings{
+				Name:         "Old name",
+				DashboardUID: "ops",
+				Schedule:     "0 8 * * 1",
+				Recipients:   []string{"ops@example.com", "team@example.com"},
+				Format:       reports.FormatPNG,
+				TimeFrom:     "now-7d",
+				TimeTo:       "now",
+				Variables:    map[string]string{"env": "prod"},
+				Enabled:      true,
+			},
+			UID:         "weekly-overview",
+			OrgID:       2,
+			Provisioned: true,
+		})
+		require.NoError(t, err)
+
+		result := dryrun.NewResult()
+		err = DryRun(context.Background(), correctProperties, reportService, orgService, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		file := filepath.Join(correctProperties, "reports.yaml")
+		require.Equal(t, []dryrun.Change{
+			{Action: dryrun.ActionDelete, OrgID: 1, UID: "old-report", Name: "Old report", File: file},
+			{Action: dryrun.ActionUpdate, OrgID: 2, UID: "weekly-overview", Name: "Weekly overview", File: file, Fields: []string{"name"}},
+			{Action: dryrun.ActionCreate, OrgID: 3, UID: "daily-overview", Name: "Daily overview", File: file},
+		}, result.Changes[dryrun.ResourceReport])
+		require.Equal(t, "Old name", reportService.Reports[2]["weekly-overview"].Name)
+		require.Contains(t, reportService.Reports[1], "old-report")
+		require.NotContains(t, reportService.Reports[3], "daily-overview")
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), missingUID, reportstest.NewFakeReportService(), orgtest.NewOrgServiceFake(), result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, dryrun.ResourceReport, result.Errors[0].Resource)
+	})
+
+	t.Run("should report files with unknown organizations", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedError = org.ErrOrgNotFound
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, reportstest.NewFakeReportService(), orgService, result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Empty(t, result.Changes[dryrun.ResourceReport])
+	})
+}
@@ -0,0 +1,112 @@
+package reports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the reports in those files.
+func Provision(ctx context.Context, configDirectory string, reportService reports.Service, orgService org.Service) error {
+	logger := log.New("provisioning.reports")
+	rp := ReportProvisioner{
+		log:           logger,
+		cfgProvider:   &configReader{log: logger},
+		reportService: reportService,
+		orgService:    orgService,
+	}
+	return rp.applyChanges(ctx, configDirectory)
+}
+
+// ReportProvisioner is responsible for provisioning reports based on
+// configuration read by the `configReader`
+type ReportProvisioner struct {
+	log           log.Logger
+	cfgProvider   *configReader
+	reportService reports.Service
+	orgService    org.Service
+}
+
+func (rp *ReportProvisioner) apply(ctx context.Context, cfg *reportsAsConfig) error {
+	for _, report := range cfg.DeleteReports {
+		orgID, err := rp.orgID(ctx, report.OrgID, report.OrgName)
+		if err != nil {
+			return err
+		}
+
+		rp.log.Debug("Deleting report from configuration", "uid", report.UID, "orgId", orgID)
+		err = rp.reportService.Delete(ctx, &reports.DeleteReportCommand{UID: report.UID, OrgID: orgID, Provisioned: true})
+		if err != nil && !errors.Is(err, reports.ErrReportNotFound) {
+			return err
+		}
+	}
+
+	for _, report := range cfg.Reports {
+		orgID, err := rp.orgID(ctx, report.OrgID, report.OrgName)
+		if err != nil {
+			return err
+		}
+
+		_, err = rp.reportService.Get(ctx, &reports.GetReportQuery{UID: report.UID, OrgID: orgID})
+		if errors.Is(err, reports.ErrReportNotFound) {
+			rp.log.Info("Inserting report from configuration", "uid", report.UID, "name", report.Name)
+			_, err = rp.reportService.Create(ctx, &reports.CreateReportCommand{
+				ReportSettings: report.ReportSettings,
+				UID:            report.UID,
+				OrgID:          orgID,
+				Provisioned:    true,
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		rp.log.Debug("Updating report from configuration", "uid", report.UID, "name", report.Name)
+		_, err = rp.reportService.Update(ctx, &reports.UpdateReportCommand{
+			ReportSettings: report.ReportSettings,
+			UID:            report.UID,
+			OrgID:          orgID,
+			Provisioned:    true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rp *ReportProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := rp.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := rp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rp *ReportProvisioner) orgID(ctx context.Context, orgID int64, orgName string) (int64, error) {
+	if orgID != 0 {
+		return orgID, nil
+	}
+
+	res, err := rp.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: orgName})
+	if err != nil {
+		return 0, err
+	}
+	return res.ID, nil
+}
@@ -0,0 +1,59 @@
+package reports
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/reports/reportstest"
+)
+
+func TestReportProvisioner(t *testing.T) {
+	t.Setenv("REPORT_DASHBOARD_UID", "ops")
+
+	orgService := orgtest.NewOrgServiceFake()
+	orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+
+	t.Run("Creates and deletes reports", func(t *testing.T) {
+		reportService := reportstest.NewFakeReportService()
+		_, err := reportService.Create(context.Background(), &reports.CreateReportCommand{UID: "old-report", OrgID: 1, Provisioned: true})
+		require.NoError(t, err)
+
+		err = Provision(context.Background(), correctProperties, reportService, orgService)
+		require.NoError(t, err)
+
+		require.NotContains(t, reportService.Reports[1], "old-report")
+
+		weekly := reportService.Reports[2]["weekly-overview"]
+		require.NotNil(t, weekly)
+		require.True(t, weekly.Provisioned)
+		require.True(t, weekly.Enabled)
+		require.Equal(t, "ops", weekly.DashboardUID)
+
+		daily := reportService.Reports[3]["daily-overview"]
+		require.NotNil(t, daily)
+		require.False(t, daily.Enabled)
+	})
+
+	t.Run("Updates existing reports", func(t *testing.T) {
+		reportService := reportstest.NewFakeReportService()
+		_, err := reportService.Create(context.Background(), &reports.CreateReportCommand{
+			ReportSettings: reports.ReportSettings{Name: "Old name"},
+			UID:            "weekly-overview",
+			OrgID:          2,
+			UserID:         10,
+		})
+		require.NoError(t, err)
+
+		err = Provision(context.Background(), correctProperties, reportService, orgService)
+		require.NoError(t, err)
+
+		weekly := reportService.Reports[2]["weekly-overview"]
+		require.Equal(t, "Weekly overview", weekly.Name)
+		require.True(t, weekly.Provisioned)
+	})
+}
@@ -0,0 +1,97 @@
+package reports
+
+import (
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+// reportsAsConfig is a normalized data object for reports config data. Any config version should be mappable
+// to this type.
+type reportsAsConfig struct {
+	Reports       []*reportFromConfig
+	DeleteReports []*deleteReportConfig
+}
+
+type reportFromConfig struct {
+	UID     string
+	OrgID   int64
+	OrgName string
+	reports.ReportSettings
+}
+
+type deleteReportConfig struct {
+	UID     string
+	OrgID   int64
+	OrgName string
+}
+
+type reportFromConfigV1 struct {
+	UID          values.StringValue    `json:"uid" yaml:"uid"`
+	OrgID        values.Int64Value     `json:"orgId" yaml:"orgId"`
+	OrgName      values.StringValue    `json:"orgName" yaml:"orgName"`
+	Name         values.StringValue    `json:"name" yaml:"name"`
+	DashboardUID values.StringValue    `json:"dashboardUid" yaml:"dashboardUid"`
+	Schedule     values.StringValue    `json:"schedule" yaml:"schedule"`
+	Recipients   []values.StringValue  `json:"recipients" yaml:"recipients"`
+	Format       values.StringValue    `json:"format" yaml:"format"`
+	TimeFrom     values.StringValue    `json:"timeFrom" yaml:"timeFrom"`
+	TimeTo       values.StringValue    `json:"timeTo" yaml:"timeTo"`
+	Variables    values.StringMapValue `json:"variables" yaml:"variables"`
+	Disabled     values.BoolValue      `json:"disabled" yaml:"disabled"`
+}
+
+type deleteReportConfigV1 struct {
+	UID     values.StringValue `json:"uid" yaml:"uid"`
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue `json:"orgName" yaml:"orgName"`
+}
+
+// reportsAsConfigV1 is a mapping for version 1 configs. This is mapped to its normalised version.
+type reportsAsConfigV1 struct {
+	APIVersion    values.Int64Value       `json:"apiVersion" yaml:"apiVersion"`
+	Reports       []*reportFromConfigV1   `json:"reports" yaml:"reports"`
+	DeleteReports []*deleteReportConfigV1 `json:"deleteReports" yaml:"deleteReports"`
+}
+
+// mapToReportsFromConfig maps config syntax to a normalized reportsAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *reportsAsConfigV1) mapToReportsFromConfig() *reportsAsConfig {
+	r := &reportsAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, report := range cfg.Reports {
+		recipients := make([]string, 0, len(report.Recipients))
+		for _, recipient := range report.Recipients {
+			recipients = append(recipients, recipient.Value())
+		}
+
+		r.Reports = append(r.Reports, &reportFromConfig{
+			UID:     report.UID.Value(),
+			OrgID:   report.OrgID.Value(),
+			OrgName: report.OrgName.Value(),
+			ReportSettings: reports.ReportSettings{
+				Name:         report.Name.Value(),
+				DashboardUID: report.DashboardUID.Value(),
+				Schedule:     report.Schedule.Value(),
+				Recipients:   recipients,
+				Format:       reports.Format(report.Format.Value()),
+				TimeFrom:     report.TimeFrom.Value(),
+				TimeTo:       report.TimeTo.Value(),
+				Variables:    report.Variables.Value(),
+				Enabled:      !report.Disabled.Value(),
+			},
+		})
+	}
+
+	for _, report := range cfg.DeleteReports {
+		r.DeleteReports = append(r.DeleteReports, &deleteReportConfig{
+			UID:     report.UID.Value(),
+			OrgID:   report.OrgID.Value(),
+			OrgName: report.OrgName.Value(),
+		})
+	}
+
+	return r
+}
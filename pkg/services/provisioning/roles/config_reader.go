@@ -0,0 +1,134 @@
+package roles
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*rolesAsConfig, error) {
+	var configs []*rolesAsConfig
+	cr.log.Debug("Looking for role provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read role provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing role provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseRoleConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	if err := validateRoles(configs); err != nil {
+		return nil, err
+	}
+
+	checkOrgID(configs)
+
+	return configs, nil
+}
+
+func (cr *configReader) parseRoleConfig(path string, file fs.DirEntry) (*rolesAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *rolesAsConfigV2
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToRolesFromConfig(), nil
+}
+
+// validateRoles checks that provisioned roles are custom roles, that roles
+// to delete can be identified, and that team assignments refer to a role.
+func validateRoles(configs []*rolesAsConfig) error {
+	for i := range configs {
+		var errStrings []string
+		for index, r := range configs[i].Roles {
+			if r.State != statePresent && r.State != stateAbsent {
+				errStrings = append(errStrings, fmt.Sprintf("role item %d in configuration has invalid state %q", index+1, r.State))
+				continue
+			}
+			if r.State == stateAbsent {
+				if r.Name == "" && r.UID == "" {
+					errStrings = append(errStrings, fmt.Sprintf("role item %d in configuration doesn't contain uid or name", index+1))
+				}
+				continue
+			}
+			if r.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("role item %d in configuration doesn't contain required field name", index+1))
+				continue
+			}
+			if _, err := accesscontrol.ValidateCustomRole(r.Name, r.Permissions); err != nil {
+				errStrings = append(errStrings, fmt.Sprintf("role %q is invalid: %v", r.Name, err))
+			}
+		}
+		for index, t := range configs[i].Teams {
+			if t.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("team item %d in configuration doesn't contain required field name", index+1))
+			}
+			for _, ref := range t.Roles {
+				if ref.UID == "" && ref.Name == "" {
+					errStrings = append(errStrings, fmt.Sprintf("role of team %q doesn't contain uid or name", t.Name))
+				}
+				if ref.State != statePresent && ref.State != stateAbsent {
+					errStrings = append(errStrings, fmt.Sprintf("role of team %q has invalid state %q", t.Name, ref.State))
+				}
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf("%s", strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+// checkOrgID defaults roles and teams to the main organization.
+func checkOrgID(configs []*rolesAsConfig) {
+	for i := range configs {
+		for _, r := range configs[i].Roles {
+			if r.OrgID < 1 {
+				r.OrgID = 1
+			}
+		}
+		for _, t := range configs[i].Teams {
+			if t.OrgID < 1 {
+				t.OrgID = 1
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package roles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	correctProperties = "./testdata/test-configs/correct-properties"
+	brokenYaml        = "./testdata/test-configs/broken-yaml"
+	missingName       = "./testdata/test-configs/missing-name"
+	emptyFolder       = "./testdata/test-configs/empty_folder"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Role without name should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(missingName)
+		require.Error(t, err)
+		require.Equal(t, "role item 1 in configuration doesn't contain required field name", err.Error())
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("USERS_SCOPE", "global.users:*")
+
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+		require.Len(t, cfg[0].Roles, 3)
+
+		reader0 := cfg[0].Roles[0]
+		require.Equal(t, "custom:dashboards:reader", reader0.Name)
+		require.Equal(t, "dashboardsreader", reader0.UID)
+		require.Equal(t, int64(2), reader0.Version)
+		require.Equal(t, int64(2), reader0.OrgID)
+		require.Equal(t, statePresent, reader0.State)
+		require.Equal(t, []accesscontrol.Permission{
+			{Action: "dashboards:read", Scope: "dashboards:*"},
+			{Action: "folders:read", Scope: "folders:*"},
+		}, reader0.Permissions)
+
+		global := cfg[0].Roles[1]
+		require.True(t, global.Global)
+		require.Equal(t, int64(1), global.OrgID)
+		require.Equal(t, []accesscontrol.Permission{{Action: "users:read", Scope: "global.users:*"}}, global.Permissions)
+
+		require.Equal(t, stateAbsent, cfg[0].Roles[2].State)
+
+		require.Len(t, cfg[0].Teams, 1)
+		require.Equal(t, "Platform", cfg[0].Teams[0].Name)
+		require.Equal(t, []*roleRefFromConfig{
+			{UID: "dashboardsreader", State: statePresent},
+			{Name: "custom:legacy", State: stateAbsent},
+		}, cfg[0].Teams[0].Roles)
+	})
+}
@@ -0,0 +1,222 @@
+package roles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+// DryRun validates the provisioning config files in a directory and adds the
+// changes provisioning them would apply to the result, without applying them.
+func DryRun(ctx context.Context, configDirectory string, roleService accesscontrol.RoleService, teamService team.Service, result *dryrun.Result) error {
+	logger := log.New("provisioning.roles")
+	rp := RoleProvisioner{
+		log:         logger,
+		cfgProvider: &configReader{log: logger},
+		roleService: roleService,
+		teamService: teamService,
+	}
+	return rp.dryRun(ctx, configDirectory, result)
+}
+
+func (rp *RoleProvisioner) dryRun(ctx context.Context, configPath string, result *dryrun.Result) error {
+	files, err := os.ReadDir(configPath)
+	if err != nil {
+		// like readConfig, a missing directory means there is nothing to provision
+		return nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(configPath, file.Name())
+		cfg, err := rp.cfgProvider.parseRoleConfig(configPath, file)
+		if err != nil {
+			result.AddError(dryrun.ResourceRole, filename, err)
+			continue
+		}
+
+		cfgs := []*rolesAsConfig{cfg}
+		if err := validateRoles(cfgs); err != nil {
+			result.AddError(dryrun.ResourceRole, filename, err)
+			continue
+		}
+		checkOrgID(cfgs)
+
+		changes, err := rp.changes(ctx, cfg, filename)
+		if err != nil {
+			if errors.Is(err, accesscontrol.ErrRoleNotFound) {
+				result.AddError(dryrun.ResourceRole, filename, err)
+				continue
+			}
+			return err
+		}
+		for _, change := range changes {
+			result.Add(dryrun.ResourceRole, change)
+		}
+	}
+
+	return nil
+}
+
+func (rp *RoleProvisioner) changes(ctx context.Context, cfg *rolesAsConfig, filename string) ([]dryrun.Change, error) {
+	var changes []dryrun.Change
+	for _, r := range cfg.Roles {
+		if r.State != stateAbsent {
+			continue
+		}
+
+		existing, err := rp.findRole(ctx, r.OrgID, r.UID, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			continue
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionDelete, OrgID: r.OrgID, UID: existing.UID, Name: existing.Name, File: filename})
+	}
+
+	// roles created by the file can be assigned to its teams
+	created := map[string]string{}
+	for _, r := range cfg.Roles {
+		if r.State != statePresent {
+			continue
+		}
+
+		uid := r.UID
+		if uid == "" {
+			uid = accesscontrol.PrefixedRoleUID(r.Name)
+		}
+		version := r.Version
+		if version < 1 {
+			version = 1
+		}
+
+		existing, err := rp.findRole(ctx, r.OrgID, uid, "")
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			created[uid], created[r.Name] = uid, uid
+			changes = append(changes, dryrun.Change{Action: dryrun.ActionCreate, OrgID: r.OrgID, UID: uid, Name: r.Name, File: filename})
+			continue
+		}
+
+		// like saveRole, roles are only updated when the version increases
+		if existing.Version >= version {
+			continue
+		}
+		fields := dryrun.Fields(
+			dryrun.Field("name", existing.Name, r.Name),
+			dryrun.Field("displayName", existing.DisplayName, r.DisplayName),
+			dryrun.Field("description", existing.Description, r.Description),
+			dryrun.Field("group", existing.Group, r.Group),
+			dryrun.Field("hidden", existing.Hidden, r.Hidden),
+			dryrun.Field("version", existing.Version, version),
+			dryrun.Field("permissions", permissionSet(existing.Permissions), permissionSet(r.Permissions)),
+		)
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionUpdate, OrgID: r.OrgID, UID: uid, Name: r.Name, File: filename, Fields: fields})
+	}
+
+	for _, t := range cfg.Teams {
+		change, err := rp.teamRolesChange(ctx, t, created, filename)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	return changes, nil
+}
+
+// teamRolesChange returns the change of the roles assigned to a team, or nil
+// when the team already has the configured roles.
+func (rp *RoleProvisioner) teamRolesChange(ctx context.Context, t *teamRolesFromConfig, created map[string]string, filename string) (*dryrun.Change, error) {
+	res, err := rp.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        t.OrgID,
+		Name:         t.Name,
+		Limit:        1,
+		Page:         1,
+		SignedInUser: provisioningUser(t.OrgID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]bool{}
+	if len(res.Teams) > 0 {
+		assigned, err := rp.roleService.GetTeamRoles(ctx, t.OrgID, res.Teams[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range assigned {
+			current[role.UID] = true
+		}
+	}
+
+	desired := make(map[string]bool, len(current))
+	for uid := range current {
+		desired[uid] = true
+	}
+	for _, ref := range t.Roles {
+		role, err := rp.findRole(ctx, t.OrgID, ref.UID, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if ref.State == stateAbsent {
+			if role != nil {
+				delete(desired, role.UID)
+			}
+			continue
+		}
+
+		uid := created[ref.Name]
+		switch {
+		case role != nil:
+			uid = role.UID
+		case ref.UID != "":
+			uid = created[ref.UID]
+		}
+		if uid == "" {
+			return nil, fmt.Errorf("role of team %q: %w", t.Name, accesscontrol.ErrRoleNotFound)
+		}
+		desired[uid] = true
+	}
+
+	// the team is expected to be created by the team provisioner, which runs first
+	if len(res.Teams) == 0 {
+		if len(desired) == 0 {
+			return nil, nil
+		}
+		return &dryrun.Change{Action: dryrun.ActionCreate, OrgID: t.OrgID, Name: "team " + t.Name, File: filename}, nil
+	}
+
+	fields := dryrun.Fields(dryrun.Field("roles", current, desired))
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return &dryrun.Change{Action: dryrun.ActionUpdate, OrgID: t.OrgID, Name: "team " + t.Name, File: filename, Fields: fields}, nil
+}
+
+// permissionSet returns the actions and scopes of the permissions, which are
+// what provisioning compares.
+func permissionSet(permissions []accesscontrol.Permission) map[string]bool {
+	set := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		set[p.Action+" "+p.Scope] = true
+	}
+	return set
+}
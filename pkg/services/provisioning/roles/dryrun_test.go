@@ -0,0 +1,74 @@
+package roles
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("USERS_SCOPE", "global.users:*")
+	teamService := &spyTeamService{FakeService: teamtest.NewFakeService(), team: &team.TeamDTO{ID: 5, OrgID: 2, Name: "Platform"}}
+	file := filepath.Join(correctProperties, "roles.yaml")
+	globalUID := accesscontrol.PrefixedRoleUID("custom:global:users:reader")
+
+	t.Run("should report new, updated and deleted roles without changing them", func(t *testing.T) {
+		roleService := newSpyRoleService()
+		roleService.roles["legacy"] = &accesscontrol.RoleDTO{OrgID: 1, UID: "legacy", Name: "custom:legacy", Version: 1}
+		roleService.roles["dashboardsreader"] = &accesscontrol.RoleDTO{
+			OrgID:       2,
+			UID:         "dashboardsreader",
+			Name:        "custom:dashboards:reader",
+			Description: "Read all dashboards",
+			Version:     1,
+			Permissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}},
+		}
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, roleService, teamService, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		require.Equal(t, []dryrun.Change{
+			{Action: dryrun.ActionDelete, OrgID: 1, UID: "legacy", Name: "custom:legacy", File: file},
+			{Action: dryrun.ActionUpdate, OrgID: 2, UID: "dashboardsreader", Name: "custom:dashboards:reader", File: file, Fields: []string{"permissions", "version"}},
+			{Action: dryrun.ActionCreate, OrgID: 1, UID: globalUID, Name: "custom:global:users:reader", File: file},
+			{Action: dryrun.ActionUpdate, OrgID: 2, Name: "team Platform", File: file, Fields: []string{"roles"}},
+		}, result.Changes[dryrun.ResourceRole])
+		require.Contains(t, roleService.roles, "legacy")
+		require.NotContains(t, roleService.roles, globalUID)
+		require.Equal(t, int64(1), roleService.roles["dashboardsreader"].Version)
+		require.Empty(t, roleService.teamRoles)
+	})
+
+	t.Run("should not report roles already assigned to teams", func(t *testing.T) {
+		roleService := newSpyRoleService()
+		roleService.roles["dashboardsreader"] = &accesscontrol.RoleDTO{OrgID: 2, UID: "dashboardsreader", Name: "custom:dashboards:reader", Version: 2}
+		roleService.roles[globalUID] = &accesscontrol.RoleDTO{OrgID: accesscontrol.GlobalOrgID, UID: globalUID, Name: "custom:global:users:reader", Version: 1}
+		roleService.teamRoles[5] = map[string]bool{"dashboardsreader": true}
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, roleService, teamService, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		require.False(t, result.HasChanges())
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), missingName, newSpyRoleService(), teamService, result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, dryrun.ResourceRole, result.Errors[0].Resource)
+	})
+}
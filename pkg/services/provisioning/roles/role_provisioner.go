@@ -0,0 +1,212 @@
+package roles
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the custom roles and team role assignments in those files.
+func Provision(ctx context.Context, configDirectory string, roleService accesscontrol.RoleService, teamService team.Service) error {
+	logger := log.New("provisioning.roles")
+	rp := RoleProvisioner{
+		log:         logger,
+		cfgProvider: &configReader{log: logger},
+		roleService: roleService,
+		teamService: teamService,
+	}
+	return rp.applyChanges(ctx, configDirectory)
+}
+
+// RoleProvisioner is responsible for provisioning custom roles and their
+// assignments to teams based on configuration read by the `configReader`
+type RoleProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	roleService accesscontrol.RoleService
+	teamService team.Service
+}
+
+func (rp *RoleProvisioner) apply(ctx context.Context, cfg *rolesAsConfig) error {
+	for _, r := range cfg.Roles {
+		if r.State != stateAbsent {
+			continue
+		}
+
+		existing, err := rp.findRole(ctx, r.OrgID, r.UID, r.Name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			continue
+		}
+
+		rp.log.Debug("Deleting role from configuration", "uid", existing.UID, "name", existing.Name, "orgId", r.OrgID)
+		if err := rp.roleService.DeleteCustomRole(ctx, r.OrgID, existing.UID); err != nil && !errors.Is(err, accesscontrol.ErrRoleNotFound) {
+			return err
+		}
+	}
+
+	for _, r := range cfg.Roles {
+		if r.State != statePresent {
+			continue
+		}
+		if err := rp.saveRole(ctx, r); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range cfg.Teams {
+		if err := rp.syncTeamRoles(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveRole creates the role, or updates it when the version in the
+// configuration is greater than the stored one.
+func (rp *RoleProvisioner) saveRole(ctx context.Context, r *roleFromConfig) error {
+	uid := r.UID
+	if uid == "" {
+		uid = accesscontrol.PrefixedRoleUID(r.Name)
+	}
+	version := r.Version
+	if version < 1 {
+		version = 1
+	}
+
+	existing, err := rp.findRole(ctx, r.OrgID, uid, "")
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		rp.log.Info("Inserting role from configuration", "uid", uid, "name", r.Name, "orgId", r.OrgID)
+		_, err := rp.roleService.CreateCustomRole(ctx, r.OrgID, accesscontrol.CreateRoleCommand{
+			UID:         uid,
+			Name:        r.Name,
+			DisplayName: r.DisplayName,
+			Description: r.Description,
+			Group:       r.Group,
+			Hidden:      r.Hidden,
+			Global:      r.Global,
+			Version:     version,
+			Permissions: r.Permissions,
+		})
+		return err
+	}
+
+	if existing.Version >= version {
+		rp.log.Debug("Skipping role with unchanged version", "uid", uid, "version", existing.Version)
+		return nil
+	}
+
+	rp.log.Debug("Updating role from configuration", "uid", uid, "name", r.Name, "version", version)
+	_, err = rp.roleService.UpdateCustomRole(ctx, r.OrgID, uid, accesscontrol.UpdateRoleCommand{
+		Name:        r.Name,
+		DisplayName: r.DisplayName,
+		Description: r.Description,
+		Group:       r.Group,
+		Hidden:      r.Hidden,
+		Version:     version,
+		Permissions: r.Permissions,
+	})
+	return err
+}
+
+func (rp *RoleProvisioner) syncTeamRoles(ctx context.Context, t *teamRolesFromConfig) error {
+	res, err := rp.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        t.OrgID,
+		Name:         t.Name,
+		Limit:        1,
+		Page:         1,
+		SignedInUser: provisioningUser(t.OrgID),
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Teams) == 0 {
+		return team.ErrTeamNotFound
+	}
+	teamID := res.Teams[0].ID
+
+	for _, ref := range t.Roles {
+		role, err := rp.findRole(ctx, t.OrgID, ref.UID, ref.Name)
+		if err != nil {
+			return err
+		}
+
+		if ref.State == stateAbsent {
+			if role == nil {
+				continue
+			}
+			rp.log.Debug("Revoking role from team", "team", t.Name, "role", role.Name)
+			if err := rp.roleService.RemoveTeamRole(ctx, t.OrgID, teamID, role.UID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if role == nil {
+			return accesscontrol.ErrRoleNotFound
+		}
+		rp.log.Debug("Assigning role to team", "team", t.Name, "role", role.Name)
+		if err := rp.roleService.AddTeamRole(ctx, t.OrgID, teamID, role.UID); err != nil && !errors.Is(err, accesscontrol.ErrRoleAssignmentExists) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findRole looks a custom role up by uid, or by name when no uid is given.
+// It returns nil when the role doesn't exist.
+func (rp *RoleProvisioner) findRole(ctx context.Context, orgID int64, uid, name string) (*accesscontrol.RoleDTO, error) {
+	if uid != "" {
+		role, err := rp.roleService.GetCustomRole(ctx, orgID, uid)
+		if errors.Is(err, accesscontrol.ErrRoleNotFound) {
+			return nil, nil
+		}
+		return role, err
+	}
+
+	roles, err := rp.roleService.GetCustomRoles(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+	return nil, nil
+}
+
+func (rp *RoleProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := rp.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := rp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func provisioningUser(orgID int64) identity.Requester {
+	return accesscontrol.BackgroundUser("role_provisioning", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+	})
+}
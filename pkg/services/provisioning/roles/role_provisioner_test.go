@@ -0,0 +1,150 @@
+package roles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+)
+
+func TestRoleProvisioner(t *testing.T) {
+	t.Setenv("USERS_SCOPE", "global.users:*")
+	teamService := &spyTeamService{FakeService: teamtest.NewFakeService(), team: &team.TeamDTO{ID: 5, OrgID: 2, Name: "Platform"}}
+
+	t.Run("Creates and deletes roles and assigns them to teams", func(t *testing.T) {
+		roleService := newSpyRoleService()
+		roleService.roles["legacy"] = &accesscontrol.RoleDTO{OrgID: 1, UID: "legacy", Name: "custom:legacy", Version: 1}
+
+		err := Provision(context.Background(), correctProperties, roleService, teamService)
+		require.NoError(t, err)
+
+		require.NotContains(t, roleService.roles, "legacy")
+
+		reader := roleService.roles["dashboardsreader"]
+		require.NotNil(t, reader)
+		require.Equal(t, int64(2), reader.OrgID)
+		require.Equal(t, int64(2), reader.Version)
+		require.Len(t, reader.Permissions, 2)
+
+		global := roleService.roles[accesscontrol.PrefixedRoleUID("custom:global:users:reader")]
+		require.NotNil(t, global)
+		require.Equal(t, int64(accesscontrol.GlobalOrgID), global.OrgID)
+
+		require.Equal(t, map[string]bool{"dashboardsreader": true}, roleService.teamRoles[5])
+	})
+
+	t.Run("Updates roles only when the version increases", func(t *testing.T) {
+		roleService := newSpyRoleService()
+		roleService.roles["dashboardsreader"] = &accesscontrol.RoleDTO{OrgID: 2, UID: "dashboardsreader", Name: "custom:dashboards:reader", Version: 1}
+		globalUID := accesscontrol.PrefixedRoleUID("custom:global:users:reader")
+		roleService.roles[globalUID] = &accesscontrol.RoleDTO{OrgID: accesscontrol.GlobalOrgID, UID: globalUID, Name: "custom:global:users:reader", Version: 1}
+
+		err := Provision(context.Background(), correctProperties, roleService, teamService)
+		require.NoError(t, err)
+
+		require.Len(t, roleService.roles["dashboardsreader"].Permissions, 2)
+		require.Empty(t, roleService.roles[globalUID].Permissions)
+		require.Equal(t, 1, roleService.updates)
+	})
+}
+
+type spyRoleService struct {
+	actest.FakeRoleService
+	roles     map[string]*accesscontrol.RoleDTO
+	teamRoles map[int64]map[string]bool
+	updates   int
+}
+
+func newSpyRoleService() *spyRoleService {
+	return &spyRoleService{
+		roles:     map[string]*accesscontrol.RoleDTO{},
+		teamRoles: map[int64]map[string]bool{},
+	}
+}
+
+func (s *spyRoleService) get(orgID int64, uid string) *accesscontrol.RoleDTO {
+	for _, r := range s.roles {
+		if r.UID == uid && (r.OrgID == orgID || r.OrgID == accesscontrol.GlobalOrgID) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *spyRoleService) GetCustomRoles(ctx context.Context, orgID int64) ([]*accesscontrol.RoleDTO, error) {
+	var res []*accesscontrol.RoleDTO
+	for _, r := range s.roles {
+		if r.OrgID == orgID || r.OrgID == accesscontrol.GlobalOrgID {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+func (s *spyRoleService) GetCustomRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	if r := s.get(orgID, uid); r != nil {
+		return r, nil
+	}
+	return nil, accesscontrol.ErrRoleNotFound
+}
+
+func (s *spyRoleService) CreateCustomRole(ctx context.Context, orgID int64, cmd accesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	if cmd.Global {
+		orgID = accesscontrol.GlobalOrgID
+	}
+	r := &accesscontrol.RoleDTO{OrgID: orgID, UID: cmd.UID, Name: cmd.Name, Version: cmd.Version, Permissions: cmd.Permissions}
+	s.roles[cmd.UID] = r
+	return r, nil
+}
+
+func (s *spyRoleService) UpdateCustomRole(ctx context.Context, orgID int64, uid string, cmd accesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	r := s.get(orgID, uid)
+	r.Name, r.Version, r.Permissions = cmd.Name, cmd.Version, cmd.Permissions
+	s.updates++
+	return r, nil
+}
+
+func (s *spyRoleService) DeleteCustomRole(ctx context.Context, orgID int64, uid string) error {
+	delete(s.roles, uid)
+	return nil
+}
+
+func (s *spyRoleService) AddTeamRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	if s.teamRoles[teamID] == nil {
+		s.teamRoles[teamID] = map[string]bool{}
+	}
+	s.teamRoles[teamID][roleUID] = true
+	return nil
+}
+
+func (s *spyRoleService) RemoveTeamRole(ctx context.Context, orgID, teamID int64, roleUID string) error {
+	delete(s.teamRoles[teamID], roleUID)
+	return nil
+}
+
+type spyTeamService struct {
+	*teamtest.FakeService
+	team *team.TeamDTO
+}
+
+func (s *spyTeamService) SearchTeams(ctx context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	if query.OrgID != s.team.OrgID || query.Name != s.team.Name {
+		return team.SearchTeamQueryResult{}, nil
+	}
+	return team.SearchTeamQueryResult{TotalCount: 1, Teams: []*team.TeamDTO{s.team}}, nil
+}
+
+func (s *spyRoleService) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	var res []*accesscontrol.RoleDTO
+	for uid := range s.teamRoles[teamID] {
+		if r := s.get(orgID, uid); r != nil {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
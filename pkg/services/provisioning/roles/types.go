@@ -0,0 +1,141 @@
+package roles
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+const (
+	statePresent = "present"
+	stateAbsent  = "absent"
+)
+
+// rolesAsConfig is a normalized data object for roles config data. Any config version should be mappable
+// to this type.
+type rolesAsConfig struct {
+	Roles []*roleFromConfig
+	Teams []*teamRolesFromConfig
+}
+
+type roleFromConfig struct {
+	Name        string
+	UID         string
+	DisplayName string
+	Description string
+	Group       string
+	Hidden      bool
+	Version     int64
+	OrgID       int64
+	Global      bool
+	State       string
+	Permissions []accesscontrol.Permission
+}
+
+// teamRolesFromConfig lists the roles to assign to, or revoke from, a team.
+type teamRolesFromConfig struct {
+	Name  string
+	OrgID int64
+	Roles []*roleRefFromConfig
+}
+
+// roleRefFromConfig identifies a role by uid or name.
+type roleRefFromConfig struct {
+	UID   string
+	Name  string
+	State string
+}
+
+type roleFromConfigV2 struct {
+	Name        values.StringValue        `json:"name" yaml:"name"`
+	UID         values.StringValue        `json:"uid" yaml:"uid"`
+	DisplayName values.StringValue        `json:"displayName" yaml:"displayName"`
+	Description values.StringValue        `json:"description" yaml:"description"`
+	Group       values.StringValue        `json:"group" yaml:"group"`
+	Hidden      values.BoolValue          `json:"hidden" yaml:"hidden"`
+	Version     values.Int64Value         `json:"version" yaml:"version"`
+	OrgID       values.Int64Value         `json:"orgId" yaml:"orgId"`
+	Global      values.BoolValue          `json:"global" yaml:"global"`
+	State       values.StringValue        `json:"state" yaml:"state"`
+	Permissions []*permissionFromConfigV2 `json:"permissions" yaml:"permissions"`
+}
+
+type permissionFromConfigV2 struct {
+	Action values.StringValue `json:"action" yaml:"action"`
+	Scope  values.StringValue `json:"scope" yaml:"scope"`
+}
+
+type teamRolesFromConfigV2 struct {
+	Name  values.StringValue     `json:"name" yaml:"name"`
+	OrgID values.Int64Value      `json:"orgId" yaml:"orgId"`
+	Roles []*roleRefFromConfigV2 `json:"roles" yaml:"roles"`
+}
+
+type roleRefFromConfigV2 struct {
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+	State values.StringValue `json:"state" yaml:"state"`
+}
+
+// rolesAsConfigV2 is a mapping for version 2 configs, the version used by the
+// access control provisioning files. This is mapped to its normalised version.
+type rolesAsConfigV2 struct {
+	APIVersion values.Int64Value        `json:"apiVersion" yaml:"apiVersion"`
+	Roles      []*roleFromConfigV2      `json:"roles" yaml:"roles"`
+	Teams      []*teamRolesFromConfigV2 `json:"teams" yaml:"teams"`
+}
+
+// mapToRolesFromConfig maps config syntax to a normalized rolesAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *rolesAsConfigV2) mapToRolesFromConfig() *rolesAsConfig {
+	r := &rolesAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, role := range cfg.Roles {
+		permissions := make([]accesscontrol.Permission, 0, len(role.Permissions))
+		for _, p := range role.Permissions {
+			permissions = append(permissions, accesscontrol.Permission{Action: p.Action.Value(), Scope: p.Scope.Value()})
+		}
+
+		r.Roles = append(r.Roles, &roleFromConfig{
+			Name:        role.Name.Value(),
+			UID:         role.UID.Value(),
+			DisplayName: role.DisplayName.Value(),
+			Description: role.Description.Value(),
+			Group:       role.Group.Value(),
+			Hidden:      role.Hidden.Value(),
+			Version:     role.Version.Value(),
+			OrgID:       role.OrgID.Value(),
+			Global:      role.Global.Value(),
+			State:       stateOrDefault(role.State.Value()),
+			Permissions: permissions,
+		})
+	}
+
+	for _, t := range cfg.Teams {
+		refs := make([]*roleRefFromConfig, 0, len(t.Roles))
+		for _, ref := range t.Roles {
+			refs = append(refs, &roleRefFromConfig{
+				UID:   ref.UID.Value(),
+				Name:  ref.Name.Value(),
+				State: stateOrDefault(ref.State.Value()),
+			})
+		}
+
+		r.Teams = append(r.Teams, &teamRolesFromConfig{
+			Name:  t.Name.Value(),
+			OrgID: t.OrgID.Value(),
+			Roles: refs,
+		})
+	}
+
+	return r
+}
+
+func stateOrDefault(state string) string {
+	if state == "" {
+		return statePresent
+	}
+	return state
+}
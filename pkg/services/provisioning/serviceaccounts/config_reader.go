@@ -0,0 +1,110 @@
+package serviceaccounts
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*serviceAccountsAsConfig, error) {
+	var configs []*serviceAccountsAsConfig
+	cr.log.Debug("Looking for service account provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read service account provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing service account provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseServiceAccountConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	if err := validateServiceAccounts(configs); err != nil {
+		return nil, err
+	}
+
+	checkOrgIDAndOrgName(configs)
+
+	return configs, nil
+}
+
+func (cr *configReader) parseServiceAccountConfig(path string, file fs.DirEntry) (*serviceAccountsAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *serviceAccountsAsConfigV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToServiceAccountsFromConfig(), nil
+}
+
+// validateServiceAccounts checks that service accounts have a name, which is
+// how provisioned service accounts are matched to existing ones, and a valid role.
+func validateServiceAccounts(configs []*serviceAccountsAsConfig) error {
+	for i := range configs {
+		var errStrings []string
+		for index, s := range configs[i].ServiceAccounts {
+			if s.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("service account item %d in configuration doesn't contain required field name", index+1))
+			}
+			if s.Role != "" && !s.Role.IsValid() {
+				errStrings = append(errStrings, fmt.Sprintf("service account %q has invalid role %q", s.Name, s.Role))
+			}
+		}
+		for index, s := range configs[i].DeleteServiceAccounts {
+			if s.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("delete service account item %d in configuration doesn't contain required field name", index+1))
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf("%s", strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+func checkOrgIDAndOrgName(configs []*serviceAccountsAsConfig) {
+	for i := range configs {
+		for _, s := range configs[i].ServiceAccounts {
+			s.OrgID = utils.OrgIDOrDefault(s.OrgID, s.OrgName)
+		}
+		for _, s := range configs[i].DeleteServiceAccounts {
+			s.OrgID = utils.OrgIDOrDefault(s.OrgID, s.OrgName)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package serviceaccounts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	correctProperties = "./testdata/test-configs/correct-properties"
+	brokenYaml        = "./testdata/test-configs/broken-yaml"
+	missingName       = "./testdata/test-configs/missing-name"
+	emptyFolder       = "./testdata/test-configs/empty_folder"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Service account without name should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(missingName)
+		require.Error(t, err)
+		require.Equal(t, "service account item 1 in configuration doesn't contain required field name", err.Error())
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("SA_ROLE", "Editor")
+
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+		require.Len(t, cfg[0].ServiceAccounts, 2)
+
+		ci := cfg[0].ServiceAccounts[0]
+		require.Equal(t, "ci", ci.Name)
+		require.Equal(t, int64(2), ci.OrgID)
+		require.Equal(t, org.RoleEditor, ci.Role)
+		require.False(t, ci.IsDisabled)
+
+		backup := cfg[0].ServiceAccounts[1]
+		require.Equal(t, int64(0), backup.OrgID)
+		require.Equal(t, "Org 3", backup.OrgName)
+		require.True(t, backup.IsDisabled)
+
+		require.Len(t, cfg[0].DeleteServiceAccounts, 1)
+		require.Equal(t, "legacy", cfg[0].DeleteServiceAccounts[0].Name)
+		require.Equal(t, int64(1), cfg[0].DeleteServiceAccounts[0].OrgID)
+	})
+}
@@ -0,0 +1,122 @@
+package serviceaccounts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	sa "github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+// DryRun validates the provisioning config files in a directory and adds the
+// changes provisioning them would apply to the result, without applying them.
+func DryRun(ctx context.Context, configDirectory string, serviceAccountsService sa.Service, orgService org.Service, result *dryrun.Result) error {
+	logger := log.New("provisioning.serviceaccounts")
+	sp := ServiceAccountProvisioner{
+		log:                    logger,
+		cfgProvider:            &configReader{log: logger},
+		serviceAccountsService: serviceAccountsService,
+		orgService:             orgService,
+	}
+	return sp.dryRun(ctx, configDirectory, result)
+}
+
+func (sp *ServiceAccountProvisioner) dryRun(ctx context.Context, configPath string, result *dryrun.Result) error {
+	files, err := os.ReadDir(configPath)
+	if err != nil {
+		// like readConfig, a missing directory means there is nothing to provision
+		return nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(configPath, file.Name())
+		cfg, err := sp.cfgProvider.parseServiceAccountConfig(configPath, file)
+		if err != nil {
+			result.AddError(dryrun.ResourceServiceAccount, filename, err)
+			continue
+		}
+
+		cfgs := []*serviceAccountsAsConfig{cfg}
+		if err := validateServiceAccounts(cfgs); err != nil {
+			result.AddError(dryrun.ResourceServiceAccount, filename, err)
+			continue
+		}
+		checkOrgIDAndOrgName(cfgs)
+
+		changes, err := sp.changes(ctx, cfg, filename)
+		if err != nil {
+			if errors.Is(err, org.ErrOrgNotFound) {
+				result.AddError(dryrun.ResourceServiceAccount, filename, err)
+				continue
+			}
+			return err
+		}
+		for _, change := range changes {
+			result.Add(dryrun.ResourceServiceAccount, change)
+		}
+	}
+
+	return nil
+}
+
+func (sp *ServiceAccountProvisioner) changes(ctx context.Context, cfg *serviceAccountsAsConfig, filename string) ([]dryrun.Change, error) {
+	var changes []dryrun.Change
+	for _, s := range cfg.DeleteServiceAccounts {
+		orgID, err := sp.orgID(ctx, s.OrgID, s.OrgName)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = sp.serviceAccountsService.RetrieveServiceAccountIdByName(ctx, orgID, s.Name)
+		if errors.Is(err, sa.ErrServiceAccountNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionDelete, OrgID: orgID, Name: s.Name, File: filename})
+	}
+
+	for _, s := range cfg.ServiceAccounts {
+		orgID, err := sp.orgID(ctx, s.OrgID, s.OrgName)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := sp.serviceAccountsService.RetrieveServiceAccountIdByName(ctx, orgID, s.Name)
+		if errors.Is(err, sa.ErrServiceAccountNotFound) {
+			changes = append(changes, dryrun.Change{Action: dryrun.ActionCreate, OrgID: orgID, Name: s.Name, File: filename})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		existing, err := sp.serviceAccountsService.RetrieveServiceAccount(ctx, orgID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		values := []dryrun.FieldValues{dryrun.Field("isDisabled", existing.IsDisabled, s.IsDisabled)}
+		// like apply, the role is left untouched when it isn't configured
+		if s.Role != "" {
+			values = append(values, dryrun.Field("role", existing.Role, string(s.Role)))
+		}
+		fields := dryrun.Fields(values...)
+		if len(fields) == 0 {
+			continue
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionUpdate, OrgID: orgID, Name: s.Name, File: filename, Fields: fields})
+	}
+
+	return changes, nil
+}
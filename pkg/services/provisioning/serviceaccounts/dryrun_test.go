@@ -0,0 +1,63 @@
+package serviceaccounts
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("SA_ROLE", "Editor")
+
+	t.Run("should report new, updated and deleted service accounts without changing them", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+		saService := newSpyServiceAccountService()
+		legacy := saService.add(1, "legacy", org.RoleViewer)
+		ci := saService.add(2, "ci", org.RoleViewer)
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, saService, orgService, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		file := filepath.Join(correctProperties, "serviceaccounts.yaml")
+		require.Equal(t, []dryrun.Change{
+			{Action: dryrun.ActionDelete, OrgID: 1, Name: "legacy", File: file},
+			{Action: dryrun.ActionUpdate, OrgID: 2, Name: "ci", File: file, Fields: []string{"role"}},
+			{Action: dryrun.ActionCreate, OrgID: 3, Name: "backup", File: file},
+		}, result.Changes[dryrun.ResourceServiceAccount])
+		require.Contains(t, saService.accounts, legacy.Id)
+		require.Equal(t, string(org.RoleViewer), ci.Role)
+		require.Nil(t, saService.byName(3, "backup"))
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), missingName, newSpyServiceAccountService(), orgtest.NewOrgServiceFake(), result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, dryrun.ResourceServiceAccount, result.Errors[0].Resource)
+	})
+
+	t.Run("should report files with unknown organizations", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedError = org.ErrOrgNotFound
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, newSpyServiceAccountService(), orgService, result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Empty(t, result.Changes[dryrun.ResourceServiceAccount])
+	})
+}
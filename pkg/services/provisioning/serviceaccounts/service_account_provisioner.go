@@ -0,0 +1,136 @@
+package serviceaccounts
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	sa "github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the service accounts in those files.
+func Provision(ctx context.Context, configDirectory string, serviceAccountsService sa.Service, orgService org.Service, provenanceService provenance.Service) error {
+	logger := log.New("provisioning.serviceaccounts")
+	sp := ServiceAccountProvisioner{
+		log:                    logger,
+		cfgProvider:            &configReader{log: logger},
+		serviceAccountsService: serviceAccountsService,
+		orgService:             orgService,
+		provenanceService:      provenanceService,
+	}
+	return sp.applyChanges(ctx, configDirectory)
+}
+
+// ServiceAccountProvisioner is responsible for provisioning service accounts based on
+// configuration read by the `configReader`
+type ServiceAccountProvisioner struct {
+	log                    log.Logger
+	cfgProvider            *configReader
+	serviceAccountsService sa.Service
+	orgService             org.Service
+	provenanceService      provenance.Service
+}
+
+func (sp *ServiceAccountProvisioner) apply(ctx context.Context, cfg *serviceAccountsAsConfig) error {
+	for _, s := range cfg.DeleteServiceAccounts {
+		orgID, err := sp.orgID(ctx, s.OrgID, s.OrgName)
+		if err != nil {
+			return err
+		}
+
+		id, err := sp.serviceAccountsService.RetrieveServiceAccountIdByName(ctx, orgID, s.Name)
+		if errors.Is(err, sa.ErrServiceAccountNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		sp.log.Debug("Deleting service account from configuration", "name", s.Name, "orgId", orgID)
+		if err := sp.serviceAccountsService.DeleteServiceAccount(ctx, orgID, id); err != nil {
+			return err
+		}
+		if err := sp.provenanceService.DeleteProvisioned(ctx, orgID, provenance.KindServiceAccount, strconv.FormatInt(id, 10)); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range cfg.ServiceAccounts {
+		orgID, err := sp.orgID(ctx, s.OrgID, s.OrgName)
+		if err != nil {
+			return err
+		}
+
+		var role *org.RoleType
+		if s.Role != "" {
+			role = &s.Role
+		}
+		isDisabled := s.IsDisabled
+
+		id, err := sp.serviceAccountsService.RetrieveServiceAccountIdByName(ctx, orgID, s.Name)
+		switch {
+		case errors.Is(err, sa.ErrServiceAccountNotFound):
+			sp.log.Info("Inserting service account from configuration", "name", s.Name, "orgId", orgID)
+			created, err := sp.serviceAccountsService.CreateServiceAccount(ctx, orgID, &sa.CreateServiceAccountForm{
+				Name:       s.Name,
+				Role:       role,
+				IsDisabled: &isDisabled,
+			})
+			if err != nil {
+				return err
+			}
+			id = created.Id
+		case err != nil:
+			return err
+		default:
+			sp.log.Debug("Updating service account from configuration", "name", s.Name, "orgId", orgID)
+			name := s.Name
+			_, err := sp.serviceAccountsService.UpdateServiceAccount(ctx, orgID, id, &sa.UpdateServiceAccountForm{
+				Name:             &name,
+				ServiceAccountID: id,
+				Role:             role,
+				IsDisabled:       &isDisabled,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := sp.provenanceService.SetProvisioned(ctx, orgID, provenance.KindServiceAccount, strconv.FormatInt(id, 10)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sp *ServiceAccountProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := sp.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := sp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sp *ServiceAccountProvisioner) orgID(ctx context.Context, orgID int64, orgName string) (int64, error) {
+	if orgID != 0 {
+		return orgID, nil
+	}
+
+	res, err := sp.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: orgName})
+	if err != nil {
+		return 0, err
+	}
+	return res.ID, nil
+}
@@ -0,0 +1,131 @@
+package serviceaccounts
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
+	sa "github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+)
+
+func TestServiceAccountProvisioner(t *testing.T) {
+	t.Setenv("SA_ROLE", "Editor")
+
+	orgService := orgtest.NewOrgServiceFake()
+	orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+
+	t.Run("Creates and deletes service accounts", func(t *testing.T) {
+		saService := newSpyServiceAccountService()
+		legacy := saService.add(1, "legacy", org.RoleViewer)
+		provenanceService := provenancetest.NewFakeService()
+
+		err := Provision(context.Background(), correctProperties, saService, orgService, provenanceService)
+		require.NoError(t, err)
+
+		require.NotContains(t, saService.accounts, legacy.Id)
+
+		ci := saService.byName(2, "ci")
+		require.NotNil(t, ci)
+		require.Equal(t, string(org.RoleEditor), ci.Role)
+
+		provisioned, err := provenanceService.IsProvisioned(context.Background(), 2, provenance.KindServiceAccount, strconv.FormatInt(ci.Id, 10))
+		require.NoError(t, err)
+		require.True(t, provisioned)
+
+		backup := saService.byName(3, "backup")
+		require.NotNil(t, backup)
+		require.True(t, backup.IsDisabled)
+	})
+
+	t.Run("Updates existing service accounts", func(t *testing.T) {
+		saService := newSpyServiceAccountService()
+		ci := saService.add(2, "ci", org.RoleViewer)
+		ci.IsDisabled = true
+
+		err := Provision(context.Background(), correctProperties, saService, orgService, provenancetest.NewFakeService())
+		require.NoError(t, err)
+
+		require.Len(t, saService.accounts, 2)
+		require.Equal(t, string(org.RoleEditor), ci.Role)
+		require.False(t, ci.IsDisabled)
+	})
+}
+
+type spyServiceAccountService struct {
+	*tests.FakeServiceAccountService
+	nextID   int64
+	accounts map[int64]*sa.ServiceAccountDTO
+}
+
+func newSpyServiceAccountService() *spyServiceAccountService {
+	return &spyServiceAccountService{
+		FakeServiceAccountService: &tests.FakeServiceAccountService{},
+		accounts:                  map[int64]*sa.ServiceAccountDTO{},
+	}
+}
+
+func (s *spyServiceAccountService) add(orgID int64, name string, role org.RoleType) *sa.ServiceAccountDTO {
+	s.nextID++
+	account := &sa.ServiceAccountDTO{Id: s.nextID, OrgId: orgID, Name: name, Role: string(role)}
+	s.accounts[account.Id] = account
+	return account
+}
+
+func (s *spyServiceAccountService) byName(orgID int64, name string) *sa.ServiceAccountDTO {
+	for _, account := range s.accounts {
+		if account.OrgId == orgID && account.Name == name {
+			return account
+		}
+	}
+	return nil
+}
+
+func (s *spyServiceAccountService) RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error) {
+	if account := s.byName(orgID, name); account != nil {
+		return account.Id, nil
+	}
+	return 0, sa.ErrServiceAccountNotFound.Errorf("service account with name %s not found", name)
+}
+
+func (s *spyServiceAccountService) CreateServiceAccount(ctx context.Context, orgID int64, form *sa.CreateServiceAccountForm) (*sa.ServiceAccountDTO, error) {
+	role := org.RoleViewer
+	if form.Role != nil {
+		role = *form.Role
+	}
+	account := s.add(orgID, form.Name, role)
+	account.IsDisabled = *form.IsDisabled
+	return account, nil
+}
+
+func (s *spyServiceAccountService) UpdateServiceAccount(ctx context.Context, orgID, id int64, form *sa.UpdateServiceAccountForm) (*sa.ServiceAccountProfileDTO, error) {
+	account, ok := s.accounts[id]
+	if !ok {
+		return nil, sa.ErrServiceAccountNotFound
+	}
+	account.Name = *form.Name
+	if form.Role != nil {
+		account.Role = string(*form.Role)
+	}
+	account.IsDisabled = *form.IsDisabled
+	return &sa.ServiceAccountProfileDTO{Id: id, Name: account.Name, Role: account.Role}, nil
+}
+
+func (s *spyServiceAccountService) DeleteServiceAccount(ctx context.Context, orgID, id int64) error {
+	delete(s.accounts, id)
+	return nil
+}
+
+func (s *spyServiceAccountService) RetrieveServiceAccount(ctx context.Context, orgID, id int64) (*sa.ServiceAccountProfileDTO, error) {
+	account, ok := s.accounts[id]
+	if !ok {
+		return nil, sa.ErrServiceAccountNotFound
+	}
+	return &sa.ServiceAccountProfileDTO{Id: id, Name: account.Name, OrgId: account.OrgId, IsDisabled: account.IsDisabled, Role: account.Role}, nil
+}
@@ -0,0 +1,77 @@
+package serviceaccounts
+
+import (
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// serviceAccountsAsConfig is a normalized data object for service accounts config data. Any config version should be mappable
+// to this type.
+type serviceAccountsAsConfig struct {
+	ServiceAccounts       []*serviceAccountFromConfig
+	DeleteServiceAccounts []*deleteServiceAccountConfig
+}
+
+type serviceAccountFromConfig struct {
+	Name       string
+	OrgID      int64
+	OrgName    string
+	Role       org.RoleType
+	IsDisabled bool
+}
+
+type deleteServiceAccountConfig struct {
+	Name    string
+	OrgID   int64
+	OrgName string
+}
+
+type serviceAccountFromConfigV1 struct {
+	Name       values.StringValue `json:"name" yaml:"name"`
+	OrgID      values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName    values.StringValue `json:"orgName" yaml:"orgName"`
+	Role       values.StringValue `json:"role" yaml:"role"`
+	IsDisabled values.BoolValue   `json:"isDisabled" yaml:"isDisabled"`
+}
+
+type deleteServiceAccountConfigV1 struct {
+	Name    values.StringValue `json:"name" yaml:"name"`
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue `json:"orgName" yaml:"orgName"`
+}
+
+// serviceAccountsAsConfigV1 is a mapping for version 1 configs. This is mapped to its normalised version.
+type serviceAccountsAsConfigV1 struct {
+	APIVersion            values.Int64Value               `json:"apiVersion" yaml:"apiVersion"`
+	ServiceAccounts       []*serviceAccountFromConfigV1   `json:"serviceAccounts" yaml:"serviceAccounts"`
+	DeleteServiceAccounts []*deleteServiceAccountConfigV1 `json:"deleteServiceAccounts" yaml:"deleteServiceAccounts"`
+}
+
+// mapToServiceAccountsFromConfig maps config syntax to a normalized serviceAccountsAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *serviceAccountsAsConfigV1) mapToServiceAccountsFromConfig() *serviceAccountsAsConfig {
+	r := &serviceAccountsAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, s := range cfg.ServiceAccounts {
+		r.ServiceAccounts = append(r.ServiceAccounts, &serviceAccountFromConfig{
+			Name:       s.Name.Value(),
+			OrgID:      s.OrgID.Value(),
+			OrgName:    s.OrgName.Value(),
+			Role:       org.RoleType(s.Role.Value()),
+			IsDisabled: s.IsDisabled.Value(),
+		})
+	}
+
+	for _, s := range cfg.DeleteServiceAccounts {
+		r.DeleteServiceAccounts = append(r.DeleteServiceAccounts, &deleteServiceAccountConfig{
+			Name:    s.Name.Value(),
+			OrgID:   s.OrgID.Value(),
+			OrgName: s.OrgName.Value(),
+		})
+	}
+
+	return r
+}
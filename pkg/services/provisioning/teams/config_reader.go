@@ -0,0 +1,121 @@
+package teams
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*teamsAsConfig, error) {
+	var configs []*teamsAsConfig
+	cr.log.Debug("Looking for team provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read team provisioning files from directory", "path", path, "error", err)
+		return configs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing team provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseTeamConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	if err := validateTeams(configs); err != nil {
+		return nil, err
+	}
+
+	checkOrgIDAndOrgName(configs)
+
+	return configs, nil
+}
+
+func (cr *configReader) parseTeamConfig(path string, file fs.DirEntry) (*teamsAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *teamsAsConfigV1
+	if err := yaml.Unmarshal(yamlFile, &cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToTeamsFromConfig(), nil
+}
+
+// validateTeams checks that teams have a name, which is how provisioned
+// teams are matched to existing ones, and that their members and groups are valid.
+func validateTeams(configs []*teamsAsConfig) error {
+	for i := range configs {
+		var errStrings []string
+		for index, t := range configs[i].Teams {
+			if t.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("team item %d in configuration doesn't contain required field name", index+1))
+			}
+			for _, member := range t.Members {
+				if member.Login == "" && member.Email == "" {
+					errStrings = append(errStrings, fmt.Sprintf("member of team %q doesn't contain login or email", t.Name))
+				}
+				if member.Permission != permissionMember && member.Permission != permissionAdmin {
+					errStrings = append(errStrings, fmt.Sprintf("member of team %q has invalid permission %q, expected %s or %s",
+						t.Name, member.Permission, permissionMember, permissionAdmin))
+				}
+			}
+			for _, group := range t.Groups {
+				if group == "" {
+					errStrings = append(errStrings, fmt.Sprintf("team %q contains an empty group", t.Name))
+				}
+			}
+		}
+		for index, t := range configs[i].DeleteTeams {
+			if t.Name == "" {
+				errStrings = append(errStrings, fmt.Sprintf("delete team item %d in configuration doesn't contain required field name", index+1))
+			}
+		}
+
+		if len(errStrings) != 0 {
+			return fmt.Errorf("%s", strings.Join(errStrings, "\n"))
+		}
+	}
+
+	return nil
+}
+
+func checkOrgIDAndOrgName(configs []*teamsAsConfig) {
+	for i := range configs {
+		for _, t := range configs[i].Teams {
+			t.OrgID = utils.OrgIDOrDefault(t.OrgID, t.OrgName)
+		}
+		for _, t := range configs[i].DeleteTeams {
+			t.OrgID = utils.OrgIDOrDefault(t.OrgID, t.OrgName)
+		}
+	}
+}
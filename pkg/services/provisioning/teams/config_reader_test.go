@@ -0,0 +1,66 @@
+package teams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	correctProperties = "./testdata/test-configs/correct-properties"
+	brokenYaml        = "./testdata/test-configs/broken-yaml"
+	missingName       = "./testdata/test-configs/missing-name"
+	emptyFolder       = "./testdata/test-configs/empty_folder"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Team without name should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger")}
+		_, err := reader.readConfig(missingName)
+		require.Error(t, err)
+		require.Equal(t, "team item 1 in configuration doesn't contain required field name", err.Error())
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("TEAM_EMAIL", "platform@example.com")
+
+		reader := &configReader{log: log.New("test logger")}
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+		require.Len(t, cfg[0].Teams, 2)
+
+		platform := cfg[0].Teams[0]
+		require.Equal(t, "Platform", platform.Name)
+		require.Equal(t, "platform@example.com", platform.Email)
+		require.Equal(t, int64(2), platform.OrgID)
+		require.Equal(t, []*memberFromConfig{
+			{Login: "alice", Permission: permissionAdmin},
+			{Email: "bob@example.com", Permission: permissionMember},
+		}, platform.Members)
+		require.Equal(t, []string{"cn=platform,ou=groups,dc=grafana,dc=org", "platform-engineers"}, platform.Groups)
+
+		support := cfg[0].Teams[1]
+		require.Equal(t, int64(0), support.OrgID)
+		require.Equal(t, "Org 3", support.OrgName)
+
+		require.Len(t, cfg[0].DeleteTeams, 1)
+		require.Equal(t, "Legacy", cfg[0].DeleteTeams[0].Name)
+		require.Equal(t, int64(1), cfg[0].DeleteTeams[0].OrgID)
+	})
+}
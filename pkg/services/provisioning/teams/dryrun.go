@@ -0,0 +1,170 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// DryRun validates the provisioning config files in a directory and adds the
+// changes provisioning them would apply to the result, without applying them.
+func DryRun(ctx context.Context, configDirectory string, teamService team.Service, userService user.Service,
+	orgService org.Service, teamSyncService teamsync.Service, result *dryrun.Result) error {
+	logger := log.New("provisioning.teams")
+	tp := TeamProvisioner{
+		log:             logger,
+		cfgProvider:     &configReader{log: logger},
+		teamService:     teamService,
+		userService:     userService,
+		orgService:      orgService,
+		teamSyncService: teamSyncService,
+	}
+	return tp.dryRun(ctx, configDirectory, result)
+}
+
+func (tp *TeamProvisioner) dryRun(ctx context.Context, configPath string, result *dryrun.Result) error {
+	files, err := os.ReadDir(configPath)
+	if err != nil {
+		// like readConfig, a missing directory means there is nothing to provision
+		return nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		filename := filepath.Join(configPath, file.Name())
+		cfg, err := tp.cfgProvider.parseTeamConfig(configPath, file)
+		if err != nil {
+			result.AddError(dryrun.ResourceTeam, filename, err)
+			continue
+		}
+
+		cfgs := []*teamsAsConfig{cfg}
+		if err := validateTeams(cfgs); err != nil {
+			result.AddError(dryrun.ResourceTeam, filename, err)
+			continue
+		}
+		checkOrgIDAndOrgName(cfgs)
+
+		changes, err := tp.changes(ctx, cfg, filename)
+		if err != nil {
+			if errors.Is(err, org.ErrOrgNotFound) || errors.Is(err, user.ErrUserNotFound) {
+				result.AddError(dryrun.ResourceTeam, filename, err)
+				continue
+			}
+			return err
+		}
+		for _, change := range changes {
+			result.Add(dryrun.ResourceTeam, change)
+		}
+	}
+
+	return nil
+}
+
+func (tp *TeamProvisioner) changes(ctx context.Context, cfg *teamsAsConfig, filename string) ([]dryrun.Change, error) {
+	var changes []dryrun.Change
+	for _, t := range cfg.DeleteTeams {
+		orgID, err := tp.orgID(ctx, t.OrgID, t.OrgName)
+		if err != nil {
+			return nil, err
+		}
+
+		existing, err := tp.findTeam(ctx, orgID, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			continue
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionDelete, OrgID: orgID, UID: existing.UID, Name: t.Name, File: filename})
+	}
+
+	for _, t := range cfg.Teams {
+		orgID, err := tp.orgID(ctx, t.OrgID, t.OrgName)
+		if err != nil {
+			return nil, err
+		}
+
+		members := make(map[int64]string, len(t.Members))
+		for _, member := range t.Members {
+			u, err := tp.findUser(ctx, member)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find member of team %q: %w", t.Name, err)
+			}
+			members[u.ID] = member.Permission
+		}
+
+		existing, err := tp.findTeam(ctx, orgID, t.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			changes = append(changes, dryrun.Change{Action: dryrun.ActionCreate, OrgID: orgID, Name: t.Name, File: filename})
+			continue
+		}
+
+		currentMembers, err := tp.currentMembers(ctx, orgID, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+		currentGroups, err := tp.teamSyncService.GetTeamGroups(ctx, &teamsync.GetTeamGroupsQuery{OrgID: orgID, TeamID: existing.ID})
+		if err != nil {
+			return nil, err
+		}
+		groups := make(map[string]bool, len(currentGroups))
+		for _, g := range currentGroups {
+			groups[g.GroupID] = true
+		}
+		desiredGroups := make(map[string]bool, len(t.Groups))
+		for _, group := range t.Groups {
+			desiredGroups[group] = true
+		}
+
+		fields := dryrun.Fields(
+			dryrun.Field("email", existing.Email, t.Email),
+			dryrun.Field("members", currentMembers, members),
+			dryrun.Field("groups", groups, desiredGroups),
+		)
+		if len(fields) == 0 {
+			continue
+		}
+		changes = append(changes, dryrun.Change{Action: dryrun.ActionUpdate, OrgID: orgID, UID: existing.UID, Name: t.Name, File: filename, Fields: fields})
+	}
+
+	return changes, nil
+}
+
+// currentMembers returns the permission of the members of the team, members
+// synced from an external identity provider are ignored like by syncMembers.
+func (tp *TeamProvisioner) currentMembers(ctx context.Context, orgID, teamID int64) (map[int64]string, error) {
+	current, err := tp.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{OrgID: orgID, TeamID: teamID, SignedInUser: provisioningUser(orgID)})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[int64]string, len(current))
+	for _, m := range current {
+		if m.External {
+			continue
+		}
+		members[m.UserID] = permissionMember
+		if m.Permission == dashboardaccess.PERMISSION_ADMIN {
+			members[m.UserID] = permissionAdmin
+		}
+	}
+	return members, nil
+}
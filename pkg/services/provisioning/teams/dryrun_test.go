@@ -0,0 +1,83 @@
+package teams
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/dryrun"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Setenv("TEAM_EMAIL", "platform@example.com")
+
+	userService := &spyUserService{FakeUserService: usertest.NewUserServiceFake(), users: []*user.User{
+		{ID: 10, Login: "alice", Email: "alice@example.com"},
+		{ID: 11, Login: "bob", Email: "bob@example.com"},
+	}}
+
+	t.Run("should report new, updated and deleted teams without changing them", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+		teamService := newSpyTeamService()
+		legacy := teamService.add(1, "Legacy")
+		legacy.UID = "legacy"
+		platform := teamService.add(2, "Platform")
+		platform.UID = "platform"
+		platform.Email = "platform@example.com"
+		teamService.members[platform.ID] = map[int64]dashboardaccess.PermissionType{10: dashboardaccess.PERMISSION_ADMIN, 12: 0}
+		teamService.external[platform.ID] = map[int64]bool{13: true}
+		teamSyncService := &teamsynctest.FakeService{Groups: []*teamsync.TeamGroupDTO{
+			{OrgID: 2, TeamID: platform.ID, GroupID: "platform-engineers"},
+			{OrgID: 2, TeamID: platform.ID, GroupID: "cn=platform,ou=groups,dc=grafana,dc=org"},
+		}}
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, teamService, userService, orgService, teamSyncService, result)
+		require.NoError(t, err)
+
+		require.True(t, result.Valid)
+		file := filepath.Join(correctProperties, "teams.yaml")
+		require.Equal(t, []dryrun.Change{
+			{Action: dryrun.ActionDelete, OrgID: 1, UID: "legacy", Name: "Legacy", File: file},
+			{Action: dryrun.ActionUpdate, OrgID: 2, UID: "platform", Name: "Platform", File: file, Fields: []string{"members"}},
+			{Action: dryrun.ActionCreate, OrgID: 3, Name: "Support", File: file},
+		}, result.Changes[dryrun.ResourceTeam])
+		require.Contains(t, teamService.teams, legacy.ID)
+		require.Nil(t, teamService.byName(3, "Support"))
+		require.Equal(t, map[int64]dashboardaccess.PermissionType{10: dashboardaccess.PERMISSION_ADMIN, 12: 0}, teamService.members[platform.ID])
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), missingName, newSpyTeamService(), userService, orgtest.NewOrgServiceFake(), teamsynctest.NewFakeService(), result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Equal(t, dryrun.ResourceTeam, result.Errors[0].Resource)
+	})
+
+	t.Run("should report files with unknown members", func(t *testing.T) {
+		orgService := orgtest.NewOrgServiceFake()
+		orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+		noUsers := &spyUserService{FakeUserService: usertest.NewUserServiceFake()}
+
+		result := dryrun.NewResult()
+		err := DryRun(context.Background(), correctProperties, newSpyTeamService(), noUsers, orgService, teamsynctest.NewFakeService(), result)
+		require.NoError(t, err)
+
+		require.False(t, result.Valid)
+		require.Len(t, result.Errors, 1)
+		require.Empty(t, result.Changes[dryrun.ResourceTeam])
+	})
+}
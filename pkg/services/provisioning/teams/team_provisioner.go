@@ -0,0 +1,274 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the teams in those files.
+func Provision(
+	ctx context.Context,
+	configDirectory string,
+	teamService team.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService,
+	acService accesscontrol.Service,
+	userService user.Service,
+	orgService org.Service,
+	provenanceService provenance.Service,
+	teamSyncService teamsync.Service,
+) error {
+	logger := log.New("provisioning.teams")
+	tp := TeamProvisioner{
+		log:                    logger,
+		cfgProvider:            &configReader{log: logger},
+		teamService:            teamService,
+		teamPermissionsService: teamPermissionsService,
+		acService:              acService,
+		userService:            userService,
+		orgService:             orgService,
+		provenanceService:      provenanceService,
+		teamSyncService:        teamSyncService,
+	}
+	return tp.applyChanges(ctx, configDirectory)
+}
+
+// TeamProvisioner is responsible for provisioning teams and their members
+// based on configuration read by the `configReader`
+type TeamProvisioner struct {
+	log                    log.Logger
+	cfgProvider            *configReader
+	teamService            team.Service
+	teamPermissionsService accesscontrol.TeamPermissionsService
+	acService              accesscontrol.Service
+	userService            user.Service
+	orgService             org.Service
+	provenanceService      provenance.Service
+	teamSyncService        teamsync.Service
+}
+
+func (tp *TeamProvisioner) apply(ctx context.Context, cfg *teamsAsConfig) error {
+	for _, t := range cfg.DeleteTeams {
+		orgID, err := tp.orgID(ctx, t.OrgID, t.OrgName)
+		if err != nil {
+			return err
+		}
+
+		existing, err := tp.findTeam(ctx, orgID, t.Name)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			continue
+		}
+
+		tp.log.Debug("Deleting team from configuration", "name", t.Name, "orgId", orgID)
+		if err := tp.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: orgID, ID: existing.ID}); err != nil && !errors.Is(err, team.ErrTeamNotFound) {
+			return err
+		}
+		if err := tp.acService.DeleteTeamPermissions(ctx, orgID, existing.ID); err != nil {
+			return err
+		}
+		if err := tp.provenanceService.DeleteProvisioned(ctx, orgID, provenance.KindTeam, strconv.FormatInt(existing.ID, 10)); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range cfg.Teams {
+		orgID, err := tp.orgID(ctx, t.OrgID, t.OrgName)
+		if err != nil {
+			return err
+		}
+
+		existing, err := tp.findTeam(ctx, orgID, t.Name)
+		if err != nil {
+			return err
+		}
+
+		var teamID int64
+		if existing == nil {
+			tp.log.Info("Inserting team from configuration", "name", t.Name, "orgId", orgID)
+			created, err := tp.teamService.CreateTeam(t.Name, t.Email, orgID)
+			if err != nil {
+				return err
+			}
+			teamID = created.ID
+		} else {
+			tp.log.Debug("Updating team from configuration", "name", t.Name, "orgId", orgID)
+			teamID = existing.ID
+			err := tp.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{ID: teamID, Name: t.Name, Email: t.Email, OrgID: orgID})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tp.provenanceService.SetProvisioned(ctx, orgID, provenance.KindTeam, strconv.FormatInt(teamID, 10)); err != nil {
+			return err
+		}
+
+		if err := tp.syncMembers(ctx, orgID, teamID, t); err != nil {
+			return err
+		}
+
+		if err := tp.syncGroups(ctx, orgID, teamID, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncMembers makes the team members match the configuration. Members
+// synced from an external identity provider are left untouched.
+func (tp *TeamProvisioner) syncMembers(ctx context.Context, orgID, teamID int64, t *teamFromConfig) error {
+	current, err := tp.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{OrgID: orgID, TeamID: teamID, SignedInUser: provisioningUser(orgID)})
+	if err != nil {
+		return err
+	}
+
+	currentPermission := make(map[int64]string, len(current))
+	for _, m := range current {
+		if m.External {
+			continue
+		}
+		currentPermission[m.UserID] = permissionMember
+		if m.Permission == dashboardaccess.PERMISSION_ADMIN {
+			currentPermission[m.UserID] = permissionAdmin
+		}
+	}
+
+	resourceID := strconv.FormatInt(teamID, 10)
+	desired := make(map[int64]bool, len(t.Members))
+	for _, member := range t.Members {
+		u, err := tp.findUser(ctx, member)
+		if err != nil {
+			return fmt.Errorf("failed to find member of team %q: %w", t.Name, err)
+		}
+		desired[u.ID] = true
+
+		if permission, ok := currentPermission[u.ID]; ok && permission == member.Permission {
+			continue
+		}
+		if _, err := tp.teamPermissionsService.SetUserPermission(ctx, orgID, accesscontrol.User{ID: u.ID}, resourceID, member.Permission); err != nil {
+			return err
+		}
+	}
+
+	for userID := range currentPermission {
+		if desired[userID] {
+			continue
+		}
+		tp.log.Debug("Removing team member not in configuration", "team", t.Name, "userId", userID)
+		if _, err := tp.teamPermissionsService.SetUserPermission(ctx, orgID, accesscontrol.User{ID: userID}, resourceID, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncGroups makes the external groups mapped to the team match the configuration.
+func (tp *TeamProvisioner) syncGroups(ctx context.Context, orgID, teamID int64, t *teamFromConfig) error {
+	current, err := tp.teamSyncService.GetTeamGroups(ctx, &teamsync.GetTeamGroupsQuery{OrgID: orgID, TeamID: teamID})
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool, len(current))
+	for _, g := range current {
+		existing[g.GroupID] = true
+	}
+
+	desired := make(map[string]bool, len(t.Groups))
+	for _, group := range t.Groups {
+		desired[group] = true
+		if existing[group] {
+			continue
+		}
+		if err := tp.teamSyncService.AddTeamGroup(ctx, &teamsync.AddTeamGroupCommand{OrgID: orgID, TeamID: teamID, GroupID: group}); err != nil {
+			return fmt.Errorf("failed to add group to team %q: %w", t.Name, err)
+		}
+	}
+
+	for group := range existing {
+		if desired[group] {
+			continue
+		}
+		tp.log.Debug("Removing team group not in configuration", "team", t.Name, "group", group)
+		if err := tp.teamSyncService.RemoveTeamGroup(ctx, &teamsync.RemoveTeamGroupCommand{OrgID: orgID, TeamID: teamID, GroupID: group}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tp *TeamProvisioner) findTeam(ctx context.Context, orgID int64, name string) (*team.TeamDTO, error) {
+	res, err := tp.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        orgID,
+		Name:         name,
+		Limit:        1,
+		Page:         1,
+		SignedInUser: provisioningUser(orgID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Teams) == 0 {
+		return nil, nil
+	}
+	return res.Teams[0], nil
+}
+
+func (tp *TeamProvisioner) findUser(ctx context.Context, member *memberFromConfig) (*user.User, error) {
+	if member.Login != "" {
+		return tp.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: member.Login})
+	}
+	return tp.userService.GetByEmail(ctx, &user.GetUserByEmailQuery{Email: member.Email})
+}
+
+func (tp *TeamProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := tp.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := tp.apply(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tp *TeamProvisioner) orgID(ctx context.Context, orgID int64, orgName string) (int64, error) {
+	if orgID != 0 {
+		return orgID, nil
+	}
+
+	res, err := tp.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: orgName})
+	if err != nil {
+		return 0, err
+	}
+	return res.ID, nil
+}
+
+func provisioningUser(orgID int64) identity.Requester {
+	return accesscontrol.BackgroundUser("team_provisioning", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+	})
+}
@@ -0,0 +1,215 @@
+package teams
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestTeamProvisioner(t *testing.T) {
+	t.Setenv("TEAM_EMAIL", "platform@example.com")
+
+	orgService := orgtest.NewOrgServiceFake()
+	orgService.ExpectedOrg = &org.Org{ID: 3, Name: "Org 3"}
+	userService := &spyUserService{FakeUserService: usertest.NewUserServiceFake(), users: []*user.User{
+		{ID: 10, Login: "alice", Email: "alice@example.com"},
+		{ID: 11, Login: "bob", Email: "bob@example.com"},
+	}}
+
+	t.Run("Creates and deletes teams", func(t *testing.T) {
+		teamService := newSpyTeamService()
+		legacy := teamService.add(1, "Legacy")
+		permissions := &spyTeamPermissions{teamService: teamService}
+		provenanceService := provenancetest.NewFakeService()
+		teamSyncService := teamsynctest.NewFakeService()
+
+		err := Provision(context.Background(), correctProperties, teamService, permissions, actest.FakeService{}, userService, orgService, provenanceService, teamSyncService)
+		require.NoError(t, err)
+
+		require.NotContains(t, teamService.teams, legacy.ID)
+
+		platform := teamService.byName(2, "Platform")
+		require.NotNil(t, platform)
+		require.Equal(t, "platform@example.com", platform.Email)
+		require.Equal(t, map[int64]dashboardaccess.PermissionType{
+			10: dashboardaccess.PERMISSION_ADMIN,
+			11: 0,
+		}, teamService.members[platform.ID])
+
+		provisioned, err := provenanceService.IsProvisioned(context.Background(), 2, provenance.KindTeam, strconv.FormatInt(platform.ID, 10))
+		require.NoError(t, err)
+		require.True(t, provisioned)
+
+		groups, err := teamSyncService.GetTeamGroups(context.Background(), &teamsync.GetTeamGroupsQuery{OrgID: 2, TeamID: platform.ID})
+		require.NoError(t, err)
+		require.Equal(t, []*teamsync.TeamGroupDTO{
+			{OrgID: 2, TeamID: platform.ID, GroupID: "cn=platform,ou=groups,dc=grafana,dc=org"},
+			{OrgID: 2, TeamID: platform.ID, GroupID: "platform-engineers"},
+		}, groups)
+
+		require.NotNil(t, teamService.byName(3, "Support"))
+	})
+
+	t.Run("Updates existing teams and removes members not in configuration", func(t *testing.T) {
+		teamService := newSpyTeamService()
+		platform := teamService.add(2, "Platform")
+		teamService.members[platform.ID] = map[int64]dashboardaccess.PermissionType{10: 0, 12: 0}
+		teamService.external[platform.ID] = map[int64]bool{13: true}
+		permissions := &spyTeamPermissions{teamService: teamService}
+		teamSyncService := &teamsynctest.FakeService{Groups: []*teamsync.TeamGroupDTO{
+			{OrgID: 2, TeamID: platform.ID, GroupID: "platform-engineers"},
+			{OrgID: 2, TeamID: platform.ID, GroupID: "cn=legacy,ou=groups,dc=grafana,dc=org"},
+		}}
+
+		err := Provision(context.Background(), correctProperties, teamService, permissions, actest.FakeService{}, userService, orgService, provenancetest.NewFakeService(), teamSyncService)
+		require.NoError(t, err)
+
+		require.Equal(t, "platform@example.com", teamService.teams[platform.ID].Email)
+		require.Equal(t, map[int64]dashboardaccess.PermissionType{
+			10: dashboardaccess.PERMISSION_ADMIN,
+			11: 0,
+		}, teamService.members[platform.ID])
+		require.True(t, teamService.external[platform.ID][13])
+		require.Equal(t, []*teamsync.TeamGroupDTO{
+			{OrgID: 2, TeamID: platform.ID, GroupID: "platform-engineers"},
+			{OrgID: 2, TeamID: platform.ID, GroupID: "cn=platform,ou=groups,dc=grafana,dc=org"},
+		}, teamSyncService.Groups)
+	})
+}
+
+type spyTeamService struct {
+	*teamtest.FakeService
+	nextID   int64
+	teams    map[int64]*team.TeamDTO
+	members  map[int64]map[int64]dashboardaccess.PermissionType
+	external map[int64]map[int64]bool
+}
+
+func newSpyTeamService() *spyTeamService {
+	return &spyTeamService{
+		FakeService: teamtest.NewFakeService(),
+		teams:       map[int64]*team.TeamDTO{},
+		members:     map[int64]map[int64]dashboardaccess.PermissionType{},
+		external:    map[int64]map[int64]bool{},
+	}
+}
+
+func (s *spyTeamService) add(orgID int64, name string) *team.TeamDTO {
+	s.nextID++
+	t := &team.TeamDTO{ID: s.nextID, OrgID: orgID, Name: name}
+	s.teams[t.ID] = t
+	s.members[t.ID] = map[int64]dashboardaccess.PermissionType{}
+	return t
+}
+
+func (s *spyTeamService) byName(orgID int64, name string) *team.TeamDTO {
+	for _, t := range s.teams {
+		if t.OrgID == orgID && t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *spyTeamService) CreateTeam(name, email string, orgID int64) (team.Team, error) {
+	t := s.add(orgID, name)
+	t.Email = email
+	return team.Team{ID: t.ID, OrgID: orgID, Name: name, Email: email}, nil
+}
+
+func (s *spyTeamService) UpdateTeam(ctx context.Context, cmd *team.UpdateTeamCommand) error {
+	t, ok := s.teams[cmd.ID]
+	if !ok {
+		return team.ErrTeamNotFound
+	}
+	t.Name, t.Email = cmd.Name, cmd.Email
+	return nil
+}
+
+func (s *spyTeamService) DeleteTeam(ctx context.Context, cmd *team.DeleteTeamCommand) error {
+	delete(s.teams, cmd.ID)
+	return nil
+}
+
+func (s *spyTeamService) SearchTeams(ctx context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	res := team.SearchTeamQueryResult{}
+	if t := s.byName(query.OrgID, query.Name); t != nil {
+		res.Teams = append(res.Teams, t)
+	}
+	return res, nil
+}
+
+func (s *spyTeamService) GetTeamMembers(ctx context.Context, query *team.GetTeamMembersQuery) ([]*team.TeamMemberDTO, error) {
+	var res []*team.TeamMemberDTO
+	for userID, permission := range s.members[query.TeamID] {
+		res = append(res, &team.TeamMemberDTO{TeamID: query.TeamID, UserID: userID, Permission: permission})
+	}
+	for userID := range s.external[query.TeamID] {
+		res = append(res, &team.TeamMemberDTO{TeamID: query.TeamID, UserID: userID, External: true})
+	}
+	return res, nil
+}
+
+type spyTeamPermissions struct {
+	teamService *spyTeamService
+}
+
+func (s *spyTeamPermissions) GetPermissions(ctx context.Context, user identity.Requester, resourceID string) ([]accesscontrol.ResourcePermission, error) {
+	return nil, nil
+}
+
+func (s *spyTeamPermissions) SetUserPermission(ctx context.Context, orgID int64, user accesscontrol.User, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
+	teamID, err := strconv.ParseInt(resourceID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	switch permission {
+	case "":
+		delete(s.teamService.members[teamID], user.ID)
+	case permissionAdmin:
+		s.teamService.members[teamID][user.ID] = dashboardaccess.PERMISSION_ADMIN
+	default:
+		s.teamService.members[teamID][user.ID] = 0
+	}
+	return &accesscontrol.ResourcePermission{}, nil
+}
+
+type spyUserService struct {
+	*usertest.FakeUserService
+	users []*user.User
+}
+
+func (s *spyUserService) GetByLogin(ctx context.Context, query *user.GetUserByLoginQuery) (*user.User, error) {
+	for _, u := range s.users {
+		if u.Login == query.LoginOrEmail {
+			return u, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (s *spyUserService) GetByEmail(ctx context.Context, query *user.GetUserByEmailQuery) (*user.User, error) {
+	for _, u := range s.users {
+		if u.Email == query.Email {
+			return u, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
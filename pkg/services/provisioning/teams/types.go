@@ -0,0 +1,119 @@
+package teams
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+const (
+	permissionMember = "Member"
+	permissionAdmin  = "Admin"
+)
+
+// teamsAsConfig is a normalized data object for teams config data. Any config version should be mappable
+// to this type.
+type teamsAsConfig struct {
+	Teams       []*teamFromConfig
+	DeleteTeams []*deleteTeamConfig
+}
+
+type teamFromConfig struct {
+	Name    string
+	Email   string
+	OrgID   int64
+	OrgName string
+	Members []*memberFromConfig
+	// Groups are the external groups, such as LDAP group DNs, whose users
+	// are synced to the team.
+	Groups []string
+}
+
+// memberFromConfig identifies a user by login or email.
+type memberFromConfig struct {
+	Login      string
+	Email      string
+	Permission string
+}
+
+type deleteTeamConfig struct {
+	Name    string
+	OrgID   int64
+	OrgName string
+}
+
+type teamFromConfigV1 struct {
+	Name    values.StringValue    `json:"name" yaml:"name"`
+	Email   values.StringValue    `json:"email" yaml:"email"`
+	OrgID   values.Int64Value     `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue    `json:"orgName" yaml:"orgName"`
+	Members []*memberFromConfigV1 `json:"members" yaml:"members"`
+	Groups  []values.StringValue  `json:"groups" yaml:"groups"`
+}
+
+type memberFromConfigV1 struct {
+	Login      values.StringValue `json:"login" yaml:"login"`
+	Email      values.StringValue `json:"email" yaml:"email"`
+	Permission values.StringValue `json:"permission" yaml:"permission"`
+}
+
+type deleteTeamConfigV1 struct {
+	Name    values.StringValue `json:"name" yaml:"name"`
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	OrgName values.StringValue `json:"orgName" yaml:"orgName"`
+}
+
+// teamsAsConfigV1 is a mapping for version 1 configs. This is mapped to its normalised version.
+type teamsAsConfigV1 struct {
+	APIVersion  values.Int64Value     `json:"apiVersion" yaml:"apiVersion"`
+	Teams       []*teamFromConfigV1   `json:"teams" yaml:"teams"`
+	DeleteTeams []*deleteTeamConfigV1 `json:"deleteTeams" yaml:"deleteTeams"`
+}
+
+// mapToTeamsFromConfig maps config syntax to a normalized teamsAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *teamsAsConfigV1) mapToTeamsFromConfig() *teamsAsConfig {
+	r := &teamsAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, t := range cfg.Teams {
+		members := make([]*memberFromConfig, 0, len(t.Members))
+		for _, member := range t.Members {
+			permission := member.Permission.Value()
+			if permission == "" {
+				permission = permissionMember
+			}
+			members = append(members, &memberFromConfig{
+				Login:      member.Login.Value(),
+				Email:      member.Email.Value(),
+				Permission: permission,
+			})
+		}
+
+		groups := make([]string, 0, len(t.Groups))
+		for _, group := range t.Groups {
+			groups = append(groups, strings.TrimSpace(group.Value()))
+		}
+
+		r.Teams = append(r.Teams, &teamFromConfig{
+			Name:    t.Name.Value(),
+			Email:   t.Email.Value(),
+			OrgID:   t.OrgID.Value(),
+			OrgName: t.OrgName.Value(),
+			Members: members,
+			Groups:  groups,
+		})
+	}
+
+	for _, t := range cfg.DeleteTeams {
+		r.DeleteTeams = append(r.DeleteTeams, &deleteTeamConfig{
+			Name:    t.Name.Value(),
+			OrgID:   t.OrgID.Value(),
+			OrgName: t.OrgName.Value(),
+		})
+	}
+
+	return r
+}
@@ -24,3 +24,16 @@ func CheckOrgExists(ctx context.Context, orgService org.Service, orgID int64) er
 	}
 	return nil
 }
+
+// OrgIDOrDefault returns 0 when the organization of a provisioned resource is
+// identified by its name, and defaults to the main organization when neither
+// is set.
+func OrgIDOrDefault(orgID int64, orgName string) int64 {
+	if orgID >= 1 {
+		return orgID
+	}
+	if orgName == "" {
+		return 1
+	}
+	return 0
+}
@@ -1 +1,14 @@
 package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgIDOrDefault(t *testing.T) {
+	require.Equal(t, int64(2), OrgIDOrDefault(2, "Org 3"))
+	require.Equal(t, int64(0), OrgIDOrDefault(0, "Org 3"))
+	require.Equal(t, int64(1), OrgIDOrDefault(0, ""))
+	require.Equal(t, int64(1), OrgIDOrDefault(-1, ""))
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	// maxUnlockAttempts is the number of failed passphrase attempts allowed per
+	// client address within unlockAttemptsWindow
+	maxUnlockAttempts    = 5
+	unlockAttemptsWindow = 5 * time.Minute
+	// maxUnlockAttemptsPerAccessToken is the number of failed passphrase attempts
+	// allowed from all client addresses within unlockAttemptsWindow, so guessing
+	// the passphrase can't be spread over many addresses
+	maxUnlockAttemptsPerAccessToken = 50
+
+	defaultAccessLogLimit = 100
+	maxAccessLogLimit     = 1000
+)
+
+// swagger:route POST /public/dashboards/{accessToken}/unlock dashboard_public unlockPublicDashboard
+//
+//	Unlock a passphrase protected public dashboard
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 429: tooManyRequestsPublicError
+// 500: internalServerPublicError
+func (api *Api) UnlockPublicDashboard(c *contextmodel.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !validation.IsValidAccessToken(accessToken) {
+		return response.Err(ErrInvalidAccessToken.Errorf("UnlockPublicDashboard: invalid access token"))
+	}
+
+	dto := UnlockPublicDashboardDTO{}
+	if err := web.Bind(c.Req, &dto); err != nil {
+		return response.Err(ErrBadRequest.Errorf("UnlockPublicDashboard: error parsing request: %v", err))
+	}
+
+	pd, err := api.PublicDashboardService.FindByAccessToken(c.Req.Context(), accessToken)
+	if err != nil {
+		return response.Err(err)
+	}
+	if !pd.IsEnabled {
+		return response.Err(ErrPublicDashboardNotEnabled.Errorf("UnlockPublicDashboard: public dashboard is paused"))
+	}
+
+	now := time.Now()
+	remoteAddr := ClientAddr(c.Req, api.cfg.PublicDashboardsTrustedProxies)
+
+	if result, err := CheckPublicDashboardAccess(pd, remoteAddr, now); err != nil {
+		api.recordAccess(c, pd, AccessActionUnlock, result)
+		return response.Err(err)
+	}
+
+	failures, err := api.PublicDashboardService.FindAccessLog(c.Req.Context(), AccessLogQuery{
+		PublicDashboardUid: pd.Uid,
+		RemoteAddr:         remoteAddr,
+		Result:             AccessResultPassphraseInvalid,
+		Since:              now.Add(-unlockAttemptsWindow),
+		Limit:              maxUnlockAttempts,
+	})
+	if err != nil {
+		return response.Err(err)
+	}
+	if len(failures) >= maxUnlockAttempts {
+		api.recordAccess(c, pd, AccessActionUnlock, AccessResultRateLimited)
+		return response.Err(ErrPublicDashboardTooManyAttempts.Errorf("UnlockPublicDashboard: too many failed attempts from %s", remoteAddr))
+	}
+
+	// the access token identifies the public dashboard
+	failures, err = api.PublicDashboardService.FindAccessLog(c.Req.Context(), AccessLogQuery{
+		PublicDashboardUid: pd.Uid,
+		Result:             AccessResultPassphraseInvalid,
+		Since:              now.Add(-unlockAttemptsWindow),
+		Limit:              maxUnlockAttemptsPerAccessToken,
+	})
+	if err != nil {
+		return response.Err(err)
+	}
+	if len(failures) >= maxUnlockAttemptsPerAccessToken {
+		api.recordAccess(c, pd, AccessActionUnlock, AccessResultRateLimited)
+		return response.Err(ErrPublicDashboardTooManyAttempts.Errorf("UnlockPublicDashboard: too many failed attempts"))
+	}
+
+	if !pd.VerifyPassphrase(dto.Passphrase) {
+		api.recordAccess(c, pd, AccessActionUnlock, AccessResultPassphraseInvalid)
+		return response.Err(ErrPublicDashboardPassphraseRequired.Errorf("UnlockPublicDashboard: invalid passphrase"))
+	}
+
+	api.recordAccess(c, pd, AccessActionUnlock, AccessResultAllowed)
+
+	if pd.RequiresPassphrase() {
+		expiry := now.Add(unlockCookieTTL)
+		cookies.WriteCookie(c.Resp, UnlockCookieName(accessToken), NewUnlockCookieValue(api.cfg.SecretKey, pd, expiry), int(unlockCookieTTL.Seconds()), nil)
+	}
+
+	return response.Success("Public dashboard unlocked")
+}
+
+// swagger:route GET /dashboards/uid/{dashboardUid}/public-dashboards/{uid}/access-log dashboard_public getPublicDashboardAccessLog
+//
+//	Get the access log of a public dashboard
+//
+// Responses:
+// 200: getPublicDashboardAccessLogResponse
+// 400: badRequestPublicError
+// 401: unauthorisedPublicError
+// 403: forbiddenPublicError
+// 404: notFoundPublicError
+// 500: internalServerPublicError
+func (api *Api) GetPublicDashboardAccessLog(c *contextmodel.ReqContext) response.Response {
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !validation.IsValidShortUID(dashboardUid) {
+		return response.Err(ErrInvalidUid.Errorf("GetPublicDashboardAccessLog: invalid dashboard Uid %s", dashboardUid))
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("GetPublicDashboardAccessLog: invalid Uid %s", uid))
+	}
+
+	pd, err := api.PublicDashboardService.Find(c.Req.Context(), uid)
+	if err != nil {
+		return response.Err(err)
+	}
+	if pd == nil || pd.DashboardUid != dashboardUid || pd.OrgId != c.SignedInUser.GetOrgID() {
+		return response.Err(ErrPublicDashboardNotFound.Errorf("GetPublicDashboardAccessLog: public dashboard not found"))
+	}
+
+	limit := c.QueryInt("limit")
+	if limit <= 0 {
+		limit = defaultAccessLogLimit
+	}
+	if limit > maxAccessLogLimit {
+		limit = maxAccessLogLimit
+	}
+
+	entries, err := api.PublicDashboardService.FindAccessLog(c.Req.Context(), AccessLogQuery{
+		PublicDashboardUid: pd.Uid,
+		Result:             c.Query("result"),
+		Limit:              limit,
+	})
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, entries)
+}
+
+func (api *Api) recordAccess(c *contextmodel.ReqContext, pd *PublicDashboard, action string, result string) {
+	remoteAddr := ClientAddr(c.Req, api.cfg.PublicDashboardsTrustedProxies)
+	if !api.throttle.allow(pd.Uid, action, result, remoteAddr, time.Now()) {
+		return
+	}
+	if err := api.PublicDashboardService.RecordAccess(c.Req.Context(), pd, action, result, remoteAddr, c.Req.UserAgent()); err != nil {
+		api.log.Warn("Failed to record public dashboard access", "publicDashboardUid", pd.Uid, "error", err)
+	}
+}
+
+// swagger:parameters unlockPublicDashboard
+type UnlockPublicDashboardParams struct {
+	// in:path
+	// required:true
+	AccessToken string `json:"accessToken"`
+	// in:body
+	// required:true
+	Body UnlockPublicDashboardDTO
+}
+
+// swagger:parameters getPublicDashboardAccessLog
+type GetPublicDashboardAccessLogParams struct {
+	// in:path
+	// required:true
+	DashboardUid string `json:"dashboardUid"`
+	// in:path
+	// required:true
+	Uid string `json:"uid"`
+	// in:query
+	// required:false
+	Limit int `json:"limit"`
+	// in:query
+	// required:false
+	Result string `json:"result"`
+}
+
+// swagger:response getPublicDashboardAccessLogResponse
+type GetPublicDashboardAccessLogResponse struct {
+	// in: body
+	Body []*AccessLogEntry `json:"body"`
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+func TestAPIUnlockPublicDashboard(t *testing.T) {
+	pd := &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true}
+	require.NoError(t, pd.SetPassphrase("correct horse"))
+	path := fmt.Sprintf("/api/public/dashboards/%s/unlock", validAccessToken)
+
+	failures := func(n int) []*AccessLogEntry {
+		entries := make([]*AccessLogEntry, n)
+		for i := range entries {
+			entries[i] = &AccessLogEntry{PublicDashboardUid: pd.Uid, Result: AccessResultPassphraseInvalid}
+		}
+		return entries
+	}
+	fromClient := mock.MatchedBy(func(q AccessLogQuery) bool { return q.Limit == maxUnlockAttempts })
+	fromAnyClient := mock.MatchedBy(func(q AccessLogQuery) bool { return q.Limit == maxUnlockAttemptsPerAccessToken })
+
+	testCases := []struct {
+		Name                 string
+		Passphrase           string
+		ClientFailures       int
+		AccessTokenFailures  int
+		ExpectedHttpResponse int
+		ExpectedResult       string
+	}{
+		{
+			Name:                 "Unlocks with the passphrase",
+			Passphrase:           "correct horse",
+			ExpectedHttpResponse: http.StatusOK,
+			ExpectedResult:       AccessResultAllowed,
+		},
+		{
+			Name:                 "Rejects an invalid passphrase",
+			Passphrase:           "wrong",
+			ExpectedHttpResponse: http.StatusUnauthorized,
+			ExpectedResult:       AccessResultPassphraseInvalid,
+		},
+		{
+			Name:                 "Rate limits the failed attempts of a client",
+			Passphrase:           "correct horse",
+			ClientFailures:       maxUnlockAttempts,
+			ExpectedHttpResponse: http.StatusTooManyRequests,
+			ExpectedResult:       AccessResultRateLimited,
+		},
+		{
+			Name:                 "Rate limits the failed attempts of the access token from all clients",
+			Passphrase:           "correct horse",
+			AccessTokenFailures:  maxUnlockAttemptsPerAccessToken,
+			ExpectedHttpResponse: http.StatusTooManyRequests,
+			ExpectedResult:       AccessResultRateLimited,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			service.On("FindByAccessToken", mock.Anything, validAccessToken).Return(pd, nil)
+			service.On("FindAccessLog", mock.Anything, fromClient).Return(failures(test.ClientFailures), nil)
+			service.On("FindAccessLog", mock.Anything, fromAnyClient).Return(failures(test.AccessTokenFailures), nil).Maybe()
+			service.On("RecordAccess", mock.Anything, pd, AccessActionUnlock, test.ExpectedResult, mock.Anything, mock.Anything).Return(nil)
+
+			testServer := setupTestServer(t, nil, service, nil, true)
+			body := strings.NewReader(fmt.Sprintf(`{"passphrase": %q}`, test.Passphrase))
+			response := callAPI(testServer, http.MethodPost, path, body, t)
+			require.Equal(t, test.ExpectedHttpResponse, response.Code)
+		})
+	}
+}
+
+func TestAccessThrottle(t *testing.T) {
+	now := time.Now()
+
+	t.Run("records a denied access once per interval", func(t *testing.T) {
+		throttle := newAccessThrottle()
+		require.True(t, throttle.allow("pd", AccessActionQuery, AccessResultExpired, "10.0.0.1", now))
+		require.False(t, throttle.allow("pd", AccessActionQuery, AccessResultExpired, "10.0.0.1", now.Add(time.Second)))
+		require.True(t, throttle.allow("pd", AccessActionQuery, AccessResultExpired, "10.0.0.2", now))
+		require.True(t, throttle.allow("pd", AccessActionQuery, AccessResultNetworkDenied, "10.0.0.1", now))
+		require.True(t, throttle.allow("pd", AccessActionQuery, AccessResultExpired, "10.0.0.1", now.Add(deniedAccessInterval)))
+	})
+
+	t.Run("always records allowed accesses and invalid passphrases", func(t *testing.T) {
+		throttle := newAccessThrottle()
+		for i := 0; i < 3; i++ {
+			require.True(t, throttle.allow("pd", AccessActionView, AccessResultAllowed, "10.0.0.1", now))
+			require.True(t, throttle.allow("pd", AccessActionUnlock, AccessResultPassphraseInvalid, "10.0.0.1", now))
+		}
+	})
+
+	t.Run("drops denied accesses of new clients when full", func(t *testing.T) {
+		throttle := newAccessThrottle()
+		for i := 0; i < maxDeniedAccessKeys; i++ {
+			require.True(t, throttle.allow("pd", AccessActionQuery, AccessResultExpired, fmt.Sprintf("client-%d", i), now))
+		}
+		require.False(t, throttle.allow("pd", AccessActionQuery, AccessResultExpired, "10.0.0.1", now))
+		require.True(t, throttle.allow("pd", AccessActionQuery, AccessResultExpired, "10.0.0.1", now.Add(deniedAccessInterval)))
+	})
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const (
+	// deniedAccessInterval is the interval within which the same denied access
+	// of a client is only recorded once
+	deniedAccessInterval = time.Minute
+	// maxDeniedAccessKeys bounds the memory used to throttle the denied accesses,
+	// new denied accesses are not recorded when it is reached
+	maxDeniedAccessKeys = 10000
+)
+
+type deniedAccessKey struct {
+	publicDashboardUid string
+	action             string
+	result             string
+	remoteAddr         string
+}
+
+// accessThrottle limits the access log rows written for denied requests, which
+// can be sent by anyone without authentication.
+type accessThrottle struct {
+	mu       sync.Mutex
+	recorded map[deniedAccessKey]time.Time
+}
+
+func newAccessThrottle() *accessThrottle {
+	return &accessThrottle{recorded: map[deniedAccessKey]time.Time{}}
+}
+
+// allow returns true when the access should be recorded. Allowed accesses and
+// invalid passphrases, which the unlock rate limit counts, are always recorded.
+func (t *accessThrottle) allow(pdUid string, action string, result string, remoteAddr string, now time.Time) bool {
+	if t == nil || result == AccessResultAllowed || result == AccessResultPassphraseInvalid {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := deniedAccessKey{publicDashboardUid: pdUid, action: action, result: result, remoteAddr: remoteAddr}
+	if last, ok := t.recorded[key]; ok && now.Sub(last) < deniedAccessInterval {
+		return false
+	}
+
+	if _, ok := t.recorded[key]; !ok && len(t.recorded) >= maxDeniedAccessKeys {
+		t.prune(now)
+		if len(t.recorded) >= maxDeniedAccessKeys {
+			return false
+		}
+	}
+
+	t.recorded[key] = now
+	return true
+}
+
+func (t *accessThrottle) prune(now time.Time) {
+	for key, last := range t.recorded {
+		if now.Sub(last) >= deniedAccessInterval {
+			delete(t.recorded, key)
+		}
+	}
+}
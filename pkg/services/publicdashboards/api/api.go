@@ -32,6 +32,7 @@ type Api struct {
 	license       licensing.Licensing
 	log           log.Logger
 	routeRegister routing.RouteRegister
+	throttle      *accessThrottle
 }
 
 func ProvideApi(
@@ -52,6 +53,7 @@ func ProvideApi(
 		license:                license,
 		log:                    log.New("publicdashboards.api"),
 		routeRegister:          rr,
+		throttle:               newAccessThrottle(),
 	}
 
 	// register endpoints if the feature is enabled
@@ -78,6 +80,10 @@ func (api *Api) RegisterAPIEndpoints() {
 		apiRoute.Post("/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
 	}, api.Middleware.HandleApi)
 
+	// Unlock passphrase protected public dashboard, outside the group as it must
+	// be reachable before the public dashboard is unlocked
+	api.routeRegister.Post("/api/public/dashboards/:accessToken/unlock", routing.Wrap(api.UnlockPublicDashboard))
+
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.accessControl)
 	uidScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(accesscontrol.Parameter(":dashboardUid"))
@@ -103,6 +109,11 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.routeRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// Get public dashboard access log
+	api.routeRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/access-log",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.GetPublicDashboardAccessLog))
 }
 
 // swagger:route GET /dashboards/public-dashboards dashboard_public listPublicDashboards
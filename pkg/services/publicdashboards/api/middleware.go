@@ -1,12 +1,22 @@
 package api
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -68,21 +78,203 @@ func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 	}
 }
 
-// Empty middleware created in order to bind the enterprise one
+// Middleware enforces the access restrictions of a public dashboard: expiry,
+// allowed networks and passphrase. The enterprise middleware is bound in its place.
 type Middleware struct {
+	cfg      *setting.Cfg
+	service  publicdashboards.Service
+	log      log.Logger
+	throttle *accessThrottle
 }
 
 var _ publicdashboards.Middleware = (*Middleware)(nil)
 
-func ProvideMiddleware() *Middleware {
-	return &Middleware{}
+func ProvideMiddleware(cfg *setting.Cfg, service publicdashboards.Service) *Middleware {
+	return &Middleware{
+		cfg:      cfg,
+		service:  service,
+		log:      log.New("publicdashboards.middleware"),
+		throttle: newAccessThrottle(),
+	}
 }
+
+// HandleApi rejects api requests to a public dashboard that is expired, is not
+// accessible from the client network, or has not been unlocked with its passphrase
 func (m *Middleware) HandleApi(c *contextmodel.ReqContext) {
+	pd := m.findPublicDashboard(c)
+	if pd == nil {
+		return
+	}
+
+	if result, err := CheckPublicDashboardAccess(pd, ClientAddr(c.Req, m.cfg.PublicDashboardsTrustedProxies), time.Now()); err != nil {
+		m.recordAccess(c, pd, AccessActionQuery, result)
+		c.WriteErr(err)
+		return
+	}
+
+	if pd.RequiresPassphrase() && !IsUnlocked(c.Req, m.cfg.SecretKey, pd, time.Now()) {
+		m.recordAccess(c, pd, AccessActionQuery, AccessResultPassphraseRequired)
+		c.WriteErr(ErrPublicDashboardPassphraseRequired.Errorf("HandleApi: public dashboard %s is locked", pd.Uid))
+		return
+	}
 }
+
+// HandleView rejects views of a public dashboard that is expired or is not
+// accessible from the client network, and audits the view. The passphrase is
+// asked by the frontend when the api responds with 401.
 func (m *Middleware) HandleView(c *contextmodel.ReqContext) {
+	pd := m.findPublicDashboard(c)
+	if pd == nil {
+		return
+	}
+
+	if result, err := CheckPublicDashboardAccess(pd, ClientAddr(c.Req, m.cfg.PublicDashboardsTrustedProxies), time.Now()); err != nil {
+		m.recordAccess(c, pd, AccessActionView, result)
+		m.log.Debug("Public dashboard access denied", "publicDashboardUid", pd.Uid, "error", err)
+		c.Handle(m.cfg, http.StatusForbidden, "Public dashboard not accessible", nil)
+		return
+	}
+
+	m.recordAccess(c, pd, AccessActionView, AccessResultAllowed)
 }
+
 func (m *Middleware) HandleAccessView(c *contextmodel.ReqContext) {
 }
+
 func (m *Middleware) HandleConfirmAccessView(c *contextmodel.ReqContext) {
+}
+
+// findPublicDashboard returns the enabled public dashboard of the request access
+// token, or nil when there is nothing to enforce and the handlers should respond
+func (m *Middleware) findPublicDashboard(c *contextmodel.ReqContext) *PublicDashboard {
+	if m.service == nil {
+		return nil
+	}
+
+	accessToken, ok := web.Params(c.Req)[":accessToken"]
+	if !ok || !validation.IsValidAccessToken(accessToken) {
+		return nil
+	}
+
+	pd, err := m.service.FindByAccessToken(c.Req.Context(), accessToken)
+	if err != nil || pd == nil || !pd.IsEnabled {
+		return nil
+	}
+
+	return pd
+}
+
+func (m *Middleware) recordAccess(c *contextmodel.ReqContext, pd *PublicDashboard, action string, result string) {
+	remoteAddr := ClientAddr(c.Req, m.cfg.PublicDashboardsTrustedProxies)
+	if !m.throttle.allow(pd.Uid, action, result, remoteAddr, time.Now()) {
+		return
+	}
+	if err := m.service.RecordAccess(c.Req.Context(), pd, action, result, remoteAddr, c.Req.UserAgent()); err != nil {
+		m.log.Warn("Failed to record public dashboard access", "publicDashboardUid", pd.Uid, "error", err)
+	}
+}
+
+// ClientAddr returns the IP address of the client. The X-Forwarded-For and
+// X-Real-IP headers can be set by anyone, so they are only used when the
+// request comes from one of the trusted proxies.
+func ClientAddr(req *http.Request, trustedProxies []string) string {
+	addr := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	if !isTrustedProxy(addr, trustedProxies) {
+		return addr
+	}
+
+	// proxies append the address they received the request from, the client
+	// is the last address not added by a trusted proxy
+	if forwarded := req.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			addr = hop
+			if !isTrustedProxy(hop, trustedProxies) {
+				break
+			}
+		}
+		return addr
+	}
+
+	if realIP := strings.TrimSpace(req.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return addr
+}
+
+func isTrustedProxy(addr string, trustedProxies []string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range trustedProxies {
+		if _, cidr, err := net.ParseCIDR(proxy); err == nil {
+			if cidr.Contains(ip) {
+				return true
+			}
+		} else if trusted := net.ParseIP(proxy); trusted != nil && trusted.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPublicDashboardAccess returns an error, and the result to store in the access
+// log, if the public dashboard is expired at t or is not accessible from the remote address
+func CheckPublicDashboardAccess(pd *PublicDashboard, remoteAddr string, t time.Time) (string, error) {
+	if pd.IsExpired(t) {
+		return AccessResultExpired, ErrPublicDashboardExpired.Errorf("public dashboard %s expired at %s", pd.Uid, pd.ExpiresAt)
+	}
+	if !pd.AllowsIP(remoteAddr) {
+		return AccessResultNetworkDenied, ErrPublicDashboardNetworkDenied.Errorf("public dashboard %s is not accessible from %s", pd.Uid, remoteAddr)
+	}
+	return AccessResultAllowed, nil
+}
+
+// unlockCookieTTL is how long a viewer stays unlocked after entering the passphrase
+const unlockCookieTTL = 24 * time.Hour
+
+// UnlockCookieName returns the name of the cookie that unlocks the public dashboard
+func UnlockCookieName(accessToken string) string {
+	return "grafana_public_dashboard_" + accessToken
+}
+
+// NewUnlockCookieValue returns a cookie value, signed with the secret key, that
+// unlocks the public dashboard until expiry. Changing the passphrase invalidates it.
+func NewUnlockCookieValue(secretKey string, pd *PublicDashboard, expiry time.Time) string {
+	exp := strconv.FormatInt(expiry.Unix(), 10)
+	return exp + "." + signUnlock(secretKey, pd, exp)
+}
+
+// IsUnlocked returns true if the request has a valid unlock cookie for the public dashboard
+func IsUnlocked(req *http.Request, secretKey string, pd *PublicDashboard, t time.Time) bool {
+	cookie, err := req.Cookie(UnlockCookieName(pd.AccessToken))
+	if err != nil {
+		return false
+	}
+
+	exp, sig, ok := strings.Cut(cookie.Value, ".")
+	if !ok {
+		return false
+	}
+
+	expiry, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || t.Unix() >= expiry {
+		return false
+	}
+
+	return hmac.Equal([]byte(sig), []byte(signUnlock(secretKey, pd, exp)))
+}
 
+func signUnlock(secretKey string, pd *PublicDashboard, exp string) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(pd.AccessToken + "|" + pd.PassphraseHash + "|" + exp))
+	return hex.EncodeToString(mac.Sum(nil))
 }
@@ -5,13 +5,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -152,6 +156,228 @@ func TestSetPublicDashboardFlag(t *testing.T) {
 	})
 }
 
+func TestMiddlewareHandleApi(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	cfg := setting.NewCfg()
+	cfg.SecretKey = "secret"
+
+	locked := &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true}
+	require.NoError(t, locked.SetPassphrase("correct horse"))
+
+	tests := []struct {
+		Name                 string
+		PublicDashboard      *PublicDashboard
+		RemoteAddr           string
+		Headers              map[string]string
+		TrustedProxies       []string
+		Cookie               *http.Cookie
+		ExpectedResponseCode int
+		ExpectedResult       string
+	}{
+		{
+			Name:                 "Allows public dashboard without restrictions",
+			PublicDashboard:      &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true},
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Leaves paused public dashboard to the handler",
+			PublicDashboard:      &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: false, ExpiresAt: &past},
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Returns 403 when public dashboard expired",
+			PublicDashboard:      &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true, ExpiresAt: &past},
+			ExpectedResponseCode: http.StatusForbidden,
+			ExpectedResult:       AccessResultExpired,
+		},
+		{
+			Name:                 "Returns 403 when client is not in the allowed networks",
+			PublicDashboard:      &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true, AllowedNetworks: []string{"10.0.0.0/8"}},
+			RemoteAddr:           "192.168.0.1:1234",
+			ExpectedResponseCode: http.StatusForbidden,
+			ExpectedResult:       AccessResultNetworkDenied,
+		},
+		{
+			Name:                 "Allows client in the allowed networks",
+			PublicDashboard:      &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true, AllowedNetworks: []string{"10.0.0.0/8"}},
+			RemoteAddr:           "10.1.2.3:1234",
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Returns 403 when forwarded headers are spoofed by the client",
+			PublicDashboard:      &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true, AllowedNetworks: []string{"10.0.0.0/8"}},
+			RemoteAddr:           "192.168.0.1:1234",
+			Headers:              map[string]string{"X-Forwarded-For": "10.1.2.3", "X-Real-IP": "10.1.2.3"},
+			ExpectedResponseCode: http.StatusForbidden,
+			ExpectedResult:       AccessResultNetworkDenied,
+		},
+		{
+			Name:                 "Returns 403 when forwarded headers are spoofed through a trusted proxy",
+			PublicDashboard:      &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true, AllowedNetworks: []string{"10.0.0.0/8"}},
+			RemoteAddr:           "172.16.0.1:1234",
+			Headers:              map[string]string{"X-Forwarded-For": "10.1.2.3, 192.168.0.1"},
+			TrustedProxies:       []string{"172.16.0.0/12"},
+			ExpectedResponseCode: http.StatusForbidden,
+			ExpectedResult:       AccessResultNetworkDenied,
+		},
+		{
+			Name:                 "Allows client in the allowed networks behind a trusted proxy",
+			PublicDashboard:      &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true, AllowedNetworks: []string{"10.0.0.0/8"}},
+			RemoteAddr:           "172.16.0.1:1234",
+			Headers:              map[string]string{"X-Forwarded-For": "10.1.2.3"},
+			TrustedProxies:       []string{"172.16.0.0/12"},
+			ExpectedResponseCode: http.StatusOK,
+		},
+		{
+			Name:                 "Returns 401 when public dashboard is locked",
+			PublicDashboard:      locked,
+			ExpectedResponseCode: http.StatusUnauthorized,
+			ExpectedResult:       AccessResultPassphraseRequired,
+		},
+		{
+			Name:                 "Returns 401 when unlock cookie is forged",
+			PublicDashboard:      locked,
+			Cookie:               &http.Cookie{Name: UnlockCookieName(validAccessToken), Value: NewUnlockCookieValue("another secret", locked, time.Now().Add(time.Hour))},
+			ExpectedResponseCode: http.StatusUnauthorized,
+			ExpectedResult:       AccessResultPassphraseRequired,
+		},
+		{
+			Name:                 "Returns 401 when unlock cookie expired",
+			PublicDashboard:      locked,
+			Cookie:               &http.Cookie{Name: UnlockCookieName(validAccessToken), Value: NewUnlockCookieValue(cfg.SecretKey, locked, past)},
+			ExpectedResponseCode: http.StatusUnauthorized,
+			ExpectedResult:       AccessResultPassphraseRequired,
+		},
+		{
+			Name:                 "Allows unlocked public dashboard",
+			PublicDashboard:      locked,
+			Cookie:               &http.Cookie{Name: UnlockCookieName(validAccessToken), Value: NewUnlockCookieValue(cfg.SecretKey, locked, time.Now().Add(time.Hour))},
+			ExpectedResponseCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+			publicdashboardService.On("FindByAccessToken", mock.Anything, validAccessToken).Return(tt.PublicDashboard, nil)
+			if tt.ExpectedResult != "" {
+				publicdashboardService.On("RecordAccess", mock.Anything, tt.PublicDashboard, AccessActionQuery, tt.ExpectedResult, mock.Anything, mock.Anything).Return(nil)
+			}
+
+			cfg.PublicDashboardsTrustedProxies = tt.TrustedProxies
+			m := ProvideMiddleware(cfg, publicdashboardService)
+			params := map[string]string{":accessToken": validAccessToken}
+			mw := func(c *contextmodel.ReqContext) {
+				if tt.RemoteAddr != "" {
+					c.Req.RemoteAddr = tt.RemoteAddr
+				}
+				for k, v := range tt.Headers {
+					c.Req.Header.Set(k, v)
+				}
+				if tt.Cookie != nil {
+					c.Req.AddCookie(tt.Cookie)
+				}
+				m.HandleApi(c)
+			}
+			ctx := &contextmodel.ReqContext{Logger: log.New("publicdashboards-test")}
+			_, resp := runMw(t, ctx, "GET", "/api/public/dashboards/"+validAccessToken, params, mw)
+			require.Equal(t, tt.ExpectedResponseCode, resp.Code)
+			publicdashboardService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Records repeated denied requests once", func(t *testing.T) {
+		pd := &PublicDashboard{Uid: "pd", AccessToken: validAccessToken, IsEnabled: true, ExpiresAt: &past}
+		publicdashboardService := &publicdashboards.FakePublicDashboardService{}
+		publicdashboardService.On("FindByAccessToken", mock.Anything, validAccessToken).Return(pd, nil)
+		publicdashboardService.On("RecordAccess", mock.Anything, pd, AccessActionQuery, AccessResultExpired, mock.Anything, mock.Anything).Return(nil).Once()
+
+		cfg.PublicDashboardsTrustedProxies = nil
+		m := ProvideMiddleware(cfg, publicdashboardService)
+		params := map[string]string{":accessToken": validAccessToken}
+		for i := 0; i < 3; i++ {
+			ctx := &contextmodel.ReqContext{Logger: log.New("publicdashboards-test")}
+			_, resp := runMw(t, ctx, "GET", "/api/public/dashboards/"+validAccessToken, params, m.HandleApi)
+			require.Equal(t, http.StatusForbidden, resp.Code)
+		}
+		publicdashboardService.AssertNumberOfCalls(t, "RecordAccess", 1)
+	})
+
+	t.Run("Does nothing without a service", func(t *testing.T) {
+		params := map[string]string{":accessToken": validAccessToken}
+		_, resp := runMw(t, nil, "GET", "/api/public/dashboards/"+validAccessToken, params, (&Middleware{}).HandleApi)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
+
+func TestClientAddr(t *testing.T) {
+	trusted := []string{"172.16.0.0/12", "192.168.0.10"}
+
+	tests := []struct {
+		Name           string
+		RemoteAddr     string
+		Headers        map[string]string
+		TrustedProxies []string
+		Expected       string
+	}{
+		{
+			Name:       "Uses the connection address",
+			RemoteAddr: "10.1.2.3:1234",
+			Expected:   "10.1.2.3",
+		},
+		{
+			Name:       "Uses the connection address of IPv6 clients",
+			RemoteAddr: "[2001:db8::1]:1234",
+			Expected:   "2001:db8::1",
+		},
+		{
+			Name:       "Ignores forwarded headers without trusted proxies",
+			RemoteAddr: "10.1.2.3:1234",
+			Headers:    map[string]string{"X-Forwarded-For": "192.168.0.1", "X-Real-IP": "192.168.0.1"},
+			Expected:   "10.1.2.3",
+		},
+		{
+			Name:           "Ignores forwarded headers from untrusted clients",
+			RemoteAddr:     "10.1.2.3:1234",
+			Headers:        map[string]string{"X-Forwarded-For": "192.168.0.1", "X-Real-IP": "192.168.0.1"},
+			TrustedProxies: trusted,
+			Expected:       "10.1.2.3",
+		},
+		{
+			Name:           "Uses X-Real-IP from a trusted proxy",
+			RemoteAddr:     "192.168.0.10:1234",
+			Headers:        map[string]string{"X-Real-IP": "10.1.2.3"},
+			TrustedProxies: trusted,
+			Expected:       "10.1.2.3",
+		},
+		{
+			Name:           "Uses the last untrusted address of X-Forwarded-For",
+			RemoteAddr:     "172.16.0.1:1234",
+			Headers:        map[string]string{"X-Forwarded-For": "10.0.0.1, 10.1.2.3, 172.16.0.2"},
+			TrustedProxies: trusted,
+			Expected:       "10.1.2.3",
+		},
+		{
+			Name:           "Stops at invalid addresses in X-Forwarded-For",
+			RemoteAddr:     "172.16.0.1:1234",
+			Headers:        map[string]string{"X-Forwarded-For": "10.1.2.3, invalid"},
+			TrustedProxies: trusted,
+			Expected:       "172.16.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.RemoteAddr
+			for k, v := range tt.Headers {
+				req.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.Expected, ClientAddr(req, tt.TrustedProxies))
+		})
+	}
+}
+
 // This is a helper to test middleware. It handles creating a
 // proper contextmodel.ReqContext, setting web parameters, executing middleware, and
 // returning a response. Response will default to result of
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -113,6 +114,7 @@ func (d *PublicDashboardStoreImpl) Find(ctx context.Context, uid string) (*Publi
 		return nil, nil
 	}
 
+	publicDashboard.HasPassphrase = publicDashboard.RequiresPassphrase()
 	return publicDashboard, nil
 }
 
@@ -138,6 +140,7 @@ func (d *PublicDashboardStoreImpl) FindByAccessToken(ctx context.Context, access
 		return nil, nil
 	}
 
+	publicDashboard.HasPassphrase = publicDashboard.RequiresPassphrase()
 	return publicDashboard, nil
 }
 
@@ -166,6 +169,7 @@ func (d *PublicDashboardStoreImpl) FindByDashboardUid(ctx context.Context, orgId
 		return nil, nil
 	}
 
+	publicDashboard.HasPassphrase = publicDashboard.RequiresPassphrase()
 	return publicDashboard, nil
 }
 
@@ -247,12 +251,33 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		templateVariablesJSON, err := json.Marshal(cmd.PublicDashboard.TemplateVariables)
+		if err != nil {
+			return err
+		}
+
+		allowedNetworksJSON, err := json.Marshal(cmd.PublicDashboard.AllowedNetworks)
+		if err != nil {
+			return err
+		}
+
+		var expiresAt any
+		if cmd.PublicDashboard.ExpiresAt != nil && !cmd.PublicDashboard.ExpiresAt.IsZero() {
+			expiresAt = cmd.PublicDashboard.ExpiresAt.UTC().Format("2006-01-02 15:04:05")
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, template_variables_enabled = ?, share = ?, time_settings = ?, template_variables = ?, expires_at = ?, allowed_networks = ?, passphrase_hash = ?, passphrase_salt = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
+			cmd.PublicDashboard.TemplateVariablesEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			string(templateVariablesJSON),
+			expiresAt,
+			string(allowedNetworksJSON),
+			cmd.PublicDashboard.PassphraseHash,
+			cmd.PublicDashboard.PassphraseSalt,
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
 			cmd.PublicDashboard.Uid)
@@ -309,3 +334,49 @@ func (d *PublicDashboardStoreImpl) GetMetrics(ctx context.Context) (*Metrics, er
 
 	return metrics, nil
 }
+
+// CreateAccessLogEntry stores an audit record of an access to a public dashboard
+func (d *PublicDashboardStoreImpl) CreateAccessLogEntry(ctx context.Context, entry *AccessLogEntry) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(entry)
+		return err
+	})
+}
+
+// FindAccessLog Returns the access log entries of a public dashboard, newest first
+func (d *PublicDashboardStoreImpl) FindAccessLog(ctx context.Context, query AccessLogQuery) ([]*AccessLogEntry, error) {
+	entries := make([]*AccessLogEntry, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("public_dashboard_uid = ?", query.PublicDashboardUid)
+		if query.RemoteAddr != "" {
+			q = q.And("remote_addr = ?", query.RemoteAddr)
+		}
+		if query.Result != "" {
+			q = q.And("result = ?", query.Result)
+		}
+		if !query.Since.IsZero() {
+			q = q.And("created_at >= ?", query.Since)
+		}
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Desc("created_at").Find(&entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// DeleteAccessLogBefore deletes the access log entries created before t
+func (d *PublicDashboardStoreImpl) DeleteAccessLogBefore(ctx context.Context, t time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Where("created_at < ?", t).Delete(&AccessLogEntry{})
+		return err
+	})
+
+	return affectedRows, err
+}
@@ -624,6 +624,41 @@ func TestIntegrationUpdatePublicDashboard(t *testing.T) {
 		assert.NotEqual(t, updatedPublicDashboard.AnnotationsEnabled, pdNotUpdatedRetrieved.AnnotationsEnabled)
 		assert.NotEqual(t, updatedPublicDashboard.Share, pdNotUpdatedRetrieved.Share)
 	})
+
+	t.Run("updates and clears access restrictions", func(t *testing.T) {
+		setup()
+		pd := insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true, PublicShareType)
+
+		expiresAt := time.Now().UTC().Add(time.Hour).Round(time.Second)
+		pd.ExpiresAt = &expiresAt
+		pd.AllowedNetworks = []string{"10.0.0.0/8"}
+		require.NoError(t, pd.SetPassphrase("correct horse"))
+		pd.UpdatedAt = time.Now()
+
+		_, err := publicdashboardStore.Update(context.Background(), SavePublicDashboardCommand{PublicDashboard: *pd})
+		require.NoError(t, err)
+
+		pdRetrieved, err := publicdashboardStore.Find(context.Background(), pd.Uid)
+		require.NoError(t, err)
+		require.NotNil(t, pdRetrieved.ExpiresAt)
+		assert.Equal(t, expiresAt, pdRetrieved.ExpiresAt.UTC())
+		assert.Equal(t, []string{"10.0.0.0/8"}, pdRetrieved.AllowedNetworks)
+		assert.True(t, pdRetrieved.HasPassphrase)
+		assert.True(t, pdRetrieved.VerifyPassphrase("correct horse"))
+
+		pd.ExpiresAt = nil
+		pd.AllowedNetworks = []string{}
+		require.NoError(t, pd.SetPassphrase(""))
+
+		_, err = publicdashboardStore.Update(context.Background(), SavePublicDashboardCommand{PublicDashboard: *pd})
+		require.NoError(t, err)
+
+		pdRetrieved, err = publicdashboardStore.Find(context.Background(), pd.Uid)
+		require.NoError(t, err)
+		assert.Nil(t, pdRetrieved.ExpiresAt)
+		assert.Empty(t, pdRetrieved.AllowedNetworks)
+		assert.False(t, pdRetrieved.HasPassphrase)
+	})
 }
 
 func TestIntegrationGetOrgIdByAccessToken(t *testing.T) {
@@ -741,6 +776,76 @@ func TestIntegrationDelete(t *testing.T) {
 	})
 }
 
+func TestIntegrationAccessLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var publicdashboardStore *PublicDashboardStoreImpl
+
+	setup := func() {
+		sqlStore, cfg := db.InitTestDBwithCfg(t)
+		publicdashboardStore = ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+	}
+
+	insertEntry := func(t *testing.T, pdUid string, result string, remoteAddr string, createdAt time.Time) {
+		t.Helper()
+		err := publicdashboardStore.CreateAccessLogEntry(context.Background(), &AccessLogEntry{
+			OrgId:              1,
+			PublicDashboardUid: pdUid,
+			Action:             AccessActionUnlock,
+			Result:             result,
+			RemoteAddr:         remoteAddr,
+			UserAgent:          "test",
+			CreatedAt:          createdAt,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("finds entries of the public dashboard newest first", func(t *testing.T) {
+		setup()
+		insertEntry(t, "pd1", AccessResultAllowed, "10.0.0.1", DefaultTime.Add(-2*time.Minute))
+		insertEntry(t, "pd1", AccessResultPassphraseInvalid, "10.0.0.1", DefaultTime.Add(-time.Minute))
+		insertEntry(t, "pd2", AccessResultAllowed, "10.0.0.1", DefaultTime)
+
+		entries, err := publicdashboardStore.FindAccessLog(context.Background(), AccessLogQuery{PublicDashboardUid: "pd1"})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, AccessResultPassphraseInvalid, entries[0].Result)
+		assert.Equal(t, AccessResultAllowed, entries[1].Result)
+	})
+
+	t.Run("filters entries by result, address and time", func(t *testing.T) {
+		setup()
+		insertEntry(t, "pd1", AccessResultPassphraseInvalid, "10.0.0.1", DefaultTime.Add(-time.Hour))
+		insertEntry(t, "pd1", AccessResultPassphraseInvalid, "10.0.0.1", DefaultTime)
+		insertEntry(t, "pd1", AccessResultPassphraseInvalid, "10.0.0.2", DefaultTime)
+		insertEntry(t, "pd1", AccessResultAllowed, "10.0.0.1", DefaultTime)
+
+		entries, err := publicdashboardStore.FindAccessLog(context.Background(), AccessLogQuery{
+			PublicDashboardUid: "pd1",
+			RemoteAddr:         "10.0.0.1",
+			Result:             AccessResultPassphraseInvalid,
+			Since:              DefaultTime.Add(-time.Minute),
+		})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("deletes entries older than the retention", func(t *testing.T) {
+		setup()
+		insertEntry(t, "pd1", AccessResultAllowed, "10.0.0.1", DefaultTime.Add(-48*time.Hour))
+		insertEntry(t, "pd1", AccessResultAllowed, "10.0.0.1", DefaultTime)
+
+		affectedRows, err := publicdashboardStore.DeleteAccessLogBefore(context.Background(), DefaultTime.Add(-24*time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, affectedRows)
+
+		entries, err := publicdashboardStore.FindAccessLog(context.Background(), AccessLogQuery{PublicDashboardUid: "pd1"})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+}
+
 func TestFindByFolder(t *testing.T) {
 	t.Run("returns nil when dashboard is not a folder", func(t *testing.T) {
 		sqlStore, _ := db.InitTestDBwithCfg(t)
@@ -23,6 +23,15 @@ var (
 	ErrDashboardIsPublic                   = errutil.BadRequest("publicdashboards.dashboardIsPublic", errutil.WithPublicMessage("Dashboard is already public"))
 	ErrPublicDashboardUidExists            = errutil.BadRequest("publicdashboards.uidExists", errutil.WithPublicMessage("Public Dashboard Uid already exists"))
 	ErrPublicDashboardAccessTokenExists    = errutil.BadRequest("publicdashboards.accessTokenExists", errutil.WithPublicMessage("Public Dashboard Access Token already exists"))
+	ErrInvalidAllowedNetwork               = errutil.BadRequest("publicdashboards.invalidAllowedNetwork", errutil.WithPublicMessage("Invalid allowed network"))
+	ErrInvalidPassphrase                   = errutil.BadRequest("publicdashboards.invalidPassphrase", errutil.WithPublicMessage("Invalid passphrase"))
+	ErrInvalidTemplateVariable             = errutil.BadRequest("publicdashboards.invalidTemplateVariable", errutil.WithPublicMessage("Invalid template variable value"))
+	ErrInvalidTimeSettings                 = errutil.BadRequest("publicdashboards.invalidTimeSettings", errutil.WithPublicMessage("Invalid time settings"))
 
-	ErrPublicDashboardNotEnabled = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+	ErrPublicDashboardPassphraseRequired = errutil.Unauthorized("publicdashboards.passphraseRequired", errutil.WithPublicMessage("Public dashboard requires a passphrase"))
+	ErrPublicDashboardTooManyAttempts    = errutil.TooManyRequests("publicdashboards.tooManyAttempts", errutil.WithPublicMessage("Too many failed attempts, try again later"))
+
+	ErrPublicDashboardNotEnabled    = errutil.Forbidden("publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
+	ErrPublicDashboardExpired       = errutil.Forbidden("publicdashboards.expired", errutil.WithPublicMessage("Public dashboard expired"))
+	ErrPublicDashboardNetworkDenied = errutil.Forbidden("publicdashboards.networkDenied", errutil.WithPublicMessage("Public dashboard is not accessible from your network"))
 )
@@ -1,11 +1,14 @@
 package models
 
 import (
+	"crypto/subtle"
 	"encoding/json"
+	"net"
 	"time"
 
 	"github.com/grafana/grafana/pkg/kinds/dashboard"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 // PublicDashboardErr represents a dashboard error.
@@ -48,12 +51,20 @@ type PublicDashboard struct {
 	CreatedAt    time.Time `json:"createdAt" xorm:"created_at"`
 	UpdatedAt    time.Time `json:"updatedAt" xorm:"updated_at"`
 	//config fields
-	TimeSettings         *TimeSettings `json:"-" xorm:"time_settings"`
-	TimeSelectionEnabled bool          `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
-	IsEnabled            bool          `json:"isEnabled" xorm:"is_enabled"`
-	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
-	Share                ShareType     `json:"share" xorm:"share"`
-	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
+	TimeSettings             *TimeSettings      `json:"timeSettings,omitempty" xorm:"time_settings"`
+	TimeSelectionEnabled     bool               `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
+	IsEnabled                bool               `json:"isEnabled" xorm:"is_enabled"`
+	AnnotationsEnabled       bool               `json:"annotationsEnabled" xorm:"annotations_enabled"`
+	TemplateVariablesEnabled bool               `json:"templateVariablesEnabled" xorm:"template_variables_enabled"`
+	TemplateVariables        *TemplateVariables `json:"templateVariables,omitempty" xorm:"template_variables"`
+	Share                    ShareType          `json:"share" xorm:"share"`
+	Recipients               []EmailDTO         `json:"recipients,omitempty" xorm:"-"`
+	// access restrictions
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty" xorm:"expires_at"`
+	AllowedNetworks []string   `json:"allowedNetworks,omitempty" xorm:"allowed_networks"`
+	PassphraseHash  string     `json:"-" xorm:"passphrase_hash"`
+	PassphraseSalt  string     `json:"-" xorm:"passphrase_salt"`
+	HasPassphrase   bool       `json:"hasPassphrase" xorm:"-"`
 }
 
 type PublicDashboardDTO struct {
@@ -63,6 +74,20 @@ type PublicDashboardDTO struct {
 	IsEnabled            *bool     `json:"isEnabled"`
 	AnnotationsEnabled   *bool     `json:"annotationsEnabled"`
 	Share                ShareType `json:"share"`
+	// TimeSettings overrides the default time range of the dashboard. Set
+	// empty from and to values to use the dashboard time range.
+	TimeSettings             *TimeSettings      `json:"timeSettings"`
+	TemplateVariablesEnabled *bool              `json:"templateVariablesEnabled"`
+	TemplateVariables        *TemplateVariables `json:"templateVariables"`
+	// ExpiresAt is when the public dashboard stops being accessible. Set it
+	// to the zero time to remove the expiry.
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// Passphrase viewers must enter to access the public dashboard. Set it
+	// to an empty string to remove the passphrase.
+	Passphrase *string `json:"passphrase"`
+	// AllowedNetworks are the IP addresses and CIDR ranges allowed to access
+	// the public dashboard. Set it to an empty list to allow all networks.
+	AllowedNetworks []string `json:"allowedNetworks"`
 }
 
 type EmailDTO struct {
@@ -96,6 +121,107 @@ func (pd PublicDashboard) TableName() string {
 	return "dashboard_public"
 }
 
+// IsExpired returns true if the public dashboard has an expiry before t.
+func (pd PublicDashboard) IsExpired(t time.Time) bool {
+	return pd.ExpiresAt != nil && !pd.ExpiresAt.IsZero() && !t.Before(*pd.ExpiresAt)
+}
+
+// AllowsIP returns true if the address is in one of the allowed networks, or
+// if the public dashboard has no network restrictions.
+func (pd PublicDashboard) AllowsIP(addr string) bool {
+	if len(pd.AllowedNetworks) == 0 {
+		return true
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range pd.AllowedNetworks {
+		if _, cidr, err := net.ParseCIDR(n); err == nil {
+			if cidr.Contains(ip) {
+				return true
+			}
+		} else if allowed := net.ParseIP(n); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresPassphrase returns true if viewers must enter a passphrase.
+func (pd PublicDashboard) RequiresPassphrase() bool {
+	return pd.PassphraseHash != ""
+}
+
+// SetPassphrase stores the hash of the passphrase, an empty passphrase removes it.
+func (pd *PublicDashboard) SetPassphrase(passphrase string) error {
+	if passphrase == "" {
+		pd.PassphraseHash = ""
+		pd.PassphraseSalt = ""
+		return nil
+	}
+	salt, err := util.GetRandomString(10)
+	if err != nil {
+		return err
+	}
+	hash, err := util.EncodePassword(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	pd.PassphraseHash = hash
+	pd.PassphraseSalt = salt
+	return nil
+}
+
+// VerifyPassphrase returns true if the passphrase matches the stored hash.
+func (pd PublicDashboard) VerifyPassphrase(passphrase string) bool {
+	if !pd.RequiresPassphrase() {
+		return true
+	}
+	hash, err := util.EncodePassword(passphrase, pd.PassphraseSalt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(pd.PassphraseHash)) == 1
+}
+
+const (
+	AccessActionView   = "view"
+	AccessActionQuery  = "query"
+	AccessActionUnlock = "unlock"
+
+	AccessResultAllowed            = "allowed"
+	AccessResultExpired            = "expired"
+	AccessResultNetworkDenied      = "network_denied"
+	AccessResultPassphraseRequired = "passphrase_required"
+	AccessResultPassphraseInvalid  = "passphrase_invalid"
+	AccessResultRateLimited        = "rate_limited"
+)
+
+// AccessLogEntry is an audit record of an access to a public dashboard.
+type AccessLogEntry struct {
+	Id                 int64     `json:"id" xorm:"pk autoincr 'id'"`
+	OrgId              int64     `json:"-" xorm:"org_id"`
+	PublicDashboardUid string    `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	Action             string    `json:"action" xorm:"action"`
+	Result             string    `json:"result" xorm:"result"`
+	RemoteAddr         string    `json:"remoteAddr" xorm:"remote_addr"`
+	UserAgent          string    `json:"userAgent" xorm:"user_agent"`
+	CreatedAt          time.Time `json:"createdAt" xorm:"created_at"`
+}
+
+func (e AccessLogEntry) TableName() string {
+	return "dashboard_public_access_log"
+}
+
+type AccessLogQuery struct {
+	PublicDashboardUid string
+	RemoteAddr         string
+	Result             string
+	Since              time.Time
+	Limit              int
+}
+
 type PublicDashboardListQuery struct {
 	OrgID  int64
 	Query  string
@@ -134,6 +260,28 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// HasTimeRange returns true if both ends of the time range are set
+func (ts *TimeSettings) HasTimeRange() bool {
+	return ts != nil && ts.From != "" && ts.To != ""
+}
+
+// TemplateVariables are the template variable values viewers of a public
+// dashboard can select between
+type TemplateVariables struct {
+	// Allowed maps a variable name to the values viewers can select. Custom,
+	// constant and interval variables default to the options declared in the
+	// dashboard, other variables can only be selected if declared here.
+	Allowed map[string][]string `json:"allowed,omitempty"`
+}
+
+func (tv *TemplateVariables) FromDB(data []byte) error {
+	return json.Unmarshal(data, tv)
+}
+
+func (tv *TemplateVariables) ToDB() ([]byte, error) {
+	return json.Marshal(tv)
+}
+
 // DTO for transforming user input in the api
 type SavePublicDashboardDTO struct {
 	Uid             string
@@ -154,6 +302,12 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables are the selected template variable values by variable name
+	Variables map[string]string
+}
+
+type UnlockPublicDashboardDTO struct {
+	Passphrase string `json:"passphrase"`
 }
 
 type AnnotationsQueryDTO struct {
@@ -2,10 +2,49 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPublicDashboardTableName(t *testing.T) {
 	assert.Equal(t, "dashboard_public", PublicDashboard{}.TableName())
 }
+
+func TestPublicDashboardIsExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	assert.False(t, PublicDashboard{}.IsExpired(now))
+	assert.False(t, PublicDashboard{ExpiresAt: &time.Time{}}.IsExpired(now))
+	assert.False(t, PublicDashboard{ExpiresAt: &future}.IsExpired(now))
+	assert.True(t, PublicDashboard{ExpiresAt: &past}.IsExpired(now))
+}
+
+func TestPublicDashboardAllowsIP(t *testing.T) {
+	pd := PublicDashboard{AllowedNetworks: []string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32"}}
+
+	assert.True(t, PublicDashboard{}.AllowsIP("203.0.113.1"))
+	assert.True(t, pd.AllowsIP("10.1.2.3"))
+	assert.True(t, pd.AllowsIP("192.168.1.10"))
+	assert.True(t, pd.AllowsIP("2001:db8::1"))
+	assert.False(t, pd.AllowsIP("192.168.1.11"))
+	assert.False(t, pd.AllowsIP("not an ip"))
+}
+
+func TestPublicDashboardPassphrase(t *testing.T) {
+	pd := PublicDashboard{}
+	assert.False(t, pd.RequiresPassphrase())
+	assert.True(t, pd.VerifyPassphrase(""))
+
+	require.NoError(t, pd.SetPassphrase("correct horse"))
+	assert.True(t, pd.RequiresPassphrase())
+	assert.NotEqual(t, "correct horse", pd.PassphraseHash)
+	assert.True(t, pd.VerifyPassphrase("correct horse"))
+	assert.False(t, pd.VerifyPassphrase("battery staple"))
+
+	require.NoError(t, pd.SetPassphrase(""))
+	assert.False(t, pd.RequiresPassphrase())
+}
@@ -13,6 +13,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	models "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 
 	user "github.com/grafana/grafana/pkg/services/user"
@@ -63,6 +65,30 @@ func (_m *FakePublicDashboardService) Delete(ctx context.Context, uid string, da
 	return r0
 }
 
+// DeleteAccessLogBefore provides a mock function with given fields: ctx, t
+func (_m *FakePublicDashboardService) DeleteAccessLogBefore(ctx context.Context, t time.Time) (int64, error) {
+	ret := _m.Called(ctx, t)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, t)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, t)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, t)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteByDashboard provides a mock function with given fields: ctx, dashboard
 func (_m *FakePublicDashboardService) DeleteByDashboard(ctx context.Context, dashboard *dashboards.Dashboard) error {
 	ret := _m.Called(ctx, dashboard)
@@ -151,6 +177,32 @@ func (_m *FakePublicDashboardService) Find(ctx context.Context, uid string) (*mo
 	return r0, r1
 }
 
+// FindAccessLog provides a mock function with given fields: ctx, query
+func (_m *FakePublicDashboardService) FindAccessLog(ctx context.Context, query models.AccessLogQuery) ([]*models.AccessLogEntry, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*models.AccessLogEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.AccessLogQuery) ([]*models.AccessLogEntry, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.AccessLogQuery) []*models.AccessLogEntry); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.AccessLogEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.AccessLogQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAllWithPagination provides a mock function with given fields: ctx, query
 func (_m *FakePublicDashboardService) FindAllWithPagination(ctx context.Context, query *models.PublicDashboardListQuery) (*models.PublicDashboardListResponseWithPagination, error) {
 	ret := _m.Called(ctx, query)
@@ -499,6 +551,20 @@ func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context)
 	return r0, r1
 }
 
+// RecordAccess provides a mock function with given fields: ctx, pd, action, result, remoteAddr, userAgent
+func (_m *FakePublicDashboardService) RecordAccess(ctx context.Context, pd *models.PublicDashboard, action string, result string, remoteAddr string, userAgent string) error {
+	ret := _m.Called(ctx, pd, action, result, remoteAddr, userAgent)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboard, string, string, string, string) error); ok {
+		r0 = rf(ctx, pd, action, result, remoteAddr, userAgent)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
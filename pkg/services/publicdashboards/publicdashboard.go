@@ -2,6 +2,7 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -38,6 +39,10 @@ type Service interface {
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	RecordAccess(ctx context.Context, pd *PublicDashboard, action string, result string, remoteAddr string, userAgent string) error
+	FindAccessLog(ctx context.Context, query AccessLogQuery) ([]*AccessLogEntry, error)
+	DeleteAccessLogBefore(ctx context.Context, t time.Time) (int64, error)
 }
 
 // ServiceWrapper these methods have different behavior between OSS and Enterprise. The latter would call the OSS service first
@@ -63,6 +68,10 @@ type Store interface {
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
 	GetMetrics(ctx context.Context) (*Metrics, error)
+
+	CreateAccessLogEntry(ctx context.Context, entry *AccessLogEntry) error
+	FindAccessLog(ctx context.Context, query AccessLogQuery) ([]*AccessLogEntry, error)
+	DeleteAccessLogBefore(ctx context.Context, t time.Time) (int64, error)
 }
 
 //go:generate mockery --name Middleware --structname FakePublicDashboardMiddleware --inpackage --filename public_dashboard_middleware_mock.go
@@ -162,11 +162,18 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(dashboard *dashboards.D
 		return dtos.MetricRequest{}, models.ErrPanelNotFound.Errorf("buildMetricRequest: public dashboard panel not found")
 	}
 
+	// only allow-listed template variable values are interpolated, so viewers cannot inject arbitrary queries
+	variables, err := getVariableValues(dashboard.Data, publicDashboard, reqDTO.Variables)
+	if err != nil {
+		return dtos.MetricRequest{}, err
+	}
+
 	ts := buildTimeSettings(dashboard, reqDTO, publicDashboard)
 
 	// determine safe resolution to query data at
 	safeInterval, safeResolution := pd.getSafeIntervalAndMaxDataPoints(reqDTO, ts)
 	for i := range queries {
+		interpolateVariables(queries[i], variables)
 		queries[i].Set("intervalMs", safeInterval)
 		queries[i].Set("maxDataPoints", safeResolution)
 		queries[i].Set("queryCachingTTL", reqDTO.QueryCachingTTL)
@@ -368,7 +375,7 @@ var NewDataTimeRange = legacydata.NewDataTimeRange
 
 // BuildTimeSettings build time settings object using selected values if enabled and are valid or dashboard default values
 func buildTimeSettings(d *dashboards.Dashboard, reqDTO models.PublicDashboardQueryDTO, pd *models.PublicDashboard) models.TimeSettings {
-	from, to, timezone := getTimeRangeValuesOrDefault(reqDTO, d, pd.TimeSelectionEnabled)
+	from, to, timezone := getTimeRangeValuesOrDefault(reqDTO, d, pd)
 
 	timeRange := NewDataTimeRange(from, to)
 
@@ -388,14 +395,20 @@ func buildTimeSettings(d *dashboards.Dashboard, reqDTO models.PublicDashboardQue
 	}
 }
 
-// returns from, to and timezone from the request if the timeSelection is enabled or the dashboard default values
-func getTimeRangeValuesOrDefault(reqDTO models.PublicDashboardQueryDTO, d *dashboards.Dashboard, timeSelectionEnabled bool) (string, string, *time.Location) {
+// returns from, to and timezone from the request if the timeSelection is enabled or the default values, which are
+// the public dashboard time range override if set or the dashboard time range
+func getTimeRangeValuesOrDefault(reqDTO models.PublicDashboardQueryDTO, d *dashboards.Dashboard, pd *models.PublicDashboard) (string, string, *time.Location) {
 	from := d.Data.GetPath("time", "from").MustString()
 	to := d.Data.GetPath("time", "to").MustString()
 	dashboardTimezone := d.Data.GetPath("timezone").MustString()
 
+	if pd.TimeSettings.HasTimeRange() {
+		from = pd.TimeSettings.From
+		to = pd.TimeSettings.To
+	}
+
 	// we use the values from the request if the time selection is enabled and the values are valid
-	if timeSelectionEnabled {
+	if pd.TimeSelectionEnabled {
 		if reqDTO.TimeRange.From != "" && reqDTO.TimeRange.To != "" {
 			from = reqDTO.TimeRange.From
 			to = reqDTO.TimeRange.To
@@ -1683,6 +1683,16 @@ func TestBuildTimeSettings(t *testing.T) {
 				To:   strconv.FormatInt(fakeNow.UnixMilli(), 10),
 			},
 		},
+		{
+			name:      "should use public dashboard time settings over the dashboard time range",
+			dashboard: &dashboards.Dashboard{Data: buildJsonDataWithTimeRange("now-1d/d", "now-1d/d", "Europe/Madrid")},
+			pubdash:   &PublicDashboard{TimeSelectionEnabled: false, TimeSettings: &TimeSettings{From: "now-1h", To: "now"}},
+			reqDTO:    PublicDashboardQueryDTO{},
+			want: TimeSettings{
+				From: strconv.FormatInt(fakeNow.Add(-time.Hour).UnixMilli(), 10),
+				To:   strconv.FormatInt(fakeNow.UnixMilli(), 10),
+			},
+		},
 		{
 			name:      "should use dashboard time if pubdash time empty",
 			dashboard: &dashboards.Dashboard{Data: defaultDashboardData},
@@ -98,8 +98,12 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 		PublicDashboardEnabled: pubdash.IsEnabled,
 	}
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
+	if pubdash.TimeSettings.HasTimeRange() {
+		dash.Data.Set("time", map[string]any{"from": pubdash.TimeSettings.From, "to": pubdash.TimeSettings.To})
+	}
 
 	sanitizeData(dash.Data)
+	sanitizeTemplating(dash.Data, pubdash)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
@@ -268,7 +272,10 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		return nil, ErrInvalidUid.Errorf("Update: the public dashboard does not belong to the dashboard")
 	}
 
-	publicDashboard := newUpdatePublicDashboard(dto, existingPubdash)
+	publicDashboard, err := newUpdatePublicDashboard(dto, existingPubdash)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("Update: failed to set public dashboard passphrase: %w", err)
+	}
 
 	// set values to update
 	cmd := SavePublicDashboardCommand{
@@ -355,6 +362,38 @@ func (pd *PublicDashboardServiceImpl) GetOrgIdByAccessToken(ctx context.Context,
 	return pd.store.GetOrgIdByAccessToken(ctx, accessToken)
 }
 
+// RecordAccess stores an audit record of an access to the public dashboard
+func (pd *PublicDashboardServiceImpl) RecordAccess(ctx context.Context, pubdash *PublicDashboard, action string, result string, remoteAddr string, userAgent string) error {
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	entry := &AccessLogEntry{
+		OrgId:              pubdash.OrgId,
+		PublicDashboardUid: pubdash.Uid,
+		Action:             action,
+		Result:             result,
+		RemoteAddr:         remoteAddr,
+		UserAgent:          userAgent,
+		CreatedAt:          time.Now(),
+	}
+	if err := pd.store.CreateAccessLogEntry(ctx, entry); err != nil {
+		return ErrInternalServerError.Errorf("RecordAccess: failed to record access to public dashboard %s: %w", pubdash.Uid, err)
+	}
+	return nil
+}
+
+func (pd *PublicDashboardServiceImpl) FindAccessLog(ctx context.Context, query AccessLogQuery) ([]*AccessLogEntry, error) {
+	entries, err := pd.store.FindAccessLog(ctx, query)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindAccessLog: failed to find access log of public dashboard %s: %w", query.PublicDashboardUid, err)
+	}
+	return entries, nil
+}
+
+func (pd *PublicDashboardServiceImpl) DeleteAccessLogBefore(ctx context.Context, t time.Time) (int64, error) {
+	return pd.store.DeleteAccessLogBefore(ctx, t)
+}
+
 func (pd *PublicDashboardServiceImpl) Delete(ctx context.Context, uid string, dashboardUid string) error {
 	// get existing public dashboard if exists
 	existingPubdash, err := pd.store.Find(ctx, uid)
@@ -493,6 +532,12 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 	isEnabled := returnValueOrDefault(dto.PublicDashboard.IsEnabled, false)
 	annotationsEnabled := returnValueOrDefault(dto.PublicDashboard.AnnotationsEnabled, false)
 	timeSelectionEnabled := returnValueOrDefault(dto.PublicDashboard.TimeSelectionEnabled, false)
+	templateVariablesEnabled := returnValueOrDefault(dto.PublicDashboard.TemplateVariablesEnabled, false)
+
+	timeSettings := dto.PublicDashboard.TimeSettings
+	if timeSettings == nil {
+		timeSettings = &TimeSettings{}
+	}
 
 	share := dto.PublicDashboard.Share
 	if dto.PublicDashboard.Share == "" {
@@ -501,44 +546,100 @@ func (pd *PublicDashboardServiceImpl) newCreatePublicDashboard(ctx context.Conte
 
 	now := time.Now()
 
-	return &PublicDashboard{
-		Uid:                  uid,
-		DashboardUid:         dto.DashboardUid,
-		OrgId:                dto.OrgID,
-		IsEnabled:            isEnabled,
-		AnnotationsEnabled:   annotationsEnabled,
-		TimeSelectionEnabled: timeSelectionEnabled,
-		TimeSettings:         &TimeSettings{},
-		Share:                share,
-		CreatedBy:            dto.UserId,
-		CreatedAt:            now,
-		UpdatedBy:            dto.UserId,
-		UpdatedAt:            now,
-		AccessToken:          accessToken,
-	}, nil
+	publicDashboard := &PublicDashboard{
+		Uid:                      uid,
+		DashboardUid:             dto.DashboardUid,
+		OrgId:                    dto.OrgID,
+		IsEnabled:                isEnabled,
+		AnnotationsEnabled:       annotationsEnabled,
+		TimeSelectionEnabled:     timeSelectionEnabled,
+		TimeSettings:             timeSettings,
+		TemplateVariablesEnabled: templateVariablesEnabled,
+		TemplateVariables:        dto.PublicDashboard.TemplateVariables,
+		Share:                    share,
+		CreatedBy:                dto.UserId,
+		CreatedAt:                now,
+		UpdatedBy:                dto.UserId,
+		UpdatedAt:                now,
+		AccessToken:              accessToken,
+		ExpiresAt:                expiresAtOrNil(dto.PublicDashboard.ExpiresAt),
+		AllowedNetworks:          dto.PublicDashboard.AllowedNetworks,
+	}
+
+	if dto.PublicDashboard.Passphrase != nil {
+		if err := publicDashboard.SetPassphrase(*dto.PublicDashboard.Passphrase); err != nil {
+			return nil, ErrInternalServerError.Errorf("Create: failed to set public dashboard passphrase: %w", err)
+		}
+	}
+
+	return publicDashboard, nil
 }
 
-func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard) *PublicDashboard {
+func newUpdatePublicDashboard(dto *SavePublicDashboardDTO, pd *PublicDashboard) (*PublicDashboard, error) {
 	pubdashDTO := dto.PublicDashboard
 	timeSelectionEnabled := returnValueOrDefault(pubdashDTO.TimeSelectionEnabled, pd.TimeSelectionEnabled)
 	isEnabled := returnValueOrDefault(pubdashDTO.IsEnabled, pd.IsEnabled)
 	annotationsEnabled := returnValueOrDefault(pubdashDTO.AnnotationsEnabled, pd.AnnotationsEnabled)
+	templateVariablesEnabled := returnValueOrDefault(pubdashDTO.TemplateVariablesEnabled, pd.TemplateVariablesEnabled)
+
+	timeSettings := pd.TimeSettings
+	if pubdashDTO.TimeSettings != nil {
+		timeSettings = pubdashDTO.TimeSettings
+	}
+
+	templateVariables := pd.TemplateVariables
+	if pubdashDTO.TemplateVariables != nil {
+		templateVariables = pubdashDTO.TemplateVariables
+	}
 
 	share := pubdashDTO.Share
 	if pubdashDTO.Share == "" {
 		share = pd.Share
 	}
 
-	return &PublicDashboard{
-		Uid:                  pd.Uid,
-		IsEnabled:            isEnabled,
-		AnnotationsEnabled:   annotationsEnabled,
-		TimeSelectionEnabled: timeSelectionEnabled,
-		TimeSettings:         pd.TimeSettings,
-		Share:                share,
-		UpdatedBy:            dto.UserId,
-		UpdatedAt:            time.Now(),
+	expiresAt := pd.ExpiresAt
+	if pubdashDTO.ExpiresAt != nil {
+		expiresAt = expiresAtOrNil(pubdashDTO.ExpiresAt)
+	}
+
+	allowedNetworks := pd.AllowedNetworks
+	if pubdashDTO.AllowedNetworks != nil {
+		allowedNetworks = pubdashDTO.AllowedNetworks
+	}
+
+	publicDashboard := &PublicDashboard{
+		Uid:                      pd.Uid,
+		IsEnabled:                isEnabled,
+		AnnotationsEnabled:       annotationsEnabled,
+		TimeSelectionEnabled:     timeSelectionEnabled,
+		TimeSettings:             timeSettings,
+		TemplateVariablesEnabled: templateVariablesEnabled,
+		TemplateVariables:        templateVariables,
+		Share:                    share,
+		ExpiresAt:                expiresAt,
+		AllowedNetworks:          allowedNetworks,
+		PassphraseHash:           pd.PassphraseHash,
+		PassphraseSalt:           pd.PassphraseSalt,
+		UpdatedBy:                dto.UserId,
+		UpdatedAt:                time.Now(),
+	}
+
+	// a nil passphrase keeps the current one, an empty one removes it
+	if pubdashDTO.Passphrase != nil {
+		if err := publicDashboard.SetPassphrase(*pubdashDTO.Passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	return publicDashboard, nil
+}
+
+// expiresAtOrNil returns nil for the zero time, which removes the expiry
+func expiresAtOrNil(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
 	}
+	return t
 }
 
 func returnValueOrDefault(value *bool, defaultValue bool) bool {
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+// allValue is the value of the "All" option of a template variable, which
+// would need the datasource to expand it and is never allowed
+const allValue = "$__all"
+
+// variableRegex matches $var, ${var}, ${var:format} and [[var]] references
+var variableRegex = regexp.MustCompile(`\$(\w+)|\$\{(\w+)(?::[^}]*)?\}|\[\[(\w+)(?::[^\]]*)?\]\]`)
+
+// getAllowedVariableValues returns the values viewers can select for each template variable of the dashboard.
+// Values declared on the public dashboard take precedence, custom, constant and interval variables default
+// to the options declared in the dashboard. Other variables, like query variables, are only allowed when declared.
+func getAllowedVariableValues(dashboard *simplejson.Json, pd *models.PublicDashboard) map[string][]string {
+	allowed := make(map[string][]string)
+
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		var values []string
+		if pd.TemplateVariables != nil && pd.TemplateVariables.Allowed[name] != nil {
+			values = pd.TemplateVariables.Allowed[name]
+		} else {
+			switch variable.Get("type").MustString() {
+			case "constant":
+				values = []string{variable.Get("query").MustString()}
+			case "custom", "interval":
+				values = getVariableOptionValues(variable)
+			}
+		}
+
+		values = removeValue(values, allValue)
+		if len(values) > 0 {
+			allowed[name] = values
+		}
+	}
+
+	return allowed
+}
+
+// getVariableOptionValues returns the option values of the variable, or parses them from its query
+func getVariableOptionValues(variable *simplejson.Json) []string {
+	var values []string
+	for _, optionObj := range variable.Get("options").MustArray() {
+		option := simplejson.NewFromAny(optionObj)
+		if value, ok := option.Get("value").Interface().(string); ok {
+			values = append(values, value)
+		}
+	}
+	if len(values) > 0 {
+		return values
+	}
+
+	// custom variable queries are comma separated values, optionally as "text : value"
+	for _, part := range strings.Split(variable.Get("query").MustString(), ",") {
+		part = strings.TrimSpace(part)
+		if _, value, ok := strings.Cut(part, " : "); ok {
+			part = strings.TrimSpace(value)
+		}
+		if part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// getVariableValues resolves the value of each allowed template variable: the value selected by the viewer
+// when template variables are enabled, otherwise the current value saved in the dashboard if it is allowed,
+// otherwise the first allowed value
+func getVariableValues(dashboard *simplejson.Json, pd *models.PublicDashboard, selected map[string]string) (map[string]string, error) {
+	allowed := getAllowedVariableValues(dashboard, pd)
+	values := make(map[string]string, len(allowed))
+
+	if pd.TemplateVariablesEnabled {
+		for name, value := range selected {
+			if !containsValue(allowed[name], value) {
+				return nil, models.ErrInvalidTemplateVariable.Errorf("getVariableValues: value of template variable %s is not allowed", name)
+			}
+			values[name] = value
+		}
+	}
+
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		if _, ok := values[name]; ok || len(allowed[name]) == 0 {
+			continue
+		}
+
+		values[name] = allowed[name][0]
+		if current := getVariableCurrentValue(variable); containsValue(allowed[name], current) {
+			values[name] = current
+		}
+	}
+
+	return values, nil
+}
+
+// getVariableCurrentValue returns the value saved in the dashboard, the first one for multi-value variables
+func getVariableCurrentValue(variable *simplejson.Json) string {
+	current := variable.GetPath("current", "value")
+	if value, err := current.String(); err == nil {
+		return value
+	}
+	if values := current.MustStringArray(); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// interpolateVariables replaces the template variable references in the string fields of the query.
+// References to variables without a value, like global variables, are left for the datasource.
+func interpolateVariables(query *simplejson.Json, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+
+	for key, value := range query.MustMap() {
+		// the datasource and refId are never interpolated
+		if key == "datasource" || key == "refId" {
+			continue
+		}
+		query.Set(key, interpolateValue(value, values))
+	}
+}
+
+func interpolateValue(value any, values map[string]string) any {
+	switch v := value.(type) {
+	case string:
+		return variableRegex.ReplaceAllStringFunc(v, func(match string) string {
+			groups := variableRegex.FindStringSubmatch(match)
+			for _, name := range groups[1:] {
+				if value, ok := values[name]; ok {
+					return value
+				}
+			}
+			return match
+		})
+	case map[string]any:
+		for key, item := range v {
+			v[key] = interpolateValue(item, values)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = interpolateValue(item, values)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// sanitizeTemplating replaces the template variables of the dashboard with custom variables that only offer
+// the allowed values, so variable queries are not exposed. Variables are hidden unless they are enabled.
+func sanitizeTemplating(dashboard *simplejson.Json, pd *models.PublicDashboard) {
+	allowed := getAllowedVariableValues(dashboard, pd)
+	current, err := getVariableValues(dashboard, pd, nil)
+	if err != nil {
+		current = map[string]string{}
+	}
+
+	variables := dashboard.GetPath("templating", "list").MustArray()
+	for i, variableObj := range variables {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+
+		options := make([]any, 0, len(allowed[name]))
+		for _, value := range allowed[name] {
+			options = append(options, map[string]any{
+				"text":     value,
+				"value":    value,
+				"selected": value == current[name],
+			})
+		}
+
+		sanitized := map[string]any{
+			"name":    name,
+			"label":   variable.Get("label").MustString(),
+			"type":    "custom",
+			"query":   strings.Join(allowed[name], ","),
+			"options": options,
+			"current": map[string]any{"text": current[name], "value": current[name]},
+			"multi":   false,
+			// 2 hides the variable, 0 shows it with its label
+			"hide": 2,
+		}
+		if pd.TemplateVariablesEnabled && len(allowed[name]) > 1 {
+			sanitized["hide"] = variable.Get("hide").MustInt()
+		}
+
+		variables[i] = sanitized
+	}
+
+	if len(variables) > 0 {
+		dashboard.SetPath([]string{"templating", "list"}, variables)
+	}
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeValue(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
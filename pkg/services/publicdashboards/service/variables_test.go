@@ -0,0 +1,167 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const dashboardWithTemplateVariables = `
+{
+  "templating": {
+    "list": [
+      {
+        "name": "env",
+        "label": "Environment",
+        "type": "custom",
+        "query": "Production : prod, Staging : staging",
+        "current": {"text": "Staging", "value": "staging"}
+      },
+      {
+        "name": "region",
+        "type": "custom",
+        "options": [
+          {"text": "All", "value": "$__all"},
+          {"text": "eu", "value": "eu"},
+          {"text": "us", "value": "us"}
+        ],
+        "current": {"text": ["us"], "value": ["us"]}
+      },
+      {
+        "name": "cluster",
+        "type": "constant",
+        "query": "main"
+      },
+      {
+        "name": "host",
+        "type": "query",
+        "query": "label_values(up, host)",
+        "current": {"text": "secret-host", "value": "secret-host"}
+      }
+    ]
+  }
+}`
+
+func TestGetAllowedVariableValues(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	t.Run("defaults to the options of custom and constant variables", func(t *testing.T) {
+		allowed := getAllowedVariableValues(dashboard, &PublicDashboard{})
+
+		assert.Equal(t, map[string][]string{
+			"env":     {"prod", "staging"},
+			"region":  {"eu", "us"},
+			"cluster": {"main"},
+		}, allowed)
+	})
+
+	t.Run("uses values declared on the public dashboard", func(t *testing.T) {
+		pd := &PublicDashboard{TemplateVariables: &TemplateVariables{Allowed: map[string][]string{
+			"env":  {"prod"},
+			"host": {"web-1", "web-2"},
+		}}}
+		allowed := getAllowedVariableValues(dashboard, pd)
+
+		assert.Equal(t, []string{"prod"}, allowed["env"])
+		assert.Equal(t, []string{"web-1", "web-2"}, allowed["host"])
+	})
+}
+
+func TestGetVariableValues(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	t.Run("uses the saved values when they are allowed", func(t *testing.T) {
+		values, err := getVariableValues(dashboard, &PublicDashboard{}, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"env": "staging", "region": "us", "cluster": "main"}, values)
+	})
+
+	t.Run("ignores selected values when template variables are disabled", func(t *testing.T) {
+		values, err := getVariableValues(dashboard, &PublicDashboard{}, map[string]string{"env": "prod", "host": "anything"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "staging", values["env"])
+		assert.NotContains(t, values, "host")
+	})
+
+	t.Run("uses selected values when they are allowed", func(t *testing.T) {
+		pd := &PublicDashboard{TemplateVariablesEnabled: true}
+		values, err := getVariableValues(dashboard, pd, map[string]string{"env": "prod", "region": "eu"})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"env": "prod", "region": "eu", "cluster": "main"}, values)
+	})
+
+	t.Run("rejects values that are not allowed", func(t *testing.T) {
+		pd := &PublicDashboard{TemplateVariablesEnabled: true}
+		for _, selected := range []map[string]string{
+			{"env": "prod\"} or vector(1) #"},
+			{"region": "$__all"},
+			{"host": "secret-host"},
+			{"unknown": "value"},
+		} {
+			_, err := getVariableValues(dashboard, pd, selected)
+			require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+		}
+	})
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	query := simplejson.NewFromAny(map[string]any{
+		"refId":      "$env",
+		"datasource": map[string]any{"uid": "${env}"},
+		"expr":       `up{env="$env", region="${region:regex}", cluster="[[cluster]]", host="$host"} [$__interval]`,
+		"tags":       []any{"$envx", map[string]any{"value": "$env"}},
+		"hide":       false,
+	})
+
+	interpolateVariables(query, map[string]string{"env": "prod", "region": "eu", "cluster": "main"})
+
+	assert.Equal(t, "$env", query.Get("refId").MustString())
+	assert.Equal(t, "${env}", query.GetPath("datasource", "uid").MustString())
+	assert.Equal(t, `up{env="prod", region="eu", cluster="main", host="$host"} [$__interval]`, query.Get("expr").MustString())
+	assert.Equal(t, "$envx", query.Get("tags").GetIndex(0).MustString())
+	assert.Equal(t, "prod", query.Get("tags").GetIndex(1).Get("value").MustString())
+	assert.False(t, query.Get("hide").MustBool())
+}
+
+func TestSanitizeTemplating(t *testing.T) {
+	t.Run("hides variables when template variables are disabled", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+		require.NoError(t, err)
+
+		sanitizeTemplating(dashboard, &PublicDashboard{})
+
+		for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+			variable := simplejson.NewFromAny(variableObj)
+			assert.Equal(t, 2, variable.Get("hide").MustInt())
+		}
+	})
+
+	t.Run("replaces variables with the allowed options", func(t *testing.T) {
+		dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+		require.NoError(t, err)
+
+		sanitizeTemplating(dashboard, &PublicDashboard{TemplateVariablesEnabled: true})
+
+		variables := dashboard.GetPath("templating", "list")
+		env := variables.GetIndex(0)
+		assert.Equal(t, "custom", env.Get("type").MustString())
+		assert.Equal(t, "Environment", env.Get("label").MustString())
+		assert.Equal(t, "prod,staging", env.Get("query").MustString())
+		assert.Equal(t, "staging", env.GetPath("current", "value").MustString())
+		assert.Equal(t, 0, env.Get("hide").MustInt())
+
+		host := variables.GetIndex(3)
+		assert.Equal(t, "", host.Get("query").MustString())
+		assert.Empty(t, host.Get("options").MustArray())
+		assert.Equal(t, 2, host.Get("hide").MustInt())
+	})
+}
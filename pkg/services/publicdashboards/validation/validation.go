@@ -1,6 +1,9 @@
 package validation
 
 import (
+	"net"
+	"regexp"
+
 	"github.com/google/uuid"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
@@ -13,9 +16,76 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	for _, network := range dto.PublicDashboard.AllowedNetworks {
+		if !IsValidAllowedNetwork(network) {
+			return ErrInvalidAllowedNetwork.Errorf("ValidateSavePublicDashboard: invalid allowed network %s", network)
+		}
+	}
+
+	if dto.PublicDashboard.Passphrase != nil && len(*dto.PublicDashboard.Passphrase) > 0 && len(*dto.PublicDashboard.Passphrase) < MinPassphraseLength {
+		return ErrInvalidPassphrase.Errorf("ValidateSavePublicDashboard: passphrase must be at least %d characters", MinPassphraseLength)
+	}
+
+	if err := validateTimeSettings(dto.PublicDashboard.TimeSettings); err != nil {
+		return err
+	}
+
+	if tv := dto.PublicDashboard.TemplateVariables; tv != nil {
+		for name, values := range tv.Allowed {
+			if !IsValidVariableName(name) {
+				return ErrInvalidTemplateVariable.Errorf("ValidateSavePublicDashboard: invalid template variable name %s", name)
+			}
+			if len(values) > MaxAllowedVariableValues {
+				return ErrInvalidTemplateVariable.Errorf("ValidateSavePublicDashboard: template variable %s has more than %d allowed values", name, MaxAllowedVariableValues)
+			}
+		}
+	}
+
 	return nil
 }
 
+// MaxAllowedVariableValues is the maximum number of values that can be allowed for a template variable
+const MaxAllowedVariableValues = 100
+
+var variableNameRegex = regexp.MustCompile(`^\w+$`)
+
+// IsValidVariableName asserts that the name can be referenced as a template variable
+func IsValidVariableName(name string) bool {
+	return variableNameRegex.MatchString(name)
+}
+
+// validateTimeSettings asserts that the time range override is either empty or has valid from and to values
+func validateTimeSettings(ts *TimeSettings) error {
+	if ts == nil || (ts.From == "" && ts.To == "") {
+		return nil
+	}
+
+	if !ts.HasTimeRange() {
+		return ErrInvalidTimeSettings.Errorf("ValidateSavePublicDashboard: time settings require both from and to")
+	}
+
+	timeRange := legacydata.NewDataTimeRange(ts.From, ts.To)
+	if _, err := timeRange.ParseFrom(); err != nil {
+		return ErrInvalidTimeSettings.Errorf("ValidateSavePublicDashboard: time settings from is invalid")
+	}
+	if _, err := timeRange.ParseTo(); err != nil {
+		return ErrInvalidTimeSettings.Errorf("ValidateSavePublicDashboard: time settings to is invalid")
+	}
+
+	return nil
+}
+
+// MinPassphraseLength is the minimum length of a public dashboard passphrase
+const MinPassphraseLength = 8
+
+// IsValidAllowedNetwork asserts that the network is an IP address or a CIDR range
+func IsValidAllowedNetwork(network string) bool {
+	if _, _, err := net.ParseCIDR(network); err == nil {
+		return true
+	}
+	return net.ParseIP(network) != nil
+}
+
 func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *PublicDashboard) error {
 	if req.IntervalMs < 0 {
 		return ErrInvalidInterval.Errorf("ValidateQueryPublicDashboardRequest: intervalMS should be greater than 0")
@@ -22,6 +22,52 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns no error when allowed networks are IP addresses and CIDR ranges", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{AllowedNetworks: []string{"10.0.0.0/8", "192.168.1.10", "2001:db8::/32"}}}
+
+		err := ValidatePublicDashboard(dto)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns error when allowed network is invalid", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{AllowedNetworks: []string{"10.0.0.0/33"}}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidAllowedNetwork)
+	})
+
+	t.Run("Returns error when passphrase is too short", func(t *testing.T) {
+		passphrase := "short"
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{Passphrase: &passphrase}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidPassphrase)
+	})
+
+	t.Run("Returns no error when time settings are a valid time range", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{TimeSettings: &TimeSettings{From: "now-7d", To: "now"}}}
+
+		err := ValidatePublicDashboard(dto)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns error when time settings are incomplete or invalid", func(t *testing.T) {
+		for _, ts := range []*TimeSettings{{From: "now-7d"}, {From: "yesterday", To: "now"}} {
+			dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{TimeSettings: ts}}
+
+			err := ValidatePublicDashboard(dto)
+			require.ErrorIs(t, err, ErrInvalidTimeSettings)
+		}
+	})
+
+	t.Run("Returns error when template variable name is invalid", func(t *testing.T) {
+		tv := &TemplateVariables{Allowed: map[string][]string{"host name": {"a"}}}
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", UserId: 1, PublicDashboard: &PublicDashboardDTO{TemplateVariables: tv}}
+
+		err := ValidatePublicDashboard(dto)
+		require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -126,7 +128,7 @@ func (s QueryHistoryService) deleteQuery(ctx context.Context, user *user.SignedI
 		}
 
 		queryID = id
-		return nil
+		return s.insertEntityEvent(session, user.OrgID, UID, store.EntityEventTypeDelete)
 	})
 
 	return queryID, err
@@ -151,6 +153,9 @@ func (s QueryHistoryService) patchQueryComment(ctx context.Context, user *user.S
 		if err != nil {
 			return err
 		}
+		if err := s.insertEntityEvent(session, user.OrgID, UID, store.EntityEventTypeUpdate); err != nil {
+			return err
+		}
 
 		starred, err := session.Table("query_history_star").Where("user_id = ? AND query_uid = ?", user.UserID, UID).Exist()
 		if err != nil {
@@ -207,7 +212,7 @@ func (s QueryHistoryService) starQuery(ctx context.Context, user *user.SignedInU
 		}
 
 		isStarred = true
-		return nil
+		return s.insertEntityEvent(session, user.OrgID, UID, store.EntityEventTypeCreate)
 	})
 
 	if err != nil {
@@ -250,7 +255,7 @@ func (s QueryHistoryService) unstarQuery(ctx context.Context, user *user.SignedI
 		}
 
 		isStarred = false
-		return nil
+		return s.insertEntityEvent(session, user.OrgID, UID, store.EntityEventTypeDelete)
 	})
 
 	if err != nil {
@@ -270,6 +275,15 @@ func (s QueryHistoryService) unstarQuery(ctx context.Context, user *user.SignedI
 	return dto, nil
 }
 
+// insertEntityEvent records a change to a starred query so the search index picks it up.
+func (s QueryHistoryService) insertEntityEvent(session *db.Session, orgID int64, uid string, eventType store.EntityEventType) error {
+	if s.features == nil || !s.features.IsEnabledGlobally(featuremgmt.FlagPanelTitleSearch) {
+		return nil
+	}
+	_, err := session.Insert(store.NewDatabaseEntityEvent(uid, orgID, store.EntityTypeQuery, eventType))
+	return err
+}
+
 func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, olderThan int64) (int, error) {
 	var rowsCount int64
 
@@ -7,15 +7,17 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister) *QueryHistoryService {
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, features featuremgmt.FeatureToggles) *QueryHistoryService {
 	s := &QueryHistoryService{
 		store:         sqlStore,
 		Cfg:           cfg,
 		RouteRegister: routeRegister,
+		features:      features,
 		log:           log.New("query-history"),
 		now:           time.Now,
 	}
@@ -43,6 +45,7 @@ type QueryHistoryService struct {
 	store         db.DB
 	Cfg           *setting.Cfg
 	RouteRegister routing.RouteRegister
+	features      featuremgmt.FeatureToggles
 	log           log.Logger
 	now           func() time.Time
 }
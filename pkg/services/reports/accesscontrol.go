@@ -0,0 +1,21 @@
+package reports
+
+import (
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	ScopeRoot = "reports"
+
+	ActionRead   = "reports:read"
+	ActionCreate = "reports:create"
+	ActionWrite  = "reports:write"
+	ActionDelete = "reports:delete"
+	// ActionSend allows to send a report right away, regardless of its schedule.
+	ActionSend = "reports:send"
+)
+
+var (
+	ScopeProvider = ac.NewScopeProvider(ScopeRoot)
+	ScopeAll      = ScopeProvider.GetResourceAllScope()
+)
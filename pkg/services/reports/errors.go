@@ -0,0 +1,20 @@
+package reports
+
+import (
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrReportNotFound      = errutil.NotFound("reports.not-found", errutil.WithPublicMessage("Report not found"))
+	ErrReportAlreadyExists = errutil.Conflict("reports.already-exists", errutil.WithPublicMessage("A report with the same uid already exists"))
+	ErrInvalidReport       = errutil.BadRequest("reports.invalid", errutil.WithPublicMessage("Report name and dashboard are required"))
+	ErrInvalidUID          = errutil.BadRequest("reports.invalid-uid", errutil.WithPublicMessage("Invalid report uid"))
+	ErrInvalidSchedule     = errutil.BadRequest("reports.invalid-schedule", errutil.WithPublicMessage("Invalid report schedule, expected a cron expression"))
+	ErrInvalidRecipients   = errutil.BadRequest("reports.invalid-recipients", errutil.WithPublicMessage("Invalid report recipients"))
+	ErrInvalidFormat       = errutil.BadRequest("reports.invalid-format", errutil.WithPublicMessage("Invalid report format, expected pdf or png"))
+	ErrInvalidTimeRange    = errutil.BadRequest("reports.invalid-time-range", errutil.WithPublicMessage("Invalid report time range"))
+	ErrInvalidVariable     = errutil.BadRequest("reports.invalid-variable", errutil.WithPublicMessage("Invalid report template variable"))
+	ErrProvisionedReport   = errutil.BadRequest("reports.provisioned", errutil.WithPublicMessage("Provisioned reports can not be changed"))
+	ErrDashboardAccess     = errutil.Forbidden("reports.dashboard-access", errutil.WithPublicMessage("Report owner can not view the dashboard"))
+	ErrRendererUnavailable = errutil.NotImplemented("reports.renderer-unavailable", errutil.WithPublicMessage("Image renderer is not available"))
+)
@@ -0,0 +1,177 @@
+package reports
+
+import (
+	"net/mail"
+	"regexp"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+type Format string
+
+const (
+	FormatPDF Format = "pdf"
+	FormatPNG Format = "png"
+)
+
+// MaxRecipients is the maximum number of recipients of a report
+const MaxRecipients = 100
+
+var variableNameRegex = regexp.MustCompile(`^\w+$`)
+
+// Report is a dashboard rendered and emailed on a cron schedule
+type Report struct {
+	ID           int64             `xorm:"pk autoincr 'id'" json:"id"`
+	UID          string            `xorm:"uid" json:"uid"`
+	OrgID        int64             `xorm:"org_id" json:"orgId"`
+	Name         string            `json:"name"`
+	DashboardUID string            `xorm:"dashboard_uid" json:"dashboardUid"`
+	Schedule     string            `json:"schedule"`
+	Recipients   []string          `json:"recipients"`
+	Format       Format            `json:"format"`
+	TimeFrom     string            `json:"timeFrom"`
+	TimeTo       string            `json:"timeTo"`
+	Variables    map[string]string `json:"variables"`
+	Enabled      bool              `json:"enabled"`
+	Provisioned  bool              `json:"provisioned"`
+	// UserID is the user that last saved the report, the dashboard is
+	// rendered as this user. Provisioned reports are rendered as an admin.
+	UserID    int64     `xorm:"user_id" json:"userId"`
+	NextRun   time.Time `json:"nextRun"`
+	LastRun   time.Time `json:"lastRun"`
+	LastError string    `json:"lastError"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+func (r Report) TableName() string {
+	return "report"
+}
+
+// ReportSettings are the user defined settings of a report
+type ReportSettings struct {
+	Name         string            `json:"name"`
+	DashboardUID string            `json:"dashboardUid"`
+	Schedule     string            `json:"schedule"`
+	Recipients   []string          `json:"recipients"`
+	Format       Format            `json:"format"`
+	TimeFrom     string            `json:"timeFrom"`
+	TimeTo       string            `json:"timeTo"`
+	Variables    map[string]string `json:"variables"`
+	Enabled      bool              `json:"enabled"`
+}
+
+// Validate returns an error if the settings are invalid, an empty format
+// is valid and defaults to pdf.
+func (s *ReportSettings) Validate() error {
+	if s.Name == "" || s.DashboardUID == "" {
+		return ErrInvalidReport.Errorf("report name and dashboard uid are required")
+	}
+
+	if _, err := ParseSchedule(s.Schedule); err != nil {
+		return err
+	}
+
+	if len(s.Recipients) == 0 || len(s.Recipients) > MaxRecipients {
+		return ErrInvalidRecipients.Errorf("a report needs between 1 and %d recipients, got %d", MaxRecipients, len(s.Recipients))
+	}
+	for _, recipient := range s.Recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return ErrInvalidRecipients.Errorf("invalid recipient %q: %w", recipient, err)
+		}
+	}
+
+	switch s.Format {
+	case "", FormatPDF, FormatPNG:
+	default:
+		return ErrInvalidFormat.Errorf("invalid format %q", s.Format)
+	}
+
+	if s.TimeFrom != "" || s.TimeTo != "" {
+		timeRange := legacydata.NewDataTimeRange(s.TimeFrom, s.TimeTo)
+		if _, err := timeRange.ParseFrom(); err != nil || s.TimeFrom == "" {
+			return ErrInvalidTimeRange.Errorf("invalid time range start %q", s.TimeFrom)
+		}
+		if _, err := timeRange.ParseTo(); err != nil || s.TimeTo == "" {
+			return ErrInvalidTimeRange.Errorf("invalid time range end %q", s.TimeTo)
+		}
+	}
+
+	for name := range s.Variables {
+		if !variableNameRegex.MatchString(name) {
+			return ErrInvalidVariable.Errorf("invalid template variable name %q", name)
+		}
+	}
+
+	return nil
+}
+
+// ParseSchedule parses a standard five field cron expression, descriptors
+// like @daily are supported as well.
+func ParseSchedule(schedule string) (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, ErrInvalidSchedule.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+	return sched, nil
+}
+
+type CreateReportCommand struct {
+	ReportSettings
+	UID         string `json:"uid"`
+	OrgID       int64  `json:"-"`
+	UserID      int64  `json:"-"`
+	Provisioned bool   `json:"-"`
+}
+
+type UpdateReportCommand struct {
+	ReportSettings
+	UID         string `json:"-"`
+	OrgID       int64  `json:"-"`
+	UserID      int64  `json:"-"`
+	Provisioned bool   `json:"-"`
+}
+
+type DeleteReportCommand struct {
+	UID         string
+	OrgID       int64
+	Provisioned bool
+}
+
+type SendReportCommand struct {
+	UID   string
+	OrgID int64
+}
+
+type GetReportQuery struct {
+	UID   string
+	OrgID int64
+}
+
+type ListReportsQuery struct {
+	OrgID        int64
+	DashboardUID string
+}
+
+type GetDueReportsQuery struct {
+	Now   time.Time
+	Limit int
+}
+
+// ClaimReportRunCommand moves the next run of a report from DueRun to
+// NextRun, Claimed is false when another instance moved it first.
+type ClaimReportRunCommand struct {
+	ID      int64
+	DueRun  time.Time
+	NextRun time.Time
+	Claimed bool
+}
+
+type UpdateReportResultCommand struct {
+	ID        int64
+	LastRun   time.Time
+	LastError string
+}
@@ -0,0 +1,312 @@
+package reportimpl
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints() {
+	authorize := ac.Middleware(s.accessControl)
+	uidScope := reports.ScopeProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+
+	s.routeRegister.Group("/api/reports", func(reportRoute routing.RouteRegister) {
+		reportRoute.Get("/", authorize(ac.EvalPermission(reports.ActionRead)), routing.Wrap(s.listHandler))
+		reportRoute.Post("/", authorize(ac.EvalPermission(reports.ActionCreate)), routing.Wrap(s.createHandler))
+		reportRoute.Get("/:uid", authorize(ac.EvalPermission(reports.ActionRead, uidScope)), routing.Wrap(s.getHandler))
+		reportRoute.Put("/:uid", authorize(ac.EvalPermission(reports.ActionWrite, uidScope)), routing.Wrap(s.updateHandler))
+		reportRoute.Delete("/:uid", authorize(ac.EvalPermission(reports.ActionDelete, uidScope)), routing.Wrap(s.deleteHandler))
+		reportRoute.Post("/:uid/send", authorize(ac.EvalPermission(reports.ActionSend, uidScope)), routing.Wrap(s.sendHandler))
+	})
+}
+
+// swagger:route GET /reports reports listReports
+//
+// Get all reports of the organization.
+//
+// Responses:
+// 200: listReportsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) listHandler(c *contextmodel.ReqContext) response.Response {
+	result, err := s.List(c.Req.Context(), &reports.ListReportsQuery{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardUID: c.Query("dashboardUid"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get reports", err)
+	}
+
+	// reports are only listed if the user can read them and view their dashboard
+	canView := map[string]bool{}
+	filtered := make([]*reports.Report, 0, len(result))
+	for _, r := range result {
+		canRead, err := s.accessControl.Evaluate(c.Req.Context(), c.SignedInUser, ac.EvalPermission(reports.ActionRead, reports.ScopeProvider.GetResourceScopeUID(r.UID)))
+		if err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+		}
+		if !canRead {
+			continue
+		}
+		ok, found := canView[r.DashboardUID]
+		if !found {
+			ok, err = s.canViewDashboard(c, r.DashboardUID)
+			if err != nil {
+				return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard", err)
+			}
+			canView[r.DashboardUID] = ok
+		}
+		if ok {
+			filtered = append(filtered, r)
+		}
+	}
+	return response.JSON(http.StatusOK, filtered)
+}
+
+// swagger:route GET /reports/{uid} reports getReport
+//
+// Get a report.
+//
+// Responses:
+// 200: getReportResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) getHandler(c *contextmodel.ReqContext) response.Response {
+	result, rsp := s.getReport(c, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /reports reports createReport
+//
+// Create a report.
+//
+// The report is rendered as the signed in user, who needs to be able to view the dashboard.
+//
+// Responses:
+// 200: getReportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.CreateReportCommand{ReportSettings: reports.ReportSettings{Enabled: true}}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = c.SignedInUser.UserID
+
+	if rsp := s.checkDashboardAccess(c, cmd.DashboardUID); rsp != nil {
+		return rsp
+	}
+
+	result, err := s.Create(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create report", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route PUT /reports/{uid} reports updateReport
+//
+// Update a report.
+//
+// The report is rendered as the user that last updated it, who needs to be able to view the dashboard.
+//
+// Responses:
+// 200: getReportResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) updateHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.UpdateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.UID = web.Params(c.Req)[":uid"]
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = c.SignedInUser.UserID
+
+	if _, rsp := s.getReport(c, cmd.UID); rsp != nil {
+		return rsp
+	}
+	if rsp := s.checkDashboardAccess(c, cmd.DashboardUID); rsp != nil {
+		return rsp
+	}
+
+	result, err := s.Update(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update report", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route DELETE /reports/{uid} reports deleteReport
+//
+// Delete a report.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) deleteHandler(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if _, rsp := s.getReport(c, uid); rsp != nil {
+		return rsp
+	}
+
+	err := s.Delete(c.Req.Context(), &reports.DeleteReportCommand{
+		UID:   uid,
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete report", err)
+	}
+	return response.Success("Report deleted")
+}
+
+// swagger:route POST /reports/{uid}/send reports sendReport
+//
+// Send a report now.
+//
+// Renders the report and emails it to its recipients, regardless of its schedule.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (s *Service) sendHandler(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if _, rsp := s.getReport(c, uid); rsp != nil {
+		return rsp
+	}
+
+	err := s.SendNow(c.Req.Context(), &reports.SendReportCommand{
+		UID:   uid,
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to send report", err)
+	}
+	return response.Success("Report sent")
+}
+
+// getReport returns the report, or an error response if it does not exist or
+// the signed in user can not view its dashboard.
+func (s *Service) getReport(c *contextmodel.ReqContext, uid string) (*reports.Report, response.Response) {
+	report, err := s.Get(c.Req.Context(), &reports.GetReportQuery{
+		UID:   uid,
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return nil, response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+
+	canView, err := s.canViewDashboard(c, report.DashboardUID)
+	if err != nil {
+		return nil, response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard", err)
+	}
+	if !canView {
+		return nil, response.Err(reports.ErrReportNotFound.Errorf("user can not view dashboard %s of report %s", report.DashboardUID, uid))
+	}
+	return report, nil
+}
+
+// checkDashboardAccess returns an error response if the signed in user can
+// not view the dashboard, since reports are rendered as that user.
+func (s *Service) checkDashboardAccess(c *contextmodel.ReqContext, dashboardUID string) response.Response {
+	if dashboardUID == "" {
+		// reported by the validation of the report
+		return nil
+	}
+
+	canView, err := s.canViewDashboard(c, dashboardUID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard", err)
+	}
+	if !canView {
+		return response.Err(reports.ErrDashboardAccess.Errorf("user can not view dashboard %s", dashboardUID))
+	}
+	return nil
+}
+
+// canViewDashboard returns false if the signed in user can not view the
+// dashboard, or the dashboard no longer exists.
+func (s *Service) canViewDashboard(c *contextmodel.ReqContext, dashboardUID string) (bool, error) {
+	g, err := guardian.NewByUID(c.Req.Context(), dashboardUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		if errors.Is(err, guardian.ErrGuardianDashboardNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	canView, err := g.CanView()
+	if err != nil {
+		return false, nil
+	}
+	return canView, nil
+}
+
+// swagger:parameters listReports
+type ListReportsParams struct {
+	// in:query
+	// required:false
+	DashboardUID string `json:"dashboardUid"`
+}
+
+// swagger:parameters getReport deleteReport sendReport
+type ReportUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters createReport
+type CreateReportParams struct {
+	// in:body
+	// required:true
+	Body reports.CreateReportCommand
+}
+
+// swagger:parameters updateReport
+type UpdateReportParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body reports.UpdateReportCommand
+}
+
+// swagger:response listReportsResponse
+type ListReportsResponse struct {
+	// in: body
+	Body []*reports.Report `json:"body"`
+}
+
+// swagger:response getReportResponse
+type GetReportResponse struct {
+	// in: body
+	Body *reports.Report `json:"body"`
+}
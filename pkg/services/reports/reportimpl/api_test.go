@@ -0,0 +1,113 @@
+package reportimpl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestIntegrationReportAPI(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	scenario := setupTestService(t, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	s := scenario.service
+
+	ops := newCreateCommand("ops-report")
+	_, err := s.Create(ctx, ops)
+	require.NoError(t, err)
+	secret := newCreateCommand("secret-report")
+	secret.DashboardUID = "secret"
+	_, err = s.Create(ctx, secret)
+	require.NoError(t, err)
+
+	// the user can only view the ops dashboard
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewUIDs: []string{"ops"}})
+
+	s.routeRegister = routing.NewRouteRegister()
+	s.accessControl = acimpl.ProvideAccessControl(s.cfg)
+	s.registerAPIEndpoints()
+	server := webtest.NewServer(t, s.routeRegister)
+
+	send := func(t *testing.T, method, target string, permissions []accesscontrol.Permission) *http.Response {
+		t.Helper()
+		req := server.NewRequest(method, target, nil)
+		req = webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+			UserID:      10,
+			OrgID:       1,
+			Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(permissions)},
+		})
+		resp, err := server.Send(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, resp.Body.Close()) })
+		return resp
+	}
+
+	readAll := []accesscontrol.Permission{{Action: reports.ActionRead, Scope: reports.ScopeAll}}
+
+	t.Run("list requires the read permission", func(t *testing.T) {
+		resp := send(t, http.MethodGet, "/api/reports", nil)
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("list only returns reports of dashboards the user can view", func(t *testing.T) {
+		resp := send(t, http.MethodGet, "/api/reports", readAll)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result []*reports.Report
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result, 1)
+		require.Equal(t, "ops-report", result[0].UID)
+	})
+
+	t.Run("list only returns reports the user can read", func(t *testing.T) {
+		resp := send(t, http.MethodGet, "/api/reports", []accesscontrol.Permission{
+			{Action: reports.ActionRead, Scope: reports.ScopeProvider.GetResourceScopeUID("secret-report")},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result []*reports.Report
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Empty(t, result)
+	})
+
+	t.Run("get is restricted to the report scope", func(t *testing.T) {
+		resp := send(t, http.MethodGet, "/api/reports/ops-report", []accesscontrol.Permission{
+			{Action: reports.ActionRead, Scope: reports.ScopeProvider.GetResourceScopeUID("secret-report")},
+		})
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("reports of dashboards the user can not view are not found", func(t *testing.T) {
+		resp := send(t, http.MethodGet, "/api/reports/secret-report", readAll)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp = send(t, http.MethodPost, "/api/reports/secret-report/send", []accesscontrol.Permission{{Action: reports.ActionSend, Scope: reports.ScopeAll}})
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		resp = send(t, http.MethodDelete, "/api/reports/secret-report", []accesscontrol.Permission{{Action: reports.ActionDelete, Scope: reports.ScopeAll}})
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		_, err := s.Get(ctx, &reports.GetReportQuery{UID: "secret-report", OrgID: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("get returns reports of dashboards the user can view", func(t *testing.T) {
+		resp := send(t, http.MethodGet, "/api/reports/ops-report", readAll)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,47 @@
+package reportimpl
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+var (
+	reportsReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:reports:reader",
+		DisplayName: "Reports reader",
+		Description: "List, view and send reports",
+		Group:       "Reports",
+		Permissions: []accesscontrol.Permission{
+			{Action: reports.ActionRead, Scope: reports.ScopeAll},
+			{Action: reports.ActionSend, Scope: reports.ScopeAll},
+		},
+	}
+
+	reportsWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:reports:writer",
+		DisplayName: "Reports writer",
+		Description: "Create, update, delete, send and view reports",
+		Group:       "Reports",
+		Permissions: []accesscontrol.Permission{
+			{Action: reports.ActionRead, Scope: reports.ScopeAll},
+			{Action: reports.ActionCreate},
+			{Action: reports.ActionWrite, Scope: reports.ScopeAll},
+			{Action: reports.ActionDelete, Scope: reports.ScopeAll},
+			{Action: reports.ActionSend, Scope: reports.ScopeAll},
+		},
+	}
+)
+
+func declareFixedRoles(ac accesscontrol.Service) error {
+	reportsReader := accesscontrol.RoleRegistration{
+		Role:   reportsReaderRole,
+		Grants: []string{string(org.RoleEditor)},
+	}
+	reportsWriter := accesscontrol.RoleRegistration{
+		Role:   reportsWriterRole,
+		Grants: []string{string(org.RoleEditor)},
+	}
+
+	return ac.DeclareFixedRoles(reportsReader, reportsWriter)
+}
@@ -0,0 +1,394 @@
+package reportimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	checkInterval = time.Minute
+	// maxReportsPerRun limits the number of reports sent by one instance per
+	// check, the remaining ones are picked up by the next check.
+	maxReportsPerRun = 20
+
+	emailTemplate = "report"
+	renderWidth   = 1600
+	// renderHeight of -1 renders the full height of the dashboard
+	renderHeight = -1
+)
+
+type Service struct {
+	cfg              *setting.Cfg
+	store            store
+	renderer         reports.Renderer
+	emailSender      notifications.EmailSender
+	dashboardService dashboards.DashboardService
+	userService      user.Service
+	accessControl    accesscontrol.AccessControl
+	routeRegister    routing.RouteRegister
+	log              log.Logger
+	now              func() time.Time
+}
+
+var _ reports.Service = &Service{}
+
+func ProvideService(cfg *setting.Cfg, db db.DB, renderService rendering.Service, emailSender notifications.EmailSender,
+	dashboardService dashboards.DashboardService, userService user.Service, routeRegister routing.RouteRegister,
+	accessControl accesscontrol.AccessControl, accesscontrolService accesscontrol.Service) (*Service, error) {
+	s := &Service{
+		cfg:              cfg,
+		store:            &sqlStore{db: db},
+		renderer:         renderService,
+		emailSender:      emailSender,
+		dashboardService: dashboardService,
+		userService:      userService,
+		accessControl:    accessControl,
+		routeRegister:    routeRegister,
+		log:              log.New("reports"),
+		now:              time.Now,
+	}
+
+	if cfg.ReportsEnabled {
+		if err := declareFixedRoles(accesscontrolService); err != nil {
+			return nil, err
+		}
+		s.registerAPIEndpoints()
+	}
+
+	return s, nil
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.ReportsEnabled
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.sendDue(ctx)
+		}
+	}
+}
+
+func (s *Service) Create(ctx context.Context, cmd *reports.CreateReportCommand) (*reports.Report, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	} else if !util.IsValidShortUID(cmd.UID) {
+		return nil, reports.ErrInvalidUID.Errorf("invalid report uid %q", cmd.UID)
+	}
+
+	nextRun, err := s.nextRun(cmd.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	report := &reports.Report{
+		UID:          cmd.UID,
+		OrgID:        cmd.OrgID,
+		Name:         cmd.Name,
+		DashboardUID: cmd.DashboardUID,
+		Schedule:     cmd.Schedule,
+		Recipients:   cmd.Recipients,
+		Format:       formatOrDefault(cmd.Format),
+		TimeFrom:     cmd.TimeFrom,
+		TimeTo:       cmd.TimeTo,
+		Variables:    cmd.Variables,
+		Enabled:      cmd.Enabled,
+		Provisioned:  cmd.Provisioned,
+		UserID:       cmd.UserID,
+		NextRun:      nextRun,
+		Created:      now,
+		Updated:      now,
+	}
+	if err := s.store.Insert(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd *reports.UpdateReportCommand) (*reports.Report, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	report, err := s.store.Get(ctx, &reports.GetReportQuery{UID: cmd.UID, OrgID: cmd.OrgID})
+	if err != nil {
+		return nil, err
+	}
+	if report.Provisioned && !cmd.Provisioned {
+		return nil, reports.ErrProvisionedReport.Errorf("report %s is provisioned", cmd.UID)
+	}
+
+	nextRun, err := s.nextRun(cmd.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Name = cmd.Name
+	report.DashboardUID = cmd.DashboardUID
+	report.Schedule = cmd.Schedule
+	report.Recipients = cmd.Recipients
+	report.Format = formatOrDefault(cmd.Format)
+	report.TimeFrom = cmd.TimeFrom
+	report.TimeTo = cmd.TimeTo
+	report.Variables = cmd.Variables
+	report.Enabled = cmd.Enabled
+	report.Provisioned = cmd.Provisioned
+	report.UserID = cmd.UserID
+	report.NextRun = nextRun
+	report.Updated = s.now()
+	if err := s.store.Update(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Service) Get(ctx context.Context, query *reports.GetReportQuery) (*reports.Report, error) {
+	return s.store.Get(ctx, query)
+}
+
+func (s *Service) List(ctx context.Context, query *reports.ListReportsQuery) ([]*reports.Report, error) {
+	return s.store.List(ctx, query)
+}
+
+func (s *Service) Delete(ctx context.Context, cmd *reports.DeleteReportCommand) error {
+	report, err := s.store.Get(ctx, &reports.GetReportQuery{UID: cmd.UID, OrgID: cmd.OrgID})
+	if err != nil {
+		return err
+	}
+	if report.Provisioned && !cmd.Provisioned {
+		return reports.ErrProvisionedReport.Errorf("report %s is provisioned", cmd.UID)
+	}
+	return s.store.Delete(ctx, cmd)
+}
+
+func (s *Service) SendNow(ctx context.Context, cmd *reports.SendReportCommand) error {
+	report, err := s.store.Get(ctx, &reports.GetReportQuery{UID: cmd.UID, OrgID: cmd.OrgID})
+	if err != nil {
+		return err
+	}
+
+	sendErr := s.send(ctx, report)
+	s.saveResult(ctx, report, sendErr)
+	return sendErr
+}
+
+func (s *Service) sendDue(ctx context.Context) {
+	now := s.now()
+	due, err := s.store.GetDue(ctx, &reports.GetDueReportsQuery{Now: now, Limit: maxReportsPerRun})
+	if err != nil {
+		s.log.Error("Failed to get due reports", "error", err)
+		return
+	}
+
+	for _, report := range due {
+		nextRun, err := s.nextRun(report.Schedule)
+		if err != nil {
+			s.log.Warn("Invalid report schedule", "uid", report.UID, "error", err)
+			continue
+		}
+
+		// Claiming moves the next run forward, so only one instance sends the report.
+		claim := &reports.ClaimReportRunCommand{ID: report.ID, DueRun: report.NextRun, NextRun: nextRun}
+		if err := s.store.ClaimRun(ctx, claim); err != nil {
+			s.log.Error("Failed to claim report run", "uid", report.UID, "error", err)
+			continue
+		}
+		if !claim.Claimed {
+			continue
+		}
+
+		s.saveResult(ctx, report, s.send(ctx, report))
+	}
+}
+
+func (s *Service) saveResult(ctx context.Context, report *reports.Report, sendErr error) {
+	result := &reports.UpdateReportResultCommand{ID: report.ID, LastRun: s.now()}
+	if sendErr != nil {
+		s.log.Warn("Failed to send report", "uid", report.UID, "dashboardUID", report.DashboardUID, "error", sendErr)
+		result.LastError = sendErr.Error()
+	}
+	if err := s.store.UpdateResult(ctx, result); err != nil {
+		s.log.Error("Failed to save report result", "uid", report.UID, "error", err)
+	}
+}
+
+// send renders the dashboard of the report as its owner and emails it to
+// the recipients.
+func (s *Service) send(ctx context.Context, report *reports.Report) error {
+	if !s.renderer.IsAvailable(ctx) {
+		return reports.ErrRendererUnavailable.Errorf("image renderer is not available")
+	}
+
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: report.DashboardUID, OrgID: report.OrgID})
+	if err != nil {
+		return err
+	}
+
+	authOpts, err := s.authOpts(ctx, report, dash)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := s.timeRange(report, dash)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("orgId", strconv.FormatInt(report.OrgID, 10))
+	params.Set("from", strconv.FormatInt(from.UnixMilli(), 10))
+	params.Set("to", strconv.FormatInt(to.UnixMilli(), 10))
+	for name, value := range report.Variables {
+		params.Set("var-"+name, value)
+	}
+	dashboardPath := path.Join("d", dash.UID, dash.Slug)
+	dashboardURL := s.cfg.AppURL + dashboardPath + "?" + params.Encode()
+	params.Set("kiosk", "true")
+
+	renderType := rendering.RenderPDF
+	if report.Format == reports.FormatPNG {
+		renderType = rendering.RenderPNG
+	}
+
+	result, err := s.renderer.Render(ctx, renderType, rendering.Opts{
+		TimeoutOpts: rendering.TimeoutOpts{
+			Timeout: s.cfg.ReportsRenderTimeout,
+		},
+		AuthOpts: authOpts,
+		ErrorOpts: rendering.ErrorOpts{
+			ErrorConcurrentLimitReached: true,
+			ErrorRenderUnavailable:      true,
+		},
+		Width:           renderWidth,
+		Height:          renderHeight,
+		Path:            dashboardPath + "?" + params.Encode(),
+		ConcurrentLimit: s.cfg.RendererConcurrentRequestLimit,
+		Theme:           models.ThemeLight,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(result.FilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.log.Warn("Failed to remove rendered report", "path", result.FilePath, "error", err)
+		}
+	}()
+
+	// nolint:gosec
+	// The file path comes from the rendering service.
+	content, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		return err
+	}
+
+	return s.emailSender.SendEmailCommandHandlerSync(ctx, &notifications.SendEmailCommandSync{
+		SendEmailCommand: notifications.SendEmailCommand{
+			To:       report.Recipients,
+			Template: emailTemplate,
+			Data: map[string]any{
+				"ReportName":     report.Name,
+				"DashboardTitle": dash.Title,
+				"DashboardUrl":   dashboardURL,
+				"TimeFrom":       from.UTC().Format(time.RFC1123),
+				"TimeTo":         to.UTC().Format(time.RFC1123),
+			},
+			AttachedFiles: []*notifications.SendEmailAttachFile{{
+				Name:    fmt.Sprintf("%s.%s", dash.Slug, renderType),
+				Content: content,
+			}},
+		},
+	})
+}
+
+// authOpts returns the identity the dashboard is rendered as. Reports are
+// rendered as the user that last saved them, who needs to be able to view
+// the dashboard. Provisioned reports have no owner and are rendered as an admin.
+func (s *Service) authOpts(ctx context.Context, report *reports.Report, dash *dashboards.Dashboard) (rendering.AuthOpts, error) {
+	if report.Provisioned {
+		return rendering.AuthOpts{OrgID: report.OrgID, OrgRole: org.RoleAdmin}, nil
+	}
+
+	usr, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: report.UserID, OrgID: report.OrgID})
+	if err != nil {
+		return rendering.AuthOpts{}, fmt.Errorf("failed to get report owner: %w", err)
+	}
+
+	g, err := guardian.NewByDashboard(ctx, dash, report.OrgID, usr)
+	if err != nil {
+		return rendering.AuthOpts{}, err
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return rendering.AuthOpts{}, reports.ErrDashboardAccess.Errorf("user %d can not view dashboard %s", usr.UserID, dash.UID)
+	}
+
+	return rendering.AuthOpts{OrgID: report.OrgID, UserID: usr.UserID, OrgRole: usr.OrgRole}, nil
+}
+
+// timeRange resolves the time range of the report, which defaults to the
+// time range saved in the dashboard.
+func (s *Service) timeRange(report *reports.Report, dash *dashboards.Dashboard) (time.Time, time.Time, error) {
+	from, to := report.TimeFrom, report.TimeTo
+	if from == "" || to == "" {
+		from = dash.Data.GetPath("time", "from").MustString("now-6h")
+		to = dash.Data.GetPath("time", "to").MustString("now")
+	}
+
+	timeRange := legacydata.DataTimeRange{From: from, To: to, Now: s.now()}
+	fromTime, err := timeRange.ParseFrom()
+	if err != nil {
+		return time.Time{}, time.Time{}, reports.ErrInvalidTimeRange.Errorf("invalid time range start %q: %w", from, err)
+	}
+	toTime, err := timeRange.ParseTo()
+	if err != nil {
+		return time.Time{}, time.Time{}, reports.ErrInvalidTimeRange.Errorf("invalid time range end %q: %w", to, err)
+	}
+	return fromTime, toTime, nil
+}
+
+func (s *Service) nextRun(schedule string) (time.Time, error) {
+	sched, err := reports.ParseSchedule(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(s.now()).Truncate(time.Second), nil
+}
+
+func formatOrDefault(format reports.Format) reports.Format {
+	if format == "" {
+		return reports.FormatPDF
+	}
+	return format
+}
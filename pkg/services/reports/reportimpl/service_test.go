@@ -0,0 +1,221 @@
+package reportimpl
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/reports/reportstest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type testScenario struct {
+	service  *Service
+	renderer *reportstest.StubRenderer
+	email    *notifications.NotificationServiceMock
+}
+
+func setupTestService(t *testing.T, now time.Time) *testScenario {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	cfg.ReportsEnabled = true
+	cfg.ReportsRenderTimeout = time.Minute
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(&dashboards.Dashboard{
+		UID:   "ops",
+		Slug:  "operations",
+		Title: "Operations",
+		OrgID: 1,
+		Data:  simplejson.NewFromAny(map[string]any{"time": map[string]any{"from": "now-1h", "to": "now"}}),
+	}, nil).Maybe()
+
+	userService := &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 10, OrgID: 1, OrgRole: org.RoleEditor}}
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+
+	scenario := &testScenario{
+		renderer: reportstest.NewStubRenderer(t.TempDir()),
+		email:    notifications.MockNotificationService(),
+	}
+	scenario.service = &Service{
+		cfg:              cfg,
+		store:            &sqlStore{db: db.InitTestDB(t)},
+		renderer:         scenario.renderer,
+		emailSender:      scenario.email,
+		dashboardService: dashboardService,
+		userService:      userService,
+		log:              log.NewNopLogger(),
+		now:              func() time.Time { return now },
+	}
+	return scenario
+}
+
+func newCreateCommand(uid string) *reports.CreateReportCommand {
+	return &reports.CreateReportCommand{
+		ReportSettings: reports.ReportSettings{
+			Name:         "Operations weekly",
+			DashboardUID: "ops",
+			Schedule:     "0 8 * * 1",
+			Recipients:   []string{"ops@example.com"},
+			TimeFrom:     "now-7d",
+			TimeTo:       "now",
+			Variables:    map[string]string{"env": "prod"},
+			Enabled:      true,
+		},
+		UID:    uid,
+		OrgID:  1,
+		UserID: 10,
+	}
+}
+
+func TestIntegrationReportService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	// a Wednesday
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Create validates the report and schedules the next run", func(t *testing.T) {
+		sc := setupTestService(t, now)
+
+		_, err := sc.service.Create(ctx, &reports.CreateReportCommand{ReportSettings: reports.ReportSettings{
+			Name: "Invalid", DashboardUID: "ops", Schedule: "every monday", Recipients: []string{"ops@example.com"},
+		}, OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrInvalidSchedule)
+
+		report, err := sc.service.Create(ctx, newCreateCommand(""))
+		require.NoError(t, err)
+		require.NotEmpty(t, report.UID)
+		require.Equal(t, reports.FormatPDF, report.Format)
+		require.Equal(t, time.Date(2024, 5, 20, 8, 0, 0, 0, time.Local).Unix(), report.NextRun.Unix())
+	})
+
+	t.Run("SendNow renders the dashboard and emails it", func(t *testing.T) {
+		sc := setupTestService(t, now)
+		_, err := sc.service.Create(ctx, newCreateCommand("weekly"))
+		require.NoError(t, err)
+
+		err = sc.service.SendNow(ctx, &reports.SendReportCommand{UID: "weekly", OrgID: 1})
+		require.NoError(t, err)
+
+		require.Len(t, sc.renderer.Renders, 1)
+		render := sc.renderer.Renders[0]
+		require.Equal(t, rendering.RenderPDF, render.Type)
+		require.Equal(t, rendering.AuthOpts{OrgID: 1, UserID: 10, OrgRole: org.RoleEditor}, render.Opts.AuthOpts)
+
+		renderURL, err := url.Parse(render.Opts.Path)
+		require.NoError(t, err)
+		require.Equal(t, "d/ops/operations", renderURL.Path)
+		require.Equal(t, "prod", renderURL.Query().Get("var-env"))
+		require.Equal(t, "true", renderURL.Query().Get("kiosk"))
+		require.Equal(t, "1715162400000", renderURL.Query().Get("from"))
+		require.Equal(t, "1715767200000", renderURL.Query().Get("to"))
+
+		sent := sc.email.EmailSync
+		require.Equal(t, []string{"ops@example.com"}, sent.To)
+		require.Equal(t, "report", sent.Template)
+		require.Equal(t, "Operations", sent.Data["DashboardTitle"])
+		require.Len(t, sent.AttachedFiles, 1)
+		require.Equal(t, "operations.pdf", sent.AttachedFiles[0].Name)
+		require.Equal(t, []byte("rendered"), sent.AttachedFiles[0].Content)
+
+		// the rendered file is removed once sent
+		files, err := os.ReadDir(sc.renderer.Dir)
+		require.NoError(t, err)
+		require.Empty(t, files)
+
+		report, err := sc.service.Get(ctx, &reports.GetReportQuery{UID: "weekly", OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, now.Unix(), report.LastRun.Unix())
+		require.Empty(t, report.LastError)
+	})
+
+	t.Run("SendNow records the error when the renderer is unavailable", func(t *testing.T) {
+		sc := setupTestService(t, now)
+		sc.renderer.Unavailable = true
+		_, err := sc.service.Create(ctx, newCreateCommand("weekly"))
+		require.NoError(t, err)
+
+		err = sc.service.SendNow(ctx, &reports.SendReportCommand{UID: "weekly", OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrRendererUnavailable)
+
+		report, err := sc.service.Get(ctx, &reports.GetReportQuery{UID: "weekly", OrgID: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, report.LastError)
+	})
+
+	t.Run("Due reports are sent once and rescheduled", func(t *testing.T) {
+		sc := setupTestService(t, now)
+		_, err := sc.service.Create(ctx, newCreateCommand("weekly"))
+		require.NoError(t, err)
+
+		// the first run is next Monday
+		sc.service.sendDue(ctx)
+		require.Len(t, sc.renderer.Renders, 0)
+
+		monday := time.Date(2024, 5, 20, 8, 0, 30, 0, time.Local)
+		sc.service.now = func() time.Time { return monday }
+		sc.service.sendDue(ctx)
+		sc.service.sendDue(ctx)
+		require.Len(t, sc.renderer.Renders, 1)
+
+		report, err := sc.service.Get(ctx, &reports.GetReportQuery{UID: "weekly", OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2024, 5, 27, 8, 0, 0, 0, time.Local).Unix(), report.NextRun.Unix())
+	})
+
+	t.Run("Provisioned reports can only be changed by provisioning", func(t *testing.T) {
+		sc := setupTestService(t, now)
+		cmd := newCreateCommand("provisioned")
+		cmd.Provisioned = true
+		_, err := sc.service.Create(ctx, cmd)
+		require.NoError(t, err)
+
+		_, err = sc.service.Update(ctx, &reports.UpdateReportCommand{ReportSettings: cmd.ReportSettings, UID: "provisioned", OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrProvisionedReport)
+
+		err = sc.service.Delete(ctx, &reports.DeleteReportCommand{UID: "provisioned", OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrProvisionedReport)
+
+		err = sc.service.Delete(ctx, &reports.DeleteReportCommand{UID: "provisioned", OrgID: 1, Provisioned: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("Provisioned reports are rendered as an admin", func(t *testing.T) {
+		sc := setupTestService(t, now)
+		cmd := newCreateCommand("provisioned")
+		cmd.Provisioned = true
+		cmd.UserID = 0
+		cmd.Format = reports.FormatPNG
+		_, err := sc.service.Create(ctx, cmd)
+		require.NoError(t, err)
+
+		err = sc.service.SendNow(ctx, &reports.SendReportCommand{UID: "provisioned", OrgID: 1})
+		require.NoError(t, err)
+
+		require.Len(t, sc.renderer.Renders, 1)
+		require.Equal(t, rendering.RenderPNG, sc.renderer.Renders[0].Type)
+		require.Equal(t, rendering.AuthOpts{OrgID: 1, OrgRole: org.RoleAdmin}, sc.renderer.Renders[0].Opts.AuthOpts)
+		require.Equal(t, "operations.png", sc.email.EmailSync.AttachedFiles[0].Name)
+	})
+}
@@ -0,0 +1,19 @@
+package reportimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+type store interface {
+	Insert(context.Context, *reports.Report) error
+	Update(context.Context, *reports.Report) error
+	Get(context.Context, *reports.GetReportQuery) (*reports.Report, error)
+	List(context.Context, *reports.ListReportsQuery) ([]*reports.Report, error)
+	Delete(context.Context, *reports.DeleteReportCommand) error
+
+	GetDue(context.Context, *reports.GetDueReportsQuery) ([]*reports.Report, error)
+	ClaimRun(context.Context, *reports.ClaimReportRunCommand) error
+	UpdateResult(context.Context, *reports.UpdateReportResultCommand) error
+}
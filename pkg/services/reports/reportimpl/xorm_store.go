@@ -0,0 +1,124 @@
+package reportimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+type sqlStore struct {
+	db db.DB
+}
+
+var _ store = &sqlStore{}
+
+func (s *sqlStore) Insert(ctx context.Context, report *reports.Report) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", report.OrgID, report.UID).Exist(&reports.Report{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return reports.ErrReportAlreadyExists.Errorf("report %s already exists", report.UID)
+		}
+
+		_, err = sess.Insert(report)
+		return err
+	})
+}
+
+func (s *sqlStore) Update(ctx context.Context, report *reports.Report) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(report.ID).
+			Cols("name", "dashboard_uid", "schedule", "recipients", "format", "time_from", "time_to",
+				"variables", "enabled", "provisioned", "user_id", "next_run", "updated").
+			Update(report)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return reports.ErrReportNotFound.Errorf("report %s not found", report.UID)
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) Get(ctx context.Context, query *reports.GetReportQuery) (*reports.Report, error) {
+	report := &reports.Report{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", query.OrgID, query.UID).Get(report)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return reports.ErrReportNotFound.Errorf("report %s not found", query.UID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *sqlStore) List(ctx context.Context, query *reports.ListReportsQuery) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ?", query.OrgID)
+		if query.DashboardUID != "" {
+			sess.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		return sess.Asc("name").Find(&result)
+	})
+	return result, err
+}
+
+func (s *sqlStore) Delete(ctx context.Context, cmd *reports.DeleteReportCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM report WHERE org_id = ? AND uid = ?", cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return reports.ErrReportNotFound.Errorf("report %s not found", cmd.UID)
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) GetDue(ctx context.Context, query *reports.GetDueReportsQuery) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("enabled = ? AND next_run <= ?", true, query.Now).Asc("next_run")
+		if query.Limit > 0 {
+			sess.Limit(query.Limit)
+		}
+		return sess.Find(&result)
+	})
+	return result, err
+}
+
+// ClaimRun moves the next run of the report, if it was not moved by another
+// instance since the report was read.
+func (s *sqlStore) ClaimRun(ctx context.Context, cmd *reports.ClaimReportRunCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE report SET next_run = ? WHERE id = ? AND next_run = ?", cmd.NextRun, cmd.ID, cmd.DueRun)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		cmd.Claimed = affected == 1
+		return nil
+	})
+}
+
+func (s *sqlStore) UpdateResult(ctx context.Context, cmd *reports.UpdateReportResultCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE report SET last_run = ?, last_error = ? WHERE id = ?", cmd.LastRun, cmd.LastError, cmd.ID)
+		return err
+	})
+}
@@ -0,0 +1,142 @@
+package reportimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationReportStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	store := &sqlStore{db: db.InitTestDB(t)}
+
+	newReport := func(uid string, orgID int64, nextRun time.Time) *reports.Report {
+		return &reports.Report{
+			UID:          uid,
+			OrgID:        orgID,
+			Name:         "Report " + uid,
+			DashboardUID: "dash",
+			Schedule:     "@daily",
+			Recipients:   []string{"ops@example.com", "team@example.com"},
+			Format:       reports.FormatPDF,
+			Variables:    map[string]string{"env": "prod"},
+			Enabled:      true,
+			UserID:       1,
+			NextRun:      nextRun,
+			Created:      now,
+			Updated:      now,
+		}
+	}
+
+	t.Run("Can insert and get a report", func(t *testing.T) {
+		err := store.Insert(ctx, newReport("a", 1, now.Add(time.Hour)))
+		require.NoError(t, err)
+
+		report, err := store.Get(ctx, &reports.GetReportQuery{UID: "a", OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, "Report a", report.Name)
+		require.Equal(t, []string{"ops@example.com", "team@example.com"}, report.Recipients)
+		require.Equal(t, map[string]string{"env": "prod"}, report.Variables)
+		require.True(t, report.Enabled)
+	})
+
+	t.Run("Inserting a report with an existing uid fails", func(t *testing.T) {
+		err := store.Insert(ctx, newReport("a", 1, now))
+		require.ErrorIs(t, err, reports.ErrReportAlreadyExists)
+
+		err = store.Insert(ctx, newReport("a", 2, now))
+		require.NoError(t, err)
+	})
+
+	t.Run("Getting a missing report fails", func(t *testing.T) {
+		_, err := store.Get(ctx, &reports.GetReportQuery{UID: "missing", OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+	})
+
+	t.Run("Can update a report", func(t *testing.T) {
+		report, err := store.Get(ctx, &reports.GetReportQuery{UID: "a", OrgID: 1})
+		require.NoError(t, err)
+
+		report.Name = "Renamed"
+		report.Enabled = false
+		report.Recipients = []string{"other@example.com"}
+		err = store.Update(ctx, report)
+		require.NoError(t, err)
+
+		report, err = store.Get(ctx, &reports.GetReportQuery{UID: "a", OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, "Renamed", report.Name)
+		require.False(t, report.Enabled)
+		require.Equal(t, []string{"other@example.com"}, report.Recipients)
+	})
+
+	t.Run("Can list reports of an organization", func(t *testing.T) {
+		err := store.Insert(ctx, newReport("b", 1, now))
+		require.NoError(t, err)
+
+		result, err := store.List(ctx, &reports.ListReportsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		result, err = store.List(ctx, &reports.ListReportsQuery{OrgID: 1, DashboardUID: "other"})
+		require.NoError(t, err)
+		require.Len(t, result, 0)
+	})
+
+	t.Run("Due reports are enabled reports with a past next run", func(t *testing.T) {
+		err := store.Insert(ctx, newReport("c", 1, now.Add(-time.Minute)))
+		require.NoError(t, err)
+
+		due, err := store.GetDue(ctx, &reports.GetDueReportsQuery{Now: now})
+		require.NoError(t, err)
+		uids := make([]string, 0, len(due))
+		for _, report := range due {
+			uids = append(uids, report.UID)
+		}
+		require.ElementsMatch(t, []string{"b", "c"}, uids)
+	})
+
+	t.Run("A report run can only be claimed once", func(t *testing.T) {
+		report, err := store.Get(ctx, &reports.GetReportQuery{UID: "c", OrgID: 1})
+		require.NoError(t, err)
+
+		claim := &reports.ClaimReportRunCommand{ID: report.ID, DueRun: report.NextRun, NextRun: now.Add(time.Hour)}
+		require.NoError(t, store.ClaimRun(ctx, claim))
+		require.True(t, claim.Claimed)
+
+		claim.Claimed = false
+		require.NoError(t, store.ClaimRun(ctx, claim))
+		require.False(t, claim.Claimed)
+
+		err = store.UpdateResult(ctx, &reports.UpdateReportResultCommand{ID: report.ID, LastRun: now, LastError: "failed"})
+		require.NoError(t, err)
+
+		report, err = store.Get(ctx, &reports.GetReportQuery{UID: "c", OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, "failed", report.LastError)
+		require.True(t, report.NextRun.After(now))
+	})
+
+	t.Run("Can delete a report", func(t *testing.T) {
+		err := store.Delete(ctx, &reports.DeleteReportCommand{UID: "b", OrgID: 1})
+		require.NoError(t, err)
+
+		err = store.Delete(ctx, &reports.DeleteReportCommand{UID: "b", OrgID: 1})
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+	})
+}
@@ -0,0 +1,27 @@
+package reports
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/rendering"
+)
+
+// Service manages scheduled reports, which render a dashboard and email the
+// result to a list of recipients.
+type Service interface {
+	Create(context.Context, *CreateReportCommand) (*Report, error)
+	Update(context.Context, *UpdateReportCommand) (*Report, error)
+	Get(context.Context, *GetReportQuery) (*Report, error)
+	List(context.Context, *ListReportsQuery) ([]*Report, error)
+	Delete(context.Context, *DeleteReportCommand) error
+
+	// SendNow renders and sends the report right away, regardless of its
+	// schedule and whether it is enabled.
+	SendNow(context.Context, *SendReportCommand) error
+}
+
+// Renderer is the part of the rendering service used to render reports.
+type Renderer interface {
+	IsAvailable(ctx context.Context) bool
+	Render(ctx context.Context, renderType rendering.RenderType, opts rendering.Opts, session rendering.Session) (*rendering.RenderResult, error)
+}
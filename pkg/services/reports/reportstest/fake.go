@@ -0,0 +1,105 @@
+package reportstest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+var _ reports.Service = &FakeReportService{}
+
+// FakeReportService keeps reports in memory, by organization and uid.
+type FakeReportService struct {
+	Reports       map[int64]map[string]*reports.Report
+	SentReports   []*reports.SendReportCommand
+	ExpectedError error
+}
+
+func NewFakeReportService() *FakeReportService {
+	return &FakeReportService{Reports: map[int64]map[string]*reports.Report{}}
+}
+
+func (f *FakeReportService) Create(_ context.Context, cmd *reports.CreateReportCommand) (*reports.Report, error) {
+	if f.ExpectedError != nil {
+		return nil, f.ExpectedError
+	}
+	if _, ok := f.Reports[cmd.OrgID][cmd.UID]; ok {
+		return nil, reports.ErrReportAlreadyExists.Errorf("report %s already exists", cmd.UID)
+	}
+	if f.Reports[cmd.OrgID] == nil {
+		f.Reports[cmd.OrgID] = map[string]*reports.Report{}
+	}
+	report := newReport(cmd.UID, cmd.OrgID, cmd.UserID, cmd.Provisioned, cmd.ReportSettings)
+	f.Reports[cmd.OrgID][cmd.UID] = report
+	return report, nil
+}
+
+func (f *FakeReportService) Update(_ context.Context, cmd *reports.UpdateReportCommand) (*reports.Report, error) {
+	if f.ExpectedError != nil {
+		return nil, f.ExpectedError
+	}
+	if _, ok := f.Reports[cmd.OrgID][cmd.UID]; !ok {
+		return nil, reports.ErrReportNotFound.Errorf("report %s not found", cmd.UID)
+	}
+	report := newReport(cmd.UID, cmd.OrgID, cmd.UserID, cmd.Provisioned, cmd.ReportSettings)
+	f.Reports[cmd.OrgID][cmd.UID] = report
+	return report, nil
+}
+
+func (f *FakeReportService) Get(_ context.Context, query *reports.GetReportQuery) (*reports.Report, error) {
+	if f.ExpectedError != nil {
+		return nil, f.ExpectedError
+	}
+	report, ok := f.Reports[query.OrgID][query.UID]
+	if !ok {
+		return nil, reports.ErrReportNotFound.Errorf("report %s not found", query.UID)
+	}
+	return report, nil
+}
+
+func (f *FakeReportService) List(_ context.Context, query *reports.ListReportsQuery) ([]*reports.Report, error) {
+	if f.ExpectedError != nil {
+		return nil, f.ExpectedError
+	}
+	result := make([]*reports.Report, 0)
+	for _, report := range f.Reports[query.OrgID] {
+		if query.DashboardUID == "" || report.DashboardUID == query.DashboardUID {
+			result = append(result, report)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeReportService) Delete(_ context.Context, cmd *reports.DeleteReportCommand) error {
+	if f.ExpectedError != nil {
+		return f.ExpectedError
+	}
+	if _, ok := f.Reports[cmd.OrgID][cmd.UID]; !ok {
+		return reports.ErrReportNotFound.Errorf("report %s not found", cmd.UID)
+	}
+	delete(f.Reports[cmd.OrgID], cmd.UID)
+	return nil
+}
+
+func (f *FakeReportService) SendNow(_ context.Context, cmd *reports.SendReportCommand) error {
+	f.SentReports = append(f.SentReports, cmd)
+	return f.ExpectedError
+}
+
+func newReport(uid string, orgID int64, userID int64, provisioned bool, settings reports.ReportSettings) *reports.Report {
+	return &reports.Report{
+		UID:          uid,
+		OrgID:        orgID,
+		Name:         settings.Name,
+		DashboardUID: settings.DashboardUID,
+		Schedule:     settings.Schedule,
+		Recipients:   settings.Recipients,
+		Format:       settings.Format,
+		TimeFrom:     settings.TimeFrom,
+		TimeTo:       settings.TimeTo,
+		Variables:    settings.Variables,
+		Enabled:      settings.Enabled,
+		Provisioned:  provisioned,
+		UserID:       userID,
+	}
+}
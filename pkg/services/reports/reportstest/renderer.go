@@ -0,0 +1,54 @@
+package reportstest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+var _ reports.Renderer = &StubRenderer{}
+
+// StubRenderer writes Content to a file in Dir instead of rendering the
+// dashboard, and records the options of each render.
+type StubRenderer struct {
+	Dir         string
+	Content     []byte
+	Unavailable bool
+	Err         error
+
+	Renders []StubRender
+}
+
+type StubRender struct {
+	Type rendering.RenderType
+	Opts rendering.Opts
+}
+
+func NewStubRenderer(dir string) *StubRenderer {
+	return &StubRenderer{Dir: dir, Content: []byte("rendered")}
+}
+
+func (r *StubRenderer) IsAvailable(context.Context) bool {
+	return !r.Unavailable
+}
+
+func (r *StubRenderer) Render(_ context.Context, renderType rendering.RenderType, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+	r.Renders = append(r.Renders, StubRender{Type: renderType, Opts: opts})
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	f, err := os.CreateTemp(r.Dir, "report-*."+string(renderType))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Write(r.Content); err != nil {
+		return nil, err
+	}
+	return &rendering.RenderResult{FilePath: filepath.Clean(f.Name())}, nil
+}
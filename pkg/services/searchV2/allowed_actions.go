@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -89,6 +90,8 @@ func (s *StandardSearchService) createAllowedActions(ctx context.Context, orgId
 			prefix = datasources.ScopePrefix
 		case entityKindDashboard:
 			prefix = dashboards.ScopeDashboardsPrefix
+		case entityKindLibraryPanel:
+			prefix = libraryelements.ScopeLibraryPanelsPrefix
 		default:
 			continue
 		}
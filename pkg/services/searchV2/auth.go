@@ -2,16 +2,21 @@ package searchV2
 
 import (
 	"context"
+	"slices"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
-// ResourceFilter checks if a given a uid (resource identifier) check if we have the requested permission
+// ResourceFilter checks if a given a uid (resource identifier) check if we have the requested permission.
+// The parentUID is the folder of the resource, or the ID of the user who saved a query.
 type ResourceFilter func(kind entityKind, uid, parentUID string) bool
 
 // FutureAuthService eventually implemented by the security service
@@ -30,21 +35,49 @@ type simpleAuthService struct {
 
 func (a *simpleAuthService) GetDashboardReadFilter(ctx context.Context, orgID int64, user *user.SignedInUser) (ResourceFilter, error) {
 	canReadDashboard, canReadFolder := accesscontrol.Checker(user, dashboards.ActionDashboardsRead), accesscontrol.Checker(user, dashboards.ActionFoldersRead)
-	return func(kind entityKind, uid, parent string) bool {
-		if kind == entityKindFolder {
-			scopes, err := dashboards.GetInheritedScopes(ctx, orgID, uid, a.folderService)
+	canReadAlertRule, canReadLibraryPanel := accesscontrol.Checker(user, accesscontrol.ActionAlertingRuleRead), accesscontrol.Checker(user, libraryelements.ActionLibraryPanelsRead)
+	canReadDatasource := accesscontrol.Checker(user, datasources.ActionRead)
+	userID := strconv.FormatInt(user.UserID, 10)
+
+	// The filter is called for every search hit and most hits share a few
+	// folders, so inherited scopes are resolved once per folder.
+	inheritedScopes := map[string][]string{}
+	getInheritedScopes := func(folderUID string) []string {
+		scopes, ok := inheritedScopes[folderUID]
+		if !ok {
+			var err error
+			scopes, err = dashboards.GetInheritedScopes(ctx, orgID, folderUID, a.folderService)
 			if err != nil {
 				a.logger.Debug("Could not retrieve inherited folder scopes:", "err", err)
 			}
-			scopes = append(scopes, dashboards.ScopeFoldersProvider.GetResourceScopeUID(uid))
-			return canReadFolder(scopes...)
+			inheritedScopes[folderUID] = scopes
+		}
+		// Callers append to the scopes, so the cached slice is not shared.
+		return slices.Clip(scopes)
+	}
+	// getFolderScopes returns the scopes of a folder and of its parents.
+	getFolderScopes := func(folderUID string) []string {
+		return append(getInheritedScopes(folderUID), dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID))
+	}
+
+	return func(kind entityKind, uid, parent string) bool {
+		switch kind {
+		case entityKindAlertRule:
+			return canReadAlertRule(getFolderScopes(parent)...)
+		case entityKindLibraryPanel:
+			folderScopes := getFolderScopes(parent)
+			return canReadFolder(folderScopes...) || canReadLibraryPanel(append(folderScopes, libraryelements.ScopeLibraryPanelsProvider.GetResourceScopeUID(uid))...)
+		case entityKindDatasource:
+			return canReadDatasource(datasources.ScopeProvider.GetResourceScopeUID(uid))
+		case entityKindQuery:
+			return !user.IsAnonymous && parent == userID
+		}
+
+		if kind == entityKindFolder {
+			return canReadFolder(getFolderScopes(uid)...)
 		} else if kind == entityKindDashboard {
-			scopes, err := dashboards.GetInheritedScopes(ctx, orgID, parent, a.folderService)
-			if err != nil {
-				a.logger.Debug("Could not retrieve inherited folder scopes:", "err", err)
-			}
+			scopes := getFolderScopes(parent)
 			scopes = append(scopes, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(uid))
-			scopes = append(scopes, dashboards.ScopeFoldersProvider.GetResourceScopeUID(parent))
 			return canReadDashboard(scopes...)
 		}
 		return false
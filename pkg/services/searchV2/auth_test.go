@@ -0,0 +1,50 @@
+package searchV2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// countingFolderService counts GetParents calls per folder.
+type countingFolderService struct {
+	foldertest.FakeService
+	calls map[string]int
+}
+
+func (s *countingFolderService) GetParents(_ context.Context, q folder.GetParentsQuery) ([]*folder.Folder, error) {
+	s.calls[q.UID]++
+	if q.UID == "child" {
+		return []*folder.Folder{{UID: "parent"}}, nil
+	}
+	return nil, nil
+}
+
+func TestSimpleAuthService_GetDashboardReadFilter(t *testing.T) {
+	folderService := &countingFolderService{calls: map[string]int{}}
+	a := &simpleAuthService{folderService: folderService, logger: log.NewNopLogger()}
+	signedInUser := &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+		dashboards.ActionDashboardsRead: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("parent")},
+		dashboards.ActionFoldersRead:    {dashboards.ScopeFoldersProvider.GetResourceScopeUID("parent")},
+	}}}
+
+	filter, err := a.GetDashboardReadFilter(context.Background(), 1, signedInUser)
+	require.NoError(t, err)
+
+	// Access is inherited from the parent folder.
+	require.True(t, filter(entityKindDashboard, "a", "child"))
+	require.True(t, filter(entityKindDashboard, "b", "child"))
+	require.True(t, filter(entityKindFolder, "child", "parent"))
+	require.False(t, filter(entityKindDashboard, "c", "other"))
+	require.False(t, filter(entityKindDashboard, "d", "other"))
+
+	// Parents are resolved once per folder.
+	require.Equal(t, map[string]int{"child": 1, "other": 1}, folderService.calls)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/slugify"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/store/entity"
+	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
 )
 
 const (
@@ -33,11 +34,14 @@ const (
 	documentFieldTransformer = "transformer"
 	documentFieldDSUID       = "ds_uid"
 	documentFieldDSType      = "ds_type"
+	documentFieldOwner       = "owner" // user who saved a query
+	documentFieldQuery       = "query" // text of the queries
+	documentFieldMetric      = "metric"
 	DocumentFieldCreatedAt   = "created_at"
 	DocumentFieldUpdatedAt   = "updated_at"
 )
 
-func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
+func initOrgIndex(dashboards []dashboard, entities []indexedEntity, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
 	dashboardWriter, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
 	if err != nil {
 		return nil, fmt.Errorf("error opening writer: %v", err)
@@ -120,6 +124,14 @@ func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDas
 		}
 	}
 
+	// Then alert rules, library panels, data sources and saved queries.
+	for _, e := range entities {
+		batch.Insert(getEntityDoc(e))
+		if err := flushIfRequired(false); err != nil {
+			return nil, err
+		}
+	}
+
 	// Flush docs in batch with force as we are in the end.
 	if err := flushIfRequired(true); err != nil {
 		return nil, err
@@ -169,7 +181,8 @@ func getNonFolderDashboardDoc(dash dashboard, location string) *bluge.Document {
 	}
 
 	for _, ref := range dash.summary.References {
-		if ref.Family == entity.StandardKindDataSource {
+		switch ref.Family {
+		case entity.StandardKindDataSource:
 			if ref.Type != "" {
 				doc.AddField(bluge.NewKeywordField(documentFieldDSType, ref.Type).
 					StoreValue().
@@ -182,9 +195,16 @@ func getNonFolderDashboardDoc(dash dashboard, location string) *bluge.Document {
 					Aggregatable().
 					SearchTermPositions())
 			}
+		case entity.ExternalEntityReferenceMetric:
+			addQueryFields(doc, nil, []string{ref.Identifier})
 		}
 	}
 
+	// the dashboard can be found by the queries of all its panels
+	for _, panel := range dash.summary.Nested {
+		addQueryFields(doc, getPanelQueries(panel), nil)
+	}
+
 	return doc
 }
 
@@ -207,9 +227,11 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 			AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
 			AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindPanel)).Aggregatable().StoreValue()) // likely want independent index for this
 
+		addQueryFields(doc, getPanelQueries(panel), nil)
+
 		for _, ref := range panel.References {
 			switch ref.Family {
-			case entity.StandardKindDashboard:
+			case entity.StandardKindDataSource:
 				if ref.Type != "" {
 					doc.AddField(bluge.NewKeywordField(documentFieldDSType, ref.Type).
 						StoreValue().
@@ -230,6 +252,8 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 				if ref.Type == entity.ExternalEntityReferenceRuntime_Transformer && ref.Identifier != "" {
 					doc.AddField(bluge.NewKeywordField(documentFieldTransformer, ref.Identifier).Aggregatable())
 				}
+			case entity.ExternalEntityReferenceMetric:
+				addQueryFields(doc, nil, []string{ref.Identifier})
 			}
 		}
 
@@ -238,6 +262,27 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 	return docs
 }
 
+// getPanelQueries returns the text of the queries of a panel summary.
+func getPanelQueries(panel *entity.EntitySummary) []string {
+	var queries []string
+	if js, ok := panel.Fields[kdash.PanelFieldQueries]; ok {
+		_ = json.Unmarshal([]byte(js), &queries)
+	}
+	return queries
+}
+
+// addQueryFields indexes the text of queries, and the metrics they select.
+func addQueryFields(doc *bluge.Document, queries []string, metrics []string) {
+	for _, query := range queries {
+		doc.AddField(bluge.NewTextField(documentFieldQuery, query))
+	}
+	for _, metric := range metrics {
+		if metric != "" {
+			doc.AddField(bluge.NewKeywordField(documentFieldMetric, metric).Aggregatable())
+		}
+	}
+}
+
 // Names need to be indexed a few ways to support key features
 func newSearchDocument(uid string, name string, descr string, url string) *bluge.Document {
 	doc := bluge.NewDocument(uid)
@@ -441,6 +486,20 @@ func doSearchQuery(
 		hasConstraints = true
 	}
 
+	// Text of the queries
+	if q.QueryText != "" {
+		fullQuery.AddMust(bluge.NewMatchQuery(q.QueryText).
+			SetField(documentFieldQuery).
+			SetOperator(bluge.MatchQueryOperatorAnd)) // all terms must match
+		hasConstraints = true
+	}
+
+	// Metric
+	if q.Metric != "" {
+		fullQuery.AddMust(bluge.NewTermQuery(q.Metric).SetField(documentFieldMetric))
+		hasConstraints = true
+	}
+
 	// Folder
 	if q.Location != "" {
 		fullQuery.AddMust(bluge.NewTermQuery(q.Location).SetField(documentFieldLocation))
@@ -587,7 +646,7 @@ func doSearchQuery(
 		}
 
 		fKind.Append(kind)
-		fUID.Append(uid)
+		fUID.Append(entityUID(entityKind(kind), uid))
 		fPType.Append(ptype)
 		fName.Append(name)
 		fURL.Append(url)
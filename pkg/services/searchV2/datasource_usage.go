@@ -0,0 +1,104 @@
+package searchV2
+
+import (
+	"errors"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// datasourceUsageLimit is the maximum number of usages returned by the usage report.
+const datasourceUsageLimit = 1000
+
+// datasourceUsageKinds are the kinds of entities that can use a data source.
+var datasourceUsageKinds = []string{
+	string(entityKindDashboard),
+	string(entityKindPanel),
+	string(entityKindAlertRule),
+	string(entityKindLibraryPanel),
+	string(entityKindQuery),
+}
+
+// DatasourceUsage lists the dashboards, panels, alert rules, library panels and
+// saved queries that use a data source.
+type DatasourceUsage struct {
+	UID           string                `json:"uid"`
+	Dashboards    []DatasourceUsageItem `json:"dashboards"`
+	Panels        []DatasourceUsageItem `json:"panels"`
+	AlertRules    []DatasourceUsageItem `json:"alertRules"`
+	LibraryPanels []DatasourceUsageItem `json:"libraryPanels"`
+	Queries       []DatasourceUsageItem `json:"queries"`
+	// Truncated is true when only the first datasourceUsageLimit usages are listed
+	Truncated bool `json:"truncated"`
+}
+
+type DatasourceUsageItem struct {
+	UID      string `json:"uid"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Location string `json:"location,omitempty"`
+}
+
+func newDatasourceUsageQuery(uid string) DashboardQuery {
+	return DashboardQuery{
+		Datasource:   uid,
+		Kind:         datasourceUsageKinds,
+		Sort:         documentFieldName_sort,
+		SkipLocation: true,
+		Limit:        datasourceUsageLimit,
+	}
+}
+
+// newDatasourceUsage builds the usage report from the response of the usage query.
+func newDatasourceUsage(uid string, resp *backend.DataResponse) (*DatasourceUsage, error) {
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if len(resp.Frames) == 0 {
+		return nil, errors.New("invalid search response")
+	}
+
+	usage := &DatasourceUsage{
+		UID:           uid,
+		Dashboards:    []DatasourceUsageItem{},
+		Panels:        []DatasourceUsageItem{},
+		AlertRules:    []DatasourceUsageItem{},
+		LibraryPanels: []DatasourceUsageItem{},
+		Queries:       []DatasourceUsageItem{},
+	}
+
+	frame := resp.Frames[0]
+	fKind, _ := frame.FieldByName("kind")
+	fUID, _ := frame.FieldByName("uid")
+	fName, _ := frame.FieldByName("name")
+	fURL, _ := frame.FieldByName("url")
+	fLocation, _ := frame.FieldByName("location")
+	if fKind == nil || fUID == nil || fName == nil || fURL == nil || fLocation == nil {
+		return nil, errors.New("invalid search response")
+	}
+
+	for i := 0; i < fKind.Len(); i++ {
+		item := DatasourceUsageItem{
+			UID:      fUID.At(i).(string),
+			Name:     fName.At(i).(string),
+			URL:      fURL.At(i).(string),
+			Location: fLocation.At(i).(string),
+		}
+		switch entityKind(fKind.At(i).(string)) {
+		case entityKindDashboard:
+			usage.Dashboards = append(usage.Dashboards, item)
+		case entityKindPanel:
+			usage.Panels = append(usage.Panels, item)
+		case entityKindAlertRule:
+			usage.AlertRules = append(usage.AlertRules, item)
+		case entityKindLibraryPanel:
+			usage.LibraryPanels = append(usage.LibraryPanels, item)
+		case entityKindQuery:
+			usage.Queries = append(usage.Queries, item)
+		}
+	}
+
+	if meta, ok := frame.Meta.Custom.(*customMeta); ok {
+		usage.Truncated = meta.Count > uint64(fKind.Len())
+	}
+	return usage, nil
+}
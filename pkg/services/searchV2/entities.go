@@ -0,0 +1,427 @@
+package searchV2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blugelabs/bluge"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/store"
+	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
+)
+
+// indexedEntityKinds are the kinds indexed next to dashboards, folders and panels.
+var indexedEntityKinds = []entityKind{
+	entityKindAlertRule,
+	entityKindLibraryPanel,
+	entityKindDatasource,
+	entityKindQuery,
+}
+
+// entityKindByEntityType maps the entity types of the entity events to the kinds in the index.
+var entityKindByEntityType = map[store.EntityType]entityKind{
+	store.EntityTypeAlertRule:    entityKindAlertRule,
+	store.EntityTypeLibraryPanel: entityKindLibraryPanel,
+	store.EntityTypeDataSource:   entityKindDatasource,
+	store.EntityTypeQuery:        entityKindQuery,
+}
+
+type entityLoader interface {
+	// LoadEntities returns entities of the given kind. If uid is empty – then
+	// implementation must return all entities of the kind in the organization.
+	// If uid is not empty – then only return the entity with specified UID or
+	// empty slice if not found (this is required to apply partial update).
+	LoadEntities(ctx context.Context, orgID int64, kind entityKind, uid string) ([]indexedEntity, error)
+}
+
+// indexedEntity is an alert rule, library panel, data source or saved query.
+type indexedEntity struct {
+	kind        entityKind
+	uid         string
+	name        string
+	description string
+	url         string
+	// location is the folder of alert rules and library panels
+	location string
+	// owner is the ID of the user who saved a query, only they can find it
+	owner     string
+	tags      []string
+	dsUIDs    []string
+	dsType    string
+	panelType string
+	// queries is the text of the queries, and metrics the metrics they select
+	queries []string
+	metrics []string
+	created time.Time
+	updated time.Time
+}
+
+// Entities share the index with dashboards, so the kind is part of the
+// document ID to avoid conflicts between UIDs.
+func entityDocumentID(kind entityKind, uid string) string {
+	return string(kind) + "/" + uid
+}
+
+// entityUID returns the UID of an entity from its document ID.
+func entityUID(kind entityKind, id string) string {
+	if !kind.isIndexedEntity() {
+		return id
+	}
+	return strings.TrimPrefix(id, string(kind)+"/")
+}
+
+func getEntityDoc(e indexedEntity) *bluge.Document {
+	doc := newSearchDocument(entityDocumentID(e.kind, e.uid), e.name, e.description, e.url).
+		AddField(bluge.NewKeywordField(documentFieldKind, string(e.kind)).Aggregatable().StoreValue())
+
+	if e.location != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldLocation, e.location).Aggregatable().StoreValue())
+	}
+	if e.owner != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldOwner, e.owner).Aggregatable())
+	}
+	if !e.created.IsZero() {
+		doc.AddField(bluge.NewDateTimeField(DocumentFieldCreatedAt, e.created).Sortable().StoreValue())
+	}
+	if !e.updated.IsZero() {
+		doc.AddField(bluge.NewDateTimeField(DocumentFieldUpdatedAt, e.updated).Sortable().StoreValue())
+	}
+	if e.panelType != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldPanelType, e.panelType).Aggregatable().StoreValue())
+	}
+	if e.dsType != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldDSType, e.dsType).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	for _, dsUID := range e.dsUIDs {
+		doc.AddField(bluge.NewKeywordField(documentFieldDSUID, dsUID).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	for _, tag := range e.tags {
+		doc.AddField(bluge.NewKeywordField(documentFieldTag, tag).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	addQueryFields(doc, e.queries, e.metrics)
+	return doc
+}
+
+func (i *searchIndex) updateEntity(index *orgIndex, kind entityKind, uid string, entities []indexedEntity) error {
+	writer := index.writerForIndex(indexTypeDashboard)
+	if len(entities) == 0 {
+		batch := bluge.NewBatch()
+		batch.Delete(bluge.NewDocument(entityDocumentID(kind, uid)).ID())
+		return writer.Batch(batch)
+	}
+	doc := getEntityDoc(entities[0])
+	return writer.Update(doc.ID(), doc)
+}
+
+type sqlEntityLoader struct {
+	sql    db.DB
+	tracer tracing.Tracer
+}
+
+func newSQLEntityLoader(sql db.DB, tracer tracing.Tracer) *sqlEntityLoader {
+	return &sqlEntityLoader{sql: sql, tracer: tracer}
+}
+
+func (l sqlEntityLoader) LoadEntities(ctx context.Context, orgID int64, kind entityKind, uid string) ([]indexedEntity, error) {
+	ctx, span := l.tracer.Start(ctx, "sqlEntityLoader LoadEntities", trace.WithAttributes(
+		attribute.Int64("orgID", orgID),
+		attribute.String("kind", string(kind)),
+	))
+	defer span.End()
+
+	switch kind {
+	case entityKindAlertRule:
+		return l.loadAlertRules(ctx, orgID, uid)
+	case entityKindLibraryPanel:
+		return l.loadLibraryPanels(ctx, orgID, uid)
+	case entityKindDatasource:
+		return l.loadDatasources(ctx, orgID, uid)
+	case entityKindQuery:
+		return l.loadQueries(ctx, orgID, uid)
+	default:
+		return nil, fmt.Errorf("unsupported entity kind %s", kind)
+	}
+}
+
+type alertRuleQueryResult struct {
+	UID          string `xorm:"uid"`
+	Title        string `xorm:"title"`
+	NamespaceUID string `xorm:"namespace_uid"`
+	RuleGroup    string `xorm:"rule_group"`
+	Labels       []byte `xorm:"labels"`
+	Data         []byte `xorm:"data"`
+	Updated      time.Time
+}
+
+func (l sqlEntityLoader) loadAlertRules(ctx context.Context, orgID int64, uid string) ([]indexedEntity, error) {
+	rows := make([]*alertRuleQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("alert_rule").Where("org_id = ?", orgID)
+		if uid != "" {
+			sess.Where("uid = ?", uid)
+		}
+		sess.Cols("uid", "title", "namespace_uid", "rule_group", "labels", "data", "updated")
+		return sess.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		e := indexedEntity{
+			kind:        entityKindAlertRule,
+			uid:         row.UID,
+			name:        row.Title,
+			description: row.RuleGroup,
+			url:         fmt.Sprintf("/alerting/grafana/%s/view", row.UID),
+			location:    row.NamespaceUID,
+			updated:     row.Updated,
+		}
+
+		// alert rules only use the key part of labels, like dashboards
+		var labels map[string]string
+		if len(row.Labels) > 0 && json.Unmarshal(row.Labels, &labels) == nil {
+			for k := range labels {
+				e.tags = append(e.tags, k)
+			}
+			sort.Strings(e.tags)
+		}
+
+		var queries []struct {
+			DatasourceUID string         `json:"datasourceUid"`
+			Model         map[string]any `json:"model"`
+		}
+		if len(row.Data) > 0 && json.Unmarshal(row.Data, &queries) == nil {
+			models := make([]map[string]any, 0, len(queries))
+			for _, q := range queries {
+				if q.DatasourceUID == "" || q.DatasourceUID == expressionDatasourceUID {
+					continue
+				}
+				models = append(models, q.Model)
+				if !stringInSlice(q.DatasourceUID, e.dsUIDs) {
+					e.dsUIDs = append(e.dsUIDs, q.DatasourceUID)
+				}
+			}
+			info := kdash.ReadQueryInfo(models)
+			e.queries, e.metrics = info.Queries, info.Metrics
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+// expressionDatasourceUID is the UID of server side expressions in alert rule queries.
+const expressionDatasourceUID = "__expr__"
+
+type libraryPanelQueryResult struct {
+	UID         string `xorm:"uid"`
+	Name        string `xorm:"name"`
+	Description string `xorm:"description"`
+	Type        string `xorm:"type"`
+	Model       []byte `xorm:"model"`
+	FolderUID   string `xorm:"folder_uid"`
+	Created     time.Time
+	Updated     time.Time
+}
+
+func (l sqlEntityLoader) loadLibraryPanels(ctx context.Context, orgID int64, uid string) ([]indexedEntity, error) {
+	rows := make([]*libraryPanelQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := "SELECT le.uid, le.name, le.description, le.type, le.model, le.created, le.updated, COALESCE(dashboard.uid, '') AS folder_uid" +
+			" FROM library_element AS le LEFT JOIN dashboard ON dashboard.id = le.folder_id" +
+			" WHERE le.org_id = ? AND le.kind = ?"
+		params := []any{orgID, int64(model.PanelElement)}
+		if uid != "" {
+			sql += " AND le.uid = ?"
+			params = append(params, uid)
+		}
+		return sess.SQL(sql, params...).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		location := row.FolderUID
+		if location == "" {
+			location = folder.GeneralFolderUID
+		}
+		e := indexedEntity{
+			kind:        entityKindLibraryPanel,
+			uid:         row.UID,
+			name:        row.Name,
+			description: row.Description,
+			url:         "/library-panels/",
+			location:    location,
+			panelType:   row.Type,
+			created:     row.Created,
+			updated:     row.Updated,
+		}
+
+		var panel struct {
+			Datasource *struct {
+				UID  string `json:"uid"`
+				Type string `json:"type"`
+			} `json:"datasource"`
+			Targets []map[string]any `json:"targets"`
+		}
+		if len(row.Model) > 0 && json.Unmarshal(row.Model, &panel) == nil {
+			if panel.Datasource != nil {
+				if panel.Datasource.UID != "" {
+					e.dsUIDs = []string{panel.Datasource.UID}
+				}
+				e.dsType = panel.Datasource.Type
+			}
+			info := kdash.ReadQueryInfo(panel.Targets)
+			e.queries, e.metrics = info.Queries, info.Metrics
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+type datasourceQueryResult struct {
+	UID     string `xorm:"uid"`
+	Name    string `xorm:"name"`
+	Type    string `xorm:"type"`
+	Created time.Time
+	Updated time.Time
+}
+
+func (l sqlEntityLoader) loadDatasources(ctx context.Context, orgID int64, uid string) ([]indexedEntity, error) {
+	rows := make([]*datasourceQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("data_source").Where("org_id = ?", orgID)
+		if uid != "" {
+			sess.Where("uid = ?", uid)
+		}
+		sess.Cols("uid", "name", "type", "created", "updated")
+		return sess.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		entities = append(entities, indexedEntity{
+			kind:    entityKindDatasource,
+			uid:     row.UID,
+			name:    row.Name,
+			url:     fmt.Sprintf("/connections/datasources/edit/%s", row.UID),
+			dsUIDs:  []string{row.UID},
+			dsType:  row.Type,
+			created: row.Created,
+			updated: row.Updated,
+		})
+	}
+	return entities, nil
+}
+
+type savedQueryQueryResult struct {
+	UID           string `xorm:"uid"`
+	DatasourceUID string `xorm:"datasource_uid"`
+	CreatedBy     int64  `xorm:"created_by"`
+	CreatedAt     int64  `xorm:"created_at"`
+	Comment       string `xorm:"comment"`
+	Queries       []byte `xorm:"queries"`
+}
+
+// loadQueries loads the saved queries, which are the starred queries of the query history.
+func (l sqlEntityLoader) loadQueries(ctx context.Context, orgID int64, uid string) ([]indexedEntity, error) {
+	rows := make([]*savedQueryQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := "SELECT qh.uid, qh.datasource_uid, qh.created_by, qh.created_at, qh.comment, qh.queries" +
+			" FROM query_history AS qh INNER JOIN query_history_star AS qhs ON qhs.query_uid = qh.uid AND qhs.user_id = qh.created_by" +
+			" WHERE qh.org_id = ?"
+		params := []any{orgID}
+		if uid != "" {
+			sql += " AND qh.uid = ?"
+			params = append(params, uid)
+		}
+		return sess.SQL(sql, params...).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]indexedEntity, 0, len(rows))
+	for _, row := range rows {
+		created := time.Unix(row.CreatedAt, 0)
+		var targets []map[string]any
+		_ = json.Unmarshal(row.Queries, &targets)
+		info := kdash.ReadQueryInfo(targets)
+		entities = append(entities, indexedEntity{
+			kind:    entityKindQuery,
+			uid:     row.UID,
+			name:    savedQueryName(row.Comment, row.Queries),
+			url:     savedQueryURL(row.DatasourceUID, row.Queries),
+			owner:   strconv.FormatInt(row.CreatedBy, 10),
+			dsUIDs:  []string{row.DatasourceUID},
+			queries: info.Queries,
+			metrics: info.Metrics,
+			created: created,
+			updated: created,
+		})
+	}
+	return entities, nil
+}
+
+// savedQueryName returns the comment of the query, or the text of its first
+// query if it has no comment.
+func savedQueryName(comment string, queries []byte) string {
+	if comment != "" {
+		return comment
+	}
+
+	var targets []map[string]any
+	if json.Unmarshal(queries, &targets) != nil {
+		return ""
+	}
+	for _, target := range targets {
+		for _, key := range []string{"expr", "query", "rawSql"} {
+			if text, ok := target[key].(string); ok && text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// savedQueryURL returns the Explore URL that runs the saved query.
+func savedQueryURL(datasourceUID string, queries []byte) string {
+	if !json.Valid(queries) {
+		return "/explore"
+	}
+	left, err := json.Marshal(map[string]any{
+		"datasource": datasourceUID,
+		"queries":    json.RawMessage(queries),
+	})
+	if err != nil {
+		return "/explore"
+	}
+	return "/explore?left=" + url.QueryEscape(string(left))
+}
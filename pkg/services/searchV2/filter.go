@@ -21,23 +21,30 @@ type PermissionFilter struct {
 type entityKind string
 
 const (
-	entityKindPanel      entityKind = entity.StandardKindPanel
-	entityKindDashboard  entityKind = entity.StandardKindDashboard
-	entityKindFolder     entityKind = entity.StandardKindFolder
-	entityKindDatasource entityKind = entity.StandardKindDataSource
-	entityKindQuery      entityKind = entity.StandardKindQuery
+	entityKindPanel        entityKind = entity.StandardKindPanel
+	entityKindDashboard    entityKind = entity.StandardKindDashboard
+	entityKindFolder       entityKind = entity.StandardKindFolder
+	entityKindDatasource   entityKind = entity.StandardKindDataSource
+	entityKindQuery        entityKind = entity.StandardKindQuery
+	entityKindAlertRule    entityKind = entity.StandardKindAlertRule
+	entityKindLibraryPanel entityKind = entity.StandardKindLibraryPanel
 )
 
 func (r entityKind) IsValid() bool {
-	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder
+	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder || r.isIndexedEntity()
 }
 
 func (r entityKind) supportsAuthzCheck() bool {
-	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder
+	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder || r.isIndexedEntity()
+}
+
+// isIndexedEntity returns true for the kinds loaded by the entityLoader.
+func (r entityKind) isIndexedEntity() bool {
+	return r == entityKindAlertRule || r == entityKindLibraryPanel || r == entityKindDatasource || r == entityKindQuery
 }
 
 var (
-	permissionFilterFields                 = []string{documentFieldUID, documentFieldKind, documentFieldLocation}
+	permissionFilterFields                 = []string{documentFieldUID, documentFieldKind, documentFieldLocation, documentFieldOwner}
 	panelIdFieldRegex                      = regexp.MustCompile(`^(.*)#([0-9]{1,4})$`)
 	panelIdFieldDashboardUidSubmatchIndex  = 1
 	panelIdFieldPanelIdSubmatchIndex       = 2
@@ -66,7 +73,7 @@ func (q *PermissionFilter) logAccessDecision(decision bool, kind any, id string,
 	}
 }
 
-func (q *PermissionFilter) canAccess(kind entityKind, id, location string) bool {
+func (q *PermissionFilter) canAccess(kind entityKind, id, location, owner string) bool {
 	if !kind.supportsAuthzCheck() {
 		q.logAccessDecision(false, kind, id, "entityDoesNotSupportAuthz")
 		return false
@@ -98,6 +105,15 @@ func (q *PermissionFilter) canAccess(kind entityKind, id, location string) bool
 		decision := q.filter(entityKindDashboard, dashboardUid, folderUid)
 		q.logAccessDecision(decision, kind, id, "resourceFilter", "folderUid", folderUid, "dashboardUid", dashboardUid, "panelId", matches[panelIdFieldPanelIdSubmatchIndex])
 		return decision
+	case entityKindAlertRule, entityKindLibraryPanel, entityKindDatasource:
+		decision := q.filter(kind, entityUID(kind, id), location)
+		q.logAccessDecision(decision, kind, id, "resourceFilter", "location", location)
+		return decision
+	case entityKindQuery:
+		// Saved queries are only visible to the user who saved them
+		decision := q.filter(kind, entityUID(kind, id), owner)
+		q.logAccessDecision(decision, kind, id, "resourceFilter", "owner", owner)
+		return decision
 	default:
 		q.logAccessDecision(false, kind, id, "reason", "unknownKind")
 		return false
@@ -115,7 +131,7 @@ func (q *PermissionFilter) Searcher(i search.Reader, options search.SearcherOpti
 		return nil, err
 	}
 	return searcher.NewFilteringSearcher(s, func(d *search.DocumentMatch) bool {
-		var kind, id, location string
+		var kind, id, location, owner string
 		err := dvReader.VisitDocumentValues(d.Number, func(field string, term []byte) {
 			if field == documentFieldKind {
 				kind = string(term)
@@ -123,6 +139,8 @@ func (q *PermissionFilter) Searcher(i search.Reader, options search.SearcherOpti
 				id = string(term)
 			} else if field == documentFieldLocation {
 				location = string(term)
+			} else if field == documentFieldOwner {
+				owner = string(term)
 			}
 		})
 		if err != nil {
@@ -136,6 +154,6 @@ func (q *PermissionFilter) Searcher(i search.Reader, options search.SearcherOpti
 			return false
 		}
 
-		return q.canAccess(e, id, location)
+		return q.canAccess(e, id, location, owner)
 	}), err
 }
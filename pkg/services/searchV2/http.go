@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/middleware"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 type SearchHTTPService interface {
@@ -31,6 +32,7 @@ func ProvideSearchHTTPService(search SearchService) SearchHTTPService {
 
 func (s *searchHTTPService) RegisterHTTPRoutes(storageRoute routing.RouteRegister) {
 	storageRoute.Post("/", middleware.ReqSignedIn, routing.Wrap(s.doQuery))
+	storageRoute.Get("/datasources/:uid/usage", middleware.ReqSignedIn, routing.Wrap(s.getDatasourceUsage))
 }
 
 func (s *searchHTTPService) doQuery(c *contextmodel.ReqContext) response.Response {
@@ -77,3 +79,26 @@ func (s *searchHTTPService) doQuery(c *contextmodel.ReqContext) response.Respons
 
 	return response.JSON(http.StatusOK, bytes)
 }
+
+// getDatasourceUsage returns the dashboards, panels, alert rules, library panels
+// and saved queries that use a data source and the signed in user can see.
+func (s *searchHTTPService) getDatasourceUsage(c *contextmodel.ReqContext) response.Response {
+	searchReadinessCheckResp := s.search.IsReady(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if !searchReadinessCheckResp.IsReady {
+		dashboardSearchNotServedRequestsCounter.With(prometheus.Labels{
+			"reason": searchReadinessCheckResp.Reason,
+		}).Inc()
+
+		msg := "search index is not ready"
+		return response.Error(http.StatusServiceUnavailable, msg, errors.New(msg))
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	resp := s.search.doDashboardQuery(c.Req.Context(), c.SignedInUser, c.SignedInUser.GetOrgID(), newDatasourceUsageQuery(uid))
+	usage, err := newDatasourceUsage(uid, resp)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "error handling datasource usage request", err)
+	}
+
+	return response.JSON(http.StatusOK, usage)
+}
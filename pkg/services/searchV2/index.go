@@ -91,6 +91,7 @@ func (i *orgIndex) readerForIndex(idxType indexType) (*bluge.Reader, func(), err
 type searchIndex struct {
 	mu                      sync.RWMutex
 	loader                  dashboardLoader
+	entityLoader            entityLoader
 	perOrgIndex             map[int64]*orgIndex
 	initializedOrgs         map[int64]bool
 	initialIndexingComplete bool
@@ -106,9 +107,10 @@ type searchIndex struct {
 	settings                setting.SearchSettings
 }
 
-func newSearchIndex(dashLoader dashboardLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
+func newSearchIndex(dashLoader dashboardLoader, entLoader entityLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
 	return &searchIndex{
 		loader:          dashLoader,
+		entityLoader:    entLoader,
 		eventStore:      evStore,
 		perOrgIndex:     map[int64]*orgIndex{},
 		initializedOrgs: map[int64]bool{},
@@ -489,14 +491,26 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 	}
 	i.logger.Info("Finish loading org dashboards", "elapsed", orgSearchIndexLoadTime, "orgId", orgID)
 
+	var entities []indexedEntity
+	for _, kind := range indexedEntityKinds {
+		kindEntities, err := i.entityLoader.LoadEntities(ctx, orgID, kind, "")
+		if err != nil {
+			return 0, fmt.Errorf("error loading %s entities: %w", kind, err)
+		}
+		entities = append(entities, kindEntities...)
+	}
+	orgSearchIndexLoadTime = time.Since(started)
+	i.logger.Info("Finish loading org entities", "elapsed", orgSearchIndexLoadTime, "orgId", orgID, "entityCount", len(entities))
+
 	dashboardExtender := i.extender.GetDashboardExtender(orgID)
 
 	_, initOrgIndexSpan := i.tracer.Start(ctx, "searchV2 buildOrgIndex init org index", trace.WithAttributes(
 		attribute.Int64("org_id", orgID),
 		attribute.Int("dashboardCount", len(dashboards)),
+		attribute.Int("entityCount", len(entities)),
 	))
 
-	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender)
+	index, err := initOrgIndex(dashboards, entities, i.logger, dashboardExtender)
 
 	initOrgIndexSpan.End()
 
@@ -511,7 +525,8 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 			"orgSearchIndexLoadTime", orgSearchIndexLoadTime,
 			"orgSearchIndexBuildTime", orgSearchIndexBuildTime,
 			"orgSearchIndexTotalTime", orgSearchIndexTotalTime,
-			"orgSearchDashboardCount", len(dashboards))...)
+			"orgSearchDashboardCount", len(dashboards),
+			"orgSearchEntityCount", len(entities))...)
 
 	i.mu.Lock()
 	if oldIndex, ok := i.perOrgIndex[orgID]; ok {
@@ -654,6 +669,10 @@ func (i *searchIndex) applyEvent(ctx context.Context, orgID int64, kind store.En
 	}
 	i.mu.Unlock()
 
+	if entKind, ok := entityKindByEntityType[kind]; ok {
+		return i.applyEntityEvent(ctx, orgID, entKind, uid)
+	}
+
 	// Both dashboard and folder share same DB table.
 	dbDashboards, err := i.loader.LoadDashboards(ctx, orgID, uid)
 	if err != nil {
@@ -688,6 +707,24 @@ func (i *searchIndex) applyEvent(ctx context.Context, orgID int64, kind store.En
 	return nil
 }
 
+func (i *searchIndex) applyEntityEvent(ctx context.Context, orgID int64, kind entityKind, uid string) error {
+	entities, err := i.entityLoader.LoadEntities(ctx, orgID, kind, uid)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	index, ok := i.perOrgIndex[orgID]
+	if !ok {
+		// Skip event for org not yet fully indexed.
+		return nil
+	}
+	// No entity means it was deleted.
+	return i.updateEntity(index, kind, uid, entities)
+}
+
 func (i *searchIndex) removeDashboard(_ context.Context, index *orgIndex, dashboardUID string) error {
 	dashboardLocation, ok, err := getDashboardLocation(index, dashboardUID)
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/store/entity"
+	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -28,6 +29,20 @@ func (t *testDashboardLoader) LoadDashboards(_ context.Context, _ int64, _ strin
 	return t.dashboards, nil
 }
 
+type testEntityLoader struct {
+	entities []indexedEntity
+}
+
+func (t *testEntityLoader) LoadEntities(_ context.Context, _ int64, kind entityKind, uid string) ([]indexedEntity, error) {
+	var entities []indexedEntity
+	for _, e := range t.entities {
+		if e.kind == kind && (uid == "" || e.uid == uid) {
+			entities = append(entities, e)
+		}
+	}
+	return entities, nil
+}
+
 var testLogger = log.New("index-test-logger")
 
 var testAllowAllFilter = func(kind entityKind, uid, parent string) bool {
@@ -58,11 +73,16 @@ func initTestIndexFromDashes(t *testing.T, dashboards []dashboard) *searchIndex
 }
 
 func initTestIndexFromDashesExtended(t *testing.T, dashboards []dashboard, extender DocumentExtender) *searchIndex {
+	t.Helper()
+	return initTestIndex(t, dashboards, &testEntityLoader{}, extender)
+}
+
+func initTestIndex(t *testing.T, dashboards []dashboard, entityLoader *testEntityLoader, extender DocumentExtender) *searchIndex {
 	t.Helper()
 	dashboardLoader := &testDashboardLoader{
 		dashboards: dashboards,
 	}
-	index := newSearchIndex(dashboardLoader, &store.MockEntityEventsService{}, extender, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
+	index := newSearchIndex(dashboardLoader, entityLoader, &store.MockEntityEventsService{}, extender, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
 	require.NotNil(t, index)
 	numDashboards, err := index.buildOrgIndex(context.Background(), testOrgID)
 	require.NoError(t, err)
@@ -731,3 +751,208 @@ func TestDashboardIndex_MultiTermPrefixMatch(t *testing.T) {
 		})
 	}
 }
+
+var testEntities = []indexedEntity{
+	{kind: entityKindDatasource, uid: "1", name: "Prometheus", dsUIDs: []string{"1"}, dsType: "prometheus"},
+	{kind: entityKindDatasource, uid: "2", name: "Loki", dsUIDs: []string{"2"}, dsType: "loki"},
+	{kind: entityKindAlertRule, uid: "rule", name: "Prometheus down", location: "ops", tags: []string{"severity"}, dsUIDs: []string{"1"}},
+	{kind: entityKindLibraryPanel, uid: "lib", name: "Request rate", location: "general", panelType: "timeseries"},
+	{kind: entityKindQuery, uid: "query", name: "rate(http_requests_total[5m])", owner: "1", dsUIDs: []string{"1"}},
+	{kind: entityKindQuery, uid: "other-query", name: "rate(http_errors_total[5m])", owner: "2", dsUIDs: []string{"1"}},
+}
+
+func getResultUIDs(t *testing.T, resp *backend.DataResponse) []string {
+	t.Helper()
+	require.NoError(t, resp.Error)
+	uidField, idx := resp.Frames[0].FieldByName("uid")
+	require.NotEqual(t, -1, idx)
+	uids := make([]string, 0, uidField.Len())
+	for i := 0; i < uidField.Len(); i++ {
+		uids = append(uids, uidField.At(i).(string))
+	}
+	return uids
+}
+
+func TestEntityIndex(t *testing.T) {
+	t.Run("entities-kind-filter", func(t *testing.T) {
+		index := initTestIndex(t, testDashboards, &testEntityLoader{entities: testEntities}, &NoopDocumentExtender{})
+		orgIdx := index.perOrgIndex[testOrgID]
+
+		resp := doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			DashboardQuery{Kind: []string{string(entityKindDatasource)}, Sort: documentFieldName_sort},
+			&NoopQueryExtender{}, "")
+		// the data source uid does not conflict with the dashboard with the same uid
+		require.Equal(t, []string{"2", "1"}, getResultUIDs(t, resp))
+
+		resp = doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			DashboardQuery{Kind: []string{string(entityKindAlertRule), string(entityKindLibraryPanel)}, Sort: documentFieldName_sort},
+			&NoopQueryExtender{}, "")
+		require.Equal(t, []string{"rule", "lib"}, getResultUIDs(t, resp))
+	})
+
+	t.Run("entities-ngram", func(t *testing.T) {
+		index := initTestIndex(t, testDashboards, &testEntityLoader{entities: testEntities}, &NoopDocumentExtender{})
+		orgIdx := index.perOrgIndex[testOrgID]
+
+		resp := doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			DashboardQuery{Query: "prom", Sort: documentFieldName_sort},
+			&NoopQueryExtender{}, "")
+		require.Equal(t, []string{"1", "rule"}, getResultUIDs(t, resp))
+	})
+
+	t.Run("entities-permission-filter", func(t *testing.T) {
+		index := initTestIndex(t, testDashboards, &testEntityLoader{entities: testEntities}, &NoopDocumentExtender{})
+		orgIdx := index.perOrgIndex[testOrgID]
+
+		var checked []string
+		filter := func(kind entityKind, uid, parent string) bool {
+			checked = append(checked, string(kind)+":"+uid+":"+parent)
+			switch kind {
+			case entityKindQuery:
+				return parent == "1"
+			case entityKindAlertRule:
+				return parent == "general"
+			default:
+				return true
+			}
+		}
+		resp := doSearchQuery(context.Background(), testLogger, orgIdx, filter,
+			DashboardQuery{Kind: []string{string(entityKindAlertRule), string(entityKindQuery)}},
+			&NoopQueryExtender{}, "")
+		require.Equal(t, []string{"query"}, getResultUIDs(t, resp))
+		require.Contains(t, checked, "alertrule:rule:ops")
+		require.Contains(t, checked, "query:other-query:2")
+	})
+
+	t.Run("entities-updated-by-events", func(t *testing.T) {
+		loader := &testEntityLoader{entities: testEntities[:2]}
+		index := initTestIndex(t, testDashboards, loader, &NoopDocumentExtender{})
+		orgIdx := index.perOrgIndex[testOrgID]
+		query := DashboardQuery{Kind: []string{string(entityKindDatasource)}, Sort: documentFieldName_sort}
+
+		loader.entities = []indexedEntity{
+			{kind: entityKindDatasource, uid: "2", name: "Loki logs"},
+			{kind: entityKindDatasource, uid: "3", name: "Tempo"},
+		}
+		err := index.applyEvent(context.Background(), testOrgID, store.EntityTypeDataSource, "1", store.EntityEventTypeDelete)
+		require.NoError(t, err)
+		err = index.applyEvent(context.Background(), testOrgID, store.EntityTypeDataSource, "2", store.EntityEventTypeUpdate)
+		require.NoError(t, err)
+		err = index.applyEvent(context.Background(), testOrgID, store.EntityTypeDataSource, "3", store.EntityEventTypeCreate)
+		require.NoError(t, err)
+
+		resp := doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter, query, &NoopQueryExtender{}, "")
+		require.Equal(t, []string{"2", "3"}, getResultUIDs(t, resp))
+
+		resp = doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			DashboardQuery{Query: "logs"}, &NoopQueryExtender{}, "")
+		require.Equal(t, []string{"2"}, getResultUIDs(t, resp))
+	})
+}
+
+func TestSavedQuery(t *testing.T) {
+	queries := []byte(`[{"refId":"A","expr":"up"}]`)
+
+	require.Equal(t, "my query", savedQueryName("my query", queries))
+	require.Equal(t, "up", savedQueryName("", queries))
+	require.Equal(t, "", savedQueryName("", []byte(`[{"refId":"A"}]`)))
+
+	require.Equal(t, "/explore?left=%7B%22datasource%22%3A%22prom%22%2C%22queries%22%3A%5B%7B%22refId%22%3A%22A%22%2C%22expr%22%3A%22up%22%7D%5D%7D", savedQueryURL("prom", queries))
+	require.Equal(t, "/explore", savedQueryURL("prom", []byte("not json")))
+}
+
+func newNestedQueryPanel(id, dashId int64, name string, dsUID string, metric string, query string) *entity.EntitySummary {
+	summary := newNestedPanel(id, dashId, name)
+	summary.Fields = map[string]string{
+		"type":                  "timeseries",
+		kdash.PanelFieldQueries: fmt.Sprintf("[%q]", query),
+	}
+	summary.References = []*entity.EntityExternalReference{
+		{Family: entity.StandardKindDataSource, Identifier: dsUID},
+	}
+	if metric != "" {
+		summary.References = append(summary.References, &entity.EntityExternalReference{Family: entity.ExternalEntityReferenceMetric, Identifier: metric})
+	}
+	return summary
+}
+
+var dashboardsWithQueries = []dashboard{
+	{
+		id:  1,
+		uid: "1",
+		summary: &entity.EntitySummary{
+			Name: "Node exporter",
+			References: []*entity.EntityExternalReference{
+				{Family: entity.StandardKindDataSource, Identifier: "prom"},
+				{Family: entity.ExternalEntityReferenceMetric, Identifier: "node_cpu_seconds_total"},
+			},
+			Nested: []*entity.EntitySummary{
+				newNestedQueryPanel(1, 1, "CPU", "prom", "node_cpu_seconds_total", "sum by (mode) (rate(node_cpu_seconds_total[5m]))"),
+			},
+		},
+	},
+	{
+		id:  2,
+		uid: "2",
+		summary: &entity.EntitySummary{
+			Name: "Orders",
+			References: []*entity.EntityExternalReference{
+				{Family: entity.StandardKindDataSource, Identifier: "mysql"},
+			},
+			Nested: []*entity.EntitySummary{
+				newNestedQueryPanel(1, 2, "Orders per day", "mysql", "", "SELECT count(*) FROM orders GROUP BY day"),
+			},
+		},
+	},
+}
+
+var queryEntities = []indexedEntity{
+	{kind: entityKindDatasource, uid: "prom", name: "Prometheus", dsUIDs: []string{"prom"}, dsType: "prometheus"},
+	{kind: entityKindAlertRule, uid: "rule", name: "High CPU", location: "ops", dsUIDs: []string{"prom"},
+		queries: []string{"rate(node_cpu_seconds_total[5m]) > 0.9"}, metrics: []string{"node_cpu_seconds_total"}},
+	{kind: entityKindLibraryPanel, uid: "lib", name: "Order count", location: "general", dsUIDs: []string{"mysql"},
+		queries: []string{"SELECT count(*) FROM orders"}},
+}
+
+func TestDashboardIndex_Queries(t *testing.T) {
+	index := initTestIndex(t, dashboardsWithQueries, &testEntityLoader{entities: queryEntities}, &NoopDocumentExtender{})
+	orgIdx := index.perOrgIndex[testOrgID]
+
+	t.Run("queries-text", func(t *testing.T) {
+		resp := doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			DashboardQuery{QueryText: "orders count", Sort: documentFieldName_sort},
+			&NoopQueryExtender{}, "")
+		require.Equal(t, []string{"lib", "2", "2#1"}, getResultUIDs(t, resp))
+
+		resp = doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			DashboardQuery{QueryText: "orders users"},
+			&NoopQueryExtender{}, "")
+		require.Empty(t, getResultUIDs(t, resp))
+	})
+
+	t.Run("queries-metric", func(t *testing.T) {
+		resp := doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			DashboardQuery{Metric: "node_cpu_seconds_total", Sort: documentFieldName_sort},
+			&NoopQueryExtender{}, "")
+		require.Equal(t, []string{"1#1", "rule", "1"}, getResultUIDs(t, resp))
+
+		resp = doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			DashboardQuery{Metric: "node_cpu_seconds_total", Kind: []string{string(entityKindDashboard)}},
+			&NoopQueryExtender{}, "")
+		require.Equal(t, []string{"1"}, getResultUIDs(t, resp))
+	})
+
+	t.Run("queries-datasource-usage", func(t *testing.T) {
+		resp := doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+			newDatasourceUsageQuery("prom"), &NoopQueryExtender{}, "")
+		usage, err := newDatasourceUsage("prom", resp)
+		require.NoError(t, err)
+
+		require.Equal(t, []DatasourceUsageItem{{UID: "1", Name: "Node exporter", URL: "/d/1/", Location: "general"}}, usage.Dashboards)
+		require.Equal(t, []DatasourceUsageItem{{UID: "1#1", Name: "CPU", URL: "/d/1/node-exporter?viewPanel=1", Location: "general/1"}}, usage.Panels)
+		require.Equal(t, []DatasourceUsageItem{{UID: "rule", Name: "High CPU", URL: "/alerting/grafana/rule/view", Location: "ops"}}, usage.AlertRules)
+		require.Empty(t, usage.LibraryPanels)
+		require.Empty(t, usage.Queries)
+		require.False(t, usage.Truncated)
+	})
+}
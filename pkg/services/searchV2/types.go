@@ -23,6 +23,8 @@ type DashboardQuery struct {
 	Tags               []string     `json:"tags,omitempty"`
 	Kind               []string     `json:"kind,omitempty"`
 	PanelType          string       `json:"panel_type,omitempty"`
+	QueryText          string       `json:"queryText,omitempty"` // text in the queries of panels, alert rules and saved queries
+	Metric             string       `json:"metric,omitempty"`    // metric selected by the queries
 	UIDs               []string     `json:"uid,omitempty"`
 	Explain            bool         `json:"explain,omitempty"`            // adds details on why document matched
 	WithAllowedActions bool         `json:"withAllowedActions,omitempty"` // adds allowed actions per entity
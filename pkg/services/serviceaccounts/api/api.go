@@ -14,6 +14,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -28,6 +29,7 @@ type ServiceAccountsAPI struct {
 	RouterRegister       routing.RouteRegister
 	log                  log.Logger
 	permissionService    accesscontrol.ServiceAccountPermissionsService
+	provenanceService    provenance.Service
 	isExternalSAEnabled  bool
 }
 
@@ -38,6 +40,7 @@ func NewServiceAccountsAPI(
 	accesscontrolService accesscontrol.Service,
 	routerRegister routing.RouteRegister,
 	permissionService accesscontrol.ServiceAccountPermissionsService,
+	provenanceService provenance.Service,
 	features *featuremgmt.FeatureManager,
 ) *ServiceAccountsAPI {
 	return &ServiceAccountsAPI{
@@ -48,6 +51,7 @@ func NewServiceAccountsAPI(
 		RouterRegister:       routerRegister,
 		log:                  log.New("serviceaccounts.api"),
 		permissionService:    permissionService,
+		provenanceService:    provenanceService,
 		isExternalSAEnabled:  features.IsEnabledGlobally(featuremgmt.FlagExternalServiceAccounts),
 	}
 }
@@ -191,6 +195,10 @@ func (api *ServiceAccountsAPI) UpdateServiceAccount(c *contextmodel.ReqContext)
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update service account", err)
 	}
 
+	if err := api.ensureNotProvisioned(c, scopeID); err != nil {
+		return response.Err(err)
+	}
+
 	resp, err := api.service.UpdateServiceAccount(c.Req.Context(), c.SignedInUser.GetOrgID(), scopeID, &cmd)
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "Failed update service account", err)
@@ -209,6 +217,12 @@ func (api *ServiceAccountsAPI) UpdateServiceAccount(c *contextmodel.ReqContext)
 	})
 }
 
+// ensureNotProvisioned prevents changes to the service accounts created from provisioning files.
+func (api *ServiceAccountsAPI) ensureNotProvisioned(c *contextmodel.ReqContext, serviceAccountID int64) error {
+	return provenance.EnsureNotProvisioned(c.Req.Context(), api.provenanceService, c.SignedInUser.GetOrgID(),
+		provenance.KindServiceAccount, strconv.FormatInt(serviceAccountID, 10))
+}
+
 func (api *ServiceAccountsAPI) validateRole(r *org.RoleType, orgRole org.RoleType) error {
 	if r != nil && !r.IsValid() {
 		return serviceaccounts.ErrServiceAccountInvalidRole.Errorf("invalid role specified")
@@ -237,6 +251,9 @@ func (api *ServiceAccountsAPI) DeleteServiceAccount(ctx *contextmodel.ReqContext
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "Service account ID is invalid", err)
 	}
+	if err := api.ensureNotProvisioned(ctx, scopeID); err != nil {
+		return response.Err(err)
+	}
 	err = api.service.DeleteServiceAccount(ctx.Req.Context(), ctx.SignedInUser.GetOrgID(), scopeID)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Service account deletion error", err)
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -16,6 +18,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	satests "github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -100,6 +104,7 @@ func TestServiceAccountsAPI_DeleteServiceAccount(t *testing.T) {
 	type TestCase struct {
 		desc         string
 		id           int64
+		provisioned  bool
 		permissions  []accesscontrol.Permission
 		expectedCode int
 	}
@@ -117,11 +122,24 @@ func TestServiceAccountsAPI_DeleteServiceAccount(t *testing.T) {
 			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionDelete, Scope: "serviceaccounts:id:1"}},
 			expectedCode: http.StatusForbidden,
 		},
+		{
+			desc:         "should not be able to delete a provisioned service account",
+			id:           1,
+			provisioned:  true,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionDelete, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			server := setupTests(t)
+			provenanceService := provenancetest.NewFakeService()
+			if tt.provisioned {
+				require.NoError(t, provenanceService.SetProvisioned(context.Background(), 1, provenance.KindServiceAccount, strconv.FormatInt(tt.id, 10)))
+			}
+			server := setupTests(t, func(a *ServiceAccountsAPI) {
+				a.provenanceService = provenanceService
+			})
 			req := server.NewRequest(http.MethodDelete, fmt.Sprintf("/api/serviceaccounts/%d", tt.id), nil)
 			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(tt.permissions)}})
 			res, err := server.Send(req)
@@ -308,6 +326,7 @@ func setupTests(t *testing.T, opts ...func(a *ServiceAccountsAPI)) *webtest.Serv
 		RouterRegister:       routing.NewRouteRegister(),
 		log:                  log.NewNopLogger(),
 		permissionService:    &actest.FakePermissionsService{},
+		provenanceService:    provenancetest.NewFakeService(),
 	}
 
 	for _, o := range opts {
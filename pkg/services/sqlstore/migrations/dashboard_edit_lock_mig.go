@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDashboardEditLockMigrations(mg *Migrator) {
+	dashboardEditLockV1 := Table{
+		Name: "dashboard_edit_lock",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "acquired", Type: DB_DateTime, Nullable: false},
+			{Name: "expires", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard_edit_lock table v1", NewAddTableMigration(dashboardEditLockV1))
+	mg.AddMigration("add unique index dashboard_edit_lock.org_id-dashboard_uid", NewAddIndexMigration(dashboardEditLockV1, dashboardEditLockV1.Indices[0]))
+}
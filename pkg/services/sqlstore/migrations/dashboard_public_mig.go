@@ -96,4 +96,48 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add expires_at column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name: "expires_at", Type: DB_DateTime, Nullable: true,
+	}))
+
+	mg.AddMigration("add allowed_networks column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name: "allowed_networks", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add passphrase_hash column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name: "passphrase_hash", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+
+	mg.AddMigration("add passphrase_salt column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name: "passphrase_salt", Type: DB_NVarchar, Length: 50, Nullable: true,
+	}))
+
+	accessLogV1 := Table{
+		Name: "dashboard_public_access_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "action", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "result", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "remote_addr", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "user_agent", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"public_dashboard_uid", "created_at"}},
+			{Cols: []string{"created_at"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public access log table", NewAddTableMigration(accessLogV1))
+	addTableIndicesMigrations(mg, "v1", accessLogV1)
+
+	mg.AddMigration("add template_variables_enabled column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "template_variables_enabled",
+		Type:     DB_Bool,
+		Nullable: false,
+		Default:  "0",
+	}))
 }
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDashboardSnapshotRefreshMigrations(mg *Migrator) {
+	snapshotRefreshV1 := Table{
+		Name: "dashboard_snapshot_refresh",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "snapshot_key", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "schedule", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "next_run", Type: DB_DateTime, Nullable: false},
+			{Name: "last_run", Type: DB_DateTime, Nullable: true},
+			{Name: "last_error", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"snapshot_key"}, Type: UniqueIndex},
+			{Cols: []string{"next_run"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_snapshot_refresh table v1", NewAddTableMigration(snapshotRefreshV1))
+	mg.AddMigration("add unique index dashboard_snapshot_refresh.snapshot_key", NewAddIndexMigration(snapshotRefreshV1, snapshotRefreshV1.Indices[0]))
+	mg.AddMigration("add index dashboard_snapshot_refresh.next_run", NewAddIndexMigration(snapshotRefreshV1, snapshotRefreshV1.Indices[1]))
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDeletedDashboardMigrations(mg *Migrator) {
+	deletedDashboardV1 := Table{
+		Name: "deleted_dashboard",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: DB_BigInt, Nullable: false},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 189, Nullable: false},
+			{Name: "is_folder", Type: DB_Bool, Nullable: false},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: true},
+			{Name: "data", Type: DB_LongText, Nullable: false},
+			{Name: "deleted", Type: DB_DateTime, Nullable: false},
+			{Name: "deleted_by", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "folder_uid"}},
+			{Cols: []string{"deleted"}},
+		},
+	}
+
+	mg.AddMigration("create deleted_dashboard table v1", NewAddTableMigration(deletedDashboardV1))
+	mg.AddMigration("add unique index deleted_dashboard.org_id-uid", NewAddIndexMigration(deletedDashboardV1, deletedDashboardV1.Indices[0]))
+	mg.AddMigration("add index deleted_dashboard.org_id-folder_uid", NewAddIndexMigration(deletedDashboardV1, deletedDashboardV1.Indices[1]))
+	mg.AddMigration("add index deleted_dashboard.deleted", NewAddIndexMigration(deletedDashboardV1, deletedDashboardV1.Indices[2]))
+}
@@ -62,3 +62,37 @@ func addLibraryElementsMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("alter library_element model to mediumtext", migrator.NewRawSQLMigration("").
 		Mysql("ALTER TABLE library_element MODIFY model MEDIUMTEXT NOT NULL;"))
 }
+
+// addLibraryElementVersionMigrations adds the version history of library elements.
+func addLibraryElementVersionMigrations(mg *migrator.Migrator) {
+	libraryElementVersionV1 := migrator.Table{
+		Name: model.LibraryElementVersionTableName,
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "element_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "folder_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: 150, Nullable: false},
+			{Name: "type", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "description", Type: migrator.DB_NVarchar, Length: 2048, Nullable: false},
+			{Name: "model", Type: migrator.DB_Text, Nullable: false},
+			{Name: "message", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "created_by", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"element_id", "version"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create "+model.LibraryElementVersionTableName+" table v1", migrator.NewAddTableMigration(libraryElementVersionV1))
+	mg.AddMigration("add index "+model.LibraryElementVersionTableName+" element_id-version", migrator.NewAddIndexMigration(libraryElementVersionV1, libraryElementVersionV1.Indices[0]))
+	mg.AddMigration("alter "+model.LibraryElementVersionTableName+" model to mediumtext", migrator.NewRawSQLMigration("").
+		Mysql("ALTER TABLE "+model.LibraryElementVersionTableName+" MODIFY model MEDIUMTEXT NOT NULL;"))
+
+	// the current state of existing library elements is their first version in the history
+	mg.AddMigration("copy library elements to "+model.LibraryElementVersionTableName, migrator.NewRawSQLMigration(
+		"INSERT INTO "+model.LibraryElementVersionTableName+" (org_id, element_id, version, folder_id, name, type, description, model, message, created, created_by)"+
+			" SELECT org_id, id, version, folder_id, name, type, description, model, '', updated, updated_by FROM library_element"))
+}
@@ -117,6 +117,25 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	ualert.AddRuleNotificationSettingsColumns(mg)
 
 	accesscontrol.AddAlertingScopeRemovalMigration(mg)
+
+	addLivePipelineMigrations(mg)
+
+	addDeletedDashboardMigrations(mg)
+
+	addDashboardEditLockMigrations(mg)
+
+	addDashboardSnapshotRefreshMigrations(mg)
+
+	addReportMigrations(mg)
+
+	addPlaylistItemOverridesMigration(mg)
+
+	addLibraryElementVersionMigrations(mg)
+
+	addProvisionedResourceMigrations(mg)
+
+	addTeamGroupMigrations(mg)
+	addTwoFactorMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
@@ -60,6 +60,38 @@ func addPlaylistUIDMigration(mg *Migrator) {
 	}))
 }
 
+func addPlaylistItemOverridesMigration(mg *Migrator) {
+	playlistItem := Table{Name: "playlist_item"}
+	mg.AddMigration("Add playlist_item column duration", NewAddColumnMigration(playlistItem, &Column{
+		Name: "duration", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column time_from", NewAddColumnMigration(playlistItem, &Column{
+		Name: "time_from", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column time_to", NewAddColumnMigration(playlistItem, &Column{
+		Name: "time_to", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist_item column variables", NewAddColumnMigration(playlistItem, &Column{
+		Name: "variables", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("Add playlist column kiosk_token", NewAddColumnMigration(playlistV2(), &Column{
+		Name: "kiosk_token", Type: DB_NVarchar, Length: 64, Nullable: true,
+	}))
+	mg.AddMigration("Add index for kiosk_token in playlist", NewAddIndexMigration(playlistV2(), &Index{
+		Cols: []string{"kiosk_token"},
+	}))
+	mg.AddMigration("Add playlist column kiosk_dashboard_uids", NewAddColumnMigration(playlistV2(), &Column{
+		Name: "kiosk_dashboard_uids", Type: DB_Text, Nullable: true,
+	}))
+	mg.AddMigration("Add playlist column kiosk_datasource_uids", NewAddColumnMigration(playlistV2(), &Column{
+		Name: "kiosk_datasource_uids", Type: DB_Text, Nullable: true,
+	}))
+	// kiosk tokens created before their grants were stored would grant nothing
+	mg.AddMigration("Revoke playlist kiosk tokens without grants", NewRawSQLMigration(
+		"UPDATE playlist SET kiosk_token = NULL WHERE kiosk_dashboard_uids IS NULL"))
+}
+
 func playlistV2() Table {
 	return Table{
 		Name: "playlist",
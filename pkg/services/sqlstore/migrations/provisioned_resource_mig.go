@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addProvisionedResourceMigrations(mg *Migrator) {
+	provisionedResourceV1 := Table{
+		Name: "provisioned_resource",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "resource_key", Type: DB_NVarchar, Length: 190, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "kind", "resource_key"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create provisioned_resource table v1", NewAddTableMigration(provisionedResourceV1))
+	addTableIndicesMigrations(mg, "v1", provisionedResourceV1)
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addTwoFactorMigrations(mg *Migrator) {
+	userTwoFactorV1 := Table{
+		Name: "user_two_factor",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "secret", Type: DB_Text, Nullable: false},
+			{Name: "enabled", Type: DB_Bool, Nullable: false},
+			{Name: "last_used_step", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_two_factor table v1", NewAddTableMigration(userTwoFactorV1))
+	addTableIndicesMigrations(mg, "v1", userTwoFactorV1)
+
+	recoveryCodeV1 := Table{
+		Name: "user_two_factor_recovery_code",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "code_hash", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id", "code_hash"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_two_factor_recovery_code table v1", NewAddTableMigration(recoveryCodeV1))
+	addTableIndicesMigrations(mg, "v1", recoveryCodeV1)
+
+	orgPolicyV1 := Table{
+		Name: "org_two_factor_policy",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "enforced", Type: DB_Bool, Nullable: false},
+			{Name: "min_role", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create org_two_factor_policy table v1", NewAddTableMigration(orgPolicyV1))
+	addTableIndicesMigrations(mg, "v1", orgPolicyV1)
+}
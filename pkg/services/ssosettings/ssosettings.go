@@ -3,6 +3,7 @@ package ssosettings
 import (
 	"context"
 
+	"github.com/grafana/grafana/pkg/login/saml"
 	"github.com/grafana/grafana/pkg/login/social"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/ssosettings/models"
@@ -14,6 +15,9 @@ var (
 	ConfigurableOAuthProviders = []string{"github", "gitlab", "google", "generic_oauth", "azuread", "okta"}
 
 	AllOAuthProviders = []string{social.GitHubProviderName, social.GitlabProviderName, social.GoogleProviderName, social.GenericOAuthProviderName, social.GrafanaComProviderName, social.AzureADProviderName, social.OktaProviderName}
+
+	// AllProviders is the list of all providers that have SSO settings, OAuth providers and SAML
+	AllProviders = append(append([]string{}, AllOAuthProviders...), saml.ProviderName)
 )
 
 // Service is a SSO settings service
@@ -44,7 +44,8 @@ func ProvideService(cfg *setting.Cfg, sqlStore db.DB, ac ac.AccessControl,
 	secrets secrets.Service, usageStats usagestats.Service, registerer prometheus.Registerer) *Service {
 	strategies := []ssosettings.FallbackStrategy{
 		strategies.NewOAuthStrategy(cfg),
-		// register other strategies here, for example SAML
+		strategies.NewSAMLStrategy(cfg),
+		// register other strategies here
 	}
 
 	store := database.ProvideStore(sqlStore)
@@ -114,14 +115,14 @@ func (s *Service) GetForProviderWithRedactedSecrets(ctx context.Context, provide
 }
 
 func (s *Service) List(ctx context.Context) ([]*models.SSOSettings, error) {
-	result := make([]*models.SSOSettings, 0, len(ssosettings.AllOAuthProviders))
+	result := make([]*models.SSOSettings, 0, len(ssosettings.AllProviders))
 	storedSettings, err := s.store.List(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 
-	for _, provider := range ssosettings.AllOAuthProviders {
+	for _, provider := range ssosettings.AllProviders {
 		dbSettings := getSettingByProvider(provider, storedSettings)
 		if dbSettings != nil {
 			// Settings are coming from the database thus secrets are encrypted
@@ -353,6 +354,10 @@ func (s *Service) doReload(ctx context.Context) {
 
 	for provider, connector := range s.reloadables {
 		setting := getSettingByProvider(provider, settingsList)
+		if setting == nil {
+			s.logger.Warn("no SSO Settings found for provider, skipping reload", "provider", provider)
+			continue
+		}
 
 		err = connector.Reload(ctx, *setting)
 		if err != nil {
@@ -476,6 +481,11 @@ func overrideMaps(maps ...map[string]any) map[string]any {
 }
 
 func isSecret(fieldName string) bool {
+	// the SAML private key is stored next to its path, only the key itself is a secret
+	if strings.EqualFold(fieldName, "private_key") {
+		return true
+	}
+
 	secretFieldPatterns := []string{"secret"}
 
 	for _, v := range secretFieldPatterns {
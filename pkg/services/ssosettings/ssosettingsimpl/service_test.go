@@ -385,6 +385,9 @@ func TestService_List(t *testing.T) {
 					"grafana_com": {
 						"enabled": false,
 					},
+					"saml": {
+						"enabled": false,
+					},
 				}
 			},
 			want: []*models.SSOSettings{
@@ -434,6 +437,11 @@ func TestService_List(t *testing.T) {
 					Settings: map[string]any{"enabled": false},
 					Source:   models.System,
 				},
+				{
+					Provider: "saml",
+					Settings: map[string]any{"enabled": false},
+					Source:   models.System,
+				},
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,52 @@
+package strategies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSAMLStrategy_GetProviderConfig(t *testing.T) {
+	iniFile, err := ini.Load([]byte(`
+	[auth.saml]
+	enabled = true
+	single_logout = true
+	certificate_path = /etc/grafana/saml.crt
+	private_key_path = /etc/grafana/saml.key
+	signature_algorithm = rsa-sha512
+	idp_metadata_url = https://idp.example.com/metadata
+	assertion_attribute_groups = groups
+	role_values_editor = editor, developer
+	`))
+	require.NoError(t, err)
+
+	cfg := setting.NewCfg()
+	cfg.Raw = iniFile
+
+	strategy := NewSAMLStrategy(cfg)
+	require.True(t, strategy.IsMatch("saml"))
+	require.False(t, strategy.IsMatch("generic_oauth"))
+
+	result, err := strategy.GetProviderConfig(context.Background(), "saml")
+	require.NoError(t, err)
+
+	require.Equal(t, true, result["enabled"])
+	require.Equal(t, true, result["single_logout"])
+	require.Equal(t, true, result["allow_sign_up"])
+	require.Equal(t, "/etc/grafana/saml.crt", result["certificate_path"])
+	require.Equal(t, "rsa-sha512", result["signature_algorithm"])
+	require.Equal(t, "https://idp.example.com/metadata", result["idp_metadata_url"])
+	require.Equal(t, "groups", result["assertion_attribute_groups"])
+	require.Equal(t, "editor, developer", result["role_values_editor"])
+	// defaults
+	require.Equal(t, "SAML", result["name"])
+	require.Equal(t, "90s", result["max_issue_delay"])
+	require.Equal(t, "48h", result["metadata_valid_duration"])
+	require.Equal(t, "displayName", result["assertion_attribute_name"])
+	require.Equal(t, "mail", result["assertion_attribute_login"])
+	require.Equal(t, "urn:oasis:names:tc:SAML:2.0:nameid-format:transient", result["name_id_format"])
+}
@@ -46,6 +46,9 @@ const (
 	// ExternalEntityReferenceRuntime_Transformer is a "type" under runtime
 	// UIDs include: joinByField, organize, seriesToColumns, etc
 	ExternalEntityReferenceRuntime_Transformer = "transformer"
+
+	// ExternalEntityReferenceMetric: metric selected by the queries of a panel
+	ExternalEntityReferenceMetric = "metric"
 )
 
 // EntitySummaryBuilder will read an object, validate it, and return a summary, sanitized payload, or an error
@@ -27,6 +27,11 @@ const (
 	EntityTypeFolder    EntityType = "folder"
 	EntityTypeImage     EntityType = "image"
 	EntityTypeJSON      EntityType = "json"
+
+	EntityTypeAlertRule    EntityType = "alertrule"
+	EntityTypeLibraryPanel EntityType = "librarypanel"
+	EntityTypeDataSource   EntityType = "datasource"
+	EntityTypeQuery        EntityType = "query"
 )
 
 // CreateDatabaseEntityId creates entityId for entities stored in the existing SQL tables
@@ -42,6 +47,15 @@ func CreateDatabaseEntityId(internalId any, orgId int64, entityType EntityType)
 	return fmt.Sprintf("database/%d/%s/%s", orgId, entityType, internalIdAsString)
 }
 
+// NewDatabaseEntityEvent creates the event of a change to an entity stored in the existing SQL tables
+func NewDatabaseEntityEvent(internalId any, orgId int64, entityType EntityType, eventType EntityEventType) *EntityEvent {
+	return &EntityEvent{
+		EventType: eventType,
+		EntityId:  CreateDatabaseEntityId(internalId, orgId, entityType),
+		Created:   time.Now().Unix(),
+	}
+}
+
 type EntityEvent struct {
 	Id        int64
 	EventType EntityEventType
@@ -0,0 +1,231 @@
+package dashboard
+
+import (
+	"sort"
+	"strings"
+)
+
+// queryTextFields are the target fields holding the text of a query: PromQL and
+// LogQL expressions, SQL, InfluxQL, Flux and Graphite targets.
+var queryTextFields = map[string]bool{
+	"expr":   true,
+	"rawSql": true,
+	"query":  true,
+	"target": true,
+}
+
+// metricNameFields are the target fields holding a metric name, like in
+// CloudWatch and OpenTSDB queries.
+var metricNameFields = map[string]bool{
+	"metricName": true,
+	"metric":     true,
+}
+
+func isQueryField(field string) bool {
+	return queryTextFields[field] || metricNameFields[field]
+}
+
+// QueryInfo is the text of the queries of a panel or an alert rule, and the
+// names of the metrics they select.
+type QueryInfo struct {
+	Queries []string
+	Metrics []string
+}
+
+// ReadQueryInfo reads the query info of targets that are already decoded,
+// like the queries of alert rules and of the query history.
+func ReadQueryInfo(targets []map[string]any) QueryInfo {
+	info := QueryInfo{}
+	for _, target := range targets {
+		fields := make([]string, 0, len(target))
+		for field := range target {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			if value, ok := target[field].(string); ok {
+				info.add(field, value)
+			}
+		}
+	}
+	return info
+}
+
+func (q *QueryInfo) add(field string, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+
+	switch {
+	case queryTextFields[field]:
+		if !stringInSlice(value, q.Queries) {
+			q.Queries = append(q.Queries, value)
+		}
+		if field == "expr" {
+			for _, name := range MetricNames(value) {
+				q.addMetric(name)
+			}
+		}
+	case metricNameFields[field]:
+		q.addMetric(value)
+	}
+}
+
+func (q *QueryInfo) addMetric(name string) {
+	if !stringInSlice(name, q.Metrics) {
+		q.Metrics = append(q.Metrics, name)
+	}
+}
+
+// promQLKeywords are the identifiers of PromQL expressions that are not metric
+// names. Aggregation operators are included since they can be followed by a
+// `by` clause instead of their arguments, and LogQL parsers since Loki
+// expressions are stored in the same field.
+var promQLKeywords = map[string]bool{
+	"sum":          true,
+	"min":          true,
+	"max":          true,
+	"avg":          true,
+	"group":        true,
+	"stddev":       true,
+	"stdvar":       true,
+	"count":        true,
+	"count_values": true,
+	"bottomk":      true,
+	"topk":         true,
+	"quantile":     true,
+	"by":           true,
+	"without":      true,
+	"on":           true,
+	"ignoring":     true,
+	"group_left":   true,
+	"group_right":  true,
+	"bool":         true,
+	"offset":       true,
+	"and":          true,
+	"or":           true,
+	"unless":       true,
+	"inf":          true,
+	"nan":          true,
+	"json":         true,
+	"logfmt":       true,
+	"unpack":       true,
+	"decolorize":   true,
+}
+
+// promQLLabelKeywords are followed by a list of label names, e.g. `sum by (job)`.
+var promQLLabelKeywords = map[string]bool{
+	"by":          true,
+	"without":     true,
+	"on":          true,
+	"ignoring":    true,
+	"group_left":  true,
+	"group_right": true,
+}
+
+// MetricNames returns the names of the metrics selected by a PromQL expression.
+// It is a best effort scan that skips strings, label matchers, ranges, function
+// calls, keywords and template variables, it does not validate the expression.
+func MetricNames(expr string) []string {
+	names := make([]string, 0)
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			i = skipQuoted(expr, i)
+		case c == '{':
+			i = skipUntil(expr, i+1, '}')
+		case c == '[':
+			i = skipUntil(expr, i+1, ']')
+		case c == '$':
+			// template variables: $var, ${var} and $__interval
+			i++
+			if i < len(expr) && expr[i] == '{' {
+				i = skipUntil(expr, i+1, '}')
+			}
+			for i < len(expr) && isMetricNameChar(expr[i]) {
+				i++
+			}
+		case c >= '0' && c <= '9':
+			// numbers and durations: 5, 1e3, 0x1f, 5m
+			for i < len(expr) && (isMetricNameChar(expr[i]) || expr[i] == '.') {
+				i++
+			}
+		case isMetricNameStart(c):
+			start := i
+			for i < len(expr) && isMetricNameChar(expr[i]) {
+				i++
+			}
+			name := expr[start:i]
+
+			next := i
+			for next < len(expr) && (expr[next] == ' ' || expr[next] == '\t' || expr[next] == '\n') {
+				next++
+			}
+			isCall := next < len(expr) && expr[next] == '('
+
+			switch {
+			case isCall && promQLLabelKeywords[name]:
+				i = skipUntil(expr, next+1, ')')
+			case isCall || promQLKeywords[name]:
+				// function names and keywords
+			case !stringInSlice(name, names):
+				names = append(names, name)
+			}
+		default:
+			i++
+		}
+	}
+	return names
+}
+
+func isMetricNameStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == ':'
+}
+
+func isMetricNameChar(c byte) bool {
+	return isMetricNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// skipQuoted returns the index after the string starting at start.
+func skipQuoted(expr string, start int) int {
+	quote := expr[start]
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(expr)
+}
+
+// skipUntil returns the index after the closing character, ignoring the ones
+// within strings.
+func skipUntil(expr string, start int, closing byte) int {
+	for i := start; i < len(expr); {
+		switch c := expr[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			i = skipQuoted(expr, i)
+		case c == closing:
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(expr)
+}
+
+func stringInSlice(str string, list []string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
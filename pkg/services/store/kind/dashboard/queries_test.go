@@ -0,0 +1,41 @@
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricNames(t *testing.T) {
+	tests := []struct {
+		expr    string
+		metrics []string
+	}{
+		{expr: "up", metrics: []string{"up"}},
+		{expr: `up{job="grafana", instance=~"$instance"}`, metrics: []string{"up"}},
+		{expr: "sum by (mode) (rate(node_cpu_seconds_total[5m]))", metrics: []string{"node_cpu_seconds_total"}},
+		{expr: "sum(rate(http_requests_total{code=~\"5..\"}[$__rate_interval])) without (instance) / sum(rate(http_requests_total[1m]))", metrics: []string{"http_requests_total"}},
+		{expr: "a_total / on (job) group_left(version) b_info offset 5m", metrics: []string{"a_total", "b_info"}},
+		{expr: "histogram_quantile(0.99, sum by (le) (rate(grafana_http_request_duration_seconds_bucket[${interval}])))", metrics: []string{"grafana_http_request_duration_seconds_bucket"}},
+		{expr: `label_replace(up, "host", "$1", "instance", "(.*):.*") > bool 0`, metrics: []string{"up"}},
+		{expr: `sum(count_over_time({app="grafana"} |= "error" | logfmt [5m]))`, metrics: []string{}},
+		{expr: "", metrics: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			require.Equal(t, tt.metrics, MetricNames(tt.expr))
+		})
+	}
+}
+
+func TestReadQueryInfo(t *testing.T) {
+	info := ReadQueryInfo([]map[string]any{
+		{"refId": "A", "expr": "rate(up[5m])"},
+		{"refId": "B", "rawSql": " SELECT 1 ", "hide": true},
+		{"refId": "C", "metricName": "CPUUtilization", "query": ""},
+		{"refId": "D", "expr": "rate(up[5m])"},
+	})
+	require.Equal(t, []string{"rate(up[5m])", "SELECT 1"}, info.Queries)
+	require.Equal(t, []string{"up", "CPUUtilization"}, info.Metrics)
+}
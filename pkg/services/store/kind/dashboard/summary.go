@@ -11,6 +11,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/store/entity"
 )
 
+// PanelFieldQueries is the summary field with the JSON list of the queries of a panel
+const PanelFieldQueries = "queries"
+
 // This summary does not resolve old name as UID
 func GetEntitySummaryBuilder() entity.EntitySummaryBuilder {
 	builder := NewStaticDashboardSummaryBuilder(&directLookup{}, true)
@@ -106,6 +109,14 @@ func panelSummary(panel panelInfo, uid string, dashboardRefs ReferenceAccumulato
 		panelRefs.Add(entity.ExternalEntityReferenceRuntime, entity.ExternalEntityReferenceRuntime_Transformer, v)
 		dashboardRefs.Add(entity.ExternalEntityReferenceRuntime, entity.ExternalEntityReferenceRuntime_Transformer, v)
 	}
+	if len(panel.Queries.Queries) > 0 {
+		queries, _ := json.Marshal(panel.Queries.Queries)
+		p.Fields[PanelFieldQueries] = string(queries)
+	}
+	for _, v := range panel.Queries.Metrics {
+		panelRefs.Add(entity.ExternalEntityReferenceMetric, "", v)
+		dashboardRefs.Add(entity.ExternalEntityReferenceMetric, "", v)
+	}
 	p.References = panelRefs.Get()
 	panels = append(panels, p)
 
@@ -5,8 +5,9 @@ import (
 )
 
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries QueryInfo
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -76,6 +77,11 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
 			iter.Skip()
 
 		default:
+			if isQueryField(l1Field) && iter.WhatIsNext() == jsoniter.StringValue {
+				s.queries.add(l1Field, iter.ReadString())
+				continue
+			}
+
 			v := iter.Read()
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
 		}
@@ -9,6 +9,8 @@ type panelInfo struct {
 	LibraryPanel  string          `json:"libraryPanel,omitempty"` // UID of referenced library panel
 	Datasource    []DataSourceRef `json:"datasource,omitempty"`   // UIDs
 	Transformer   []string        `json:"transformer,omitempty"`  // ids of the transformation steps
+	// Queries are only used to build the summary
+	Queries QueryInfo `json:"-"`
 	// Rows define panels as sub objects
 	Collapsed []panelInfo `json:"collapsed,omitempty"`
 }
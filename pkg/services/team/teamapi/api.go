@@ -7,7 +7,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/licensing"
 	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -19,6 +21,8 @@ type TeamAPI struct {
 	cfg                    *setting.Cfg
 	preferenceService      pref.Service
 	ds                     dashboards.DashboardService
+	provenanceService      provenance.Service
+	teamSyncService        teamsync.Service
 }
 
 func ProvideTeamAPI(
@@ -31,6 +35,8 @@ func ProvideTeamAPI(
 	cfg *setting.Cfg,
 	preferenceService pref.Service,
 	ds dashboards.DashboardService,
+	provenanceService provenance.Service,
+	teamSyncService teamsync.Service,
 ) *TeamAPI {
 	tapi := &TeamAPI{
 		teamService:            teamService,
@@ -40,6 +46,8 @@ func ProvideTeamAPI(
 		cfg:                    cfg,
 		preferenceService:      preferenceService,
 		ds:                     ds,
+		provenanceService:      provenanceService,
+		teamSyncService:        teamSyncService,
 	}
 
 	tapi.registerRoutes(routeRegister, acEvaluator)
@@ -65,6 +73,12 @@ func (tapi *TeamAPI) registerRoutes(router routing.RouteRegister, ac accesscontr
 				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.updateTeamMember))
 			teamsRoute.Delete("/:teamId/members/:userId", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsWrite,
 				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.removeTeamMember))
+			teamsRoute.Get("/:teamId/groups", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsRead,
+				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.getTeamGroups))
+			teamsRoute.Post("/:teamId/groups", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsWrite,
+				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.addTeamGroup))
+			teamsRoute.Delete("/:teamId/groups", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsWrite,
+				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.removeTeamGroup))
 			teamsRoute.Get("/:teamId/preferences", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsRead,
 				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.getTeamPreferences))
 			teamsRoute.Put("/:teamId/preferences", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsWrite,
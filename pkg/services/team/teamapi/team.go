@@ -12,6 +12,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
 	"github.com/grafana/grafana/pkg/services/preference/prefapi"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/team/sortopts"
 	"github.com/grafana/grafana/pkg/util"
@@ -94,6 +95,10 @@ func (tapi *TeamAPI) updateTeam(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
 	}
 
+	if err := tapi.ensureNotProvisioned(c, cmd.ID); err != nil {
+		return response.Err(err)
+	}
+
 	if err := tapi.teamService.UpdateTeam(c.Req.Context(), &cmd); err != nil {
 		if errors.Is(err, team.ErrTeamNameTaken) {
 			return response.Error(http.StatusBadRequest, "Team name taken", err)
@@ -121,6 +126,10 @@ func (tapi *TeamAPI) deleteTeamByID(c *contextmodel.ReqContext) response.Respons
 		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
 	}
 
+	if err := tapi.ensureNotProvisioned(c, teamID); err != nil {
+		return response.Err(err)
+	}
+
 	if err := tapi.teamService.DeleteTeam(c.Req.Context(), &team.DeleteTeamCommand{OrgID: orgID, ID: teamID}); err != nil {
 		if errors.Is(err, team.ErrTeamNotFound) {
 			return response.Error(http.StatusNotFound, "Failed to delete Team. ID not found", nil)
@@ -376,6 +385,12 @@ type CreateTeamResponse struct {
 
 // getMultiAccessControlMetadata returns the accesscontrol metadata associated with a given set of resources
 // Context must contain permissions in the given org (see LoadPermissionsMiddleware or AuthorizeInOrgMiddleware)
+// ensureNotProvisioned prevents changes to the teams, and their members, created from provisioning files.
+func (tapi *TeamAPI) ensureNotProvisioned(c *contextmodel.ReqContext, teamID int64) error {
+	return provenance.EnsureNotProvisioned(c.Req.Context(), tapi.provenanceService, c.SignedInUser.GetOrgID(),
+		provenance.KindTeam, strconv.FormatInt(teamID, 10))
+}
+
 func (tapi *TeamAPI) getMultiAccessControlMetadata(c *contextmodel.ReqContext,
 	prefix string, resourceIDs map[string]bool) map[string]accesscontrol.Metadata {
 	if !c.QueryBool("accesscontrol") {
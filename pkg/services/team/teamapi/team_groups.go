@@ -0,0 +1,145 @@
+package teamapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /teams/{teamId}/groups sync_team_groups getTeamGroupsApi
+//
+// Get External Groups.
+//
+// Responses:
+// 200: getTeamGroupsApiResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (tapi *TeamAPI) getTeamGroups(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	groups, err := tapi.teamSyncService.GetTeamGroups(c.Req.Context(), &teamsync.GetTeamGroupsQuery{OrgID: c.SignedInUser.GetOrgID(), TeamID: teamID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get team groups", err)
+	}
+
+	return response.JSON(http.StatusOK, groups)
+}
+
+// swagger:route POST /teams/{teamId}/groups sync_team_groups addTeamGroupApi
+//
+// Add External Group.
+//
+// Users of the external group are added to the team when they log in.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (tapi *TeamAPI) addTeamGroup(c *contextmodel.ReqContext) response.Response {
+	cmd := teamsync.AddTeamGroupCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	var err error
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.TeamID, err = strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	if err := tapi.ensureNotProvisioned(c, cmd.TeamID); err != nil {
+		return response.Err(err)
+	}
+
+	if err := tapi.teamSyncService.AddTeamGroup(c.Req.Context(), &cmd); err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return response.Error(http.StatusNotFound, "Team not found", nil)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to add group to team", err)
+	}
+
+	return response.Success("Group added to Team")
+}
+
+// swagger:route DELETE /teams/{teamId}/groups sync_team_groups removeTeamGroupApiQuery
+//
+// Remove External Group.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (tapi *TeamAPI) removeTeamGroup(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	// group ids, like LDAP DNs, commonly contain characters that don't fit in a path segment
+	groupID := c.Query("groupId")
+	if groupID == "" {
+		return response.Error(http.StatusBadRequest, "groupId is required", nil)
+	}
+
+	if err := tapi.ensureNotProvisioned(c, teamID); err != nil {
+		return response.Err(err)
+	}
+
+	cmd := &teamsync.RemoveTeamGroupCommand{OrgID: c.SignedInUser.GetOrgID(), TeamID: teamID, GroupID: groupID}
+	if err := tapi.teamSyncService.RemoveTeamGroup(c.Req.Context(), cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to remove group from team", err)
+	}
+
+	return response.Success("Team Group removed")
+}
+
+// swagger:parameters getTeamGroupsApi
+type GetTeamGroupsApiParams struct {
+	// in:path
+	// required:true
+	TeamID int64 `json:"teamId"`
+}
+
+// swagger:parameters addTeamGroupApi
+type AddTeamGroupApiParams struct {
+	// in:body
+	// required:true
+	Body teamsync.AddTeamGroupCommand `json:"body"`
+	// in:path
+	// required:true
+	TeamID int64 `json:"teamId"`
+}
+
+// swagger:parameters removeTeamGroupApiQuery
+type RemoveTeamGroupApiQueryParams struct {
+	// in:query
+	GroupID string `json:"groupId"`
+	// in:path
+	// required:true
+	TeamID int64 `json:"teamId"`
+}
+
+// swagger:response getTeamGroupsApiResponse
+type GetTeamGroupsApiResponse struct {
+	// in:body
+	Body []*teamsync.TeamGroupDTO `json:"body"`
+}
@@ -0,0 +1,168 @@
+package teamapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+const adminsGroup = "cn=admins,ou=groups,dc=grafana,dc=org"
+
+func TestGetTeamGroupsAPIEndpoint(t *testing.T) {
+	teamSyncService := &teamsynctest.FakeService{Groups: []*teamsync.TeamGroupDTO{
+		{OrgID: 1, TeamID: 1, GroupID: adminsGroup},
+		{OrgID: 1, TeamID: 2, GroupID: "cn=editors,ou=groups,dc=grafana,dc=org"},
+	}}
+	server := SetupAPITestServer(t, func(a *TeamAPI) {
+		a.teamSyncService = teamSyncService
+	})
+
+	t.Run("should be able to get team groups with correct permission", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/teams/1/groups"),
+			authedUserWithPermissions(1, 1, []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsPermissionsRead, Scope: "teams:id:1"}}),
+		)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var groups []*teamsync.TeamGroupDTO
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&groups))
+		require.NoError(t, res.Body.Close())
+		require.Len(t, groups, 1)
+		assert.Equal(t, adminsGroup, groups[0].GroupID)
+	})
+
+	t.Run("should not be able to get team groups without correct permission", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/teams/1/groups"),
+			authedUserWithPermissions(1, 1, []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsPermissionsRead, Scope: "teams:id:2"}}),
+		)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
+func TestAddTeamGroupAPIEndpoint(t *testing.T) {
+	writeTeam1 := []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsPermissionsWrite, Scope: "teams:id:1"}}
+	body := `{"groupId": "` + adminsGroup + `"}`
+
+	t.Run("should be able to add team group with correct permission", func(t *testing.T) {
+		teamSyncService := teamsynctest.NewFakeService()
+		server := SetupAPITestServer(t, func(a *TeamAPI) {
+			a.teamSyncService = teamSyncService
+		})
+
+		req := webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodPost, "/api/teams/1/groups", strings.NewReader(body)),
+			authedUserWithPermissions(1, 1, writeTeam1),
+		)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, []*teamsync.TeamGroupDTO{{OrgID: 1, TeamID: 1, GroupID: adminsGroup}}, teamSyncService.Groups)
+
+		req = webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodPost, "/api/teams/1/groups", strings.NewReader(body)),
+			authedUserWithPermissions(1, 1, writeTeam1),
+		)
+		res, err = server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not be able to add team group without correct permission", func(t *testing.T) {
+		server := SetupAPITestServer(t)
+
+		req := webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodPost, "/api/teams/1/groups", strings.NewReader(body)),
+			authedUserWithPermissions(1, 1, []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsPermissionsWrite, Scope: "teams:id:2"}}),
+		)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not be able to add group to a provisioned team", func(t *testing.T) {
+		provenanceService := provenancetest.NewFakeService()
+		require.NoError(t, provenanceService.SetProvisioned(context.Background(), 1, provenance.KindTeam, "1"))
+		server := SetupAPITestServer(t, func(a *TeamAPI) {
+			a.provenanceService = provenanceService
+		})
+
+		req := webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodPost, "/api/teams/1/groups", strings.NewReader(body)),
+			authedUserWithPermissions(1, 1, writeTeam1),
+		)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
+func TestRemoveTeamGroupAPIEndpoint(t *testing.T) {
+	writeTeam1 := []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsPermissionsWrite, Scope: "teams:id:1"}}
+	removeURL := "/api/teams/1/groups?groupId=" + url.QueryEscape(adminsGroup)
+
+	t.Run("should be able to remove team group with correct permission", func(t *testing.T) {
+		teamSyncService := &teamsynctest.FakeService{Groups: []*teamsync.TeamGroupDTO{{OrgID: 1, TeamID: 1, GroupID: adminsGroup}}}
+		server := SetupAPITestServer(t, func(a *TeamAPI) {
+			a.teamSyncService = teamSyncService
+		})
+
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, removeURL, nil), authedUserWithPermissions(1, 1, writeTeam1))
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+		assert.Empty(t, teamSyncService.Groups)
+
+		req = webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, removeURL, nil), authedUserWithPermissions(1, 1, writeTeam1))
+		res, err = server.Send(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should require a group id", func(t *testing.T) {
+		server := SetupAPITestServer(t)
+
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/teams/1/groups", nil), authedUserWithPermissions(1, 1, writeTeam1))
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not be able to remove team group without correct permission", func(t *testing.T) {
+		server := SetupAPITestServer(t)
+
+		req := webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodDelete, removeURL, nil),
+			authedUserWithPermissions(1, 1, []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsPermissionsRead, Scope: "teams:id:1"}}),
+		)
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
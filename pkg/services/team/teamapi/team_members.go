@@ -50,7 +50,7 @@ func (tapi *TeamAPI) getTeamMembers(c *contextmodel.ReqContext) response.Respons
 		member.AvatarURL = dtos.GetGravatarUrl(tapi.cfg, member.Email)
 		member.Labels = []string{}
 
-		if tapi.license.FeatureEnabled("teamgroupsync") && member.External {
+		if member.External {
 			authProvider := login.GetAuthProviderLabel(member.AuthModule)
 			member.Labels = append(member.Labels, authProvider)
 		}
@@ -83,6 +83,10 @@ func (tapi *TeamAPI) addTeamMember(c *contextmodel.ReqContext) response.Response
 		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
 	}
 
+	if err := tapi.ensureNotProvisioned(c, cmd.TeamID); err != nil {
+		return response.Err(err)
+	}
+
 	isTeamMember, err := tapi.teamService.IsTeamMember(c.SignedInUser.GetOrgID(), cmd.TeamID, cmd.UserID)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to add team member.", err)
@@ -126,6 +130,10 @@ func (tapi *TeamAPI) updateTeamMember(c *contextmodel.ReqContext) response.Respo
 	}
 	orgId := c.SignedInUser.GetOrgID()
 
+	if err := tapi.ensureNotProvisioned(c, teamId); err != nil {
+		return response.Err(err)
+	}
+
 	isTeamMember, err := tapi.teamService.IsTeamMember(orgId, teamId, userId)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to update team member.", err)
@@ -172,6 +180,10 @@ func (tapi *TeamAPI) removeTeamMember(c *contextmodel.ReqContext) response.Respo
 		return response.Error(http.StatusBadRequest, "userId is invalid", err)
 	}
 
+	if err := tapi.ensureNotProvisioned(c, teamId); err != nil {
+		return response.Err(err)
+	}
+
 	teamIDString := strconv.FormatInt(teamId, 10)
 	if _, err := tapi.teamPermissionsService.SetUserPermission(c.Req.Context(), orgId, accesscontrol.User{ID: userId}, teamIDString, ""); err != nil {
 		if errors.Is(err, team.ErrTeamNotFound) {
@@ -16,7 +16,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/licensing"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/preference/preftest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
 	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web/webtest"
@@ -37,6 +39,8 @@ func SetupAPITestServer(t *testing.T, opts ...func(a *TeamAPI)) *webtest.Server
 		cfg,
 		preftest.NewPreferenceServiceFake(),
 		dashboards.NewFakeDashboardService(t),
+		provenancetest.NewFakeService(),
+		teamsynctest.NewFakeService(),
 	)
 	for _, o := range opts {
 		o(a)
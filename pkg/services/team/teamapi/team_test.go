@@ -1,6 +1,7 @@
 package teamapi
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -12,6 +13,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/preference/preftest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/team/teamtest"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -158,6 +161,24 @@ func TestTeamAPIEndpoint_UpdateTeam(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, res.StatusCode)
 		require.NoError(t, res.Body.Close())
 	})
+
+	t.Run("Should prevent updating a provisioned team", func(t *testing.T) {
+		provenanceService := provenancetest.NewFakeService()
+		require.NoError(t, provenanceService.SetProvisioned(context.Background(), 1, provenance.KindTeam, "1"))
+		server := SetupAPITestServer(t, func(hs *TeamAPI) {
+			hs.teamService = &teamtest.FakeService{ExpectedTeamDTO: &team.TeamDTO{}}
+			hs.provenanceService = provenanceService
+		})
+
+		req := server.NewRequest(http.MethodPut, fmt.Sprintf(detailTeamURL, 1), strings.NewReader(teamCmd))
+		req = webtest.RequestWithSignedInUser(req, authedUserWithPermissions(1, 1, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionTeamsWrite, Scope: "teams:*"},
+		}))
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
 }
 
 // Given a team with a user, when the user is granted X permission,
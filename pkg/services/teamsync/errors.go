@@ -0,0 +1,11 @@
+package teamsync
+
+import (
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrTeamGroupNotFound     = errutil.NotFound("teamsync.group-not-found", errutil.WithPublicMessage("Group is not mapped to the team"))
+	ErrTeamGroupAlreadyAdded = errutil.Conflict("teamsync.group-already-added", errutil.WithPublicMessage("Group is already mapped to the team"))
+	ErrInvalidGroupID        = errutil.BadRequest("teamsync.invalid-group-id", errutil.WithPublicMessage("Group id is required and can be at most 190 characters"))
+)
@@ -0,0 +1,53 @@
+package teamsync
+
+import (
+	"time"
+)
+
+// TeamGroup maps a group of an external identity provider to a team.
+type TeamGroup struct {
+	ID      int64  `xorm:"pk autoincr 'id'"`
+	OrgID   int64  `xorm:"org_id"`
+	TeamID  int64  `xorm:"team_id"`
+	GroupID string `xorm:"group_id"`
+
+	Created time.Time
+	Updated time.Time
+}
+
+type TeamGroupDTO struct {
+	OrgID    int64  `json:"orgId" xorm:"org_id"`
+	OrgName  string `json:"orgName,omitempty" xorm:"org_name"`
+	TeamID   int64  `json:"teamId" xorm:"team_id"`
+	TeamName string `json:"teamName,omitempty" xorm:"team_name"`
+	GroupID  string `json:"groupId" xorm:"group_id"`
+}
+
+type GetTeamGroupsQuery struct {
+	OrgID  int64
+	TeamID int64
+}
+
+type GetTeamsByGroupsQuery struct {
+	// OrgIDs restricts the lookup to the given organizations, all
+	// organizations are searched when empty.
+	OrgIDs []int64
+	Groups []string
+}
+
+type AddTeamGroupCommand struct {
+	OrgID   int64  `json:"-"`
+	TeamID  int64  `json:"-"`
+	GroupID string `json:"groupId"`
+}
+
+type RemoveTeamGroupCommand struct {
+	OrgID   int64
+	TeamID  int64
+	GroupID string
+}
+
+type SyncUserTeamsCommand struct {
+	UserID int64
+	Groups []string
+}
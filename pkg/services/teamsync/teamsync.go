@@ -0,0 +1,24 @@
+package teamsync
+
+import (
+	"context"
+)
+
+// Service maps groups of an external identity provider, such as LDAP group
+// DNs or OAuth group claims, to teams and keeps the team memberships of
+// externally authenticated users in line with those mappings.
+type Service interface {
+	GetTeamGroups(context.Context, *GetTeamGroupsQuery) ([]*TeamGroupDTO, error)
+	AddTeamGroup(context.Context, *AddTeamGroupCommand) error
+	RemoveTeamGroup(context.Context, *RemoveTeamGroupCommand) error
+
+	// GetTeamsByGroups returns the team mappings matching any of the given
+	// groups. Groups are compared case-insensitively.
+	GetTeamsByGroups(context.Context, *GetTeamsByGroupsQuery) ([]*TeamGroupDTO, error)
+
+	// SyncUserTeams adds the user to the teams mapped to its groups and
+	// removes it from the teams it was previously added to by team sync but
+	// no longer has a matching group for. Memberships that were not created
+	// by team sync are left untouched.
+	SyncUserTeams(context.Context, *SyncUserTeamsCommand) error
+}
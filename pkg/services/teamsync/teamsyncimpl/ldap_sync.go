@@ -0,0 +1,148 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ldap/service"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	ldapSyncActionName = "ldap team sync"
+	ldapSyncPageSize   = 500
+)
+
+// LDAPSync periodically looks up the groups of the users that logged in
+// through LDAP and syncs their team memberships, so that changes to the LDAP
+// groups apply without having to wait for the user to log in again.
+type LDAPSync struct {
+	cfg         *setting.Cfg
+	teamSync    teamsync.Service
+	ldapService service.LDAP
+	userService user.Service
+	serverLock  *serverlock.ServerLockService
+	log         log.Logger
+	now         func() time.Time
+}
+
+func ProvideLDAPSync(cfg *setting.Cfg, teamSync teamsync.Service, ldapService service.LDAP,
+	userService user.Service, serverLock *serverlock.ServerLockService) *LDAPSync {
+	return &LDAPSync{
+		cfg:         cfg,
+		teamSync:    teamSync,
+		ldapService: ldapService,
+		userService: userService,
+		serverLock:  serverLock,
+		log:         log.New("teamsync.ldap"),
+		now:         time.Now,
+	}
+}
+
+func (s *LDAPSync) IsDisabled() bool {
+	return !s.cfg.LDAPAuthEnabled || !s.cfg.LDAPActiveSyncEnabled
+}
+
+func (s *LDAPSync) Run(ctx context.Context) error {
+	schedule, err := cron.ParseStandard(s.cfg.LDAPSyncCron)
+	if err != nil {
+		s.log.Error("Invalid LDAP sync schedule, background team sync is disabled", "schedule", s.cfg.LDAPSyncCron, "error", err)
+		return nil
+	}
+
+	for {
+		now := s.now()
+		timer := time.NewTimer(schedule.Next(now).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+			err := s.serverLock.LockAndExecute(ctx, ldapSyncActionName, time.Minute, func(ctx context.Context) {
+				if err := s.Sync(ctx); err != nil {
+					s.log.Error("Failed to sync LDAP teams", "error", err)
+				}
+			})
+			if err != nil {
+				s.log.Error("Failed to acquire lock for LDAP team sync", "error", err)
+			}
+		}
+	}
+}
+
+// Sync syncs the teams of every user whose latest login was through LDAP.
+// Users that can no longer be found in LDAP are skipped.
+func (s *LDAPSync) Sync(ctx context.Context) error {
+	client := s.ldapService.Client()
+	if client == nil {
+		return service.ErrLDAPNotEnabled
+	}
+
+	start := s.now()
+	requester := accesscontrol.BackgroundUser("ldap_team_sync", accesscontrol.GlobalOrgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionUsersRead, Scope: accesscontrol.ScopeGlobalUsersAll},
+	})
+
+	synced, failed := 0, 0
+	for page := 1; ; page++ {
+		result, err := s.userService.Search(ctx, &user.SearchUsersQuery{
+			SignedInUser: requester,
+			AuthModule:   login.LDAPAuthModule,
+			Page:         page,
+			Limit:        ldapSyncPageSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(result.Users) == 0 {
+			break
+		}
+
+		logins := make([]string, 0, len(result.Users))
+		for _, u := range result.Users {
+			logins = append(logins, u.Login)
+		}
+
+		infos, err := client.Users(logins)
+		if err != nil {
+			return err
+		}
+
+		groups := make(map[string][]string, len(infos))
+		for _, info := range infos {
+			groups[strings.ToLower(info.Login)] = info.Groups
+		}
+
+		for _, u := range result.Users {
+			userGroups, ok := groups[strings.ToLower(u.Login)]
+			if !ok {
+				s.log.Debug("Skipping team sync for user not found in LDAP", "userId", u.ID, "login", u.Login)
+				continue
+			}
+
+			if err := s.teamSync.SyncUserTeams(ctx, &teamsync.SyncUserTeamsCommand{UserID: u.ID, Groups: userGroups}); err != nil {
+				s.log.Warn("Failed to sync user teams", "userId", u.ID, "error", err)
+				failed++
+				continue
+			}
+			synced++
+		}
+
+		if len(result.Users) < ldapSyncPageSize {
+			break
+		}
+	}
+
+	s.log.Info("Synced LDAP teams", "users", synced, "failed", failed, "duration", s.now().Sub(start))
+	return nil
+}
@@ -0,0 +1,110 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldap/multildap"
+	"github.com/grafana/grafana/pkg/services/ldap/service"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLDAPSync_Sync(t *testing.T) {
+	directory := &fakeDirectory{groups: map[string][]string{
+		"alice": {editorsGroup},
+		"bob":   {adminsGroup, editorsGroup},
+		// carol no longer belongs to any group
+		"carol": {},
+	}}
+
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedSearchUsers = user.SearchUserQueryResult{Users: []*user.UserSearchHitDTO{
+		{ID: 1, Login: "alice"},
+		{ID: 2, Login: "Bob"},
+		{ID: 3, Login: "carol"},
+		// dave was removed from LDAP
+		{ID: 4, Login: "dave"},
+	}}
+
+	teamSync := teamsynctest.NewFakeService()
+	s := setupLDAPSync(t, teamSync, &service.LDAPFakeService{ExpectedClient: directory}, userService)
+
+	require.NoError(t, s.Sync(context.Background()))
+	assert.Equal(t, []string{"alice", "Bob", "carol", "dave"}, directory.requested)
+	assert.Equal(t, []*teamsync.SyncUserTeamsCommand{
+		{UserID: 1, Groups: []string{editorsGroup}},
+		{UserID: 2, Groups: []string{adminsGroup, editorsGroup}},
+		{UserID: 3, Groups: []string{}},
+	}, teamSync.SyncedUsers)
+}
+
+func TestLDAPSync_SyncWithoutLDAP(t *testing.T) {
+	s := setupLDAPSync(t, teamsynctest.NewFakeService(), service.NewLDAPFakeService(), usertest.NewUserServiceFake())
+	require.ErrorIs(t, s.Sync(context.Background()), service.ErrLDAPNotEnabled)
+}
+
+func TestLDAPSync_IsDisabled(t *testing.T) {
+	s := setupLDAPSync(t, teamsynctest.NewFakeService(), service.NewLDAPFakeService(), usertest.NewUserServiceFake())
+	assert.True(t, s.IsDisabled())
+
+	s.cfg.LDAPAuthEnabled = true
+	assert.False(t, s.IsDisabled())
+
+	s.cfg.LDAPActiveSyncEnabled = false
+	assert.True(t, s.IsDisabled())
+}
+
+func setupLDAPSync(t *testing.T, teamSync teamsync.Service, ldapService service.LDAP, userService user.Service) *LDAPSync {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.LDAPActiveSyncEnabled = true
+	return &LDAPSync{
+		cfg:         cfg,
+		teamSync:    teamSync,
+		ldapService: ldapService,
+		userService: userService,
+		log:         log.NewNopLogger(),
+		now:         time.Now,
+	}
+}
+
+// fakeDirectory is an in-process stand-in for the LDAP servers, returning
+// the groups of the users it knows about.
+type fakeDirectory struct {
+	multildap.MultiLDAP
+	groups    map[string][]string
+	requested []string
+}
+
+func (d *fakeDirectory) Users(logins []string) ([]*login.ExternalUserInfo, error) {
+	d.requested = append(d.requested, logins...)
+
+	result := make([]*login.ExternalUserInfo, 0, len(logins))
+	for _, l := range logins {
+		if groups, ok := d.groups[strings.ToLower(l)]; ok {
+			result = append(result, &login.ExternalUserInfo{Login: strings.ToLower(l), AuthModule: login.LDAPAuthModule, Groups: groups})
+		}
+	}
+	return result, nil
+}
+
+func (d *fakeDirectory) User(l string) (*login.ExternalUserInfo, ldap.ServerConfig, error) {
+	users, err := d.Users([]string{l})
+	if err != nil || len(users) == 0 {
+		return nil, ldap.ServerConfig{}, multildap.ErrDidNotFindUser
+	}
+	return users[0], ldap.ServerConfig{}, nil
+}
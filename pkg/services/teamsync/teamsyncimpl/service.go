@@ -0,0 +1,145 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
+const (
+	// maxGroupIDLength matches the size of the team_group.group_id column
+	maxGroupIDLength = 190
+
+	permissionMember = "Member"
+)
+
+type Service struct {
+	store                  store
+	teamService            team.Service
+	orgService             org.Service
+	teamPermissionsService accesscontrol.TeamPermissionsService
+	log                    log.Logger
+	now                    func() time.Time
+}
+
+var _ teamsync.Service = &Service{}
+
+func ProvideService(db db.DB, teamService team.Service, orgService org.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService) *Service {
+	s := &Service{
+		store:                  &sqlStore{db: db},
+		teamService:            teamService,
+		orgService:             orgService,
+		teamPermissionsService: teamPermissionsService,
+		log:                    log.New("teamsync"),
+		now:                    time.Now,
+	}
+
+	teamService.RegisterDelete("DELETE FROM team_group WHERE org_id = ? AND team_id = ?")
+	orgService.RegisterDelete("DELETE FROM team_group WHERE org_id = ?")
+
+	return s
+}
+
+func (s *Service) GetTeamGroups(ctx context.Context, query *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroupDTO, error) {
+	return s.store.GetByTeam(ctx, query)
+}
+
+func (s *Service) AddTeamGroup(ctx context.Context, cmd *teamsync.AddTeamGroupCommand) error {
+	groupID := strings.TrimSpace(cmd.GroupID)
+	if groupID == "" || len(groupID) > maxGroupIDLength {
+		return teamsync.ErrInvalidGroupID.Errorf("invalid group id %q", cmd.GroupID)
+	}
+
+	now := s.now()
+	return s.store.Insert(ctx, &teamsync.TeamGroup{
+		OrgID:   cmd.OrgID,
+		TeamID:  cmd.TeamID,
+		GroupID: groupID,
+		Created: now,
+		Updated: now,
+	})
+}
+
+func (s *Service) RemoveTeamGroup(ctx context.Context, cmd *teamsync.RemoveTeamGroupCommand) error {
+	return s.store.Delete(ctx, cmd)
+}
+
+func (s *Service) GetTeamsByGroups(ctx context.Context, query *teamsync.GetTeamsByGroupsQuery) ([]*teamsync.TeamGroupDTO, error) {
+	return s.store.GetByGroups(ctx, query)
+}
+
+func (s *Service) SyncUserTeams(ctx context.Context, cmd *teamsync.SyncUserTeamsCommand) error {
+	ctxLogger := s.log.FromContext(ctx)
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: cmd.UserID})
+	if err != nil {
+		return err
+	}
+
+	// team sync only manages teams of organizations the user is a member of
+	userOrgs := make(map[int64]bool, len(orgs))
+	orgIDs := make([]int64, 0, len(orgs))
+	for _, o := range orgs {
+		userOrgs[o.OrgID] = true
+		orgIDs = append(orgIDs, o.OrgID)
+	}
+
+	desired := map[int64]*teamsync.TeamGroupDTO{}
+	if len(orgIDs) > 0 {
+		mappings, err := s.store.GetByGroups(ctx, &teamsync.GetTeamsByGroupsQuery{OrgIDs: orgIDs, Groups: cmd.Groups})
+		if err != nil {
+			return err
+		}
+		for _, m := range mappings {
+			desired[m.TeamID] = m
+		}
+	}
+
+	memberships, err := s.teamService.GetUserTeamMemberships(ctx, 0, cmd.UserID, false)
+	if err != nil {
+		return err
+	}
+
+	isMember := make(map[int64]bool, len(memberships))
+	for _, m := range memberships {
+		isMember[m.TeamID] = true
+		if !m.External || desired[m.TeamID] != nil || !userOrgs[m.OrgID] {
+			continue
+		}
+
+		ctxLogger.Debug("Removing user from team", "userId", cmd.UserID, "orgId", m.OrgID, "teamId", m.TeamID)
+		if err := s.setMembership(ctx, m.OrgID, m.TeamID, cmd.UserID, ""); err != nil {
+			return err
+		}
+	}
+
+	for teamID, m := range desired {
+		// users added manually keep their membership and permission
+		if isMember[teamID] {
+			continue
+		}
+
+		ctxLogger.Debug("Adding user to team", "userId", cmd.UserID, "orgId", m.OrgID, "teamId", teamID, "group", m.GroupID)
+		if err := s.setMembership(ctx, m.OrgID, teamID, cmd.UserID, permissionMember); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setMembership adds or removes the user through the team permissions service
+// so that the managed team permissions are kept in line with the membership.
+func (s *Service) setMembership(ctx context.Context, orgID, teamID, userID int64, permission string) error {
+	_, err := s.teamPermissionsService.SetUserPermission(ctx, orgID, accesscontrol.User{ID: userID, IsExternal: true}, strconv.FormatInt(teamID, 10), permission)
+	return err
+}
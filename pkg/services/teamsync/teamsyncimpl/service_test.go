@@ -0,0 +1,182 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
+const (
+	editorsGroup = "cn=editors,ou=groups,dc=grafana,dc=org"
+	adminsGroup  = "cn=admins,ou=groups,dc=grafana,dc=org"
+)
+
+func TestService_SyncUserTeams(t *testing.T) {
+	mappings := []*teamsync.TeamGroupDTO{
+		{OrgID: 1, TeamID: 1, GroupID: editorsGroup},
+		{OrgID: 1, TeamID: 2, GroupID: adminsGroup},
+		{OrgID: 2, TeamID: 3, GroupID: editorsGroup},
+		// the user is not a member of org 3
+		{OrgID: 3, TeamID: 4, GroupID: editorsGroup},
+	}
+	userOrgs := []*org.UserOrgDTO{{OrgID: 1}, {OrgID: 2}}
+
+	tests := []struct {
+		desc        string
+		groups      []string
+		memberships []*team.TeamMemberDTO
+		expected    []membershipCall
+	}{
+		{
+			desc:   "should add user to the teams of its groups in its orgs",
+			groups: []string{"CN=Editors,OU=Groups,DC=grafana,DC=org"},
+			expected: []membershipCall{
+				{orgID: 1, teamID: "1", permission: permissionMember},
+				{orgID: 2, teamID: "3", permission: permissionMember},
+			},
+		},
+		{
+			desc:   "should keep existing memberships",
+			groups: []string{editorsGroup},
+			memberships: []*team.TeamMemberDTO{
+				{OrgID: 1, TeamID: 1, External: true},
+				{OrgID: 2, TeamID: 3, External: false},
+			},
+		},
+		{
+			desc:   "should remove user from external teams it no longer has a group for",
+			groups: []string{adminsGroup},
+			memberships: []*team.TeamMemberDTO{
+				{OrgID: 1, TeamID: 1, External: true},
+				{OrgID: 2, TeamID: 3, External: true},
+			},
+			expected: []membershipCall{
+				{orgID: 1, teamID: "1", permission: ""},
+				{orgID: 2, teamID: "3", permission: ""},
+				{orgID: 1, teamID: "2", permission: permissionMember},
+			},
+		},
+		{
+			desc:   "should not remove memberships that were not added by team sync",
+			groups: nil,
+			memberships: []*team.TeamMemberDTO{
+				{OrgID: 1, TeamID: 1, External: false},
+				{OrgID: 1, TeamID: 5, External: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			permissions := &spyTeamPermissionsService{}
+			s := &Service{
+				store:                  &fakeStore{groups: mappings},
+				teamService:            &teamtest.FakeService{ExpectedMembers: tt.memberships},
+				orgService:             &orgtest.FakeOrgService{ExpectedUserOrgDTO: userOrgs},
+				teamPermissionsService: permissions,
+				log:                    log.NewNopLogger(),
+				now:                    time.Now,
+			}
+
+			err := s.SyncUserTeams(context.Background(), &teamsync.SyncUserTeamsCommand{UserID: 10, Groups: tt.groups})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, permissions.calls)
+			for _, user := range permissions.users {
+				assert.Equal(t, accesscontrol.User{ID: 10, IsExternal: true}, user)
+			}
+		})
+	}
+}
+
+func TestService_AddTeamGroup(t *testing.T) {
+	s := &Service{store: &fakeStore{}, now: time.Now}
+
+	err := s.AddTeamGroup(context.Background(), &teamsync.AddTeamGroupCommand{OrgID: 1, TeamID: 1, GroupID: "  "})
+	require.ErrorIs(t, err, teamsync.ErrInvalidGroupID)
+
+	err = s.AddTeamGroup(context.Background(), &teamsync.AddTeamGroupCommand{OrgID: 1, TeamID: 1, GroupID: strings.Repeat("a", maxGroupIDLength+1)})
+	require.ErrorIs(t, err, teamsync.ErrInvalidGroupID)
+
+	err = s.AddTeamGroup(context.Background(), &teamsync.AddTeamGroupCommand{OrgID: 1, TeamID: 1, GroupID: " " + editorsGroup})
+	require.NoError(t, err)
+
+	groups, err := s.GetTeamGroups(context.Background(), &teamsync.GetTeamGroupsQuery{OrgID: 1, TeamID: 1})
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, editorsGroup, groups[0].GroupID)
+}
+
+type membershipCall struct {
+	orgID      int64
+	teamID     string
+	permission string
+}
+
+type spyTeamPermissionsService struct {
+	actest.FakePermissionsService
+	calls []membershipCall
+	users []accesscontrol.User
+}
+
+func (s *spyTeamPermissionsService) SetUserPermission(_ context.Context, orgID int64, user accesscontrol.User, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
+	s.calls = append(s.calls, membershipCall{orgID: orgID, teamID: resourceID, permission: permission})
+	s.users = append(s.users, user)
+	return nil, nil
+}
+
+// fakeStore keeps the team group mappings in memory
+type fakeStore struct {
+	groups []*teamsync.TeamGroupDTO
+}
+
+func (f *fakeStore) Insert(_ context.Context, group *teamsync.TeamGroup) error {
+	f.groups = append(f.groups, &teamsync.TeamGroupDTO{OrgID: group.OrgID, TeamID: group.TeamID, GroupID: group.GroupID})
+	return nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, cmd *teamsync.RemoveTeamGroupCommand) error {
+	return nil
+}
+
+func (f *fakeStore) GetByTeam(_ context.Context, query *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroupDTO, error) {
+	result := make([]*teamsync.TeamGroupDTO, 0)
+	for _, g := range f.groups {
+		if g.OrgID == query.OrgID && g.TeamID == query.TeamID {
+			result = append(result, g)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeStore) GetByGroups(_ context.Context, query *teamsync.GetTeamsByGroupsQuery) ([]*teamsync.TeamGroupDTO, error) {
+	result := make([]*teamsync.TeamGroupDTO, 0)
+	for _, g := range f.groups {
+		inOrg := len(query.OrgIDs) == 0
+		for _, orgID := range query.OrgIDs {
+			inOrg = inOrg || orgID == g.OrgID
+		}
+		if !inOrg {
+			continue
+		}
+		for _, group := range query.Groups {
+			if strings.EqualFold(group, g.GroupID) {
+				result = append(result, g)
+				break
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
+type store interface {
+	Insert(context.Context, *teamsync.TeamGroup) error
+	Delete(context.Context, *teamsync.RemoveTeamGroupCommand) error
+	GetByTeam(context.Context, *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroupDTO, error)
+	GetByGroups(context.Context, *teamsync.GetTeamsByGroupsQuery) ([]*teamsync.TeamGroupDTO, error)
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+var _ store = &sqlStore{}
+
+const teamGroupDTOColumns = `team_group.org_id, org.name AS org_name, team_group.team_id, team.name AS team_name, team_group.group_id
+	FROM team_group
+	INNER JOIN team ON team.id = team_group.team_id
+	INNER JOIN org ON org.id = team_group.org_id`
+
+func (s *sqlStore) Insert(ctx context.Context, group *teamsync.TeamGroup) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		teamExists, err := sess.Table("team").Where("org_id = ? AND id = ?", group.OrgID, group.TeamID).Exist()
+		if err != nil {
+			return err
+		}
+		if !teamExists {
+			return team.ErrTeamNotFound
+		}
+
+		exists, err := sess.Where("org_id = ? AND team_id = ? AND group_id = ?", group.OrgID, group.TeamID, group.GroupID).Exist(&teamsync.TeamGroup{})
+		if err != nil {
+			return err
+		}
+		if exists {
+			return teamsync.ErrTeamGroupAlreadyAdded.Errorf("group %s is already mapped to team %d", group.GroupID, group.TeamID)
+		}
+
+		_, err = sess.Insert(group)
+		return err
+	})
+}
+
+func (s *sqlStore) Delete(ctx context.Context, cmd *teamsync.RemoveTeamGroupCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM team_group WHERE org_id = ? AND team_id = ? AND group_id = ?", cmd.OrgID, cmd.TeamID, cmd.GroupID)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return teamsync.ErrTeamGroupNotFound.Errorf("group %s is not mapped to team %d", cmd.GroupID, cmd.TeamID)
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) GetByTeam(ctx context.Context, query *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroupDTO, error) {
+	result := make([]*teamsync.TeamGroupDTO, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := "SELECT " + teamGroupDTOColumns + " WHERE team_group.org_id = ? AND team_group.team_id = ? ORDER BY team_group.group_id"
+		return sess.SQL(q, query.OrgID, query.TeamID).Find(&result)
+	})
+	return result, err
+}
+
+func (s *sqlStore) GetByGroups(ctx context.Context, query *teamsync.GetTeamsByGroupsQuery) ([]*teamsync.TeamGroupDTO, error) {
+	result := make([]*teamsync.TeamGroupDTO, 0)
+	if len(query.Groups) == 0 {
+		return result, nil
+	}
+
+	params := make([]any, 0, len(query.Groups)+len(query.OrgIDs))
+	for _, group := range query.Groups {
+		params = append(params, strings.ToLower(group))
+	}
+	q := "SELECT " + teamGroupDTOColumns + " WHERE LOWER(team_group.group_id) IN (?" + strings.Repeat(",?", len(query.Groups)-1) + ")"
+
+	if len(query.OrgIDs) > 0 {
+		for _, orgID := range query.OrgIDs {
+			params = append(params, orgID)
+		}
+		q += " AND team_group.org_id IN (?" + strings.Repeat(",?", len(query.OrgIDs)-1) + ")"
+	}
+	q += " ORDER BY team_group.org_id, team_group.team_id"
+
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(q, params...).Find(&result)
+	})
+	return result, err
+}
@@ -0,0 +1,105 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationTeamGroupStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	testDB := db.InitTestDB(t)
+	store := &sqlStore{db: testDB}
+
+	err := testDB.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, o := range []*org.Org{{ID: 1, Name: "Org 1"}, {ID: 2, Name: "Org 2"}} {
+			o.Created, o.Updated = now, now
+			if _, err := sess.Insert(o); err != nil {
+				return err
+			}
+		}
+		for _, tm := range []*team.Team{{ID: 1, UID: "a", OrgID: 1, Name: "Editors"}, {ID: 2, UID: "b", OrgID: 1, Name: "Admins"}, {ID: 3, UID: "c", OrgID: 2, Name: "Editors"}} {
+			tm.Created, tm.Updated = now, now
+			if _, err := sess.Insert(tm); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	insert := func(orgID, teamID int64, groupID string) error {
+		return store.Insert(ctx, &teamsync.TeamGroup{OrgID: orgID, TeamID: teamID, GroupID: groupID, Created: now, Updated: now})
+	}
+
+	t.Run("Can add groups to a team", func(t *testing.T) {
+		require.NoError(t, insert(1, 1, "cn=editors,dc=grafana,dc=org"))
+		require.NoError(t, insert(1, 1, "cn=viewers,dc=grafana,dc=org"))
+		require.NoError(t, insert(1, 2, "cn=admins,dc=grafana,dc=org"))
+		require.NoError(t, insert(2, 3, "cn=editors,dc=grafana,dc=org"))
+
+		groups, err := store.GetByTeam(ctx, &teamsync.GetTeamGroupsQuery{OrgID: 1, TeamID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*teamsync.TeamGroupDTO{
+			{OrgID: 1, OrgName: "Org 1", TeamID: 1, TeamName: "Editors", GroupID: "cn=editors,dc=grafana,dc=org"},
+			{OrgID: 1, OrgName: "Org 1", TeamID: 1, TeamName: "Editors", GroupID: "cn=viewers,dc=grafana,dc=org"},
+		}, groups)
+	})
+
+	t.Run("Adding the same group twice fails", func(t *testing.T) {
+		err := insert(1, 1, "cn=editors,dc=grafana,dc=org")
+		require.ErrorIs(t, err, teamsync.ErrTeamGroupAlreadyAdded)
+	})
+
+	t.Run("Adding a group to a missing team fails", func(t *testing.T) {
+		err := insert(2, 1, "cn=editors,dc=grafana,dc=org")
+		require.ErrorIs(t, err, team.ErrTeamNotFound)
+	})
+
+	t.Run("Can get teams by groups case-insensitively", func(t *testing.T) {
+		groups, err := store.GetByGroups(ctx, &teamsync.GetTeamsByGroupsQuery{Groups: []string{"CN=Editors,DC=grafana,DC=org", "cn=admins,dc=grafana,dc=org"}})
+		require.NoError(t, err)
+		require.Len(t, groups, 3)
+		assert.Equal(t, []int64{1, 2, 3}, []int64{groups[0].TeamID, groups[1].TeamID, groups[2].TeamID})
+
+		groups, err = store.GetByGroups(ctx, &teamsync.GetTeamsByGroupsQuery{OrgIDs: []int64{2}, Groups: []string{"cn=editors,dc=grafana,dc=org"}})
+		require.NoError(t, err)
+		require.Equal(t, []*teamsync.TeamGroupDTO{
+			{OrgID: 2, OrgName: "Org 2", TeamID: 3, TeamName: "Editors", GroupID: "cn=editors,dc=grafana,dc=org"},
+		}, groups)
+
+		groups, err = store.GetByGroups(ctx, &teamsync.GetTeamsByGroupsQuery{OrgIDs: []int64{1}})
+		require.NoError(t, err)
+		require.Empty(t, groups)
+	})
+
+	t.Run("Can remove a group from a team", func(t *testing.T) {
+		err := store.Delete(ctx, &teamsync.RemoveTeamGroupCommand{OrgID: 1, TeamID: 1, GroupID: "cn=viewers,dc=grafana,dc=org"})
+		require.NoError(t, err)
+
+		err = store.Delete(ctx, &teamsync.RemoveTeamGroupCommand{OrgID: 1, TeamID: 1, GroupID: "cn=viewers,dc=grafana,dc=org"})
+		require.ErrorIs(t, err, teamsync.ErrTeamGroupNotFound)
+
+		groups, err := store.GetByTeam(ctx, &teamsync.GetTeamGroupsQuery{OrgID: 1, TeamID: 1})
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+	})
+}
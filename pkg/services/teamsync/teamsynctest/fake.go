@@ -0,0 +1,93 @@
+package teamsynctest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
+var _ teamsync.Service = &FakeService{}
+
+// FakeService keeps the team group mappings in memory and records the
+// synced users.
+type FakeService struct {
+	Groups        []*teamsync.TeamGroupDTO
+	SyncedUsers   []*teamsync.SyncUserTeamsCommand
+	ExpectedError error
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+func (f *FakeService) GetTeamGroups(_ context.Context, query *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroupDTO, error) {
+	if f.ExpectedError != nil {
+		return nil, f.ExpectedError
+	}
+	result := make([]*teamsync.TeamGroupDTO, 0)
+	for _, g := range f.Groups {
+		if g.OrgID == query.OrgID && g.TeamID == query.TeamID {
+			result = append(result, g)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeService) AddTeamGroup(_ context.Context, cmd *teamsync.AddTeamGroupCommand) error {
+	if f.ExpectedError != nil {
+		return f.ExpectedError
+	}
+	for _, g := range f.Groups {
+		if g.OrgID == cmd.OrgID && g.TeamID == cmd.TeamID && g.GroupID == cmd.GroupID {
+			return teamsync.ErrTeamGroupAlreadyAdded.Errorf("group %s is already mapped to team %d", cmd.GroupID, cmd.TeamID)
+		}
+	}
+	f.Groups = append(f.Groups, &teamsync.TeamGroupDTO{OrgID: cmd.OrgID, TeamID: cmd.TeamID, GroupID: cmd.GroupID})
+	return nil
+}
+
+func (f *FakeService) RemoveTeamGroup(_ context.Context, cmd *teamsync.RemoveTeamGroupCommand) error {
+	if f.ExpectedError != nil {
+		return f.ExpectedError
+	}
+	for i, g := range f.Groups {
+		if g.OrgID == cmd.OrgID && g.TeamID == cmd.TeamID && g.GroupID == cmd.GroupID {
+			f.Groups = append(f.Groups[:i], f.Groups[i+1:]...)
+			return nil
+		}
+	}
+	return teamsync.ErrTeamGroupNotFound.Errorf("group %s is not mapped to team %d", cmd.GroupID, cmd.TeamID)
+}
+
+func (f *FakeService) GetTeamsByGroups(_ context.Context, query *teamsync.GetTeamsByGroupsQuery) ([]*teamsync.TeamGroupDTO, error) {
+	if f.ExpectedError != nil {
+		return nil, f.ExpectedError
+	}
+	orgs := make(map[int64]bool, len(query.OrgIDs))
+	for _, orgID := range query.OrgIDs {
+		orgs[orgID] = true
+	}
+
+	result := make([]*teamsync.TeamGroupDTO, 0)
+	for _, g := range f.Groups {
+		if len(orgs) > 0 && !orgs[g.OrgID] {
+			continue
+		}
+		for _, group := range query.Groups {
+			if strings.EqualFold(group, g.GroupID) {
+				result = append(result, g)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeService) SyncUserTeams(_ context.Context, cmd *teamsync.SyncUserTeamsCommand) error {
+	if f.ExpectedError != nil {
+		return f.ExpectedError
+	}
+	f.SyncedUsers = append(f.SyncedUsers, cmd)
+	return nil
+}
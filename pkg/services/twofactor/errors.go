@@ -0,0 +1,17 @@
+package twofactor
+
+import (
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrCodeRequired       = errutil.Unauthorized("twofactor.code-required", errutil.WithPublicMessage("Two-factor authentication code required"))
+	ErrInvalidCode        = errutil.Unauthorized("twofactor.invalid-code", errutil.WithPublicMessage("Invalid two-factor authentication code"))
+	ErrTooManyAttempts    = errutil.TooManyRequests("twofactor.too-many-attempts", errutil.WithPublicMessage("Too many invalid two-factor authentication codes, try again later"))
+	ErrEnrollmentRequired = errutil.Forbidden("twofactor.enrollment-required", errutil.WithPublicMessage("Two-factor authentication has to be set up before continuing"))
+	ErrNotEnrolled        = errutil.BadRequest("twofactor.not-enrolled", errutil.WithPublicMessage("Two-factor authentication has not been set up"))
+	ErrAlreadyEnabled     = errutil.Conflict("twofactor.already-enabled", errutil.WithPublicMessage("Two-factor authentication is already enabled"))
+	ErrEnforced           = errutil.Forbidden("twofactor.enforced", errutil.WithPublicMessage("Two-factor authentication is enforced and can not be disabled"))
+	ErrInvalidPolicy      = errutil.BadRequest("twofactor.invalid-policy", errutil.WithPublicMessage("Invalid two-factor authentication policy"))
+	ErrDisabled           = errutil.NotFound("twofactor.disabled", errutil.WithPublicMessage("Two-factor authentication is not enabled"))
+)
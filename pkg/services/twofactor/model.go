@@ -0,0 +1,131 @@
+package twofactor
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// UserSecret is the TOTP secret of a user. The secret is stored encrypted and
+// only accepted for logins once Enabled is set.
+type UserSecret struct {
+	ID     int64  `xorm:"pk autoincr 'id'"`
+	UserID int64  `xorm:"user_id"`
+	Secret string `xorm:"secret"`
+	// Enabled is set once the user has verified a code for the secret.
+	Enabled bool `xorm:"enabled"`
+	// LastUsedStep is the time step of the last accepted code, codes from the
+	// same or an earlier step are rejected to prevent replays.
+	LastUsedStep int64     `xorm:"last_used_step"`
+	Created      time.Time `xorm:"created"`
+	Updated      time.Time `xorm:"updated"`
+}
+
+func (UserSecret) TableName() string {
+	return "user_two_factor"
+}
+
+// RecoveryCode is a hashed, single use code that can be used instead of a
+// TOTP code.
+type RecoveryCode struct {
+	ID       int64     `xorm:"pk autoincr 'id'"`
+	UserID   int64     `xorm:"user_id"`
+	CodeHash string    `xorm:"code_hash"`
+	Created  time.Time `xorm:"created"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "user_two_factor_recovery_code"
+}
+
+// OrgPolicy enforces two-factor authentication for the members of an
+// organization with at least MinRole.
+type OrgPolicy struct {
+	ID       int64        `xorm:"pk autoincr 'id'" json:"-"`
+	OrgID    int64        `xorm:"org_id" json:"orgId"`
+	Enforced bool         `xorm:"enforced" json:"enforced"`
+	MinRole  org.RoleType `xorm:"min_role" json:"minRole"`
+	Updated  time.Time    `xorm:"updated" json:"updated"`
+}
+
+func (OrgPolicy) TableName() string {
+	return "org_two_factor_policy"
+}
+
+// Status is the two-factor authentication state of a user.
+type Status struct {
+	// Enabled is true when the user has an activated secret.
+	Enabled bool `json:"enabled"`
+	// Enforced is true when the user has to set up two-factor authentication,
+	// either through the server configuration or an organization policy.
+	Enforced               bool `json:"enforced"`
+	RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"`
+}
+
+// Enrollment holds the secret of a pending enrollment. URL is an otpauth://
+// URI meant to be rendered as a QR code by authenticator apps.
+type Enrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"recoveryCodes"`
+}
+
+type GetStatusQuery struct {
+	UserID int64
+}
+
+type EnrollCommand struct {
+	UserID int64
+	Login  string
+}
+
+type ActivateCommand struct {
+	UserID int64  `json:"-"`
+	Code   string `json:"code"`
+}
+
+type DisableCommand struct {
+	UserID    int64  `json:"-"`
+	Login     string `json:"-"`
+	IPAddress string `json:"-"`
+	Code      string `json:"code"`
+}
+
+type RegenerateRecoveryCodesCommand struct {
+	UserID    int64  `json:"-"`
+	Login     string `json:"-"`
+	IPAddress string `json:"-"`
+	Code      string `json:"code"`
+}
+
+type ResetCommand struct {
+	UserID int64
+}
+
+type VerifyCommand struct {
+	UserID int64
+	Code   string
+}
+
+type GetOrgPolicyQuery struct {
+	OrgID int64
+}
+
+type SetOrgPolicyCommand struct {
+	OrgID    int64        `json:"-"`
+	Enforced bool         `json:"enforced"`
+	MinRole  org.RoleType `json:"minRole"`
+}
+
+func (cmd *SetOrgPolicyCommand) Validate() error {
+	if cmd.MinRole == "" {
+		cmd.MinRole = org.RoleViewer
+	}
+	if !cmd.MinRole.IsValid() || cmd.MinRole == org.RoleNone {
+		return ErrInvalidPolicy.Errorf("invalid min role: %s", cmd.MinRole)
+	}
+	return nil
+}
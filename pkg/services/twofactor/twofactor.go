@@ -0,0 +1,31 @@
+package twofactor
+
+import (
+	"context"
+)
+
+// Service manages TOTP based two-factor authentication for users that log in
+// with a Grafana password.
+type Service interface {
+	// GetStatus returns whether the user has enabled two-factor authentication
+	// and whether it is enforced for them.
+	GetStatus(context.Context, *GetStatusQuery) (*Status, error)
+
+	// Enroll generates a new secret for the user. The secret is only used for
+	// logins once it has been activated with a valid code.
+	Enroll(context.Context, *EnrollCommand) (*Enrollment, error)
+	Activate(context.Context, *ActivateCommand) (*RecoveryCodes, error)
+	Disable(context.Context, *DisableCommand) error
+	RegenerateRecoveryCodes(context.Context, *RegenerateRecoveryCodesCommand) (*RecoveryCodes, error)
+
+	// Reset removes the second factor of a user without requiring a code, it
+	// is meant to be used by administrators when a user lost their device.
+	Reset(context.Context, *ResetCommand) error
+
+	// Verify checks a TOTP or recovery code for a user with two-factor
+	// authentication enabled. Recovery codes can only be used once.
+	Verify(context.Context, *VerifyCommand) error
+
+	GetOrgPolicy(context.Context, *GetOrgPolicyQuery) (*OrgPolicy, error)
+	SetOrgPolicy(context.Context, *SetOrgPolicyCommand) error
+}
@@ -0,0 +1,338 @@
+package twofactorimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints() {
+	authorize := ac.Middleware(s.accessControl)
+	userIDScope := ac.Scope("global.users", "id", ac.Parameter(":id"))
+
+	s.routeRegister.Group("/api/user/two-factor", func(userRoute routing.RouteRegister) {
+		userRoute.Get("/", routing.Wrap(s.getStatusHandler))
+		userRoute.Post("/enroll", routing.Wrap(s.enrollHandler))
+		userRoute.Post("/activate", routing.Wrap(s.activateHandler))
+		userRoute.Post("/disable", routing.Wrap(s.disableHandler))
+		userRoute.Post("/recovery-codes", routing.Wrap(s.regenerateRecoveryCodesHandler))
+	}, middleware.ReqSignedInNoAnonymous)
+
+	s.routeRegister.Group("/api/admin/users/:id/two-factor", func(adminRoute routing.RouteRegister) {
+		adminRoute.Get("/", authorize(ac.EvalPermission(ac.ActionUsersRead, userIDScope)), routing.Wrap(s.adminGetStatusHandler))
+		adminRoute.Delete("/", authorize(ac.EvalPermission(ac.ActionUsersPasswordUpdate, userIDScope)), routing.Wrap(s.adminResetHandler))
+	}, middleware.ReqSignedIn)
+
+	s.routeRegister.Group("/api/org/two-factor", func(orgRoute routing.RouteRegister) {
+		orgRoute.Get("/", authorize(ac.EvalPermission(ac.ActionOrgsRead)), routing.Wrap(s.getOrgPolicyHandler))
+		orgRoute.Put("/", authorize(ac.EvalPermission(ac.ActionOrgsWrite)), routing.Wrap(s.setOrgPolicyHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// swagger:route GET /user/two-factor signed_in_user getTwoFactorStatus
+//
+// Get the two-factor authentication status of the signed in user.
+//
+// Responses:
+// 200: twoFactorStatusResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) getStatusHandler(c *contextmodel.ReqContext) response.Response {
+	userID, errResponse := getUserID(c)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	status, err := s.GetStatus(c.Req.Context(), &twofactor.GetStatusQuery{UserID: userID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get two-factor authentication status", err)
+	}
+	return response.JSON(http.StatusOK, status)
+}
+
+// swagger:route POST /user/two-factor/enroll signed_in_user enrollTwoFactor
+//
+// Start the two-factor authentication enrollment of the signed in user.
+//
+// The returned otpauth URL can be rendered as a QR code for authenticator apps. The secret is only used for logins once it has been activated.
+//
+// Responses:
+// 200: twoFactorEnrollmentResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) enrollHandler(c *contextmodel.ReqContext) response.Response {
+	userID, errResponse := getUserID(c)
+	if errResponse != nil {
+		return errResponse
+	}
+
+	enrollment, err := s.Enroll(c.Req.Context(), &twofactor.EnrollCommand{UserID: userID, Login: c.SignedInUser.GetLogin()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enroll two-factor authentication", err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+// swagger:route POST /user/two-factor/activate signed_in_user activateTwoFactor
+//
+// Activate two-factor authentication for the signed in user with a code from the authenticator app.
+//
+// The recovery codes are only returned once.
+//
+// Responses:
+// 200: twoFactorRecoveryCodesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 409: conflictError
+// 500: internalServerError
+func (s *Service) activateHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := twofactor.ActivateCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	userID, errResponse := getUserID(c)
+	if errResponse != nil {
+		return errResponse
+	}
+	cmd.UserID = userID
+
+	codes, err := s.Activate(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to activate two-factor authentication", err)
+	}
+	return response.JSON(http.StatusOK, codes)
+}
+
+// swagger:route POST /user/two-factor/disable signed_in_user disableTwoFactor
+//
+// Disable two-factor authentication for the signed in user.
+//
+// Requires a code from the authenticator app or a recovery code. Not allowed when two-factor authentication is enforced for the user.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) disableHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := twofactor.DisableCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	userID, errResponse := getUserID(c)
+	if errResponse != nil {
+		return errResponse
+	}
+	cmd.UserID = userID
+	cmd.Login = c.SignedInUser.GetLogin()
+	cmd.IPAddress = web.RemoteAddr(c.Req)
+
+	if err := s.Disable(c.Req.Context(), &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to disable two-factor authentication", err)
+	}
+	return response.Success("Two-factor authentication disabled")
+}
+
+// swagger:route POST /user/two-factor/recovery-codes signed_in_user regenerateTwoFactorRecoveryCodes
+//
+// Replace the recovery codes of the signed in user.
+//
+// Requires a code from the authenticator app or a recovery code.
+//
+// Responses:
+// 200: twoFactorRecoveryCodesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) regenerateRecoveryCodesHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := twofactor.RegenerateRecoveryCodesCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	userID, errResponse := getUserID(c)
+	if errResponse != nil {
+		return errResponse
+	}
+	cmd.UserID = userID
+	cmd.Login = c.SignedInUser.GetLogin()
+	cmd.IPAddress = web.RemoteAddr(c.Req)
+
+	codes, err := s.RegenerateRecoveryCodes(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to generate recovery codes", err)
+	}
+	return response.JSON(http.StatusOK, codes)
+}
+
+// swagger:route GET /admin/users/{user_id}/two-factor admin_users adminGetUserTwoFactorStatus
+//
+// Get the two-factor authentication status of a user.
+//
+// Responses:
+// 200: twoFactorStatusResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) adminGetStatusHandler(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	status, err := s.GetStatus(c.Req.Context(), &twofactor.GetStatusQuery{UserID: userID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get two-factor authentication status", err)
+	}
+	return response.JSON(http.StatusOK, status)
+}
+
+// swagger:route DELETE /admin/users/{user_id}/two-factor admin_users adminResetUserTwoFactor
+//
+// Reset the two-factor authentication of a user.
+//
+// Removes the secret and recovery codes, the user has to enroll again if two-factor authentication is enforced.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) adminResetHandler(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := s.Reset(c.Req.Context(), &twofactor.ResetCommand{UserID: userID}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to reset two-factor authentication", err)
+	}
+	return response.Success("Two-factor authentication reset")
+}
+
+// swagger:route GET /org/two-factor org getOrgTwoFactorPolicy
+//
+// Get the two-factor authentication policy of the current organization.
+//
+// Responses:
+// 200: orgTwoFactorPolicyResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) getOrgPolicyHandler(c *contextmodel.ReqContext) response.Response {
+	policy, err := s.GetOrgPolicy(c.Req.Context(), &twofactor.GetOrgPolicyQuery{OrgID: c.SignedInUser.GetOrgID()})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get two-factor authentication policy", err)
+	}
+	return response.JSON(http.StatusOK, policy)
+}
+
+// swagger:route PUT /org/two-factor org setOrgTwoFactorPolicy
+//
+// Enforce two-factor authentication for the members of the current organization with at least the given role.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (s *Service) setOrgPolicyHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := twofactor.SetOrgPolicyCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+
+	if err := s.SetOrgPolicy(c.Req.Context(), &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update two-factor authentication policy", err)
+	}
+	return response.Success("Two-factor authentication policy updated")
+}
+
+func getUserID(c *contextmodel.ReqContext) (int64, response.Response) {
+	namespace, identifier := c.SignedInUser.GetNamespacedID()
+	if namespace != identity.NamespaceUser {
+		return 0, response.Error(http.StatusForbidden, "Endpoint only available for users", nil)
+	}
+
+	userID, err := identity.IntIdentifier(namespace, identifier)
+	if err != nil {
+		return 0, response.Error(http.StatusInternalServerError, "Failed to parse user id", err)
+	}
+	return userID, nil
+}
+
+// swagger:parameters activateTwoFactor
+type ActivateTwoFactorParams struct {
+	// in:body
+	// required:true
+	Body twofactor.ActivateCommand
+}
+
+// swagger:parameters disableTwoFactor
+type DisableTwoFactorParams struct {
+	// in:body
+	// required:true
+	Body twofactor.DisableCommand
+}
+
+// swagger:parameters regenerateTwoFactorRecoveryCodes
+type RegenerateTwoFactorRecoveryCodesParams struct {
+	// in:body
+	// required:true
+	Body twofactor.RegenerateRecoveryCodesCommand
+}
+
+// swagger:parameters adminGetUserTwoFactorStatus adminResetUserTwoFactor
+type AdminUserTwoFactorParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+}
+
+// swagger:parameters setOrgTwoFactorPolicy
+type SetOrgTwoFactorPolicyParams struct {
+	// in:body
+	// required:true
+	Body twofactor.SetOrgPolicyCommand
+}
+
+// swagger:response twoFactorStatusResponse
+type TwoFactorStatusResponse struct {
+	// in: body
+	Body *twofactor.Status `json:"body"`
+}
+
+// swagger:response twoFactorEnrollmentResponse
+type TwoFactorEnrollmentResponse struct {
+	// in: body
+	Body *twofactor.Enrollment `json:"body"`
+}
+
+// swagger:response twoFactorRecoveryCodesResponse
+type TwoFactorRecoveryCodesResponse struct {
+	// in: body
+	Body *twofactor.RecoveryCodes `json:"body"`
+}
+
+// swagger:response orgTwoFactorPolicyResponse
+type OrgTwoFactorPolicyResponse struct {
+	// in: body
+	Body *twofactor.OrgPolicy `json:"body"`
+}
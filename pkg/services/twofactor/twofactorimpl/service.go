@@ -0,0 +1,392 @@
+package twofactorimpl
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// statusCacheTTL bounds how long a status is cached. The status is checked on
+// every API request of a session, changes made on other instances are picked
+// up once the cached value expires.
+const statusCacheTTL = time.Minute
+
+type Service struct {
+	cfg             *setting.Cfg
+	store           store
+	secretsService  secrets.Service
+	userService     user.Service
+	orgService      org.Service
+	authInfoService login.AuthInfoService
+	loginAttempts   loginattempt.Service
+	accessControl   accesscontrol.AccessControl
+	routeRegister   routing.RouteRegister
+	cache           *localcache.CacheService
+	log             log.Logger
+	now             func() time.Time
+}
+
+var _ twofactor.Service = &Service{}
+
+func ProvideService(cfg *setting.Cfg, db db.DB, secretsService secrets.Service, userService user.Service, orgService org.Service,
+	authInfoService login.AuthInfoService, loginAttempts loginattempt.Service, accessControl accesscontrol.AccessControl, routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		cfg:             cfg,
+		store:           &sqlStore{db: db},
+		secretsService:  secretsService,
+		userService:     userService,
+		orgService:      orgService,
+		authInfoService: authInfoService,
+		loginAttempts:   loginAttempts,
+		accessControl:   accessControl,
+		routeRegister:   routeRegister,
+		cache:           localcache.New(statusCacheTTL, 2*statusCacheTTL),
+		log:             log.New("twofactor"),
+		now:             time.Now,
+	}
+
+	orgService.RegisterDelete("DELETE FROM org_two_factor_policy WHERE org_id = ?")
+
+	if cfg.TwoFactorAuthEnabled {
+		s.registerAPIEndpoints()
+	}
+
+	return s
+}
+
+func (s *Service) GetStatus(ctx context.Context, query *twofactor.GetStatusQuery) (*twofactor.Status, error) {
+	if !s.cfg.TwoFactorAuthEnabled {
+		return &twofactor.Status{}, nil
+	}
+
+	key := statusCacheKey(query.UserID)
+	if cached, ok := s.cache.Get(key); ok {
+		status := *cached.(*twofactor.Status)
+		return &status, nil
+	}
+
+	status := &twofactor.Status{}
+	secret, err := s.store.GetSecret(ctx, query.UserID)
+	if err != nil && !errors.Is(err, twofactor.ErrNotEnrolled) {
+		return nil, err
+	}
+	if secret != nil && secret.Enabled {
+		status.Enabled = true
+		count, err := s.store.CountRecoveryCodes(ctx, query.UserID)
+		if err != nil {
+			return nil, err
+		}
+		status.RecoveryCodesRemaining = int(count)
+	}
+
+	status.Enforced, err = s.isEnforced(ctx, query.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	cached := *status
+	s.cache.Set(key, &cached, statusCacheTTL)
+	return status, nil
+}
+
+// isEnforced returns true when the server configuration or a policy of one
+// of the user's organizations requires a second factor. Only users logging in
+// with a Grafana password are affected, the second factor of users from an
+// external identity provider is handled by the provider.
+func (s *Service) isEnforced(ctx context.Context, userID int64) (bool, error) {
+	_, err := s.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{UserId: userID})
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, user.ErrUserNotFound) {
+		return false, err
+	}
+
+	if s.cfg.TwoFactorAuthEnforceServerAdmins {
+		usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+		if err != nil {
+			return false, err
+		}
+		if usr.IsAdmin {
+			return true, nil
+		}
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+
+	enforcedRole := org.RoleType(s.cfg.TwoFactorAuthEnforcedRole)
+	roles := make(map[int64]org.RoleType, len(orgs))
+	orgIDs := make([]int64, 0, len(orgs))
+	for _, o := range orgs {
+		if enforcedRole != "" && o.Role.Includes(enforcedRole) {
+			return true, nil
+		}
+		roles[o.OrgID] = o.Role
+		orgIDs = append(orgIDs, o.OrgID)
+	}
+
+	policies, err := s.store.GetOrgPolicies(ctx, orgIDs)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range policies {
+		if p.Enforced && roles[p.OrgID].Includes(p.MinRole) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) Enroll(ctx context.Context, cmd *twofactor.EnrollCommand) (*twofactor.Enrollment, error) {
+	if !s.cfg.TwoFactorAuthEnabled {
+		return nil, twofactor.ErrDisabled.Errorf("two-factor authentication is disabled")
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.secretsService.Encrypt(ctx, []byte(secret), secrets.WithoutScope())
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	err = s.store.SaveSecret(ctx, &twofactor.UserSecret{
+		UserID:  cmd.UserID,
+		Secret:  base64.StdEncoding.EncodeToString(encrypted),
+		Created: now,
+		Updated: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(cmd.UserID)
+
+	return &twofactor.Enrollment{
+		Secret: secret,
+		URL:    otpauthURL(s.cfg.TwoFactorAuthIssuer, cmd.Login, secret),
+	}, nil
+}
+
+func (s *Service) Activate(ctx context.Context, cmd *twofactor.ActivateCommand) (*twofactor.RecoveryCodes, error) {
+	secret, err := s.store.GetSecret(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if secret.Enabled {
+		return nil, twofactor.ErrAlreadyEnabled.Errorf("user %d already has two-factor authentication enabled", cmd.UserID)
+	}
+
+	plain, err := s.decryptSecret(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := validateTOTP(plain, cmd.Code, s.now())
+	if !ok {
+		return nil, twofactor.ErrInvalidCode.Errorf("invalid code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Activate(ctx, cmd.UserID, step, hashes); err != nil {
+		return nil, err
+	}
+	s.invalidate(cmd.UserID)
+
+	s.log.FromContext(ctx).Info("Two-factor authentication enabled", "userID", cmd.UserID)
+	return &twofactor.RecoveryCodes{Codes: codes}, nil
+}
+
+func (s *Service) Disable(ctx context.Context, cmd *twofactor.DisableCommand) error {
+	status, err := s.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: cmd.UserID})
+	if err != nil {
+		return err
+	}
+	if status.Enforced {
+		return twofactor.ErrEnforced.Errorf("two-factor authentication is enforced for user %d", cmd.UserID)
+	}
+
+	if err := s.verifyAttempt(ctx, cmd.UserID, cmd.Login, cmd.IPAddress, cmd.Code); err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, cmd.UserID); err != nil {
+		return err
+	}
+	s.invalidate(cmd.UserID)
+
+	s.log.FromContext(ctx).Info("Two-factor authentication disabled", "userID", cmd.UserID)
+	return nil
+}
+
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, cmd *twofactor.RegenerateRecoveryCodesCommand) (*twofactor.RecoveryCodes, error) {
+	if err := s.verifyAttempt(ctx, cmd.UserID, cmd.Login, cmd.IPAddress, cmd.Code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.ReplaceRecoveryCodes(ctx, cmd.UserID, hashes); err != nil {
+		return nil, err
+	}
+	s.invalidate(cmd.UserID)
+
+	return &twofactor.RecoveryCodes{Codes: codes}, nil
+}
+
+func (s *Service) Reset(ctx context.Context, cmd *twofactor.ResetCommand) error {
+	if err := s.store.Delete(ctx, cmd.UserID); err != nil {
+		return err
+	}
+	s.invalidate(cmd.UserID)
+
+	s.log.FromContext(ctx).Info("Two-factor authentication reset", "userID", cmd.UserID)
+	return nil
+}
+
+func (s *Service) Verify(ctx context.Context, cmd *twofactor.VerifyCommand) error {
+	secret, err := s.store.GetSecret(ctx, cmd.UserID)
+	if err != nil {
+		return err
+	}
+	if !secret.Enabled {
+		return twofactor.ErrNotEnrolled.Errorf("two-factor authentication is not activated for user %d", cmd.UserID)
+	}
+
+	if looksLikeRecoveryCode(cmd.Code) {
+		ok, err := s.store.UseRecoveryCode(ctx, cmd.UserID, hashRecoveryCode(cmd.Code))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return twofactor.ErrInvalidCode.Errorf("invalid recovery code")
+		}
+		s.invalidate(cmd.UserID)
+		s.log.FromContext(ctx).Info("Recovery code used", "userID", cmd.UserID)
+		return nil
+	}
+
+	plain, err := s.decryptSecret(ctx, secret)
+	if err != nil {
+		return err
+	}
+	step, ok := validateTOTP(plain, cmd.Code, s.now())
+	if !ok || step <= secret.LastUsedStep {
+		return twofactor.ErrInvalidCode.Errorf("invalid code")
+	}
+
+	// another request could have used the same code since the secret was read
+	ok, err = s.store.UseStep(ctx, cmd.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return twofactor.ErrInvalidCode.Errorf("code has already been used")
+	}
+	return nil
+}
+
+// verifyAttempt verifies the code of a signed in user. Invalid codes count as
+// failed logins, so guessing codes is rate limited like logging in.
+func (s *Service) verifyAttempt(ctx context.Context, userID int64, login, ipAddress, code string) error {
+	ok, err := s.loginAttempts.Validate(ctx, login)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return twofactor.ErrTooManyAttempts.Errorf("too many consecutive incorrect attempts for user %d", userID)
+	}
+
+	if err := s.Verify(ctx, &twofactor.VerifyCommand{UserID: userID, Code: code}); err != nil {
+		if errors.Is(err, twofactor.ErrInvalidCode) {
+			_ = s.loginAttempts.Add(ctx, login, ipAddress)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Service) GetOrgPolicy(ctx context.Context, query *twofactor.GetOrgPolicyQuery) (*twofactor.OrgPolicy, error) {
+	policies, err := s.store.GetOrgPolicies(ctx, []int64{query.OrgID})
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return &twofactor.OrgPolicy{OrgID: query.OrgID, MinRole: org.RoleViewer}, nil
+	}
+	return policies[0], nil
+}
+
+func (s *Service) SetOrgPolicy(ctx context.Context, cmd *twofactor.SetOrgPolicyCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+
+	err := s.store.SetOrgPolicy(ctx, &twofactor.OrgPolicy{
+		OrgID:    cmd.OrgID,
+		Enforced: cmd.Enforced,
+		MinRole:  cmd.MinRole,
+		Updated:  s.now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// the policy affects the status of every member of the organization
+	s.cache.Flush()
+	return nil
+}
+
+func (s *Service) decryptSecret(ctx context.Context, secret *twofactor.UserSecret) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(secret.Secret)
+	if err != nil {
+		return "", err
+	}
+	plain, err := s.secretsService.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt two-factor secret: %w", err)
+	}
+	return string(plain), nil
+}
+
+func (s *Service) invalidate(userID int64) {
+	s.cache.Delete(statusCacheKey(userID))
+}
+
+func statusCacheKey(userID int64) string {
+	return fmt.Sprintf("twofactor-status-%d", userID)
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
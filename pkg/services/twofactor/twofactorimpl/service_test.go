@@ -0,0 +1,289 @@
+package twofactorimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/login/authinfotest"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+const testUserID = int64(1)
+
+type testEnv struct {
+	service     *Service
+	orgService  *orgtest.FakeOrgService
+	userService *usertest.FakeUserService
+	authInfo    *authinfotest.FakeService
+	attempts    *loginattempttest.MockLoginAttemptService
+	now         time.Time
+}
+
+func setupTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.TwoFactorAuthEnabled = true
+	cfg.TwoFactorAuthIssuer = "Grafana"
+
+	env := &testEnv{
+		orgService:  &orgtest.FakeOrgService{ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleViewer}}},
+		userService: &usertest.FakeUserService{ExpectedUser: &user.User{ID: testUserID}},
+		authInfo:    &authinfotest.FakeService{ExpectedError: user.ErrUserNotFound},
+		attempts:    &loginattempttest.MockLoginAttemptService{ExpectedValid: true},
+		now:         time.Unix(1700000000, 0),
+	}
+	env.service = &Service{
+		cfg:             cfg,
+		store:           &sqlStore{db: db.InitTestDB(t)},
+		secretsService:  fakes.NewFakeSecretsService(),
+		userService:     env.userService,
+		orgService:      env.orgService,
+		authInfoService: env.authInfo,
+		loginAttempts:   env.attempts,
+		cache:           localcache.New(statusCacheTTL, 2*statusCacheTTL),
+		log:             log.NewNopLogger(),
+		now:             func() time.Time { return env.now },
+	}
+	return env
+}
+
+func (env *testEnv) code(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := totpCode(secret, totpStep(env.now))
+	require.NoError(t, err)
+	return code
+}
+
+func (env *testEnv) enroll(t *testing.T) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrollment, err := env.service.Enroll(ctx, &twofactor.EnrollCommand{UserID: testUserID, Login: "alice"})
+	require.NoError(t, err)
+
+	codes, err := env.service.Activate(ctx, &twofactor.ActivateCommand{UserID: testUserID, Code: env.code(t, enrollment.Secret)})
+	require.NoError(t, err)
+	return enrollment.Secret, codes.Codes
+}
+
+func TestIntegrationTwoFactorService_Enrollment(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	env := setupTestEnv(t)
+
+	enrollment, err := env.service.Enroll(ctx, &twofactor.EnrollCommand{UserID: testUserID, Login: "alice"})
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URL, "otpauth://totp/Grafana:alice?")
+	assert.Contains(t, enrollment.URL, "secret="+enrollment.Secret)
+
+	status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+	require.NoError(t, err)
+	assert.False(t, status.Enabled, "pending enrollments are not enabled")
+
+	_, err = env.service.Activate(ctx, &twofactor.ActivateCommand{UserID: testUserID, Code: "000000"})
+	assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+
+	codes, err := env.service.Activate(ctx, &twofactor.ActivateCommand{UserID: testUserID, Code: env.code(t, enrollment.Secret)})
+	require.NoError(t, err)
+	assert.Len(t, codes.Codes, recoveryCodeCount)
+
+	status, err = env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, recoveryCodeCount, status.RecoveryCodesRemaining)
+
+	_, err = env.service.Enroll(ctx, &twofactor.EnrollCommand{UserID: testUserID, Login: "alice"})
+	assert.ErrorIs(t, err, twofactor.ErrAlreadyEnabled)
+}
+
+func TestIntegrationTwoFactorService_Verify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	env := setupTestEnv(t)
+	secret, recoveryCodes := env.enroll(t)
+
+	t.Run("rejects the code used for activation", func(t *testing.T) {
+		err := env.service.Verify(ctx, &twofactor.VerifyCommand{UserID: testUserID, Code: env.code(t, secret)})
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+	})
+
+	t.Run("accepts a code once", func(t *testing.T) {
+		env.now = env.now.Add(totpPeriod)
+		code := env.code(t, secret)
+		require.NoError(t, env.service.Verify(ctx, &twofactor.VerifyCommand{UserID: testUserID, Code: code}))
+
+		err := env.service.Verify(ctx, &twofactor.VerifyCommand{UserID: testUserID, Code: code})
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+	})
+
+	t.Run("accepts a recovery code once", func(t *testing.T) {
+		require.NoError(t, env.service.Verify(ctx, &twofactor.VerifyCommand{UserID: testUserID, Code: recoveryCodes[0]}))
+
+		err := env.service.Verify(ctx, &twofactor.VerifyCommand{UserID: testUserID, Code: recoveryCodes[0]})
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+
+		status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.Equal(t, recoveryCodeCount-1, status.RecoveryCodesRemaining)
+	})
+
+	t.Run("regenerating recovery codes invalidates the old ones", func(t *testing.T) {
+		codes, err := env.service.RegenerateRecoveryCodes(ctx, &twofactor.RegenerateRecoveryCodesCommand{UserID: testUserID, Code: recoveryCodes[1]})
+		require.NoError(t, err)
+		assert.Len(t, codes.Codes, recoveryCodeCount)
+
+		err = env.service.Verify(ctx, &twofactor.VerifyCommand{UserID: testUserID, Code: recoveryCodes[2]})
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+		require.NoError(t, env.service.Verify(ctx, &twofactor.VerifyCommand{UserID: testUserID, Code: codes.Codes[0]}))
+	})
+
+	t.Run("invalid codes count as failed login attempts", func(t *testing.T) {
+		env.attempts.AddCalled = false
+		_, err := env.service.RegenerateRecoveryCodes(ctx, &twofactor.RegenerateRecoveryCodesCommand{UserID: testUserID, Login: "user", Code: "000000"})
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+		assert.True(t, env.attempts.AddCalled)
+
+		env.attempts.AddCalled = false
+		err = env.service.Disable(ctx, &twofactor.DisableCommand{UserID: testUserID, Login: "user", Code: "000000"})
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+		assert.True(t, env.attempts.AddCalled)
+	})
+
+	t.Run("codes are not verified after too many failed attempts", func(t *testing.T) {
+		env.attempts.ExpectedValid = false
+		t.Cleanup(func() { env.attempts.ExpectedValid = true })
+
+		env.now = env.now.Add(totpPeriod)
+		err := env.service.Disable(ctx, &twofactor.DisableCommand{UserID: testUserID, Login: "user", Code: env.code(t, secret)})
+		assert.ErrorIs(t, err, twofactor.ErrTooManyAttempts)
+
+		_, err = env.service.RegenerateRecoveryCodes(ctx, &twofactor.RegenerateRecoveryCodesCommand{UserID: testUserID, Login: "user", Code: env.code(t, secret)})
+		assert.ErrorIs(t, err, twofactor.ErrTooManyAttempts)
+	})
+
+	t.Run("disable removes the second factor", func(t *testing.T) {
+		env.now = env.now.Add(totpPeriod)
+		require.NoError(t, env.service.Disable(ctx, &twofactor.DisableCommand{UserID: testUserID, Code: env.code(t, secret)}))
+
+		status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.False(t, status.Enabled)
+
+		err = env.service.Verify(ctx, &twofactor.VerifyCommand{UserID: testUserID, Code: env.code(t, secret)})
+		assert.ErrorIs(t, err, twofactor.ErrNotEnrolled)
+	})
+}
+
+func TestIntegrationTwoFactorService_Enforcement(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+
+	t.Run("is not enforced by default", func(t *testing.T) {
+		env := setupTestEnv(t)
+		status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.False(t, status.Enforced)
+	})
+
+	t.Run("is enforced by an org policy for members with the min role", func(t *testing.T) {
+		env := setupTestEnv(t)
+		require.NoError(t, env.service.SetOrgPolicy(ctx, &twofactor.SetOrgPolicyCommand{OrgID: 1, Enforced: true, MinRole: org.RoleEditor}))
+
+		status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.False(t, status.Enforced, "viewers are below the min role")
+
+		require.NoError(t, env.service.SetOrgPolicy(ctx, &twofactor.SetOrgPolicyCommand{OrgID: 1, Enforced: true}))
+		status, err = env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.True(t, status.Enforced)
+
+		policy, err := env.service.GetOrgPolicy(ctx, &twofactor.GetOrgPolicyQuery{OrgID: 1})
+		require.NoError(t, err)
+		assert.True(t, policy.Enforced)
+		assert.Equal(t, org.RoleViewer, policy.MinRole)
+	})
+
+	t.Run("is enforced by the configured role", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.service.cfg.TwoFactorAuthEnforcedRole = string(org.RoleEditor)
+		env.orgService.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleViewer}, {OrgID: 2, Role: org.RoleAdmin}}
+
+		status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.True(t, status.Enforced)
+	})
+
+	t.Run("is enforced for server admins", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.service.cfg.TwoFactorAuthEnforceServerAdmins = true
+		env.userService.ExpectedUser = &user.User{ID: testUserID, IsAdmin: true}
+
+		status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.True(t, status.Enforced)
+	})
+
+	t.Run("is not enforced for users of an external identity provider", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.service.cfg.TwoFactorAuthEnforcedRole = string(org.RoleViewer)
+		env.authInfo.ExpectedError = nil
+		env.authInfo.ExpectedUserAuth = &login.UserAuth{UserId: testUserID, AuthModule: login.GenericOAuthModule}
+
+		status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.False(t, status.Enforced)
+	})
+
+	t.Run("enforced users can not disable it but can be reset", func(t *testing.T) {
+		env := setupTestEnv(t)
+		env.service.cfg.TwoFactorAuthEnforcedRole = string(org.RoleViewer)
+		secret, _ := env.enroll(t)
+
+		env.now = env.now.Add(totpPeriod)
+		err := env.service.Disable(ctx, &twofactor.DisableCommand{UserID: testUserID, Code: env.code(t, secret)})
+		assert.ErrorIs(t, err, twofactor.ErrEnforced)
+
+		require.NoError(t, env.service.Reset(ctx, &twofactor.ResetCommand{UserID: testUserID}))
+		status, err := env.service.GetStatus(ctx, &twofactor.GetStatusQuery{UserID: testUserID})
+		require.NoError(t, err)
+		assert.False(t, status.Enabled)
+		assert.True(t, status.Enforced)
+	})
+
+	t.Run("rejects invalid policies", func(t *testing.T) {
+		env := setupTestEnv(t)
+		err := env.service.SetOrgPolicy(ctx, &twofactor.SetOrgPolicyCommand{OrgID: 1, Enforced: true, MinRole: org.RoleNone})
+		assert.ErrorIs(t, err, twofactor.ErrInvalidPolicy)
+	})
+}
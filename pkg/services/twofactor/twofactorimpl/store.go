@@ -0,0 +1,197 @@
+package twofactorimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+)
+
+type store interface {
+	GetSecret(ctx context.Context, userID int64) (*twofactor.UserSecret, error)
+	// SaveSecret stores a pending secret, replacing any previous pending one.
+	SaveSecret(ctx context.Context, secret *twofactor.UserSecret) error
+	// Activate enables the secret of a user and replaces its recovery codes.
+	Activate(ctx context.Context, userID, step int64, codeHashes []string) error
+	// UseStep records step as the last used step, it returns false when a
+	// code for the same or a later step has already been used.
+	UseStep(ctx context.Context, userID, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error
+	// UseRecoveryCode removes a recovery code, it returns false when the code
+	// does not exist.
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID int64) (int64, error)
+	Delete(ctx context.Context, userID int64) error
+
+	GetOrgPolicies(ctx context.Context, orgIDs []int64) ([]*twofactor.OrgPolicy, error)
+	SetOrgPolicy(ctx context.Context, policy *twofactor.OrgPolicy) error
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+var _ store = &sqlStore{}
+
+func (s *sqlStore) GetSecret(ctx context.Context, userID int64) (*twofactor.UserSecret, error) {
+	var secret twofactor.UserSecret
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("user_id = ?", userID).Get(&secret)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return twofactor.ErrNotEnrolled.Errorf("no secret found for user %d", userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+func (s *sqlStore) SaveSecret(ctx context.Context, secret *twofactor.UserSecret) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing twofactor.UserSecret
+		has, err := sess.Where("user_id = ?", secret.UserID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if has && existing.Enabled {
+			return twofactor.ErrAlreadyEnabled.Errorf("user %d already has two-factor authentication enabled", secret.UserID)
+		}
+		if has {
+			if _, err := sess.Exec("DELETE FROM user_two_factor WHERE user_id = ?", secret.UserID); err != nil {
+				return err
+			}
+		}
+		_, err = sess.Insert(secret)
+		return err
+	})
+}
+
+func (s *sqlStore) Activate(ctx context.Context, userID, step int64, codeHashes []string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_two_factor SET enabled = ?, last_used_step = ?, updated = ? WHERE user_id = ? AND enabled = ?",
+			true, step, time.Now(), userID, false)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return twofactor.ErrAlreadyEnabled.Errorf("user %d has no pending enrollment", userID)
+		}
+		return replaceRecoveryCodes(sess, userID, codeHashes)
+	})
+}
+
+func (s *sqlStore) UseStep(ctx context.Context, userID, step int64) (bool, error) {
+	var ok bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_two_factor SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?", step, userID, step)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		ok = affected > 0
+		return err
+	})
+	return ok, err
+}
+
+func (s *sqlStore) ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		return replaceRecoveryCodes(sess, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(sess *db.Session, userID int64, codeHashes []string) error {
+	if _, err := sess.Exec("DELETE FROM user_two_factor_recovery_code WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, hash := range codeHashes {
+		if _, err := sess.Insert(&twofactor.RecoveryCode{UserID: userID, CodeHash: hash, Created: now}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	var ok bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM user_two_factor_recovery_code WHERE user_id = ? AND code_hash = ?", userID, codeHash)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		ok = affected > 0
+		return err
+	})
+	return ok, err
+}
+
+func (s *sqlStore) CountRecoveryCodes(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		count, err = sess.Where("user_id = ?", userID).Count(&twofactor.RecoveryCode{})
+		return err
+	})
+	return count, err
+}
+
+func (s *sqlStore) Delete(ctx context.Context, userID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_two_factor_recovery_code WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM user_two_factor WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+func (s *sqlStore) GetOrgPolicies(ctx context.Context, orgIDs []int64) ([]*twofactor.OrgPolicy, error) {
+	result := make([]*twofactor.OrgPolicy, 0)
+	if len(orgIDs) == 0 {
+		return result, nil
+	}
+
+	params := make([]any, 0, len(orgIDs))
+	for _, id := range orgIDs {
+		params = append(params, id)
+	}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id IN (?"+strings.Repeat(",?", len(orgIDs)-1)+")", params...).OrderBy("org_id").Find(&result)
+	})
+	return result, err
+}
+
+func (s *sqlStore) SetOrgPolicy(ctx context.Context, policy *twofactor.OrgPolicy) error {
+	if policy.MinRole == "" {
+		policy.MinRole = org.RoleViewer
+	}
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var existing twofactor.OrgPolicy
+		has, err := sess.Where("org_id = ?", policy.OrgID).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			_, err = sess.Insert(policy)
+			return err
+		}
+		policy.ID = existing.ID
+		_, err = sess.ID(existing.ID).Cols("enforced", "min_role", "updated").Update(policy)
+		return err
+	})
+}
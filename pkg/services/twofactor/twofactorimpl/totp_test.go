@@ -0,0 +1,93 @@
+package twofactorimpl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the base32 encoding of the SHA1 key from the RFC 6238 test vectors.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{unix: 59, expected: "287082"},
+		{unix: 1111111109, expected: "081804"},
+		{unix: 1234567890, expected: "005924"},
+		{unix: 2000000000, expected: "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := totpCode(rfc6238Secret, totpStep(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, code, "time %d", tt.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := totpStep(now)
+
+	t.Run("accepts the current code", func(t *testing.T) {
+		step, ok := validateTOTP(rfc6238Secret, "005924", now)
+		assert.True(t, ok)
+		assert.Equal(t, current, step)
+	})
+
+	t.Run("accepts codes from the previous and next step", func(t *testing.T) {
+		prev, err := totpCode(rfc6238Secret, current-1)
+		require.NoError(t, err)
+		step, ok := validateTOTP(rfc6238Secret, prev, now)
+		assert.True(t, ok)
+		assert.Equal(t, current-1, step)
+
+		next, err := totpCode(rfc6238Secret, current+1)
+		require.NoError(t, err)
+		step, ok = validateTOTP(rfc6238Secret, next, now)
+		assert.True(t, ok)
+		assert.Equal(t, current+1, step)
+	})
+
+	t.Run("rejects codes outside of the allowed skew", func(t *testing.T) {
+		old, err := totpCode(rfc6238Secret, current-2)
+		require.NoError(t, err)
+		_, ok := validateTOTP(rfc6238Secret, old, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects malformed codes", func(t *testing.T) {
+		for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+			_, ok := validateTOTP(rfc6238Secret, code, now)
+			assert.False(t, ok, code)
+		}
+	})
+}
+
+func TestOTPAuthURL(t *testing.T) {
+	u := otpauthURL("Grafana", "admin@example.com", rfc6238Secret)
+	assert.Equal(t, "otpauth://totp/Grafana:admin@example.com?algorithm=SHA1&digits=6&issuer=Grafana&period=30&secret="+rfc6238Secret, u)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := generateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Len(t, code, recoveryCodeLength+1)
+		assert.True(t, looksLikeRecoveryCode(code))
+		assert.False(t, seen[code], "duplicate recovery code")
+		seen[code] = true
+	}
+
+	// codes can be typed without the dash and in upper case
+	assert.Equal(t, hashRecoveryCode("abcde-fghij"), hashRecoveryCode(" ABCDEFGHIJ "))
+	assert.False(t, looksLikeRecoveryCode("123456"))
+}
@@ -0,0 +1,70 @@
+package twofactortest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/twofactor"
+)
+
+var _ twofactor.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedStatus        *twofactor.Status
+	ExpectedEnrollment    *twofactor.Enrollment
+	ExpectedRecoveryCodes *twofactor.RecoveryCodes
+	ExpectedPolicy        *twofactor.OrgPolicy
+	ExpectedError         error
+	// ExpectedVerifyError is returned by Verify, ExpectedError is used when unset.
+	ExpectedVerifyError error
+
+	VerifiedCodes []string
+	ResetUserIDs  []int64
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{ExpectedStatus: &twofactor.Status{}}
+}
+
+func (f *FakeService) GetStatus(ctx context.Context, query *twofactor.GetStatusQuery) (*twofactor.Status, error) {
+	return f.ExpectedStatus, f.ExpectedError
+}
+
+func (f *FakeService) Enroll(ctx context.Context, cmd *twofactor.EnrollCommand) (*twofactor.Enrollment, error) {
+	return f.ExpectedEnrollment, f.ExpectedError
+}
+
+func (f *FakeService) Activate(ctx context.Context, cmd *twofactor.ActivateCommand) (*twofactor.RecoveryCodes, error) {
+	return f.ExpectedRecoveryCodes, f.ExpectedError
+}
+
+func (f *FakeService) Disable(ctx context.Context, cmd *twofactor.DisableCommand) error {
+	return f.ExpectedError
+}
+
+func (f *FakeService) RegenerateRecoveryCodes(ctx context.Context, cmd *twofactor.RegenerateRecoveryCodesCommand) (*twofactor.RecoveryCodes, error) {
+	return f.ExpectedRecoveryCodes, f.ExpectedError
+}
+
+func (f *FakeService) Reset(ctx context.Context, cmd *twofactor.ResetCommand) error {
+	if f.ExpectedError != nil {
+		return f.ExpectedError
+	}
+	f.ResetUserIDs = append(f.ResetUserIDs, cmd.UserID)
+	return nil
+}
+
+func (f *FakeService) Verify(ctx context.Context, cmd *twofactor.VerifyCommand) error {
+	f.VerifiedCodes = append(f.VerifiedCodes, cmd.Code)
+	if f.ExpectedVerifyError != nil {
+		return f.ExpectedVerifyError
+	}
+	return f.ExpectedError
+}
+
+func (f *FakeService) GetOrgPolicy(ctx context.Context, query *twofactor.GetOrgPolicyQuery) (*twofactor.OrgPolicy, error) {
+	return f.ExpectedPolicy, f.ExpectedError
+}
+
+func (f *FakeService) SetOrgPolicy(ctx context.Context, cmd *twofactor.SetOrgPolicyCommand) error {
+	return f.ExpectedError
+}
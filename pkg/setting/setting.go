@@ -219,6 +219,9 @@ type Cfg struct {
 	DashboardVersionsToKeep  int
 	MinRefreshInterval       string
 	DefaultHomeDashboardPath string
+	// DeletedDashboardsRetention is how long deleted dashboards and folders
+	// can be restored. Dashboards are deleted permanently if zero.
+	DeletedDashboardsRetention time.Duration
 
 	// Auth
 	LoginCookieName               string
@@ -244,6 +247,12 @@ type Cfg struct {
 	// stand in until a more complete solution is implemented
 	AuthConfigUIAdminAccess bool
 
+	// Two-factor authentication for users logging in with a Grafana password
+	TwoFactorAuthEnabled             bool
+	TwoFactorAuthIssuer              string
+	TwoFactorAuthEnforcedRole        string
+	TwoFactorAuthEnforceServerAdmins bool
+
 	// AWS Plugin Auth
 	AWSAllowedAuthProviders   []string
 	AWSAssumeRoleEnabled      bool
@@ -264,6 +273,8 @@ type Cfg struct {
 	OAuthAllowInsecureEmailLookup bool
 
 	JWTAuth AuthJWTSettings
+	// Mutual TLS Auth
+	MTLSAuth AuthMTLSSettings
 	// Extended JWT Auth
 	ExtendedJWTAuthEnabled    bool
 	ExtendedJWTExpectIssuer   string
@@ -431,6 +442,12 @@ type Cfg struct {
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
+	// LivePipelineEnabled enables Live pipeline channel rules and the
+	// data inputs configured in its storage.
+	LivePipelineEnabled bool
+	// LivePipelineRulesCheckInterval is how often Live checks whether
+	// pipeline channel rules were changed by another Grafana instance.
+	LivePipelineRulesCheckInterval time.Duration
 
 	// Grafana.com URL, used for OAuth redirect.
 	GrafanaComURL string
@@ -449,6 +466,10 @@ type Cfg struct {
 	// Query history
 	QueryHistoryEnabled bool
 
+	// Scheduled reports
+	ReportsEnabled       bool
+	ReportsRenderTimeout time.Duration
+
 	Storage StorageSettings
 
 	Search SearchSettings
@@ -493,7 +514,9 @@ type Cfg struct {
 	DatabaseInstrumentQueries bool
 
 	// Public dashboards
-	PublicDashboardsEnabled bool
+	PublicDashboardsEnabled            bool
+	PublicDashboardsAccessLogRetention time.Duration
+	PublicDashboardsTrustedProxies     []string
 
 	// Feature Management Settings
 	FeatureManagement FeatureMgmtSettings
@@ -1098,6 +1121,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	cfg.DashboardVersionsToKeep = dashboards.Key("versions_to_keep").MustInt(20)
 	cfg.MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.DeletedDashboardsRetention = dashboards.Key("deleted_dashboards_retention").MustDuration(30 * 24 * time.Hour)
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1169,6 +1193,10 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	queryHistory := iniFile.Section("query_history")
 	cfg.QueryHistoryEnabled = queryHistory.Key("enabled").MustBool(true)
 
+	reports := iniFile.Section("reports")
+	cfg.ReportsEnabled = reports.Key("enabled").MustBool(true)
+	cfg.ReportsRenderTimeout = reports.Key("render_timeout").MustDuration(time.Minute)
+
 	panelsSection := iniFile.Section("panels")
 	cfg.DisableSanitizeHtml = panelsSection.Key("disable_sanitize_html").MustBool(false)
 
@@ -1195,6 +1223,9 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	cfg.handleAWSConfig()
 	cfg.readAzureSettings()
 	cfg.readAuthJWTSettings()
+	if err := cfg.readAuthMTLSSettings(); err != nil {
+		return err
+	}
 	cfg.readAuthProxySettings()
 	cfg.readSessionConfig()
 	if err := cfg.readSmtpSettings(); err != nil {
@@ -1615,6 +1646,16 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.BasicAuthEnabled = authBasic.Key("enabled").MustBool(true)
 	cfg.BasicAuthStrongPasswordPolicy = authBasic.Key("password_policy").MustBool(false)
 
+	// two-factor auth
+	authTwoFactor := iniFile.Section("auth.two_factor")
+	cfg.TwoFactorAuthEnabled = authTwoFactor.Key("enabled").MustBool(false)
+	cfg.TwoFactorAuthIssuer = valueAsString(authTwoFactor, "issuer", "Grafana")
+	cfg.TwoFactorAuthEnforcedRole = authTwoFactor.Key("enforced_role").In("", []string{
+		string(roletype.RoleViewer),
+		string(roletype.RoleEditor),
+		string(roletype.RoleAdmin)})
+	cfg.TwoFactorAuthEnforceServerAdmins = authTwoFactor.Key("enforce_server_admins").MustBool(false)
+
 	// Extended JWT auth
 	authExtendedJWT := cfg.SectionWithEnvOverrides("auth.extended_jwt")
 	cfg.ExtendedJWTAuthEnabled = authExtendedJWT.Key("enabled").MustBool(false)
@@ -1983,6 +2024,11 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 	}
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
 	cfg.LiveHAEnginePassword = section.Key("ha_engine_password").MustString("")
+	cfg.LivePipelineEnabled = section.Key("pipeline_enabled").MustBool(false)
+	cfg.LivePipelineRulesCheckInterval = section.Key("pipeline_rules_check_interval").MustDuration(2 * time.Second)
+	if cfg.LivePipelineRulesCheckInterval <= 0 {
+		return fmt.Errorf("unexpected value %s for [live] pipeline_rules_check_interval", cfg.LivePipelineRulesCheckInterval)
+	}
 
 	var originPatterns []string
 	allowedOrigins := section.Key("allowed_origins").MustString("")
@@ -2004,4 +2050,6 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 func (cfg *Cfg) readPublicDashboardsSettings() {
 	publicDashboards := cfg.Raw.Section("public_dashboards")
 	cfg.PublicDashboardsEnabled = publicDashboards.Key("enabled").MustBool(true)
+	cfg.PublicDashboardsAccessLogRetention = publicDashboards.Key("access_log_retention").MustDuration(30 * 24 * time.Hour)
+	cfg.PublicDashboardsTrustedProxies = util.SplitString(publicDashboards.Key("trusted_proxies").String())
 }
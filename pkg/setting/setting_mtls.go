@@ -0,0 +1,99 @@
+package setting
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	MTLSClientAuthOptional = "optional"
+	MTLSClientAuthRequired = "required"
+
+	MTLSIdentityServiceAccount = "service_account"
+	MTLSIdentityUser           = "user"
+
+	MTLSFieldSubjectCN = "subject_cn"
+	MTLSFieldSubject   = "subject"
+	MTLSFieldSANDNS    = "san_dns"
+	MTLSFieldSANEmail  = "san_email"
+	MTLSFieldSANURI    = "san_uri"
+)
+
+type AuthMTLSSettings struct {
+	// Mutual TLS Auth
+	Enabled    bool
+	ClientAuth string
+	CAFile     string
+	CRLFiles   []string
+	Rules      []MTLSMappingRule
+}
+
+// MTLSMappingRule maps a field of a verified client certificate to a Grafana identity.
+// Rules are configured in [auth.mtls.rule.<name>] sections and evaluated in file order.
+type MTLSMappingRule struct {
+	Name         string
+	Field        string
+	Pattern      *regexp.Regexp
+	IdentityType string
+	// Login is expanded with the submatches of Pattern, e.g. "sa-$1"
+	Login string
+}
+
+func (cfg *Cfg) readAuthMTLSSettings() error {
+	mtlsSettings := AuthMTLSSettings{}
+	authMTLS := cfg.Raw.Section("auth.mtls")
+	mtlsSettings.Enabled = authMTLS.Key("enabled").MustBool(false)
+	mtlsSettings.ClientAuth = authMTLS.Key("client_auth").In(MTLSClientAuthOptional, []string{MTLSClientAuthOptional, MTLSClientAuthRequired})
+	mtlsSettings.CAFile = valueAsString(authMTLS, "ca_file", "")
+	mtlsSettings.CRLFiles = util.SplitString(valueAsString(authMTLS, "crl_files", ""))
+
+	rules, err := readMTLSMappingRules(cfg.Raw)
+	if err != nil {
+		return err
+	}
+	mtlsSettings.Rules = rules
+
+	if mtlsSettings.Enabled && mtlsSettings.CAFile == "" {
+		return fmt.Errorf("auth.mtls: ca_file is required when mTLS authentication is enabled")
+	}
+
+	cfg.MTLSAuth = mtlsSettings
+	return nil
+}
+
+func readMTLSMappingRules(iniFile *ini.File) ([]MTLSMappingRule, error) {
+	prefix := "auth.mtls.rule."
+	var rules []MTLSMappingRule
+	for _, section := range iniFile.Sections() {
+		if !strings.HasPrefix(section.Name(), prefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(section.Name(), prefix)
+		field := section.Key("field").In(MTLSFieldSubjectCN, []string{
+			MTLSFieldSubjectCN, MTLSFieldSubject, MTLSFieldSANDNS, MTLSFieldSANEmail, MTLSFieldSANURI,
+		})
+
+		pattern, err := regexp.Compile(valueAsString(section, "pattern", "^(.+)$"))
+		if err != nil {
+			return nil, fmt.Errorf("auth.mtls: invalid pattern in rule %q: %w", name, err)
+		}
+
+		rules = append(rules, MTLSMappingRule{
+			Name:    name,
+			Field:   field,
+			Pattern: pattern,
+			IdentityType: section.Key("identity_type").In(MTLSIdentityServiceAccount, []string{
+				MTLSIdentityServiceAccount, MTLSIdentityUser,
+			}),
+			Login: valueAsString(section, "login", "$0"),
+		})
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,68 @@
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadAuthMTLSSettings(t *testing.T) {
+	t.Run("will load mapping rules in file order", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[auth.mtls]
+enabled = true
+ca_file = /etc/grafana/clients-ca.pem
+crl_files = /etc/grafana/a.crl, /etc/grafana/b.crl
+client_auth = required
+
+[auth.mtls.rule.ci]
+field = san_uri
+pattern = ^spiffe://example.org/ci/(.+)$
+login = sa-$1
+
+[auth.mtls.rule.people]
+field = san_email
+identity_type = user
+`))
+		require.NoError(t, err)
+		cfg := NewCfg()
+		cfg.Raw = f
+
+		require.NoError(t, cfg.readAuthMTLSSettings())
+
+		assert.True(t, cfg.MTLSAuth.Enabled)
+		assert.Equal(t, MTLSClientAuthRequired, cfg.MTLSAuth.ClientAuth)
+		assert.Equal(t, []string{"/etc/grafana/a.crl", "/etc/grafana/b.crl"}, cfg.MTLSAuth.CRLFiles)
+		require.Len(t, cfg.MTLSAuth.Rules, 2)
+
+		assert.Equal(t, "ci", cfg.MTLSAuth.Rules[0].Name)
+		assert.Equal(t, MTLSFieldSANURI, cfg.MTLSAuth.Rules[0].Field)
+		assert.Equal(t, MTLSIdentityServiceAccount, cfg.MTLSAuth.Rules[0].IdentityType)
+		assert.Equal(t, "sa-$1", cfg.MTLSAuth.Rules[0].Login)
+
+		assert.Equal(t, "people", cfg.MTLSAuth.Rules[1].Name)
+		assert.Equal(t, MTLSIdentityUser, cfg.MTLSAuth.Rules[1].IdentityType)
+		assert.Equal(t, "$0", cfg.MTLSAuth.Rules[1].Login)
+		assert.Equal(t, "^(.+)$", cfg.MTLSAuth.Rules[1].Pattern.String())
+	})
+
+	t.Run("will return error when ca_file is missing", func(t *testing.T) {
+		f, err := ini.Load([]byte("[auth.mtls]\nenabled = true\n"))
+		require.NoError(t, err)
+		cfg := NewCfg()
+		cfg.Raw = f
+
+		require.Error(t, cfg.readAuthMTLSSettings())
+	})
+
+	t.Run("will return error when pattern is invalid", func(t *testing.T) {
+		f, err := ini.Load([]byte("[auth.mtls.rule.broken]\npattern = ^(\n"))
+		require.NoError(t, err)
+		cfg := NewCfg()
+		cfg.Raw = f
+
+		require.Error(t, cfg.readAuthMTLSSettings())
+	})
+}
@@ -0,0 +1,299 @@
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/find", s.handleMetricsFind)
+	mux.HandleFunc("/tags/autoComplete/tags", s.handleTagsAutoComplete)
+	mux.HandleFunc("/tags/autoComplete/values", s.handleTagValuesAutoComplete)
+	mux.HandleFunc("/functions", s.handleFunctions)
+	return mux
+}
+
+// graphiteError is returned when Graphite responds with a non 2xx status.
+type graphiteError struct {
+	status int
+	body   string
+}
+
+func (e *graphiteError) Error() string {
+	return fmt.Sprintf("request failed, status: %d, body: %s", e.status, e.body)
+}
+
+// parseResourceRequest decodes request parameters from JSON body of POST
+// request or from query string of GET request.
+func parseResourceRequest(req *http.Request, dst any, fromQuery func(url.Values)) error {
+	switch req.Method {
+	case http.MethodGet:
+		fromQuery(req.URL.Query())
+		return nil
+	case http.MethodPost:
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			return nil
+		}
+		return json.Unmarshal(body, dst)
+	default:
+		return fmt.Errorf("unsupported method: %s", req.Method)
+	}
+}
+
+func (s *Service) handleMetricsFind(rw http.ResponseWriter, req *http.Request) {
+	var findReq GraphiteMetricsFindRequest
+	err := parseResourceRequest(req, &findReq, func(q url.Values) {
+		findReq.Query = q.Get("query")
+		findReq.From = q.Get("from")
+		findReq.Until = q.Get("until")
+	})
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+	if findReq.Query == "" {
+		writeErrorResponse(rw, http.StatusBadRequest, errors.New("query is required"))
+		return
+	}
+
+	params := url.Values{"query": []string{findReq.Query}}
+	if findReq.From != "" {
+		params.Set("from", findReq.From)
+	}
+	if findReq.Until != "" {
+		params.Set("until", findReq.Until)
+	}
+	body, err := s.doResourceRequest(req.Context(), http.MethodPost, "metrics/find", params)
+	if err != nil {
+		writeGraphiteError(rw, err)
+		return
+	}
+
+	var result []GraphiteMetricsFindResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		writeErrorResponse(rw, http.StatusInternalServerError, fmt.Errorf("failed to unmarshal graphite response: %w", err))
+		return
+	}
+	if result == nil {
+		result = []GraphiteMetricsFindResponse{}
+	}
+	writeJSONResponse(rw, result)
+}
+
+func (s *Service) handleTagsAutoComplete(rw http.ResponseWriter, req *http.Request) {
+	var tagsReq GraphiteTagsRequest
+	err := parseResourceRequest(req, &tagsReq, func(q url.Values) {
+		tagsReq.TagPrefix = q.Get("tagPrefix")
+		tagsReq.Expr = q["expr"]
+		tagsReq.Limit, _ = strconv.Atoi(q.Get("limit"))
+	})
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	params := url.Values{}
+	if tagsReq.TagPrefix != "" {
+		params.Set("tagPrefix", tagsReq.TagPrefix)
+	}
+	s.autoComplete(rw, req, "tags/autoComplete/tags", params, tagsReq.Expr, tagsReq.Limit)
+}
+
+func (s *Service) handleTagValuesAutoComplete(rw http.ResponseWriter, req *http.Request) {
+	var valuesReq GraphiteTagValuesRequest
+	err := parseResourceRequest(req, &valuesReq, func(q url.Values) {
+		valuesReq.Tag = q.Get("tag")
+		valuesReq.ValuePrefix = q.Get("valuePrefix")
+		valuesReq.Expr = q["expr"]
+		valuesReq.Limit, _ = strconv.Atoi(q.Get("limit"))
+	})
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+	if valuesReq.Tag == "" {
+		writeErrorResponse(rw, http.StatusBadRequest, errors.New("tag is required"))
+		return
+	}
+
+	params := url.Values{"tag": []string{valuesReq.Tag}}
+	if valuesReq.ValuePrefix != "" {
+		params.Set("valuePrefix", valuesReq.ValuePrefix)
+	}
+	s.autoComplete(rw, req, "tags/autoComplete/values", params, valuesReq.Expr, valuesReq.Limit)
+}
+
+func (s *Service) autoComplete(rw http.ResponseWriter, req *http.Request, endpoint string, params url.Values, expr []string, limit int) {
+	for _, e := range expr {
+		if e != "" {
+			params.Add("expr", e)
+		}
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	body, err := s.doResourceRequest(req.Context(), http.MethodGet, endpoint, params)
+	if err != nil {
+		writeGraphiteError(rw, err)
+		return
+	}
+	var result []string
+	if err := json.Unmarshal(body, &result); err != nil {
+		writeErrorResponse(rw, http.StatusInternalServerError, fmt.Errorf("failed to unmarshal graphite response: %w", err))
+		return
+	}
+	if result == nil {
+		result = []string{}
+	}
+	writeJSONResponse(rw, result)
+}
+
+// Graphite returns Infinity as default value of some function parameters,
+// which is not valid JSON.
+var infinityDefaultRegexp = regexp.MustCompile(`"default": ?Infinity`)
+
+func (s *Service) handleFunctions(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeErrorResponse(rw, http.StatusMethodNotAllowed, fmt.Errorf("unsupported method: %s", req.Method))
+		return
+	}
+	body, err := s.doResourceRequest(req.Context(), http.MethodGet, "functions", url.Values{})
+	if err != nil {
+		writeGraphiteError(rw, err)
+		return
+	}
+	body = infinityDefaultRegexp.ReplaceAll(body, []byte(`"default": 1e9999`))
+	if !json.Valid(body) {
+		writeErrorResponse(rw, http.StatusInternalServerError, errors.New("graphite returned invalid functions response"))
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	if _, err := rw.Write(body); err != nil {
+		logger.Error("Failed to write response", "error", err)
+	}
+}
+
+// doResourceRequest sends a request to Graphite endpoint using data source
+// HTTP client, so data source auth and proxy settings apply.
+func (s *Service) doResourceRequest(ctx context.Context, method string, endpoint string, params url.Values) ([]byte, error) {
+	logger := logger.FromContext(ctx)
+	pluginCtx := httpadapter.PluginConfigFromContext(ctx)
+	dsInfo, err := s.getDSInfo(ctx, pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, endpoint)
+
+	var graphiteReq *http.Request
+	if method == http.MethodPost {
+		graphiteReq, err = http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(params.Encode()))
+		if err == nil {
+			graphiteReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		u.RawQuery = params.Encode()
+		graphiteReq, err = http.NewRequestWithContext(ctx, method, u.String(), nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite resource")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("endpoint", endpoint),
+		attribute.Int64("datasource_id", dsInfo.Id),
+		attribute.Int64("org_id", pluginCtx.OrgID),
+	)
+	s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if res != nil {
+		span.SetAttributes(attribute.Int("graphite.response.code", res.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("Resource request failed", "endpoint", endpoint, "status", res.Status, "body", string(body))
+		err := &graphiteError{status: res.StatusCode, body: string(body)}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return body, nil
+}
+
+func writeGraphiteError(rw http.ResponseWriter, err error) {
+	var graphiteErr *graphiteError
+	if errors.As(err, &graphiteErr) {
+		status := graphiteErr.status
+		// Graphite authentication failures must not be mistaken for a failed
+		// authentication against Grafana, which would log the user out.
+		if status == http.StatusUnauthorized || status == http.StatusForbidden {
+			status = http.StatusBadGateway
+		}
+		writeErrorResponse(rw, status, err)
+		return
+	}
+	writeErrorResponse(rw, http.StatusInternalServerError, err)
+}
+
+func writeErrorResponse(rw http.ResponseWriter, code int, err error) {
+	b, _ := json.Marshal(map[string]string{"message": err.Error()})
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	if _, err := rw.Write(b); err != nil {
+		logger.Error("Failed to write response", "error", err)
+	}
+}
+
+func writeJSONResponse(rw http.ResponseWriter, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeErrorResponse(rw, http.StatusInternalServerError, err)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	if _, err := rw.Write(b); err != nil {
+		logger.Error("Failed to write response", "error", err)
+	}
+}
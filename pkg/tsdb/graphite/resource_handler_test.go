@@ -0,0 +1,162 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+type resourceInstanceManager struct {
+	url string
+}
+
+func (f resourceInstanceManager) Get(_ context.Context, _ backend.PluginContext) (instancemgmt.Instance, error) {
+	return datasourceInfo{HTTPClient: http.DefaultClient, URL: f.url}, nil
+}
+
+func (f resourceInstanceManager) Do(_ context.Context, _ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+type fakeResourceSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeResourceSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func setupResourceTest(t *testing.T, handler http.HandlerFunc) *Service {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	s := &Service{
+		im:     resourceInstanceManager{url: server.URL},
+		tracer: tracing.InitializeTracerForTest(),
+	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
+}
+
+func callResource(t *testing.T, s *Service, method, path, query string, body []byte) *backend.CallResourceResponse {
+	t.Helper()
+	sender := &fakeResourceSender{}
+	reqURL := path
+	if query != "" {
+		reqURL += "?" + query
+	}
+	err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+		Method: method,
+		Path:   path,
+		URL:    reqURL,
+		Body:   body,
+	}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.resp)
+	return sender.resp
+}
+
+func TestCallResource_MetricsFind(t *testing.T) {
+	s := setupResourceTest(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/metrics/find", r.URL.Path)
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "apps.*", r.PostForm.Get("query"))
+		require.Equal(t, "-1h", r.PostForm.Get("from"))
+		_, _ = w.Write([]byte(`[{"text":"backend","id":"apps.backend","leaf":0,"expandable":1,"allowChildren":1},{"text":"count","id":"apps.count","leaf":true,"expandable":false,"allowChildren":false}]`))
+	})
+
+	t.Run("from JSON body", func(t *testing.T) {
+		resp := callResource(t, s, http.MethodPost, "metrics/find", "", []byte(`{"query":"apps.*","from":"-1h"}`))
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `[
+			{"text":"backend","id":"apps.backend","leaf":false,"expandable":true,"allowChildren":true},
+			{"text":"count","id":"apps.count","leaf":true,"expandable":false,"allowChildren":false}
+		]`, string(resp.Body))
+	})
+
+	t.Run("from query string", func(t *testing.T) {
+		resp := callResource(t, s, http.MethodGet, "metrics/find", "query=apps.*&from=-1h", nil)
+		require.Equal(t, http.StatusOK, resp.Status)
+	})
+
+	t.Run("query is required", func(t *testing.T) {
+		resp := callResource(t, s, http.MethodGet, "metrics/find", "", nil)
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+}
+
+func TestCallResource_TagsAutoComplete(t *testing.T) {
+	s := setupResourceTest(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/tags/autoComplete/tags":
+			require.Equal(t, "ho", q.Get("tagPrefix"))
+			require.Equal(t, []string{"name=cpu", "dc=eu"}, q["expr"])
+			require.Equal(t, "10", q.Get("limit"))
+			_, _ = w.Write([]byte(`["host"]`))
+		case "/tags/autoComplete/values":
+			require.Equal(t, "host", q.Get("tag"))
+			require.Equal(t, "a", q.Get("valuePrefix"))
+			_, _ = w.Write([]byte(`["a1","a2"]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	resp := callResource(t, s, http.MethodGet, "tags/autoComplete/tags", "tagPrefix=ho&expr=name%3Dcpu&expr=dc%3Deu&limit=10", nil)
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.JSONEq(t, `["host"]`, string(resp.Body))
+
+	resp = callResource(t, s, http.MethodPost, "tags/autoComplete/values", "", []byte(`{"tag":"host","valuePrefix":"a"}`))
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.JSONEq(t, `["a1","a2"]`, string(resp.Body))
+
+	resp = callResource(t, s, http.MethodPost, "tags/autoComplete/values", "", []byte(`{}`))
+	require.Equal(t, http.StatusBadRequest, resp.Status)
+}
+
+func TestCallResource_Functions(t *testing.T) {
+	s := setupResourceTest(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/functions", r.URL.Path)
+		_, _ = w.Write([]byte(`{"movingWindow":{"name":"movingWindow","params":[{"name":"xFilesFactor","default": Infinity}]}}`))
+	})
+
+	resp := callResource(t, s, http.MethodGet, "functions", "", nil)
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.Contains(t, string(resp.Body), `"default": 1e9999`)
+}
+
+func TestCallResource_GraphiteError(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		upstreamStatus int
+		expectedStatus int
+	}{
+		{desc: "unauthorized is reported as bad gateway", upstreamStatus: http.StatusUnauthorized, expectedStatus: http.StatusBadGateway},
+		{desc: "forbidden is reported as bad gateway", upstreamStatus: http.StatusForbidden, expectedStatus: http.StatusBadGateway},
+		{desc: "other statuses are passed through", upstreamStatus: http.StatusNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := setupResourceTest(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.upstreamStatus)
+				_, _ = w.Write([]byte("upstream message"))
+			})
+
+			resp := callResource(t, s, http.MethodGet, "tags/autoComplete/tags", "", nil)
+			require.Equal(t, tc.expectedStatus, resp.Status)
+			require.Contains(t, string(resp.Body), "upstream message")
+		})
+	}
+}
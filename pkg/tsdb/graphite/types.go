@@ -1,6 +1,10 @@
 package graphite
 
-import "github.com/grafana/grafana/pkg/tsdb/legacydata"
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
 
 type TargetResponseDTO struct {
 	Target     string                          `json:"target"`
@@ -8,3 +12,46 @@ type TargetResponseDTO struct {
 	// Graphite <=1.1.7 may return some tags as numbers requiring extra conversion. See https://github.com/grafana/grafana/issues/37614
 	Tags map[string]any `json:"tags"`
 }
+
+type GraphiteMetricsFindRequest struct {
+	Query string `json:"query"`
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+}
+
+type GraphiteMetricsFindResponse struct {
+	Text          string       `json:"text"`
+	Id            string       `json:"id"`
+	Leaf          graphiteBool `json:"leaf"`
+	Expandable    graphiteBool `json:"expandable"`
+	AllowChildren graphiteBool `json:"allowChildren"`
+}
+
+type GraphiteTagsRequest struct {
+	TagPrefix string   `json:"tagPrefix,omitempty"`
+	Expr      []string `json:"expr,omitempty"`
+	Limit     int      `json:"limit,omitempty"`
+}
+
+type GraphiteTagValuesRequest struct {
+	Tag         string   `json:"tag"`
+	ValuePrefix string   `json:"valuePrefix,omitempty"`
+	Expr        []string `json:"expr,omitempty"`
+	Limit       int      `json:"limit,omitempty"`
+}
+
+// graphiteBool is a boolean Graphite may return either as a JSON boolean
+// or as 0/1 number depending on version.
+type graphiteBool bool
+
+func (b *graphiteBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", "1":
+		*b = true
+	case "false", "0", "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean value: %s", data)
+	}
+	return nil
+}